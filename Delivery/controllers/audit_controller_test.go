@@ -0,0 +1,60 @@
+package controllers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Amaankaa/Blog-Starter-Project/Delivery/controllers"
+	auditpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/audit"
+	"github.com/Amaankaa/Blog-Starter-Project/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newAuditRouter(uc *mocks.IAuditUsecase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	ac := controllers.NewAuditController(uc)
+	router := gin.New()
+	router.GET("/admin/audit", ac.ListEntries)
+	router.GET("/admin/audit/export", ac.ExportEntries)
+	return router
+}
+
+func TestAuditController_ExportEntries_WritesJSONLines(t *testing.T) {
+	mockUC := &mocks.IAuditUsecase{}
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	mockUC.On("ExportEntries", mock.Anything, auditpkg.Filter{Action: auditpkg.ActionPostHide, From: &from}, mock.Anything).
+		Run(func(args mock.Arguments) {
+			fn := args.Get(2).(func(auditpkg.Entry) error)
+			_ = fn(auditpkg.Entry{Seq: 1, Action: auditpkg.ActionPostHide, Hash: "a"})
+			_ = fn(auditpkg.Entry{Seq: 2, Action: auditpkg.ActionPostHide, PrevHash: "a", Hash: "b"})
+		}).Return(nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/admin/audit/export?action=post.hide&from=2025-03-01", nil)
+	newAuditRouter(mockUC).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	if assert.Len(t, lines, 2) {
+		assert.Contains(t, lines[0], `"seq":1`)
+		assert.Contains(t, lines[1], `"prevHash":"a"`)
+	}
+	mockUC.AssertExpectations(t)
+}
+
+func TestAuditController_ListEntries_InvalidDate(t *testing.T) {
+	mockUC := &mocks.IAuditUsecase{}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/admin/audit?to=yesterday", nil)
+	newAuditRouter(mockUC).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertNotCalled(t, "ListEntries", mock.Anything, mock.Anything)
+}
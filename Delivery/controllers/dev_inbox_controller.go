@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+	"github.com/gin-gonic/gin"
+)
+
+// DevInboxController shows the emails caught in local development. It is
+// only routed when PROVIDERS=local.
+type DevInboxController struct {
+	inbox services.IEmailInbox
+}
+
+func NewDevInboxController(inbox services.IEmailInbox) *DevInboxController {
+	return &DevInboxController{inbox: inbox}
+}
+
+// ListEmails lists caught emails, newest first. ?to narrows to one recipient.
+func (dc *DevInboxController) ListEmails(c *gin.Context) {
+	emails, err := dc.inbox.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if to := c.Query("to"); to != "" {
+		matching := []services.InboxEmail{}
+		for _, e := range emails {
+			if e.To == to {
+				matching = append(matching, e)
+			}
+		}
+		emails = matching
+	}
+	c.JSON(http.StatusOK, gin.H{"emails": emails})
+}
+
+// GetEmail returns one email as JSON, or with ?format=html, text or eml just
+// that part, so it can be opened in a browser or a mail client
+func (dc *DevInboxController) GetEmail(c *gin.Context) {
+	email, raw, err := dc.inbox.Get(c.Request.Context(), c.Param("id"))
+	if errors.Is(err, services.ErrInboxEmailNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	switch c.Query("format") {
+	case "html":
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(email.HTML))
+	case "text":
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(email.Text))
+	case "eml":
+		c.Header("Content-Disposition", `attachment; filename="`+email.ID+`.eml"`)
+		c.Data(http.StatusOK, "message/rfc822", raw)
+	case "", "json":
+		c.JSON(http.StatusOK, email)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be html, text, eml or json"})
+	}
+}
+
+// ClearEmails empties the inbox
+func (dc *DevInboxController) ClearEmails(c *gin.Context) {
+	if err := dc.inbox.Clear(c.Request.Context()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
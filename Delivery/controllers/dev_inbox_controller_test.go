@@ -0,0 +1,57 @@
+package controllers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Amaankaa/Blog-Starter-Project/Delivery/controllers"
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+	"github.com/Amaankaa/Blog-Starter-Project/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newDevInboxRouter(inbox *mocks.IEmailInbox) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	dc := controllers.NewDevInboxController(inbox)
+	router := gin.New()
+	router.GET("/dev/inbox", dc.ListEmails)
+	router.GET("/dev/inbox/:id", dc.GetEmail)
+	return router
+}
+
+func TestDevInboxController_ListEmails_FiltersByRecipient(t *testing.T) {
+	inbox := &mocks.IEmailInbox{}
+	inbox.On("List", mock.Anything).Return([]services.InboxEmail{
+		{ID: "2", To: "b@example.com", Subject: "Second"},
+		{ID: "1", To: "a@example.com", Subject: "First"},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/dev/inbox?to=a@example.com", nil)
+	newDevInboxRouter(inbox).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"subject":"First"`)
+	assert.NotContains(t, w.Body.String(), "Second")
+}
+
+func TestDevInboxController_GetEmail_HTML(t *testing.T) {
+	inbox := &mocks.IEmailInbox{}
+	inbox.On("Get", mock.Anything, "1").Return(services.InboxEmail{ID: "1", HTML: "<p>Your code</p>"}, []byte("raw"), nil)
+	inbox.On("Get", mock.Anything, "missing").Return(services.InboxEmail{}, nil, services.ErrInboxEmailNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/dev/inbox/1?format=html", nil)
+	newDevInboxRouter(inbox).ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "<p>Your code</p>", w.Body.String())
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/dev/inbox/missing", nil)
+	newDevInboxRouter(inbox).ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	emailpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/email"
+	"github.com/gin-gonic/gin"
+)
+
+// EmailDomainRuleController lets admins manage the email domains signup
+// verification always accepts or refuses
+type EmailDomainRuleController struct {
+	rules emailpkg.IDomainRuleUsecase
+}
+
+func NewEmailDomainRuleController(rules emailpkg.IDomainRuleUsecase) *EmailDomainRuleController {
+	return &EmailDomainRuleController{rules: rules}
+}
+
+type setDomainRuleRequest struct {
+	Action emailpkg.DomainRuleAction `json:"action" binding:"required"`
+	Note   string                    `json:"note"`
+}
+
+// ListRules lists the rules by domain; ?action=allow or deny narrows them
+func (dc *EmailDomainRuleController) ListRules(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	rules, err := dc.rules.ListRules(ctx, emailpkg.DomainRuleAction(c.Query("action")))
+	if err != nil {
+		domainRuleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"rules": rules})
+}
+
+// SetRule allows or denies a domain and its subdomains, replacing any rule
+// it already has
+func (dc *EmailDomainRuleController) SetRule(c *gin.Context) {
+	var req setDomainRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	rule, err := dc.rules.SetRule(ctx, c.Param("domain"), req.Action, req.Note, c.GetString("user_id"))
+	if err != nil {
+		domainRuleError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+func (dc *EmailDomainRuleController) DeleteRule(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := dc.rules.DeleteRule(ctx, c.Param("domain"), c.GetString("user_id")); err != nil {
+		domainRuleError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func domainRuleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, emailpkg.ErrDomainRuleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, emailpkg.ErrInvalidDomain), errors.Is(err, emailpkg.ErrInvalidDomainAction):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,65 @@
+package controllers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Amaankaa/Blog-Starter-Project/Delivery/controllers"
+	emailpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/email"
+	"github.com/Amaankaa/Blog-Starter-Project/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newEmailDomainRuleRouter(uc *mocks.IDomainRuleUsecase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	dc := controllers.NewEmailDomainRuleController(uc)
+	router := gin.New()
+	router.Use(func(c *gin.Context) { c.Set("user_id", "admin"); c.Next() })
+	router.GET("/admin/email/domains", dc.ListRules)
+	router.PUT("/admin/email/domains/:domain", dc.SetRule)
+	router.DELETE("/admin/email/domains/:domain", dc.DeleteRule)
+	return router
+}
+
+func TestEmailDomainRuleController_SetRule(t *testing.T) {
+	mockUC := &mocks.IDomainRuleUsecase{}
+	mockUC.On("SetRule", mock.Anything, "mailinator.com", emailpkg.DomainAllow, "QA team", "admin").
+		Return(emailpkg.DomainRule{Domain: "mailinator.com", Action: emailpkg.DomainAllow}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/admin/email/domains/mailinator.com", strings.NewReader(`{"action":"allow","note":"QA team"}`))
+	req.Header.Set("Content-Type", "application/json")
+	newEmailDomainRuleRouter(mockUC).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"action":"allow"`)
+	mockUC.AssertExpectations(t)
+}
+
+func TestEmailDomainRuleController_SetRule_InvalidDomain(t *testing.T) {
+	mockUC := &mocks.IDomainRuleUsecase{}
+	mockUC.On("SetRule", mock.Anything, "bad_domain", emailpkg.DomainDeny, "", "admin").
+		Return(emailpkg.DomainRule{}, emailpkg.ErrInvalidDomain)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPut, "/admin/email/domains/bad_domain", strings.NewReader(`{"action":"deny"}`))
+	req.Header.Set("Content-Type", "application/json")
+	newEmailDomainRuleRouter(mockUC).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestEmailDomainRuleController_DeleteRule_NotFound(t *testing.T) {
+	mockUC := &mocks.IDomainRuleUsecase{}
+	mockUC.On("DeleteRule", mock.Anything, "example.com", "admin").Return(emailpkg.ErrDomainRuleNotFound)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodDelete, "/admin/email/domains/example.com", nil)
+	newEmailDomainRuleRouter(mockUC).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
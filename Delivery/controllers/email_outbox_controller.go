@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	emailpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/email"
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+	"github.com/gin-gonic/gin"
+)
+
+// EmailOutboxController lets admins watch deliveries and retry failed ones
+type EmailOutboxController struct {
+	outbox emailpkg.IOutboxUsecase
+}
+
+func NewEmailOutboxController(outbox emailpkg.IOutboxUsecase) *EmailOutboxController {
+	return &EmailOutboxController{outbox: outbox}
+}
+
+// ListMessages pages through the outbox, newest first. Filters: status
+// (pending, sending, sent or dead), to and template.
+func (ec *EmailOutboxController) ListMessages(c *gin.Context) {
+	filter := emailpkg.Filter{
+		Status:   emailpkg.Status(c.Query("status")),
+		To:       c.Query("to"),
+		Template: services.EmailTemplate(c.Query("template")),
+	}
+	filter.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	filter.PageSize, _ = strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	if filter.Status != "" && !emailpkg.IsValidStatus(filter.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	res, err := ec.outbox.ListMessages(ctx, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (ec *EmailOutboxController) GetMessage(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	message, err := ec.outbox.GetMessage(ctx, c.Param("id"))
+	if err != nil {
+		outboxError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, message)
+}
+
+// RetryMessage queues a dead-lettered email again
+func (ec *EmailOutboxController) RetryMessage(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	message, err := ec.outbox.RetryMessage(ctx, c.Param("id"), c.GetString("user_id"))
+	if err != nil {
+		outboxError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, message)
+}
+
+// Stats counts messages by status, so a growing backlog or dead letters show up
+func (ec *EmailOutboxController) Stats(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	stats, err := ec.outbox.Stats(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+func outboxError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, emailpkg.ErrMessageNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, emailpkg.ErrMessageNotDead):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,61 @@
+package controllers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Amaankaa/Blog-Starter-Project/Delivery/controllers"
+	emailpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/email"
+	"github.com/Amaankaa/Blog-Starter-Project/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newEmailOutboxRouter(uc *mocks.IOutboxUsecase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	ec := controllers.NewEmailOutboxController(uc)
+	router := gin.New()
+	router.Use(func(c *gin.Context) { c.Set("user_id", "admin"); c.Next() })
+	router.GET("/admin/email/outbox", ec.ListMessages)
+	router.POST("/admin/email/outbox/:id/retry", ec.RetryMessage)
+	return router
+}
+
+func TestEmailOutboxController_ListMessages_FiltersByStatus(t *testing.T) {
+	mockUC := &mocks.IOutboxUsecase{}
+	mockUC.On("ListMessages", mock.Anything, emailpkg.Filter{Status: emailpkg.StatusDead, Page: 1, PageSize: 20}).
+		Return(emailpkg.MessageListResponse{Messages: []emailpkg.Message{{To: "a@example.com", Status: emailpkg.StatusDead}}, Total: 1}, nil)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/admin/email/outbox?status=dead", nil)
+	newEmailOutboxRouter(mockUC).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"to":"a@example.com"`)
+	mockUC.AssertExpectations(t)
+}
+
+func TestEmailOutboxController_ListMessages_InvalidStatus(t *testing.T) {
+	mockUC := &mocks.IOutboxUsecase{}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/admin/email/outbox?status=bounced", nil)
+	newEmailOutboxRouter(mockUC).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertNotCalled(t, "ListMessages", mock.Anything, mock.Anything)
+}
+
+func TestEmailOutboxController_RetryMessage_NotDead(t *testing.T) {
+	mockUC := &mocks.IOutboxUsecase{}
+	mockUC.On("RetryMessage", mock.Anything, "abc", "admin").Return(emailpkg.Message{}, emailpkg.ErrMessageNotDead)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/admin/email/outbox/abc/retry", nil)
+	newEmailOutboxRouter(mockUC).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	mockUC.AssertExpectations(t)
+}
@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+	"github.com/gin-gonic/gin"
+)
+
+// EmailTemplateController lets admins see what transactional emails look like
+type EmailTemplateController struct {
+	templates services.IEmailRenderer
+}
+
+func NewEmailTemplateController(templates services.IEmailRenderer) *EmailTemplateController {
+	return &EmailTemplateController{templates: templates}
+}
+
+// ListTemplates names every template and the locales they are translated into
+func (ec *EmailTemplateController) ListTemplates(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"templates": services.EmailTemplates,
+		"locales":   ec.templates.Locales(),
+	})
+}
+
+// PreviewTemplate renders a template with sample data. ?locale picks the
+// translation; ?format=html or text returns that variant on its own so it
+// can be opened in a browser, otherwise subject and both bodies come as JSON.
+func (ec *EmailTemplateController) PreviewTemplate(c *gin.Context) {
+	template := services.EmailTemplate(c.Param("id"))
+	data, ok := ec.templates.SampleData(template)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "email template not found"})
+		return
+	}
+
+	email, err := ec.templates.Render(template, c.Query("locale"), data)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	switch c.Query("format") {
+	case "html":
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(email.HTML))
+	case "text":
+		c.Data(http.StatusOK, "text/plain; charset=utf-8", []byte(email.Text))
+	case "", "json":
+		c.JSON(http.StatusOK, email)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be html, text or json"})
+	}
+}
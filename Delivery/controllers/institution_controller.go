@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	institutionpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/institution"
+	"github.com/gin-gonic/gin"
+)
+
+// InstitutionController lists the institutions students can verify with,
+// and lets admins register them
+type InstitutionController struct {
+	institutions institutionpkg.IInstitutionUsecase
+}
+
+func NewInstitutionController(institutions institutionpkg.IInstitutionUsecase) *InstitutionController {
+	return &InstitutionController{institutions: institutions}
+}
+
+// ListInstitutions lists institutions by name; ?q matches part of the name
+// or a domain
+func (ic *InstitutionController) ListInstitutions(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	institutions, err := ic.institutions.ListInstitutions(ctx, c.Query("q"))
+	if err != nil {
+		institutionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"institutions": institutions})
+}
+
+func (ic *InstitutionController) GetInstitution(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	institution, err := ic.institutions.GetInstitution(ctx, c.Param("id"))
+	if err != nil {
+		institutionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, institution)
+}
+
+func (ic *InstitutionController) CreateInstitution(c *gin.Context) {
+	var req institutionpkg.InstitutionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	institution, err := ic.institutions.CreateInstitution(ctx, req, c.GetString("user_id"))
+	if err != nil {
+		institutionError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, institution)
+}
+
+// UpdateInstitution replaces an institution's name, domains and country
+func (ic *InstitutionController) UpdateInstitution(c *gin.Context) {
+	var req institutionpkg.InstitutionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	institution, err := ic.institutions.UpdateInstitution(ctx, c.Param("id"), req, c.GetString("user_id"))
+	if err != nil {
+		institutionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, institution)
+}
+
+// DeleteInstitution also takes verified student status away from its students
+func (ic *InstitutionController) DeleteInstitution(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := ic.institutions.DeleteInstitution(ctx, c.Param("id"), c.GetString("user_id")); err != nil {
+		institutionError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func institutionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, institutionpkg.ErrInstitutionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, institutionpkg.ErrInvalidInstitution):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, institutionpkg.ErrDomainInUse):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,83 @@
+package controllers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Amaankaa/Blog-Starter-Project/Delivery/controllers"
+	institutionpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/institution"
+	"github.com/Amaankaa/Blog-Starter-Project/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newInstitutionRouter(uc *mocks.IInstitutionUsecase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	ic := controllers.NewInstitutionController(uc)
+	router := gin.New()
+	router.Use(func(c *gin.Context) { c.Set("user_id", "admin"); c.Next() })
+	router.GET("/institutions", ic.ListInstitutions)
+	router.GET("/institutions/:id", ic.GetInstitution)
+	router.POST("/admin/institutions", ic.CreateInstitution)
+	router.PUT("/admin/institutions/:id", ic.UpdateInstitution)
+	router.DELETE("/admin/institutions/:id", ic.DeleteInstitution)
+	return router
+}
+
+func TestInstitutionController_CreateInstitution(t *testing.T) {
+	mockUC := &mocks.IInstitutionUsecase{}
+	req := institutionpkg.InstitutionRequest{Name: "Addis Ababa University", Domains: []string{"aau.edu.et"}}
+	mockUC.On("CreateInstitution", mock.Anything, req, "admin").
+		Return(institutionpkg.Institution{ID: primitive.NewObjectID(), Name: req.Name, Domains: req.Domains}, nil)
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest(http.MethodPost, "/admin/institutions", strings.NewReader(`{"name":"Addis Ababa University","domains":["aau.edu.et"]}`))
+	httpReq.Header.Set("Content-Type", "application/json")
+	newInstitutionRouter(mockUC).ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"domains":["aau.edu.et"]`)
+	mockUC.AssertExpectations(t)
+}
+
+func TestInstitutionController_CreateInstitution_DomainInUse(t *testing.T) {
+	mockUC := &mocks.IInstitutionUsecase{}
+	mockUC.On("CreateInstitution", mock.Anything, mock.Anything, "admin").
+		Return(institutionpkg.Institution{}, institutionpkg.ErrDomainInUse)
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest(http.MethodPost, "/admin/institutions", strings.NewReader(`{"name":"AAU","domains":["aau.edu.et"]}`))
+	httpReq.Header.Set("Content-Type", "application/json")
+	newInstitutionRouter(mockUC).ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestInstitutionController_ListInstitutions(t *testing.T) {
+	mockUC := &mocks.IInstitutionUsecase{}
+	mockUC.On("ListInstitutions", mock.Anything, "addis").
+		Return([]institutionpkg.Institution{{Name: "Addis Ababa University"}}, nil)
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest(http.MethodGet, "/institutions?q=addis", nil)
+	newInstitutionRouter(mockUC).ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Addis Ababa University")
+	assert.NotContains(t, w.Body.String(), "createdBy")
+}
+
+func TestInstitutionController_DeleteInstitution_NotFound(t *testing.T) {
+	mockUC := &mocks.IInstitutionUsecase{}
+	mockUC.On("DeleteInstitution", mock.Anything, "missing", "admin").Return(institutionpkg.ErrInstitutionNotFound)
+
+	w := httptest.NewRecorder()
+	httpReq, _ := http.NewRequest(http.MethodDelete, "/admin/institutions/missing", nil)
+	newInstitutionRouter(mockUC).ServeHTTP(w, httpReq)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,24 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	"github.com/gin-gonic/gin"
+)
+
+// JWKSController publishes the public keys that verify our access tokens
+type JWKSController struct {
+	jwtService userpkg.IJWTService
+}
+
+func NewJWKSController(jwtService userpkg.IJWTService) *JWKSController {
+	return &JWKSController{jwtService: jwtService}
+}
+
+func (jc *JWKSController) GetJWKS(c *gin.Context) {
+	// Keys change on rotation; let verifiers cache briefly
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(userpkg.JWKSMaxAge.Seconds())))
+	c.JSON(http.StatusOK, jc.jwtService.PublicKeys())
+}
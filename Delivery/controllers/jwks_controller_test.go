@@ -0,0 +1,39 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Amaankaa/Blog-Starter-Project/Delivery/controllers"
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	"github.com/Amaankaa/Blog-Starter-Project/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJWKSController_GetJWKS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockJWT := &mocks.IJWTService{}
+	mockJWT.On("PublicKeys").Return(userpkg.JWKSet{Keys: []userpkg.JWK{
+		{KeyType: "OKP", KeyID: "key-1", Algorithm: "EdDSA", Use: "sig", Curve: "Ed25519", X: "abc"},
+	}})
+
+	router := gin.New()
+	router.GET("/.well-known/jwks.json", controllers.NewJWKSController(mockJWT).GetJWKS)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body map[string][]map[string]string
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	if assert.Len(t, body["keys"], 1) {
+		assert.Equal(t, "key-1", body["keys"][0]["kid"])
+		assert.Equal(t, "Ed25519", body["keys"][0]["crv"])
+		assert.NotContains(t, body["keys"][0], "n")
+	}
+	mockJWT.AssertExpectations(t)
+}
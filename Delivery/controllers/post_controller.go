@@ -70,6 +70,15 @@ func (ctrl *PostController) GetPosts(c *gin.Context) {
 		Tag:      c.Query("tag"),
 	}
 
+	// Only verified students of this institution
+	if institution := c.Query("institution"); institution != "" {
+		if _, err := primitive.ObjectIDFromHex(institution); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid institution ID"})
+			return
+		}
+		filter.Institution = institution
+	}
+
 	// Parse year filter
 	if yearStr := c.Query("year"); yearStr != "" {
 		if year, err := strconv.Atoi(yearStr); err == nil {
@@ -571,3 +580,55 @@ func (ctrl *PostController) GetPostsByCategory(c *gin.Context) {
 
 	c.JSON(http.StatusOK, result)
 }
+
+// HidePost handles POST /posts/:id/hide (content:moderate via router middleware)
+func (ctrl *PostController) HidePost(c *gin.Context) {
+	ctrl.moderatePost(c, true)
+}
+
+// UnhidePost handles POST /posts/:id/unhide (content:moderate via router middleware)
+func (ctrl *PostController) UnhidePost(c *gin.Context) {
+	ctrl.moderatePost(c, false)
+}
+
+func (ctrl *PostController) moderatePost(c *gin.Context, hide bool) {
+	postID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid post ID"})
+		return
+	}
+	var uidStr string
+	if v, ok := c.Get("userID"); ok {
+		uidStr = v.(string)
+	} else if v, ok := c.Get("user_id"); ok {
+		uidStr = v.(string)
+	} else {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	moderatorID, err := primitive.ObjectIDFromHex(uidStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	message := "Post hidden successfully"
+	if hide {
+		err = ctrl.postUsecase.HidePost(ctx, postID, moderatorID)
+	} else {
+		err = ctrl.postUsecase.UnhidePost(ctx, postID, moderatorID)
+		message = "Post unhidden successfully"
+	}
+	if err != nil {
+		if err.Error() == "post not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Post not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
@@ -50,6 +50,7 @@ func (s *PostControllerTestSuite) SetupTest() {
 	s.router.PATCH("/posts/:id", s.controller.UpdatePost)
 	s.router.DELETE("/posts/:id", s.controller.DeletePost)
 	s.router.POST("/posts/:id/like", s.controller.LikePost)
+	s.router.POST("/posts/:id/hide", s.controller.HidePost)
 	s.router.DELETE("/posts/:id/like", s.controller.UnlikePost)
 	s.router.GET("/posts", s.controller.GetPosts)
 	s.router.GET("/posts/search", s.controller.SearchPosts)
@@ -261,6 +262,29 @@ func (s *PostControllerTestSuite) TestLikePost_Success() {
 	s.Equal("Post liked successfully", response["message"])
 }
 
+// Test HidePost
+func (s *PostControllerTestSuite) TestHidePost_Success() {
+	// Arrange
+	postID := primitive.NewObjectID()
+	moderatorID, _ := primitive.ObjectIDFromHex("507f1f77bcf86cd799439011")
+
+	s.mockPostUsecase.On("HidePost", mock.Anything, postID, moderatorID).Return(nil)
+
+	// Act
+	w := s.performRequest("POST", "/posts/"+postID.Hex()+"/hide", nil, map[string]string{"Authorization": "Bearer token"})
+
+	// Assert
+	s.Equal(http.StatusOK, w.Code)
+}
+
+func (s *PostControllerTestSuite) TestHidePost_Unauthorized() {
+	// Act
+	w := s.performRequest("POST", "/posts/"+primitive.NewObjectID().Hex()+"/hide", nil, nil)
+
+	// Assert
+	s.Equal(http.StatusUnauthorized, w.Code)
+}
+
 // Test GetPosts
 func (s *PostControllerTestSuite) TestGetPosts_Success() {
 	// Arrange
@@ -53,6 +53,13 @@ func (ctrl *ResourceController) CreateResource(c *gin.Context) {
 // GET /resources
 func (ctrl *ResourceController) GetResources(c *gin.Context) {
 	filter := resourcepkg.ResourceFilter{Type: c.Query("type"), Category: c.Query("category"), CreatorID: c.Query("creatorId"), Tag: c.Query("tag"), Difficulty: c.Query("difficulty")}
+	if v := c.Query("institution"); v != "" {
+		if _, err := primitive.ObjectIDFromHex(v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid institution ID"})
+			return
+		}
+		filter.Institution = v
+	}
 	if v := c.Query("isVerified"); v != "" {
 		if b, err := strconv.ParseBool(v); err == nil {
 			filter.IsVerified = &b
@@ -673,7 +680,7 @@ func (ctrl *ResourceController) ReportResource(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Resource reported successfully"})
 }
 
-// POST /resources/:id/verify (admin-only via router middleware)
+// POST /resources/:id/verify (resources:verify via router middleware)
 func (ctrl *ResourceController) VerifyResource(c *gin.Context) {
 	resID, err := primitive.ObjectIDFromHex(c.Param("id"))
 	if err != nil {
@@ -706,3 +713,54 @@ func (ctrl *ResourceController) VerifyResource(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "Resource verified successfully"})
 }
+
+// POST /resources/:id/hide (content:moderate via router middleware)
+func (ctrl *ResourceController) HideResource(c *gin.Context) {
+	ctrl.moderateResource(c, true)
+}
+
+// POST /resources/:id/unhide (content:moderate via router middleware)
+func (ctrl *ResourceController) UnhideResource(c *gin.Context) {
+	ctrl.moderateResource(c, false)
+}
+
+func (ctrl *ResourceController) moderateResource(c *gin.Context, hide bool) {
+	resID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid resource ID"})
+		return
+	}
+	var uidStr string
+	if v, ok := c.Get("userID"); ok {
+		uidStr = v.(string)
+	} else if v, ok := c.Get("user_id"); ok {
+		uidStr = v.(string)
+	} else {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	moderatorID, err := primitive.ObjectIDFromHex(uidStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	message := "Resource hidden successfully"
+	if hide {
+		err = ctrl.usecase.HideResource(ctx, resID, moderatorID)
+	} else {
+		err = ctrl.usecase.UnhideResource(ctx, resID, moderatorID)
+		message = "Resource unhidden successfully"
+	}
+	if err != nil {
+		if err.Error() == "resource not found" {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Resource not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
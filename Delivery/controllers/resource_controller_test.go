@@ -65,6 +65,8 @@ func (s *ResourceControllerTestSuite) SetupTest() {
 	s.router.GET("/users/:userId/resources/stats", s.controller.GetUserResourceStats)
 	s.router.POST("/resources/:id/report", s.controller.ReportResource)
 	s.router.POST("/resources/:id/verify", s.controller.VerifyResource)
+	s.router.POST("/resources/:id/hide", s.controller.HideResource)
+	s.router.POST("/resources/:id/unhide", s.controller.UnhideResource)
 }
 
 func (s *ResourceControllerTestSuite) TearDownTest() {
@@ -538,3 +540,19 @@ func (s *ResourceControllerTestSuite) TestVerifyResource_NotFound() {
 	w := s.performRequest("POST", "/resources/"+rid.Hex()+"/verify", nil, map[string]string{"Authorization": "Bearer token"})
 	s.Equal(http.StatusNotFound, w.Code)
 }
+
+func (s *ResourceControllerTestSuite) TestHideResource_Success() {
+	rid := primitive.NewObjectID()
+	uid, _ := primitive.ObjectIDFromHex("507f1f77bcf86cd799439011")
+	s.mockResourceUsecase.On("HideResource", mock.Anything, rid, uid).Return(nil)
+	w := s.performRequest("POST", "/resources/"+rid.Hex()+"/hide", nil, map[string]string{"Authorization": "Bearer token"})
+	s.Equal(http.StatusOK, w.Code)
+}
+
+func (s *ResourceControllerTestSuite) TestUnhideResource_NotFound() {
+	rid := primitive.NewObjectID()
+	uid, _ := primitive.ObjectIDFromHex("507f1f77bcf86cd799439011")
+	s.mockResourceUsecase.On("UnhideResource", mock.Anything, rid, uid).Return(errors.New("resource not found"))
+	w := s.performRequest("POST", "/resources/"+rid.Hex()+"/unhide", nil, map[string]string{"Authorization": "Bearer token"})
+	s.Equal(http.StatusNotFound, w.Code)
+}
@@ -2,10 +2,15 @@ package controllers
 
 import (
 	"context"
+	"errors"
+	"math"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
+	institutionpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/institution"
 	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
 	"github.com/gin-gonic/gin"
 )
@@ -17,6 +22,14 @@ type Controller struct {
 	MentorshipController *MentorshipController
 	CommentController    *CommentController
 	MessagingController  *MessagingController
+	JWKSController       *JWKSController
+	AuditController      *AuditController
+
+	EmailTemplateController   *EmailTemplateController
+	EmailOutboxController     *EmailOutboxController
+	EmailDomainRuleController *EmailDomainRuleController
+	DevInboxController        *DevInboxController
+	InstitutionController     *InstitutionController
 }
 
 // Backwards-compatible constructor (without resource controller)
@@ -88,8 +101,110 @@ func (ctrl *Controller) Register(c *gin.Context) {
 
 func (ctrl *Controller) Login(c *gin.Context) {
 	var input struct {
-		Login    string `json:"login"`
-		Password string `json:"password"`
+		Login      string `json:"login"`
+		Password   string `json:"password"`
+		DeviceName string `json:"device_name"`
+	}
+
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	device := deviceInfo(c, input.DeviceName)
+	result, err := ctrl.userUsecase.LoginUser(ctx, input.Login, input.Password, device)
+	if err != nil {
+		var throttled *userpkg.LoginThrottledError
+		if errors.As(err, &throttled) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(signInErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	writeLoginResult(c, result)
+}
+
+// writeLoginResult answers a first sign-in step: tokens, or an MFA challenge
+// to finish at /login/mfa
+func writeLoginResult(c *gin.Context, result userpkg.LoginResult) {
+	if result.MFARequired() {
+		c.JSON(http.StatusOK, gin.H{
+			"mfa_required":   true,
+			"mfa_token":      result.MFAToken,
+			"mfa_expires_at": result.MFAExpiresAt,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user":          result.User,
+		"access_token":  result.AccessToken,
+		"refresh_token": result.RefreshToken,
+	})
+}
+
+// OIDCProviders lists the providers that can be used with /auth/oidc/:provider
+func (ctrl *Controller) OIDCProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"providers": ctrl.userUsecase.OIDCProviders()})
+}
+
+// oidcStateCookie ties a sign-in to the browser that started it
+const oidcStateCookie = "oidc_state"
+
+// setOIDCStateCookie sets or, with maxAge -1, clears the state cookie. It is
+// Lax rather than Strict because the callback is a redirect from the provider.
+func setOIDCStateCookie(c *gin.Context, state string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, state, maxAge, "/auth/oidc", "", gin.Mode() == gin.ReleaseMode, true)
+}
+
+// StartOIDCLogin redirects the browser to the provider's sign-in page
+func (ctrl *Controller) StartOIDCLogin(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	authURL, state, err := ctrl.userUsecase.StartOIDCLogin(ctx, c.Param("provider"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	setOIDCStateCookie(c, state, int((10 * time.Minute).Seconds()))
+	c.Header("Cache-Control", "no-store")
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// OIDCCallback is where the provider sends the browser back; it answers like /login
+func (ctrl *Controller) OIDCCallback(c *gin.Context) {
+	if reason := c.Query("error"); reason != "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "sign-in was not completed: " + reason})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	browserState, _ := c.Cookie(oidcStateCookie)
+	setOIDCStateCookie(c, "", -1)
+	result, err := ctrl.userUsecase.CompleteOIDCLogin(ctx, c.Param("provider"), c.Query("state"), browserState, c.Query("code"), deviceInfo(c, ""))
+	if err != nil {
+		c.JSON(signInErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	writeLoginResult(c, result)
+}
+
+func (ctrl *Controller) LoginMFA(c *gin.Context) {
+	var input struct {
+		MFAToken   string `json:"mfa_token"`
+		Code       string `json:"code"`
+		DeviceName string `json:"device_name"`
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -100,22 +215,23 @@ func (ctrl *Controller) Login(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	user, accessToken, refreshToken, err := ctrl.userUsecase.LoginUser(ctx, input.Login, input.Password)
+	result, err := ctrl.userUsecase.CompleteMFALogin(ctx, input.MFAToken, input.Code, deviceInfo(c, input.DeviceName))
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		c.JSON(signInErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"user":          user,
-		"access_token":  accessToken,
-		"refresh_token": refreshToken,
+		"user":          result.User,
+		"access_token":  result.AccessToken,
+		"refresh_token": result.RefreshToken,
 	})
 }
 
 func (ctrl *Controller) RefreshToken(c *gin.Context) {
 	var body struct {
 		RefreshToken string `json:"refresh_token"`
+		DeviceName   string `json:"device_name"`
 	}
 
 	if err := c.ShouldBindJSON(&body); err != nil || body.RefreshToken == "" {
@@ -126,15 +242,92 @@ func (ctrl *Controller) RefreshToken(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	newTokens, err := ctrl.userUsecase.RefreshToken(ctx, body.RefreshToken)
+	newTokens, err := ctrl.userUsecase.RefreshToken(ctx, body.RefreshToken, deviceInfo(c, body.DeviceName))
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		c.JSON(signInErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, newTokens)
 }
 
+// RequestMagicLink emails a sign-in link. The answer is the same whether or
+// not the address has an account.
+func (ctrl *Controller) RequestMagicLink(c *gin.Context) {
+	var req struct {
+		Email string `json:"email" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := ctrl.userUsecase.RequestMagicLink(ctx, req.Email, c.ClientIP()); err != nil {
+		var throttled *userpkg.OTPThrottledError
+		if errors.As(err, &throttled) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "If the address has an account, a sign-in link is on its way"})
+}
+
+// LoginMagicLink exchanges the query of a magic link for the same response
+// as /login
+func (ctrl *Controller) LoginMagicLink(c *gin.Context) {
+	var input struct {
+		Email      string `json:"email" binding:"required"`
+		Token      string `json:"token" binding:"required"`
+		Expires    string `json:"expires" binding:"required"`
+		Signature  string `json:"signature" binding:"required"`
+		DeviceName string `json:"device_name"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	expires, err := strconv.ParseInt(input.Expires, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": userpkg.ErrInvalidLink.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	result, err := ctrl.userUsecase.LoginWithMagicLink(ctx, input.Email, input.Token, time.Unix(expires, 0), input.Signature, deviceInfo(c, input.DeviceName))
+	if err != nil {
+		var throttled *userpkg.LoginThrottledError
+		if errors.As(err, &throttled) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(signInErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	writeLoginResult(c, result)
+}
+
+// signInErrorStatus is 403 for a suspended account and 401 for any other
+// failed sign-in
+func signInErrorStatus(err error) int {
+	var suspended *userpkg.AccountSuspendedError
+	if errors.As(err, &suspended) {
+		return http.StatusForbidden
+	}
+	return http.StatusUnauthorized
+}
+
 func (ctrl *Controller) ForgotPassword(c *gin.Context) {
 	var req struct{ Email string }
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -145,7 +338,13 @@ func (ctrl *Controller) ForgotPassword(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	if err := ctrl.userUsecase.SendResetOTP(ctx, req.Email); err != nil {
+	if err := ctrl.userUsecase.SendResetOTP(ctx, req.Email, c.ClientIP()); err != nil {
+		var throttled *userpkg.OTPThrottledError
+		if errors.As(err, &throttled) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -167,17 +366,22 @@ func (ctrl *Controller) VerifyOTP(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	if err := ctrl.userUsecase.VerifyOTP(ctx, req.Email, req.OTP); err != nil {
+	resetToken, err := ctrl.userUsecase.VerifyOTP(ctx, req.Email, req.OTP)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "OTP verified, you can reset your password."})
+	c.JSON(http.StatusOK, gin.H{
+		"message":     "OTP verified, you can reset your password.",
+		"reset_token": resetToken,
+	})
 }
 
 func (ctrl *Controller) ResetPassword(c *gin.Context) {
 	var req struct {
 		Email       string `json:"email"`
+		ResetToken  string `json:"reset_token"`
 		NewPassword string `json:"new_password"`
 	}
 
@@ -189,7 +393,7 @@ func (ctrl *Controller) ResetPassword(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	if err := ctrl.userUsecase.ResetPassword(ctx, req.Email, req.NewPassword); err != nil {
+	if err := ctrl.userUsecase.ResetPassword(ctx, req.Email, req.ResetToken, req.NewPassword); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -207,7 +411,7 @@ func (ctrl *Controller) Logout(c *gin.Context) {
 		return
 	}
 
-	err := ctrl.userUsecase.Logout(c.Request.Context(), userID)
+	err := ctrl.userUsecase.Logout(c.Request.Context(), userID, c.GetString("session_id"), c.GetString("token_id"))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"message": "Logout failed"})
 		return
@@ -224,136 +428,991 @@ func (ctrl *Controller) Logout(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Logged out successfully"})
 }
 
-func (ctrl *Controller) PromoteUser(c *gin.Context) {
-	userID := c.Param("id")
-	actorID, ok := c.Get("user_id")
+// Session management
+
+func (ctrl *Controller) ListSessions(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
-	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+
+	sessions, err := ctrl.userUsecase.ListSessions(ctx, userID, c.GetString("session_id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
-	if err := ctrl.userUsecase.PromoteUser(ctx, userID, actorID.(string)); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	c.JSON(http.StatusOK, gin.H{"sessions": sessions})
+}
+
+func (ctrl *Controller) RevokeSession(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "user promoted"})
-}
 
-func (ctrl *Controller) DemoteUser(c *gin.Context) {
-	userID := c.Param("id")
-	actorID, ok := c.Get("user_id")
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
-	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
-		return
-	}
-	if err := ctrl.userUsecase.DemoteUser(ctx, userID, actorID.(string)); err != nil {
+
+	if err := ctrl.userUsecase.RevokeSession(ctx, userID, c.Param("id")); err != nil {
+		if errors.Is(err, userpkg.ErrSessionNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"message": "user demoted"})
+	c.JSON(http.StatusOK, gin.H{"message": "session revoked"})
 }
 
-func (ctrl *Controller) VerifyUser(c *gin.Context) {
-	var req struct {
-		Email string `json:"email"`
-		OTP   string `json:"otp"`
-	}
-
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+func (ctrl *Controller) RevokeOtherSessions(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
 	}
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	if err := ctrl.userUsecase.VerifyUser(ctx, req.Email, req.OTP); err != nil {
+	if err := ctrl.userUsecase.RevokeOtherSessions(ctx, userID, c.GetString("session_id")); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "User verified"})
+	c.JSON(http.StatusOK, gin.H{"message": "logged out of all other sessions"})
 }
 
-func (ctrl *Controller) GetProfile(c *gin.Context) {
-
+func (ctrl *Controller) EnrollMFA(c *gin.Context) {
 	userID := c.GetString("user_id")
-
 	if userID == "" {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
 	}
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
-	user, err := ctrl.userUsecase.GetUserProfile(ctx, userID)
+
+	enrollment, err := ctrl.userUsecase.EnrollMFA(ctx, userID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, user)
+	c.JSON(http.StatusOK, enrollment)
 }
 
-func (ctrl *Controller) UpdateProfile(c *gin.Context) {
+func (ctrl *Controller) ConfirmMFA(c *gin.Context) {
 	userID := c.GetString("user_id")
 	if userID == "" {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
 	}
 
-	// Parse multipart form (10MB limit)
-	err := c.Request.ParseMultipartForm(10 << 20)
+	var req struct {
+		Code string `json:"code"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.Code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "code is required"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	codes, err := ctrl.userUsecase.ConfirmMFA(ctx, userID, req.Code)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to parse form"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	c.JSON(http.StatusOK, gin.H{
+		"message":        "Two-factor authentication enabled. Store these recovery codes somewhere safe; they will not be shown again.",
+		"recovery_codes": codes,
+	})
+}
 
-	var updates userpkg.UpdateProfileRequest
+func (ctrl *Controller) DisableMFA(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
 
-	// Get text fields
-	if fullname := c.PostForm("fullname"); fullname != "" {
-		updates.Fullname = fullname
+	var req struct {
+		Password string `json:"password"`
+		Code     string `json:"code"`
 	}
-	if bio := c.PostForm("bio"); bio != "" {
-		updates.Bio = bio
+	if err := c.ShouldBindJSON(&req); err != nil || req.Password == "" || req.Code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "password and code are required"})
+		return
 	}
-	if phone := c.PostForm("phone"); phone != "" {
-		updates.ContactInfo.Phone = phone
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := ctrl.userUsecase.DisableMFA(ctx, userID, req.Password, req.Code); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
-	if website := c.PostForm("website"); website != "" {
-		updates.ContactInfo.Website = website
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
+
+func (ctrl *Controller) RegenerateRecoveryCodes(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
 	}
-	if twitter := c.PostForm("twitter"); twitter != "" {
-		updates.ContactInfo.Twitter = twitter
+
+	var req struct {
+		Code string `json:"code"`
 	}
-	if linkedin := c.PostForm("linkedin"); linkedin != "" {
-		updates.ContactInfo.LinkedIn = linkedin
+	if err := c.ShouldBindJSON(&req); err != nil || req.Code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "code is required"})
+		return
 	}
 
-	// Handle file upload
-	file, header, err := c.Request.FormFile("profilePicture")
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	codes, err := ctrl.userUsecase.RegenerateRecoveryCodes(ctx, userID, req.Code)
 	if err != nil {
-		file = nil
-		header = nil
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
+	c.JSON(http.StatusOK, gin.H{"recovery_codes": codes})
+}
 
-	var filename string
-	if header != nil {
-		filename = header.Filename
-	} else {
-		filename = ""
+// deviceInfo collects client details for the session list
+func deviceInfo(c *gin.Context, deviceName string) userpkg.DeviceInfo {
+	return userpkg.DeviceInfo{
+		DeviceName: deviceName,
+		UserAgent:  c.Request.UserAgent(),
+		IP:         c.ClientIP(),
 	}
+}
 
+func (ctrl *Controller) PromoteUser(c *gin.Context) {
+	userID := c.Param("id")
+	actorID, ok := c.Get("user_id")
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+	if err := ctrl.userUsecase.PromoteUser(ctx, userID, actorID.(string)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "user promoted"})
+}
 
-	updatedUser, err := ctrl.userUsecase.UpdateProfile(ctx, userID, updates, file, filename)
-	if err != nil {
+func (ctrl *Controller) DemoteUser(c *gin.Context) {
+	userID := c.Param("id")
+	actorID, ok := c.Get("user_id")
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+	if err := ctrl.userUsecase.DemoteUser(ctx, userID, actorID.(string)); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	c.JSON(http.StatusOK, gin.H{"message": "user demoted"})
+}
 
-	c.JSON(http.StatusOK, updatedUser)
+// GrantRole gives a user the role in the path, replacing their current one
+func (ctrl *Controller) GrantRole(c *gin.Context) {
+	actorID := c.GetString("user_id")
+	if actorID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := ctrl.userUsecase.GrantRole(ctx, c.Param("id"), c.Param("role"), actorID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "role granted", "role": c.Param("role")})
+}
+
+// RevokeRole takes the role in the path away, leaving a regular user
+func (ctrl *Controller) RevokeRole(c *gin.Context) {
+	actorID := c.GetString("user_id")
+	if actorID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := ctrl.userUsecase.RevokeRole(ctx, c.Param("id"), c.Param("role"), actorID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "role revoked"})
+}
+
+func (ctrl *Controller) UnlockUser(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := ctrl.userUsecase.UnlockUser(ctx, c.Param("id"), c.GetString("user_id")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "user unlocked"})
+}
+
+// Admin console
+
+// ListUsers pages through users. Filters: q, role, verified, mentor,
+// suspended, signed_up_from and signed_up_to (RFC 3339 or YYYY-MM-DD).
+func (ctrl *Controller) ListUsers(c *gin.Context) {
+	filter := userpkg.UserFilter{
+		Query: c.Query("q"),
+		Role:  c.Query("role"),
+	}
+	filter.Page, _ = strconv.Atoi(c.DefaultQuery("page", "1"))
+	filter.PageSize, _ = strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+
+	for param, dst := range map[string]**bool{
+		"verified":  &filter.IsVerified,
+		"mentor":    &filter.IsMentor,
+		"suspended": &filter.Suspended,
+	} {
+		v := c.Query(param)
+		if v == "" {
+			continue
+		}
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + param + " filter"})
+			return
+		}
+		*dst = &b
+	}
+	for param, dst := range map[string]**time.Time{
+		"signed_up_from": &filter.SignedUpFrom,
+		"signed_up_to":   &filter.SignedUpTo,
+	} {
+		v := c.Query(param)
+		if v == "" {
+			continue
+		}
+		t, err := parseDateParam(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid " + param + " date"})
+			return
+		}
+		*dst = &t
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	res, err := ctrl.userUsecase.ListUsers(ctx, filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// parseDateParam accepts a full RFC 3339 timestamp or a plain date (UTC midnight)
+func parseDateParam(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", v)
+}
+
+func (ctrl *Controller) GetUserForAdmin(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	user, err := ctrl.userUsecase.GetUserForAdmin(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+// SuspendUser suspends the user until expires_at, or bans them when it is omitted
+func (ctrl *Controller) SuspendUser(c *gin.Context) {
+	actorID := c.GetString("user_id")
+	if actorID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "missing user context"})
+		return
+	}
+	var body struct {
+		Reason    string     `json:"reason"`
+		ExpiresAt *time.Time `json:"expires_at"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	user, err := ctrl.userUsecase.SuspendUser(ctx, c.Param("id"), actorID, body.Reason, body.ExpiresAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+func (ctrl *Controller) LiftSuspension(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := ctrl.userUsecase.LiftSuspension(ctx, c.Param("id"), c.GetString("user_id")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "suspension lifted"})
+}
+
+func (ctrl *Controller) ForceReverification(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := ctrl.userUsecase.ForceReverification(ctx, c.Param("id"), c.GetString("user_id")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "verification code sent"})
+}
+
+func (ctrl *Controller) ResetUserMFA(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := ctrl.userUsecase.ResetUserMFA(ctx, c.Param("id"), c.GetString("user_id")); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "two-factor authentication reset"})
+}
+
+func (ctrl *Controller) VerifyUser(c *gin.Context) {
+	var req struct {
+		Email string `json:"email"`
+		OTP   string `json:"otp"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := ctrl.userUsecase.VerifyUser(ctx, req.Email, req.OTP); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User verified"})
+}
+
+func (ctrl *Controller) GetProfile(c *gin.Context) {
+
+	userID := c.GetString("user_id")
+
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+	user, err := ctrl.userUsecase.GetUserProfile(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
+func (ctrl *Controller) UpdateProfile(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	// Parse multipart form (10MB limit)
+	err := c.Request.ParseMultipartForm(10 << 20)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to parse form"})
+		return
+	}
+
+	var updates userpkg.UpdateProfileRequest
+
+	// Get text fields
+	if fullname := c.PostForm("fullname"); fullname != "" {
+		updates.Fullname = fullname
+	}
+	if bio := c.PostForm("bio"); bio != "" {
+		updates.Bio = bio
+	}
+	if phone := c.PostForm("phone"); phone != "" {
+		updates.ContactInfo.Phone = phone
+	}
+	if website := c.PostForm("website"); website != "" {
+		updates.ContactInfo.Website = website
+	}
+	if twitter := c.PostForm("twitter"); twitter != "" {
+		updates.ContactInfo.Twitter = twitter
+	}
+	if linkedin := c.PostForm("linkedin"); linkedin != "" {
+		updates.ContactInfo.LinkedIn = linkedin
+	}
+
+	// Handle file upload
+	file, header, err := c.Request.FormFile("profilePicture")
+	if err != nil {
+		file = nil
+		header = nil
+	}
+
+	var filename string
+	if header != nil {
+		filename = header.Filename
+	} else {
+		filename = ""
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	updatedUser, err := ctrl.userUsecase.UpdateProfile(ctx, userID, updates, file, filename)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, updatedUser)
+}
+
+// ChangePassword replaces the caller's password and signs out their other sessions
+func (ctrl *Controller) ChangePassword(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req struct {
+		CurrentPassword string `json:"current_password"`
+		NewPassword     string `json:"new_password"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.CurrentPassword == "" || req.NewPassword == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "current_password and new_password are required"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := ctrl.userUsecase.ChangePassword(ctx, userID, c.GetString("session_id"), req.CurrentPassword, req.NewPassword); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "password changed; other sessions were signed out, refresh your access token to continue"})
+}
+
+// SendReauthCode emails a code that an account without a password uses in
+// place of one to delete the account or change its email
+func (ctrl *Controller) SendReauthCode(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := ctrl.userUsecase.SendReauthCode(ctx, userID, c.ClientIP()); err != nil {
+		var throttled *userpkg.OTPThrottledError
+		if errors.As(err, &throttled) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "Confirmation code sent to your email"})
+}
+
+// reauthSecret picks whichever of password and the emailed code was sent
+func reauthSecret(password, code string) string {
+	if password != "" {
+		return password
+	}
+	return code
+}
+
+// DeleteAccount schedules the caller's account for deletion after the grace period
+func (ctrl *Controller) DeleteAccount(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req struct {
+		Password string `json:"password"`
+		Code     string `json:"code"`    // from /account/reauth-code, for accounts without a password
+		Content  string `json:"content"` // "delete" or "anonymize"
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || (req.Password == "" && req.Code == "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "password or code is required"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	deletion, err := ctrl.userUsecase.RequestAccountDeletion(ctx, userID, reauthSecret(req.Password, req.Code), req.Content)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":       "Account scheduled for deletion",
+		"content":       deletion.ContentMode,
+		"scheduled_for": deletion.ScheduledFor,
+	})
+}
+
+func (ctrl *Controller) GetAccountDeletion(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	deletion, err := ctrl.userUsecase.GetAccountDeletion(ctx, userID)
+	if errors.Is(err, userpkg.ErrDeletionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load account deletion"})
+		return
+	}
+	c.JSON(http.StatusOK, deletion)
+}
+
+// CancelAccountDeletion undoes a pending deletion during the grace period
+func (ctrl *Controller) CancelAccountDeletion(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	err := ctrl.userUsecase.CancelAccountDeletion(ctx, userID)
+	switch {
+	case errors.Is(err, userpkg.ErrDeletionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, userpkg.ErrDeletionInProgress):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel account deletion"})
+	default:
+		c.JSON(http.StatusOK, gin.H{"message": "Account deletion cancelled"})
+	}
+}
+
+// RequestDataExport queues an archive of the caller's data
+func (ctrl *Controller) RequestDataExport(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	export, err := ctrl.userUsecase.RequestDataExport(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, export)
+}
+
+// GetDataExport reports an export's progress and, once ready, a download link
+func (ctrl *Controller) GetDataExport(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	export, err := ctrl.userUsecase.GetDataExport(ctx, userID, c.Param("id"))
+	if errors.Is(err, userpkg.ErrExportNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load data export"})
+		return
+	}
+	c.JSON(http.StatusOK, export)
+}
+
+// DownloadDataExport serves an archive through a signed link, so it needs no session
+func (ctrl *Controller) DownloadDataExport(c *gin.Context) {
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": userpkg.ErrInvalidLink.Error()})
+		return
+	}
+
+	archive, export, err := ctrl.userUsecase.OpenDataExport(c.Request.Context(), c.Param("id"), time.Unix(expires, 0), c.Query("signature"))
+	switch {
+	case errors.Is(err, userpkg.ErrInvalidLink):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, userpkg.ErrExportNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to open data export"})
+		return
+	}
+	defer archive.Close()
+
+	c.Header("Cache-Control", "no-store")
+	c.DataFromReader(http.StatusOK, export.Size, "application/zip", archive, map[string]string{
+		"Content-Disposition": `attachment; filename="sharespace-export-` + export.RequestedAt.UTC().Format("2006-01-02") + `.zip"`,
+	})
+}
+
+// RequestEmailChange starts moving the caller's account to a new address
+func (ctrl *Controller) RequestEmailChange(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req struct {
+		Password string `json:"password"`
+		Code     string `json:"code"` // from /account/reauth-code, for accounts without a password
+		NewEmail string `json:"new_email"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || (req.Password == "" && req.Code == "") || req.NewEmail == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "password or code, and new_email are required"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	change, err := ctrl.userUsecase.RequestEmailChange(ctx, userID, reauthSecret(req.Password, req.Code), req.NewEmail, c.ClientIP())
+	if err != nil {
+		var throttled *userpkg.OTPThrottledError
+		switch {
+		case errors.As(err, &throttled):
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		case errors.Is(err, userpkg.ErrEmailTaken):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":    "Verification code sent to the new address",
+		"new_email":  change.NewEmail,
+		"expires_at": change.ExpiresAt,
+	})
+}
+
+// ConfirmEmailChange completes a change with the codes sent to both addresses
+func (ctrl *Controller) ConfirmEmailChange(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req struct {
+		OTP         string `json:"otp"`
+		OldEmailOTP string `json:"old_email_otp"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.OTP == "" || req.OldEmailOTP == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "otp and old_email_otp are required"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	user, err := ctrl.userUsecase.ConfirmEmailChange(ctx, userID, req.OTP, req.OldEmailOTP)
+	switch {
+	case errors.Is(err, userpkg.ErrEmailChangeNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, userpkg.ErrEmailTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusOK, gin.H{"message": "Email changed", "user": user})
+	}
+}
+
+// CancelEmailChange is the link in the notice sent to the old address, so it
+// needs no session
+func (ctrl *Controller) CancelEmailChange(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	err := ctrl.userUsecase.CancelEmailChange(ctx, c.Query("user"), c.Query("signature"))
+	switch {
+	case errors.Is(err, userpkg.ErrInvalidLink):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel email change"})
+	default:
+		c.JSON(http.StatusOK, gin.H{"message": "Email change cancelled"})
+	}
+}
+
+// Verified students
+
+// RequestStudentVerification sends a code to an institutional address
+func (ctrl *Controller) RequestStudentVerification(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req struct {
+		Email string `json:"email"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.Email == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "email is required"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	pending, err := ctrl.userUsecase.RequestStudentVerification(ctx, userID, req.Email, c.ClientIP())
+	if err != nil {
+		var throttled *userpkg.OTPThrottledError
+		switch {
+		case errors.As(err, &throttled):
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		case errors.Is(err, userpkg.ErrNoInstitutionForEmail):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		case errors.Is(err, userpkg.ErrStudentEmailTaken):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":     "Verification code sent to the institutional address",
+		"email":       pending.Email,
+		"institution": pending.Institution,
+		"expires_at":  pending.ExpiresAt,
+	})
+}
+
+// ConfirmStudentVerification makes the caller a verified student with the
+// code sent to the institutional address
+func (ctrl *Controller) ConfirmStudentVerification(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req struct {
+		Email string `json:"email"`
+		OTP   string `json:"otp"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil || req.Email == "" || req.OTP == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "email and otp are required"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	user, err := ctrl.userUsecase.ConfirmStudentVerification(ctx, userID, req.Email, req.OTP)
+	switch {
+	case errors.Is(err, userpkg.ErrNoInstitutionForEmail):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, userpkg.ErrStudentEmailTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case err != nil:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusOK, gin.H{"message": "Student status verified", "student": user.Student})
+	}
+}
+
+func (ctrl *Controller) RemoveStudentStatus(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := ctrl.userUsecase.RemoveStudentStatus(ctx, userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetPublicProfile shows what other users may see of an account
+func (ctrl *Controller) GetPublicProfile(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	profile, err := ctrl.userUsecase.GetPublicProfile(ctx, c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	c.JSON(http.StatusOK, profile)
+}
+
+// FindMentors searches available mentors. ?topics is a comma-separated list
+// matching any of them; ?institution keeps only that institution's verified
+// students.
+func (ctrl *Controller) FindMentors(c *gin.Context) {
+	var topics []string
+	for _, topic := range strings.Split(c.Query("topics"), ",") {
+		if topic = strings.TrimSpace(topic); topic != "" {
+			topics = append(topics, topic)
+		}
+	}
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	offset, _ := strconv.Atoi(c.Query("offset"))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	mentors, err := ctrl.userUsecase.FindMentors(ctx, topics, c.Query("institution"), limit, offset)
+	switch {
+	case errors.Is(err, institutionpkg.ErrInstitutionNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid institution ID"})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	default:
+		if mentors == nil {
+			mentors = []userpkg.PublicProfile{}
+		}
+		c.JSON(http.StatusOK, gin.H{"mentors": mentors})
+	}
+}
+
+// Personal access tokens
+
+// CreateAccessToken issues a scoped token; its secret is only in this response
+func (ctrl *Controller) CreateAccessToken(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	var body struct {
+		Name      string     `json:"name" binding:"required"`
+		Scopes    []string   `json:"scopes" binding:"required"`
+		ExpiresAt *time.Time `json:"expires_at"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	token, err := ctrl.userUsecase.CreateAccessToken(ctx, userID, body.Name, body.Scopes, body.ExpiresAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusCreated, token)
+}
+
+func (ctrl *Controller) ListAccessTokens(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	tokens, err := ctrl.userUsecase.ListAccessTokens(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"tokens": tokens, "scopes": userpkg.TokenScopes})
+}
+
+func (ctrl *Controller) RevokeAccessToken(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	if err := ctrl.userUsecase.RevokeAccessToken(ctx, userID, c.Param("id")); err != nil {
+		if errors.Is(err, userpkg.ErrAccessTokenNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "access token revoked"})
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -42,7 +43,43 @@ func (s *ControllerTestSuite) SetupTest() {
 		c.Next()
 	}
 	s.router.PUT("/user/:id/promote", addActor, ctrl.PromoteUser)
+	addSession := func(c *gin.Context) {
+		c.Set("user_id", "user123")
+		c.Set("session_id", "session123")
+		c.Next()
+	}
+	s.router.GET("/sessions", addSession, ctrl.ListSessions)
+	s.router.DELETE("/sessions/:id", addSession, ctrl.RevokeSession)
+	s.router.POST("/sessions/logout-others", addSession, ctrl.RevokeOtherSessions)
+	s.router.POST("/login/mfa", ctrl.LoginMFA)
+	s.router.POST("/login/magic", ctrl.RequestMagicLink)
+	s.router.POST("/login/magic/verify", ctrl.LoginMagicLink)
+	s.router.GET("/auth/oidc/:provider", ctrl.StartOIDCLogin)
+	s.router.GET("/auth/oidc/:provider/callback", ctrl.OIDCCallback)
+	s.router.POST("/mfa/confirm", addSession, ctrl.ConfirmMFA)
+	s.router.POST("/mfa/disable", addSession, ctrl.DisableMFA)
 	s.router.PUT("/user/:id/demote", addActor, ctrl.DemoteUser)
+	s.router.PUT("/user/:id/roles/:role", addActor, ctrl.GrantRole)
+	s.router.DELETE("/user/:id/roles/:role", addActor, ctrl.RevokeRole)
+	s.router.PUT("/user/:id/unlock", addActor, ctrl.UnlockUser)
+	s.router.GET("/admin/users", addActor, ctrl.ListUsers)
+	s.router.GET("/admin/users/:id", addActor, ctrl.GetUserForAdmin)
+	s.router.POST("/admin/users/:id/suspend", addActor, ctrl.SuspendUser)
+	s.router.POST("/account/reauth-code", addSession, ctrl.SendReauthCode)
+	s.router.DELETE("/account", addSession, ctrl.DeleteAccount)
+	s.router.DELETE("/account/deletion", addSession, ctrl.CancelAccountDeletion)
+	s.router.PUT("/account/password", addSession, ctrl.ChangePassword)
+	s.router.POST("/account/email", addSession, ctrl.RequestEmailChange)
+	s.router.POST("/account/email/confirm", addSession, ctrl.ConfirmEmailChange)
+	s.router.GET("/account/email/cancel", ctrl.CancelEmailChange)
+	s.router.POST("/account/student", addSession, ctrl.RequestStudentVerification)
+	s.router.POST("/account/student/confirm", addSession, ctrl.ConfirmStudentVerification)
+	s.router.GET("/mentors", addSession, ctrl.FindMentors)
+	s.router.POST("/account/export", addSession, ctrl.RequestDataExport)
+	s.router.GET("/account/export/:id", addSession, ctrl.GetDataExport)
+	s.router.GET("/account/export/:id/download", ctrl.DownloadDataExport)
+	s.router.POST("/tokens", addSession, ctrl.CreateAccessToken)
+	s.router.DELETE("/tokens/:id", addSession, ctrl.RevokeAccessToken)
 }
 
 func (s *ControllerTestSuite) performRequest(method, path string, body interface{}) *httptest.ResponseRecorder {
@@ -72,7 +109,7 @@ func (s *ControllerTestSuite) TestRefreshToken_Success() {
 		AccessExpiresAt:  time.Now().Add(1 * time.Hour),
 		RefreshExpiresAt: time.Now().Add(24 * time.Hour),
 	}
-	s.mockUC.On("RefreshToken", mock.Anything, "valid-refresh-token").
+	s.mockUC.On("RefreshToken", mock.Anything, "valid-refresh-token", mock.Anything).
 		Return(expected, nil)
 
 	w := s.performRequest("POST", "/refresh", map[string]string{"refresh_token": "valid-refresh-token"})
@@ -92,7 +129,7 @@ func (s *ControllerTestSuite) TestRefreshToken_MissingToken() {
 }
 
 func (s *ControllerTestSuite) TestRefreshToken_InvalidToken() {
-	s.mockUC.On("RefreshToken", mock.Anything, "bad-token").
+	s.mockUC.On("RefreshToken", mock.Anything, "bad-token", mock.Anything).
 		Return(userpkg.TokenResult{}, errors.New("unauthorized"))
 
 	w := s.performRequest("POST", "/refresh", map[string]string{"refresh_token": "bad-token"})
@@ -100,9 +137,10 @@ func (s *ControllerTestSuite) TestRefreshToken_InvalidToken() {
 }
 
 func (s *ControllerTestSuite) TestVerifyOTP_Success() {
-	s.mockUC.On("VerifyOTP", mock.Anything, "test@example.com", "123456").Return(nil)
+	s.mockUC.On("VerifyOTP", mock.Anything, "test@example.com", "123456").Return("reset-grant", nil)
 	w := s.performRequest("POST", "/verify-otp", map[string]string{"email": "test@example.com", "otp": "123456"})
 	s.Equal(http.StatusOK, w.Code)
+	s.Contains(w.Body.String(), "reset-grant")
 }
 
 func (s *ControllerTestSuite) TestVerifyOTP_InvalidJSON() {
@@ -114,14 +152,14 @@ func (s *ControllerTestSuite) TestVerifyOTP_InvalidJSON() {
 }
 
 func (s *ControllerTestSuite) TestVerifyOTP_WrongOTP() {
-	s.mockUC.On("VerifyOTP", mock.Anything, "test@example.com", "wrong").Return(errors.New("invalid otp"))
+	s.mockUC.On("VerifyOTP", mock.Anything, "test@example.com", "wrong").Return("", errors.New("invalid otp"))
 	w := s.performRequest("POST", "/verify-otp", map[string]string{"email": "test@example.com", "otp": "wrong"})
 	s.Equal(http.StatusBadRequest, w.Code)
 }
 
 func (s *ControllerTestSuite) TestResetPassword_Success() {
-	s.mockUC.On("ResetPassword", mock.Anything, "test@example.com", "NewPass@123").Return(nil)
-	w := s.performRequest("POST", "/reset-password", map[string]string{"email": "test@example.com", "new_password": "NewPass@123"})
+	s.mockUC.On("ResetPassword", mock.Anything, "test@example.com", "reset-grant", "NewPass@123").Return(nil)
+	w := s.performRequest("POST", "/reset-password", map[string]string{"email": "test@example.com", "reset_token": "reset-grant", "new_password": "NewPass@123"})
 	s.Equal(http.StatusOK, w.Code)
 }
 
@@ -134,8 +172,8 @@ func (s *ControllerTestSuite) TestResetPassword_InvalidJSON() {
 }
 
 func (s *ControllerTestSuite) TestResetPassword_WeakPassword() {
-	s.mockUC.On("ResetPassword", mock.Anything, "test@example.com", "123").Return(errors.New("weak password"))
-	w := s.performRequest("POST", "/reset-password", map[string]string{"email": "test@example.com", "new_password": "123"})
+	s.mockUC.On("ResetPassword", mock.Anything, "test@example.com", "reset-grant", "123").Return(errors.New("weak password"))
+	w := s.performRequest("POST", "/reset-password", map[string]string{"email": "test@example.com", "reset_token": "reset-grant", "new_password": "123"})
 	s.Equal(http.StatusBadRequest, w.Code)
 }
 
@@ -174,8 +212,8 @@ func (s *ControllerTestSuite) TestRegister_VerificationFail() {
 }
 
 func (s *ControllerTestSuite) TestLogin_InvalidCredentials() {
-	s.mockUC.On("LoginUser", mock.Anything, "user1", "wrongpass").
-		Return(userpkg.User{}, "", "", errors.New("invalid credentials"))
+	s.mockUC.On("LoginUser", mock.Anything, "user1", "wrongpass", mock.Anything).
+		Return(userpkg.LoginResult{}, errors.New("invalid credentials"))
 
 	w := s.performRequest("POST", "/login", map[string]string{"login": "user1", "password": "wrongpass"})
 	s.Equal(http.StatusUnauthorized, w.Code)
@@ -183,7 +221,7 @@ func (s *ControllerTestSuite) TestLogin_InvalidCredentials() {
 
 func (s *ControllerTestSuite) TestLogin_Unverified() {
 	// usecase.LoginUser returns error "email not verified"
-	s.mockUC.On("LoginUser", mock.Anything, "user1", "pass").Return(userpkg.User{}, "", "", errors.New("email not verified"))
+	s.mockUC.On("LoginUser", mock.Anything, "user1", "pass", mock.Anything).Return(userpkg.LoginResult{}, errors.New("email not verified"))
 
 	w := s.performRequest("POST", "/login", map[string]string{"login": "user1", "password": "pass"})
 	s.Equal(http.StatusUnauthorized, w.Code)
@@ -211,6 +249,23 @@ func (s *ControllerTestSuite) TestPromoteUser_Error() {
 	s.Contains(w.Body.String(), "fail to promote")
 }
 
+func (s *ControllerTestSuite) TestGrantRole_Success() {
+	s.mockUC.On("GrantRole", mock.Anything, "user123", "verifier", "admin999").Return(nil)
+
+	w := s.performRequest("PUT", "/user/user123/roles/verifier", nil)
+
+	s.Equal(http.StatusOK, w.Code)
+	s.Contains(w.Body.String(), "role granted")
+}
+
+func (s *ControllerTestSuite) TestRevokeRole_NotHeld() {
+	s.mockUC.On("RevokeRole", mock.Anything, "user123", "moderator", "admin999").Return(errors.New("user does not have this role"))
+
+	w := s.performRequest("DELETE", "/user/user123/roles/moderator", nil)
+
+	s.Equal(http.StatusBadRequest, w.Code)
+}
+
 func (s *ControllerTestSuite) TestDemoteUser_Success() {
 	id := "user456"
 	s.mockUC.On("DemoteUser", mock.Anything, id, "admin999").Return(nil)
@@ -270,6 +325,404 @@ func (s *ControllerTestSuite) TestGetProfile_Unauthorized() {
 	s.Contains(w.Body.String(), "Unauthorized")
 }
 
+func (s *ControllerTestSuite) TestListSessions_Success() {
+	sessions := []userpkg.Session{{DeviceName: "Phone", Current: true}}
+	s.mockUC.On("ListSessions", mock.Anything, "user123", "session123").Return(sessions, nil)
+
+	w := s.performRequest("GET", "/sessions", nil)
+
+	s.Equal(http.StatusOK, w.Code)
+	s.Contains(w.Body.String(), "Phone")
+}
+
+func (s *ControllerTestSuite) TestRevokeSession_NotFound() {
+	s.mockUC.On("RevokeSession", mock.Anything, "user123", "abc").Return(userpkg.ErrSessionNotFound)
+
+	w := s.performRequest("DELETE", "/sessions/abc", nil)
+
+	s.Equal(http.StatusNotFound, w.Code)
+}
+
+func (s *ControllerTestSuite) TestRevokeOtherSessions_Success() {
+	s.mockUC.On("RevokeOtherSessions", mock.Anything, "user123", "session123").Return(nil)
+
+	w := s.performRequest("POST", "/sessions/logout-others", nil)
+
+	s.Equal(http.StatusOK, w.Code)
+	s.mockUC.AssertExpectations(s.T())
+}
+
+func (s *ControllerTestSuite) TestLogin_Throttled() {
+	s.mockUC.On("LoginUser", mock.Anything, "user1", "pass", mock.Anything).
+		Return(userpkg.LoginResult{}, &userpkg.LoginThrottledError{Locked: true, RetryAfter: 90500 * time.Millisecond})
+
+	w := s.performRequest("POST", "/login", map[string]string{"login": "user1", "password": "pass"})
+
+	s.Equal(http.StatusTooManyRequests, w.Code)
+	s.Equal("91", w.Header().Get("Retry-After"))
+	s.Contains(w.Body.String(), "locked")
+}
+
+func (s *ControllerTestSuite) TestLogin_Suspended() {
+	s.mockUC.On("LoginUser", mock.Anything, "user1", "pass", mock.Anything).
+		Return(userpkg.LoginResult{}, &userpkg.AccountSuspendedError{Reason: "spam"})
+
+	w := s.performRequest("POST", "/login", map[string]string{"login": "user1", "password": "pass"})
+
+	s.Equal(http.StatusForbidden, w.Code)
+	s.Contains(w.Body.String(), "account banned: spam")
+}
+
+func (s *ControllerTestSuite) TestListUsers_ParsesFilters() {
+	s.mockUC.On("ListUsers", mock.Anything, mock.MatchedBy(func(f userpkg.UserFilter) bool {
+		return f.Query == "bob" && f.Role == "moderator" && f.IsMentor != nil && *f.IsMentor &&
+			f.IsVerified == nil && f.SignedUpFrom != nil && f.SignedUpFrom.Equal(time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)) &&
+			f.Page == 2 && f.PageSize == 50
+	})).Return(userpkg.UserListResponse{Total: 0, Page: 2, PageSize: 50}, nil)
+
+	w := s.performRequest("GET", "/admin/users?q=bob&role=moderator&mentor=true&signed_up_from=2025-01-02&page=2&pageSize=50", nil)
+
+	s.Equal(http.StatusOK, w.Code)
+}
+
+func (s *ControllerTestSuite) TestListUsers_InvalidFilter() {
+	w := s.performRequest("GET", "/admin/users?suspended=maybe", nil)
+
+	s.Equal(http.StatusBadRequest, w.Code)
+}
+
+func (s *ControllerTestSuite) TestGetUserForAdmin_NotFound() {
+	s.mockUC.On("GetUserForAdmin", mock.Anything, "user123").Return(userpkg.AdminUserView{}, errors.New("mongo: no documents in result"))
+
+	w := s.performRequest("GET", "/admin/users/user123", nil)
+
+	s.Equal(http.StatusNotFound, w.Code)
+}
+
+func (s *ControllerTestSuite) TestSuspendUser_Success() {
+	until := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	s.mockUC.On("SuspendUser", mock.Anything, "user123", "admin999", "spam", mock.MatchedBy(func(t *time.Time) bool {
+		return t != nil && t.Equal(until)
+	})).Return(userpkg.AdminUserView{}, nil)
+
+	w := s.performRequest("POST", "/admin/users/user123/suspend", map[string]string{"reason": "spam", "expires_at": "2030-01-01T00:00:00Z"})
+
+	s.Equal(http.StatusOK, w.Code)
+}
+
+func (s *ControllerTestSuite) TestForgotPassword_Throttled() {
+	s.mockUC.On("SendResetOTP", mock.Anything, "a@b.com", mock.Anything).
+		Return(&userpkg.OTPThrottledError{RetryAfter: 42 * time.Second})
+
+	w := s.performRequest("POST", "/forgot-password", map[string]string{"email": "a@b.com"})
+
+	s.Equal(http.StatusTooManyRequests, w.Code)
+	s.Equal("42", w.Header().Get("Retry-After"))
+}
+
+func (s *ControllerTestSuite) TestUnlockUser_Success() {
+	s.mockUC.On("UnlockUser", mock.Anything, "user42", "admin999").Return(nil)
+
+	w := s.performRequest("PUT", "/user/user42/unlock", nil)
+
+	s.Equal(http.StatusOK, w.Code)
+	s.mockUC.AssertExpectations(s.T())
+}
+
+func (s *ControllerTestSuite) TestLogin_MFARequired() {
+	s.mockUC.On("LoginUser", mock.Anything, "user1", "pass", mock.Anything).
+		Return(userpkg.LoginResult{MFAToken: "challenge", MFAExpiresAt: time.Now().Add(5 * time.Minute)}, nil)
+
+	w := s.performRequest("POST", "/login", map[string]string{"login": "user1", "password": "pass"})
+
+	s.Equal(http.StatusOK, w.Code)
+	var body map[string]interface{}
+	s.NoError(json.Unmarshal(w.Body.Bytes(), &body))
+	s.Equal(true, body["mfa_required"])
+	s.Equal("challenge", body["mfa_token"])
+	s.NotContains(body, "access_token")
+}
+
+func (s *ControllerTestSuite) TestLoginMFA_Success() {
+	s.mockUC.On("CompleteMFALogin", mock.Anything, "challenge", "123456", mock.Anything).
+		Return(userpkg.LoginResult{AccessToken: "access", RefreshToken: "refresh"}, nil)
+
+	w := s.performRequest("POST", "/login/mfa", map[string]string{"mfa_token": "challenge", "code": "123456"})
+
+	s.Equal(http.StatusOK, w.Code)
+	s.Contains(w.Body.String(), `"access_token":"access"`)
+}
+
+func (s *ControllerTestSuite) TestLoginMFA_InvalidCode() {
+	s.mockUC.On("CompleteMFALogin", mock.Anything, "challenge", "000000", mock.Anything).
+		Return(userpkg.LoginResult{}, errors.New("invalid mfa code"))
+
+	w := s.performRequest("POST", "/login/mfa", map[string]string{"mfa_token": "challenge", "code": "000000"})
+
+	s.Equal(http.StatusUnauthorized, w.Code)
+}
+
+func (s *ControllerTestSuite) TestConfirmMFA_ReturnsRecoveryCodes() {
+	s.mockUC.On("ConfirmMFA", mock.Anything, "user123", "123456").Return([]string{"abcde-fghij"}, nil)
+
+	w := s.performRequest("POST", "/mfa/confirm", map[string]string{"code": "123456"})
+
+	s.Equal(http.StatusOK, w.Code)
+	s.Contains(w.Body.String(), "abcde-fghij")
+}
+
+func (s *ControllerTestSuite) TestDisableMFA_MissingFields() {
+	w := s.performRequest("POST", "/mfa/disable", map[string]string{"code": "123456"})
+
+	s.Equal(http.StatusBadRequest, w.Code)
+	s.mockUC.AssertNotCalled(s.T(), "DisableMFA", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestControllerTestSuite(t *testing.T) {
 	suite.Run(t, new(ControllerTestSuite))
 }
+
+func (s *ControllerTestSuite) TestDeleteAccount_Scheduled() {
+	scheduled := time.Now().Add(7 * 24 * time.Hour)
+	s.mockUC.On("RequestAccountDeletion", mock.Anything, "user123", "secret", userpkg.DeletionContentAnonymize).
+		Return(userpkg.AccountDeletion{ContentMode: userpkg.DeletionContentAnonymize, ScheduledFor: scheduled}, nil)
+
+	w := s.performRequest("DELETE", "/account", map[string]string{"password": "secret", "content": "anonymize"})
+
+	s.Equal(http.StatusAccepted, w.Code)
+	s.Contains(w.Body.String(), "scheduled_for")
+	s.mockUC.AssertExpectations(s.T())
+}
+
+func (s *ControllerTestSuite) TestDeleteAccount_MissingPassword() {
+	w := s.performRequest("DELETE", "/account", map[string]string{"content": "delete"})
+
+	s.Equal(http.StatusBadRequest, w.Code)
+	s.mockUC.AssertNotCalled(s.T(), "RequestAccountDeletion")
+}
+
+func (s *ControllerTestSuite) TestDeleteAccount_WithEmailedCode() {
+	s.mockUC.On("RequestAccountDeletion", mock.Anything, "user123", "123456", userpkg.DeletionContentDelete).
+		Return(userpkg.AccountDeletion{ContentMode: userpkg.DeletionContentDelete}, nil)
+
+	w := s.performRequest("DELETE", "/account", map[string]string{"code": "123456", "content": "delete"})
+
+	s.Equal(http.StatusAccepted, w.Code)
+	s.mockUC.AssertExpectations(s.T())
+}
+
+func (s *ControllerTestSuite) TestSendReauthCode_Throttled() {
+	s.mockUC.On("SendReauthCode", mock.Anything, "user123", mock.Anything).
+		Return(&userpkg.OTPThrottledError{RetryAfter: 30 * time.Second})
+
+	w := s.performRequest("POST", "/account/reauth-code", nil)
+
+	s.Equal(http.StatusTooManyRequests, w.Code)
+	s.Equal("30", w.Header().Get("Retry-After"))
+}
+
+func (s *ControllerTestSuite) TestCancelAccountDeletion_InProgress() {
+	s.mockUC.On("CancelAccountDeletion", mock.Anything, "user123").Return(userpkg.ErrDeletionInProgress)
+
+	w := s.performRequest("DELETE", "/account/deletion", nil)
+
+	s.Equal(http.StatusConflict, w.Code)
+}
+
+func (s *ControllerTestSuite) TestRequestDataExport_Accepted() {
+	s.mockUC.On("RequestDataExport", mock.Anything, "user123").
+		Return(userpkg.DataExport{Status: userpkg.ExportPending}, nil)
+
+	w := s.performRequest("POST", "/account/export", nil)
+
+	s.Equal(http.StatusAccepted, w.Code)
+	s.Contains(w.Body.String(), `"status":"pending"`)
+}
+
+func (s *ControllerTestSuite) TestGetDataExport_NotFound() {
+	s.mockUC.On("GetDataExport", mock.Anything, "user123", "exp1").Return(userpkg.DataExport{}, userpkg.ErrExportNotFound)
+
+	w := s.performRequest("GET", "/account/export/exp1", nil)
+
+	s.Equal(http.StatusNotFound, w.Code)
+}
+
+func (s *ControllerTestSuite) TestDownloadDataExport_ServesZip() {
+	s.mockUC.On("OpenDataExport", mock.Anything, "exp1", time.Unix(1700000000, 0), "sig").
+		Return(io.NopCloser(bytes.NewReader([]byte("PK"))), userpkg.DataExport{Size: 2}, nil)
+
+	w := s.performRequest("GET", "/account/export/exp1/download?expires=1700000000&signature=sig", nil)
+
+	s.Equal(http.StatusOK, w.Code)
+	s.Equal("application/zip", w.Header().Get("Content-Type"))
+	s.Contains(w.Header().Get("Content-Disposition"), "attachment")
+	s.Equal("PK", w.Body.String())
+}
+
+func (s *ControllerTestSuite) TestDownloadDataExport_InvalidLink() {
+	w := s.performRequest("GET", "/account/export/exp1/download?signature=sig", nil)
+
+	s.Equal(http.StatusForbidden, w.Code)
+	s.mockUC.AssertNotCalled(s.T(), "OpenDataExport", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *ControllerTestSuite) TestChangePassword_Success() {
+	s.mockUC.On("ChangePassword", mock.Anything, "user123", "session123", "old secret", "a much better passphrase").Return(nil)
+
+	w := s.performRequest("PUT", "/account/password", map[string]string{"current_password": "old secret", "new_password": "a much better passphrase"})
+
+	s.Equal(http.StatusOK, w.Code)
+}
+
+func (s *ControllerTestSuite) TestChangePassword_MissingFields() {
+	w := s.performRequest("PUT", "/account/password", map[string]string{"new_password": "a much better passphrase"})
+
+	s.Equal(http.StatusBadRequest, w.Code)
+	s.mockUC.AssertNotCalled(s.T(), "ChangePassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *ControllerTestSuite) TestRequestEmailChange_Taken() {
+	s.mockUC.On("RequestEmailChange", mock.Anything, "user123", "secret", "new@example.com", mock.Anything).
+		Return(userpkg.EmailChange{}, userpkg.ErrEmailTaken)
+
+	w := s.performRequest("POST", "/account/email", map[string]string{"password": "secret", "new_email": "new@example.com"})
+
+	s.Equal(http.StatusConflict, w.Code)
+}
+
+func (s *ControllerTestSuite) TestConfirmEmailChange_Success() {
+	s.mockUC.On("ConfirmEmailChange", mock.Anything, "user123", "123456", "654321").
+		Return(userpkg.User{Email: "new@example.com"}, nil)
+
+	w := s.performRequest("POST", "/account/email/confirm", map[string]string{"otp": "123456", "old_email_otp": "654321"})
+
+	s.Equal(http.StatusOK, w.Code)
+	s.Contains(w.Body.String(), "new@example.com")
+}
+
+func (s *ControllerTestSuite) TestCancelEmailChange_InvalidLink() {
+	s.mockUC.On("CancelEmailChange", mock.Anything, "user123", "bad").Return(userpkg.ErrInvalidLink)
+
+	w := s.performRequest("GET", "/account/email/cancel?user=user123&signature=bad", nil)
+
+	s.Equal(http.StatusForbidden, w.Code)
+}
+
+func (s *ControllerTestSuite) TestRequestStudentVerification_UnknownDomain() {
+	s.mockUC.On("RequestStudentVerification", mock.Anything, "user123", "me@gmail.com", mock.Anything).
+		Return(userpkg.StudentVerification{}, userpkg.ErrNoInstitutionForEmail)
+
+	w := s.performRequest("POST", "/account/student", map[string]string{"email": "me@gmail.com"})
+
+	s.Equal(http.StatusUnprocessableEntity, w.Code)
+}
+
+func (s *ControllerTestSuite) TestConfirmStudentVerification_Success() {
+	s.mockUC.On("ConfirmStudentVerification", mock.Anything, "user123", "me@aau.edu.et", "123456").
+		Return(userpkg.User{Student: &userpkg.StudentStatus{Institution: "Addis Ababa University", Email: "me@aau.edu.et"}}, nil)
+
+	w := s.performRequest("POST", "/account/student/confirm", map[string]string{"email": "me@aau.edu.et", "otp": "123456"})
+
+	s.Equal(http.StatusOK, w.Code)
+	s.Contains(w.Body.String(), "Addis Ababa University")
+}
+
+func (s *ControllerTestSuite) TestFindMentors_ParsesFilters() {
+	s.mockUC.On("FindMentors", mock.Anything, []string{"go", "ml"}, "inst1", 10, 0).
+		Return([]userpkg.PublicProfile{{DisplayName: "mentor", VerifiedStudent: true}}, nil)
+
+	w := s.performRequest("GET", "/mentors?topics=go,%20ml&institution=inst1&limit=10", nil)
+
+	s.Equal(http.StatusOK, w.Code)
+	s.Contains(w.Body.String(), `"verifiedStudent":true`)
+}
+
+func (s *ControllerTestSuite) TestCreateAccessToken_ReturnsSecretOnce() {
+	s.mockUC.On("CreateAccessToken", mock.Anything, "user123", "ci", []string{userpkg.ScopeResourcesWrite}, (*time.Time)(nil)).
+		Return(userpkg.CreatedAccessToken{
+			PersonalAccessToken: userpkg.PersonalAccessToken{Name: "ci", TokenHash: "hash"},
+			Token:               "ssp_secret",
+		}, nil)
+
+	w := s.performRequest("POST", "/tokens", map[string]interface{}{"name": "ci", "scopes": []string{"resources:write"}})
+
+	s.Equal(http.StatusCreated, w.Code)
+	s.Contains(w.Body.String(), `"token":"ssp_secret"`)
+	s.NotContains(w.Body.String(), "hash")
+}
+
+func (s *ControllerTestSuite) TestRevokeAccessToken_NotFound() {
+	s.mockUC.On("RevokeAccessToken", mock.Anything, "user123", "tok1").Return(userpkg.ErrAccessTokenNotFound)
+
+	w := s.performRequest("DELETE", "/tokens/tok1", nil)
+
+	s.Equal(http.StatusNotFound, w.Code)
+}
+
+func (s *ControllerTestSuite) TestLoginMagicLink_Success() {
+	s.mockUC.On("LoginWithMagicLink", mock.Anything, "a@b.com", "tok", time.Unix(1700000000, 0), "sig", mock.Anything).
+		Return(userpkg.LoginResult{AccessToken: "access", RefreshToken: "refresh"}, nil)
+
+	w := s.performRequest("POST", "/login/magic/verify", map[string]string{
+		"email": "a@b.com", "token": "tok", "expires": "1700000000", "signature": "sig",
+	})
+
+	s.Equal(http.StatusOK, w.Code)
+	s.Contains(w.Body.String(), `"access_token":"access"`)
+}
+
+func (s *ControllerTestSuite) TestLoginMagicLink_InvalidLink() {
+	s.mockUC.On("LoginWithMagicLink", mock.Anything, "a@b.com", "tok", time.Unix(1700000000, 0), "sig", mock.Anything).
+		Return(userpkg.LoginResult{}, userpkg.ErrInvalidLink)
+
+	w := s.performRequest("POST", "/login/magic/verify", map[string]string{
+		"email": "a@b.com", "token": "tok", "expires": "1700000000", "signature": "sig",
+	})
+
+	s.Equal(http.StatusUnauthorized, w.Code)
+}
+
+func (s *ControllerTestSuite) TestRequestMagicLink_Throttled() {
+	s.mockUC.On("RequestMagicLink", mock.Anything, "a@b.com", mock.Anything).
+		Return(&userpkg.OTPThrottledError{RetryAfter: 30 * time.Second})
+
+	w := s.performRequest("POST", "/login/magic", map[string]string{"email": "a@b.com"})
+
+	s.Equal(http.StatusTooManyRequests, w.Code)
+	s.Equal("30", w.Header().Get("Retry-After"))
+}
+
+func (s *ControllerTestSuite) TestStartOIDCLogin_Redirects() {
+	s.mockUC.On("StartOIDCLogin", mock.Anything, "google").Return("https://accounts.example.com/authorize?state=x", "x", nil)
+
+	w := s.performRequest("GET", "/auth/oidc/google", nil)
+
+	s.Equal(http.StatusFound, w.Code)
+	s.Equal("https://accounts.example.com/authorize?state=x", w.Header().Get("Location"))
+	cookie := w.Result().Cookies()[0]
+	s.Equal("oidc_state", cookie.Name)
+	s.Equal("x", cookie.Value)
+	s.True(cookie.HttpOnly)
+	s.Equal(http.SameSiteLaxMode, cookie.SameSite)
+}
+
+func (s *ControllerTestSuite) TestOIDCCallback_Success() {
+	s.mockUC.On("CompleteOIDCLogin", mock.Anything, "google", "st", "st", "cd", mock.Anything).
+		Return(userpkg.LoginResult{AccessToken: "access", RefreshToken: "refresh"}, nil)
+
+	req := httptest.NewRequest("GET", "/auth/oidc/google/callback?state=st&code=cd", nil)
+	req.AddCookie(&http.Cookie{Name: "oidc_state", Value: "st"})
+	w := httptest.NewRecorder()
+	s.router.ServeHTTP(w, req)
+
+	s.Equal(http.StatusOK, w.Code)
+	s.Contains(w.Body.String(), `"access_token":"access"`)
+	s.Equal(-1, w.Result().Cookies()[0].MaxAge, "the state cookie is cleared")
+}
+
+func (s *ControllerTestSuite) TestOIDCCallback_ProviderError() {
+	w := s.performRequest("GET", "/auth/oidc/google/callback?error=access_denied", nil)
+
+	s.Equal(http.StatusUnauthorized, w.Code)
+	s.Contains(w.Body.String(), "access_denied")
+}
@@ -4,15 +4,18 @@ import (
 	"context"
 	"log"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/Amaankaa/Blog-Starter-Project/Delivery/controllers"
 	"github.com/Amaankaa/Blog-Starter-Project/Delivery/routers"
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
 	infrastructure "github.com/Amaankaa/Blog-Starter-Project/Infrastructure"
 	repositories "github.com/Amaankaa/Blog-Starter-Project/Repositories"
 	usecases "github.com/Amaankaa/Blog-Starter-Project/Usecases"
 
 	"github.com/joho/godotenv"
+	"github.com/redis/go-redis/v9"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
@@ -47,31 +50,66 @@ func main() {
 	commentCollection := db.Collection("comments")
 	conversationsCollection := db.Collection("conversations")
 	messagesCollection := db.Collection("messages")
+	revokedTokensCollection := db.Collection("revoked_tokens")
+	jwtKeysCollection := db.Collection("jwt_keys")
+	mfaChallengesCollection := db.Collection("mfa_challenges")
+	loginAttemptsCollection := db.Collection("login_attempts")
+	otpSendsCollection := db.Collection("otp_sends")
+	accountDeletionsCollection := db.Collection("account_deletions")
+	mentorshipRequestsCollection := db.Collection("mentorship_requests")
+	mentorshipConnectionsCollection := db.Collection("mentorship_connections")
+	dataExportsCollection := db.Collection("data_exports")
+	emailChangesCollection := db.Collection("email_changes")
+	auditLogCollection := db.Collection("audit_log")
+	accessTokensCollection := db.Collection("personal_access_tokens")
+	magicLinksCollection := db.Collection("magic_links")
+	oauthStatesCollection := db.Collection("oauth_states")
+	emailOutboxCollection := db.Collection("email_outbox")
+	emailDomainRulesCollection := db.Collection("email_domain_rules")
+	institutionsCollection := db.Collection("institutions")
 
 	// Initialize infrastructure services
-	passwordService := infrastructure.NewPasswordService()
-	jwtService := infrastructure.NewJWTService()
-
-	emailVerifier, err := infrastructure.NewEmailListVerifyVerifier()
+	// Argon2id by default; bcrypt hashes keep working and are upgraded at login
+	passwordService, err := infrastructure.NewPasswordServiceFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to initialize password hashing: %v", err)
+	}
+	// JWT keys: HS256 by default, RS256/EdDSA keys are rotated and shared through Mongo
+	keyRing, err := infrastructure.NewKeyRingFromEnv(ctx, repositories.NewSigningKeyRepository(jwtKeysCollection))
 	if err != nil {
-		log.Fatalf("Failed to initialize email verifier: %v", err)
+		log.Fatalf("Failed to initialize JWT keys: %v", err)
 	}
-	emailSender := infrastructure.NewBrevoEmailSender()
+	keyRing.Start(context.Background())
+	jwtService := infrastructure.NewJWTServiceWithKeyRing(keyRing)
 
-	// Cloudinary configuration
-	cloudName := os.Getenv("CLOUDINARY_CLOUD_NAME")
-	cloudAPIKey := os.Getenv("CLOUDINARY_API_KEY")
-	cloudAPISecret := os.Getenv("CLOUDINARY_API_SECRET")
+	// Links in emails point here; signed links use the same key everywhere
+	publicURL := os.Getenv("PUBLIC_URL")
+	if publicURL == "" {
+		publicURL = "http://localhost:8080"
+	}
 
-	if cloudName == "" || cloudAPIKey == "" || cloudAPISecret == "" {
-		log.Fatal("Cloudinary credentials not set in environment")
+	// Cloudinary, email verification and delivery, and AI: the real services,
+	// or local fakes with PROVIDERS=local so no credentials or network are needed.
+	// Signup email verification also applies the admin domain allow/deny lists.
+	emailDomainRuleRepo := repositories.NewEmailDomainRuleRepository(emailDomainRulesCollection)
+	providers, err := infrastructure.NewProvidersFromEnv(publicURL, emailDomainRuleRepo)
+	if err != nil {
+		log.Fatalf("Failed to initialize providers: %v", err)
+	}
+	if providers.Mode == infrastructure.ProvidersLocal {
+		log.Printf("Using local providers: uploads at %s, emails at %s", infrastructure.LocalUploadsPath, infrastructure.LocalInboxPath)
 	}
+	emailVerifier := providers.EmailVerifier
+	cloudinaryService := providers.Images
 
-	// Services
-	cloudinaryService, err := infrastructure.NewCloudinaryService(cloudName, cloudAPIKey, cloudAPISecret)
+	emailTemplates, err := infrastructure.NewEmailTemplates()
 	if err != nil {
-		log.Fatalf("Failed to initialize Cloudinary service: %v", err)
+		log.Fatalf("Failed to load email templates: %v", err)
 	}
+	// Emails are queued in Mongo and delivered in the background
+	emailOutboxRepo := repositories.NewEmailOutboxRepository(emailOutboxCollection)
+	emailOutbox := usecases.NewEmailOutbox(emailOutboxRepo, emailTemplates)
+	emailSender := emailOutbox
 
 	//Repositories: only take collection (not services)
 	userRepo := repositories.NewUserRepository(userCollection)
@@ -81,18 +119,92 @@ func main() {
 	resourceRepo := repositories.NewResourceRepository(resourceCollection)
 	commentRepo := repositories.NewCommentRepository(commentCollection)
 	messagingRepo := repositories.NewMessagingRepository(conversationsCollection, messagesCollection)
-	//AI configuration
-	aiAPIKey := os.Getenv("GEMINI_API_KEY")
-	if aiAPIKey == "" {
-		log.Fatal("GEMINI_API_KEY not set in environment")
+	mentorshipRepo := repositories.NewMentorshipRepository(mentorshipRequestsCollection, mentorshipConnectionsCollection)
+	accountDeletionRepo := repositories.NewAccountDeletionRepository(accountDeletionsCollection)
+	dataExportRepo := repositories.NewDataExportRepository(dataExportsCollection)
+	accessTokenRepo := repositories.NewPersonalAccessTokenRepository(accessTokensCollection)
+
+	// Access-token revocations: Mongo is shared across replicas, memory suits a single instance
+	var revocationStore userpkg.IRevocationStore
+	if os.Getenv("REVOCATION_STORE") == "memory" {
+		revocationStore = infrastructure.NewInMemoryRevocationStore()
+	} else {
+		revocationStore = repositories.NewRevocationRepository(revokedTokensCollection)
+	}
+
+	// Deleted accounts can be restored for this long before they are erased
+	deletionGrace := usecases.DefaultAccountDeletionGrace
+	if raw := os.Getenv("ACCOUNT_DELETION_GRACE"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			log.Fatalf("invalid ACCOUNT_DELETION_GRACE: %v", err)
+		}
+		deletionGrace = d
+	}
+
+	// Data export archives are kept on local disk until they expire
+	exportDir := os.Getenv("EXPORT_DIR")
+	if exportDir == "" {
+		exportDir = "exports"
+	}
+	exportStore, err := infrastructure.NewFileExportStore(exportDir)
+	if err != nil {
+		log.Fatalf("Failed to initialize export store: %v", err)
+	}
+	exportRetention := usecases.DefaultDataExportRetention
+	if raw := os.Getenv("EXPORT_RETENTION"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			log.Fatalf("invalid EXPORT_RETENTION: %v", err)
+		}
+		exportRetention = d
+	}
+
+	urlSigner, err := infrastructure.NewURLSignerFromEnv(providers.Mode == infrastructure.ProvidersLocal)
+	if err != nil {
+		log.Fatalf("Failed to initialize URL signer: %v", err)
+	}
+	// Keys the stored hashes of emailed codes; without it they are reversible
+	otpHashKey := os.Getenv("OTP_HASH_KEY")
+	if otpHashKey == "" {
+		if providers.Mode != infrastructure.ProvidersLocal {
+			log.Fatal("OTP_HASH_KEY is required")
+		}
+		log.Println("OTP_HASH_KEY not set; emailed codes will not survive a restart")
+	}
+	// Sign-in links open this page, which posts them to /login/magic/verify
+	magicLinkURL := os.Getenv("MAGIC_LINK_URL")
+	if magicLinkURL == "" {
+		magicLinkURL = publicURL + "/login/magic"
+	}
+
+	// Social and university sign-in; none are configured by default
+	oidcProviders, err := infrastructure.NewOIDCProvidersFromEnv(publicURL)
+	if err != nil {
+		log.Fatalf("Failed to configure OIDC providers: %v", err)
 	}
-	aiAPIURL := os.Getenv("GEMINI_API_URL")
-	if aiAPIURL == "" {
-		log.Fatal("GEMINI_API_URL not set in environment")
+
+	// Password rules for registration, reset and change
+	passwordPolicy, err := infrastructure.NewPasswordPolicyFromEnv(passwordService)
+	if err != nil {
+		log.Fatalf("Failed to initialize password policy: %v", err)
 	}
 
+	// Admins must use two-factor authentication unless explicitly turned off
+	adminMFARequired := os.Getenv("ADMIN_MFA_REQUIRED") != "false"
+
 	//Usecase: handles business logic, gets all dependencies
+	// Privileged actions are appended to the hash-chained audit log
+	auditUsecase := usecases.NewAuditUsecase(repositories.NewAuditRepository(auditLogCollection))
+	emailOutbox.WithAuditLogger(auditUsecase)
+	emailDomainRules := usecases.NewEmailDomainRules(emailDomainRuleRepo).WithAuditLogger(auditUsecase)
 	verificationRepo := repositories.NewVerificationRepo(verificationCollection)
+	mfaChallengeRepo := repositories.NewMFAChallengeRepo(mfaChallengesCollection)
+	loginAttemptRepo := repositories.NewLoginAttemptRepository(loginAttemptsCollection)
+	emailChangeRepo := repositories.NewEmailChangeRepository(emailChangesCollection)
+	// Students verify an address at a registered institution's domain
+	institutionRepo := repositories.NewInstitutionRepository(institutionsCollection)
+	institutionUsecase := usecases.NewInstitutionUsecase(institutionRepo, userRepo).WithAuditLogger(auditUsecase)
 	userUsecase := usecases.NewUserUsecase(
 		userRepo,
 		passwordService,
@@ -103,29 +215,127 @@ func main() {
 		passwordResetRepo,
 		verificationRepo,
 		cloudinaryService,
-	)
-	postUsecase := usecases.NewPostUsecase(postRepo, userRepo)
-	resourceUsecase := usecases.NewResourceUsecase(resourceRepo, userRepo)
+	).WithRevocationStore(revocationStore).
+		WithMFA(infrastructure.NewTOTPService(), mfaChallengeRepo, adminMFARequired).
+		WithLoginThrottle(loginAttemptRepo, usecases.DefaultLoginThrottlePolicy()).
+		WithOTPThrottle(repositories.NewOTPSendRepository(otpSendsCollection), usecases.DefaultOTPSendPolicy()).
+		WithAccountDeletion(accountDeletionRepo, deletionGrace).
+		WithDataExport(dataExportRepo, exportStore, urlSigner).
+		WithEmailChange(emailChangeRepo, urlSigner, publicURL).
+		WithPasswordPolicy(passwordPolicy).
+		WithAuditLogger(auditUsecase).
+		WithPersonalAccessTokens(accessTokenRepo).
+		WithMagicLinks(repositories.NewVerificationRepo(magicLinksCollection), urlSigner, magicLinkURL).
+		WithOIDC(repositories.NewOAuthStateRepository(oauthStatesCollection), oidcProviders...).
+		WithStudentVerification(institutionRepo)
+	if otpHashKey != "" {
+		userUsecase.WithCodeKey([]byte(otpHashKey))
+	}
+	postUsecase := usecases.NewPostUsecase(postRepo, userRepo).WithAuditLogger(auditUsecase)
+	resourceUsecase := usecases.NewResourceUsecase(resourceRepo, userRepo).WithAuditLogger(auditUsecase)
 	commentUsecase := usecases.NewCommentUsecase(commentRepo, postRepo, userRepo)
 	messagingUsecase := usecases.NewMessagingUsecase(messagingRepo, userRepo)
 
+	// Erase accounts whose deletion grace period has passed
+	usecases.NewAccountDeletionJob(
+		accountDeletionRepo,
+		userRepo,
+		tokenRepo,
+		verificationRepo,
+		passwordResetRepo,
+		postRepo,
+		commentRepo,
+		resourceRepo,
+		mentorshipRepo,
+		messagingRepo,
+		accessTokenRepo,
+		dataExportRepo,
+		exportStore,
+		emailChangeRepo,
+		mfaChallengeRepo,
+		loginAttemptRepo,
+	).Start(context.Background(), 10*time.Minute)
+
+	// Build requested data exports and clear out expired archives
+	usecases.NewDataExportJob(
+		dataExportRepo,
+		exportStore,
+		userRepo,
+		tokenRepo,
+		postRepo,
+		commentRepo,
+		resourceRepo,
+		mentorshipRepo,
+		messagingRepo,
+		emailSender,
+		exportRetention,
+	).Start(context.Background(), time.Minute)
+
+	// Deliver queued emails, retrying failures with backoff
+	emailWorkers := 4
+	if raw := os.Getenv("EMAIL_WORKERS"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			log.Fatalf("invalid EMAIL_WORKERS: %q", raw)
+		}
+		emailWorkers = n
+	}
+	usecases.NewEmailDeliveryJob(emailOutboxRepo, providers.EmailTransport, usecases.DefaultEmailDeliveryPolicy()).
+		Start(context.Background(), emailWorkers, 5*time.Second)
+
 	//Controllers
 	postController := controllers.NewPostController(postUsecase)
 	resourceController := controllers.NewResourceController(resourceUsecase)
 	commentController := controllers.NewCommentController(commentUsecase)
 	messagingController := controllers.NewMessagingController(messagingUsecase)
 	controller := controllers.NewControllerWithMessaging(userUsecase, postController, resourceController, nil, commentController, messagingController)
+	controller.JWKSController = controllers.NewJWKSController(jwtService)
+	controller.AuditController = controllers.NewAuditController(auditUsecase)
+	controller.EmailTemplateController = controllers.NewEmailTemplateController(emailTemplates)
+	controller.EmailOutboxController = controllers.NewEmailOutboxController(emailOutbox)
+	controller.EmailDomainRuleController = controllers.NewEmailDomainRuleController(emailDomainRules)
+	controller.InstitutionController = controllers.NewInstitutionController(institutionUsecase)
+	if providers.Inbox != nil {
+		controller.DevInboxController = controllers.NewDevInboxController(providers.Inbox)
+	}
 
 	// Initialize AuthMiddleware
-	authMiddleware := infrastructure.NewAuthMiddleware(jwtService)
+	authMiddleware := infrastructure.NewAuthMiddleware(jwtService, tokenRepo, revocationStore).
+		RejectSuspended(userRepo).
+		AcceptPersonalAccessTokens(accessTokenRepo)
+	if adminMFARequired {
+		authMiddleware.RequireAdminMFA()
+	}
+
+	// Rate limits: Redis shares counters across replicas, memory suits a single instance
+	var rateLimitStore infrastructure.RateLimitStore
+	if os.Getenv("RATE_LIMIT_STORE") == "redis" {
+		redisAddr := os.Getenv("REDIS_ADDR")
+		if redisAddr == "" {
+			redisAddr = "localhost:6379"
+		}
+		rateLimitStore = infrastructure.NewRedisRateLimitStore(redis.NewClient(&redis.Options{
+			Addr:     redisAddr,
+			Password: os.Getenv("REDIS_PASSWORD"),
+		}))
+	} else {
+		rateLimitStore = infrastructure.NewInMemoryRateLimitStore()
+	}
 
 	//Router
-	r := routers.SetupRouter(controller, authMiddleware)
+	r, err := routers.SetupRouter(controller, authMiddleware, infrastructure.NewRateLimiter(rateLimitStore), infrastructure.TrustedProxiesFromEnv())
+	if err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
 	// WebSocket hub and route
 	hub := infrastructure.NewHub(messagingUsecase)
 	protected := r.Group("")
 	protected.Use(authMiddleware.AuthMiddleware())
 	protected.GET("/ws", hub.WSHandler)
+	// Images uploaded to the local stand-in for Cloudinary
+	if providers.UploadsDir != "" {
+		r.Static(infrastructure.LocalUploadsPath, providers.UploadsDir)
+	}
 
 	//Start Server
 	log.Println("Server running on :8080")
@@ -0,0 +1,46 @@
+package routers
+
+import (
+	"time"
+
+	infrastructure "github.com/Amaankaa/Blog-Starter-Project/Infrastructure"
+)
+
+// Rate limit policies per route group. Routes that send email or check
+// credentials are counted per route and per IP; everything behind auth is
+// counted per IP before the token is checked, then per user.
+var (
+	emailRateLimit = infrastructure.RateLimitPolicy{
+		Name:     "email",
+		Limit:    5,
+		Window:   15 * time.Minute,
+		Key:      infrastructure.KeyByIP,
+		PerRoute: true,
+	}
+	authRateLimit = infrastructure.RateLimitPolicy{
+		Name:     "auth",
+		Limit:    20,
+		Window:   time.Minute,
+		Key:      infrastructure.KeyByIP,
+		PerRoute: true,
+	}
+	publicReadRateLimit = infrastructure.RateLimitPolicy{
+		Name:   "public-read",
+		Limit:  300,
+		Window: time.Minute,
+		Key:    infrastructure.KeyByIP,
+	}
+	// Runs ahead of auth, so requests with guessed access tokens are limited too
+	protectedIPRateLimit = infrastructure.RateLimitPolicy{
+		Name:   "protected-ip",
+		Limit:  300,
+		Window: time.Minute,
+		Key:    infrastructure.KeyByIP,
+	}
+	userRateLimit = infrastructure.RateLimitPolicy{
+		Name:   "user",
+		Limit:  120,
+		Window: time.Minute,
+		Key:    infrastructure.KeyByUser,
+	}
+)
@@ -5,16 +5,32 @@ import (
 	"time"
 
 	"github.com/Amaankaa/Blog-Starter-Project/Delivery/controllers"
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
 	infrastructure "github.com/Amaankaa/Blog-Starter-Project/Infrastructure"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-contrib/cors"
 )
 
-func SetupRouter(controller *controllers.Controller, authMiddleware *infrastructure.AuthMiddleware) *gin.Engine {
+// SetupRouter registers every route. rateLimiter may be nil to disable rate
+// limiting. trustedProxies may set X-Forwarded-For; nil trusts no proxy.
+func SetupRouter(controller *controllers.Controller, authMiddleware *infrastructure.AuthMiddleware, rateLimiter *infrastructure.RateLimiter, trustedProxies []string) (*gin.Engine, error) {
 	r := gin.Default()
+	// Per-IP limits and audit IPs depend on clients not picking their own IP
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		return nil, err
+	}
 	// CORS (adjust for production as needed)
 	r.Use(cors.Default())
+	// Lets usecases record the caller's IP in the audit log
+	r.Use(infrastructure.ClientIPContext())
+
+	limit := func(policy infrastructure.RateLimitPolicy) gin.HandlerFunc {
+		if rateLimiter == nil {
+			return func(c *gin.Context) { c.Next() }
+		}
+		return rateLimiter.Limit(policy)
+	}
 
 	// Health check endpoint
 	r.GET("/health", func(c *gin.Context) {
@@ -26,24 +42,72 @@ func SetupRouter(controller *controllers.Controller, authMiddleware *infrastruct
 		})
 	})
 
-	// Public routes
-	r.POST("/register", controller.Register)
-	r.POST("/verify-user", controller.VerifyUser) // Registration verification (separate from password-reset OTP)
-	r.POST("/login", controller.Login)
-	r.POST("/forgot-password", controller.ForgotPassword)
-	r.POST("/verify-otp", controller.VerifyOTP)
-	r.POST("/reset-password", controller.ResetPassword)
+	// Public routes that send email
+	emailRoutes := r.Group("", limit(emailRateLimit))
+	emailRoutes.POST("/register", controller.Register)
+	emailRoutes.POST("/forgot-password", controller.ForgotPassword)
+	emailRoutes.POST("/login/magic", controller.RequestMagicLink)
+
+	// Public credential routes
+	authRoutes := r.Group("", limit(authRateLimit))
+	authRoutes.POST("/verify-user", controller.VerifyUser) // Registration verification (separate from password-reset OTP)
+	authRoutes.POST("/login", controller.Login)
+	authRoutes.POST("/login/mfa", controller.LoginMFA)
+	authRoutes.POST("/login/magic/verify", controller.LoginMagicLink)
+	authRoutes.GET("/auth/oidc", controller.OIDCProviders)
+	authRoutes.GET("/auth/oidc/:provider", controller.StartOIDCLogin)
+	authRoutes.GET("/auth/oidc/:provider/callback", controller.OIDCCallback)
+	authRoutes.POST("/verify-otp", controller.VerifyOTP)
+	authRoutes.POST("/reset-password", controller.ResetPassword)
 	// Optional: expose refresh endpoint
-	r.POST("/auth/refresh", controller.RefreshToken)
+	authRoutes.POST("/auth/refresh", controller.RefreshToken)
 
-	// Protected routes
+	// Public reads
+	public := r.Group("", limit(publicReadRateLimit))
+	if controller.JWKSController != nil {
+		public.GET("/.well-known/jwks.json", controller.JWKSController.GetJWKS)
+	}
+	// Signed link, so the archive can be fetched without a session
+	public.GET("/account/export/:id/download", controller.DownloadDataExport)
+	public.GET("/account/email/cancel", controller.CancelEmailChange)
+	public.GET("/users/:userId/profile", controller.GetPublicProfile)
+	if controller.InstitutionController != nil {
+		public.GET("/institutions", controller.InstitutionController.ListInstitutions)
+		public.GET("/institutions/:id", controller.InstitutionController.GetInstitution)
+	}
+
+	// Protected routes, limited per IP before auth so failed attempts count,
+	// then per user once authenticated
 	protected := r.Group("")
-	protected.Use(authMiddleware.AuthMiddleware())
+	protected.Use(limit(protectedIPRateLimit), authMiddleware.AuthMiddleware(), limit(userRateLimit))
 
 	//User routes
 	protected.POST("/logout", controller.Logout)
 	protected.GET("/profile", controller.GetProfile)
 	protected.PUT("/profile", controller.UpdateProfile)
+	protected.GET("/sessions", controller.ListSessions)
+	protected.DELETE("/sessions/:id", controller.RevokeSession)
+	protected.POST("/sessions/logout-others", controller.RevokeOtherSessions)
+	protected.POST("/mfa/enroll", controller.EnrollMFA)
+	protected.POST("/mfa/confirm", controller.ConfirmMFA)
+	protected.POST("/mfa/disable", controller.DisableMFA)
+	protected.POST("/mfa/recovery-codes", controller.RegenerateRecoveryCodes)
+	protected.POST("/account/reauth-code", controller.SendReauthCode)
+	protected.DELETE("/account", controller.DeleteAccount)
+	protected.GET("/account/deletion", controller.GetAccountDeletion)
+	protected.DELETE("/account/deletion", controller.CancelAccountDeletion)
+	protected.PUT("/account/password", controller.ChangePassword)
+	protected.POST("/account/email", controller.RequestEmailChange)
+	protected.POST("/account/email/confirm", controller.ConfirmEmailChange)
+	protected.POST("/account/export", controller.RequestDataExport)
+	protected.GET("/account/export/:id", controller.GetDataExport)
+	protected.POST("/account/student", controller.RequestStudentVerification)
+	protected.POST("/account/student/confirm", controller.ConfirmStudentVerification)
+	protected.DELETE("/account/student", controller.RemoveStudentStatus)
+	protected.GET("/mentors", controller.FindMentors)
+	protected.POST("/tokens", controller.CreateAccessToken)
+	protected.GET("/tokens", controller.ListAccessTokens)
+	protected.DELETE("/tokens/:id", controller.RevokeAccessToken)
 
 	// Posts routes (protected)
 	protected.POST("/posts", controller.PostController.CreatePost)
@@ -57,14 +121,14 @@ func SetupRouter(controller *controllers.Controller, authMiddleware *infrastruct
 	protected.DELETE("/comments/:commentId", controller.CommentController.DeleteComment)
 
 	// Posts routes (public - can be viewed without authentication, but with optional user context)
-	r.GET("/posts", controller.PostController.GetPosts)
-	r.GET("/posts/search", controller.PostController.SearchPosts)
-	r.GET("/posts/popular", controller.PostController.GetPopularPosts)
-	r.GET("/posts/trending-tags", controller.PostController.GetTrendingTags)
-	r.GET("/posts/:id", controller.PostController.GetPost)
-	r.GET("/posts/:id/comments", controller.CommentController.GetComments)
-	r.GET("/posts/category/:category", controller.PostController.GetPostsByCategory)
-	r.GET("/users/:userId/posts", controller.PostController.GetUserPosts)
+	public.GET("/posts", controller.PostController.GetPosts)
+	public.GET("/posts/search", controller.PostController.SearchPosts)
+	public.GET("/posts/popular", controller.PostController.GetPopularPosts)
+	public.GET("/posts/trending-tags", controller.PostController.GetTrendingTags)
+	public.GET("/posts/:id", controller.PostController.GetPost)
+	public.GET("/posts/:id/comments", controller.CommentController.GetComments)
+	public.GET("/posts/category/:category", controller.PostController.GetPostsByCategory)
+	public.GET("/users/:userId/posts", controller.PostController.GetUserPosts)
 
 	// Resources routes (protected)
 	protected.POST("/resources", controller.ResourceController.CreateResource)
@@ -78,15 +142,15 @@ func SetupRouter(controller *controllers.Controller, authMiddleware *infrastruct
 	protected.POST("/resources/:id/report", controller.ResourceController.ReportResource)
 
 	// Resources routes (public)
-	r.GET("/resources", controller.ResourceController.GetResources)
-	r.GET("/resources/search", controller.ResourceController.SearchResources)
-	r.GET("/resources/popular", controller.ResourceController.GetPopularResources)
-	r.GET("/resources/trending", controller.ResourceController.GetTrendingResources)
-	r.GET("/resources/top-rated", controller.ResourceController.GetTopRatedResources)
-	r.GET("/resources/:id", controller.ResourceController.GetResource)
-	r.GET("/users/:userId/resources", controller.ResourceController.GetUserResources)
-	r.GET("/users/:userId/resources/liked", controller.ResourceController.GetUserLikedResources)
-	r.GET("/users/:userId/resources/bookmarked", controller.ResourceController.GetUserBookmarkedResources)
+	public.GET("/resources", controller.ResourceController.GetResources)
+	public.GET("/resources/search", controller.ResourceController.SearchResources)
+	public.GET("/resources/popular", controller.ResourceController.GetPopularResources)
+	public.GET("/resources/trending", controller.ResourceController.GetTrendingResources)
+	public.GET("/resources/top-rated", controller.ResourceController.GetTopRatedResources)
+	public.GET("/resources/:id", controller.ResourceController.GetResource)
+	public.GET("/users/:userId/resources", controller.ResourceController.GetUserResources)
+	public.GET("/users/:userId/resources/liked", controller.ResourceController.GetUserLikedResources)
+	public.GET("/users/:userId/resources/bookmarked", controller.ResourceController.GetUserBookmarkedResources)
 
 	// Mentorship routes (protected)
 	if controller.MentorshipController != nil {
@@ -106,7 +170,7 @@ func SetupRouter(controller *controllers.Controller, authMiddleware *infrastruct
 		protected.GET("/mentorship/stats", controller.MentorshipController.GetMentorshipStats)
 		protected.GET("/mentorship/insights", controller.MentorshipController.GetMentorshipInsights)
 	}
-	r.GET("/users/:userId/resources/stats", controller.ResourceController.GetUserResourceStats)
+	public.GET("/users/:userId/resources/stats", controller.ResourceController.GetUserResourceStats)
 
 	// Messaging routes (protected) and WebSocket endpoint
 	if controller.MessagingController != nil {
@@ -115,13 +179,92 @@ func SetupRouter(controller *controllers.Controller, authMiddleware *infrastruct
 		protected.GET("/conversations/:id/messages", controller.MessagingController.GetMessages)
 	}
 
-	// Admin routes for user promotion and demotion
-	admin := protected.Group("")
-	admin.Use(authMiddleware.AdminOnly())
-	admin.PUT("/user/:id/promote", controller.PromoteUser)
-	admin.PUT("/user/:id/demote", controller.DemoteUser)
-	// Admin-only resource verification
-	admin.POST("/resources/:id/verify", controller.ResourceController.VerifyResource)
+	// Role management
+	roles := protected.Group("")
+	roles.Use(authMiddleware.RequirePermission(userpkg.PermRolesManage))
+	roles.PUT("/user/:id/promote", controller.PromoteUser)
+	roles.PUT("/user/:id/demote", controller.DemoteUser)
+	roles.PUT("/user/:id/roles/:role", controller.GrantRole)
+	roles.DELETE("/user/:id/roles/:role", controller.RevokeRole)
+	protected.PUT("/user/:id/unlock", authMiddleware.RequirePermission(userpkg.PermUsersManage), controller.UnlockUser)
+
+	// Admin user console
+	admin := protected.Group("/admin/users")
+	admin.Use(authMiddleware.RequirePermission(userpkg.PermUsersManage))
+	admin.GET("", controller.ListUsers)
+	admin.GET("/:id", controller.GetUserForAdmin)
+	admin.POST("/:id/suspend", controller.SuspendUser)
+	admin.DELETE("/:id/suspend", controller.LiftSuspension)
+	admin.POST("/:id/reverify", controller.ForceReverification)
+	admin.POST("/:id/mfa/reset", controller.ResetUserMFA)
+
+	// Audit log
+	if controller.AuditController != nil {
+		audit := protected.Group("/admin/audit")
+		audit.Use(authMiddleware.RequirePermission(userpkg.PermAuditRead))
+		audit.GET("", controller.AuditController.ListEntries)
+		audit.GET("/export", controller.AuditController.ExportEntries)
+		audit.GET("/verify", controller.AuditController.VerifyChain)
+	}
+
+	// Email templates
+	if controller.EmailTemplateController != nil {
+		emails := protected.Group("/admin/email/templates")
+		emails.Use(authMiddleware.RequirePermission(userpkg.PermEmailManage))
+		emails.GET("", controller.EmailTemplateController.ListTemplates)
+		emails.GET("/:id/preview", controller.EmailTemplateController.PreviewTemplate)
+	}
+
+	// Email outbox
+	if controller.EmailOutboxController != nil {
+		outbox := protected.Group("/admin/email/outbox")
+		outbox.Use(authMiddleware.RequirePermission(userpkg.PermEmailManage))
+		outbox.GET("", controller.EmailOutboxController.ListMessages)
+		outbox.GET("/stats", controller.EmailOutboxController.Stats)
+		outbox.GET("/:id", controller.EmailOutboxController.GetMessage)
+		outbox.POST("/:id/retry", controller.EmailOutboxController.RetryMessage)
+	}
+
+	// Email domains signup verification always accepts or refuses
+	if controller.EmailDomainRuleController != nil {
+		domains := protected.Group("/admin/email/domains")
+		domains.Use(authMiddleware.RequirePermission(userpkg.PermEmailManage))
+		domains.GET("", controller.EmailDomainRuleController.ListRules)
+		domains.PUT("/:domain", controller.EmailDomainRuleController.SetRule)
+		domains.DELETE("/:domain", controller.EmailDomainRuleController.DeleteRule)
+	}
+
+	// Institutions students verify their status with
+	if controller.InstitutionController != nil {
+		institutions := protected.Group("/admin/institutions")
+		institutions.Use(authMiddleware.RequirePermission(userpkg.PermInstitutionsManage))
+		institutions.POST("", controller.InstitutionController.CreateInstitution)
+		institutions.PUT("/:id", controller.InstitutionController.UpdateInstitution)
+		institutions.DELETE("/:id", controller.InstitutionController.DeleteInstitution)
+	}
+
+	// Emails caught in local development (PROVIDERS=local)
+	if controller.DevInboxController != nil {
+		inbox := r.Group(infrastructure.LocalInboxPath)
+		inbox.GET("", controller.DevInboxController.ListEmails)
+		inbox.GET("/:id", controller.DevInboxController.GetEmail)
+		inbox.DELETE("", controller.DevInboxController.ClearEmails)
+	}
+
+	// Moderation
+	moderate := authMiddleware.RequirePermission(userpkg.PermContentModerate)
+	protected.POST("/posts/:id/hide", moderate, controller.PostController.HidePost)
+	protected.POST("/posts/:id/unhide", moderate, controller.PostController.UnhidePost)
+	protected.POST("/resources/:id/hide", moderate, controller.ResourceController.HideResource)
+	protected.POST("/resources/:id/unhide", moderate, controller.ResourceController.UnhideResource)
+	protected.POST("/resources/:id/verify", authMiddleware.RequirePermission(userpkg.PermResourcesVerify), controller.ResourceController.VerifyResource)
+
+	// Personal access tokens only work on these routes, and only with the scope
+	authMiddleware.
+		AllowTokenScope(userpkg.ScopeProfileRead, "GET /profile").
+		AllowTokenScope(userpkg.ScopePostsWrite, "POST /posts", "PATCH /posts/:id", "DELETE /posts/:id").
+		AllowTokenScope(userpkg.ScopeResourcesWrite, "POST /resources", "PATCH /resources/:id", "DELETE /resources/:id").
+		AllowTokenScope(userpkg.ScopeCommentsWrite, "POST /posts/:id/comments", "PATCH /comments/:commentId", "DELETE /comments/:commentId")
 
-	return r
+	return r, nil
 }
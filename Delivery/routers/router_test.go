@@ -0,0 +1,36 @@
+package routers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Amaankaa/Blog-Starter-Project/Delivery/controllers"
+	infrastructure "github.com/Amaankaa/Blog-Starter-Project/Infrastructure"
+	"github.com/Amaankaa/Blog-Starter-Project/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProtectedRoutes_LimitRequestsThatFailAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter := infrastructure.NewRateLimiter(infrastructure.NewInMemoryRateLimitStore())
+	jwtService := new(mocks.IJWTService)
+	jwtService.On("ValidateToken", "guessed").Return(nil, errors.New("invalid signature"))
+	am := infrastructure.NewAuthMiddleware(jwtService, nil, nil)
+	r, err := SetupRouter(&controllers.Controller{}, am, limiter, nil)
+	require.NoError(t, err)
+
+	guess := func() int {
+		req := httptest.NewRequest(http.MethodGet, "/profile", nil)
+		req.Header.Set("Authorization", "Bearer guessed")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+	for i := 0; i < protectedIPRateLimit.Limit; i++ {
+		require.Equal(t, http.StatusUnauthorized, guess())
+	}
+	require.Equal(t, http.StatusTooManyRequests, guess())
+}
@@ -0,0 +1,133 @@
+package auditpkg
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// Actions recorded in the audit log
+const (
+	ActionRoleGrant         = "user.role.grant"
+	ActionRoleRevoke        = "user.role.revoke"
+	ActionUserUnlock        = "user.unlock"
+	ActionUserSuspend       = "user.suspend"
+	ActionUserUnsuspend     = "user.unsuspend"
+	ActionUserReverify      = "user.reverify"
+	ActionUserMFAReset      = "user.mfa.reset"
+	ActionPostHide          = "post.hide"
+	ActionPostUnhide        = "post.unhide"
+	ActionResourceHide      = "resource.hide"
+	ActionResourceUnhide    = "resource.unhide"
+	ActionResourceVerify    = "resource.verify"
+	ActionEmailRetry        = "email.retry"
+	ActionEmailDomainSet    = "email.domain.set"
+	ActionEmailDomainDelete = "email.domain.delete"
+	ActionInstitutionCreate = "institution.create"
+	ActionInstitutionUpdate = "institution.update"
+	ActionInstitutionDelete = "institution.delete"
+)
+
+// Target types
+const (
+	TargetUser        = "user"
+	TargetPost        = "post"
+	TargetResource    = "resource"
+	TargetEmail       = "email"
+	TargetDomain      = "email_domain"
+	TargetInstitution = "institution"
+)
+
+// Event is what a usecase reports. Before and After are snapshots of the
+// fields the action changed; anything JSON-encodable will do.
+type Event struct {
+	ActorID    string
+	Action     string
+	TargetType string
+	TargetID   string
+	Before     interface{}
+	After      interface{}
+}
+
+// Entry is one link of the audit chain. Seq is the document ID, starts at 1
+// and has no gaps. Hash covers the entry's content and PrevHash, so editing,
+// removing or reordering entries breaks every hash after them.
+type Entry struct {
+	Seq        int64           `bson:"_id" json:"seq"`
+	ActorID    string          `bson:"actorId" json:"actorId"`
+	Action     string          `bson:"action" json:"action"`
+	TargetType string          `bson:"targetType" json:"targetType"`
+	TargetID   string          `bson:"targetId" json:"targetId"`
+	Before     json.RawMessage `bson:"before,omitempty" json:"before,omitempty"`
+	After      json.RawMessage `bson:"after,omitempty" json:"after,omitempty"`
+	IP         string          `bson:"ip,omitempty" json:"ip,omitempty"`
+	CreatedAt  time.Time       `bson:"createdAt" json:"createdAt"`
+	PrevHash   string          `bson:"prevHash" json:"prevHash"`
+	Hash       string          `bson:"hash" json:"hash"`
+}
+
+// ComputeHash returns the hex SHA-256 of the entry's content chained to
+// PrevHash. CreatedAt is taken at millisecond precision, which is what Mongo
+// stores.
+func (e Entry) ComputeHash() string {
+	content, _ := json.Marshal(struct {
+		Seq        int64           `json:"seq"`
+		ActorID    string          `json:"actorId"`
+		Action     string          `json:"action"`
+		TargetType string          `json:"targetType"`
+		TargetID   string          `json:"targetId"`
+		Before     json.RawMessage `json:"before,omitempty"`
+		After      json.RawMessage `json:"after,omitempty"`
+		IP         string          `json:"ip"`
+		CreatedAt  int64           `json:"createdAt"`
+		PrevHash   string          `json:"prevHash"`
+	}{e.Seq, e.ActorID, e.Action, e.TargetType, e.TargetID, e.Before, e.After, e.IP, e.CreatedAt.UnixMilli(), e.PrevHash})
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// Filter narrows an audit query. Zero values match everything.
+type Filter struct {
+	ActorID    string
+	Action     string
+	TargetType string
+	TargetID   string
+	From       *time.Time
+	To         *time.Time
+	Page       int
+	PageSize   int
+}
+
+// EntryListResponse is one page of audit entries, newest first
+type EntryListResponse struct {
+	Entries    []Entry `json:"entries"`
+	Total      int64   `json:"total"`
+	Page       int     `json:"page"`
+	PageSize   int     `json:"pageSize"`
+	TotalPages int     `json:"totalPages"`
+}
+
+// ChainVerification is the result of walking the whole chain
+type ChainVerification struct {
+	Valid   bool  `json:"valid"`
+	Checked int64 `json:"checked"`
+	// First entry that does not match; unset when Valid
+	BrokenAtSeq int64  `json:"brokenAtSeq,omitempty"`
+	Problem     string `json:"problem,omitempty"`
+}
+
+type clientIPKey struct{}
+
+// WithClientIP attaches the caller's IP to ctx so the audit log can record
+// it without every usecase taking it as a parameter
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+// ClientIP returns the IP attached by WithClientIP, or ""
+func ClientIP(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey{}).(string)
+	return ip
+}
@@ -0,0 +1,22 @@
+package auditpkg
+
+import (
+	"context"
+	"errors"
+)
+
+// IAuditRepository stores the chain. It only ever inserts: there is no way
+// to change or remove an entry.
+type IAuditRepository interface {
+	// LastEntry returns the head of the chain; found is false while it is empty
+	LastEntry(ctx context.Context) (entry Entry, found bool, err error)
+	// InsertEntry returns ErrSeqTaken if another writer appended Seq first
+	InsertEntry(ctx context.Context, entry Entry) error
+	FindEntries(ctx context.Context, filter Filter) ([]Entry, int64, error)
+	// EachEntry calls fn for every matching entry in chain order, stopping at
+	// the first error
+	EachEntry(ctx context.Context, filter Filter, fn func(Entry) error) error
+}
+
+// ErrSeqTaken means the chain moved on while an entry was being appended
+var ErrSeqTaken = errors.New("audit sequence number already taken")
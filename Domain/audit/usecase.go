@@ -0,0 +1,18 @@
+package auditpkg
+
+import "context"
+
+// IAuditLogger appends privileged actions to the audit log. The IP comes
+// from ctx (see WithClientIP).
+type IAuditLogger interface {
+	Record(ctx context.Context, event Event) error
+}
+
+// IAuditUsecase is the admin side of the audit log
+type IAuditUsecase interface {
+	IAuditLogger
+	ListEntries(ctx context.Context, filter Filter) (EntryListResponse, error)
+	// ExportEntries calls fn for each matching entry, oldest first
+	ExportEntries(ctx context.Context, filter Filter, fn func(Entry) error) error
+	VerifyChain(ctx context.Context) (ChainVerification, error)
+}
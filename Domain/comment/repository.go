@@ -15,4 +15,13 @@ type ICommentRepository interface {
 	GetByID(ctx context.Context, id primitive.ObjectID) (*Comment, error)
 	UpdateComment(ctx context.Context, id primitive.ObjectID, content string) (*Comment, error)
 	DeleteComment(ctx context.Context, id primitive.ObjectID) error
+
+	// Account deletion
+	DeleteCommentsByPosts(ctx context.Context, postIDs []primitive.ObjectID) error
+	// DeleteCommentsByAuthor returns how many comments were removed from each post
+	DeleteCommentsByAuthor(ctx context.Context, authorID primitive.ObjectID) (map[primitive.ObjectID]int, error)
+	ReassignCommentsAuthor(ctx context.Context, fromID, toID primitive.ObjectID) error
+
+	// Data export
+	GetCommentsByAuthor(ctx context.Context, authorID primitive.ObjectID) ([]Comment, error)
 }
@@ -0,0 +1,137 @@
+package emailpkg
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Status is where a message is in the outbox
+type Status string
+
+const (
+	StatusPending Status = "pending" // waiting for its next attempt
+	StatusSending Status = "sending" // claimed by a worker
+	StatusSent    Status = "sent"
+	StatusDead    Status = "dead" // delivery gave up; an admin may retry it
+)
+
+// IsValidStatus reports whether status is one of the known statuses
+func IsValidStatus(status Status) bool {
+	switch status {
+	case StatusPending, StatusSending, StatusSent, StatusDead:
+		return true
+	}
+	return false
+}
+
+// Message is an email waiting in, or delivered from, the outbox. It is
+// rendered when queued, so a delivery retried days later still says what it
+// said when the usecase sent it.
+type Message struct {
+	ID       primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
+	To       string                 `bson:"to" json:"to"`
+	Locale   string                 `bson:"locale" json:"locale"`
+	Template services.EmailTemplate `bson:"template" json:"template"`
+	Subject  string                 `bson:"subject" json:"subject"`
+	HTML     string                 `bson:"html" json:"html"`
+	Text     string                 `bson:"text" json:"text"`
+
+	Status        Status     `bson:"status" json:"status"`
+	Attempts      int        `bson:"attempts" json:"attempts"`
+	NextAttemptAt time.Time  `bson:"nextAttemptAt" json:"nextAttemptAt"`
+	ClaimedAt     *time.Time `bson:"claimedAt,omitempty" json:"-"`
+	LastError     string     `bson:"lastError,omitempty" json:"lastError,omitempty"`
+	Transport     string     `bson:"transport,omitempty" json:"transport,omitempty"` // the transport of the last attempt
+	CreatedAt     time.Time  `bson:"createdAt" json:"createdAt"`
+	SentAt        *time.Time `bson:"sentAt,omitempty" json:"sentAt,omitempty"`
+}
+
+// Filter narrows an outbox query. Zero values match everything.
+type Filter struct {
+	Status   Status
+	To       string
+	Template services.EmailTemplate
+	Page     int
+	PageSize int
+}
+
+// MessageListResponse is one page of messages, newest first
+type MessageListResponse struct {
+	Messages   []Message `json:"messages"`
+	Total      int64     `json:"total"`
+	Page       int       `json:"page"`
+	PageSize   int       `json:"pageSize"`
+	TotalPages int       `json:"totalPages"`
+}
+
+// OutboxStats counts messages by status
+type OutboxStats struct {
+	Pending int64 `json:"pending"`
+	Sending int64 `json:"sending"`
+	Sent    int64 `json:"sent"`
+	Dead    int64 `json:"dead"`
+	// When the longest-waiting pending message was queued; unset when none are
+	OldestPendingAt *time.Time `json:"oldestPendingAt,omitempty"`
+}
+
+// DomainRuleAction is what signup verification does with a listed domain
+type DomainRuleAction string
+
+const (
+	DomainAllow DomainRuleAction = "allow" // accepted without the remaining checks
+	DomainDeny  DomainRuleAction = "deny"  // refused
+)
+
+// DomainRule is an admin's decision about an email domain. It covers the
+// domain's subdomains too, unless one of them has a rule of its own.
+type DomainRule struct {
+	Domain    string           `bson:"_id" json:"domain"`
+	Action    DomainRuleAction `bson:"action" json:"action"`
+	Note      string           `bson:"note,omitempty" json:"note,omitempty"`
+	CreatedBy string           `bson:"createdBy" json:"createdBy"`
+	CreatedAt time.Time        `bson:"createdAt" json:"createdAt"`
+}
+
+// NormalizeDomain lowercases domain and reports whether it is a valid DNS
+// name. Internationalized names must be given in their xn-- form.
+func NormalizeDomain(domain string) (string, bool) {
+	domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+	if domain == "" || len(domain) > 253 {
+		return "", false
+	}
+	for _, label := range strings.Split(domain, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return "", false
+		}
+		for _, c := range label {
+			if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+				return "", false
+			}
+		}
+	}
+	return domain, true
+}
+
+// DomainAndParents lists domain followed by each of its parents, most
+// specific first: a.b.example.com, b.example.com, example.com, com
+func DomainAndParents(domain string) []string {
+	domains := []string{domain}
+	for i := strings.IndexByte(domain, '.'); i >= 0; i = strings.IndexByte(domain, '.') {
+		domain = domain[i+1:]
+		domains = append(domains, domain)
+	}
+	return domains
+}
+
+var (
+	ErrMessageNotFound = errors.New("email not found")
+	ErrMessageNotDead  = errors.New("only dead-lettered emails can be retried")
+
+	ErrDomainRuleNotFound  = errors.New("domain rule not found")
+	ErrInvalidDomain       = errors.New("invalid domain")
+	ErrInvalidDomainAction = errors.New("action must be allow or deny")
+)
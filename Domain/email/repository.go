@@ -0,0 +1,40 @@
+package emailpkg
+
+import (
+	"context"
+	"time"
+)
+
+// IOutboxRepository stores queued emails and hands them out to workers
+type IOutboxRepository interface {
+	Enqueue(ctx context.Context, message Message) (Message, error)
+	// ClaimDue marks the oldest due message as sending. A message claimed
+	// more than lease ago is handed out again, since its worker has crashed,
+	// with that attempt added to its attempts.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (message Message, found bool, err error)
+	MarkSent(ctx context.Context, messageID string, attempts int, transport string, sentAt time.Time) error
+	// MarkRetry puts a message back to pending until nextAttemptAt
+	MarkRetry(ctx context.Context, messageID string, attempts int, transport, lastError string, nextAttemptAt time.Time) error
+	MarkDead(ctx context.Context, messageID string, attempts int, transport, lastError string) error
+	// Requeue makes a dead message pending again with a fresh set of attempts.
+	// It returns ErrMessageNotFound unless the message is dead.
+	Requeue(ctx context.Context, messageID string, now time.Time) error
+
+	GetMessage(ctx context.Context, messageID string) (Message, error)
+	FindMessages(ctx context.Context, filter Filter) ([]Message, int64, error)
+	Stats(ctx context.Context) (OutboxStats, error)
+	// DeleteSentBefore removes messages delivered before the given time
+	DeleteSentBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// IDomainRuleRepository stores the admin-managed allow and deny lists
+type IDomainRuleRepository interface {
+	// SaveRule creates the rule for its domain or replaces the existing one
+	SaveRule(ctx context.Context, rule DomainRule) error
+	DeleteRule(ctx context.Context, domain string) error
+	GetRule(ctx context.Context, domain string) (DomainRule, error)
+	ListRules(ctx context.Context, action DomainRuleAction) ([]DomainRule, error)
+	// FindRule returns the rule for the first of domains that has one, so
+	// callers pass the most specific domain first
+	FindRule(ctx context.Context, domains []string) (rule DomainRule, found bool, err error)
+}
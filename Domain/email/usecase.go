@@ -0,0 +1,20 @@
+package emailpkg
+
+import "context"
+
+// IOutboxUsecase is the admin side of the outbox
+type IOutboxUsecase interface {
+	ListMessages(ctx context.Context, filter Filter) (MessageListResponse, error)
+	GetMessage(ctx context.Context, messageID string) (Message, error)
+	// RetryMessage gives a dead-lettered message another set of attempts
+	RetryMessage(ctx context.Context, messageID, actorID string) (Message, error)
+	Stats(ctx context.Context) (OutboxStats, error)
+}
+
+// IDomainRuleUsecase lets admins manage the email domain allow and deny lists
+type IDomainRuleUsecase interface {
+	// ListRules lists every rule, or those with action when it is set
+	ListRules(ctx context.Context, action DomainRuleAction) ([]DomainRule, error)
+	SetRule(ctx context.Context, domain string, action DomainRuleAction, note, actorID string) (DomainRule, error)
+	DeleteRule(ctx context.Context, domain, actorID string) error
+}
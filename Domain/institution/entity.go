@@ -0,0 +1,34 @@
+package institutionpkg
+
+import (
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Institution is a university or college whose students can verify an
+// address at one of its email domains. A domain covers its subdomains, so
+// "aau.edu.et" also matches students@cs.aau.edu.et.
+type Institution struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name      string             `bson:"name" json:"name"`
+	Domains   []string           `bson:"domains" json:"domains"`
+	Country   string             `bson:"country,omitempty" json:"country,omitempty"`
+	CreatedBy string             `bson:"createdBy" json:"-"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// InstitutionRequest creates or replaces an institution
+type InstitutionRequest struct {
+	Name    string   `json:"name" binding:"required"`
+	Domains []string `json:"domains" binding:"required"`
+	Country string   `json:"country"`
+}
+
+var (
+	ErrInstitutionNotFound = errors.New("institution not found")
+	ErrInvalidInstitution  = errors.New("an institution needs a name and at least one valid email domain")
+	ErrDomainInUse         = errors.New("email domain already belongs to another institution")
+)
@@ -0,0 +1,20 @@
+package institutionpkg
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type IInstitutionRepository interface {
+	CreateInstitution(ctx context.Context, institution Institution) (Institution, error)
+	UpdateInstitution(ctx context.Context, institution Institution) error
+	DeleteInstitution(ctx context.Context, id primitive.ObjectID) error
+	GetInstitution(ctx context.Context, id primitive.ObjectID) (Institution, error)
+	// ListInstitutions returns institutions by name; query matches part of
+	// the name or a domain
+	ListInstitutions(ctx context.Context, query string) ([]Institution, error)
+	// FindByDomain returns the institution owning the first of domains that
+	// has one, so callers pass the most specific domain first
+	FindByDomain(ctx context.Context, domains []string) (institution Institution, found bool, err error)
+}
@@ -0,0 +1,15 @@
+package institutionpkg
+
+import "context"
+
+// IInstitutionUsecase registers institutions (admins) and lists them (everyone)
+type IInstitutionUsecase interface {
+	CreateInstitution(ctx context.Context, req InstitutionRequest, actorID string) (Institution, error)
+	// UpdateInstitution replaces the name, domains and country. Verified
+	// students keep their status and see the new name.
+	UpdateInstitution(ctx context.Context, id string, req InstitutionRequest, actorID string) (Institution, error)
+	// DeleteInstitution also takes verified student status away from its students
+	DeleteInstitution(ctx context.Context, id, actorID string) error
+	GetInstitution(ctx context.Context, id string) (Institution, error)
+	ListInstitutions(ctx context.Context, query string) ([]Institution, error)
+}
@@ -15,4 +15,6 @@ type IMessagingRepository interface {
 
 	SaveMessage(ctx context.Context, msg Message) (Message, error)
 	GetMessages(ctx context.Context, conversationID primitive.ObjectID, limit, offset int) ([]Message, error)
+	// ReplaceParticipant swaps a user for another in conversations and as message sender
+	ReplaceParticipant(ctx context.Context, fromID, toID primitive.ObjectID) error
 }
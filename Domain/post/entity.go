@@ -98,6 +98,9 @@ type PostFilter struct {
 	Year       int    `json:"year,omitempty"`
 	Month      int    `json:"month,omitempty"`
 	IsAnonymous *bool `json:"isAnonymous,omitempty"`
+	Institution string `json:"institution,omitempty"` // posts by its verified students; never anonymous ones
+	// AuthorIDs is what the usecase resolves Institution to. Nil matches any author.
+	AuthorIDs []primitive.ObjectID `json:"-"`
 }
 
 // PostPagination represents pagination options
@@ -41,6 +41,14 @@ type PostRepository interface {
 	ReportPost(ctx context.Context, postID primitive.ObjectID) error
 	HidePost(ctx context.Context, postID primitive.ObjectID) error
 	UnhidePost(ctx context.Context, postID primitive.ObjectID) error
+
+	// Account deletion
+	DeletePostsByAuthor(ctx context.Context, authorID primitive.ObjectID) ([]primitive.ObjectID, error)
+	ReassignPostsAuthor(ctx context.Context, fromID, toID primitive.ObjectID) error
+
+	// Data export
+	GetAllPostsByAuthor(ctx context.Context, authorID primitive.ObjectID) ([]Post, error)
+	GetPostsLikedByUser(ctx context.Context, userID primitive.ObjectID) ([]Post, error)
 }
 
 // PostStats represents analytics data for a post
@@ -36,6 +36,8 @@ type PostUsecase interface {
 
 	// Moderation
 	ReportPost(ctx context.Context, postID, reporterID primitive.ObjectID, reason string) error
+	HidePost(ctx context.Context, postID, moderatorID primitive.ObjectID) error
+	UnhidePost(ctx context.Context, postID, moderatorID primitive.ObjectID) error
 
 	// Validation
 	ValidatePostCategory(category string) error
@@ -161,6 +161,9 @@ type ResourceFilter struct {
 	IsVerified  *bool    `json:"isVerified,omitempty"`
 	HasDeadline *bool    `json:"hasDeadline,omitempty"`
 	Tags        []string `json:"tags,omitempty"`
+	Institution string   `json:"institution,omitempty"` // resources shared by its verified students
+	// CreatorIDs is what the usecase resolves Institution to. Nil matches any creator.
+	CreatorIDs []primitive.ObjectID `json:"-"`
 }
 
 // ResourcePagination represents pagination options for resources
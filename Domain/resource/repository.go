@@ -62,6 +62,13 @@ type ResourceRepository interface {
 	// User-specific operations
 	GetUserBookmarkedResources(ctx context.Context, userID primitive.ObjectID, pagination ResourcePagination) ([]Resource, int64, error)
 	GetUserLikedResources(ctx context.Context, userID primitive.ObjectID, pagination ResourcePagination) ([]Resource, int64, error)
+
+	// Account deletion
+	DeleteResourcesByCreator(ctx context.Context, creatorID primitive.ObjectID) error
+	ReassignResourcesCreator(ctx context.Context, fromID, toID primitive.ObjectID) error
+
+	// Data export
+	GetAllResourcesByCreator(ctx context.Context, creatorID primitive.ObjectID) ([]Resource, error)
 }
 
 // ResourceStats represents analytics data for a resource
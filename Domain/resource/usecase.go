@@ -49,6 +49,8 @@ type ResourceUsecase interface {
 	// Moderation and verification
 	ReportResource(ctx context.Context, resourceID, reporterID primitive.ObjectID, reason string) error
 	VerifyResource(ctx context.Context, resourceID, verifierID primitive.ObjectID) error
+	HideResource(ctx context.Context, resourceID, moderatorID primitive.ObjectID) error
+	UnhideResource(ctx context.Context, resourceID, moderatorID primitive.ObjectID) error
 	
 	// Validation
 	ValidateResourceType(resourceType string) error
@@ -0,0 +1,8 @@
+package services
+
+import "context"
+
+// IAIClient generates text from a prompt
+type IAIClient interface {
+	Generate(ctx context.Context, prompt string) (string, error)
+}
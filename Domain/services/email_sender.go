@@ -1,5 +1,127 @@
 package services
 
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// EmailTemplate identifies a transactional email. Each one has an HTML and a
+// plain-text variant, and its wording comes from per-locale translations.
+type EmailTemplate string
+
+const (
+	TemplateVerification       EmailTemplate = "verification"         // Code, ExpiresInMinutes
+	TemplatePasswordReset      EmailTemplate = "password-reset"       // Code, ExpiresInMinutes
+	TemplatePasswordChanged    EmailTemplate = "password-changed"     // SessionsEnded
+	TemplateAccountLocked      EmailTemplate = "account-locked"       // Failures, Until
+	TemplateSignInLink         EmailTemplate = "sign-in-link"         // Link, ExpiresInMinutes
+	TemplateSignInMethodAdded  EmailTemplate = "sign-in-method-added" // Provider
+	TemplateEmailChangeCode    EmailTemplate = "email-change-code"    // Code, ExpiresInMinutes
+	TemplateEmailChangePending EmailTemplate = "email-change-pending" // NewEmail, Code, CancelURL, CancelBefore
+	TemplateEmailChanged       EmailTemplate = "email-changed"        // NewEmail
+	TemplateStudentEmailCode   EmailTemplate = "student-email-code"   // Code, Institution, ExpiresInMinutes
+	TemplateReauthCode         EmailTemplate = "reauth-code"          // Code, ExpiresInMinutes
+	TemplateDeletionScheduled  EmailTemplate = "deletion-scheduled"   // ScheduledFor
+	TemplateDeletionCancelled  EmailTemplate = "deletion-cancelled"
+	TemplateDataExportReady    EmailTemplate = "data-export-ready" // AvailableUntil
+	TemplateAccountSuspended   EmailTemplate = "account-suspended" // Reason, Until (nil for a ban)
+	TemplateMFAEnabled         EmailTemplate = "mfa-enabled"
+	TemplateMFADisabled        EmailTemplate = "mfa-disabled"
+	TemplateMFAReset           EmailTemplate = "mfa-reset"
+	TemplateRecoveryCodeUsed   EmailTemplate = "recovery-code-used"
+	TemplateMentorshipRequest  EmailTemplate = "mentorship-request"  // MenteeName, Topics, Message
+	TemplateMentorshipResponse EmailTemplate = "mentorship-response" // MentorName, Accepted
+	TemplateDigest             EmailTemplate = "digest"              // Since, Items ([]DigestItem)
+)
+
+// EmailTemplates lists every template, in the order the admin preview shows them
+var EmailTemplates = []EmailTemplate{
+	TemplateVerification,
+	TemplatePasswordReset,
+	TemplatePasswordChanged,
+	TemplateAccountLocked,
+	TemplateSignInLink,
+	TemplateSignInMethodAdded,
+	TemplateEmailChangeCode,
+	TemplateEmailChangePending,
+	TemplateEmailChanged,
+	TemplateStudentEmailCode,
+	TemplateReauthCode,
+	TemplateDeletionScheduled,
+	TemplateDeletionCancelled,
+	TemplateDataExportReady,
+	TemplateAccountSuspended,
+	TemplateMFAEnabled,
+	TemplateMFADisabled,
+	TemplateMFAReset,
+	TemplateRecoveryCodeUsed,
+	TemplateMentorshipRequest,
+	TemplateMentorshipResponse,
+	TemplateDigest,
+}
+
+// EmailData fills a template's placeholders; the keys each template reads
+// are listed next to its constant. Every template also takes an optional
+// Name for the greeting.
+type EmailData map[string]interface{}
+
+// DigestItem is one entry of the activity digest
+type DigestItem struct {
+	Title string
+	URL   string
+}
+
+// RenderedEmail is a template filled in for one locale
+type RenderedEmail struct {
+	Locale  string `json:"locale"` // the locale actually used after fallback
+	Subject string `json:"subject"`
+	HTML    string `json:"html"`
+	Text    string `json:"text"`
+}
+
 type IEmailSender interface {
-	SendEmail(to, subject, content string) error
-}
\ No newline at end of file
+	// SendEmail renders template in locale ("" for the default) and sends it
+	SendEmail(to, locale string, template EmailTemplate, data EmailData) error
+}
+
+// IEmailRenderer turns templates into email bodies
+type IEmailRenderer interface {
+	Render(template EmailTemplate, locale string, data EmailData) (RenderedEmail, error)
+	// SampleData is what the admin preview fills template with
+	SampleData(template EmailTemplate) (EmailData, bool)
+	Locales() []string
+}
+
+// IEmailTransport delivers an email that has already been rendered
+type IEmailTransport interface {
+	// Name identifies the transport in logs and the outbox
+	Name() string
+	// Deliver returns an error wrapping ErrEmailRejected when retrying cannot help
+	Deliver(ctx context.Context, to string, email RenderedEmail) error
+}
+
+// ErrEmailRejected means the provider refused the email itself, for example
+// because the address does not exist
+var ErrEmailRejected = errors.New("email rejected")
+
+// InboxEmail is an email caught by a development transport instead of being sent
+type InboxEmail struct {
+	ID      string    `json:"id"`
+	To      string    `json:"to"`
+	Subject string    `json:"subject"`
+	Date    time.Time `json:"date"`
+	Text    string    `json:"text,omitempty"`
+	HTML    string    `json:"html,omitempty"`
+}
+
+// IEmailInbox reads the emails a development transport has caught
+type IEmailInbox interface {
+	// List returns every email without its bodies, newest first
+	List(ctx context.Context) ([]InboxEmail, error)
+	// Get returns one email with its bodies, and the raw message
+	Get(ctx context.Context, id string) (InboxEmail, []byte, error)
+	Clear(ctx context.Context) error
+}
+
+var ErrInboxEmailNotFound = errors.New("email not found in inbox")
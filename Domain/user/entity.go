@@ -13,13 +13,14 @@ type User struct {
 	Fullname       string             `bson:"fullname" json:"fullname"`
 	Email          string             `bson:"email" json:"email"`
 	Password       string             `bson:"password" json:"password"`
-	Role           string             `bson:"role" json:"role"` // e.g. "admin", "user"
+	Role           string             `bson:"role" json:"role"` // one of the Role* constants
 	IsVerified     bool               `bson:"isVerified" json:"isVerified"`
 	Bio            string             `bson:"bio,omitempty" json:"bio,omitempty"`
 	ProfilePicture string             `bson:"profilePicture,omitempty" json:"profilePicture,omitempty"`
 	ContactInfo    ContactInfo        `bson:"contactInfo,omitempty" json:"contactInfo,omitempty"`
 	UpdatedAt      time.Time          `bson:"updatedAt" json:"updatedAt"`
 	PromotedBy     primitive.ObjectID `bson:"promoted_by,omitempty" json:"promoted_by,omitempty"`
+	Locale         string             `bson:"locale,omitempty" json:"locale,omitempty"` // language for emails, e.g. "fr" or "en-GB"
 
 	// ShareSpace Anonymous Identity
 	DisplayName string `bson:"displayName,omitempty" json:"displayName,omitempty"`
@@ -34,6 +35,163 @@ type User struct {
 
 	// Privacy Controls
 	PrivacySettings PrivacySettings `bson:"privacySettings" json:"privacySettings"`
+
+	// Two-factor authentication
+	MFA MFASettings `bson:"mfa,omitempty" json:"mfa"`
+
+	// Hashes of earlier passwords, oldest first, for the reuse check
+	PasswordHistory []string `bson:"passwordHistory,omitempty" json:"-"`
+
+	// Set by an admin; blocks sign-in and every token while in force
+	Suspension *Suspension `bson:"suspension,omitempty" json:"suspension,omitempty"`
+
+	// Accounts at OIDC providers that sign in as this user
+	Identities []LinkedIdentity `bson:"identities,omitempty" json:"identities,omitempty"`
+
+	// Set once the user confirms an address at a registered institution
+	Student *StudentStatus `bson:"student,omitempty" json:"student,omitempty"`
+}
+
+// StudentStatus records the institutional address a user verified. The
+// institution's name is copied so profiles can show it without a lookup.
+type StudentStatus struct {
+	InstitutionID primitive.ObjectID `bson:"institutionId" json:"institutionId"`
+	Institution   string             `bson:"institution" json:"institution"`
+	Email         string             `bson:"email" json:"email"`
+	VerifiedAt    time.Time          `bson:"verifiedAt" json:"verifiedAt"`
+}
+
+// StudentVerification is a code sent to an institutional address, waiting
+// to be confirmed
+type StudentVerification struct {
+	Email       string             `json:"email"`
+	Institution InstitutionSummary `json:"institution"`
+	ExpiresAt   time.Time          `json:"expiresAt"`
+}
+
+// InstitutionSummary names a user's institution on their public profile
+type InstitutionSummary struct {
+	ID   primitive.ObjectID `json:"id"`
+	Name string             `json:"name"`
+}
+
+// LinkedIdentity ties a user to an account at an OIDC provider
+type LinkedIdentity struct {
+	Provider string    `bson:"provider" json:"provider"`
+	Subject  string    `bson:"subject" json:"-"` // the provider's stable user ID ("sub")
+	Email    string    `bson:"email,omitempty" json:"email,omitempty"`
+	LinkedAt time.Time `bson:"linkedAt" json:"linkedAt"`
+}
+
+// Suspension keeps a user out until ExpiresAt. Without an expiry it is a
+// permanent ban.
+type Suspension struct {
+	Reason      string             `bson:"reason" json:"reason"`
+	SuspendedBy primitive.ObjectID `bson:"suspendedBy" json:"suspendedBy"`
+	SuspendedAt time.Time          `bson:"suspendedAt" json:"suspendedAt"`
+	ExpiresAt   *time.Time         `bson:"expiresAt,omitempty" json:"expiresAt,omitempty"`
+}
+
+// SuspensionError returns an *AccountSuspendedError while the user is
+// suspended or banned at now, and nil otherwise
+func (u User) SuspensionError(now time.Time) error {
+	s := u.Suspension
+	if s == nil || (s.ExpiresAt != nil && !now.Before(*s.ExpiresAt)) {
+		return nil
+	}
+	return &AccountSuspendedError{Reason: s.Reason, Until: s.ExpiresAt}
+}
+
+// UserFilter narrows the admin user list. Nil pointers match everything.
+type UserFilter struct {
+	Query        string // part of the username, email, full name or display name
+	Role         string
+	IsVerified   *bool
+	IsMentor     *bool
+	Suspended    *bool
+	SignedUpFrom *time.Time
+	SignedUpTo   *time.Time
+	Page         int
+	PageSize     int
+}
+
+// AdminUserView is a user as shown in the admin console
+type AdminUserView struct {
+	User
+	SignedUpAt time.Time `json:"signedUpAt"`
+}
+
+// UserListResponse is one page of the admin user list
+type UserListResponse struct {
+	Users      []AdminUserView `json:"users"`
+	Total      int64           `json:"total"`
+	Page       int             `json:"page"`
+	PageSize   int             `json:"pageSize"`
+	TotalPages int             `json:"totalPages"`
+}
+
+// Roles. Each account has exactly one; what it may do comes from RolePermissions.
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleVerifier  = "verifier"
+	RoleAdmin     = "admin"
+)
+
+// Permissions checked by RequirePermission
+const (
+	PermContentModerate    = "content:moderate"    // hide and unhide posts and resources
+	PermResourcesVerify    = "resources:verify"    // mark resources as verified
+	PermUsersManage        = "users:manage"        // unlock, suspend and inspect accounts
+	PermRolesManage        = "roles:manage"        // grant and revoke roles
+	PermAuditRead          = "audit:read"          // query and export the audit log
+	PermEmailManage        = "email:manage"        // preview email templates and manage the outbox
+	PermInstitutionsManage = "institutions:manage" // register institutions and their email domains
+)
+
+// RolePermissions maps every role to what it may do
+var RolePermissions = map[string][]string{
+	RoleUser:      {},
+	RoleModerator: {PermContentModerate},
+	RoleVerifier:  {PermResourcesVerify},
+	RoleAdmin:     {PermContentModerate, PermResourcesVerify, PermUsersManage, PermRolesManage, PermAuditRead, PermEmailManage, PermInstitutionsManage},
+}
+
+// IsValidRole reports whether role is one of the known roles
+func IsValidRole(role string) bool {
+	_, ok := RolePermissions[role]
+	return ok
+}
+
+// PermissionsForRole returns the permissions of role; unknown roles get none
+func PermissionsForRole(role string) []string {
+	return append([]string{}, RolePermissions[role]...)
+}
+
+// MFASettings holds a user's TOTP enrollment. The secret and recovery codes
+// never leave the server after enrollment.
+type MFASettings struct {
+	Enabled       bool       `bson:"enabled" json:"enabled"`
+	Secret        string     `bson:"secret,omitempty" json:"-"`        // base32, active once Enabled
+	PendingSecret string     `bson:"pendingSecret,omitempty" json:"-"` // awaiting a confirmation code
+	RecoveryCodes []string   `bson:"recoveryCodes,omitempty" json:"-"` // hashed, removed once used
+	LastUsedStep  int64      `bson:"lastUsedStep,omitempty" json:"-"`  // stops a code being replayed in its window
+	EnabledAt     *time.Time `bson:"enabledAt,omitempty" json:"enabledAt,omitempty"`
+}
+
+// MFAEnrollment is what a user needs to add the account to an authenticator app
+type MFAEnrollment struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// MFAChallenge is issued after a correct password on an MFA-enabled account.
+// It is single-use and only the hash of its token is stored.
+type MFAChallenge struct {
+	TokenHash    string             `bson:"_id"`
+	UserID       primitive.ObjectID `bson:"userId"`
+	ExpiresAt    time.Time          `bson:"expiresAt"`
+	AttemptCount int                `bson:"attemptCount"`
 }
 
 type ContactInfo struct {
@@ -55,6 +213,7 @@ type UpdateProfileRequest struct {
 	Bio            string      `json:"bio,omitempty"`
 	ProfilePicture string      `json:"profilePicture,omitempty"`
 	ContactInfo    ContactInfo `json:"contactInfo,omitempty"`
+	Locale         string      `json:"locale,omitempty"`
 
 	// ShareSpace fields
 	DisplayName           string          `json:"displayName,omitempty"`
@@ -68,13 +227,226 @@ type UpdateProfileRequest struct {
 }
 
 // Token struct (We put it here since it's related with the User)
+// Every login starts a new rotation family; each refresh stores a child token
+// pointing at its parent and marks the parent as rotated.
 type Token struct {
 	ID           primitive.ObjectID `bson:"_id,omitempty"`
 	UserID       primitive.ObjectID `bson:"user_id"`
+	FamilyID     primitive.ObjectID `bson:"family_id,omitempty"`
+	ParentID     primitive.ObjectID `bson:"parent_id,omitempty"`
 	AccessToken  string             `bson:"access_token"`
 	RefreshToken string             `bson:"refresh_token"`
 	CreatedAt    time.Time          `bson:"created_at"`
 	ExpiresAt    time.Time          `bson:"expires_at"`
+	RotatedAt    *time.Time         `bson:"rotated_at,omitempty"`
+	MFA          bool               `bson:"mfa,omitempty"` // the session passed a second factor
+
+	// Device metadata shown in the sessions list
+	DeviceName string    `bson:"device_name,omitempty"`
+	UserAgent  string    `bson:"user_agent,omitempty"`
+	IP         string    `bson:"ip,omitempty"`
+	LastUsedAt time.Time `bson:"last_used_at,omitempty"`
+}
+
+// DeviceInfo describes the client a login or refresh came from
+type DeviceInfo struct {
+	DeviceName string
+	UserAgent  string
+	IP         string
+}
+
+// Session is one login as seen by its owner. Its ID is the token family ID,
+// which access tokens carry in the "sid" claim.
+type Session struct {
+	ID         primitive.ObjectID `json:"id"`
+	DeviceName string             `json:"deviceName,omitempty"`
+	UserAgent  string             `json:"userAgent,omitempty"`
+	IP         string             `json:"ip,omitempty"`
+	CreatedAt  time.Time          `json:"createdAt"`
+	LastUsedAt time.Time          `json:"lastUsedAt"`
+	ExpiresAt  time.Time          `json:"expiresAt"`
+	Current    bool               `json:"current"`
+}
+
+// PersonalAccessTokenPrefix starts every personal access token, which tells
+// them apart from JWTs in the Authorization header
+const PersonalAccessTokenPrefix = "ssp_"
+
+// Scopes a personal access token can be limited to
+const (
+	ScopeProfileRead    = "profile:read"
+	ScopePostsWrite     = "posts:write"
+	ScopeResourcesWrite = "resources:write"
+	ScopeCommentsWrite  = "comments:write"
+)
+
+// TokenScopes lists every scope a personal access token may be given
+var TokenScopes = []string{ScopeProfileRead, ScopePostsWrite, ScopeResourcesWrite, ScopeCommentsWrite}
+
+// PersonalAccessToken lets scripts call the API as a user without their
+// password. Only a hash of the token is kept; it is shown once, at creation.
+type PersonalAccessToken struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID     primitive.ObjectID `bson:"userId" json:"-"`
+	Name       string             `bson:"name" json:"name"`
+	Prefix     string             `bson:"prefix" json:"prefix"` // first characters, to recognise the token
+	TokenHash  string             `bson:"tokenHash" json:"-"`
+	Scopes     []string           `bson:"scopes" json:"scopes"`
+	CreatedAt  time.Time          `bson:"createdAt" json:"createdAt"`
+	ExpiresAt  *time.Time         `bson:"expiresAt,omitempty" json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time         `bson:"lastUsedAt,omitempty" json:"lastUsedAt,omitempty"`
+}
+
+// Expired reports whether the token has passed its expiry at now
+func (t PersonalAccessToken) Expired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
+
+// CreatedAccessToken is a new token together with its secret value
+type CreatedAccessToken struct {
+	PersonalAccessToken
+	Token string `json:"token"`
+}
+
+// OIDCIdentity is what a provider vouches for in a validated ID token
+type OIDCIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Picture       string
+}
+
+// OAuthState remembers a sign-in started with an OIDC provider until the
+// provider redirects back. It is keyed by a hash of the state parameter and
+// can be used once.
+type OAuthState struct {
+	StateHash    string    `bson:"_id"`
+	Provider     string    `bson:"provider"`
+	CodeVerifier string    `bson:"codeVerifier"` // PKCE
+	Nonce        string    `bson:"nonce"`
+	CreatedAt    time.Time `bson:"createdAt"`
+	ExpiresAt    time.Time `bson:"expiresAt"`
+}
+
+// SigningKey is an asymmetric JWT signing key shared by every API instance.
+// Tokens are signed with the newest active key and verified with any
+// unexpired one, including keys published ahead of their activation.
+type SigningKey struct {
+	ID          string    `bson:"_id"`        // published as the JWT "kid" header
+	Algorithm   string    `bson:"alg"`        // "RS256" or "EdDSA"
+	PrivateKey  []byte    `bson:"privateKey"` // PKCS#8 DER
+	CreatedAt   time.Time `bson:"createdAt"`
+	ActivatesAt time.Time `bson:"activatesAt,omitempty"` // starts signing; zero means at CreatedAt
+	ExpiresAt   time.Time `bson:"expiresAt"`             // after this no token signed by it is still valid
+}
+
+// JWK is a public key in RFC 7517 form
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// OKP (Ed25519)
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+}
+
+// JWKSMaxAge is how long verifiers may cache the JWK set. New signing keys
+// are published at least this long before they are used.
+const JWKSMaxAge = 5 * time.Minute
+
+// JWKSet is the document served at /.well-known/jwks.json
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// LoginAttempts counts recent failed logins for one account or one client IP
+type LoginAttempts struct {
+	Key           string     `bson:"_id"` // "account:<userID>" or "ip:<address>"
+	Failures      int        `bson:"failures"`
+	LastFailureAt time.Time  `bson:"lastFailureAt"`
+	LockedUntil   *time.Time `bson:"lockedUntil,omitempty"`
+	ExpiresAt     time.Time  `bson:"expiresAt"` // failures are forgotten once this passes
+}
+
+// OTPSends counts OTP emails sent to one address or requested from one IP
+// within a window that starts at the first send.
+type OTPSends struct {
+	Key         string    `bson:"_id"` // "<purpose>:email:<address>" or "ip:<address>"
+	Count       int       `bson:"count"`
+	WindowStart time.Time `bson:"windowStart"`
+	LastSentAt  time.Time `bson:"lastSentAt"`
+	ExpiresAt   time.Time `bson:"expiresAt"` // the count starts over once this passes
+}
+
+// What happens to a deleted account's posts, comments and resources
+const (
+	DeletionContentDelete    = "delete"    // removed along with the account
+	DeletionContentAnonymize = "anonymize" // kept and credited to the "Deleted user" placeholder
+)
+
+// TombstoneUsername is the name shown on the placeholder account that
+// anonymized content and messages of deleted users point to. It is reserved
+// at signup, but the account itself is found by TombstoneUserID.
+const TombstoneUsername = "deleted-user"
+
+// TombstoneUserID is the fixed ID of the placeholder account, so a user who
+// picks its name can never be mistaken for it
+var TombstoneUserID = primitive.ObjectID{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}
+
+// AccountDeletion is a pending request to delete an account. Nothing is
+// removed until ScheduledFor, and the user can cancel until then.
+type AccountDeletion struct {
+	UserID       primitive.ObjectID `bson:"_id" json:"userId"`
+	Email        string             `bson:"email" json:"-"` // verifications and resets are keyed by email
+	ContentMode  string             `bson:"contentMode" json:"contentMode"`
+	RequestedAt  time.Time          `bson:"requestedAt" json:"requestedAt"`
+	ScheduledFor time.Time          `bson:"scheduledFor" json:"scheduledFor"`
+	ClaimedAt    *time.Time         `bson:"claimedAt,omitempty" json:"-"` // set while a worker is erasing the account
+}
+
+// EmailChange is a request to move an account to a new address. It completes
+// once both addresses confirm their codes, and the old address can cancel it
+// until then.
+type EmailChange struct {
+	UserID      primitive.ObjectID `bson:"_id" json:"-"`
+	OldEmail    string             `bson:"oldEmail" json:"-"`
+	OldEmailOTP string             `bson:"oldEmailOtp" json:"-"` // hashed code sent to the old address
+	NewEmail    string             `bson:"newEmail" json:"newEmail"`
+	RequestedAt time.Time          `bson:"requestedAt" json:"requestedAt"`
+	ExpiresAt   time.Time          `bson:"expiresAt" json:"expiresAt"`
+}
+
+// Data export states
+const (
+	ExportPending = "pending"
+	ExportRunning = "running"
+	ExportReady   = "ready"
+	ExportFailed  = "failed"
+)
+
+// DataExport is a user's request for an archive of their data. The archive
+// is built in the background and kept until ExpiresAt.
+type DataExport struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID      primitive.ObjectID `bson:"userId" json:"-"`
+	Status      string             `bson:"status" json:"status"`
+	Error       string             `bson:"error,omitempty" json:"error,omitempty"`
+	File        string             `bson:"file,omitempty" json:"-"` // name in the export store
+	Size        int64              `bson:"size,omitempty" json:"size,omitempty"`
+	RequestedAt time.Time          `bson:"requestedAt" json:"requestedAt"`
+	CompletedAt *time.Time         `bson:"completedAt,omitempty" json:"completedAt,omitempty"`
+	ExpiresAt   *time.Time         `bson:"expiresAt,omitempty" json:"expiresAt,omitempty"` // the archive is deleted after this
+	ClaimedAt   *time.Time         `bson:"claimedAt,omitempty" json:"-"`
+
+	// Signed link for a ready archive, issued on each status check
+	DownloadURL       string     `bson:"-" json:"downloadUrl,omitempty"`
+	DownloadExpiresAt *time.Time `bson:"-" json:"downloadExpiresAt,omitempty"`
 }
 
 // Response upon login
@@ -85,9 +457,40 @@ type TokenResult struct {
 	RefreshExpiresAt time.Time
 }
 
+// TokenSubject is who a token pair is issued to
+type TokenSubject struct {
+	UserID    string
+	Username  string
+	Role      string
+	SessionID string
+	MFA       bool // the session was authenticated with a second factor
+
+	Permissions []string // carried in the "permissions" claim
+}
+
+// LoginResult is the outcome of a login. For accounts with MFA enabled a
+// password login only yields an MFAToken, which CompleteMFALogin exchanges
+// for the token pair.
+type LoginResult struct {
+	User         User
+	AccessToken  string
+	RefreshToken string
+	MFAToken     string
+	MFAExpiresAt time.Time
+}
+
+// MFARequired reports whether the login still needs a second factor
+func (r LoginResult) MFARequired() bool {
+	return r.MFAToken != ""
+}
+
+// PasswordReset tracks a reset flow for an email. It holds the hashed OTP
+// until VerifyOTP succeeds, after which the OTP is replaced by a hashed,
+// single-use reset grant that ResetPassword must present.
 type PasswordReset struct {
 	Email        string    `bson:"email"`
-	OTP          string    `bson:"otp"`
+	OTP          string    `bson:"otp,omitempty"`
+	GrantHash    string    `bson:"granthash,omitempty"`
 	ExpiresAt    time.Time `bson:"expiresat"`
 	AttemptCount int       `bson:"attemptcount"`
 }
@@ -101,15 +504,17 @@ type Verification struct {
 
 // PublicProfile represents what other users can see (respects privacy settings)
 type PublicProfile struct {
-	ID                    primitive.ObjectID `json:"id"`
-	DisplayName           string             `json:"displayName"`
-	Bio                   string             `json:"bio,omitempty"`
-	ProfilePicture        string             `json:"profilePicture,omitempty"`
-	IsMentor              bool               `json:"isMentor"`
-	IsMentee              bool               `json:"isMentee"`
-	MentorshipTopics      []string           `json:"mentorshipTopics,omitempty"`
-	MentorshipBio         string             `json:"mentorshipBio,omitempty"`
-	AvailableForMentoring bool               `json:"availableForMentoring"`
+	ID                    primitive.ObjectID  `json:"id"`
+	DisplayName           string              `json:"displayName"`
+	Bio                   string              `json:"bio,omitempty"`
+	ProfilePicture        string              `json:"profilePicture,omitempty"`
+	IsMentor              bool                `json:"isMentor"`
+	IsMentee              bool                `json:"isMentee"`
+	MentorshipTopics      []string            `json:"mentorshipTopics,omitempty"`
+	MentorshipBio         string              `json:"mentorshipBio,omitempty"`
+	AvailableForMentoring bool                `json:"availableForMentoring"`
+	VerifiedStudent       bool                `json:"verifiedStudent"`
+	Institution           *InstitutionSummary `json:"institution,omitempty"`
 
 	// These fields are only included if privacy settings allow
 	Fullname    string      `json:"fullname,omitempty"`
@@ -1,35 +1,141 @@
 package userpkg
 
-import "context"
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 // IUserRepository defines user data access operations
 type IUserRepository interface {
 	FindByID(ctx context.Context, userID string) (User, error)
+	FindByEmail(ctx context.Context, email string) (User, error)
 	ExistsByUsername(ctx context.Context, username string) (bool, error)
 	ExistsByEmail(ctx context.Context, email string) (bool, error)
 	CountUsers(ctx context.Context) (int64, error)
 	CreateUser(ctx context.Context, user User) (User, error)
 	GetUserByLogin(ctx context.Context, login string) (User, error)
 	UpdatePasswordByEmail(ctx context.Context, email, hashedPassword string) error
+	// UpdatePassword sets a new hash and moves the old one into the password
+	// history, keeping at most keepHistory earlier hashes
+	UpdatePassword(ctx context.Context, userID, hashedPassword string, keepHistory int) error
 	UpdateUserRoleByID(ctx context.Context, userID, role string) error
 	UpdateIsVerifiedByEmail(ctx context.Context, email string, verified bool) error
 	UpdateProfile(ctx context.Context, userID string, updates UpdateProfileRequest) (User, error)
 	GetUserProfile(ctx context.Context, userID string) (User, error)
 	UpdateRoleAndPromoter(ctx context.Context, userID string, role string, promoterID *string) error
 
+	// Two-factor authentication
+	UpdateMFA(ctx context.Context, userID string, mfa MFASettings) error
+	ConsumeTOTPStep(ctx context.Context, userID string, step int64) error
+	ConsumeRecoveryCode(ctx context.Context, userID, codeHash string) error
+	UpdateRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error
+
+	// UpdateEmail moves the account to a confirmed address, or returns
+	// ErrEmailTaken if another account got there first
+	UpdateEmail(ctx context.Context, userID, email string) error
+
+	// Admin console
+	ListUsers(ctx context.Context, filter UserFilter) ([]User, int64, error)
+	// UpdateSuspension sets the user's suspension, or lifts it when nil
+	UpdateSuspension(ctx context.Context, userID string, suspension *Suspension) error
+
+	// OIDC sign-in
+	// FindByIdentity returns the user linked to subject at provider
+	FindByIdentity(ctx context.Context, provider, subject string) (User, error)
+	// AddIdentity links an OIDC account, replacing an earlier link to the same provider
+	AddIdentity(ctx context.Context, userID string, identity LinkedIdentity) error
+
+	// Account deletion
+	DeleteUser(ctx context.Context, userID string) error
+	// EnsureTombstoneUser returns the "Deleted user" placeholder, creating it on first use
+	EnsureTombstoneUser(ctx context.Context) (User, error)
+
+	// Verified students
+	// UpdateStudentStatus sets the user's student status, or clears it when nil
+	UpdateStudentStatus(ctx context.Context, userID string, status *StudentStatus) error
+	// StudentEmailTaken reports whether an account other than userID verified email
+	StudentEmailTaken(ctx context.Context, email, userID string) (bool, error)
+	FindStudentIDs(ctx context.Context, institutionID primitive.ObjectID) ([]primitive.ObjectID, error)
+	RenameStudentInstitution(ctx context.Context, institutionID primitive.ObjectID, name string) error
+	// ClearStudentInstitution takes student status away from everyone verified at the institution
+	ClearStudentInstitution(ctx context.Context, institutionID primitive.ObjectID) (int64, error)
+
 	// ShareSpace-specific methods
 	GetPublicProfile(ctx context.Context, userID string) (PublicProfile, error)
-	FindMentors(ctx context.Context, topics []string, limit int, offset int) ([]PublicProfile, error)
+	// FindMentors matches any of topics, or every topic when there are none.
+	// A non-zero institutionID keeps only its verified students.
+	FindMentors(ctx context.Context, topics []string, institutionID primitive.ObjectID, limit int, offset int) ([]PublicProfile, error)
 	FindMentees(ctx context.Context, topics []string, limit int, offset int) ([]PublicProfile, error)
 	ExistsByDisplayName(ctx context.Context, displayName string) (bool, error)
 	SearchUsersByTopic(ctx context.Context, topic string, isMentor bool, limit int, offset int) ([]PublicProfile, error)
 }
 
+// ErrRefreshTokenReused is returned when a refresh token that was already
+// rotated is presented again.
+var ErrRefreshTokenReused = errors.New("refresh token reuse detected")
+
+// ErrSessionNotFound is returned when a session does not exist, has expired or
+// belongs to another user.
+var ErrSessionNotFound = errors.New("session not found")
+
+// ErrDeletionNotFound is returned when an account has no pending deletion
+var ErrDeletionNotFound = errors.New("no pending account deletion")
+
+// ErrDeletionInProgress is returned when a deletion can no longer be
+// cancelled because the account is already being erased.
+var ErrDeletionInProgress = errors.New("account deletion already in progress")
+
+// ErrExportNotFound is returned when a data export does not exist or belongs
+// to another user
+var ErrExportNotFound = errors.New("data export not found")
+
+// ErrInvalidLink is returned when a signed link has been tampered with or has
+// expired
+var ErrInvalidLink = errors.New("invalid or expired link")
+
+// ErrEmailChangeNotFound is returned when an account has no pending email
+// change, or it has expired
+var ErrEmailChangeNotFound = errors.New("no pending email change")
+
+// ErrEmailTaken is returned when another account already uses an address
+var ErrEmailTaken = errors.New("email already taken")
+
+// ErrNoInstitutionForEmail is returned when a student address is not at any
+// registered institution's domain
+var ErrNoInstitutionForEmail = errors.New("email domain does not belong to a registered institution")
+
+// ErrStudentEmailTaken is returned when another account already verified an
+// institutional address
+var ErrStudentEmailTaken = errors.New("institutional email already verified by another account")
+
+// ErrAccessTokenNotFound is returned when a personal access token does not
+// exist or belongs to someone else
+var ErrAccessTokenNotFound = errors.New("access token not found")
+
+// ErrOAuthStateNotFound is returned when an OIDC callback carries a state that
+// was never issued, has expired or was already used
+var ErrOAuthStateNotFound = errors.New("invalid or expired sign-in state")
+
+// ErrMFACodeUsed is returned when a TOTP code or recovery code has already
+// been used.
+var ErrMFACodeUsed = errors.New("mfa code already used")
+
 type ITokenRepository interface {
 	StoreToken(ctx context.Context, token Token) error
 	FindByRefreshToken(ctx context.Context, refreshToken string) (Token, error)
 	DeleteByRefreshToken(ctx context.Context, refreshToken string) error
 	DeleteTokensByUserID(ctx context.Context, userID string) error
+	MarkTokenRotated(ctx context.Context, tokenID primitive.ObjectID) error
+	DeleteTokensByFamilyID(ctx context.Context, familyID primitive.ObjectID) error
+
+	// Sessions are token families; only the current (unrotated) token of each is active
+	FindActiveSessionsByUserID(ctx context.Context, userID primitive.ObjectID) ([]Token, error)
+	TouchSession(ctx context.Context, familyID primitive.ObjectID) error
+	DeleteSession(ctx context.Context, userID, familyID primitive.ObjectID) error
+	DeleteOtherSessions(ctx context.Context, userID, keepFamilyID primitive.ObjectID) error
 }
 
 type IPasswordResetRepository interface {
@@ -45,3 +151,101 @@ type IVerificationRepository interface {
 	DeleteVerification(ctx context.Context, email string) error
 	IncrementAttemptCount(ctx context.Context, email string) error
 }
+
+// ISigningKeyRepository persists JWT signing keys so every instance signs and
+// verifies with the same key ring.
+type ISigningKeyRepository interface {
+	SaveKey(ctx context.Context, key SigningKey) error
+	FindActiveKeys(ctx context.Context, now time.Time) ([]SigningKey, error)
+}
+
+// IMFAChallengeRepository stores pending second-factor logins by token hash
+type IMFAChallengeRepository interface {
+	StoreChallenge(ctx context.Context, challenge MFAChallenge) error
+	FindChallenge(ctx context.Context, tokenHash string) (MFAChallenge, error)
+	IncrementChallengeAttempts(ctx context.Context, tokenHash string) error
+	DeleteChallenge(ctx context.Context, tokenHash string) error
+	DeleteChallengesByUserID(ctx context.Context, userID primitive.ObjectID) error
+}
+
+// ILoginAttemptRepository tracks failed logins per account and per IP. A
+// missing record reads as zero failures.
+type ILoginAttemptRepository interface {
+	GetLoginAttempts(ctx context.Context, key string) (LoginAttempts, error)
+	// RecordLoginFailure counts a failure and keeps the record for window;
+	// failures older than the previous window start over from one.
+	RecordLoginFailure(ctx context.Context, key string, at time.Time, window time.Duration) (LoginAttempts, error)
+	LockLogin(ctx context.Context, key string, until time.Time) error
+	ResetLoginAttempts(ctx context.Context, key string) error
+}
+
+// IOTPSendRepository tracks OTP emails per address and per IP. A missing
+// record reads as no sends.
+type IOTPSendRepository interface {
+	GetOTPSends(ctx context.Context, key string) (OTPSends, error)
+	// RecordOTPSend counts a send; once the window has passed the count
+	// starts over from one with a new window.
+	RecordOTPSend(ctx context.Context, key string, at time.Time, window time.Duration) (OTPSends, error)
+}
+
+// IAccountDeletionRepository holds scheduled account deletions
+type IAccountDeletionRepository interface {
+	ScheduleDeletion(ctx context.Context, deletion AccountDeletion) error
+	GetDeletion(ctx context.Context, userID string) (AccountDeletion, error)
+	// CancelDeletion fails with ErrDeletionInProgress once a worker has claimed it
+	CancelDeletion(ctx context.Context, userID string) error
+	// ClaimDueDeletion hands one due deletion to a worker. A claim older than
+	// lease is assumed abandoned and handed out again. ok is false when
+	// nothing is due.
+	ClaimDueDeletion(ctx context.Context, now time.Time, lease time.Duration) (deletion AccountDeletion, ok bool, err error)
+	CompleteDeletion(ctx context.Context, userID string) error
+}
+
+// IDataExportRepository tracks data export requests and their archives
+type IDataExportRepository interface {
+	CreateExport(ctx context.Context, export DataExport) (DataExport, error)
+	GetExport(ctx context.Context, exportID string) (DataExport, error)
+	// GetLatestExport returns the user's most recent export or ErrExportNotFound
+	GetLatestExport(ctx context.Context, userID string) (DataExport, error)
+	// ClaimPendingExport hands one pending export to a worker and marks it
+	// running. A running export claimed longer than lease ago is handed out
+	// again. ok is false when nothing is waiting.
+	ClaimPendingExport(ctx context.Context, now time.Time, lease time.Duration) (export DataExport, ok bool, err error)
+	MarkExportReady(ctx context.Context, exportID, file string, size int64, completedAt, expiresAt time.Time) error
+	MarkExportFailed(ctx context.Context, exportID, reason string) error
+	FindExpiredExports(ctx context.Context, now time.Time) ([]DataExport, error)
+	// FindExportsByUserID returns all of the user's exports, whatever their state
+	FindExportsByUserID(ctx context.Context, userID primitive.ObjectID) ([]DataExport, error)
+	DeleteExport(ctx context.Context, exportID string) error
+}
+
+// IPersonalAccessTokenRepository stores personal access tokens by hash
+type IPersonalAccessTokenRepository interface {
+	CreateAccessToken(ctx context.Context, token PersonalAccessToken) (PersonalAccessToken, error)
+	ListAccessTokens(ctx context.Context, userID primitive.ObjectID) ([]PersonalAccessToken, error)
+	CountAccessTokens(ctx context.Context, userID primitive.ObjectID) (int64, error)
+	// FindAccessTokenByHash returns ErrAccessTokenNotFound for unknown tokens
+	FindAccessTokenByHash(ctx context.Context, tokenHash string) (PersonalAccessToken, error)
+	TouchAccessToken(ctx context.Context, tokenID primitive.ObjectID, usedAt time.Time) error
+	// DeleteAccessToken returns ErrAccessTokenNotFound unless userID owns it
+	DeleteAccessToken(ctx context.Context, userID, tokenID primitive.ObjectID) error
+	// DeleteAccessTokensByUserID revokes all of the user's tokens
+	DeleteAccessTokensByUserID(ctx context.Context, userID primitive.ObjectID) error
+}
+
+// IOAuthStateRepository holds OIDC sign-ins between the redirect to the
+// provider and the callback
+type IOAuthStateRepository interface {
+	SaveOAuthState(ctx context.Context, state OAuthState) error
+	// ConsumeOAuthState removes and returns the state, or returns
+	// ErrOAuthStateNotFound if it is unknown or expired
+	ConsumeOAuthState(ctx context.Context, stateHash string) (OAuthState, error)
+}
+
+// IEmailChangeRepository holds pending email changes, one per user
+type IEmailChangeRepository interface {
+	SaveEmailChange(ctx context.Context, change EmailChange) error
+	// GetEmailChange returns ErrEmailChangeNotFound when none is pending
+	GetEmailChange(ctx context.Context, userID string) (EmailChange, error)
+	DeleteEmailChange(ctx context.Context, userID string) error
+}
@@ -2,27 +2,150 @@ package userpkg
 
 import (
 	"context"
+	"io"
 	"mime/multipart"
+	"time"
 )
 
+// AccessTokenTTL is how long an access token stays valid. Revocations only need
+// to be remembered for this long.
+const AccessTokenTTL = 15 * time.Minute
+
+// RefreshTokenTTL is how long a refresh token stays valid
+const RefreshTokenTTL = 7 * 24 * time.Hour
+
+// Token types, carried in the "typ" claim so a refresh token cannot be used
+// as an access token or the other way round
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
+// LoginThrottledError is returned when a login is refused because of recent
+// failed attempts. The password is not checked.
+type LoginThrottledError struct {
+	Locked     bool
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	if e.Locked {
+		return "account temporarily locked after too many failed login attempts"
+	}
+	return "too many failed login attempts, try again later"
+}
+
+// AccountSuspendedError is returned when a suspended or banned user signs in
+// or presents a token
+type AccountSuspendedError struct {
+	Reason string
+	Until  *time.Time // nil for a ban
+}
+
+func (e *AccountSuspendedError) Error() string {
+	if e.Until == nil {
+		return "account banned: " + e.Reason
+	}
+	return "account suspended until " + e.Until.UTC().Format(time.RFC3339) + ": " + e.Reason
+}
+
+// OTPThrottledError is returned when an OTP email is refused by a cooldown
+// or the daily cap. No email is sent.
+type OTPThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *OTPThrottledError) Error() string {
+	return "too many code requests, try again later"
+}
+
 type IUserUsecase interface {
 	RegisterUser(ctx context.Context, user User) (User, error)
-	Logout(ctx context.Context, userID string) error
-	LoginUser(ctx context.Context, login string, password string) (User, string, string, error)
-	RefreshToken(ctx context.Context, refreshToken string) (TokenResult, error)
-	SendResetOTP(ctx context.Context, email string) error
-	VerifyOTP(ctx context.Context, email, otp string) error
-	ResetPassword(ctx context.Context, email, newPassword string) error
+	Logout(ctx context.Context, userID, sessionID, tokenID string) error
+	LoginUser(ctx context.Context, login string, password string, device DeviceInfo) (LoginResult, error)
+	CompleteMFALogin(ctx context.Context, mfaToken, code string, device DeviceInfo) (LoginResult, error)
+	RefreshToken(ctx context.Context, refreshToken string, device DeviceInfo) (TokenResult, error)
+	// Passwordless sign-in through a single-use link sent by email
+	RequestMagicLink(ctx context.Context, email, clientIP string) error
+	LoginWithMagicLink(ctx context.Context, email, token string, expiresAt time.Time, signature string, device DeviceInfo) (LoginResult, error)
+	// Sign-in through OIDC providers such as Google or a university SSO
+	OIDCProviders() []string
+	// StartOIDCLogin returns the provider URL and the state, which the
+	// browser must keep and present again as browserState in the callback
+	StartOIDCLogin(ctx context.Context, provider string) (authURL, state string, err error)
+	CompleteOIDCLogin(ctx context.Context, provider, state, browserState, code string, device DeviceInfo) (LoginResult, error)
+	SendResetOTP(ctx context.Context, email, clientIP string) error
+	VerifyOTP(ctx context.Context, email, otp string) (string, error)
+	ResetPassword(ctx context.Context, email, resetToken, newPassword string) error
 	PromoteUser(ctx context.Context, targetUserID string, actorUserID string) error
 	DemoteUser(ctx context.Context, targetUserID string, actorUserID string) error
-	SendVerificationOTP(ctx context.Context, email string) error
+	GrantRole(ctx context.Context, targetUserID, role, actorUserID string) error
+	RevokeRole(ctx context.Context, targetUserID, role, actorUserID string) error
+
+	// Admin console
+	ListUsers(ctx context.Context, filter UserFilter) (UserListResponse, error)
+	GetUserForAdmin(ctx context.Context, userID string) (AdminUserView, error)
+	SuspendUser(ctx context.Context, targetUserID, actorUserID, reason string, expiresAt *time.Time) (AdminUserView, error)
+	LiftSuspension(ctx context.Context, targetUserID, actorUserID string) error
+	ForceReverification(ctx context.Context, targetUserID, actorUserID string) error
+	ResetUserMFA(ctx context.Context, targetUserID, actorUserID string) error
+	UnlockUser(ctx context.Context, targetUserID, actorUserID string) error
+	SendVerificationOTP(ctx context.Context, email, clientIP string) error
 	VerifyUser(ctx context.Context, email, otp string) error
 	UpdateProfile(ctx context.Context, userID string, updates UpdateProfileRequest, file multipart.File, filename string) (User, error)
 	GetUserProfile(ctx context.Context, userID string) (User, error)
 
+	// Session management
+	ListSessions(ctx context.Context, userID, currentSessionID string) ([]Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID string) error
+	RevokeOtherSessions(ctx context.Context, userID, currentSessionID string) error
+
+	// Two-factor authentication
+	EnrollMFA(ctx context.Context, userID string) (MFAEnrollment, error)
+	ConfirmMFA(ctx context.Context, userID, code string) ([]string, error)
+	DisableMFA(ctx context.Context, userID, password, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error)
+
+	// SendReauthCode emails a code that accounts without a password give in
+	// place of one to RequestAccountDeletion and RequestEmailChange
+	SendReauthCode(ctx context.Context, userID, clientIP string) error
+
+	// Account deletion
+	RequestAccountDeletion(ctx context.Context, userID, password, contentMode string) (AccountDeletion, error)
+	GetAccountDeletion(ctx context.Context, userID string) (AccountDeletion, error)
+	CancelAccountDeletion(ctx context.Context, userID string) error
+
+	// ChangePassword requires the current password and signs out every other session
+	ChangePassword(ctx context.Context, userID, sessionID, currentPassword, newPassword string) error
+
+	// Email change
+	RequestEmailChange(ctx context.Context, userID, password, newEmail, clientIP string) (EmailChange, error)
+	// ConfirmEmailChange needs the codes sent to both the new and the old address
+	ConfirmEmailChange(ctx context.Context, userID, otp, oldEmailOTP string) (User, error)
+	// CancelEmailChange checks the signed link sent to the old address
+	CancelEmailChange(ctx context.Context, userID, signature string) error
+
+	// Verified students
+	// RequestStudentVerification sends a code to an address at a registered institution
+	RequestStudentVerification(ctx context.Context, userID, email, clientIP string) (StudentVerification, error)
+	ConfirmStudentVerification(ctx context.Context, userID, email, otp string) (User, error)
+	RemoveStudentStatus(ctx context.Context, userID string) error
+
+	// Data export
+	RequestDataExport(ctx context.Context, userID string) (DataExport, error)
+	GetDataExport(ctx context.Context, userID, exportID string) (DataExport, error)
+	// OpenDataExport checks a signed download link and opens the archive
+	OpenDataExport(ctx context.Context, exportID string, expiresAt time.Time, signature string) (io.ReadCloser, DataExport, error)
+
+	// Personal access tokens
+	CreateAccessToken(ctx context.Context, userID, name string, scopes []string, expiresAt *time.Time) (CreatedAccessToken, error)
+	ListAccessTokens(ctx context.Context, userID string) ([]PersonalAccessToken, error)
+	RevokeAccessToken(ctx context.Context, userID, tokenID string) error
+
 	// ShareSpace-specific methods
 	GetPublicProfile(ctx context.Context, userID string) (PublicProfile, error)
-	FindMentors(ctx context.Context, topics []string, limit int, offset int) ([]PublicProfile, error)
+	// FindMentors narrows to the verified students of institutionID when it is set
+	FindMentors(ctx context.Context, topics []string, institutionID string, limit int, offset int) ([]PublicProfile, error)
 	FindMentees(ctx context.Context, topics []string, limit int, offset int) ([]PublicProfile, error)
 	SearchUsersByTopic(ctx context.Context, topic string, isMentor bool, limit int, offset int) ([]PublicProfile, error)
 	GenerateDisplayName(ctx context.Context, baseName string) (string, error)
@@ -31,14 +154,63 @@ type IUserUsecase interface {
 
 // User Infrastructure interfaces
 type IJWTService interface {
-	GenerateToken(userID, username, role string) (TokenResult, error)
+	GenerateToken(subject TokenSubject) (TokenResult, error)
 	ValidateToken(tokenString string) (map[string]interface{}, error)
+	PublicKeys() JWKSet
+}
+
+// IRevocationStore rejects access tokens before they expire, either one token
+// at a time by its jti or every token of a user issued before a cutoff.
+type IRevocationStore interface {
+	RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error
+	RevokeUserTokens(ctx context.Context, userID string, issuedBefore time.Time) error
+	IsRevoked(ctx context.Context, tokenID, userID string, issuedAt time.Time) (bool, error)
+}
+
+// IExportStore keeps data export archives until they expire
+type IExportStore interface {
+	Save(ctx context.Context, name string, data []byte) error
+	Open(ctx context.Context, name string) (io.ReadCloser, error)
+	Delete(ctx context.Context, name string) error
+}
+
+// IOIDCProvider is one OpenID Connect identity provider. Exchange must check
+// the ID token's signature, issuer, audience, expiry and nonce.
+type IOIDCProvider interface {
+	Name() string
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (OIDCIdentity, error)
+}
+
+// IURLSigner signs expiring links so they work without a session
+type IURLSigner interface {
+	Sign(resource string, expiresAt time.Time) string
+	Verify(resource string, expiresAt time.Time, signature string) bool
+}
+
+// IPasswordPolicy decides whether a new password is acceptable for a user.
+// For a user who is registering, user.ID is zero.
+type IPasswordPolicy interface {
+	Validate(password string, user User) error
+	// HistorySize is how many recent passwords, the current one included,
+	// may not be reused
+	HistorySize() int
+}
+
+// ITOTPService implements RFC 6238 time-based one-time passwords
+type ITOTPService interface {
+	GenerateSecret() (string, error)
+	ProvisioningURI(secret, accountName string) string
+	// ValidateCode returns the time step the code matched
+	ValidateCode(secret, code string, at time.Time) (int64, bool)
 }
 
 // PasswordService interface defines password operations
 type IPasswordService interface {
 	HashPassword(password string) (string, error)
 	ComparePassword(hashedPassword, password string) error
+	// NeedsRehash reports a hash made with outdated algorithm or parameters
+	NeedsRehash(hashedPassword string) bool
 }
 
 type ICloudinaryService interface {
@@ -1,17 +1,22 @@
 package domain
 
 import (
-	"math/rand"
-	"time"
+	"crypto/rand"
 )
 
+// GenerateOTP returns a numeric code of the given length drawn from crypto/rand
 func GenerateOTP(length int) string {
-	const charset = "0123456789"
-	r := rand.New(rand.NewSource(time.Now().UnixNano())) // local generator
-
-	otp := make([]byte, length)
-	for i := range otp {
-		otp[i] = charset[r.Intn(len(charset))]
+	otp := make([]byte, 0, length)
+	buf := make([]byte, length)
+	for len(otp) < length {
+		rand.Read(buf) // never returns an error since Go 1.24
+		for _, b := range buf {
+			// 250 is the largest multiple of 10 below 256; skipping bytes above it keeps digits uniform
+			if b >= 250 || len(otp) == length {
+				continue
+			}
+			otp = append(otp, '0'+b%10)
+		}
 	}
 	return string(otp)
-}
\ No newline at end of file
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// GenerateSecureToken returns a URL-safe random token built from n bytes of
+// crypto/rand output.
+func GenerateSecureToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken returns the hex SHA-256 of a high-entropy token, for storing
+// tokens that must be looked up by value.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// TokenMatches reports, in constant time, whether token hashes to hash
+func TokenMatches(hash, token string) bool {
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(HashToken(token))) == 1
+}
+
+// HashCode returns the hex HMAC-SHA256 of a short code, such as a 6-digit
+// OTP. A plain hash of a code from so small a space is reversed by trying
+// every value; keying it means a leaked hash is useless without the key.
+func HashCode(key []byte, code string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// CodeMatches reports, in constant time, whether code hashes to hash under key
+func CodeMatches(key []byte, hash, code string) bool {
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(HashCode(key, code))) == 1
+}
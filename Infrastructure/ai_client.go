@@ -0,0 +1,88 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// GeminiClient calls Gemini's generateContent endpoint, e.g.
+// https://generativelanguage.googleapis.com/v1beta/models/gemini-1.5-flash:generateContent
+type GeminiClient struct {
+	apiKey   string
+	endpoint string
+	client   *http.Client
+}
+
+func NewGeminiClient(apiKey, endpoint string) *GeminiClient {
+	return &GeminiClient{apiKey: apiKey, endpoint: endpoint, client: &http.Client{Timeout: 30 * time.Second}}
+}
+
+type geminiContent struct {
+	Parts []struct {
+		Text string `json:"text"`
+	} `json:"parts"`
+}
+
+func (g *GeminiClient) Generate(ctx context.Context, prompt string) (string, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"contents": []map[string]interface{}{
+			{"parts": []map[string]string{{"text": prompt}}},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.endpoint+"?key="+url.QueryEscape(g.apiKey), bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("gemini: %s", resp.Status)
+	}
+
+	var result struct {
+		Candidates []struct {
+			Content geminiContent `json:"content"`
+		} `json:"candidates"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("gemini: %w", err)
+	}
+	if len(result.Candidates) == 0 {
+		return "", errors.New("gemini: no candidates in response")
+	}
+	var text strings.Builder
+	for _, part := range result.Candidates[0].Content.Parts {
+		text.WriteString(part.Text)
+	}
+	return text.String(), nil
+}
+
+// StubAIClient answers every prompt with canned text, for local development
+type StubAIClient struct{}
+
+func NewStubAIClient() *StubAIClient {
+	return &StubAIClient{}
+}
+
+func (StubAIClient) Generate(ctx context.Context, prompt string) (string, error) {
+	if r := []rune(prompt); len(r) > 80 {
+		prompt = string(r[:80]) + "..."
+	}
+	return "[local AI stub] This is a placeholder answer to: " + prompt, nil
+}
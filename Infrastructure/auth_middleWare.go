@@ -1,62 +1,233 @@
 package infrastructure
 
 import (
-    "net/http"
-    "strings"
-    domain "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
 
-
-    "github.com/gin-gonic/gin"
+	domain "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	utils "github.com/Amaankaa/Blog-Starter-Project/Domain/utils"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-
 type AuthMiddleware struct {
-    jwtService domain.IJWTService
+	jwtService  domain.IJWTService
+	tokenRepo   domain.ITokenRepository
+	revocations domain.IRevocationStore
+
+	requireAdminMFA bool
+	users           domain.IUserRepository
+
+	accessTokens domain.IPersonalAccessTokenRepository
+	// tokenRoutes maps "METHOD /route" to the scope a personal access token
+	// needs there; routes missing from it refuse personal access tokens
+	tokenRoutes map[string]string
 }
 
+// NewAuthMiddleware builds the auth middleware. When tokenRepo is set, access
+// tokens carrying a session ID are only accepted while that session is active;
+// when revocations is set, revoked tokens are rejected before they expire.
+func NewAuthMiddleware(jwtService domain.IJWTService, tokenRepo domain.ITokenRepository, revocations domain.IRevocationStore) *AuthMiddleware {
+	return &AuthMiddleware{
+		jwtService:  jwtService,
+		tokenRepo:   tokenRepo,
+		revocations: revocations,
+	}
+}
 
-func NewAuthMiddleware(jwtService domain.IJWTService) *AuthMiddleware {
-    return &AuthMiddleware{
-        jwtService: jwtService,
-    }
+// RequireAdminMFA makes RequirePermission reject admins whose session did not
+// pass a second factor.
+func (am *AuthMiddleware) RequireAdminMFA() *AuthMiddleware {
+	am.requireAdminMFA = true
+	return am
 }
 
+// RejectSuspended turns away suspended or banned users signing in with a
+// personal access token, which is looked up on every request anyway. JWTs are
+// not checked here: suspending a user ends their sessions and revokes their
+// access tokens.
+func (am *AuthMiddleware) RejectSuspended(users domain.IUserRepository) *AuthMiddleware {
+	am.users = users
+	return am
+}
+
+// AcceptPersonalAccessTokens lets "ssp_" tokens through alongside JWTs, but
+// only on routes opened to their scopes with AllowTokenScope.
+func (am *AuthMiddleware) AcceptPersonalAccessTokens(repo domain.IPersonalAccessTokenRepository) *AuthMiddleware {
+	am.accessTokens = repo
+	return am
+}
+
+// AllowTokenScope opens routes, written as "METHOD /path/:param" the way they
+// are registered, to personal access tokens holding scope.
+func (am *AuthMiddleware) AllowTokenScope(scope string, routes ...string) *AuthMiddleware {
+	if am.tokenRoutes == nil {
+		am.tokenRoutes = make(map[string]string)
+	}
+	for _, route := range routes {
+		am.tokenRoutes[route] = scope
+	}
+	return am
+}
 
 func (am *AuthMiddleware) AuthMiddleware() gin.HandlerFunc {
-    return func(c *gin.Context) {
-        header := c.GetHeader("Authorization")
-	
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+
 		if header == "" || !strings.HasPrefix(header, "Bearer ") {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header missing or invalid"})
 			c.Abort()
 			return
 		}
 
-        tokenString := strings.TrimPrefix(header, "Bearer ")
-        claims, err := am.jwtService.ValidateToken(tokenString)
-        if err != nil {
-            c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-            c.Abort()
-            return
-        }
+		tokenString := strings.TrimPrefix(header, "Bearer ")
+		if am.accessTokens != nil && strings.HasPrefix(tokenString, domain.PersonalAccessTokenPrefix) {
+			am.authenticateAccessToken(c, tokenString)
+			return
+		}
+		claims, err := am.jwtService.ValidateToken(tokenString)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			c.Abort()
+			return
+		}
+		// Refresh tokens live far longer and are only good for /auth/refresh
+		if claims["typ"] != domain.TokenTypeAccess {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "not an access token"})
+			c.Abort()
+			return
+		}
 
+		userID, _ := claims["_id"].(string)
+		tokenID, _ := claims["jti"].(string)
+		if am.revocations != nil {
+			var issuedAt time.Time
+			if iat, ok := claims["iat"].(float64); ok {
+				issuedAt = time.Unix(int64(iat), 0)
+			}
+			revoked, err := am.revocations.IsRevoked(c.Request.Context(), tokenID, userID, issuedAt)
+			if err != nil || revoked {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "token has been revoked"})
+				c.Abort()
+				return
+			}
+		}
+
+		// Revoked sessions stop working immediately, not when the token expires
+		sessionID, _ := claims["sid"].(string)
+		if sessionID != "" && am.tokenRepo != nil {
+			sid, err := primitive.ObjectIDFromHex(sessionID)
+			if err == nil {
+				err = am.tokenRepo.TouchSession(c.Request.Context(), sid)
+			}
+			if err != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "session revoked or expired"})
+				c.Abort()
+				return
+			}
+		}
 
-        c.Set("user_id", claims["_id"])
-        c.Set("username", claims["username"])
-        c.Set("role", claims["role"])
-        c.Next()
-    }
+		c.Set("user_id", claims["_id"])
+		c.Set("username", claims["username"])
+		c.Set("role", claims["role"])
+		c.Set("permissions", tokenPermissions(claims))
+		c.Set("session_id", sessionID)
+		c.Set("token_id", tokenID)
+		c.Set("mfa", claims["mfa"] == true)
+		c.Next()
+	}
 }
 
+// accessTokenTouchInterval limits how often a token's last-used time is written
+const accessTokenTouchInterval = time.Minute
 
-func (am *AuthMiddleware) AdminOnly() gin.HandlerFunc {
-    return func(c *gin.Context) {
-        role, exists := c.Get("role")
-        if !exists || role != "admin" {
-            c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
-            c.Abort()
-            return
-        }
-        c.Next()
-    }
+// authenticateAccessToken handles requests signed with a personal access
+// token. Such requests carry no permissions, so admin routes stay closed.
+func (am *AuthMiddleware) authenticateAccessToken(c *gin.Context, raw string) {
+	scope, ok := am.tokenRoutes[c.Request.Method+" "+c.FullPath()]
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Personal access tokens cannot be used on this route"})
+		c.Abort()
+		return
+	}
+
+	ctx := c.Request.Context()
+	token, err := am.accessTokens.FindAccessTokenByHash(ctx, utils.HashToken(raw))
+	now := time.Now()
+	if err != nil || token.Expired(now) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired access token"})
+		c.Abort()
+		return
+	}
+	if !slices.Contains(token.Scopes, scope) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Missing scope: " + scope})
+		c.Abort()
+		return
+	}
+
+	userID := token.UserID.Hex()
+	var username string
+	if am.users != nil {
+		user, err := am.users.FindByID(ctx, userID)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
+			c.Abort()
+			return
+		}
+		if err := user.SuspensionError(now); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			c.Abort()
+			return
+		}
+		username = user.Username
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= accessTokenTouchInterval {
+		_ = am.accessTokens.TouchAccessToken(ctx, token.ID, now)
+	}
+
+	c.Set("user_id", userID)
+	c.Set("username", username)
+	c.Set("permissions", []string{})
+	c.Set("scopes", token.Scopes)
+	c.Set("access_token_id", token.ID.Hex())
+	c.Set("mfa", false)
+	c.Next()
+}
+
+// RequirePermission lets the request through only when the caller's token
+// grants permission
+func (am *AuthMiddleware) RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !slices.Contains(c.GetStringSlice("permissions"), permission) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Missing permission: " + permission})
+			c.Abort()
+			return
+		}
+		if am.requireAdminMFA && c.GetString("role") == domain.RoleAdmin && !c.GetBool("mfa") {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Admin accounts must sign in with two-factor authentication"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// tokenPermissions reads the "permissions" claim. Tokens issued before the
+// claim existed get the permissions of their role.
+func tokenPermissions(claims map[string]interface{}) []string {
+	raw, ok := claims["permissions"].([]interface{})
+	if !ok {
+		role, _ := claims["role"].(string)
+		return domain.PermissionsForRole(role)
+	}
+	permissions := make([]string, 0, len(raw))
+	for _, p := range raw {
+		if s, ok := p.(string); ok {
+			permissions = append(permissions, s)
+		}
+	}
+	return permissions
 }
@@ -0,0 +1,173 @@
+package infrastructure_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	utils "github.com/Amaankaa/Blog-Starter-Project/Domain/utils"
+	infrastructure "github.com/Amaankaa/Blog-Starter-Project/Infrastructure"
+	"github.com/Amaankaa/Blog-Starter-Project/mocks"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const testAccessToken = userpkg.PersonalAccessTokenPrefix + "secret"
+
+type accessTokenFixture struct {
+	tokens *mocks.IPersonalAccessTokenRepository
+	users  *mocks.IUserRepository
+	router *gin.Engine
+	token  userpkg.PersonalAccessToken
+	user   userpkg.User
+}
+
+// newAccessTokenFixture serves GET /posts/:id, open to posts:write tokens, and
+// GET /admin, which is not open to tokens at all
+func newAccessTokenFixture(t *testing.T) *accessTokenFixture {
+	gin.SetMode(gin.TestMode)
+	f := &accessTokenFixture{
+		tokens: new(mocks.IPersonalAccessTokenRepository),
+		users:  new(mocks.IUserRepository),
+	}
+	f.user = userpkg.User{ID: primitive.NewObjectID(), Username: "bob"}
+	f.token = userpkg.PersonalAccessToken{
+		ID:     primitive.NewObjectID(),
+		UserID: f.user.ID,
+		Scopes: []string{userpkg.ScopePostsWrite},
+	}
+	t.Cleanup(func() {
+		f.tokens.AssertExpectations(t)
+		f.users.AssertExpectations(t)
+	})
+
+	am := infrastructure.NewAuthMiddleware(new(mocks.IJWTService), nil, nil).
+		RejectSuspended(f.users).
+		AcceptPersonalAccessTokens(f.tokens).
+		AllowTokenScope(userpkg.ScopePostsWrite, "GET /posts/:id")
+	ok := func(c *gin.Context) { c.String(http.StatusOK, c.GetString("user_id")) }
+
+	f.router = gin.New()
+	f.router.GET("/posts/:id", am.AuthMiddleware(), ok)
+	f.router.GET("/admin", am.AuthMiddleware(), ok)
+	return f
+}
+
+func (f *accessTokenFixture) get(path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("Authorization", "Bearer "+testAccessToken)
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, req)
+	return w
+}
+
+func (f *accessTokenFixture) expectLookup() {
+	f.tokens.On("FindAccessTokenByHash", mock.Anything, utils.HashToken(testAccessToken)).Return(f.token, nil)
+}
+
+func TestAuthMiddleware_AccessTokenSignsIn(t *testing.T) {
+	f := newAccessTokenFixture(t)
+	f.expectLookup()
+	f.users.On("FindByID", mock.Anything, f.user.ID.Hex()).Return(f.user, nil)
+	f.tokens.On("TouchAccessToken", mock.Anything, f.token.ID, mock.AnythingOfType("time.Time")).Return(nil)
+
+	w := f.get("/posts/1")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, f.user.ID.Hex(), w.Body.String())
+}
+
+func TestAuthMiddleware_AccessTokenRefusedOnClosedRoute(t *testing.T) {
+	f := newAccessTokenFixture(t)
+
+	w := f.get("/admin")
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	f.tokens.AssertNotCalled(t, "FindAccessTokenByHash", mock.Anything, mock.Anything)
+}
+
+func TestAuthMiddleware_AccessTokenMissingScope(t *testing.T) {
+	f := newAccessTokenFixture(t)
+	f.token.Scopes = []string{userpkg.ScopeProfileRead}
+	f.expectLookup()
+
+	w := f.get("/posts/1")
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "Missing scope: "+userpkg.ScopePostsWrite)
+}
+
+func TestAuthMiddleware_AccessTokenExpired(t *testing.T) {
+	f := newAccessTokenFixture(t)
+	expired := time.Now().Add(-time.Minute)
+	f.token.ExpiresAt = &expired
+	f.expectLookup()
+
+	w := f.get("/posts/1")
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAuthMiddleware_AccessTokenUnknown(t *testing.T) {
+	f := newAccessTokenFixture(t)
+	f.tokens.On("FindAccessTokenByHash", mock.Anything, mock.Anything).
+		Return(userpkg.PersonalAccessToken{}, userpkg.ErrAccessTokenNotFound)
+
+	w := f.get("/posts/1")
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAuthMiddleware_AccessTokenOfSuspendedUser(t *testing.T) {
+	f := newAccessTokenFixture(t)
+	f.user.Suspension = &userpkg.Suspension{Reason: "spam"}
+	f.expectLookup()
+	f.users.On("FindByID", mock.Anything, f.user.ID.Hex()).Return(f.user, nil)
+
+	w := f.get("/posts/1")
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	f.tokens.AssertNotCalled(t, "TouchAccessToken", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthMiddleware_JWTSkipsUserLookup(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := new(mocks.IJWTService)
+	jwtService.On("ValidateToken", "jwt").Return(map[string]interface{}{"typ": userpkg.TokenTypeAccess, "_id": "user1", "role": "user"}, nil)
+	users := new(mocks.IUserRepository)
+	am := infrastructure.NewAuthMiddleware(jwtService, nil, nil).RejectSuspended(users)
+	router := gin.New()
+	router.GET("/me", am.AuthMiddleware(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer jwt")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	// Suspension revokes JWTs, so they need no lookup of their own
+	assert.Equal(t, http.StatusOK, w.Code)
+	users.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+}
+
+func TestAuthMiddleware_RejectsRefreshToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := new(mocks.IJWTService)
+	jwtService.On("ValidateToken", "refresh").Return(map[string]interface{}{"typ": userpkg.TokenTypeRefresh, "_id": "user1"}, nil)
+	jwtService.On("ValidateToken", "untyped").Return(map[string]interface{}{"_id": "user1"}, nil)
+	am := infrastructure.NewAuthMiddleware(jwtService, nil, nil)
+	router := gin.New()
+	router.GET("/me", am.AuthMiddleware(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for _, token := range []string{"refresh", "untyped"} {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code, token)
+	}
+}
@@ -0,0 +1,32 @@
+package infrastructure
+
+import (
+	"os"
+	"strings"
+
+	auditpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/audit"
+	"github.com/gin-gonic/gin"
+)
+
+// TrustedProxiesFromEnv reads TRUSTED_PROXIES, a comma-separated list of the
+// IPs or CIDRs of the load balancers in front of the API. Only they may set
+// X-Forwarded-For; with none configured the client IP is the peer address,
+// so callers cannot choose their own IP for rate limits and the audit log.
+func TrustedProxiesFromEnv() []string {
+	var proxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
+// ClientIPContext copies the caller's IP into the request context, where the
+// audit log picks it up
+func ClientIPContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(auditpkg.WithClientIP(c.Request.Context(), c.ClientIP()))
+		c.Next()
+	}
+}
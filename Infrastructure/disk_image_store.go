@@ -0,0 +1,83 @@
+package infrastructure
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// imageExtensions maps the image types DiskImageStore accepts to the
+// extension they are saved with, so the static route serves the right type
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// DiskImageStore is a stand-in for Cloudinary in local development: images
+// are written under dir and served from baseURL by a static route.
+type DiskImageStore struct {
+	dir     string
+	baseURL string
+}
+
+func NewDiskImageStore(dir, baseURL string) (*DiskImageStore, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "profile_pictures"), 0o755); err != nil {
+		return nil, err
+	}
+	return &DiskImageStore{dir: dir, baseURL: strings.TrimRight(baseURL, "/")}, nil
+}
+
+// UploadImage stores file under a random name; filename is only kept as a
+// hint in the name, like Cloudinary's public ID
+func (s *DiskImageStore) UploadImage(ctx context.Context, file multipart.File, filename string) (string, error) {
+	r := bufio.NewReader(file)
+	head, err := r.Peek(512)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	ext, ok := imageExtensions[http.DetectContentType(head)]
+	if !ok {
+		return "", errors.New("unsupported image type")
+	}
+
+	stem := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+	name := randomHex(8) + "-" + sanitizeFileStem(stem) + ext
+	out, err := os.OpenFile(filepath.Join(s.dir, "profile_pictures", name), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		return "", err
+	}
+	if err := out.Close(); err != nil {
+		return "", err
+	}
+	return s.baseURL + "/profile_pictures/" + name, nil
+}
+
+// sanitizeFileStem keeps letters, digits, dashes and underscores
+func sanitizeFileStem(stem string) string {
+	clean := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		}
+		return -1
+	}, stem)
+	if len(clean) > 40 {
+		clean = clean[:40]
+	}
+	if clean == "" {
+		clean = "image"
+	}
+	return clean
+}
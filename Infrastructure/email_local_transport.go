@@ -0,0 +1,167 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+)
+
+// FileEmailTransport saves every email as an .eml file in a directory instead
+// of sending it, for development. Any mail client can open the files, and
+// the transport doubles as the IEmailInbox behind the dev inbox endpoints.
+type FileEmailTransport struct {
+	dir  string
+	from mail.Address
+}
+
+func NewFileEmailTransport(dir string, from mail.Address) (*FileEmailTransport, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &FileEmailTransport{dir: dir, from: from}, nil
+}
+
+func (f *FileEmailTransport) Name() string { return "file" }
+
+func (f *FileEmailTransport) Deliver(ctx context.Context, to string, email services.RenderedEmail) error {
+	now := time.Now()
+	msg, err := buildMIMEMessage(f.from, to, email, now)
+	if err != nil {
+		return err
+	}
+	// Names sort in the order the emails were sent
+	name := now.UTC().Format("20060102T150405.000000000") + "-" + randomHex(4) + ".eml"
+	return os.WriteFile(filepath.Join(f.dir, name), msg, 0o600)
+}
+
+// List reads the headers of every saved email, newest first
+func (f *FileEmailTransport) List(ctx context.Context) ([]services.InboxEmail, error) {
+	files, err := os.ReadDir(f.dir)
+	if err != nil {
+		return nil, err
+	}
+	emails := []services.InboxEmail{}
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".eml" {
+			continue
+		}
+		email, _, err := f.read(strings.TrimSuffix(file.Name(), ".eml"), false)
+		if err != nil {
+			continue // not ours, or half written
+		}
+		emails = append(emails, email)
+	}
+	sort.Slice(emails, func(i, j int) bool { return emails[i].ID > emails[j].ID })
+	return emails, nil
+}
+
+func (f *FileEmailTransport) Get(ctx context.Context, id string) (services.InboxEmail, []byte, error) {
+	return f.read(id, true)
+}
+
+// Clear deletes every saved email
+func (f *FileEmailTransport) Clear(ctx context.Context) error {
+	files, err := os.ReadDir(f.dir)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if !file.IsDir() && filepath.Ext(file.Name()) == ".eml" {
+			if err := os.Remove(filepath.Join(f.dir, file.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (f *FileEmailTransport) read(id string, withBodies bool) (services.InboxEmail, []byte, error) {
+	if id == "" || filepath.Base(id) != id {
+		return services.InboxEmail{}, nil, services.ErrInboxEmailNotFound
+	}
+	raw, err := os.ReadFile(filepath.Join(f.dir, id+".eml"))
+	if errors.Is(err, os.ErrNotExist) {
+		return services.InboxEmail{}, nil, services.ErrInboxEmailNotFound
+	}
+	if err != nil {
+		return services.InboxEmail{}, nil, err
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return services.InboxEmail{}, nil, err
+	}
+
+	email := services.InboxEmail{ID: id}
+	if to, err := mail.ParseAddress(msg.Header.Get("To")); err == nil {
+		email.To = to.Address
+	}
+	email.Subject, _ = new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	email.Date, _ = msg.Header.Date()
+	if !withBodies {
+		return email, raw, nil
+	}
+
+	_, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		return services.InboxEmail{}, nil, err
+	}
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return services.InboxEmail{}, nil, err
+		}
+		body, err := io.ReadAll(part)
+		if err != nil {
+			return services.InboxEmail{}, nil, err
+		}
+		mediaType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		switch mediaType {
+		case "text/plain":
+			email.Text = strings.ReplaceAll(string(body), "\r\n", "\n")
+		case "text/html":
+			email.HTML = string(body)
+		}
+	}
+	return email, raw, nil
+}
+
+// ConsoleEmailTransport prints every email's plain-text part instead of
+// sending it, for development
+type ConsoleEmailTransport struct {
+	out io.Writer
+	mu  sync.Mutex // keeps emails from concurrent workers apart
+}
+
+// NewConsoleEmailTransport writes to out, or to stdout when out is nil
+func NewConsoleEmailTransport(out io.Writer) *ConsoleEmailTransport {
+	if out == nil {
+		out = os.Stdout
+	}
+	return &ConsoleEmailTransport{out: out}
+}
+
+func (c *ConsoleEmailTransport) Name() string { return "console" }
+
+func (c *ConsoleEmailTransport) Deliver(ctx context.Context, to string, email services.RenderedEmail) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err := fmt.Fprintf(c.out, "----- email to %s -----\nSubject: %s\n\n%s----- end of email -----\n", to, email.Subject, email.Text)
+	return err
+}
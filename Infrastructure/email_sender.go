@@ -2,37 +2,49 @@ package infrastructure
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"errors"
+	"fmt"
 	"net/http"
-	"os"
+	"net/mail"
+	"time"
+
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
 )
 
-type BrevoEmailSender struct{}
+const brevoEndpoint = "https://api.brevo.com/v3/smtp/email"
 
-func NewBrevoEmailSender() *BrevoEmailSender {
-	return &BrevoEmailSender{}
+// BrevoTransport delivers emails through Brevo's transactional email API
+type BrevoTransport struct {
+	apiKey   string
+	from     mail.Address
+	endpoint string
+	client   *http.Client
 }
 
-func (b *BrevoEmailSender) SendEmail(to, subject, content string) error {
-	apiKey := os.Getenv("BREVO_API_KEY")
-	fromEmail := os.Getenv("FROM_EMAIL")
-	fromName := os.Getenv("FROM_NAME")
-
-	if apiKey == "" || fromEmail == "" || fromName == "" {
-		return errors.New("missing Brevo config in environment")
+func NewBrevoTransport(apiKey string, from mail.Address) *BrevoTransport {
+	return &BrevoTransport{
+		apiKey:   apiKey,
+		from:     from,
+		endpoint: brevoEndpoint,
+		client:   &http.Client{Timeout: 30 * time.Second},
 	}
+}
 
+func (b *BrevoTransport) Name() string { return "brevo" }
+
+func (b *BrevoTransport) Deliver(ctx context.Context, to string, email services.RenderedEmail) error {
 	payload := map[string]interface{}{
 		"sender": map[string]string{
-			"name":  fromName,
-			"email": fromEmail,
+			"name":  b.from.Name,
+			"email": b.from.Address,
 		},
 		"to": []map[string]string{
 			{"email": to},
 		},
-		"subject":     subject,
-		"htmlContent": "<p>" + content + "</p>",
+		"subject":     email.Subject,
+		"htmlContent": email.HTML,
+		"textContent": email.Text,
 	}
 
 	body, err := json.Marshal(payload)
@@ -40,22 +52,26 @@ func (b *BrevoEmailSender) SendEmail(to, subject, content string) error {
 		return err
 	}
 
-	req, err := http.NewRequest("POST", "https://api.brevo.com/v3/smtp/email", bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.endpoint, bytes.NewBuffer(body))
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("api-key", apiKey)
+	req.Header.Set("api-key", b.apiKey)
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := b.client.Do(req)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
+	// 400 is Brevo refusing the email itself, e.g. an invalid recipient;
+	// anything else may go through on a later attempt
+	if resp.StatusCode == http.StatusBadRequest {
+		return fmt.Errorf("%w: brevo: %s", services.ErrEmailRejected, resp.Status)
+	}
 	if resp.StatusCode >= 400 {
-		return errors.New("failed to send email: " + resp.Status)
+		return fmt.Errorf("brevo: %s", resp.Status)
 	}
-
 	return nil
 }
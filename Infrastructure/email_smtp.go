@@ -0,0 +1,130 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"time"
+
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+)
+
+// SMTP connection security
+const (
+	SMTPStartTLS = "starttls" // upgrade a plain connection, required unless the server is local
+	SMTPTLS      = "tls"      // implicit TLS, usually port 465
+	SMTPPlain    = "none"     // no encryption, for a local relay or a test server
+)
+
+// SMTPConfig points SMTPTransport at a mail server
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string // no authentication when empty
+	Password string
+	Security string // SMTPStartTLS, SMTPTLS or SMTPPlain
+	From     mail.Address
+}
+
+// SMTPTransport delivers emails to an SMTP server, one connection per email
+type SMTPTransport struct {
+	cfg SMTPConfig
+}
+
+func NewSMTPTransport(cfg SMTPConfig) (*SMTPTransport, error) {
+	if cfg.Host == "" {
+		return nil, errors.New("smtp: host is required")
+	}
+	if cfg.Port == 0 {
+		cfg.Port = 587
+	}
+	switch cfg.Security {
+	case "":
+		cfg.Security = SMTPStartTLS
+	case SMTPStartTLS, SMTPTLS, SMTPPlain:
+	default:
+		return nil, fmt.Errorf("smtp: invalid security %q", cfg.Security)
+	}
+	return &SMTPTransport{cfg: cfg}, nil
+}
+
+func (s *SMTPTransport) Name() string { return "smtp" }
+
+func (s *SMTPTransport) Deliver(ctx context.Context, to string, email services.RenderedEmail) error {
+	msg, err := buildMIMEMessage(s.cfg.From, to, email, time.Now())
+	if err != nil {
+		return err
+	}
+
+	client, err := s.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if s.cfg.Security == SMTPStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("smtp: server does not support STARTTLS")
+		}
+		if err := client.StartTLS(&tls.Config{ServerName: s.cfg.Host}); err != nil {
+			return fmt.Errorf("smtp: starttls: %w", err)
+		}
+	}
+	if s.cfg.Username != "" {
+		auth := smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("smtp: auth: %w", err)
+		}
+	}
+
+	if err := client.Mail(s.cfg.From.Address); err != nil {
+		return fmt.Errorf("smtp: mail from: %w", err)
+	}
+	if err := client.Rcpt(to); err != nil {
+		// A permanent (5xx) answer to RCPT means the mailbox will never accept it
+		var reply *textproto.Error
+		if errors.As(err, &reply) && reply.Code >= 500 {
+			return fmt.Errorf("%w: smtp: rcpt to: %v", services.ErrEmailRejected, err)
+		}
+		return fmt.Errorf("smtp: rcpt to: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp: data: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("smtp: data: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp: data: %w", err)
+	}
+	return client.Quit()
+}
+
+// dial connects and greets the server; the connection is bound to ctx's deadline
+func (s *SMTPTransport) dial(ctx context.Context) (*smtp.Client, error) {
+	addr := net.JoinHostPort(s.cfg.Host, fmt.Sprint(s.cfg.Port))
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("smtp: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+	if s.cfg.Security == SMTPTLS {
+		conn = tls.Client(conn, &tls.Config{ServerName: s.cfg.Host})
+	}
+
+	client, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("smtp: %w", err)
+	}
+	return client, nil
+}
@@ -0,0 +1,315 @@
+package infrastructure
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+)
+
+//go:embed templates/email
+var emailTemplateFiles embed.FS
+
+// DefaultEmailLocale is used for recipients without a locale, and for any
+// string a locale has not translated
+const DefaultEmailLocale = "en"
+
+const emailDateLayout = "2006-01-02 15:04 UTC"
+
+// EmailTemplates renders the emails listed in services.EmailTemplates.
+// templates/email/<id>.html and <id>.txt lay each one out inside the shared
+// layout; the wording comes from templates/email/locales/<locale>.json, where
+// every string is itself a template over the email's data. Everything is
+// parsed and test-rendered in every locale at startup.
+type EmailTemplates struct {
+	html     map[services.EmailTemplate]*htmltemplate.Template
+	text     map[services.EmailTemplate]*texttemplate.Template
+	catalogs map[string]map[string]*texttemplate.Template
+}
+
+func NewEmailTemplates() (*EmailTemplates, error) {
+	e := &EmailTemplates{
+		html:     make(map[services.EmailTemplate]*htmltemplate.Template, len(services.EmailTemplates)),
+		text:     make(map[services.EmailTemplate]*texttemplate.Template, len(services.EmailTemplates)),
+		catalogs: map[string]map[string]*texttemplate.Template{},
+	}
+
+	if err := e.loadCatalogs(); err != nil {
+		return nil, err
+	}
+
+	// t, locale, subject and link are bound per render
+	funcs := emailFuncs()
+	funcs["t"] = func(string) (string, error) { return "", nil }
+	funcs["locale"] = func() string { return "" }
+	funcs["subject"] = func() string { return "" }
+	funcs["link"] = emailLink
+	for _, id := range services.EmailTemplates {
+		dir := "templates/email/"
+		h, err := htmltemplate.New(string(id)).Funcs(funcs).Option("missingkey=error").
+			ParseFS(emailTemplateFiles, dir+"layout.html", dir+string(id)+".html")
+		if err != nil {
+			return nil, fmt.Errorf("email template %s: %w", id, err)
+		}
+		t, err := texttemplate.New(string(id)).Funcs(funcs).Option("missingkey=error").
+			ParseFS(emailTemplateFiles, dir+"layout.txt", dir+string(id)+".txt")
+		if err != nil {
+			return nil, fmt.Errorf("email template %s: %w", id, err)
+		}
+		e.html[id] = h
+		e.text[id] = t
+	}
+
+	for _, id := range services.EmailTemplates {
+		data, _ := e.SampleData(id)
+		for _, locale := range e.Locales() {
+			if _, err := e.Render(id, locale, data); err != nil {
+				return nil, fmt.Errorf("email template %s (%s): %w", id, locale, err)
+			}
+		}
+	}
+	return e, nil
+}
+
+func (e *EmailTemplates) loadCatalogs() error {
+	files, err := emailTemplateFiles.ReadDir("templates/email/locales")
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if path.Ext(f.Name()) != ".json" {
+			continue
+		}
+		raw, err := emailTemplateFiles.ReadFile("templates/email/locales/" + f.Name())
+		if err != nil {
+			return err
+		}
+		var entries map[string]string
+		if err := json.Unmarshal(raw, &entries); err != nil {
+			return fmt.Errorf("email locale %s: %w", f.Name(), err)
+		}
+		locale := normalizeLocale(strings.TrimSuffix(f.Name(), ".json"))
+		catalog := make(map[string]*texttemplate.Template, len(entries))
+		for key, value := range entries {
+			t, err := texttemplate.New(key).Funcs(emailFuncs()).Option("missingkey=error").Parse(value)
+			if err != nil {
+				return fmt.Errorf("email locale %s, %s: %w", locale, key, err)
+			}
+			catalog[key] = t
+		}
+		e.catalogs[locale] = catalog
+	}
+
+	base, ok := e.catalogs[DefaultEmailLocale]
+	if !ok {
+		return errors.New("email locale " + DefaultEmailLocale + " is missing")
+	}
+	// A key only a translation has is a typo; it would never be used
+	for locale, catalog := range e.catalogs {
+		for key := range catalog {
+			if _, ok := base[key]; !ok {
+				return fmt.Errorf("email locale %s: unknown key %s", locale, key)
+			}
+		}
+	}
+	return nil
+}
+
+// Render fills template in locale, falling back from a regional locale
+// ("fr-CA") to its language and then to DefaultEmailLocale
+func (e *EmailTemplates) Render(template services.EmailTemplate, locale string, data services.EmailData) (services.RenderedEmail, error) {
+	h, ok := e.html[template]
+	if !ok {
+		return services.RenderedEmail{}, errors.New("unknown email template: " + string(template))
+	}
+	locale = e.resolveLocale(locale)
+
+	// Name is optional everywhere; every other key must be supplied
+	values := map[string]interface{}{"Name": ""}
+	for k, v := range data {
+		values[k] = v
+	}
+	translate := func(key string) (string, error) {
+		entry, ok := e.catalogs[locale][key]
+		if !ok {
+			if entry, ok = e.catalogs[DefaultEmailLocale][key]; !ok {
+				return "", errors.New("missing translation: " + key)
+			}
+		}
+		var buf bytes.Buffer
+		if err := entry.Execute(&buf, values); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	}
+
+	subject, err := translate(string(template) + ".subject")
+	if err != nil {
+		return services.RenderedEmail{}, err
+	}
+	bound := map[string]interface{}{
+		"t":       translate,
+		"locale":  func() string { return locale },
+		"subject": func() string { return subject },
+	}
+
+	// Clones keep the parsed originals unexecuted, so every render can bind its own t
+	hc, err := h.Clone()
+	if err != nil {
+		return services.RenderedEmail{}, err
+	}
+	var html bytes.Buffer
+	if err := hc.Funcs(bound).ExecuteTemplate(&html, "layout", values); err != nil {
+		return services.RenderedEmail{}, err
+	}
+	tc, err := e.text[template].Clone()
+	if err != nil {
+		return services.RenderedEmail{}, err
+	}
+	var text bytes.Buffer
+	if err := tc.Funcs(bound).ExecuteTemplate(&text, "layout", values); err != nil {
+		return services.RenderedEmail{}, err
+	}
+
+	return services.RenderedEmail{
+		Locale:  locale,
+		Subject: subject,
+		HTML:    html.String(),
+		Text:    tidyEmailText(text.String()),
+	}, nil
+}
+
+// Locales lists the locales with a translation catalog
+func (e *EmailTemplates) Locales() []string {
+	locales := make([]string, 0, len(e.catalogs))
+	for locale := range e.catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+func (e *EmailTemplates) resolveLocale(locale string) string {
+	locale = normalizeLocale(locale)
+	if _, ok := e.catalogs[locale]; ok {
+		return locale
+	}
+	if i := strings.IndexByte(locale, '-'); i > 0 {
+		if _, ok := e.catalogs[locale[:i]]; ok {
+			return locale[:i]
+		}
+	}
+	return DefaultEmailLocale
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// SampleData is realistic data for previewing template
+func (e *EmailTemplates) SampleData(template services.EmailTemplate) (services.EmailData, bool) {
+	now := time.Now()
+	later := now.Add(72 * time.Hour)
+	data := services.EmailData{"Name": "Abebe"}
+	switch template {
+	case services.TemplateVerification, services.TemplatePasswordReset:
+		data["Code"] = "482913"
+		data["ExpiresInMinutes"] = 10
+	case services.TemplatePasswordChanged:
+		data["SessionsEnded"] = true
+	case services.TemplateAccountLocked:
+		data["Failures"] = 10
+		data["Until"] = now.Add(15 * time.Minute)
+	case services.TemplateSignInLink:
+		data["Link"] = "https://sharespace.example/login/magic?token=preview"
+		data["ExpiresInMinutes"] = 10
+	case services.TemplateSignInMethodAdded:
+		data["Provider"] = "google"
+	case services.TemplateEmailChangeCode, services.TemplateReauthCode:
+		data["Code"] = "482913"
+		data["ExpiresInMinutes"] = 30
+	case services.TemplateEmailChangePending:
+		data["NewEmail"] = "abebe.new@example.com"
+		data["Code"] = "731046"
+		data["CancelURL"] = "https://sharespace.example/account/email/cancel?signature=preview"
+		data["CancelBefore"] = now.Add(30 * time.Minute)
+	case services.TemplateEmailChanged:
+		data["NewEmail"] = "abebe.new@example.com"
+	case services.TemplateStudentEmailCode:
+		data["Code"] = "482913"
+		data["Institution"] = "Addis Ababa University"
+		data["ExpiresInMinutes"] = 30
+	case services.TemplateDeletionScheduled:
+		data["ScheduledFor"] = now.Add(30 * 24 * time.Hour)
+	case services.TemplateDataExportReady:
+		data["AvailableUntil"] = later
+	case services.TemplateAccountSuspended:
+		data["Reason"] = "Repeated spam in comments"
+		data["Until"] = &later
+	case services.TemplateMentorshipRequest:
+		data["MenteeName"] = "Selam"
+		data["Topics"] = []string{"Career Guidance", "Technology Skills"}
+		data["Message"] = "I would like to learn from your experience."
+	case services.TemplateMentorshipResponse:
+		data["MentorName"] = "Dawit"
+		data["Accepted"] = true
+	case services.TemplateDigest:
+		data["Since"] = now.Add(-7 * 24 * time.Hour)
+		data["Items"] = []services.DigestItem{
+			{Title: "Coping with exam stress", URL: "https://sharespace.example/posts/1"},
+			{Title: "Scholarship application checklist", URL: "https://sharespace.example/resources/2"},
+		}
+	case services.TemplateDeletionCancelled, services.TemplateMFAEnabled, services.TemplateMFADisabled,
+		services.TemplateMFAReset, services.TemplateRecoveryCodeUsed:
+	default:
+		return nil, false
+	}
+	return data, true
+}
+
+// emailFuncs are available to templates and translations alike
+func emailFuncs() map[string]interface{} {
+	return map[string]interface{}{
+		"date": emailDate,
+		"join": strings.Join,
+	}
+}
+
+func emailDate(v interface{}) (string, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t.UTC().Format(emailDateLayout), nil
+	case *time.Time:
+		if t == nil {
+			return "", nil
+		}
+		return t.UTC().Format(emailDateLayout), nil
+	}
+	return "", fmt.Errorf("date: unexpected %T", v)
+}
+
+type emailButton struct {
+	URL   string
+	Label string
+}
+
+func emailLink(url, label string) emailButton {
+	return emailButton{URL: url, Label: label}
+}
+
+var blankLines = regexp.MustCompile(`\n{3,}`)
+
+// tidyEmailText drops the blank lines left behind by skipped sections
+func tidyEmailText(s string) string {
+	return strings.TrimSpace(blankLines.ReplaceAllString(s, "\n\n")) + "\n"
+}
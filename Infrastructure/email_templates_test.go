@@ -0,0 +1,73 @@
+package infrastructure_test
+
+import (
+	"testing"
+
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+	infrastructure "github.com/Amaankaa/Blog-Starter-Project/Infrastructure"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailTemplates_RenderEveryTemplateInEveryLocale(t *testing.T) {
+	e, err := infrastructure.NewEmailTemplates()
+	require.NoError(t, err)
+	assert.Contains(t, e.Locales(), "en")
+	assert.Contains(t, e.Locales(), "fr")
+
+	for _, id := range services.EmailTemplates {
+		data, ok := e.SampleData(id)
+		require.True(t, ok, id)
+		for _, locale := range e.Locales() {
+			email, err := e.Render(id, locale, data)
+			require.NoError(t, err, "%s/%s", id, locale)
+			assert.Equal(t, locale, email.Locale)
+			assert.NotEmpty(t, email.Subject)
+			assert.Contains(t, email.HTML, "<html lang=\""+locale+"\">")
+			assert.Contains(t, email.Text, "ShareSpace")
+		}
+	}
+}
+
+func TestEmailTemplates_LocaleFallback(t *testing.T) {
+	e, err := infrastructure.NewEmailTemplates()
+	require.NoError(t, err)
+	data := services.EmailData{"Code": "123456", "ExpiresInMinutes": 10}
+
+	regional, err := e.Render(services.TemplateVerification, "fr_CA", data)
+	require.NoError(t, err)
+	assert.Equal(t, "fr", regional.Locale)
+	assert.Equal(t, "Votre code de vérification ShareSpace", regional.Subject)
+
+	unknown, err := e.Render(services.TemplateVerification, "xx", data)
+	require.NoError(t, err)
+	assert.Equal(t, "en", unknown.Locale)
+	assert.Contains(t, unknown.Text, "123456")
+	assert.Contains(t, unknown.Text, "Hi,")
+}
+
+func TestEmailTemplates_EscapesDataInHTMLOnly(t *testing.T) {
+	e, err := infrastructure.NewEmailTemplates()
+	require.NoError(t, err)
+
+	email, err := e.Render(services.TemplateEmailChanged, "en", services.EmailData{
+		"Name":     "<b>Mallory</b>",
+		"NewEmail": "x@example.com",
+	})
+
+	require.NoError(t, err)
+	assert.NotContains(t, email.HTML, "<b>Mallory</b>")
+	assert.Contains(t, email.HTML, "&lt;b&gt;Mallory&lt;/b&gt;")
+	assert.Contains(t, email.Text, "Hi <b>Mallory</b>,")
+}
+
+func TestEmailTemplates_MissingDataIsAnError(t *testing.T) {
+	e, err := infrastructure.NewEmailTemplates()
+	require.NoError(t, err)
+
+	_, err = e.Render(services.TemplatePasswordReset, "en", services.EmailData{"Code": "123456"})
+	assert.Error(t, err)
+
+	_, err = e.Render("no-such-template", "en", nil)
+	assert.Error(t, err)
+}
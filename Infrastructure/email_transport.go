@@ -0,0 +1,74 @@
+package infrastructure
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"os"
+	"strconv"
+
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+)
+
+// Email transports selectable with EMAIL_TRANSPORT
+const (
+	EmailTransportBrevo   = "brevo"
+	EmailTransportSMTP    = "smtp"
+	EmailTransportFile    = "file"
+	EmailTransportConsole = "console"
+)
+
+// NewEmailTransportFromEnv builds the transport named by EMAIL_TRANSPORT,
+// Brevo by default. Every transport sends as FROM_NAME <FROM_EMAIL>; Brevo
+// also needs BREVO_API_KEY, SMTP reads SMTP_HOST, SMTP_PORT, SMTP_USERNAME,
+// SMTP_PASSWORD and SMTP_SECURITY, and the file transport writes to
+// EMAIL_FILE_DIR ("mail" by default).
+func NewEmailTransportFromEnv() (services.IEmailTransport, error) {
+	kind := os.Getenv("EMAIL_TRANSPORT")
+	if kind == "" {
+		kind = EmailTransportBrevo
+	}
+
+	from := mail.Address{Name: os.Getenv("FROM_NAME"), Address: os.Getenv("FROM_EMAIL")}
+	if from.Address == "" {
+		// Local transports never reach a real mailbox, so any sender will do
+		if kind != EmailTransportFile && kind != EmailTransportConsole {
+			return nil, errors.New("FROM_EMAIL not set in environment")
+		}
+		from = mail.Address{Name: "ShareSpace", Address: "no-reply@localhost"}
+	}
+
+	switch kind {
+	case EmailTransportBrevo:
+		apiKey := os.Getenv("BREVO_API_KEY")
+		if apiKey == "" {
+			return nil, errors.New("BREVO_API_KEY not set in environment")
+		}
+		return NewBrevoTransport(apiKey, from), nil
+	case EmailTransportSMTP:
+		cfg := SMTPConfig{
+			Host:     os.Getenv("SMTP_HOST"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			Security: os.Getenv("SMTP_SECURITY"),
+			From:     from,
+		}
+		if raw := os.Getenv("SMTP_PORT"); raw != "" {
+			port, err := strconv.Atoi(raw)
+			if err != nil || port <= 0 || port > 65535 {
+				return nil, fmt.Errorf("invalid SMTP_PORT: %q", raw)
+			}
+			cfg.Port = port
+		}
+		return NewSMTPTransport(cfg)
+	case EmailTransportFile:
+		dir := os.Getenv("EMAIL_FILE_DIR")
+		if dir == "" {
+			dir = "mail"
+		}
+		return NewFileEmailTransport(dir, from)
+	case EmailTransportConsole:
+		return NewConsoleEmailTransport(nil), nil
+	}
+	return nil, fmt.Errorf("invalid EMAIL_TRANSPORT: %q", kind)
+}
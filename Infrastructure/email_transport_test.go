@@ -0,0 +1,156 @@
+package infrastructure_test
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+	infrastructure "github.com/Amaankaa/Blog-Starter-Project/Infrastructure"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testEmail = services.RenderedEmail{
+	Locale:  "fr",
+	Subject: "Votre code de vérification",
+	HTML:    "<p>Code : <strong>123456</strong></p>",
+	Text:    "Code : 123456\n",
+}
+
+func TestFileEmailTransport_WritesMultipartEML(t *testing.T) {
+	dir := t.TempDir()
+	transport, err := infrastructure.NewFileEmailTransport(dir, mail.Address{Name: "ShareSpace", Address: "no-reply@sharespace.example"})
+	require.NoError(t, err)
+
+	require.NoError(t, transport.Deliver(context.Background(), "a@example.com", testEmail))
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.True(t, strings.HasSuffix(files[0].Name(), ".eml"))
+
+	raw, err := os.Open(filepath.Join(dir, files[0].Name()))
+	require.NoError(t, err)
+	defer raw.Close()
+	msg, err := mail.ReadMessage(raw)
+	require.NoError(t, err)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, testEmail.Subject, subject)
+	assert.Equal(t, "<a@example.com>", msg.Header.Get("To"))
+	assert.Equal(t, "fr", msg.Header.Get("Content-Language"))
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	var bodies []string
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		body, err := io.ReadAll(part) // NextPart decodes quoted-printable
+		require.NoError(t, err)
+		// Text parts travel with CRLF line endings
+		bodies = append(bodies, strings.ReplaceAll(string(body), "\r\n", "\n"))
+	}
+	assert.Equal(t, []string{testEmail.Text, testEmail.HTML}, bodies)
+}
+
+// fakeSMTPServer answers a single SMTP session, rejecting recipients in reject
+func fakeSMTPServer(t *testing.T, reject string) (port int, received chan string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+	received = make(chan string, 1)
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(s string) { _, _ = conn.Write([]byte(s + "\r\n")) }
+
+		reply("220 localhost ESMTP")
+		var data strings.Builder
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(cmd, "RCPT TO:") && strings.Contains(line, reject):
+				reply("550 5.1.1 no such user")
+			case cmd == "DATA":
+				reply("354 go ahead")
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil || l == ".\r\n" {
+						break
+					}
+					data.WriteString(l)
+				}
+				received <- data.String()
+				reply("250 queued")
+			case cmd == "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+	return ln.Addr().(*net.TCPAddr).Port, received
+}
+
+func newTestSMTPTransport(t *testing.T, port int) *infrastructure.SMTPTransport {
+	transport, err := infrastructure.NewSMTPTransport(infrastructure.SMTPConfig{
+		Host:     "127.0.0.1",
+		Port:     port,
+		Security: infrastructure.SMTPPlain,
+		From:     mail.Address{Name: "ShareSpace", Address: "no-reply@sharespace.example"},
+	})
+	require.NoError(t, err)
+	return transport
+}
+
+func TestSMTPTransport_Delivers(t *testing.T) {
+	port, received := fakeSMTPServer(t, "nobody@")
+	transport := newTestSMTPTransport(t, port)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, transport.Deliver(ctx, "a@example.com", testEmail))
+
+	msg := <-received
+	assert.Contains(t, msg, "To: <a@example.com>")
+	assert.Contains(t, msg, "multipart/alternative")
+}
+
+func TestSMTPTransport_RejectedRecipientIsPermanent(t *testing.T) {
+	port, _ := fakeSMTPServer(t, "nobody@")
+	transport := newTestSMTPTransport(t, port)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := transport.Deliver(ctx, "nobody@example.com", testEmail)
+	assert.ErrorIs(t, err, services.ErrEmailRejected)
+}
@@ -0,0 +1,396 @@
+package infrastructure
+
+import (
+	"bufio"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/mail"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	emailpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/email"
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+)
+
+//go:embed data/disposable-domains.txt
+var defaultDisposableDomains string
+
+// EmailVerdict is what a verification step concluded about a domain
+type EmailVerdict int
+
+const (
+	EmailUndecided EmailVerdict = iota // no objection; the next step decides
+	EmailAccepted                      // valid, the remaining steps are skipped
+	EmailRejected
+)
+
+// EmailCheck is one step of an EmailVerifierChain. Steps judge the domain of
+// an address, so their verdicts hold for every address at it.
+type EmailCheck interface {
+	Name() string
+	CheckDomain(ctx context.Context, domain string) (EmailVerdict, error)
+}
+
+// emailVerifyTimeout bounds a whole chain run; IsRealEmail has no context
+const emailVerifyTimeout = 10 * time.Second
+
+// EmailVerifierChain is the IEmailVerifier used at signup. It checks the
+// address's syntax, then runs its steps in order until one accepts or
+// rejects the domain; an address no step objects to is accepted. Each
+// step's verdict is cached per domain for the step's TTL. A step that fails
+// is logged and skipped, so a DNS or provider outage does not block signups.
+type EmailVerifierChain struct {
+	steps     []chainStep
+	remote    services.IEmailVerifier
+	remoteTTL time.Duration
+	cache     *verdictCache
+}
+
+type chainStep struct {
+	check EmailCheck
+	ttl   time.Duration
+}
+
+func NewEmailVerifierChain() *EmailVerifierChain {
+	return &EmailVerifierChain{cache: newVerdictCache(10000)}
+}
+
+// Then adds a step whose verdicts are cached for ttl
+func (c *EmailVerifierChain) Then(check EmailCheck, ttl time.Duration) *EmailVerifierChain {
+	c.steps = append(c.steps, chainStep{check: check, ttl: ttl})
+	return c
+}
+
+// ThenRemote asks verifier, a third-party service, about addresses every
+// other step let through. It checks the mailbox itself, so its verdicts are
+// cached per address rather than per domain, and only acceptances: an
+// accepted address is not sent to it again until ttl has passed.
+func (c *EmailVerifierChain) ThenRemote(verifier services.IEmailVerifier, ttl time.Duration) *EmailVerifierChain {
+	c.remote = verifier
+	c.remoteTTL = ttl
+	return c
+}
+
+func (c *EmailVerifierChain) IsRealEmail(email string) (bool, error) {
+	domain, ok := emailDomain(email)
+	if !ok {
+		return false, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), emailVerifyTimeout)
+	defer cancel()
+
+	for _, step := range c.steps {
+		key := step.check.Name() + ":" + domain
+		verdict, cached := c.cache.get(key)
+		if !cached {
+			var err error
+			verdict, err = step.check.CheckDomain(ctx, domain)
+			if err != nil {
+				log.Printf("email verification: %s check of %s failed: %v", step.check.Name(), domain, err)
+				continue
+			}
+			c.cache.set(key, verdict, step.ttl)
+		}
+		switch verdict {
+		case EmailAccepted:
+			return true, nil
+		case EmailRejected:
+			return false, nil
+		}
+	}
+
+	if c.remote == nil {
+		return true, nil
+	}
+	key := "remote:" + strings.ToLower(email)
+	if _, cached := c.cache.get(key); cached {
+		return true, nil
+	}
+	ok, err := c.remote.IsRealEmail(email)
+	if err != nil {
+		log.Printf("email verification: remote check of %s failed: %v", domain, err)
+		return true, nil
+	}
+	if ok {
+		c.cache.set(key, EmailAccepted, c.remoteTTL)
+	}
+	return ok, nil
+}
+
+// emailDomain returns the lowercased domain of a well-formed address. Bare
+// addresses only: no display names, comments or IP literals, a dotted
+// domain, and nothing under the reserved .invalid top-level domain.
+func emailDomain(email string) (string, bool) {
+	if len(email) > 254 {
+		return "", false
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return "", false
+	}
+	at := strings.LastIndexByte(email, '@')
+	if at < 1 || at > 64 {
+		return "", false
+	}
+	domain, ok := emailpkg.NormalizeDomain(email[at+1:])
+	if !ok || !strings.Contains(domain, ".") {
+		return "", false
+	}
+	tld := domain[strings.LastIndexByte(domain, '.')+1:]
+	if tld == "invalid" || strings.Trim(tld, "0123456789") == "" {
+		return "", false
+	}
+	return domain, true
+}
+
+// DisposableDomainCheck rejects throwaway email domains and their
+// subdomains. The list can be replaced while the server runs.
+type DisposableDomainCheck struct {
+	mu       sync.RWMutex
+	domains  map[string]struct{}
+	modified time.Time // of the file last loaded
+}
+
+// NewDisposableDomainCheck reads one domain per line; blank lines and lines
+// starting with # are skipped
+func NewDisposableDomainCheck(list io.Reader) (*DisposableDomainCheck, error) {
+	check := &DisposableDomainCheck{}
+	if err := check.Load(list); err != nil {
+		return nil, err
+	}
+	return check, nil
+}
+
+// NewDisposableDomainCheckFromEnv uses DISPOSABLE_DOMAINS_FILE, or the
+// bundled list when it is unset. A file is re-read whenever it changes.
+func NewDisposableDomainCheckFromEnv(ctx context.Context) (*DisposableDomainCheck, error) {
+	path := os.Getenv("DISPOSABLE_DOMAINS_FILE")
+	if path == "" {
+		return NewDisposableDomainCheck(strings.NewReader(defaultDisposableDomains))
+	}
+	check := &DisposableDomainCheck{}
+	if err := check.LoadFile(path); err != nil {
+		return nil, err
+	}
+	go check.Watch(ctx, path, time.Minute)
+	return check, nil
+}
+
+// Load replaces the list
+func (d *DisposableDomainCheck) Load(list io.Reader) error {
+	domains := map[string]struct{}{}
+	scanner := bufio.NewScanner(list)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if domain, ok := emailpkg.NormalizeDomain(line); ok {
+			domains[domain] = struct{}{}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read disposable domain list: %w", err)
+	}
+
+	d.mu.Lock()
+	d.domains = domains
+	d.mu.Unlock()
+	return nil
+}
+
+// LoadFile replaces the list with the one in path
+func (d *DisposableDomainCheck) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open disposable domain list: %w", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if err := d.Load(f); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	d.modified = info.ModTime()
+	d.mu.Unlock()
+	return nil
+}
+
+// Watch reloads the list from path whenever its modification time differs
+// from the last file loaded, until ctx is cancelled
+func (d *DisposableDomainCheck) Watch(ctx context.Context, path string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			info, err := os.Stat(path)
+			d.mu.RLock()
+			unchanged := err != nil || info.ModTime().Equal(d.modified)
+			d.mu.RUnlock()
+			if unchanged {
+				continue
+			}
+			if err := d.LoadFile(path); err != nil {
+				log.Printf("email verification: keeping the previous disposable domain list: %v", err)
+				continue
+			}
+			log.Printf("email verification: reloaded %d disposable domains from %s", d.Len(), path)
+		}
+	}
+}
+
+// Len is the number of listed domains
+func (d *DisposableDomainCheck) Len() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return len(d.domains)
+}
+
+func (d *DisposableDomainCheck) Name() string { return "disposable" }
+
+func (d *DisposableDomainCheck) CheckDomain(ctx context.Context, domain string) (EmailVerdict, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	for _, candidate := range emailpkg.DomainAndParents(domain) {
+		if _, listed := d.domains[candidate]; listed {
+			return EmailRejected, nil
+		}
+	}
+	return EmailUndecided, nil
+}
+
+// DomainRuleCheck applies the admin allow and deny lists. The most specific
+// rule wins, so allowing cs.example.edu overrides a deny for example.edu.
+type DomainRuleCheck struct {
+	rules emailpkg.IDomainRuleRepository
+}
+
+func NewDomainRuleCheck(rules emailpkg.IDomainRuleRepository) *DomainRuleCheck {
+	return &DomainRuleCheck{rules: rules}
+}
+
+func (r *DomainRuleCheck) Name() string { return "rules" }
+
+func (r *DomainRuleCheck) CheckDomain(ctx context.Context, domain string) (EmailVerdict, error) {
+	rule, found, err := r.rules.FindRule(ctx, emailpkg.DomainAndParents(domain))
+	if err != nil || !found {
+		return EmailUndecided, err
+	}
+	if rule.Action == emailpkg.DomainAllow {
+		return EmailAccepted, nil
+	}
+	return EmailRejected, nil
+}
+
+// MXResolver is the part of *net.Resolver MXCheck uses
+type MXResolver interface {
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// MXCheck rejects domains that cannot receive email: ones that do not
+// exist, publish a null MX (RFC 7505), or have neither MX nor address
+// records
+type MXCheck struct {
+	resolver MXResolver
+}
+
+// NewMXCheck looks records up through resolver, or the system resolver when
+// it is nil
+func NewMXCheck(resolver MXResolver) *MXCheck {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	return &MXCheck{resolver: resolver}
+}
+
+func (m *MXCheck) Name() string { return "mx" }
+
+func (m *MXCheck) CheckDomain(ctx context.Context, domain string) (EmailVerdict, error) {
+	records, err := m.resolver.LookupMX(ctx, domain)
+	if err != nil && !isDNSNotFound(err) {
+		return EmailUndecided, err
+	}
+	if len(records) == 1 && records[0].Host == "." {
+		return EmailRejected, nil
+	}
+	if len(records) > 0 {
+		return EmailUndecided, nil
+	}
+
+	// Without MX records mail goes to the domain's own address
+	hosts, err := m.resolver.LookupHost(ctx, domain)
+	if err != nil && !isDNSNotFound(err) {
+		return EmailUndecided, err
+	}
+	if len(hosts) == 0 {
+		return EmailRejected, nil
+	}
+	return EmailUndecided, nil
+}
+
+func isDNSNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
+
+// verdictCache keeps verdicts until they expire. When full it drops the
+// expired entries, and everything if that is not enough.
+type verdictCache struct {
+	mu      sync.Mutex
+	max     int
+	entries map[string]cachedVerdict
+}
+
+type cachedVerdict struct {
+	verdict EmailVerdict
+	expires time.Time
+}
+
+func newVerdictCache(max int) *verdictCache {
+	return &verdictCache{max: max, entries: map[string]cachedVerdict{}}
+}
+
+func (c *verdictCache) get(key string) (EmailVerdict, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || time.Now().After(entry.expires) {
+		return EmailUndecided, false
+	}
+	return entry.verdict, true
+}
+
+func (c *verdictCache) set(key string, verdict EmailVerdict, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	if len(c.entries) >= c.max {
+		for k, entry := range c.entries {
+			if now.After(entry.expires) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= c.max {
+			c.entries = map[string]cachedVerdict{}
+		}
+	}
+	c.entries[key] = cachedVerdict{verdict: verdict, expires: now.Add(ttl)}
+}
@@ -0,0 +1,249 @@
+package infrastructure_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	emailpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/email"
+	infrastructure "github.com/Amaankaa/Blog-Starter-Project/Infrastructure"
+	"github.com/Amaankaa/Blog-Starter-Project/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeResolver answers from fixed records and counts the lookups
+type fakeResolver struct {
+	mx      map[string][]*net.MX
+	hosts   map[string][]string
+	failing bool
+	lookups int
+}
+
+func (r *fakeResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	r.lookups++
+	if r.failing {
+		return nil, &net.DNSError{Err: "server misbehaving", Name: name, IsTemporary: true}
+	}
+	if records, ok := r.mx[name]; ok {
+		return records, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func (r *fakeResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if addrs, ok := r.hosts[host]; ok {
+		return addrs, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func newTestDisposableCheck(t *testing.T) *infrastructure.DisposableDomainCheck {
+	check, err := infrastructure.NewDisposableDomainCheck(strings.NewReader("# throwaway\nmailinator.com\n\nYopmail.com\n"))
+	require.NoError(t, err)
+	return check
+}
+
+func TestEmailVerifierChain_Syntax(t *testing.T) {
+	chain := infrastructure.NewEmailVerifierChain()
+
+	for email, want := range map[string]bool{
+		"student@aau.edu.et":              true,
+		"First.Last+tag@Example.COM":      true,
+		"not an email":                    false,
+		"Someone <someone@example.com>":   false,
+		"someone@localhost":               false,
+		"someone@[127.0.0.1]":             false,
+		"someone@example.invalid":         false,
+		"someone@bad_domain.com":          false,
+		strings.Repeat("a", 65) + "@x.io": false,
+	} {
+		ok, err := chain.IsRealEmail(email)
+		assert.NoError(t, err)
+		assert.Equal(t, want, ok, email)
+	}
+}
+
+func TestEmailVerifierChain_DisposableDomainsAndSubdomains(t *testing.T) {
+	chain := infrastructure.NewEmailVerifierChain().Then(newTestDisposableCheck(t), time.Hour)
+
+	for email, want := range map[string]bool{
+		"a@mailinator.com":     false,
+		"a@eu.mailinator.com":  false,
+		"a@YOPMAIL.com":        false,
+		"a@notmailinator.com":  true,
+		"a@mailinator.com.org": true,
+	} {
+		ok, err := chain.IsRealEmail(email)
+		assert.NoError(t, err)
+		assert.Equal(t, want, ok, email)
+	}
+}
+
+func TestDisposableDomainCheck_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "disposable.txt")
+	require.NoError(t, os.WriteFile(path, []byte("mailinator.com\n"), 0o600))
+	check := newTestDisposableCheck(t)
+	require.NoError(t, check.LoadFile(path))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go check.Watch(ctx, path, 10*time.Millisecond)
+
+	verdict, err := check.CheckDomain(ctx, "yopmail.com")
+	require.NoError(t, err)
+	assert.Equal(t, infrastructure.EmailUndecided, verdict, "the file replaces the list")
+
+	later := time.Now().Add(time.Second)
+	require.NoError(t, os.WriteFile(path, []byte("mailinator.com\nyopmail.com\n"), 0o600))
+	require.NoError(t, os.Chtimes(path, later, later))
+	assert.Eventually(t, func() bool { return check.Len() == 2 }, time.Second, 10*time.Millisecond)
+}
+
+func TestEmailVerifierChain_BundledDisposableList(t *testing.T) {
+	check, err := infrastructure.NewDisposableDomainCheckFromEnv(context.Background())
+	require.NoError(t, err)
+	assert.Greater(t, check.Len(), 50)
+
+	ok, err := infrastructure.NewEmailVerifierChain().Then(check, time.Hour).IsRealEmail("a@guerrillamail.com")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestEmailVerifierChain_AdminAllowOverridesLaterSteps(t *testing.T) {
+	rules := &mocks.IDomainRuleRepository{}
+	rules.On("FindRule", mock.Anything, []string{"mailinator.com", "com"}).
+		Return(emailpkg.DomainRule{Domain: "mailinator.com", Action: emailpkg.DomainAllow}, true, nil)
+	rules.On("FindRule", mock.Anything, []string{"spam.example", "example"}).
+		Return(emailpkg.DomainRule{Domain: "spam.example", Action: emailpkg.DomainDeny}, true, nil)
+	rules.On("FindRule", mock.Anything, mock.Anything).Return(emailpkg.DomainRule{}, false, nil)
+
+	chain := infrastructure.NewEmailVerifierChain().
+		Then(infrastructure.NewDomainRuleCheck(rules), time.Minute).
+		Then(newTestDisposableCheck(t), time.Hour)
+
+	ok, _ := chain.IsRealEmail("a@mailinator.com")
+	assert.True(t, ok, "allowed despite the disposable list")
+	ok, _ = chain.IsRealEmail("a@spam.example")
+	assert.False(t, ok)
+	ok, _ = chain.IsRealEmail("a@yopmail.com")
+	assert.False(t, ok)
+
+	// Verdicts are cached per domain
+	ok, _ = chain.IsRealEmail("b@mailinator.com")
+	assert.True(t, ok)
+	rules.AssertNumberOfCalls(t, "FindRule", 3)
+}
+
+func TestEmailVerifierChain_MXLookup(t *testing.T) {
+	resolver := &fakeResolver{
+		mx: map[string][]*net.MX{
+			"example.com":   {{Host: "mx.example.com.", Pref: 10}},
+			"nomail.com":    {{Host: ".", Pref: 0}},
+			"bare-host.com": {},
+		},
+		hosts: map[string][]string{"bare-host.com": {"192.0.2.1"}},
+	}
+	chain := infrastructure.NewEmailVerifierChain().Then(infrastructure.NewMXCheck(resolver), time.Hour)
+
+	for email, want := range map[string]bool{
+		"a@example.com":      true,
+		"a@nomail.com":       false, // null MX
+		"a@bare-host.com":    true,  // falls back to the address record
+		"a@doesnotexist.com": false,
+	} {
+		ok, err := chain.IsRealEmail(email)
+		assert.NoError(t, err)
+		assert.Equal(t, want, ok, email)
+	}
+
+	lookups := resolver.lookups
+	ok, _ := chain.IsRealEmail("b@example.com")
+	assert.True(t, ok)
+	assert.Equal(t, lookups, resolver.lookups, "the domain's verdict is cached")
+}
+
+func TestEmailVerifierChain_FailingStepsAreSkipped(t *testing.T) {
+	resolver := &fakeResolver{failing: true}
+	remote := &mocks.IEmailVerifier{}
+	remote.On("IsRealEmail", mock.Anything).Return(false, errors.New("EmailListVerify API returned status 503"))
+
+	chain := infrastructure.NewEmailVerifierChain().
+		Then(infrastructure.NewMXCheck(resolver), time.Hour).
+		ThenRemote(remote, time.Hour)
+
+	ok, err := chain.IsRealEmail("a@example.com")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// Failures are not cached
+	_, _ = chain.IsRealEmail("a@example.com")
+	assert.Equal(t, 2, resolver.lookups)
+}
+
+func TestEmailVerifierChain_RemoteRunsLastAndCachesAcceptedAddresses(t *testing.T) {
+	remote := &mocks.IEmailVerifier{}
+	remote.On("IsRealEmail", "nobody@example.com").Return(false, nil)
+	remote.On("IsRealEmail", "a@example.com").Return(true, nil).Once()
+	remote.On("IsRealEmail", "b@example.com").Return(false, nil)
+
+	chain := infrastructure.NewEmailVerifierChain().
+		Then(newTestDisposableCheck(t), time.Hour).
+		ThenRemote(remote, time.Hour)
+
+	ok, _ := chain.IsRealEmail("a@mailinator.com")
+	assert.False(t, ok)
+	remote.AssertNotCalled(t, "IsRealEmail", "a@mailinator.com")
+
+	ok, _ = chain.IsRealEmail("nobody@example.com")
+	assert.False(t, ok)
+	ok, _ = chain.IsRealEmail("a@example.com")
+	assert.True(t, ok)
+	ok, _ = chain.IsRealEmail("A@example.com")
+	assert.True(t, ok, "accepted from the cache")
+	// One good mailbox says nothing about the rest of its domain
+	ok, _ = chain.IsRealEmail("b@example.com")
+	assert.False(t, ok)
+	remote.AssertNumberOfCalls(t, "IsRealEmail", 3)
+}
+
+func TestEmailListVerifyVerifier_Responses(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "key", r.URL.Query().Get("secret"))
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+	verifier := &infrastructure.EmailListVerifyVerifier{APIKey: "key", Endpoint: server.URL}
+
+	body = ""
+	_, err := verifier.IsRealEmail("a@example.com")
+	assert.Error(t, err, "an empty body is an error, not a panic")
+
+	body = "ok\n"
+	ok, err := verifier.IsRealEmail("a@example.com")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	body = "email_disabled"
+	ok, err = verifier.IsRealEmail("a@example.com")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	body = "unknown"
+	_, err = verifier.IsRealEmail("a@example.com")
+	assert.Error(t, err)
+
+	body = `{"status":"ok","result":"deliverable","mx_record":true,"disposable":false}`
+	ok, err = verifier.IsRealEmail("a@example.com")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
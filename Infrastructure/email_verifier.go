@@ -2,14 +2,23 @@ package infrastructure
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
+	"strings"
 )
 
+const emailListVerifyEndpoint = "https://apps.emaillistverify.com/api/verifyEmail"
+
+// EmailListVerifyVerifier asks EmailListVerify whether a mailbox exists. It
+// is a paid call per address, so it runs last in the EmailVerifierChain.
 type EmailListVerifyVerifier struct {
-	APIKey string
+	APIKey   string
+	Endpoint string // defaults to EmailListVerify's verifyEmail API
+	Client   *http.Client
 }
 
 type emailListVerifyResponse struct {
@@ -24,6 +33,22 @@ type emailListVerifyResponse struct {
 	Deliverable bool   `json:"deliverable"`
 }
 
+// emailListVerifyStatuses maps the plain-text answers to whether the address
+// is accepted. Anything else, "unknown" included, is reported as an error.
+var emailListVerifyStatuses = map[string]bool{
+	"ok":             true,
+	"ok_for_all":     true,
+	"accept_all":     true,
+	"invalid":        false,
+	"error":          false,
+	"email_disabled": false,
+	"dead_server":    false,
+	"invalid_mx":     false,
+	"invalid_syntax": false,
+	"disposable":     false,
+	"spamtrap":       false,
+}
+
 func NewEmailListVerifyVerifier() (*EmailListVerifyVerifier, error) {
 	apiKey := os.Getenv("EMAILLISTVERIFY_API_KEY")
 	if apiKey == "" {
@@ -33,18 +58,21 @@ func NewEmailListVerifyVerifier() (*EmailListVerifyVerifier, error) {
 }
 
 func (e *EmailListVerifyVerifier) IsRealEmail(email string) (bool, error) {
-	// EmailListVerify API endpoint - try the correct endpoint
-	baseURL := "https://apps.emaillistverify.com/api/verifyEmail"
+	endpoint := e.Endpoint
+	if endpoint == "" {
+		endpoint = emailListVerifyEndpoint
+	}
+	client := e.Client
+	if client == nil {
+		client = &http.Client{Timeout: emailVerifyTimeout}
+	}
 
-	// Create URL with parameters - EmailListVerify uses 'secret' and 'email' parameters
+	// EmailListVerify takes the key as 'secret'
 	params := url.Values{}
 	params.Add("secret", e.APIKey)
 	params.Add("email", email)
 
-	requestURL := fmt.Sprintf("%s?%s", baseURL, params.Encode())
-
-
-	resp, err := http.Get(requestURL)
+	resp, err := client.Get(endpoint + "?" + params.Encode())
 	if err != nil {
 		return false, fmt.Errorf("failed to make request to EmailListVerify: %w", err)
 	}
@@ -54,35 +82,27 @@ func (e *EmailListVerifyVerifier) IsRealEmail(email string) (bool, error) {
 		return false, fmt.Errorf("EmailListVerify API returned status %d", resp.StatusCode)
 	}
 
-	// Read the response body first to debug
-	body := make([]byte, 0)
-	buf := make([]byte, 1024)
-	for {
-		n, err := resp.Body.Read(buf)
-		if n > 0 {
-			body = append(body, buf[:n]...)
-		}
-		if err != nil {
-			break
-		}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return false, fmt.Errorf("failed to read EmailListVerify response: %w", err)
+	}
+	text := strings.TrimSpace(string(body))
+	if text == "" {
+		return false, errors.New("EmailListVerify returned an empty response")
 	}
 
-	// Check if response starts with '{' (JSON) or is plain text
-	if body[0] != '{' {
-		responseText := string(body)
-		// Handle common plain text responses
-		if responseText == "ok" {
-			return true, nil
-		}
-		if responseText == "invalid" || responseText == "error" {
-			return false, nil
+	// Plain-text status, or JSON
+	if !strings.HasPrefix(text, "{") {
+		valid, known := emailListVerifyStatuses[strings.ToLower(text)]
+		if !known {
+			return false, fmt.Errorf("EmailListVerify returned non-JSON response: %s", text)
 		}
-		return false, fmt.Errorf("EmailListVerify returned non-JSON response: %s", responseText)
+		return valid, nil
 	}
 
 	var result emailListVerifyResponse
-	if err := json.Unmarshal(body, &result); err != nil {
-		return false, fmt.Errorf("failed to decode EmailListVerify JSON response: %w. Raw response: %s", err, string(body))
+	if err := json.Unmarshal([]byte(text), &result); err != nil {
+		return false, fmt.Errorf("failed to decode EmailListVerify JSON response: %w. Raw response: %s", err, text)
 	}
 
 	isValid := result.Status == "ok" &&
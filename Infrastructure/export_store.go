@@ -0,0 +1,57 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// FileExportStore keeps data export archives in a local directory. Archives
+// hold personal data, so the directory and files are private to the process
+// user.
+type FileExportStore struct {
+	dir string
+}
+
+func NewFileExportStore(dir string) (*FileExportStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &FileExportStore{dir: dir}, nil
+}
+
+func (s *FileExportStore) path(name string) (string, error) {
+	if name == "" || filepath.Base(name) != name {
+		return "", errors.New("invalid export name")
+	}
+	return filepath.Join(s.dir, name), nil
+}
+
+func (s *FileExportStore) Save(ctx context.Context, name string, data []byte) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+func (s *FileExportStore) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+func (s *FileExportStore) Delete(ctx context.Context, name string) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,449 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	utils "github.com/Amaankaa/Blog-Starter-Project/Domain/utils"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+
+	defaultKeyRotationInterval = 30 * 24 * time.Hour
+	rsaKeyBits                 = 2048
+
+	// unknownKidReloadInterval spaces out the reloads triggered by tokens
+	// naming a key this replica does not know, so forged kids cannot flood
+	// the key store
+	unknownKidReloadInterval = 10 * time.Second
+)
+
+// KeyRing holds the keys used to sign and verify JWTs.
+//
+// In asymmetric mode (RS256 or EdDSA) every token carries a "kid" header naming
+// the key that signed it. New tokens are signed with the newest active key,
+// while any unexpired key still verifies, so tokens survive a rotation. Keys
+// live in an ISigningKeyRepository so all replicas share them. Scheduled
+// rotations save the next key well before it activates, so every replica and
+// every cached JWK set knows it by the time the first token is signed with it.
+//
+// HS256 with a shared secret is the legacy mode. When a secret is configured
+// alongside an asymmetric algorithm, HS256 tokens without a kid are still
+// accepted so sessions issued before the switch keep working.
+type KeyRing struct {
+	mu        sync.RWMutex
+	algorithm string
+	secret    []byte
+	repo      userpkg.ISigningKeyRepository
+	rotation  time.Duration
+
+	keys          map[string]*ringKey
+	lastKidReload time.Time
+}
+
+type ringKey struct {
+	record userpkg.SigningKey
+	method jwt.SigningMethod
+	signer crypto.Signer
+}
+
+// activeFrom is when the key starts signing. Keys saved before rotations were
+// scheduled ahead sign from the moment they were created.
+func (k *ringKey) activeFrom() time.Time {
+	if k.record.ActivatesAt.IsZero() {
+		return k.record.CreatedAt
+	}
+	return k.record.ActivatesAt
+}
+
+// NewLegacyKeyRing signs and verifies with HS256 only
+func NewLegacyKeyRing(secret []byte) *KeyRing {
+	return &KeyRing{algorithm: AlgHS256, secret: secret, keys: map[string]*ringKey{}}
+}
+
+// NewKeyRing loads the shared keys for algorithm and creates one if none is
+// current. repo may be nil, in which case keys only live in this process.
+func NewKeyRing(ctx context.Context, algorithm string, secret []byte, repo userpkg.ISigningKeyRepository, rotation time.Duration) (*KeyRing, error) {
+	if algorithm == AlgHS256 {
+		if len(secret) == 0 {
+			return nil, errors.New("HS256 requires a secret")
+		}
+		return NewLegacyKeyRing(secret), nil
+	}
+	if algorithm != AlgRS256 && algorithm != AlgEdDSA {
+		return nil, fmt.Errorf("unsupported JWT signing algorithm %q", algorithm)
+	}
+	if rotation <= 0 {
+		rotation = defaultKeyRotationInterval
+	}
+
+	kr := &KeyRing{
+		algorithm: algorithm,
+		secret:    secret,
+		repo:      repo,
+		rotation:  rotation,
+		keys:      map[string]*ringKey{},
+	}
+	if err := kr.Reload(ctx); err != nil {
+		return nil, err
+	}
+	if err := kr.rotateIfDue(ctx); err != nil {
+		return nil, err
+	}
+	return kr, nil
+}
+
+// NewKeyRingFromEnv builds a key ring from JWT_SIGNING_ALG (HS256 by default),
+// JWT_SECRET and JWT_KEY_ROTATION_INTERVAL.
+func NewKeyRingFromEnv(ctx context.Context, repo userpkg.ISigningKeyRepository) (*KeyRing, error) {
+	algorithm := os.Getenv("JWT_SIGNING_ALG")
+	if algorithm == "" {
+		algorithm = AlgHS256
+	}
+
+	rotation := defaultKeyRotationInterval
+	if raw := os.Getenv("JWT_KEY_ROTATION_INTERVAL"); raw != "" {
+		d, err := time.ParseDuration(raw)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid JWT_KEY_ROTATION_INTERVAL %q", raw)
+		}
+		rotation = d
+	}
+
+	return NewKeyRing(ctx, algorithm, []byte(os.Getenv("JWT_SECRET")), repo, rotation)
+}
+
+// Start rotates the signing key when it is due and picks up keys created by
+// other replicas, until ctx is cancelled. It is a no-op in legacy mode.
+func (kr *KeyRing) Start(ctx context.Context) {
+	if kr.algorithm == AlgHS256 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(kr.reloadInterval())
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := kr.Reload(ctx); err != nil {
+					log.Printf("jwt: failed to reload signing keys: %v", err)
+					continue
+				}
+				if err := kr.rotateIfDue(ctx); err != nil {
+					log.Printf("jwt: failed to rotate signing key: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// reloadInterval is how often Start reloads the keys and checks the rotation
+func (kr *KeyRing) reloadInterval() time.Duration {
+	interval := kr.rotation / 10
+	if interval > time.Hour {
+		interval = time.Hour
+	}
+	if interval < time.Minute {
+		interval = time.Minute
+	}
+	return interval
+}
+
+// publishLead is how long before activation a scheduled key is saved: enough
+// for every replica to reload it and for cached JWK sets to expire
+func (kr *KeyRing) publishLead() time.Duration {
+	return kr.reloadInterval() + userpkg.JWKSMaxAge
+}
+
+// Rotate creates a new signing key and makes it current at once. Older keys
+// remain valid for verification until they expire. Verifiers holding a cached
+// JWK set reject the new key's tokens until they refetch it; scheduled
+// rotations avoid that by publishing the key ahead.
+func (kr *KeyRing) Rotate(ctx context.Context) error {
+	return kr.addKey(ctx, time.Now())
+}
+
+// addKey creates and saves a key that starts signing at activatesAt
+func (kr *KeyRing) addKey(ctx context.Context, activatesAt time.Time) error {
+	kid, err := utils.GenerateSecureToken(12)
+	if err != nil {
+		return err
+	}
+
+	var signer crypto.Signer
+	switch kr.algorithm {
+	case AlgRS256:
+		signer, err = rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	case AlgEdDSA:
+		_, signer, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		return fmt.Errorf("cannot rotate %s keys", kr.algorithm)
+	}
+	if err != nil {
+		return err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(signer)
+	if err != nil {
+		return err
+	}
+
+	record := userpkg.SigningKey{
+		ID:          kid,
+		Algorithm:   kr.algorithm,
+		PrivateKey:  der,
+		CreatedAt:   time.Now(),
+		ActivatesAt: activatesAt,
+		// The last token signed before the next rotation must still verify
+		ExpiresAt: activatesAt.Add(kr.rotation + userpkg.RefreshTokenTTL),
+	}
+	if kr.repo != nil {
+		if err := kr.repo.SaveKey(ctx, record); err != nil {
+			return err
+		}
+	}
+
+	key, err := parseSigningKey(record)
+	if err != nil {
+		return err
+	}
+
+	kr.mu.Lock()
+	kr.keys[key.record.ID] = key
+	kr.mu.Unlock()
+	return nil
+}
+
+// Reload replaces the verification keys with the unexpired keys in the
+// repository, including those not signing yet.
+func (kr *KeyRing) Reload(ctx context.Context) error {
+	now := time.Now()
+
+	var records []userpkg.SigningKey
+	if kr.repo != nil {
+		found, err := kr.repo.FindActiveKeys(ctx, now)
+		if err != nil {
+			return err
+		}
+		records = found
+	} else {
+		kr.mu.RLock()
+		for _, k := range kr.keys {
+			if k.record.ExpiresAt.After(now) {
+				records = append(records, k.record)
+			}
+		}
+		kr.mu.RUnlock()
+	}
+
+	keys := make(map[string]*ringKey, len(records))
+	for _, record := range records {
+		key, err := parseSigningKey(record)
+		if err != nil {
+			log.Printf("jwt: skipping signing key %s: %v", record.ID, err)
+			continue
+		}
+		keys[record.ID] = key
+	}
+
+	kr.mu.Lock()
+	kr.keys = keys
+	kr.mu.Unlock()
+	return nil
+}
+
+// rotateIfDue creates a signing key when there is none, and otherwise saves
+// the next key publishLead before the current one has served its rotation
+// period. If another replica already saved the next key, nothing happens.
+func (kr *KeyRing) rotateIfDue(ctx context.Context) error {
+	now := time.Now()
+	current, pending := kr.currentKey(now), kr.pendingKey(now)
+	if current == nil && pending == nil {
+		return kr.Rotate(ctx)
+	}
+	if pending != nil {
+		return nil
+	}
+
+	lead := kr.publishLead()
+	due := current.activeFrom().Add(kr.rotation)
+	if now.Before(due.Add(-lead)) {
+		return nil
+	}
+	// Overdue, e.g. after downtime: still publish ahead rather than switch now
+	if earliest := now.Add(lead); due.Before(earliest) {
+		due = earliest
+	}
+	return kr.addKey(ctx, due)
+}
+
+// currentKey is the newest key of the configured algorithm active at now
+func (kr *KeyRing) currentKey(now time.Time) *ringKey {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+
+	var current *ringKey
+	for _, k := range kr.keys {
+		if k.record.Algorithm != kr.algorithm || k.activeFrom().After(now) {
+			continue
+		}
+		if current == nil || k.activeFrom().After(current.activeFrom()) {
+			current = k
+		}
+	}
+	return current
+}
+
+// pendingKey is a published key that starts signing after now, if any
+func (kr *KeyRing) pendingKey(now time.Time) *ringKey {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+
+	for _, k := range kr.keys {
+		if k.record.Algorithm == kr.algorithm && k.activeFrom().After(now) {
+			return k
+		}
+	}
+	return nil
+}
+
+// Sign signs claims with the current key
+func (kr *KeyRing) Sign(claims jwt.MapClaims) (string, error) {
+	if kr.algorithm == AlgHS256 {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(kr.secret)
+	}
+
+	current := kr.currentKey(time.Now())
+	if current == nil {
+		return "", errors.New("no signing key available")
+	}
+
+	token := jwt.NewWithClaims(current.method, claims)
+	token.Header["kid"] = current.record.ID
+	return token.SignedString(current.signer)
+}
+
+// Keyfunc resolves the verification key for a parsed token. The algorithm is
+// taken from the key, never trusted from the token header.
+func (kr *KeyRing) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		// Legacy HS256 tokens carry no kid
+		if len(kr.secret) == 0 || token.Method != jwt.SigningMethodHS256 {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return kr.secret, nil
+	}
+
+	kr.mu.RLock()
+	key, ok := kr.keys[kid]
+	kr.mu.RUnlock()
+	if !ok {
+		// Another replica may have created the key since our last reload
+		key, ok = kr.reloadForKid(kid)
+	}
+	if !ok || token.Method.Alg() != key.method.Alg() {
+		return nil, jwt.ErrSignatureInvalid
+	}
+	return key.signer.Public(), nil
+}
+
+// reloadForKid reloads the shared keys once for a kid this replica does not
+// know, at most every unknownKidReloadInterval
+func (kr *KeyRing) reloadForKid(kid string) (*ringKey, bool) {
+	if kr.repo == nil {
+		return nil, false
+	}
+	kr.mu.Lock()
+	if time.Since(kr.lastKidReload) < unknownKidReloadInterval {
+		kr.mu.Unlock()
+		return nil, false
+	}
+	kr.lastKidReload = time.Now()
+	kr.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := kr.Reload(ctx); err != nil {
+		log.Printf("jwt: failed to reload signing keys for kid %s: %v", kid, err)
+		return nil, false
+	}
+
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+	key, ok := kr.keys[kid]
+	return key, ok
+}
+
+// PublicKeys returns the verification keys as a JWK set, oldest first
+func (kr *KeyRing) PublicKeys() userpkg.JWKSet {
+	kr.mu.RLock()
+	keys := make([]*ringKey, 0, len(kr.keys))
+	for _, k := range kr.keys {
+		keys = append(keys, k)
+	}
+	kr.mu.RUnlock()
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].record.CreatedAt.Before(keys[j].record.CreatedAt)
+	})
+
+	set := userpkg.JWKSet{Keys: make([]userpkg.JWK, 0, len(keys))}
+	for _, k := range keys {
+		jwk := userpkg.JWK{KeyID: k.record.ID, Algorithm: k.record.Algorithm, Use: "sig"}
+		switch pub := k.signer.Public().(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		default:
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+func parseSigningKey(record userpkg.SigningKey) (*ringKey, error) {
+	parsed, err := x509.ParsePKCS8PrivateKey(record.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	switch record.Algorithm {
+	case AlgRS256:
+		if k, ok := parsed.(*rsa.PrivateKey); ok {
+			return &ringKey{record: record, method: jwt.SigningMethodRS256, signer: k}, nil
+		}
+	case AlgEdDSA:
+		if k, ok := parsed.(ed25519.PrivateKey); ok {
+			return &ringKey{record: record, method: jwt.SigningMethodEdDSA, signer: k}, nil
+		}
+	}
+	return nil, fmt.Errorf("key does not match algorithm %s", record.Algorithm)
+}
@@ -0,0 +1,207 @@
+package infrastructure_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"sync"
+	"testing"
+	"time"
+
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	infrastructure "github.com/Amaankaa/Blog-Starter-Project/Infrastructure"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memKeyRepo is a signing key store shared by key rings standing in for replicas
+type memKeyRepo struct {
+	mu   sync.Mutex
+	keys []userpkg.SigningKey
+}
+
+func (r *memKeyRepo) SaveKey(_ context.Context, key userpkg.SigningKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.keys = append(r.keys, key)
+	return nil
+}
+
+func (r *memKeyRepo) FindActiveKeys(_ context.Context, now time.Time) ([]userpkg.SigningKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var active []userpkg.SigningKey
+	for _, k := range r.keys {
+		if k.ExpiresAt.After(now) {
+			active = append(active, k)
+		}
+	}
+	return active, nil
+}
+
+func testClaims() jwt.MapClaims {
+	return jwt.MapClaims{"_id": "user1", "exp": time.Now().Add(time.Minute).Unix()}
+}
+
+func verify(kr *infrastructure.KeyRing, signed string) error {
+	_, err := jwt.Parse(signed, kr.Keyfunc)
+	return err
+}
+
+func kidOf(t *testing.T, signed string) string {
+	token, _, err := new(jwt.Parser).ParseUnverified(signed, jwt.MapClaims{})
+	require.NoError(t, err)
+	kid, _ := token.Header["kid"].(string)
+	return kid
+}
+
+func TestKeyRing_SignAndVerify(t *testing.T) {
+	for _, alg := range []string{infrastructure.AlgRS256, infrastructure.AlgEdDSA} {
+		t.Run(alg, func(t *testing.T) {
+			kr, err := infrastructure.NewKeyRing(context.Background(), alg, nil, &memKeyRepo{}, time.Hour)
+			require.NoError(t, err)
+
+			signed, err := kr.Sign(testClaims())
+			require.NoError(t, err)
+
+			assert.NoError(t, verify(kr, signed))
+			assert.NotEmpty(t, kidOf(t, signed))
+			require.Len(t, kr.PublicKeys().Keys, 1)
+			assert.Equal(t, kidOf(t, signed), kr.PublicKeys().Keys[0].KeyID)
+		})
+	}
+}
+
+func TestKeyRing_RejectsAlgorithmSwitchAndUnknownKid(t *testing.T) {
+	kr, err := infrastructure.NewKeyRing(context.Background(), infrastructure.AlgEdDSA, nil, nil, time.Hour)
+	require.NoError(t, err)
+	signed, err := kr.Sign(testClaims())
+	require.NoError(t, err)
+
+	// Same kid, but HS256 with some guessable secret
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims())
+	forged.Header["kid"] = kidOf(t, signed)
+	forgedString, err := forged.SignedString([]byte("guess"))
+	require.NoError(t, err)
+	assert.Error(t, verify(kr, forgedString))
+
+	other, err := infrastructure.NewKeyRing(context.Background(), infrastructure.AlgEdDSA, nil, nil, time.Hour)
+	require.NoError(t, err)
+	foreign, err := other.Sign(testClaims())
+	require.NoError(t, err)
+	assert.Error(t, verify(kr, foreign))
+}
+
+func TestKeyRing_LegacyHS256(t *testing.T) {
+	secret := []byte("legacy-secret")
+	legacy, err := jwt.NewWithClaims(jwt.SigningMethodHS256, testClaims()).SignedString(secret)
+	require.NoError(t, err)
+
+	// Switched to EdDSA with the old secret still configured
+	kr, err := infrastructure.NewKeyRing(context.Background(), infrastructure.AlgEdDSA, secret, nil, time.Hour)
+	require.NoError(t, err)
+	assert.NoError(t, verify(kr, legacy))
+
+	// Without the secret, kid-less tokens are refused
+	kr, err = infrastructure.NewKeyRing(context.Background(), infrastructure.AlgEdDSA, nil, nil, time.Hour)
+	require.NoError(t, err)
+	assert.Error(t, verify(kr, legacy))
+
+	hs, err := infrastructure.NewKeyRing(context.Background(), infrastructure.AlgHS256, secret, nil, 0)
+	require.NoError(t, err)
+	signed, err := hs.Sign(testClaims())
+	require.NoError(t, err)
+	assert.NoError(t, verify(hs, signed))
+	assert.Empty(t, kidOf(t, signed))
+}
+
+func TestKeyRing_RotateKeepsOldTokensValid(t *testing.T) {
+	ctx := context.Background()
+	kr, err := infrastructure.NewKeyRing(ctx, infrastructure.AlgEdDSA, nil, &memKeyRepo{}, time.Hour)
+	require.NoError(t, err)
+	before, err := kr.Sign(testClaims())
+	require.NoError(t, err)
+
+	require.NoError(t, kr.Rotate(ctx))
+	after, err := kr.Sign(testClaims())
+	require.NoError(t, err)
+
+	assert.NotEqual(t, kidOf(t, before), kidOf(t, after))
+	assert.NoError(t, verify(kr, before))
+	assert.NoError(t, verify(kr, after))
+	assert.Len(t, kr.PublicKeys().Keys, 2)
+}
+
+func TestKeyRing_ScheduledRotationPublishesBeforeSigning(t *testing.T) {
+	ctx := context.Background()
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	// A key that has served its whole rotation period
+	repo := &memKeyRepo{keys: []userpkg.SigningKey{{
+		ID:         "old",
+		Algorithm:  infrastructure.AlgEdDSA,
+		PrivateKey: der,
+		CreatedAt:  time.Now().Add(-2 * time.Hour),
+		ExpiresAt:  time.Now().Add(time.Hour),
+	}}}
+
+	kr, err := infrastructure.NewKeyRing(ctx, infrastructure.AlgEdDSA, nil, repo, time.Hour)
+	require.NoError(t, err)
+
+	// The next key is published and shared, but the old one keeps signing
+	// until verifiers have had time to fetch it
+	require.Len(t, repo.keys, 2)
+	next := repo.keys[1]
+	assert.True(t, next.ActivatesAt.After(time.Now().Add(userpkg.JWKSMaxAge)))
+	assert.Equal(t, next.ActivatesAt.Add(time.Hour+userpkg.RefreshTokenTTL), next.ExpiresAt)
+	assert.Len(t, kr.PublicKeys().Keys, 2)
+	signed, err := kr.Sign(testClaims())
+	require.NoError(t, err)
+	assert.Equal(t, "old", kidOf(t, signed))
+
+	// Another replica starting now does not schedule a second key
+	_, err = infrastructure.NewKeyRing(ctx, infrastructure.AlgEdDSA, nil, repo, time.Hour)
+	require.NoError(t, err)
+	assert.Len(t, repo.keys, 2)
+}
+
+func TestKeyRing_ReplicasShareKeys(t *testing.T) {
+	ctx := context.Background()
+	repo := &memKeyRepo{}
+	a, err := infrastructure.NewKeyRing(ctx, infrastructure.AlgRS256, nil, repo, time.Hour)
+	require.NoError(t, err)
+	b, err := infrastructure.NewKeyRing(ctx, infrastructure.AlgRS256, nil, repo, time.Hour)
+	require.NoError(t, err)
+	signed, err := a.Sign(testClaims())
+	require.NoError(t, err)
+	assert.NoError(t, verify(b, signed))
+
+	// b learns of a's new key on the first token naming it, without waiting
+	// for its periodic reload
+	require.NoError(t, a.Rotate(ctx))
+	rotated, err := a.Sign(testClaims())
+	require.NoError(t, err)
+	assert.NoError(t, verify(b, rotated))
+	assert.Len(t, b.PublicKeys().Keys, 2)
+}
+
+func TestKeyRing_ReloadDropsExpiredKeys(t *testing.T) {
+	ctx := context.Background()
+	repo := &memKeyRepo{}
+	kr, err := infrastructure.NewKeyRing(ctx, infrastructure.AlgEdDSA, nil, repo, time.Hour)
+	require.NoError(t, err)
+	signed, err := kr.Sign(testClaims())
+	require.NoError(t, err)
+
+	repo.keys[0].ExpiresAt = time.Now().Add(-time.Second)
+	require.NoError(t, kr.Reload(ctx))
+
+	assert.Empty(t, kr.PublicKeys().Keys)
+	assert.Error(t, verify(kr, signed))
+	_, err = kr.Sign(testClaims())
+	assert.Error(t, err)
+}
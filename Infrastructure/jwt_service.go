@@ -3,54 +3,68 @@ package infrastructure
 import (
 	"errors"
 	"os"
-	"sync"
 	"time"
 
 	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	utils "github.com/Amaankaa/Blog-Starter-Project/Domain/utils"
 	"github.com/golang-jwt/jwt/v4"
 )
 
-var (
-	jwtSecret     []byte
-	loadSecretOnce sync.Once
-)
+type JWTService struct {
+	keys *KeyRing
+}
 
-func loadJWTSecret() {
+// NewJWTService signs with HS256 using JWT_SECRET (legacy mode)
+func NewJWTService() *JWTService {
 	secret := os.Getenv("JWT_SECRET")
 	if secret == "" {
 		panic("JWT_SECRET not set in environment")
 	}
-	jwtSecret = []byte(secret)
+	return NewJWTServiceWithKeyRing(NewLegacyKeyRing([]byte(secret)))
 }
 
-type JWTService struct{}
-
-func NewJWTService() *JWTService {
-	loadSecretOnce.Do(loadJWTSecret)
-	return &JWTService{}
+func NewJWTServiceWithKeyRing(keys *KeyRing) *JWTService {
+	return &JWTService{keys: keys}
 }
 
-func (j *JWTService) GenerateToken(userID, username, role string) (userpkg.TokenResult, error) {
-	accessExp := time.Now().Add(15 * time.Minute)
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"_id":      userID,
-		"username": username,
-		"role":     role,
-		"exp":      accessExp.Unix(),
-	})
+func (j *JWTService) GenerateToken(subject userpkg.TokenSubject) (userpkg.TokenResult, error) {
+	// Unique IDs keep tokens minted within the same second distinguishable
+	accessJTI, err := utils.GenerateSecureToken(16)
+	if err != nil {
+		return userpkg.TokenResult{}, err
+	}
+	refreshJTI, err := utils.GenerateSecureToken(16)
+	if err != nil {
+		return userpkg.TokenResult{}, err
+	}
 
-	accessTokenString, err := accessToken.SignedString(jwtSecret)
+	now := time.Now()
+	accessExp := now.Add(userpkg.AccessTokenTTL)
+	accessTokenString, err := j.keys.Sign(jwt.MapClaims{
+		"typ":         userpkg.TokenTypeAccess,
+		"_id":         subject.UserID,
+		"username":    subject.Username,
+		"role":        subject.Role,
+		"permissions": subject.Permissions,
+		"sid":         subject.SessionID,
+		"mfa":         subject.MFA,
+		"jti":         accessJTI,
+		"iat":         now.Unix(),
+		"exp":         accessExp.Unix(),
+	})
 	if err != nil {
 		return userpkg.TokenResult{}, err
 	}
 
-	refreshExp := time.Now().Add(7 * 24 * time.Hour)
-	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"_id": userID,
+	refreshExp := now.Add(userpkg.RefreshTokenTTL)
+	refreshTokenString, err := j.keys.Sign(jwt.MapClaims{
+		"typ": userpkg.TokenTypeRefresh,
+		"_id": subject.UserID,
+		"sid": subject.SessionID,
+		"jti": refreshJTI,
+		"iat": now.Unix(),
 		"exp": refreshExp.Unix(),
 	})
-
-	refreshTokenString, err := refreshToken.SignedString(jwtSecret)
 	if err != nil {
 		return userpkg.TokenResult{}, err
 	}
@@ -63,14 +77,8 @@ func (j *JWTService) GenerateToken(userID, username, role string) (userpkg.Token
 	}, nil
 }
 
-
 func (j *JWTService) ValidateToken(tokenString string) (map[string]interface{}, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, jwt.ErrSignatureInvalid
-		}
-		return jwtSecret, nil
-	})
+	token, err := jwt.Parse(tokenString, j.keys.Keyfunc)
 
 	if err != nil || !token.Valid {
 		return nil, errors.New("invalid or expired token")
@@ -82,4 +90,10 @@ func (j *JWTService) ValidateToken(tokenString string) (map[string]interface{},
 	}
 
 	return claims, nil
-}
\ No newline at end of file
+}
+
+// PublicKeys returns the keys clients can use to verify our tokens. It is
+// empty in HS256 mode.
+func (j *JWTService) PublicKeys() userpkg.JWKSet {
+	return j.keys.PublicKeys()
+}
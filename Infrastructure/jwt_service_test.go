@@ -0,0 +1,28 @@
+package infrastructure_test
+
+import (
+	"testing"
+
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	infrastructure "github.com/Amaankaa/Blog-Starter-Project/Infrastructure"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWTService_TokensCarryTheirType(t *testing.T) {
+	jwt := infrastructure.NewJWTServiceWithKeyRing(infrastructure.NewLegacyKeyRing([]byte("test-secret")))
+
+	tokens, err := jwt.GenerateToken(userpkg.TokenSubject{UserID: "user1", SessionID: "session1"})
+	require.NoError(t, err)
+
+	access, err := jwt.ValidateToken(tokens.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, userpkg.TokenTypeAccess, access["typ"])
+
+	// Refresh tokens carry the session and issue time too, so revocations apply to them
+	refresh, err := jwt.ValidateToken(tokens.RefreshToken)
+	require.NoError(t, err)
+	assert.Equal(t, userpkg.TokenTypeRefresh, refresh["typ"])
+	assert.Equal(t, "session1", refresh["sid"])
+	assert.Contains(t, refresh, "iat")
+}
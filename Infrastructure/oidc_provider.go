@@ -0,0 +1,346 @@
+package infrastructure
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// oidcClockSkew is how far the provider's clock may be off from ours
+const oidcClockSkew = time.Minute
+
+// oidcJWKSRefresh is the longest the provider's signing keys are cached. An
+// unknown key ID triggers a refresh sooner, at most once per oidcJWKSMinRefresh.
+const (
+	oidcJWKSRefresh    = time.Hour
+	oidcJWKSMinRefresh = 10 * time.Second
+)
+
+// OIDCProviderConfig describes one OpenID Connect provider
+type OIDCProviderConfig struct {
+	Name         string // used in routes, e.g. /auth/oidc/google
+	Issuer       string // discovery happens at Issuer + "/.well-known/openid-configuration"
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string // "openid" is always requested
+}
+
+// OIDCProvider signs users in with the authorization code flow and PKCE.
+// Endpoints and signing keys are discovered from the issuer and cached.
+type OIDCProvider struct {
+	cfg    OIDCProviderConfig
+	client *http.Client
+
+	mu          sync.Mutex
+	discovery   *oidcDiscovery
+	keys        map[string]interface{}
+	keysFetched time.Time
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+func NewOIDCProvider(cfg OIDCProviderConfig, client *http.Client) *OIDCProvider {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	cfg.Issuer = strings.TrimRight(cfg.Issuer, "/")
+	return &OIDCProvider{cfg: cfg, client: client}
+}
+
+// NewOIDCProvidersFromEnv reads OIDC_PROVIDERS, a comma-separated list of
+// names, and for each name N the variables OIDC_<N>_ISSUER,
+// OIDC_<N>_CLIENT_ID, OIDC_<N>_CLIENT_SECRET and optionally OIDC_<N>_SCOPES
+// (space-separated, default "email profile"). Callbacks go to
+// publicURL + "/auth/oidc/<name>/callback".
+func NewOIDCProvidersFromEnv(publicURL string) ([]userpkg.IOIDCProvider, error) {
+	var providers []userpkg.IOIDCProvider
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		cfg := OIDCProviderConfig{
+			Name:         name,
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  strings.TrimRight(publicURL, "/") + "/auth/oidc/" + name + "/callback",
+			Scopes:       strings.Fields(os.Getenv(prefix + "SCOPES")),
+		}
+		if cfg.Issuer == "" || cfg.ClientID == "" {
+			return nil, fmt.Errorf("%sISSUER and %sCLIENT_ID must be set", prefix, prefix)
+		}
+		if len(cfg.Scopes) == 0 {
+			cfg.Scopes = []string{"email", "profile"}
+		}
+		providers = append(providers, NewOIDCProvider(cfg, nil))
+	}
+	return providers, nil
+}
+
+func (p *OIDCProvider) Name() string { return p.cfg.Name }
+
+// AuthCodeURL is where the user is sent to sign in at the provider
+func (p *OIDCProvider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	d, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	scopes := append([]string{"openid"}, p.cfg.Scopes...)
+	q := url.Values{}
+	q.Set("response_type", "code")
+	q.Set("client_id", p.cfg.ClientID)
+	q.Set("redirect_uri", p.cfg.RedirectURL)
+	q.Set("scope", strings.Join(scopes, " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", codeChallenge)
+	q.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(d.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return d.AuthorizationEndpoint + sep + q.Encode(), nil
+}
+
+// Exchange redeems the authorization code and validates the ID token that
+// comes back
+func (p *OIDCProvider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (userpkg.OIDCIdentity, error) {
+	d, err := p.discover(ctx)
+	if err != nil {
+		return userpkg.OIDCIdentity{}, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.cfg.RedirectURL)
+	form.Set("client_id", p.cfg.ClientID)
+	form.Set("code_verifier", codeVerifier)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return userpkg.OIDCIdentity{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+
+	var tokens struct {
+		IDToken string `json:"id_token"`
+		Error   string `json:"error"`
+	}
+	if err := p.getJSON(req, &tokens); err != nil {
+		return userpkg.OIDCIdentity{}, fmt.Errorf("%s token exchange failed: %w", p.cfg.Name, err)
+	}
+	if tokens.IDToken == "" {
+		return userpkg.OIDCIdentity{}, fmt.Errorf("%s returned no ID token", p.cfg.Name)
+	}
+	return p.validateIDToken(ctx, tokens.IDToken, nonce)
+}
+
+func (p *OIDCProvider) validateIDToken(ctx context.Context, raw, nonce string) (userpkg.OIDCIdentity, error) {
+	d, err := p.discover(ctx)
+	if err != nil {
+		return userpkg.OIDCIdentity{}, err
+	}
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.NewParser(
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "EdDSA"}),
+		// Time claims are checked below, with some leeway for clock skew
+		jwt.WithoutClaimsValidation(),
+	).ParseWithClaims(raw, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.key(ctx, d, kid)
+	})
+	if err != nil {
+		return userpkg.OIDCIdentity{}, errors.New("invalid ID token")
+	}
+
+	now := time.Now()
+	switch {
+	case !claims.VerifyIssuer(d.Issuer, true):
+		return userpkg.OIDCIdentity{}, errors.New("ID token has the wrong issuer")
+	case !claims.VerifyAudience(p.cfg.ClientID, true):
+		return userpkg.OIDCIdentity{}, errors.New("ID token has the wrong audience")
+	case !claims.VerifyExpiresAt(now.Add(-oidcClockSkew).Unix(), true):
+		return userpkg.OIDCIdentity{}, errors.New("ID token has expired")
+	case !claims.VerifyIssuedAt(now.Add(oidcClockSkew).Unix(), false):
+		return userpkg.OIDCIdentity{}, errors.New("ID token issued in the future")
+	}
+	if got, _ := claims["nonce"].(string); got == "" || got != nonce {
+		return userpkg.OIDCIdentity{}, errors.New("ID token nonce does not match")
+	}
+	// With several audiences the token must have been issued to us
+	if azp, ok := claims["azp"].(string); ok && azp != p.cfg.ClientID {
+		return userpkg.OIDCIdentity{}, errors.New("ID token was issued to another client")
+	}
+
+	identity := userpkg.OIDCIdentity{Provider: p.cfg.Name}
+	identity.Subject, _ = claims["sub"].(string)
+	if identity.Subject == "" {
+		return userpkg.OIDCIdentity{}, errors.New("ID token has no subject")
+	}
+	identity.Email, _ = claims["email"].(string)
+	identity.Name, _ = claims["name"].(string)
+	identity.Picture, _ = claims["picture"].(string)
+	// Some providers send email_verified as a string
+	switch v := claims["email_verified"].(type) {
+	case bool:
+		identity.EmailVerified = v
+	case string:
+		identity.EmailVerified = v == "true"
+	}
+	return identity, nil
+}
+
+func (p *OIDCProvider) discover(ctx context.Context) (*oidcDiscovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.cfg.Issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+	var d oidcDiscovery
+	if err := p.getJSON(req, &d); err != nil {
+		return nil, fmt.Errorf("%s discovery failed: %w", p.cfg.Name, err)
+	}
+	if strings.TrimRight(d.Issuer, "/") != p.cfg.Issuer {
+		return nil, fmt.Errorf("%s discovery returned issuer %q", p.cfg.Name, d.Issuer)
+	}
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return nil, fmt.Errorf("%s discovery document is incomplete", p.cfg.Name)
+	}
+	p.discovery = &d
+	return p.discovery, nil
+}
+
+// key returns the provider's verification key kid, refetching the key set
+// when it is stale or the key is unknown, e.g. after a rotation
+func (p *OIDCProvider) key(ctx context.Context, d *oidcDiscovery, kid string) (interface{}, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.keys[kid]; ok && time.Since(p.keysFetched) < oidcJWKSRefresh {
+		return key, nil
+	}
+	if time.Since(p.keysFetched) < oidcJWKSMinRefresh {
+		return nil, jwt.ErrSignatureInvalid
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.JWKSURI, nil)
+	if err != nil {
+		return nil, err
+	}
+	var set struct {
+		Keys []struct {
+			userpkg.JWK
+			Y string `json:"y,omitempty"` // EC keys
+		} `json:"keys"`
+	}
+	if err := p.getJSON(req, &set); err != nil {
+		return nil, err
+	}
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		if pub, err := parseJWK(k.JWK, k.Y); err == nil {
+			keys[k.KeyID] = pub
+		}
+	}
+	p.keys = keys
+	p.keysFetched = time.Now()
+
+	key, ok := keys[kid]
+	if !ok {
+		return nil, jwt.ErrSignatureInvalid
+	}
+	return key, nil
+}
+
+func (p *OIDCProvider) getJSON(req *http.Request, dst interface{}) error {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(dst)
+}
+
+// parseJWK turns an RSA, EC or Ed25519 public JWK into a Go key
+func parseJWK(k userpkg.JWK, y string) (interface{}, error) {
+	b64 := base64.RawURLEncoding
+	switch k.KeyType {
+	case "RSA":
+		n, err := b64.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := b64.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Curve {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Curve)
+		}
+		x, err := b64.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		yb, err := b64.DecodeString(y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(yb)}, nil
+	case "OKP":
+		x, err := b64.DecodeString(k.X)
+		if err != nil || k.Curve != "Ed25519" || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("unsupported key type %q", k.KeyType)
+}
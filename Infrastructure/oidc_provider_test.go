@@ -0,0 +1,173 @@
+package infrastructure_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	infrastructure "github.com/Amaankaa/Blog-Starter-Project/Infrastructure"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeIssuer is a minimal OpenID provider: discovery, JWKS and a token
+// endpoint that checks PKCE and hands out an ID token for the pending code.
+// key signs ID tokens; JWKS always publishes the key it started with.
+type fakeIssuer struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	challenge string
+	claims    jwt.MapClaims
+}
+
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	f := &fakeIssuer{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 f.server.URL,
+			"authorization_endpoint": f.server.URL + "/authorize",
+			"token_endpoint":         f.server.URL + "/token",
+			"jwks_uri":               f.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "k1",
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if r.PostForm.Get("code") != "good-code" || base64.RawURLEncoding.EncodeToString(sum[:]) != f.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, f.claims)
+		token.Header["kid"] = "k1"
+		signed, _ := token.SignedString(f.key)
+		_ = json.NewEncoder(w).Encode(map[string]string{"access_token": "at", "id_token": signed})
+	})
+	f.server = httptest.NewServer(mux)
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeIssuer) provider() *infrastructure.OIDCProvider {
+	return infrastructure.NewOIDCProvider(infrastructure.OIDCProviderConfig{
+		Name:        "campus",
+		Issuer:      f.server.URL,
+		ClientID:    "sharespace",
+		RedirectURL: "http://api.test/auth/oidc/campus/callback",
+		Scopes:      []string{"email"},
+	}, f.server.Client())
+}
+
+// authorize runs the redirect half of the flow and returns what the provider saw
+func (f *fakeIssuer) authorize(t *testing.T, p *infrastructure.OIDCProvider, nonce string) url.Values {
+	sum := sha256.Sum256([]byte("verifier"))
+	authURL, err := p.AuthCodeURL(context.Background(), "state", nonce, base64.RawURLEncoding.EncodeToString(sum[:]))
+	require.NoError(t, err)
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+	f.challenge = u.Query().Get("code_challenge")
+	return u.Query()
+}
+
+func (f *fakeIssuer) idClaims(nonce string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":            f.server.URL,
+		"aud":            "sharespace",
+		"sub":            "student-42",
+		"email":          "ada@uni.example",
+		"email_verified": "true",
+		"name":           "Ada L",
+		"nonce":          nonce,
+		"iat":            time.Now().Unix(),
+		"exp":            time.Now().Add(5 * time.Minute).Unix(),
+	}
+}
+
+func TestOIDCProvider_ExchangeValidatesIDToken(t *testing.T) {
+	f := newFakeIssuer(t)
+	p := f.provider()
+
+	q := f.authorize(t, p, "n-1")
+	assert.Equal(t, "S256", q.Get("code_challenge_method"))
+	assert.Equal(t, "openid email", q.Get("scope"))
+	f.claims = f.idClaims("n-1")
+
+	identity, err := p.Exchange(context.Background(), "good-code", "verifier", "n-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, "campus", identity.Provider)
+	assert.Equal(t, "student-42", identity.Subject)
+	assert.Equal(t, "ada@uni.example", identity.Email)
+	assert.True(t, identity.EmailVerified)
+}
+
+func TestOIDCProvider_RejectsWrongPKCEVerifier(t *testing.T) {
+	f := newFakeIssuer(t)
+	p := f.provider()
+	f.authorize(t, p, "n-1")
+	f.claims = f.idClaims("n-1")
+
+	_, err := p.Exchange(context.Background(), "good-code", "someone-elses-verifier", "n-1")
+
+	assert.Error(t, err)
+}
+
+func TestOIDCProvider_RejectsBadClaims(t *testing.T) {
+	f := newFakeIssuer(t)
+	p := f.provider()
+	f.authorize(t, p, "n-1")
+
+	for name, mutate := range map[string]func(jwt.MapClaims){
+		"nonce":    func(c jwt.MapClaims) { c["nonce"] = "replayed" },
+		"audience": func(c jwt.MapClaims) { c["aud"] = "another-app" },
+		"issuer":   func(c jwt.MapClaims) { c["iss"] = "https://evil.example" },
+		"expired":  func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() },
+	} {
+		claims := f.idClaims("n-1")
+		mutate(claims)
+		f.claims = claims
+
+		_, err := p.Exchange(context.Background(), "good-code", "verifier", "n-1")
+		assert.Error(t, err, name)
+	}
+}
+
+func TestOIDCProvider_RejectsForeignSigningKey(t *testing.T) {
+	f := newFakeIssuer(t)
+	p := f.provider()
+	f.authorize(t, p, "n-1")
+	f.claims = f.idClaims("n-1")
+	// Signed under the published kid, but not with the published key
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	f.key = other
+
+	_, err = p.Exchange(context.Background(), "good-code", "verifier", "n-1")
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,259 @@
+package infrastructure
+
+import (
+	"bufio"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+)
+
+//go:embed data/breached-passwords.txt
+var defaultBreachedPasswords string
+
+// PasswordRule is one check a new password must pass
+type PasswordRule interface {
+	Check(password string, user userpkg.User) error
+}
+
+// PasswordPolicy runs its rules in order and reports the first failure
+type PasswordPolicy struct {
+	rules   []PasswordRule
+	history int
+}
+
+// NewPasswordPolicy builds a policy from rules. history is how many recent
+// passwords, the current one included, a user may not reuse.
+func NewPasswordPolicy(history int, rules ...PasswordRule) *PasswordPolicy {
+	return &PasswordPolicy{rules: rules, history: history}
+}
+
+// NewPasswordPolicyFromEnv builds the default policy: PASSWORD_MIN_ENTROPY
+// bits (default 50), no password from BREACHED_PASSWORDS_FILE (default: the
+// bundled list), nothing resembling the username or email, and none of the
+// last PASSWORD_HISTORY passwords (default 5).
+func NewPasswordPolicyFromEnv(passwordSvc userpkg.IPasswordService) (*PasswordPolicy, error) {
+	minEntropy := 50.0
+	if raw := os.Getenv("PASSWORD_MIN_ENTROPY"); raw != "" {
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid PASSWORD_MIN_ENTROPY: %w", err)
+		}
+		minEntropy = v
+	}
+	history := 5
+	if raw := os.Getenv("PASSWORD_HISTORY"); raw != "" {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 0 {
+			return nil, fmt.Errorf("invalid PASSWORD_HISTORY: %q", raw)
+		}
+		history = v
+	}
+
+	var breached *BreachedListRule
+	if path := os.Getenv("BREACHED_PASSWORDS_FILE"); path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open breached password list: %w", err)
+		}
+		defer f.Close()
+		if breached, err = NewBreachedListRule(f); err != nil {
+			return nil, err
+		}
+	} else {
+		breached, _ = NewBreachedListRule(strings.NewReader(defaultBreachedPasswords))
+	}
+
+	rules := []PasswordRule{
+		MaxLengthRule{Max: 128},
+		MinEntropyRule{Bits: minEntropy},
+		breached,
+		SimilarityRule{},
+	}
+	if history > 0 {
+		rules = append(rules, ReuseRule{PasswordSvc: passwordSvc})
+	}
+	return NewPasswordPolicy(history, rules...), nil
+}
+
+func (p *PasswordPolicy) Validate(password string, user userpkg.User) error {
+	for _, rule := range p.rules {
+		if err := rule.Check(password, user); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *PasswordPolicy) HistorySize() int {
+	return p.history
+}
+
+// MaxLengthRule bounds the work spent hashing attacker-supplied input
+type MaxLengthRule struct {
+	Max int
+}
+
+func (r MaxLengthRule) Check(password string, user userpkg.User) error {
+	if len([]rune(password)) > r.Max {
+		return fmt.Errorf("password cannot be longer than %d characters", r.Max)
+	}
+	return nil
+}
+
+// MinEntropyRule estimates how hard a password is to brute-force from its
+// length and the kinds of characters in it. Long passphrases pass without
+// symbols or digits; repeats and runs like "aaaa" or "1234" count for little.
+type MinEntropyRule struct {
+	Bits float64
+}
+
+func (r MinEntropyRule) Check(password string, user userpkg.User) error {
+	if passwordEntropy(password) < r.Bits {
+		return errors.New("password is too easy to guess; use a longer password or a passphrase of several words")
+	}
+	return nil
+}
+
+func passwordEntropy(password string) float64 {
+	var lower, upper, digit, symbol, other bool
+	for _, c := range password {
+		switch {
+		case c >= 'a' && c <= 'z':
+			lower = true
+		case c >= 'A' && c <= 'Z':
+			upper = true
+		case c >= '0' && c <= '9':
+			digit = true
+		case c < unicode.MaxASCII && unicode.IsPrint(c):
+			symbol = true
+		default:
+			other = true
+		}
+	}
+	pool := 0
+	for _, class := range []struct {
+		present bool
+		size    int
+	}{{lower, 26}, {upper, 26}, {digit, 10}, {symbol, 33}, {other, 100}} {
+		if class.present {
+			pool += class.size
+		}
+	}
+	if pool == 0 {
+		return 0
+	}
+
+	length := 0.0
+	prev := rune(-1)
+	for _, c := range password {
+		if unicode.ToLower(c) == unicode.ToLower(prev) || c == prev+1 || c == prev-1 {
+			length += 0.25
+		} else {
+			length++
+		}
+		prev = c
+	}
+	return length * math.Log2(float64(pool))
+}
+
+// BreachedListRule rejects passwords from a list of known leaked passwords,
+// including decorated forms such as "Password1!" or "p@ssw0rd"
+type BreachedListRule struct {
+	passwords map[string]struct{}
+}
+
+// NewBreachedListRule reads one password per line; blank lines and lines
+// starting with # are skipped
+func NewBreachedListRule(list io.Reader) (*BreachedListRule, error) {
+	rule := &BreachedListRule{passwords: map[string]struct{}{}}
+	scanner := bufio.NewScanner(list)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		rule.passwords[strings.ToLower(line)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read breached password list: %w", err)
+	}
+	return rule, nil
+}
+
+func (r *BreachedListRule) Check(password string, user userpkg.User) error {
+	for _, candidate := range passwordVariants(password) {
+		if _, found := r.passwords[candidate]; found {
+			return errors.New("password appears in a list of leaked passwords; choose another")
+		}
+	}
+	return nil
+}
+
+var leetReplacer = strings.NewReplacer("@", "a", "4", "a", "3", "e", "1", "i", "!", "i", "0", "o", "$", "s", "5", "s", "7", "t")
+
+// passwordVariants returns the lowercased password along with its core once
+// leading and trailing digits and symbols are dropped and common character
+// substitutions undone
+func passwordVariants(password string) []string {
+	lower := strings.ToLower(password)
+	core := strings.TrimFunc(lower, func(c rune) bool { return !unicode.IsLetter(c) })
+	variants := []string{lower, leetReplacer.Replace(lower)}
+	// Only treat the ends as decoration when there is a word left in the middle
+	if len(core) >= 4 {
+		variants = append(variants, core, leetReplacer.Replace(core))
+	}
+	return variants
+}
+
+// SimilarityRule rejects passwords built from the user's own username, name
+// or email address
+type SimilarityRule struct{}
+
+func (SimilarityRule) Check(password string, user userpkg.User) error {
+	parts := []string{user.Username, user.DisplayName}
+	if at := strings.LastIndex(user.Email, "@"); at > 0 {
+		parts = append(parts, user.Email[:at])
+	}
+	parts = append(parts, strings.Fields(user.Fullname)...)
+
+	variants := passwordVariants(password)
+	for _, part := range parts {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if len(part) < 4 {
+			continue
+		}
+		for _, v := range variants {
+			if strings.Contains(v, part) {
+				return errors.New("password must not contain your username, name or email")
+			}
+		}
+	}
+	return nil
+}
+
+// ReuseRule rejects the current password and the previous ones kept in the
+// user's password history
+type ReuseRule struct {
+	PasswordSvc userpkg.IPasswordService
+}
+
+func (r ReuseRule) Check(password string, user userpkg.User) error {
+	if user.ID.IsZero() {
+		// Registering: there is nothing to reuse yet
+		return nil
+	}
+	for _, hash := range append([]string{user.Password}, user.PasswordHistory...) {
+		if hash != "" && r.PasswordSvc.ComparePassword(hash, password) == nil {
+			return errors.New("password was used recently; choose one you haven't used before")
+		}
+	}
+	return nil
+}
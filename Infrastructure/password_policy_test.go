@@ -0,0 +1,145 @@
+package infrastructure_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	infrastructure "github.com/Amaankaa/Blog-Starter-Project/Infrastructure"
+	"github.com/Amaankaa/Blog-Starter-Project/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMinEntropyRule(t *testing.T) {
+	rule := infrastructure.MinEntropyRule{Bits: 50}
+
+	for _, tc := range []struct {
+		password string
+		ok       bool
+	}{
+		{"correct horse battery staple", true},
+		{"Tr0ub4dor&3", true},
+		{"xkq7wmzpvhty", true},
+		{"xkq7wmzp", false},
+		{"password", false},
+		{"aaaaaaaaaaaaaaaaaaaa", false},
+		{"abcdefghijklmnop", false},
+		{"1234567890123456", false},
+		{"", false},
+	} {
+		t.Run(tc.password, func(t *testing.T) {
+			err := rule.Check(tc.password, userpkg.User{})
+			if tc.ok {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestBreachedListRule(t *testing.T) {
+	rule, err := infrastructure.NewBreachedListRule(strings.NewReader("# leaked\n\npassword\nDragon\n123456\n"))
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		password string
+		breached bool
+	}{
+		{"password", true},
+		{"PASSWORD", true},
+		{"dragon", true},
+		{"Password1!", true},
+		{"p@ssw0rd", true},
+		{"2024dragon!!", true},
+		{"123456", true},
+		{"# leaked", false},
+		{"passwordless login", false},
+		{"dra", false},
+		{"correct horse battery staple", false},
+	} {
+		t.Run(tc.password, func(t *testing.T) {
+			err := rule.Check(tc.password, userpkg.User{})
+			if tc.breached {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSimilarityRule(t *testing.T) {
+	user := userpkg.User{
+		Username:    "selamawit",
+		DisplayName: "Sel",
+		Email:       "s.tesfaye@example.com",
+		Fullname:    "Selamawit Tesfaye",
+	}
+
+	for _, tc := range []struct {
+		password string
+		similar  bool
+	}{
+		{"selamawit-rocks-2024", true},
+		{"I am S3lamawit!", true},
+		{"my s.tesfaye password", true},
+		{"tesfaye family farm", true},
+		{"Sel likes long walks", false}, // names under 4 characters are ignored
+		{"example.com is not my name", false},
+		{"correct horse battery staple", false},
+	} {
+		t.Run(tc.password, func(t *testing.T) {
+			err := infrastructure.SimilarityRule{}.Check(tc.password, user)
+			if tc.similar {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMaxLengthRule(t *testing.T) {
+	rule := infrastructure.MaxLengthRule{Max: 8}
+
+	assert.NoError(t, rule.Check("ééééééé", userpkg.User{}), "counted in characters, not bytes")
+	assert.Error(t, rule.Check("123456789", userpkg.User{}))
+}
+
+func TestReuseRule(t *testing.T) {
+	passwordSvc := new(mocks.IPasswordService)
+	passwordSvc.On("ComparePassword", "current", "old one").Return(assert.AnError)
+	passwordSvc.On("ComparePassword", "previous", "old one").Return(nil)
+	passwordSvc.On("ComparePassword", "current", "new one").Return(assert.AnError)
+	passwordSvc.On("ComparePassword", "previous", "new one").Return(assert.AnError)
+	rule := infrastructure.ReuseRule{PasswordSvc: passwordSvc}
+	user := userpkg.User{ID: primitive.NewObjectID(), Password: "current", PasswordHistory: []string{"previous"}}
+
+	assert.Error(t, rule.Check("old one", user))
+	assert.NoError(t, rule.Check("new one", user))
+	assert.NoError(t, rule.Check("old one", userpkg.User{}), "nothing to reuse when registering")
+}
+
+func TestNewPasswordPolicyFromEnv(t *testing.T) {
+	list := filepath.Join(t.TempDir(), "breached.txt")
+	require.NoError(t, os.WriteFile(list, []byte("correct horse battery staple\n"), 0o600))
+	t.Setenv("BREACHED_PASSWORDS_FILE", list)
+	t.Setenv("PASSWORD_MIN_ENTROPY", "70")
+	t.Setenv("PASSWORD_HISTORY", "0")
+
+	policy, err := infrastructure.NewPasswordPolicyFromEnv(nil)
+	require.NoError(t, err)
+
+	assert.Zero(t, policy.HistorySize())
+	assert.Error(t, policy.Validate("correct horse battery staple", userpkg.User{}), "from the configured list")
+	assert.Error(t, policy.Validate("xkq7wmzpvhty", userpkg.User{}), "62 bits is below the configured minimum")
+
+	t.Setenv("PASSWORD_HISTORY", "-1")
+	_, err = infrastructure.NewPasswordPolicyFromEnv(nil)
+	assert.Error(t, err)
+}
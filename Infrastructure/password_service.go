@@ -1,23 +1,187 @@
 package infrastructure
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
-type PasswordService struct{}
+const (
+	HashArgon2id = "argon2id"
+	HashBcrypt   = "bcrypt"
+)
+
+var errHashFormat = errors.New("unrecognized password hash format")
+
+// HashParams chooses the algorithm for new hashes and its cost. Memory is
+// in KiB.
+type HashParams struct {
+	Algorithm   string
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+	BcryptCost  int
+}
+
+// DefaultHashParams is argon2id with 64 MiB, 3 passes and 2 lanes
+func DefaultHashParams() HashParams {
+	return HashParams{
+		Algorithm:   HashArgon2id,
+		Memory:      64 * 1024,
+		Iterations:  3,
+		Parallelism: 2,
+		SaltLength:  16,
+		KeyLength:   32,
+		BcryptCost:  bcrypt.DefaultCost,
+	}
+}
+
+// PasswordService hashes passwords. Hashes carry their algorithm
+// and parameters (argon2id in PHC string format, bcrypt in its own "$2a$"
+// format), so hashes made under older settings keep verifying.
+type PasswordService struct {
+	params HashParams
+}
 
 func NewPasswordService() *PasswordService {
-	return &PasswordService{}
+	return NewPasswordServiceWithParams(DefaultHashParams())
+}
+
+func NewPasswordServiceWithParams(params HashParams) *PasswordService {
+	return &PasswordService{params: params}
+}
+
+// NewPasswordServiceFromEnv reads PASSWORD_HASH_ALGORITHM (argon2id or
+// bcrypt), ARGON2_MEMORY_KIB, ARGON2_ITERATIONS, ARGON2_PARALLELISM and
+// BCRYPT_COST over the defaults
+func NewPasswordServiceFromEnv() (*PasswordService, error) {
+	params := DefaultHashParams()
+	if alg := os.Getenv("PASSWORD_HASH_ALGORITHM"); alg != "" {
+		if alg != HashArgon2id && alg != HashBcrypt {
+			return nil, fmt.Errorf("invalid PASSWORD_HASH_ALGORITHM: %q", alg)
+		}
+		params.Algorithm = alg
+	}
+	for _, setting := range []struct {
+		name string
+		max  uint64
+		set  func(uint64)
+	}{
+		{"ARGON2_MEMORY_KIB", 4 * 1024 * 1024, func(v uint64) { params.Memory = uint32(v) }},
+		{"ARGON2_ITERATIONS", 100, func(v uint64) { params.Iterations = uint32(v) }},
+		{"ARGON2_PARALLELISM", 255, func(v uint64) { params.Parallelism = uint8(v) }},
+		{"BCRYPT_COST", uint64(bcrypt.MaxCost), func(v uint64) { params.BcryptCost = int(v) }},
+	} {
+		raw := os.Getenv(setting.name)
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil || v == 0 || v > setting.max {
+			return nil, fmt.Errorf("invalid %s: %q", setting.name, raw)
+		}
+		setting.set(v)
+	}
+	if params.BcryptCost < bcrypt.MinCost {
+		return nil, fmt.Errorf("invalid BCRYPT_COST: must be at least %d", bcrypt.MinCost)
+	}
+	return NewPasswordServiceWithParams(params), nil
 }
 
 func (ps *PasswordService) HashPassword(password string) (string, error) {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
+	if ps.params.Algorithm == HashBcrypt {
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), ps.params.BcryptCost)
+		if err != nil {
+			return "", err
+		}
+		return string(hashedPassword), nil
+	}
+
+	salt := make([]byte, ps.params.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
 		return "", err
 	}
-	return string(hashedPassword), nil
+	key := argon2.IDKey([]byte(password), salt, ps.params.Iterations, ps.params.Memory, ps.params.Parallelism, ps.params.KeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, ps.params.Memory, ps.params.Iterations, ps.params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
 }
 
 func (ps *PasswordService) ComparePassword(hashedPassword, password string) error {
+	if strings.HasPrefix(hashedPassword, "$argon2id$") {
+		hash, err := parseArgon2id(hashedPassword)
+		if err != nil {
+			return err
+		}
+		key := argon2.IDKey([]byte(password), hash.salt, hash.iterations, hash.memory, hash.parallelism, uint32(len(hash.key)))
+		if subtle.ConstantTimeCompare(key, hash.key) != 1 {
+			return bcrypt.ErrMismatchedHashAndPassword
+		}
+		return nil
+	}
 	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
-}
\ No newline at end of file
+}
+
+// NeedsRehash reports whether a hash was made with another algorithm or
+// other parameters than new hashes get
+func (ps *PasswordService) NeedsRehash(hashedPassword string) bool {
+	if ps.params.Algorithm == HashBcrypt {
+		cost, err := bcrypt.Cost([]byte(hashedPassword))
+		return err != nil || cost != ps.params.BcryptCost
+	}
+
+	hash, err := parseArgon2id(hashedPassword)
+	if err != nil {
+		return true
+	}
+	return hash.memory != ps.params.Memory ||
+		hash.iterations != ps.params.Iterations ||
+		hash.parallelism != ps.params.Parallelism ||
+		uint32(len(hash.salt)) != ps.params.SaltLength ||
+		uint32(len(hash.key)) != ps.params.KeyLength
+}
+
+type argon2idHash struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	salt        []byte
+	key         []byte
+}
+
+// parseArgon2id reads "$argon2id$v=19$m=65536,t=3,p=2$<salt>$<key>"
+func parseArgon2id(encoded string) (argon2idHash, error) {
+	var hash argon2idHash
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != HashArgon2id {
+		return hash, errHashFormat
+	}
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return hash, errHashFormat
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &hash.memory, &hash.iterations, &hash.parallelism); err != nil {
+		return hash, errHashFormat
+	}
+	if hash.iterations == 0 || hash.parallelism == 0 {
+		return hash, errHashFormat
+	}
+	var err error
+	if hash.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return hash, errHashFormat
+	}
+	if hash.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(hash.key) == 0 {
+		return hash, errHashFormat
+	}
+	return hash, nil
+}
@@ -0,0 +1,113 @@
+package infrastructure_test
+
+import (
+	"strings"
+	"testing"
+
+	infrastructure "github.com/Amaankaa/Blog-Starter-Project/Infrastructure"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// cheapArgon2id keeps the tests fast; the format is the same at any cost
+func cheapArgon2id() infrastructure.HashParams {
+	params := infrastructure.DefaultHashParams()
+	params.Memory = 64
+	params.Iterations = 1
+	params.Parallelism = 1
+	return params
+}
+
+func TestPasswordService_Argon2id(t *testing.T) {
+	ps := infrastructure.NewPasswordServiceWithParams(cheapArgon2id())
+
+	hash, err := ps.HashPassword("correct horse")
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$"), hash)
+	assert.NoError(t, ps.ComparePassword(hash, "correct horse"))
+	assert.ErrorIs(t, ps.ComparePassword(hash, "wrong horse"), bcrypt.ErrMismatchedHashAndPassword)
+
+	// Salted: the same password never hashes the same way twice
+	again, err := ps.HashPassword("correct horse")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, again)
+}
+
+func TestPasswordService_VerifiesBcryptHashes(t *testing.T) {
+	legacy, err := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	require.NoError(t, err)
+	ps := infrastructure.NewPasswordServiceWithParams(cheapArgon2id())
+
+	assert.NoError(t, ps.ComparePassword(string(legacy), "correct horse"))
+	assert.Error(t, ps.ComparePassword(string(legacy), "wrong horse"))
+	assert.True(t, ps.NeedsRehash(string(legacy)))
+}
+
+func TestPasswordService_NeedsRehash(t *testing.T) {
+	current := infrastructure.NewPasswordServiceWithParams(cheapArgon2id())
+	hash, err := current.HashPassword("pw")
+	require.NoError(t, err)
+	assert.False(t, current.NeedsRehash(hash))
+
+	stronger := cheapArgon2id()
+	stronger.Iterations = 2
+	assert.True(t, infrastructure.NewPasswordServiceWithParams(stronger).NeedsRehash(hash))
+
+	bcryptParams := cheapArgon2id()
+	bcryptParams.Algorithm = infrastructure.HashBcrypt
+	bcryptParams.BcryptCost = bcrypt.MinCost
+	bcryptSvc := infrastructure.NewPasswordServiceWithParams(bcryptParams)
+	assert.True(t, bcryptSvc.NeedsRehash(hash))
+	bcryptHash, err := bcryptSvc.HashPassword("pw")
+	require.NoError(t, err)
+	assert.False(t, bcryptSvc.NeedsRehash(bcryptHash))
+	assert.NoError(t, current.ComparePassword(bcryptHash, "pw"))
+}
+
+func TestPasswordService_MalformedArgon2id(t *testing.T) {
+	ps := infrastructure.NewPasswordServiceWithParams(cheapArgon2id())
+	hash, err := ps.HashPassword("pw")
+	require.NoError(t, err)
+	parts := strings.Split(hash, "$")
+
+	for name, malformed := range map[string]string{
+		"missing key":   strings.Join(parts[:5], "$"),
+		"other version": strings.Replace(hash, "v=19", "v=16", 1),
+		"bad params":    strings.Replace(hash, "m=64,t=1,p=1", "m=64,t=x,p=1", 1),
+		"zero passes":   strings.Replace(hash, "t=1", "t=0", 1),
+		"zero lanes":    strings.Replace(hash, "p=1", "p=0", 1),
+		"bad salt":      strings.Replace(hash, parts[4], "!!", 1),
+		"empty key":     strings.Join(append(parts[:5:5], ""), "$"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, ps.ComparePassword(malformed, "pw"))
+			assert.True(t, ps.NeedsRehash(malformed))
+		})
+	}
+}
+
+func TestNewPasswordServiceFromEnv(t *testing.T) {
+	t.Setenv("PASSWORD_HASH_ALGORITHM", "scrypt")
+	_, err := infrastructure.NewPasswordServiceFromEnv()
+	assert.Error(t, err)
+
+	t.Setenv("PASSWORD_HASH_ALGORITHM", "")
+	t.Setenv("ARGON2_ITERATIONS", "0")
+	_, err = infrastructure.NewPasswordServiceFromEnv()
+	assert.Error(t, err)
+
+	t.Setenv("ARGON2_ITERATIONS", "")
+	t.Setenv("PASSWORD_HASH_ALGORITHM", infrastructure.HashBcrypt)
+	t.Setenv("BCRYPT_COST", "2")
+	_, err = infrastructure.NewPasswordServiceFromEnv()
+	assert.Error(t, err)
+
+	t.Setenv("BCRYPT_COST", "4")
+	ps, err := infrastructure.NewPasswordServiceFromEnv()
+	require.NoError(t, err)
+	hash, err := ps.HashPassword("pw")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hash, "$2a$04$"), hash)
+}
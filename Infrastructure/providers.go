@@ -0,0 +1,168 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"time"
+
+	emailpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/email"
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+)
+
+// Provider modes selectable with PROVIDERS
+const (
+	ProvidersRemote = "remote" // the real third-party services
+	ProvidersLocal  = "local"  // on-disk and in-process fakes, no network or credentials needed
+)
+
+// Routes the local fakes are served from
+const (
+	LocalUploadsPath = "/dev/uploads"
+	LocalInboxPath   = "/dev/inbox"
+)
+
+// Providers are the external services the app depends on
+type Providers struct {
+	Mode           string
+	Images         userpkg.ICloudinaryService
+	EmailVerifier  services.IEmailVerifier
+	EmailTransport services.IEmailTransport
+	// Not used by any feature yet; the client is built so its configuration is checked
+	AI services.IAIClient
+
+	// Local mode only: images to serve at LocalUploadsPath, and the emails
+	// caught instead of being sent
+	UploadsDir string
+	Inbox      services.IEmailInbox
+}
+
+// NewProvidersFromEnv builds every provider for the mode in PROVIDERS,
+// "remote" by default. Remote mode needs the Cloudinary, email transport and
+// Gemini settings; local mode keeps everything under LOCAL_DATA_DIR
+// (".local" by default) and links to it from publicURL. Email verification
+// consults domainRules, the admin allow and deny lists, when it is set.
+//
+// Local mode is refused when GIN_MODE is "release": its inbox, served without
+// authentication, would hand anyone every account's codes and links.
+func NewProvidersFromEnv(publicURL string, domainRules emailpkg.IDomainRuleRepository) (*Providers, error) {
+	switch mode := os.Getenv("PROVIDERS"); mode {
+	case "", ProvidersRemote:
+		return newRemoteProviders(domainRules)
+	case ProvidersLocal:
+		if os.Getenv("GIN_MODE") == "release" {
+			return nil, errors.New("PROVIDERS=local is for development and cannot be used with GIN_MODE=release")
+		}
+		dir := os.Getenv("LOCAL_DATA_DIR")
+		if dir == "" {
+			dir = ".local"
+		}
+		return NewLocalProviders(dir, publicURL, domainRules)
+	default:
+		return nil, fmt.Errorf("invalid PROVIDERS: %q", mode)
+	}
+}
+
+func newRemoteProviders(domainRules emailpkg.IDomainRuleRepository) (*Providers, error) {
+	cloudName := os.Getenv("CLOUDINARY_CLOUD_NAME")
+	cloudAPIKey := os.Getenv("CLOUDINARY_API_KEY")
+	cloudAPISecret := os.Getenv("CLOUDINARY_API_SECRET")
+	if cloudName == "" || cloudAPIKey == "" || cloudAPISecret == "" {
+		return nil, errors.New("Cloudinary credentials not set in environment")
+	}
+	images, err := NewCloudinaryService(cloudName, cloudAPIKey, cloudAPISecret)
+	if err != nil {
+		return nil, fmt.Errorf("cloudinary: %w", err)
+	}
+
+	verifier, err := newEmailVerifierChain(domainRules, true)
+	if err != nil {
+		return nil, fmt.Errorf("email verifier: %w", err)
+	}
+
+	transport, err := NewEmailTransportFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("email transport: %w", err)
+	}
+
+	aiAPIKey := os.Getenv("GEMINI_API_KEY")
+	if aiAPIKey == "" {
+		return nil, errors.New("GEMINI_API_KEY not set in environment")
+	}
+	aiAPIURL := os.Getenv("GEMINI_API_URL")
+	if aiAPIURL == "" {
+		return nil, errors.New("GEMINI_API_URL not set in environment")
+	}
+
+	return &Providers{
+		Mode:           ProvidersRemote,
+		Images:         images,
+		EmailVerifier:  verifier,
+		EmailTransport: transport,
+		AI:             NewGeminiClient(aiAPIKey, aiAPIURL),
+	}, nil
+}
+
+// NewLocalProviders keeps uploads in dir/uploads and emails in dir/mail.
+// Email verification makes no network calls.
+func NewLocalProviders(dir, publicURL string, domainRules emailpkg.IDomainRuleRepository) (*Providers, error) {
+	uploadsDir := filepath.Join(dir, "uploads")
+	images, err := NewDiskImageStore(uploadsDir, publicURL+LocalUploadsPath)
+	if err != nil {
+		return nil, fmt.Errorf("local uploads: %w", err)
+	}
+	mailbox, err := NewFileEmailTransport(filepath.Join(dir, "mail"), mail.Address{Name: "ShareSpace", Address: "no-reply@localhost"})
+	if err != nil {
+		return nil, fmt.Errorf("local mail: %w", err)
+	}
+	verifier, err := newEmailVerifierChain(domainRules, false)
+	if err != nil {
+		return nil, fmt.Errorf("email verifier: %w", err)
+	}
+
+	return &Providers{
+		Mode:           ProvidersLocal,
+		Images:         images,
+		EmailVerifier:  verifier,
+		EmailTransport: mailbox,
+		AI:             NewStubAIClient(),
+		UploadsDir:     uploadsDir,
+		Inbox:          mailbox,
+	}, nil
+}
+
+// newEmailVerifierChain checks the syntax, then the admin domain rules and the
+// disposable domain list. With network on it also looks up MX records,
+// unless EMAIL_VERIFY_MX=false, and finally asks EmailListVerify when
+// EMAILLISTVERIFY_API_KEY is set.
+func newEmailVerifierChain(domainRules emailpkg.IDomainRuleRepository, network bool) (*EmailVerifierChain, error) {
+	chain := NewEmailVerifierChain()
+	if domainRules != nil {
+		// Short, so admin changes apply within a minute on every instance
+		chain.Then(NewDomainRuleCheck(domainRules), time.Minute)
+	}
+	disposable, err := NewDisposableDomainCheckFromEnv(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	chain.Then(disposable, 10*time.Minute)
+	if !network {
+		return chain, nil
+	}
+
+	if os.Getenv("EMAIL_VERIFY_MX") != "false" {
+		chain.Then(NewMXCheck(nil), time.Hour)
+	}
+	if os.Getenv("EMAILLISTVERIFY_API_KEY") != "" {
+		remote, err := NewEmailListVerifyVerifier()
+		if err != nil {
+			return nil, err
+		}
+		chain.ThenRemote(remote, 24*time.Hour)
+	}
+	return chain, nil
+}
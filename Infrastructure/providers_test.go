@@ -0,0 +1,113 @@
+package infrastructure_test
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+	infrastructure "github.com/Amaankaa/Blog-Starter-Project/Infrastructure"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memFile is an in-memory multipart.File
+type memFile struct{ *bytes.Reader }
+
+func (memFile) Close() error { return nil }
+
+func TestLocalProviders_CatchEmailsInInbox(t *testing.T) {
+	providers, err := infrastructure.NewLocalProviders(t.TempDir(), "http://localhost:8080", nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, providers.EmailTransport.Deliver(ctx, "a@example.com", testEmail))
+
+	emails, err := providers.Inbox.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, emails, 1)
+	assert.Equal(t, "a@example.com", emails[0].To)
+	assert.Equal(t, testEmail.Subject, emails[0].Subject)
+	assert.Empty(t, emails[0].Text, "listing leaves bodies out")
+
+	email, raw, err := providers.Inbox.Get(ctx, emails[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, testEmail.Text, email.Text)
+	assert.Equal(t, testEmail.HTML, email.HTML)
+	assert.Contains(t, string(raw), "multipart/alternative")
+
+	_, _, err = providers.Inbox.Get(ctx, "../"+emails[0].ID)
+	assert.ErrorIs(t, err, services.ErrInboxEmailNotFound)
+
+	require.NoError(t, providers.Inbox.Clear(ctx))
+	emails, err = providers.Inbox.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, emails)
+}
+
+func TestLocalProviders_StoreImagesOnDisk(t *testing.T) {
+	providers, err := infrastructure.NewLocalProviders(t.TempDir(), "http://localhost:8080", nil)
+	require.NoError(t, err)
+
+	var img bytes.Buffer
+	pic := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	pic.Set(0, 0, color.White)
+	require.NoError(t, png.Encode(&img, pic))
+
+	url, err := providers.Images.UploadImage(context.Background(), memFile{bytes.NewReader(img.Bytes())}, "../../me.png")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(url, "http://localhost:8080"+infrastructure.LocalUploadsPath+"/profile_pictures/"), url)
+	assert.True(t, strings.HasSuffix(url, "-me.png"), url)
+
+	saved, err := os.ReadFile(filepath.Join(providers.UploadsDir, "profile_pictures", filepath.Base(url)))
+	require.NoError(t, err)
+	assert.Equal(t, img.Bytes(), saved)
+
+	_, err = providers.Images.UploadImage(context.Background(), memFile{bytes.NewReader([]byte("#!/bin/sh\n"))}, "x.png")
+	assert.Error(t, err)
+}
+
+func TestLocalProviders_EmailVerifierAndAI(t *testing.T) {
+	providers, err := infrastructure.NewLocalProviders(t.TempDir(), "http://localhost:8080", nil)
+	require.NoError(t, err)
+
+	for email, want := range map[string]bool{
+		"student@aau.edu.et":      true,
+		"someone@example.invalid": false,
+		"not an email":            false,
+	} {
+		ok, err := providers.EmailVerifier.IsRealEmail(email)
+		assert.NoError(t, err)
+		assert.Equal(t, want, ok, email)
+	}
+
+	answer, err := providers.AI.Generate(context.Background(), "Summarize this post")
+	require.NoError(t, err)
+	assert.Contains(t, answer, "Summarize this post")
+}
+
+func TestNewProvidersFromEnv_RejectsUnknownMode(t *testing.T) {
+	t.Setenv("PROVIDERS", "staging")
+	_, err := infrastructure.NewProvidersFromEnv("http://localhost:8080", nil)
+	assert.Error(t, err)
+}
+
+func TestProvidersFromEnv_LocalRefusedInRelease(t *testing.T) {
+	t.Setenv("PROVIDERS", infrastructure.ProvidersLocal)
+	t.Setenv("LOCAL_DATA_DIR", t.TempDir())
+	t.Setenv("GIN_MODE", "release")
+
+	_, err := infrastructure.NewProvidersFromEnv("http://localhost:8080", nil)
+	assert.Error(t, err)
+
+	t.Setenv("GIN_MODE", "debug")
+	providers, err := infrastructure.NewProvidersFromEnv("http://localhost:8080", nil)
+	require.NoError(t, err)
+	assert.Equal(t, infrastructure.ProvidersLocal, providers.Mode)
+}
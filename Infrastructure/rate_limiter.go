@@ -1,19 +1,98 @@
 package infrastructure
 
 import (
+	"context"
+	"fmt"
+	"log"
 	"net/http"
-	// "time"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"golang.org/x/time/rate"
+	"github.com/redis/go-redis/v9"
 )
 
-// rateLimitMiddleware is a simple IP-based rate limiter for Gin.
-func RateLimitMiddleware(limit rate.Limit, burst int) gin.HandlerFunc {
-	limiter := rate.NewLimiter(limit, burst)
+// RateLimitResult is the state of one key's window after a request was counted
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	Reset     time.Duration // until the window starts over
+}
+
+// RateLimitStore counts requests per key in fixed windows
+type RateLimitStore interface {
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (RateLimitResult, error)
+}
+
+// RateLimitKeyFunc picks the subject a request is counted against
+type RateLimitKeyFunc func(c *gin.Context) string
+
+// KeyByIP counts requests per client IP
+func KeyByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// KeyByUser counts requests per authenticated user, falling back to the
+// client IP on routes without a user.
+func KeyByUser(c *gin.Context) string {
+	if userID := c.GetString("user_id"); userID != "" {
+		return "user:" + userID
+	}
+	return KeyByIP(c)
+}
+
+// RateLimitPolicy is a limit applied to a route group
+type RateLimitPolicy struct {
+	Name     string
+	Limit    int
+	Window   time.Duration
+	Key      RateLimitKeyFunc
+	PerRoute bool // count each route separately instead of the group as a whole
+}
+
+// RateLimiter enforces policies against a shared store. Store failures let
+// requests through so an outage of the store cannot take the API down.
+type RateLimiter struct {
+	store RateLimitStore
+}
+
+func NewRateLimiter(store RateLimitStore) *RateLimiter {
+	return &RateLimiter{store: store}
+}
+
+// Limit returns middleware enforcing policy. It sets the RateLimit-* headers
+// from the IETF draft and Retry-After on 429 responses.
+func (rl *RateLimiter) Limit(policy RateLimitPolicy) gin.HandlerFunc {
+	keyFunc := policy.Key
+	if keyFunc == nil {
+		keyFunc = KeyByIP
+	}
+	policyHeader := fmt.Sprintf("%d;w=%d", policy.Limit, int(policy.Window.Seconds()))
 
 	return func(c *gin.Context) {
-		if !limiter.Allow() {
+		key := "ratelimit:" + policy.Name + ":"
+		if policy.PerRoute {
+			key += c.Request.Method + " " + c.FullPath() + ":"
+		}
+		key += keyFunc(c)
+
+		result, err := rl.store.Allow(c.Request.Context(), key, policy.Limit, policy.Window)
+		if err != nil {
+			log.Printf("rate limit: store error for %s: %v", policy.Name, err)
+			c.Next()
+			return
+		}
+
+		reset := strconv.Itoa(int((result.Reset + time.Second - 1) / time.Second))
+		c.Header("RateLimit-Policy", policyHeader)
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", reset)
+
+		if !result.Allowed {
+			c.Header("Retry-After", reset)
 			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests. Please try again later."})
 			return
 		}
@@ -21,11 +100,88 @@ func RateLimitMiddleware(limit rate.Limit, burst int) gin.HandlerFunc {
 	}
 }
 
-func NewRateLimiter(r float64, b int) gin.HandlerFunc {
-	return RateLimitMiddleware(rate.Limit(r), b)
+// InMemoryRateLimitStore keeps windows in process memory. Limits are per
+// instance, so it suits a single replica or local development.
+type InMemoryRateLimitStore struct {
+	mu        sync.Mutex
+	windows   map[string]*rateWindow
+	lastSweep time.Time
 }
 
-const (
-	RateLimit  = 1
-	BurstLimit = 5
-)
+type rateWindow struct {
+	count   int
+	resetAt time.Time
+}
+
+func NewInMemoryRateLimitStore() *InMemoryRateLimitStore {
+	return &InMemoryRateLimitStore{windows: make(map[string]*rateWindow)}
+}
+
+func (s *InMemoryRateLimitStore) Allow(_ context.Context, key string, limit int, window time.Duration) (RateLimitResult, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Drop finished windows now and then so idle keys do not pile up
+	if now.Sub(s.lastSweep) > time.Minute {
+		for k, w := range s.windows {
+			if !now.Before(w.resetAt) {
+				delete(s.windows, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	w, ok := s.windows[key]
+	if !ok || !now.Before(w.resetAt) {
+		w = &rateWindow{resetAt: now.Add(window)}
+		s.windows[key] = w
+	}
+	w.count++
+
+	return windowResult(w.count, limit, w.resetAt.Sub(now)), nil
+}
+
+// RedisRateLimitStore shares windows across every API instance
+type RedisRateLimitStore struct {
+	client *redis.Client
+}
+
+func NewRedisRateLimitStore(client *redis.Client) *RedisRateLimitStore {
+	return &RedisRateLimitStore{client: client}
+}
+
+// The first hit of a window sets its expiry; INCR and PEXPIRE run atomically
+var rateLimitScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if count == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return {count, redis.call("PTTL", KEYS[1])}
+`)
+
+func (s *RedisRateLimitStore) Allow(ctx context.Context, key string, limit int, window time.Duration) (RateLimitResult, error) {
+	values, err := rateLimitScript.Run(ctx, s.client, []string{key}, window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+	ttl := time.Duration(values[1]) * time.Millisecond
+	if ttl < 0 {
+		ttl = window
+	}
+	return windowResult(int(values[0]), limit, ttl), nil
+}
+
+func windowResult(count, limit int, reset time.Duration) RateLimitResult {
+	remaining := limit - count
+	if remaining < 0 {
+		remaining = 0
+	}
+	return RateLimitResult{
+		Allowed:   count <= limit,
+		Limit:     limit,
+		Remaining: remaining,
+		Reset:     reset,
+	}
+}
@@ -0,0 +1,146 @@
+package infrastructure_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	infrastructure "github.com/Amaankaa/Blog-Starter-Project/Infrastructure"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryRateLimitStore_Window(t *testing.T) {
+	store := infrastructure.NewInMemoryRateLimitStore()
+	ctx := context.Background()
+
+	for i := 1; i <= 3; i++ {
+		result, err := store.Allow(ctx, "k", 3, time.Minute)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 3, result.Limit)
+		assert.Equal(t, 3-i, result.Remaining)
+		assert.InDelta(t, time.Minute.Seconds(), result.Reset.Seconds(), 1)
+	}
+
+	result, err := store.Allow(ctx, "k", 3, time.Minute)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Zero(t, result.Remaining)
+
+	// Other keys have their own window
+	result, err = store.Allow(ctx, "other", 3, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Remaining)
+}
+
+func TestInMemoryRateLimitStore_WindowStartsOver(t *testing.T) {
+	store := infrastructure.NewInMemoryRateLimitStore()
+	ctx := context.Background()
+
+	_, _ = store.Allow(ctx, "k", 1, 20*time.Millisecond)
+	result, _ := store.Allow(ctx, "k", 1, 20*time.Millisecond)
+	require.False(t, result.Allowed)
+
+	time.Sleep(30 * time.Millisecond)
+	result, err := store.Allow(ctx, "k", 1, 20*time.Millisecond)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Zero(t, result.Remaining)
+}
+
+type failingRateLimitStore struct{}
+
+func (failingRateLimitStore) Allow(context.Context, string, int, time.Duration) (infrastructure.RateLimitResult, error) {
+	return infrastructure.RateLimitResult{}, errors.New("redis down")
+}
+
+func newLimitedRouter(t *testing.T, store infrastructure.RateLimitStore, policy infrastructure.RateLimitPolicy, trustedProxies []string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	require.NoError(t, r.SetTrustedProxies(trustedProxies))
+	limited := r.Group("", infrastructure.NewRateLimiter(store).Limit(policy))
+	ok := func(c *gin.Context) { c.Status(http.StatusNoContent) }
+	limited.GET("/a", ok)
+	limited.GET("/b", ok)
+	return r
+}
+
+func send(r *gin.Engine, path, remoteAddr, forwardedFor string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = remoteAddr
+	if forwardedFor != "" {
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimiter_HeadersAndTooManyRequests(t *testing.T) {
+	policy := infrastructure.RateLimitPolicy{Name: "test", Limit: 2, Window: time.Minute}
+	r := newLimitedRouter(t, infrastructure.NewInMemoryRateLimitStore(), policy, nil)
+
+	w := send(r, "/a", "198.51.100.1:1234", "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "2;w=60", w.Header().Get("RateLimit-Policy"))
+	assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "60", w.Header().Get("RateLimit-Reset"))
+
+	send(r, "/b", "198.51.100.1:1234", "")
+	w = send(r, "/a", "198.51.100.1:1234", "")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "60", w.Header().Get("Retry-After"))
+
+	// Another client is unaffected
+	w = send(r, "/a", "198.51.100.2:1234", "")
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestRateLimiter_PerRoute(t *testing.T) {
+	policy := infrastructure.RateLimitPolicy{Name: "test", Limit: 1, Window: time.Minute, PerRoute: true}
+	r := newLimitedRouter(t, infrastructure.NewInMemoryRateLimitStore(), policy, nil)
+
+	assert.Equal(t, http.StatusNoContent, send(r, "/a", "198.51.100.1:1234", "").Code)
+	assert.Equal(t, http.StatusNoContent, send(r, "/b", "198.51.100.1:1234", "").Code)
+	assert.Equal(t, http.StatusTooManyRequests, send(r, "/a", "198.51.100.1:1234", "").Code)
+}
+
+func TestRateLimiter_StoreErrorLetsRequestsThrough(t *testing.T) {
+	policy := infrastructure.RateLimitPolicy{Name: "test", Limit: 1, Window: time.Minute}
+	r := newLimitedRouter(t, failingRateLimitStore{}, policy, nil)
+
+	w := send(r, "/a", "198.51.100.1:1234", "")
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+}
+
+func TestRateLimiter_ForwardedForOnlyFromTrustedProxies(t *testing.T) {
+	policy := infrastructure.RateLimitPolicy{Name: "test", Limit: 1, Window: time.Minute}
+
+	// Without trusted proxies a client cannot get a fresh bucket per request
+	r := newLimitedRouter(t, infrastructure.NewInMemoryRateLimitStore(), policy, nil)
+	assert.Equal(t, http.StatusNoContent, send(r, "/a", "198.51.100.1:1234", "203.0.113.1").Code)
+	assert.Equal(t, http.StatusTooManyRequests, send(r, "/a", "198.51.100.1:1234", "203.0.113.2").Code)
+
+	// Behind a trusted load balancer each forwarded client counts separately
+	r = newLimitedRouter(t, infrastructure.NewInMemoryRateLimitStore(), policy, []string{"10.0.0.0/8"})
+	assert.Equal(t, http.StatusNoContent, send(r, "/a", "10.0.0.5:1234", "203.0.113.1").Code)
+	assert.Equal(t, http.StatusNoContent, send(r, "/a", "10.0.0.5:1234", "203.0.113.2").Code)
+	assert.Equal(t, http.StatusTooManyRequests, send(r, "/a", "10.0.0.5:1234", "203.0.113.1").Code)
+}
+
+func TestTrustedProxiesFromEnv(t *testing.T) {
+	t.Setenv("TRUSTED_PROXIES", "")
+	assert.Nil(t, infrastructure.TrustedProxiesFromEnv())
+
+	t.Setenv("TRUSTED_PROXIES", " 10.0.0.0/8, 192.0.2.10 ,")
+	assert.Equal(t, []string{"10.0.0.0/8", "192.0.2.10"}, infrastructure.TrustedProxiesFromEnv())
+}
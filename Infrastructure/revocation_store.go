@@ -0,0 +1,75 @@
+package infrastructure
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+)
+
+// InMemoryRevocationStore keeps revocations in process memory. It suits a
+// single instance or local development; use the Mongo store when running
+// several replicas.
+type InMemoryRevocationStore struct {
+	mu sync.Mutex
+	// jti -> when the token would have expired anyway
+	tokens map[string]time.Time
+	// userID -> tokens issued before this instant are rejected
+	cutoffs map[string]time.Time
+}
+
+func NewInMemoryRevocationStore() *InMemoryRevocationStore {
+	return &InMemoryRevocationStore{
+		tokens:  map[string]time.Time{},
+		cutoffs: map[string]time.Time{},
+	}
+}
+
+func (s *InMemoryRevocationStore) RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[tokenID] = expiresAt
+	return nil
+}
+
+func (s *InMemoryRevocationStore) RevokeUserTokens(ctx context.Context, userID string, issuedBefore time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// iat has second precision, so compare at that precision too
+	cutoff := issuedBefore.Truncate(time.Second)
+	if cutoff.After(s.cutoffs[userID]) {
+		s.cutoffs[userID] = cutoff
+	}
+	return nil
+}
+
+func (s *InMemoryRevocationStore) IsRevoked(ctx context.Context, tokenID, userID string, issuedAt time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pruneLocked(time.Now())
+
+	if tokenID != "" {
+		if _, ok := s.tokens[tokenID]; ok {
+			return true, nil
+		}
+	}
+	if cutoff, ok := s.cutoffs[userID]; ok && issuedAt.Before(cutoff) {
+		return true, nil
+	}
+	return false, nil
+}
+
+// pruneLocked drops entries that can no longer match a live token
+func (s *InMemoryRevocationStore) pruneLocked(now time.Time) {
+	for id, exp := range s.tokens {
+		if now.After(exp) {
+			delete(s.tokens, id)
+		}
+	}
+	for id, cutoff := range s.cutoffs {
+		if now.After(cutoff.Add(userpkg.AccessTokenTTL)) {
+			delete(s.cutoffs, id)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package infrastructure_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	infrastructure "github.com/Amaankaa/Blog-Starter-Project/Infrastructure"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryRevocationStore_RevokesTokenByID(t *testing.T) {
+	store := infrastructure.NewInMemoryRevocationStore()
+	ctx := context.Background()
+	issued := time.Now()
+
+	require.NoError(t, store.RevokeToken(ctx, "jti-1", issued.Add(time.Minute)))
+
+	revoked, err := store.IsRevoked(ctx, "jti-1", "user1", issued)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = store.IsRevoked(ctx, "jti-2", "user1", issued)
+	require.NoError(t, err)
+	assert.False(t, revoked, "other tokens of the user still work")
+}
+
+func TestInMemoryRevocationStore_UserCutoff(t *testing.T) {
+	store := infrastructure.NewInMemoryRevocationStore()
+	ctx := context.Background()
+	cutoff := time.Now().Truncate(time.Second)
+
+	require.NoError(t, store.RevokeUserTokens(ctx, "user1", cutoff.Add(500*time.Millisecond)))
+
+	revoked, _ := store.IsRevoked(ctx, "a", "user1", cutoff.Add(-time.Second))
+	assert.True(t, revoked, "issued before the cutoff")
+	revoked, _ = store.IsRevoked(ctx, "b", "user1", cutoff)
+	assert.False(t, revoked, "issued in the cutoff's second, at iat precision")
+	revoked, _ = store.IsRevoked(ctx, "c", "user2", cutoff.Add(-time.Second))
+	assert.False(t, revoked, "other users are unaffected")
+
+	// An older cutoff arriving late does not move the cutoff back
+	require.NoError(t, store.RevokeUserTokens(ctx, "user1", cutoff.Add(-time.Minute)))
+	revoked, _ = store.IsRevoked(ctx, "a", "user1", cutoff.Add(-time.Second))
+	assert.True(t, revoked)
+}
+
+func TestInMemoryRevocationStore_PrunesExpiredEntries(t *testing.T) {
+	store := infrastructure.NewInMemoryRevocationStore()
+	ctx := context.Background()
+	now := time.Now()
+
+	// A token that has expired cannot be presented, so its entry is dropped
+	require.NoError(t, store.RevokeToken(ctx, "jti-1", now.Add(-time.Second)))
+	revoked, err := store.IsRevoked(ctx, "jti-1", "user1", now.Add(-time.Minute))
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	// Likewise a cutoff older than any token still within its lifetime
+	old := now.Add(-userpkg.AccessTokenTTL - time.Minute)
+	require.NoError(t, store.RevokeUserTokens(ctx, "user1", old))
+	revoked, err = store.IsRevoked(ctx, "jti-2", "user1", old.Add(-time.Minute))
+	require.NoError(t, err)
+	assert.False(t, revoked)
+}
@@ -0,0 +1,98 @@
+package infrastructure_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	infrastructure "github.com/Amaankaa/Blog-Starter-Project/Infrastructure"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rfc6238Secret is the SHA-1 test key of RFC 6238, "12345678901234567890", in base32
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPService_RFC6238Vectors(t *testing.T) {
+	totp := infrastructure.NewTOTPService()
+
+	// The RFC's 8-digit values, cut to the 6 digits apps show
+	for unix, code := range map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	} {
+		step, ok := totp.ValidateCode(rfc6238Secret, code, time.Unix(unix, 0))
+		assert.True(t, ok, "T=%d", unix)
+		assert.Equal(t, unix/30, step, "T=%d", unix)
+	}
+}
+
+func TestTOTPService_SkewWindow(t *testing.T) {
+	totp := infrastructure.NewTOTPService()
+	issued := time.Unix(1111111111, 0) // step 37037037, code 050471
+
+	for _, tc := range []struct {
+		name string
+		at   time.Time
+		ok   bool
+	}{
+		{"same step", issued, true},
+		{"one step late", issued.Add(30 * time.Second), true},
+		{"one step early", issued.Add(-30 * time.Second), true},
+		{"two steps late", issued.Add(60 * time.Second), false},
+		{"two steps early", issued.Add(-60 * time.Second), false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			step, ok := totp.ValidateCode(rfc6238Secret, "050471", tc.at)
+			assert.Equal(t, tc.ok, ok)
+			if tc.ok {
+				// The step the code belongs to, for replay protection
+				assert.Equal(t, int64(37037037), step)
+			}
+		})
+	}
+}
+
+func TestTOTPService_RejectsMalformedInput(t *testing.T) {
+	totp := infrastructure.NewTOTPService()
+	at := time.Unix(59, 0)
+
+	_, ok := totp.ValidateCode(rfc6238Secret, "287 082", at)
+	assert.True(t, ok, "spaces are ignored")
+	_, ok = totp.ValidateCode(strings.ToLower(rfc6238Secret), "287082", at)
+	assert.True(t, ok, "the secret is case-insensitive")
+
+	for _, code := range []string{"", "28708", "2870820", "287083"} {
+		_, ok := totp.ValidateCode(rfc6238Secret, code, at)
+		assert.False(t, ok, code)
+	}
+	_, ok = totp.ValidateCode("not base32!", "287082", at)
+	assert.False(t, ok)
+}
+
+func TestTOTPService_SecretAndProvisioningURI(t *testing.T) {
+	t.Setenv("MFA_ISSUER", "Acme Blog")
+	totp := infrastructure.NewTOTPService()
+
+	secret, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	assert.Len(t, secret, 32, "160 bits in unpadded base32")
+	other, err := totp.GenerateSecret()
+	require.NoError(t, err)
+	assert.NotEqual(t, secret, other)
+
+	uri, err := url.Parse(totp.ProvisioningURI(secret, "alice@example.com"))
+	require.NoError(t, err)
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/Acme Blog:alice@example.com", uri.Path)
+	assert.Equal(t, secret, uri.Query().Get("secret"))
+	assert.Equal(t, "Acme Blog", uri.Query().Get("issuer"))
+	assert.Equal(t, "6", uri.Query().Get("digits"))
+	assert.Equal(t, "30", uri.Query().Get("period"))
+}
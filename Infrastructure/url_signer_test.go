@@ -0,0 +1,53 @@
+package infrastructure_test
+
+import (
+	"testing"
+	"time"
+
+	infrastructure "github.com/Amaankaa/Blog-Starter-Project/Infrastructure"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestURLSigner_SignAndVerify(t *testing.T) {
+	signer := infrastructure.NewURLSigner([]byte("test-key"))
+	expiresAt := time.Now().Add(time.Hour)
+
+	signature := signer.Sign("export:1", expiresAt)
+
+	assert.True(t, signer.Verify("export:1", expiresAt, signature))
+	assert.False(t, signer.Verify("export:2", expiresAt, signature), "another resource")
+	assert.False(t, signer.Verify("export:1", expiresAt.Add(time.Hour), signature), "a later expiry")
+	assert.False(t, signer.Verify("export:1", expiresAt, signature[1:]), "a damaged signature")
+	assert.False(t, infrastructure.NewURLSigner([]byte("other-key")).Verify("export:1", expiresAt, signature))
+}
+
+func TestURLSigner_Expiry(t *testing.T) {
+	signer := infrastructure.NewURLSigner([]byte("test-key"))
+	expired := time.Now().Add(-time.Second)
+
+	assert.False(t, signer.Verify("export:1", expired, signer.Sign("export:1", expired)))
+}
+
+func TestNewURLSignerFromEnv(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+
+	t.Setenv("URL_SIGNING_KEY", "")
+	_, err := infrastructure.NewURLSignerFromEnv(false)
+	assert.Error(t, err, "required outside local mode")
+
+	// Local mode makes up a key of its own
+	a, err := infrastructure.NewURLSignerFromEnv(true)
+	require.NoError(t, err)
+	b, err := infrastructure.NewURLSignerFromEnv(true)
+	require.NoError(t, err)
+	assert.True(t, a.Verify("r", expiresAt, a.Sign("r", expiresAt)))
+	assert.NotEqual(t, a.Sign("r", expiresAt), b.Sign("r", expiresAt))
+
+	t.Setenv("URL_SIGNING_KEY", "shared-key")
+	a, err = infrastructure.NewURLSignerFromEnv(false)
+	require.NoError(t, err)
+	b, err = infrastructure.NewURLSignerFromEnv(true)
+	require.NoError(t, err)
+	assert.True(t, b.Verify("r", expiresAt, a.Sign("r", expiresAt)), "replicas with the same key accept each other's links")
+}
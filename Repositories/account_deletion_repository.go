@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AccountDeletionRepository stores scheduled account deletions, one per user
+type AccountDeletionRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAccountDeletionRepository(collection *mongo.Collection) *AccountDeletionRepository {
+	return &AccountDeletionRepository{collection: collection}
+}
+
+func (r *AccountDeletionRepository) ScheduleDeletion(ctx context.Context, deletion userpkg.AccountDeletion) error {
+	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": deletion.UserID}, deletion, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (r *AccountDeletionRepository) GetDeletion(ctx context.Context, userID string) (userpkg.AccountDeletion, error) {
+	oid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return userpkg.AccountDeletion{}, err
+	}
+	var deletion userpkg.AccountDeletion
+	err = r.collection.FindOne(ctx, bson.M{"_id": oid}).Decode(&deletion)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return userpkg.AccountDeletion{}, userpkg.ErrDeletionNotFound
+	}
+	return deletion, err
+}
+
+func (r *AccountDeletionRepository) CancelDeletion(ctx context.Context, userID string) error {
+	oid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+	res, err := r.collection.DeleteOne(ctx, bson.M{"_id": oid, "claimedAt": bson.M{"$exists": false}})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 1 {
+		return nil
+	}
+	if _, err := r.GetDeletion(ctx, userID); err != nil {
+		return err
+	}
+	return userpkg.ErrDeletionInProgress
+}
+
+func (r *AccountDeletionRepository) ClaimDueDeletion(ctx context.Context, now time.Time, lease time.Duration) (userpkg.AccountDeletion, bool, error) {
+	filter := bson.M{
+		"scheduledFor": bson.M{"$lte": now},
+		"$or": bson.A{
+			bson.M{"claimedAt": bson.M{"$exists": false}},
+			bson.M{"claimedAt": bson.M{"$lt": now.Add(-lease)}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"claimedAt": now}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.M{"scheduledFor": 1}).
+		SetReturnDocument(options.After)
+
+	var deletion userpkg.AccountDeletion
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&deletion)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return userpkg.AccountDeletion{}, false, nil
+	}
+	if err != nil {
+		return userpkg.AccountDeletion{}, false, err
+	}
+	return deletion, true, nil
+}
+
+func (r *AccountDeletionRepository) CompleteDeletion(ctx context.Context, userID string) error {
+	oid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": oid})
+	return err
+}
@@ -0,0 +1,113 @@
+package repositories_test
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	repositories "github.com/Amaankaa/Blog-Starter-Project/Repositories"
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const testAccountDeletionCollection = "test_account_deletions"
+
+type accountDeletionRepositoryTestSuite struct {
+	suite.Suite
+	client     *mongo.Client
+	ctx        context.Context
+	cancel     context.CancelFunc
+	collection *mongo.Collection
+	repo       *repositories.AccountDeletionRepository
+}
+
+func TestAccountDeletionRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(accountDeletionRepositoryTestSuite))
+}
+
+func (s *accountDeletionRepositoryTestSuite) SetupSuite() {
+	err := godotenv.Load("../.env")
+	if err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	mongoURI := os.Getenv("MONGODB_URI")
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(mongoURI))
+	s.Require().NoError(err)
+
+	s.client = client
+	s.collection = client.Database("test_blog_db").Collection(testAccountDeletionCollection)
+	s.repo = repositories.NewAccountDeletionRepository(s.collection)
+
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), 10*time.Second)
+}
+
+func (s *accountDeletionRepositoryTestSuite) TearDownSuite() {
+	_ = s.collection.Drop(s.ctx)
+	s.cancel()
+	_ = s.client.Disconnect(s.ctx)
+}
+
+func (s *accountDeletionRepositoryTestSuite) SetupTest() {
+	_, err := s.collection.DeleteMany(s.ctx, bson.M{})
+	s.Require().NoError(err)
+}
+
+func (s *accountDeletionRepositoryTestSuite) schedule(at time.Time) userpkg.AccountDeletion {
+	deletion := userpkg.AccountDeletion{
+		UserID:       primitive.NewObjectID(),
+		Email:        "a@b.com",
+		ContentMode:  userpkg.DeletionContentAnonymize,
+		RequestedAt:  time.Now(),
+		ScheduledFor: at,
+	}
+	s.Require().NoError(s.repo.ScheduleDeletion(s.ctx, deletion))
+	return deletion
+}
+
+func (s *accountDeletionRepositoryTestSuite) TestScheduleAndCancel() {
+	deletion := s.schedule(time.Now().Add(time.Hour))
+
+	found, err := s.repo.GetDeletion(s.ctx, deletion.UserID.Hex())
+	s.Require().NoError(err)
+	s.Equal(userpkg.DeletionContentAnonymize, found.ContentMode)
+
+	s.NoError(s.repo.CancelDeletion(s.ctx, deletion.UserID.Hex()))
+	_, err = s.repo.GetDeletion(s.ctx, deletion.UserID.Hex())
+	s.ErrorIs(err, userpkg.ErrDeletionNotFound)
+	s.ErrorIs(s.repo.CancelDeletion(s.ctx, deletion.UserID.Hex()), userpkg.ErrDeletionNotFound)
+}
+
+func (s *accountDeletionRepositoryTestSuite) TestClaimDueDeletion() {
+	now := time.Now()
+	s.schedule(now.Add(time.Hour))
+	due := s.schedule(now.Add(-time.Minute))
+
+	claimed, ok, err := s.repo.ClaimDueDeletion(s.ctx, now, 10*time.Minute)
+	s.Require().NoError(err)
+	s.Require().True(ok)
+	s.Equal(due.UserID, claimed.UserID)
+
+	// Claimed deletions are not handed out twice or cancelled
+	_, ok, err = s.repo.ClaimDueDeletion(s.ctx, now, 10*time.Minute)
+	s.NoError(err)
+	s.False(ok)
+	s.ErrorIs(s.repo.CancelDeletion(s.ctx, due.UserID.Hex()), userpkg.ErrDeletionInProgress)
+
+	// An abandoned claim is handed out again after the lease
+	claimed, ok, err = s.repo.ClaimDueDeletion(s.ctx, now.Add(11*time.Minute), 10*time.Minute)
+	s.NoError(err)
+	s.True(ok)
+	s.Equal(due.UserID, claimed.UserID)
+
+	s.NoError(s.repo.CompleteDeletion(s.ctx, due.UserID.Hex()))
+	_, err = s.repo.GetDeletion(s.ctx, due.UserID.Hex())
+	s.ErrorIs(err, userpkg.ErrDeletionNotFound)
+}
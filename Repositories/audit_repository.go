@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	auditpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/audit"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AuditRepository stores the audit chain with Seq as the document ID, so two
+// writers can never append the same link
+type AuditRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAuditRepository(collection *mongo.Collection) *AuditRepository {
+	return &AuditRepository{collection: collection}
+}
+
+func (r *AuditRepository) LastEntry(ctx context.Context) (auditpkg.Entry, bool, error) {
+	var entry auditpkg.Entry
+	err := r.collection.FindOne(ctx, bson.M{}, options.FindOne().SetSort(bson.D{{Key: "_id", Value: -1}})).Decode(&entry)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return auditpkg.Entry{}, false, nil
+	}
+	if err != nil {
+		return auditpkg.Entry{}, false, err
+	}
+	return entry, true, nil
+}
+
+func (r *AuditRepository) InsertEntry(ctx context.Context, entry auditpkg.Entry) error {
+	_, err := r.collection.InsertOne(ctx, entry)
+	if mongo.IsDuplicateKeyError(err) {
+		return auditpkg.ErrSeqTaken
+	}
+	return err
+}
+
+func (r *AuditRepository) FindEntries(ctx context.Context, filter auditpkg.Filter) ([]auditpkg.Entry, int64, error) {
+	query := auditQuery(filter)
+	total, err := r.collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}})
+	if filter.PageSize > 0 {
+		opts.SetSkip(int64((filter.Page - 1) * filter.PageSize)).SetLimit(int64(filter.PageSize))
+	}
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	entries := []auditpkg.Entry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+func (r *AuditRepository) EachEntry(ctx context.Context, filter auditpkg.Filter, fn func(auditpkg.Entry) error) error {
+	cursor, err := r.collection.Find(ctx, auditQuery(filter), options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var entry auditpkg.Entry
+		if err := cursor.Decode(&entry); err != nil {
+			return err
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+func auditQuery(filter auditpkg.Filter) bson.M {
+	query := bson.M{}
+	if filter.ActorID != "" {
+		query["actorId"] = filter.ActorID
+	}
+	if filter.Action != "" {
+		query["action"] = filter.Action
+	}
+	if filter.TargetType != "" {
+		query["targetType"] = filter.TargetType
+	}
+	if filter.TargetID != "" {
+		query["targetId"] = filter.TargetID
+	}
+	if filter.From != nil || filter.To != nil {
+		createdAt := bson.M{}
+		if filter.From != nil {
+			createdAt["$gte"] = *filter.From
+		}
+		if filter.To != nil {
+			createdAt["$lt"] = *filter.To
+		}
+		query["createdAt"] = createdAt
+	}
+	return query
+}
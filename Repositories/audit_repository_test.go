@@ -0,0 +1,110 @@
+package repositories_test
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	auditpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/audit"
+	repositories "github.com/Amaankaa/Blog-Starter-Project/Repositories"
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const testAuditCollection = "test_audit_log"
+
+type auditRepositoryTestSuite struct {
+	suite.Suite
+	client     *mongo.Client
+	ctx        context.Context
+	cancel     context.CancelFunc
+	collection *mongo.Collection
+	repo       *repositories.AuditRepository
+}
+
+func TestAuditRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(auditRepositoryTestSuite))
+}
+
+func (s *auditRepositoryTestSuite) SetupSuite() {
+	err := godotenv.Load("../.env")
+	if err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	mongoURI := os.Getenv("MONGODB_URI")
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(mongoURI))
+	s.Require().NoError(err)
+
+	s.client = client
+	s.collection = client.Database("test_blog_db").Collection(testAuditCollection)
+	s.repo = repositories.NewAuditRepository(s.collection)
+
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), 10*time.Second)
+}
+
+func (s *auditRepositoryTestSuite) TearDownSuite() {
+	_ = s.collection.Drop(s.ctx)
+	s.cancel()
+	_ = s.client.Disconnect(s.ctx)
+}
+
+func (s *auditRepositoryTestSuite) SetupTest() {
+	_, err := s.collection.DeleteMany(s.ctx, bson.M{})
+	s.Require().NoError(err)
+}
+
+func (s *auditRepositoryTestSuite) entry(seq int64, action string) auditpkg.Entry {
+	e := auditpkg.Entry{
+		Seq:        seq,
+		ActorID:    "admin",
+		Action:     action,
+		TargetType: auditpkg.TargetUser,
+		TargetID:   "target",
+		After:      json.RawMessage(`{"role":"moderator"}`),
+		CreatedAt:  time.Now().UTC().Truncate(time.Millisecond),
+	}
+	e.Hash = e.ComputeHash()
+	return e
+}
+
+func (s *auditRepositoryTestSuite) TestInsertEntry_RejectsTakenSeq() {
+	_, found, err := s.repo.LastEntry(s.ctx)
+	s.Require().NoError(err)
+	s.False(found)
+
+	s.Require().NoError(s.repo.InsertEntry(s.ctx, s.entry(1, auditpkg.ActionRoleGrant)))
+	s.ErrorIs(s.repo.InsertEntry(s.ctx, s.entry(1, auditpkg.ActionRoleRevoke)), auditpkg.ErrSeqTaken)
+
+	last, found, err := s.repo.LastEntry(s.ctx)
+	s.Require().NoError(err)
+	s.True(found)
+	s.Equal(auditpkg.ActionRoleGrant, last.Action)
+	// The stored entry still hashes to the same value
+	s.Equal(last.Hash, last.ComputeHash())
+}
+
+func (s *auditRepositoryTestSuite) TestFindAndEachEntry() {
+	s.Require().NoError(s.repo.InsertEntry(s.ctx, s.entry(1, auditpkg.ActionRoleGrant)))
+	s.Require().NoError(s.repo.InsertEntry(s.ctx, s.entry(2, auditpkg.ActionUserSuspend)))
+	s.Require().NoError(s.repo.InsertEntry(s.ctx, s.entry(3, auditpkg.ActionRoleGrant)))
+
+	entries, total, err := s.repo.FindEntries(s.ctx, auditpkg.Filter{Action: auditpkg.ActionRoleGrant, Page: 1, PageSize: 1})
+	s.Require().NoError(err)
+	s.Equal(int64(2), total)
+	s.Require().Len(entries, 1)
+	s.Equal(int64(3), entries[0].Seq)
+
+	var seqs []int64
+	s.NoError(s.repo.EachEntry(s.ctx, auditpkg.Filter{}, func(e auditpkg.Entry) error {
+		seqs = append(seqs, e.Seq)
+		return nil
+	}))
+	s.Equal([]int64{1, 2, 3}, seqs)
+}
@@ -98,3 +98,65 @@ func (r *CommentRepository) UpdateComment(ctx context.Context, id primitive.Obje
 	}
 	return &updated, nil
 }
+
+// DeleteCommentsByPosts removes every comment on the given posts
+func (r *CommentRepository) DeleteCommentsByPosts(ctx context.Context, postIDs []primitive.ObjectID) error {
+	if len(postIDs) == 0 {
+		return nil
+	}
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"postId": bson.M{"$in": postIDs}}); err != nil {
+		return fmt.Errorf("failed to delete comments: %w", err)
+	}
+	return nil
+}
+
+func (r *CommentRepository) DeleteCommentsByAuthor(ctx context.Context, authorID primitive.ObjectID) (map[primitive.ObjectID]int, error) {
+	filter := bson.M{"authorId": authorID}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$group", Value: bson.M{"_id": "$postId", "count": bson.M{"$sum": 1}}}},
+	}
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count comments: %w", err)
+	}
+	var groups []struct {
+		PostID primitive.ObjectID `bson:"_id"`
+		Count  int                `bson:"count"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, fmt.Errorf("failed to decode comment counts: %w", err)
+	}
+
+	if _, err := r.collection.DeleteMany(ctx, filter); err != nil {
+		return nil, fmt.Errorf("failed to delete comments: %w", err)
+	}
+
+	counts := make(map[primitive.ObjectID]int, len(groups))
+	for _, g := range groups {
+		counts[g.PostID] = g.Count
+	}
+	return counts, nil
+}
+
+func (r *CommentRepository) ReassignCommentsAuthor(ctx context.Context, fromID, toID primitive.ObjectID) error {
+	_, err := r.collection.UpdateMany(ctx, bson.M{"authorId": fromID}, bson.M{"$set": bson.M{"authorId": toID}})
+	if err != nil {
+		return fmt.Errorf("failed to reassign comments: %w", err)
+	}
+	return nil
+}
+
+// GetCommentsByAuthor returns every comment an author has written
+func (r *CommentRepository) GetCommentsByAuthor(ctx context.Context, authorID primitive.ObjectID) ([]commentpkg.Comment, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"authorId": authorID}, options.Find().SetSort(bson.M{"createdAt": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find comments: %w", err)
+	}
+	defer cursor.Close(ctx)
+	var comments []commentpkg.Comment
+	if err := cursor.All(ctx, &comments); err != nil {
+		return nil, fmt.Errorf("failed to decode comments: %w", err)
+	}
+	return comments, nil
+}
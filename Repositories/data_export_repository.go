@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DataExportRepository stores data export requests and where their archives live
+type DataExportRepository struct {
+	collection *mongo.Collection
+}
+
+func NewDataExportRepository(collection *mongo.Collection) *DataExportRepository {
+	return &DataExportRepository{collection: collection}
+}
+
+func (r *DataExportRepository) CreateExport(ctx context.Context, export userpkg.DataExport) (userpkg.DataExport, error) {
+	if export.ID.IsZero() {
+		export.ID = primitive.NewObjectID()
+	}
+	if _, err := r.collection.InsertOne(ctx, export); err != nil {
+		return userpkg.DataExport{}, err
+	}
+	return export, nil
+}
+
+func (r *DataExportRepository) GetExport(ctx context.Context, exportID string) (userpkg.DataExport, error) {
+	oid, err := primitive.ObjectIDFromHex(exportID)
+	if err != nil {
+		return userpkg.DataExport{}, userpkg.ErrExportNotFound
+	}
+	return r.findOne(ctx, bson.M{"_id": oid})
+}
+
+func (r *DataExportRepository) GetLatestExport(ctx context.Context, userID string) (userpkg.DataExport, error) {
+	oid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return userpkg.DataExport{}, err
+	}
+	return r.findOne(ctx, bson.M{"userId": oid}, options.FindOne().SetSort(bson.M{"requestedAt": -1}))
+}
+
+func (r *DataExportRepository) findOne(ctx context.Context, filter bson.M, opts ...*options.FindOneOptions) (userpkg.DataExport, error) {
+	var export userpkg.DataExport
+	err := r.collection.FindOne(ctx, filter, opts...).Decode(&export)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return userpkg.DataExport{}, userpkg.ErrExportNotFound
+	}
+	return export, err
+}
+
+func (r *DataExportRepository) ClaimPendingExport(ctx context.Context, now time.Time, lease time.Duration) (userpkg.DataExport, bool, error) {
+	filter := bson.M{
+		"$or": bson.A{
+			bson.M{"status": userpkg.ExportPending},
+			bson.M{"status": userpkg.ExportRunning, "claimedAt": bson.M{"$lt": now.Add(-lease)}},
+		},
+	}
+	update := bson.M{"$set": bson.M{"status": userpkg.ExportRunning, "claimedAt": now}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.M{"requestedAt": 1}).
+		SetReturnDocument(options.After)
+
+	var export userpkg.DataExport
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&export)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return userpkg.DataExport{}, false, nil
+	}
+	if err != nil {
+		return userpkg.DataExport{}, false, err
+	}
+	return export, true, nil
+}
+
+func (r *DataExportRepository) MarkExportReady(ctx context.Context, exportID, file string, size int64, completedAt, expiresAt time.Time) error {
+	return r.update(ctx, exportID, bson.M{
+		"$set": bson.M{
+			"status":      userpkg.ExportReady,
+			"file":        file,
+			"size":        size,
+			"completedAt": completedAt,
+			"expiresAt":   expiresAt,
+		},
+		"$unset": bson.M{"claimedAt": "", "error": ""},
+	})
+}
+
+func (r *DataExportRepository) MarkExportFailed(ctx context.Context, exportID, reason string) error {
+	now := time.Now()
+	return r.update(ctx, exportID, bson.M{
+		"$set":   bson.M{"status": userpkg.ExportFailed, "error": reason, "completedAt": now},
+		"$unset": bson.M{"claimedAt": ""},
+	})
+}
+
+func (r *DataExportRepository) update(ctx context.Context, exportID string, update bson.M) error {
+	oid, err := primitive.ObjectIDFromHex(exportID)
+	if err != nil {
+		return err
+	}
+	res, err := r.collection.UpdateOne(ctx, bson.M{"_id": oid}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return userpkg.ErrExportNotFound
+	}
+	return nil
+}
+
+func (r *DataExportRepository) FindExpiredExports(ctx context.Context, now time.Time) ([]userpkg.DataExport, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"expiresAt": bson.M{"$lte": now}})
+	if err != nil {
+		return nil, err
+	}
+	var exports []userpkg.DataExport
+	if err := cursor.All(ctx, &exports); err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
+
+func (r *DataExportRepository) FindExportsByUserID(ctx context.Context, userID primitive.ObjectID) ([]userpkg.DataExport, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"userId": userID})
+	if err != nil {
+		return nil, err
+	}
+	var exports []userpkg.DataExport
+	if err := cursor.All(ctx, &exports); err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
+
+func (r *DataExportRepository) DeleteExport(ctx context.Context, exportID string) error {
+	oid, err := primitive.ObjectIDFromHex(exportID)
+	if err != nil {
+		return err
+	}
+	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": oid})
+	return err
+}
@@ -0,0 +1,129 @@
+package repositories_test
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	repositories "github.com/Amaankaa/Blog-Starter-Project/Repositories"
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const testDataExportCollection = "test_data_exports"
+
+type dataExportRepositoryTestSuite struct {
+	suite.Suite
+	client     *mongo.Client
+	ctx        context.Context
+	cancel     context.CancelFunc
+	collection *mongo.Collection
+	repo       *repositories.DataExportRepository
+}
+
+func TestDataExportRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(dataExportRepositoryTestSuite))
+}
+
+func (s *dataExportRepositoryTestSuite) SetupSuite() {
+	err := godotenv.Load("../.env")
+	if err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	mongoURI := os.Getenv("MONGODB_URI")
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(mongoURI))
+	s.Require().NoError(err)
+
+	s.client = client
+	s.collection = client.Database("test_blog_db").Collection(testDataExportCollection)
+	s.repo = repositories.NewDataExportRepository(s.collection)
+
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), 10*time.Second)
+}
+
+func (s *dataExportRepositoryTestSuite) TearDownSuite() {
+	_ = s.collection.Drop(s.ctx)
+	s.cancel()
+	_ = s.client.Disconnect(s.ctx)
+}
+
+func (s *dataExportRepositoryTestSuite) SetupTest() {
+	_, err := s.collection.DeleteMany(s.ctx, bson.M{})
+	s.Require().NoError(err)
+}
+
+func (s *dataExportRepositoryTestSuite) create(userID primitive.ObjectID, at time.Time) userpkg.DataExport {
+	export, err := s.repo.CreateExport(s.ctx, userpkg.DataExport{UserID: userID, Status: userpkg.ExportPending, RequestedAt: at})
+	s.Require().NoError(err)
+	return export
+}
+
+func (s *dataExportRepositoryTestSuite) TestGetLatestExport() {
+	userID := primitive.NewObjectID()
+	now := time.Now()
+	s.create(userID, now.Add(-time.Hour))
+	latest := s.create(userID, now)
+
+	found, err := s.repo.GetLatestExport(s.ctx, userID.Hex())
+	s.Require().NoError(err)
+	s.Equal(latest.ID, found.ID)
+
+	_, err = s.repo.GetLatestExport(s.ctx, primitive.NewObjectID().Hex())
+	s.ErrorIs(err, userpkg.ErrExportNotFound)
+}
+
+func (s *dataExportRepositoryTestSuite) TestClaimAndComplete() {
+	now := time.Now()
+	export := s.create(primitive.NewObjectID(), now)
+
+	claimed, ok, err := s.repo.ClaimPendingExport(s.ctx, now, 10*time.Minute)
+	s.Require().NoError(err)
+	s.Require().True(ok)
+	s.Equal(export.ID, claimed.ID)
+	s.Equal(userpkg.ExportRunning, claimed.Status)
+
+	// Running exports are not handed out again until the lease runs out
+	_, ok, err = s.repo.ClaimPendingExport(s.ctx, now, 10*time.Minute)
+	s.NoError(err)
+	s.False(ok)
+	_, ok, err = s.repo.ClaimPendingExport(s.ctx, now.Add(11*time.Minute), 10*time.Minute)
+	s.NoError(err)
+	s.True(ok)
+
+	expiresAt := now.Add(time.Hour)
+	s.NoError(s.repo.MarkExportReady(s.ctx, export.ID.Hex(), "a.zip", 42, now, expiresAt))
+	found, err := s.repo.GetExport(s.ctx, export.ID.Hex())
+	s.Require().NoError(err)
+	s.Equal(userpkg.ExportReady, found.Status)
+	s.Equal("a.zip", found.File)
+	s.Nil(found.ClaimedAt)
+
+	expired, err := s.repo.FindExpiredExports(s.ctx, expiresAt.Add(time.Second))
+	s.NoError(err)
+	s.Len(expired, 1)
+
+	s.NoError(s.repo.DeleteExport(s.ctx, export.ID.Hex()))
+	_, err = s.repo.GetExport(s.ctx, export.ID.Hex())
+	s.ErrorIs(err, userpkg.ErrExportNotFound)
+}
+
+func (s *dataExportRepositoryTestSuite) TestFindExportsByUserID() {
+	userID := primitive.NewObjectID()
+	now := time.Now()
+	first := s.create(userID, now.Add(-time.Hour))
+	second := s.create(userID, now)
+	s.create(primitive.NewObjectID(), now)
+
+	exports, err := s.repo.FindExportsByUserID(s.ctx, userID)
+	s.Require().NoError(err)
+	s.Require().Len(exports, 2)
+	s.ElementsMatch([]primitive.ObjectID{first.ID, second.ID}, []primitive.ObjectID{exports[0].ID, exports[1].ID})
+}
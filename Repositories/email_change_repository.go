@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EmailChangeRepository stores pending email changes, one per user. Starting
+// a new change replaces the previous one.
+type EmailChangeRepository struct {
+	collection *mongo.Collection
+}
+
+func NewEmailChangeRepository(collection *mongo.Collection) *EmailChangeRepository {
+	return &EmailChangeRepository{collection: collection}
+}
+
+func (r *EmailChangeRepository) SaveEmailChange(ctx context.Context, change userpkg.EmailChange) error {
+	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": change.UserID}, change, options.Replace().SetUpsert(true))
+	return err
+}
+
+// GetEmailChange ignores expired changes the TTL index has not removed yet
+func (r *EmailChangeRepository) GetEmailChange(ctx context.Context, userID string) (userpkg.EmailChange, error) {
+	oid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return userpkg.EmailChange{}, userpkg.ErrEmailChangeNotFound
+	}
+	var change userpkg.EmailChange
+	err = r.collection.FindOne(ctx, bson.M{"_id": oid, "expiresAt": bson.M{"$gt": time.Now()}}).Decode(&change)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return userpkg.EmailChange{}, userpkg.ErrEmailChangeNotFound
+	}
+	return change, err
+}
+
+func (r *EmailChangeRepository) DeleteEmailChange(ctx context.Context, userID string) error {
+	oid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": oid})
+	return err
+}
@@ -0,0 +1,97 @@
+package repositories_test
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	repositories "github.com/Amaankaa/Blog-Starter-Project/Repositories"
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const testEmailChangeCollection = "test_email_changes"
+
+type emailChangeRepositoryTestSuite struct {
+	suite.Suite
+	client     *mongo.Client
+	ctx        context.Context
+	cancel     context.CancelFunc
+	collection *mongo.Collection
+	repo       *repositories.EmailChangeRepository
+}
+
+func TestEmailChangeRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(emailChangeRepositoryTestSuite))
+}
+
+func (s *emailChangeRepositoryTestSuite) SetupSuite() {
+	err := godotenv.Load("../.env")
+	if err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	mongoURI := os.Getenv("MONGODB_URI")
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(mongoURI))
+	s.Require().NoError(err)
+
+	s.client = client
+	s.collection = client.Database("test_blog_db").Collection(testEmailChangeCollection)
+	s.repo = repositories.NewEmailChangeRepository(s.collection)
+
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), 10*time.Second)
+}
+
+func (s *emailChangeRepositoryTestSuite) TearDownSuite() {
+	_ = s.collection.Drop(s.ctx)
+	s.cancel()
+	_ = s.client.Disconnect(s.ctx)
+}
+
+func (s *emailChangeRepositoryTestSuite) SetupTest() {
+	_, err := s.collection.DeleteMany(s.ctx, bson.M{})
+	s.Require().NoError(err)
+}
+
+func (s *emailChangeRepositoryTestSuite) TestSaveReplacesPendingChange() {
+	userID := primitive.NewObjectID()
+	now := time.Now()
+	s.Require().NoError(s.repo.SaveEmailChange(s.ctx, userpkg.EmailChange{UserID: userID, OldEmail: "a@b.com", NewEmail: "first@b.com", RequestedAt: now, ExpiresAt: now.Add(time.Hour)}))
+	s.Require().NoError(s.repo.SaveEmailChange(s.ctx, userpkg.EmailChange{UserID: userID, OldEmail: "a@b.com", NewEmail: "second@b.com", RequestedAt: now, ExpiresAt: now.Add(time.Hour)}))
+
+	change, err := s.repo.GetEmailChange(s.ctx, userID.Hex())
+	s.Require().NoError(err)
+	s.Equal("second@b.com", change.NewEmail)
+
+	s.NoError(s.repo.DeleteEmailChange(s.ctx, userID.Hex()))
+	_, err = s.repo.GetEmailChange(s.ctx, userID.Hex())
+	s.ErrorIs(err, userpkg.ErrEmailChangeNotFound)
+}
+
+func (s *emailChangeRepositoryTestSuite) TestGetIgnoresExpiredChange() {
+	userID := primitive.NewObjectID()
+	now := time.Now()
+	s.Require().NoError(s.repo.SaveEmailChange(s.ctx, userpkg.EmailChange{UserID: userID, NewEmail: "late@b.com", RequestedAt: now.Add(-time.Hour), ExpiresAt: now.Add(-time.Minute)}))
+
+	_, err := s.repo.GetEmailChange(s.ctx, userID.Hex())
+	s.ErrorIs(err, userpkg.ErrEmailChangeNotFound)
+}
+
+func (s *emailChangeRepositoryTestSuite) TestRoundTripKeepsMilliseconds() {
+	userID := primitive.NewObjectID()
+	now := time.Now()
+	s.Require().NoError(s.repo.SaveEmailChange(s.ctx, userpkg.EmailChange{UserID: userID, OldEmailOTP: "hash", NewEmail: "new@b.com", RequestedAt: now, ExpiresAt: now.Add(time.Hour)}))
+
+	change, err := s.repo.GetEmailChange(s.ctx, userID.Hex())
+	s.Require().NoError(err)
+	// Cancel links sign the request time, so it must read back as signed
+	s.Equal(now.UnixMilli(), change.RequestedAt.UnixMilli())
+	s.Equal("hash", change.OldEmailOTP)
+}
@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	emailpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/email"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EmailDomainRuleRepository stores admin allow and deny rules, keyed by domain
+type EmailDomainRuleRepository struct {
+	collection *mongo.Collection
+}
+
+func NewEmailDomainRuleRepository(collection *mongo.Collection) *EmailDomainRuleRepository {
+	return &EmailDomainRuleRepository{collection: collection}
+}
+
+func (r *EmailDomainRuleRepository) SaveRule(ctx context.Context, rule emailpkg.DomainRule) error {
+	_, err := r.collection.ReplaceOne(ctx, bson.M{"_id": rule.Domain}, rule, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (r *EmailDomainRuleRepository) DeleteRule(ctx context.Context, domain string) error {
+	res, err := r.collection.DeleteOne(ctx, bson.M{"_id": domain})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return emailpkg.ErrDomainRuleNotFound
+	}
+	return nil
+}
+
+func (r *EmailDomainRuleRepository) GetRule(ctx context.Context, domain string) (emailpkg.DomainRule, error) {
+	var rule emailpkg.DomainRule
+	err := r.collection.FindOne(ctx, bson.M{"_id": domain}).Decode(&rule)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return emailpkg.DomainRule{}, emailpkg.ErrDomainRuleNotFound
+	}
+	return rule, err
+}
+
+func (r *EmailDomainRuleRepository) ListRules(ctx context.Context, action emailpkg.DomainRuleAction) ([]emailpkg.DomainRule, error) {
+	query := bson.M{}
+	if action != "" {
+		query["action"] = action
+	}
+	cursor, err := r.collection.Find(ctx, query, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	rules := []emailpkg.DomainRule{}
+	if err := cursor.All(ctx, &rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *EmailDomainRuleRepository) FindRule(ctx context.Context, domains []string) (emailpkg.DomainRule, bool, error) {
+	if len(domains) == 0 {
+		return emailpkg.DomainRule{}, false, nil
+	}
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": domains}})
+	if err != nil {
+		return emailpkg.DomainRule{}, false, err
+	}
+	defer cursor.Close(ctx)
+
+	var rules []emailpkg.DomainRule
+	if err := cursor.All(ctx, &rules); err != nil {
+		return emailpkg.DomainRule{}, false, err
+	}
+	// The most specific domain wins
+	for _, domain := range domains {
+		for _, rule := range rules {
+			if rule.Domain == domain {
+				return rule, true, nil
+			}
+		}
+	}
+	return emailpkg.DomainRule{}, false, nil
+}
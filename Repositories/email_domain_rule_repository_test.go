@@ -0,0 +1,107 @@
+package repositories_test
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	emailpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/email"
+	repositories "github.com/Amaankaa/Blog-Starter-Project/Repositories"
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const testEmailDomainRulesCollection = "test_email_domain_rules"
+
+type emailDomainRuleRepositoryTestSuite struct {
+	suite.Suite
+	client     *mongo.Client
+	ctx        context.Context
+	cancel     context.CancelFunc
+	collection *mongo.Collection
+	repo       *repositories.EmailDomainRuleRepository
+}
+
+func TestEmailDomainRuleRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(emailDomainRuleRepositoryTestSuite))
+}
+
+func (s *emailDomainRuleRepositoryTestSuite) SetupSuite() {
+	err := godotenv.Load("../.env")
+	if err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	mongoURI := os.Getenv("MONGODB_URI")
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(mongoURI))
+	s.Require().NoError(err)
+
+	s.client = client
+	s.collection = client.Database("test_blog_db").Collection(testEmailDomainRulesCollection)
+	s.repo = repositories.NewEmailDomainRuleRepository(s.collection)
+
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), 10*time.Second)
+}
+
+func (s *emailDomainRuleRepositoryTestSuite) TearDownSuite() {
+	_ = s.collection.Drop(s.ctx)
+	s.cancel()
+	_ = s.client.Disconnect(s.ctx)
+}
+
+func (s *emailDomainRuleRepositoryTestSuite) SetupTest() {
+	_, err := s.collection.DeleteMany(s.ctx, bson.M{})
+	s.Require().NoError(err)
+}
+
+func (s *emailDomainRuleRepositoryTestSuite) save(domain string, action emailpkg.DomainRuleAction) {
+	s.Require().NoError(s.repo.SaveRule(s.ctx, emailpkg.DomainRule{
+		Domain:    domain,
+		Action:    action,
+		CreatedBy: "admin",
+		CreatedAt: time.Now(),
+	}))
+}
+
+func (s *emailDomainRuleRepositoryTestSuite) TestFindRule_MostSpecificWins() {
+	s.save("example.edu", emailpkg.DomainDeny)
+	s.save("cs.example.edu", emailpkg.DomainAllow)
+
+	rule, found, err := s.repo.FindRule(s.ctx, []string{"cs.example.edu", "example.edu", "edu"})
+	s.Require().NoError(err)
+	s.True(found)
+	s.Equal(emailpkg.DomainAllow, rule.Action)
+
+	rule, found, err = s.repo.FindRule(s.ctx, []string{"math.example.edu", "example.edu", "edu"})
+	s.Require().NoError(err)
+	s.True(found)
+	s.Equal("example.edu", rule.Domain)
+
+	_, found, err = s.repo.FindRule(s.ctx, []string{"example.com", "com"})
+	s.NoError(err)
+	s.False(found)
+}
+
+func (s *emailDomainRuleRepositoryTestSuite) TestSaveReplacesAndDelete() {
+	s.save("mailinator.com", emailpkg.DomainAllow)
+	s.save("mailinator.com", emailpkg.DomainDeny)
+
+	rules, err := s.repo.ListRules(s.ctx, "")
+	s.Require().NoError(err)
+	s.Require().Len(rules, 1)
+	s.Equal(emailpkg.DomainDeny, rules[0].Action)
+
+	rules, err = s.repo.ListRules(s.ctx, emailpkg.DomainAllow)
+	s.NoError(err)
+	s.Empty(rules)
+
+	s.NoError(s.repo.DeleteRule(s.ctx, "mailinator.com"))
+	s.ErrorIs(s.repo.DeleteRule(s.ctx, "mailinator.com"), emailpkg.ErrDomainRuleNotFound)
+	_, err = s.repo.GetRule(s.ctx, "mailinator.com")
+	s.ErrorIs(err, emailpkg.ErrDomainRuleNotFound)
+}
@@ -0,0 +1,234 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	emailpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/email"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EmailOutboxRepository stores queued emails until a worker delivers them
+type EmailOutboxRepository struct {
+	collection *mongo.Collection
+}
+
+func NewEmailOutboxRepository(collection *mongo.Collection) *EmailOutboxRepository {
+	return &EmailOutboxRepository{collection: collection}
+}
+
+func (r *EmailOutboxRepository) Enqueue(ctx context.Context, message emailpkg.Message) (emailpkg.Message, error) {
+	if message.ID.IsZero() {
+		message.ID = primitive.NewObjectID()
+	}
+	if _, err := r.collection.InsertOne(ctx, message); err != nil {
+		return emailpkg.Message{}, err
+	}
+	return message, nil
+}
+
+func (r *EmailOutboxRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (emailpkg.Message, bool, error) {
+	filter := bson.M{
+		"$or": bson.A{
+			bson.M{"status": emailpkg.StatusPending, "nextAttemptAt": bson.M{"$lte": now}},
+			bson.M{"status": emailpkg.StatusSending, "claimedAt": bson.M{"$lt": now.Add(-lease)}},
+		},
+	}
+	// A message still marked sending was taken from a worker that never
+	// finished, so that attempt counts too; otherwise a message that crashes
+	// its workers would be retried forever
+	attempts := bson.M{"$ifNull": bson.A{"$attempts", 0}}
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"attempts": bson.M{"$cond": bson.A{
+			bson.M{"$eq": bson.A{"$status", emailpkg.StatusSending}},
+			bson.M{"$add": bson.A{attempts, 1}},
+			attempts,
+		}},
+		"status":    emailpkg.StatusSending,
+		"claimedAt": now,
+	}}}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.M{"nextAttemptAt": 1}).
+		SetReturnDocument(options.After)
+
+	var message emailpkg.Message
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&message)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return emailpkg.Message{}, false, nil
+	}
+	if err != nil {
+		return emailpkg.Message{}, false, err
+	}
+	return message, true, nil
+}
+
+func (r *EmailOutboxRepository) MarkSent(ctx context.Context, messageID string, attempts int, transport string, sentAt time.Time) error {
+	return r.update(ctx, messageID, bson.M{
+		"$set": bson.M{
+			"status":    emailpkg.StatusSent,
+			"attempts":  attempts,
+			"transport": transport,
+			"sentAt":    sentAt,
+		},
+		"$unset": bson.M{"claimedAt": "", "lastError": ""},
+	})
+}
+
+func (r *EmailOutboxRepository) MarkRetry(ctx context.Context, messageID string, attempts int, transport, lastError string, nextAttemptAt time.Time) error {
+	return r.update(ctx, messageID, bson.M{
+		"$set": bson.M{
+			"status":        emailpkg.StatusPending,
+			"attempts":      attempts,
+			"transport":     transport,
+			"lastError":     lastError,
+			"nextAttemptAt": nextAttemptAt,
+		},
+		"$unset": bson.M{"claimedAt": ""},
+	})
+}
+
+func (r *EmailOutboxRepository) MarkDead(ctx context.Context, messageID string, attempts int, transport, lastError string) error {
+	return r.update(ctx, messageID, bson.M{
+		"$set": bson.M{
+			"status":    emailpkg.StatusDead,
+			"attempts":  attempts,
+			"transport": transport,
+			"lastError": lastError,
+		},
+		"$unset": bson.M{"claimedAt": ""},
+	})
+}
+
+func (r *EmailOutboxRepository) Requeue(ctx context.Context, messageID string, now time.Time) error {
+	oid, err := primitive.ObjectIDFromHex(messageID)
+	if err != nil {
+		return emailpkg.ErrMessageNotFound
+	}
+	res, err := r.collection.UpdateOne(ctx, bson.M{"_id": oid, "status": emailpkg.StatusDead}, bson.M{
+		"$set": bson.M{"status": emailpkg.StatusPending, "attempts": 0, "nextAttemptAt": now},
+	})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return emailpkg.ErrMessageNotFound
+	}
+	return nil
+}
+
+func (r *EmailOutboxRepository) update(ctx context.Context, messageID string, update bson.M) error {
+	oid, err := primitive.ObjectIDFromHex(messageID)
+	if err != nil {
+		return emailpkg.ErrMessageNotFound
+	}
+	res, err := r.collection.UpdateOne(ctx, bson.M{"_id": oid}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return emailpkg.ErrMessageNotFound
+	}
+	return nil
+}
+
+func (r *EmailOutboxRepository) GetMessage(ctx context.Context, messageID string) (emailpkg.Message, error) {
+	oid, err := primitive.ObjectIDFromHex(messageID)
+	if err != nil {
+		return emailpkg.Message{}, emailpkg.ErrMessageNotFound
+	}
+	var message emailpkg.Message
+	err = r.collection.FindOne(ctx, bson.M{"_id": oid}).Decode(&message)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return emailpkg.Message{}, emailpkg.ErrMessageNotFound
+	}
+	return message, err
+}
+
+func (r *EmailOutboxRepository) FindMessages(ctx context.Context, filter emailpkg.Filter) ([]emailpkg.Message, int64, error) {
+	query := bson.M{}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	if filter.To != "" {
+		query["to"] = filter.To
+	}
+	if filter.Template != "" {
+		query["template"] = filter.Template
+	}
+
+	total, err := r.collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}})
+	if filter.PageSize > 0 {
+		opts.SetSkip(int64((filter.Page - 1) * filter.PageSize)).SetLimit(int64(filter.PageSize))
+	}
+	cursor, err := r.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	messages := []emailpkg.Message{}
+	if err := cursor.All(ctx, &messages); err != nil {
+		return nil, 0, err
+	}
+	return messages, total, nil
+}
+
+func (r *EmailOutboxRepository) Stats(ctx context.Context) (emailpkg.OutboxStats, error) {
+	cursor, err := r.collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}}},
+	})
+	if err != nil {
+		return emailpkg.OutboxStats{}, err
+	}
+	var groups []struct {
+		Status emailpkg.Status `bson:"_id"`
+		Count  int64           `bson:"count"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return emailpkg.OutboxStats{}, err
+	}
+
+	var stats emailpkg.OutboxStats
+	for _, g := range groups {
+		switch g.Status {
+		case emailpkg.StatusPending:
+			stats.Pending = g.Count
+		case emailpkg.StatusSending:
+			stats.Sending = g.Count
+		case emailpkg.StatusSent:
+			stats.Sent = g.Count
+		case emailpkg.StatusDead:
+			stats.Dead = g.Count
+		}
+	}
+
+	if stats.Pending > 0 {
+		var oldest emailpkg.Message
+		err := r.collection.FindOne(ctx, bson.M{"status": emailpkg.StatusPending},
+			options.FindOne().SetSort(bson.M{"createdAt": 1})).Decode(&oldest)
+		if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+			return emailpkg.OutboxStats{}, err
+		}
+		if err == nil {
+			stats.OldestPendingAt = &oldest.CreatedAt
+		}
+	}
+	return stats, nil
+}
+
+func (r *EmailOutboxRepository) DeleteSentBefore(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.collection.DeleteMany(ctx, bson.M{"status": emailpkg.StatusSent, "sentAt": bson.M{"$lt": before}})
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
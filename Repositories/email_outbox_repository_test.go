@@ -0,0 +1,162 @@
+package repositories_test
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	emailpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/email"
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+	repositories "github.com/Amaankaa/Blog-Starter-Project/Repositories"
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const testEmailOutboxCollection = "test_email_outbox"
+
+type emailOutboxRepositoryTestSuite struct {
+	suite.Suite
+	client     *mongo.Client
+	ctx        context.Context
+	cancel     context.CancelFunc
+	collection *mongo.Collection
+	repo       *repositories.EmailOutboxRepository
+}
+
+func TestEmailOutboxRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(emailOutboxRepositoryTestSuite))
+}
+
+func (s *emailOutboxRepositoryTestSuite) SetupSuite() {
+	err := godotenv.Load("../.env")
+	if err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	mongoURI := os.Getenv("MONGODB_URI")
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(mongoURI))
+	s.Require().NoError(err)
+
+	s.client = client
+	s.collection = client.Database("test_blog_db").Collection(testEmailOutboxCollection)
+	s.repo = repositories.NewEmailOutboxRepository(s.collection)
+
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), 10*time.Second)
+}
+
+func (s *emailOutboxRepositoryTestSuite) TearDownSuite() {
+	_ = s.collection.Drop(s.ctx)
+	s.cancel()
+	_ = s.client.Disconnect(s.ctx)
+}
+
+func (s *emailOutboxRepositoryTestSuite) SetupTest() {
+	_, err := s.collection.DeleteMany(s.ctx, bson.M{})
+	s.Require().NoError(err)
+}
+
+func (s *emailOutboxRepositoryTestSuite) enqueue(to string, at time.Time) emailpkg.Message {
+	message, err := s.repo.Enqueue(s.ctx, emailpkg.Message{
+		To:            to,
+		Template:      services.TemplateVerification,
+		Subject:       "Your code",
+		Status:        emailpkg.StatusPending,
+		NextAttemptAt: at,
+		CreatedAt:     at,
+	})
+	s.Require().NoError(err)
+	return message
+}
+
+func (s *emailOutboxRepositoryTestSuite) TestClaimRetryAndSend() {
+	now := time.Now()
+	message := s.enqueue("a@example.com", now)
+
+	claimed, ok, err := s.repo.ClaimDue(s.ctx, now, time.Minute)
+	s.Require().NoError(err)
+	s.Require().True(ok)
+	s.Equal(message.ID, claimed.ID)
+	s.Equal(emailpkg.StatusSending, claimed.Status)
+
+	// Claimed messages are not handed out again until the lease runs out
+	_, ok, err = s.repo.ClaimDue(s.ctx, now, time.Minute)
+	s.NoError(err)
+	s.False(ok)
+
+	next := now.Add(time.Hour)
+	s.NoError(s.repo.MarkRetry(s.ctx, message.ID.Hex(), 1, "smtp", "connection refused", next))
+	_, ok, err = s.repo.ClaimDue(s.ctx, now.Add(time.Minute), time.Minute)
+	s.NoError(err)
+	s.False(ok, "not due until the backoff has passed")
+	_, ok, err = s.repo.ClaimDue(s.ctx, next, time.Minute)
+	s.NoError(err)
+	s.True(ok)
+
+	s.NoError(s.repo.MarkSent(s.ctx, message.ID.Hex(), 2, "smtp", next))
+	found, err := s.repo.GetMessage(s.ctx, message.ID.Hex())
+	s.Require().NoError(err)
+	s.Equal(emailpkg.StatusSent, found.Status)
+	s.Equal(2, found.Attempts)
+	s.Empty(found.LastError)
+	s.Nil(found.ClaimedAt)
+
+	deleted, err := s.repo.DeleteSentBefore(s.ctx, next.Add(time.Second))
+	s.NoError(err)
+	s.Equal(int64(1), deleted)
+}
+
+func (s *emailOutboxRepositoryTestSuite) TestReclaimCountsUnfinishedAttempt() {
+	now := time.Now()
+	message := s.enqueue("a@example.com", now)
+
+	claimed, ok, err := s.repo.ClaimDue(s.ctx, now, time.Minute)
+	s.Require().NoError(err)
+	s.Require().True(ok)
+	s.Equal(0, claimed.Attempts)
+
+	// The worker never reported back
+	later := now.Add(2 * time.Minute)
+	reclaimed, ok, err := s.repo.ClaimDue(s.ctx, later, time.Minute)
+	s.Require().NoError(err)
+	s.Require().True(ok)
+	s.Equal(message.ID, reclaimed.ID)
+	s.Equal(1, reclaimed.Attempts)
+
+	reclaimed, ok, err = s.repo.ClaimDue(s.ctx, later.Add(2*time.Minute), time.Minute)
+	s.Require().NoError(err)
+	s.Require().True(ok)
+	s.Equal(2, reclaimed.Attempts)
+}
+
+func (s *emailOutboxRepositoryTestSuite) TestDeadLetterAndRequeue() {
+	now := time.Now()
+	message := s.enqueue("a@example.com", now)
+	s.enqueue("b@example.com", now)
+
+	// Only dead messages can be requeued
+	s.ErrorIs(s.repo.Requeue(s.ctx, message.ID.Hex(), now), emailpkg.ErrMessageNotFound)
+
+	s.NoError(s.repo.MarkDead(s.ctx, message.ID.Hex(), 8, "brevo", "400 Bad Request"))
+	dead, total, err := s.repo.FindMessages(s.ctx, emailpkg.Filter{Status: emailpkg.StatusDead, Page: 1, PageSize: 10})
+	s.Require().NoError(err)
+	s.Equal(int64(1), total)
+	s.Equal(message.ID, dead[0].ID)
+
+	stats, err := s.repo.Stats(s.ctx)
+	s.Require().NoError(err)
+	s.Equal(int64(1), stats.Pending)
+	s.Equal(int64(1), stats.Dead)
+	s.NotNil(stats.OldestPendingAt)
+
+	s.NoError(s.repo.Requeue(s.ctx, message.ID.Hex(), now))
+	found, err := s.repo.GetMessage(s.ctx, message.ID.Hex())
+	s.Require().NoError(err)
+	s.Equal(emailpkg.StatusPending, found.Status)
+	s.Equal(0, found.Attempts)
+	s.Equal("400 Bad Request", found.LastError)
+}
@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"regexp"
+
+	institutionpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/institution"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// InstitutionRepository stores the institutions students can verify with
+type InstitutionRepository struct {
+	collection *mongo.Collection
+}
+
+func NewInstitutionRepository(collection *mongo.Collection) *InstitutionRepository {
+	return &InstitutionRepository{collection: collection}
+}
+
+func (r *InstitutionRepository) CreateInstitution(ctx context.Context, institution institutionpkg.Institution) (institutionpkg.Institution, error) {
+	institution.ID = primitive.NewObjectID()
+	if _, err := r.collection.InsertOne(ctx, institution); err != nil {
+		return institutionpkg.Institution{}, err
+	}
+	return institution, nil
+}
+
+func (r *InstitutionRepository) UpdateInstitution(ctx context.Context, institution institutionpkg.Institution) error {
+	res, err := r.collection.ReplaceOne(ctx, bson.M{"_id": institution.ID}, institution)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return institutionpkg.ErrInstitutionNotFound
+	}
+	return nil
+}
+
+func (r *InstitutionRepository) DeleteInstitution(ctx context.Context, id primitive.ObjectID) error {
+	res, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return institutionpkg.ErrInstitutionNotFound
+	}
+	return nil
+}
+
+func (r *InstitutionRepository) GetInstitution(ctx context.Context, id primitive.ObjectID) (institutionpkg.Institution, error) {
+	var institution institutionpkg.Institution
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&institution)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return institutionpkg.Institution{}, institutionpkg.ErrInstitutionNotFound
+	}
+	return institution, err
+}
+
+func (r *InstitutionRepository) ListInstitutions(ctx context.Context, query string) ([]institutionpkg.Institution, error) {
+	filter := bson.M{}
+	if query != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(query), Options: "i"}
+		filter["$or"] = bson.A{bson.M{"name": pattern}, bson.M{"domains": pattern}}
+	}
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "name", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	institutions := []institutionpkg.Institution{}
+	if err := cursor.All(ctx, &institutions); err != nil {
+		return nil, err
+	}
+	return institutions, nil
+}
+
+func (r *InstitutionRepository) FindByDomain(ctx context.Context, domains []string) (institutionpkg.Institution, bool, error) {
+	if len(domains) == 0 {
+		return institutionpkg.Institution{}, false, nil
+	}
+	cursor, err := r.collection.Find(ctx, bson.M{"domains": bson.M{"$in": domains}})
+	if err != nil {
+		return institutionpkg.Institution{}, false, err
+	}
+	defer cursor.Close(ctx)
+
+	var institutions []institutionpkg.Institution
+	if err := cursor.All(ctx, &institutions); err != nil {
+		return institutionpkg.Institution{}, false, err
+	}
+	// The most specific domain wins
+	for _, domain := range domains {
+		for _, institution := range institutions {
+			for _, owned := range institution.Domains {
+				if owned == domain {
+					return institution, true, nil
+				}
+			}
+		}
+	}
+	return institutionpkg.Institution{}, false, nil
+}
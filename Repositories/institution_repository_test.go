@@ -0,0 +1,117 @@
+package repositories_test
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	institutionpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/institution"
+	repositories "github.com/Amaankaa/Blog-Starter-Project/Repositories"
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const testInstitutionsCollection = "test_institutions"
+
+type institutionRepositoryTestSuite struct {
+	suite.Suite
+	client     *mongo.Client
+	ctx        context.Context
+	cancel     context.CancelFunc
+	collection *mongo.Collection
+	repo       *repositories.InstitutionRepository
+}
+
+func TestInstitutionRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(institutionRepositoryTestSuite))
+}
+
+func (s *institutionRepositoryTestSuite) SetupSuite() {
+	err := godotenv.Load("../.env")
+	if err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	mongoURI := os.Getenv("MONGODB_URI")
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(mongoURI))
+	s.Require().NoError(err)
+
+	s.client = client
+	s.collection = client.Database("test_blog_db").Collection(testInstitutionsCollection)
+	s.repo = repositories.NewInstitutionRepository(s.collection)
+
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), 10*time.Second)
+}
+
+func (s *institutionRepositoryTestSuite) TearDownSuite() {
+	_ = s.collection.Drop(s.ctx)
+	s.cancel()
+	_ = s.client.Disconnect(s.ctx)
+}
+
+func (s *institutionRepositoryTestSuite) SetupTest() {
+	_, err := s.collection.DeleteMany(s.ctx, bson.M{})
+	s.Require().NoError(err)
+}
+
+func (s *institutionRepositoryTestSuite) create(name string, domains ...string) institutionpkg.Institution {
+	institution, err := s.repo.CreateInstitution(s.ctx, institutionpkg.Institution{
+		Name:      name,
+		Domains:   domains,
+		CreatedBy: "admin",
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	})
+	s.Require().NoError(err)
+	return institution
+}
+
+func (s *institutionRepositoryTestSuite) TestFindByDomain_MostSpecificWins() {
+	s.create("Example University", "example.edu")
+	cs := s.create("Example School of Computing", "cs.example.edu")
+
+	institution, found, err := s.repo.FindByDomain(s.ctx, []string{"cs.example.edu", "example.edu", "edu"})
+	s.Require().NoError(err)
+	s.True(found)
+	s.Equal(cs.ID, institution.ID)
+
+	institution, found, err = s.repo.FindByDomain(s.ctx, []string{"math.example.edu", "example.edu", "edu"})
+	s.Require().NoError(err)
+	s.True(found)
+	s.Equal("Example University", institution.Name)
+
+	_, found, err = s.repo.FindByDomain(s.ctx, []string{"example.com", "com"})
+	s.NoError(err)
+	s.False(found)
+}
+
+func (s *institutionRepositoryTestSuite) TestListUpdateDelete() {
+	aau := s.create("Addis Ababa University", "aau.edu.et")
+	s.create("Bahir Dar University", "bdu.edu.et")
+
+	institutions, err := s.repo.ListInstitutions(s.ctx, "")
+	s.Require().NoError(err)
+	s.Len(institutions, 2)
+	institutions, err = s.repo.ListInstitutions(s.ctx, "BDU")
+	s.Require().NoError(err)
+	s.Require().Len(institutions, 1)
+	s.Equal("Bahir Dar University", institutions[0].Name)
+
+	aau.Name = "AAU"
+	s.Require().NoError(s.repo.UpdateInstitution(s.ctx, aau))
+	got, err := s.repo.GetInstitution(s.ctx, aau.ID)
+	s.Require().NoError(err)
+	s.Equal("AAU", got.Name)
+
+	s.NoError(s.repo.DeleteInstitution(s.ctx, aau.ID))
+	s.ErrorIs(s.repo.DeleteInstitution(s.ctx, aau.ID), institutionpkg.ErrInstitutionNotFound)
+	_, err = s.repo.GetInstitution(s.ctx, aau.ID)
+	s.ErrorIs(err, institutionpkg.ErrInstitutionNotFound)
+	s.ErrorIs(s.repo.UpdateInstitution(s.ctx, institutionpkg.Institution{ID: primitive.NewObjectID()}), institutionpkg.ErrInstitutionNotFound)
+}
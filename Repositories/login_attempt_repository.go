@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// LoginAttemptRepository stores failed-login counters. A TTL index on
+// expiresAt removes counters that have gone quiet.
+type LoginAttemptRepository struct {
+	collection *mongo.Collection
+}
+
+func NewLoginAttemptRepository(collection *mongo.Collection) *LoginAttemptRepository {
+	return &LoginAttemptRepository{collection: collection}
+}
+
+func (r *LoginAttemptRepository) GetLoginAttempts(ctx context.Context, key string) (userpkg.LoginAttempts, error) {
+	var attempts userpkg.LoginAttempts
+	err := r.collection.FindOne(ctx, bson.M{"_id": key}).Decode(&attempts)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return userpkg.LoginAttempts{Key: key}, nil
+	}
+	return attempts, err
+}
+
+func (r *LoginAttemptRepository) RecordLoginFailure(ctx context.Context, key string, at time.Time, window time.Duration) (userpkg.LoginAttempts, error) {
+	// Pipeline update so a counter the TTL monitor has not removed yet still
+	// starts over instead of carrying stale failures forward
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.D{
+		{Key: "failures", Value: bson.D{{Key: "$cond", Value: bson.A{
+			bson.D{{Key: "$gt", Value: bson.A{"$expiresAt", at}}},
+			bson.D{{Key: "$add", Value: bson.A{"$failures", 1}}},
+			1,
+		}}}},
+		{Key: "lastFailureAt", Value: at},
+		{Key: "expiresAt", Value: bson.D{{Key: "$max", Value: bson.A{"$lockedUntil", at.Add(window)}}}},
+	}}}}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	var attempts userpkg.LoginAttempts
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": key}, update, opts).Decode(&attempts)
+	return attempts, err
+}
+
+func (r *LoginAttemptRepository) LockLogin(ctx context.Context, key string, until time.Time) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": key},
+		bson.M{
+			"$set": bson.M{"lockedUntil": until},
+			"$max": bson.M{"expiresAt": until},
+		},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+func (r *LoginAttemptRepository) ResetLoginAttempts(ctx context.Context, key string) error {
+	_, err := r.collection.DeleteOne(ctx, bson.M{"_id": key})
+	return err
+}
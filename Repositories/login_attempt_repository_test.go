@@ -0,0 +1,109 @@
+package repositories_test
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	repositories "github.com/Amaankaa/Blog-Starter-Project/Repositories"
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const testLoginAttemptCollection = "test_login_attempts"
+
+type loginAttemptRepositoryTestSuite struct {
+	suite.Suite
+	client     *mongo.Client
+	ctx        context.Context
+	cancel     context.CancelFunc
+	collection *mongo.Collection
+	repo       *repositories.LoginAttemptRepository
+}
+
+func TestLoginAttemptRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(loginAttemptRepositoryTestSuite))
+}
+
+func (s *loginAttemptRepositoryTestSuite) SetupSuite() {
+	err := godotenv.Load("../.env")
+	if err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	mongoURI := os.Getenv("MONGODB_URI")
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(mongoURI))
+	s.Require().NoError(err)
+
+	s.client = client
+	s.collection = client.Database("test_blog_db").Collection(testLoginAttemptCollection)
+	s.repo = repositories.NewLoginAttemptRepository(s.collection)
+
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), 10*time.Second)
+}
+
+func (s *loginAttemptRepositoryTestSuite) TearDownSuite() {
+	_ = s.collection.Drop(s.ctx)
+	s.cancel()
+	_ = s.client.Disconnect(s.ctx)
+}
+
+func (s *loginAttemptRepositoryTestSuite) SetupTest() {
+	_, err := s.collection.DeleteMany(s.ctx, bson.M{})
+	s.Require().NoError(err)
+}
+
+func (s *loginAttemptRepositoryTestSuite) TestRecordLoginFailure_Counts() {
+	assert := assert.New(s.T())
+	now := time.Now()
+
+	attempts, err := s.repo.RecordLoginFailure(s.ctx, "account:1", now, time.Hour)
+	assert.NoError(err)
+	assert.Equal(1, attempts.Failures)
+
+	attempts, err = s.repo.RecordLoginFailure(s.ctx, "account:1", now.Add(time.Second), time.Hour)
+	assert.NoError(err)
+	assert.Equal(2, attempts.Failures)
+	assert.WithinDuration(now.Add(time.Second+time.Hour), attempts.ExpiresAt, time.Second)
+}
+
+func (s *loginAttemptRepositoryTestSuite) TestRecordLoginFailure_StartsOverAfterWindow() {
+	assert := assert.New(s.T())
+	now := time.Now()
+
+	_, err := s.repo.RecordLoginFailure(s.ctx, "ip:1.2.3.4", now.Add(-2*time.Hour), time.Hour)
+	assert.NoError(err)
+
+	attempts, err := s.repo.RecordLoginFailure(s.ctx, "ip:1.2.3.4", now, time.Hour)
+	assert.NoError(err)
+	assert.Equal(1, attempts.Failures)
+}
+
+func (s *loginAttemptRepositoryTestSuite) TestLockAndReset() {
+	assert := assert.New(s.T())
+	until := time.Now().Add(15 * time.Minute)
+
+	_, err := s.repo.RecordLoginFailure(s.ctx, "account:2", time.Now(), time.Minute)
+	assert.NoError(err)
+	assert.NoError(s.repo.LockLogin(s.ctx, "account:2", until))
+
+	attempts, err := s.repo.GetLoginAttempts(s.ctx, "account:2")
+	assert.NoError(err)
+	if assert.NotNil(attempts.LockedUntil) {
+		assert.WithinDuration(until, *attempts.LockedUntil, time.Second)
+	}
+	// The record outlives the lock so the TTL index cannot drop it early
+	assert.False(attempts.ExpiresAt.Before(until.Truncate(time.Millisecond)))
+
+	assert.NoError(s.repo.ResetLoginAttempts(s.ctx, "account:2"))
+	attempts, err = s.repo.GetLoginAttempts(s.ctx, "account:2")
+	assert.NoError(err)
+	assert.Zero(attempts.Failures)
+	assert.Nil(attempts.LockedUntil)
+}
@@ -80,3 +80,14 @@ func (r *MessagingRepository) GetMessages(ctx context.Context, conversationID pr
 	}
 	return list, nil
 }
+
+func (r *MessagingRepository) ReplaceParticipant(ctx context.Context, fromID, toID primitive.ObjectID) error {
+	if _, err := r.msgs.UpdateMany(ctx, bson.M{"senderId": fromID}, bson.M{"$set": bson.M{"senderId": toID}}); err != nil {
+		return err
+	}
+	_, err := r.convs.UpdateMany(ctx,
+		bson.M{"participantIds": fromID},
+		bson.M{"$set": bson.M{"participantIds.$": toID}},
+	)
+	return err
+}
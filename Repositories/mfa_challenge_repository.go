@@ -0,0 +1,58 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type MFAChallengeRepo struct {
+	collection *mongo.Collection
+}
+
+func NewMFAChallengeRepo(collection *mongo.Collection) *MFAChallengeRepo {
+	return &MFAChallengeRepo{collection: collection}
+}
+
+func (r *MFAChallengeRepo) StoreChallenge(ctx context.Context, challenge userpkg.MFAChallenge) error {
+	_, err := r.collection.InsertOne(ctx, challenge)
+	return err
+}
+
+func (r *MFAChallengeRepo) FindChallenge(ctx context.Context, tokenHash string) (userpkg.MFAChallenge, error) {
+	var challenge userpkg.MFAChallenge
+	err := r.collection.FindOne(ctx, bson.M{"_id": tokenHash}).Decode(&challenge)
+	return challenge, err
+}
+
+func (r *MFAChallengeRepo) IncrementChallengeAttempts(ctx context.Context, tokenHash string) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": tokenHash}, bson.M{"$inc": bson.M{"attemptCount": 1}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("mfa challenge not found")
+	}
+	return nil
+}
+
+// DeleteChallenge consumes a challenge; only one caller can succeed
+func (r *MFAChallengeRepo) DeleteChallenge(ctx context.Context, tokenHash string) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": tokenHash})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("mfa challenge not found")
+	}
+	return nil
+}
+
+func (r *MFAChallengeRepo) DeleteChallengesByUserID(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"userId": userID})
+	return err
+}
@@ -0,0 +1,96 @@
+package repositories_test
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	repositories "github.com/Amaankaa/Blog-Starter-Project/Repositories"
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const testMFAChallengeCollection = "test_mfa_challenges"
+
+type mfaChallengeRepositoryTestSuite struct {
+	suite.Suite
+	client     *mongo.Client
+	ctx        context.Context
+	cancel     context.CancelFunc
+	collection *mongo.Collection
+	repo       *repositories.MFAChallengeRepo
+}
+
+func TestMFAChallengeRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(mfaChallengeRepositoryTestSuite))
+}
+
+func (s *mfaChallengeRepositoryTestSuite) SetupSuite() {
+	err := godotenv.Load("../.env")
+	if err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	mongoURI := os.Getenv("MONGODB_URI")
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(mongoURI))
+	s.Require().NoError(err)
+
+	s.client = client
+	s.collection = client.Database("test_blog_db").Collection(testMFAChallengeCollection)
+	s.repo = repositories.NewMFAChallengeRepo(s.collection)
+
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), 10*time.Second)
+}
+
+func (s *mfaChallengeRepositoryTestSuite) TearDownSuite() {
+	_ = s.collection.Drop(s.ctx)
+	s.cancel()
+	_ = s.client.Disconnect(s.ctx)
+}
+
+func (s *mfaChallengeRepositoryTestSuite) SetupTest() {
+	_, err := s.collection.DeleteMany(s.ctx, bson.M{})
+	s.Require().NoError(err)
+}
+
+func (s *mfaChallengeRepositoryTestSuite) TestChallengeLifecycle() {
+	challenge := userpkg.MFAChallenge{
+		TokenHash: "hash",
+		UserID:    primitive.NewObjectID(),
+		ExpiresAt: time.Now().Add(5 * time.Minute).Truncate(time.Millisecond),
+	}
+	s.Require().NoError(s.repo.StoreChallenge(s.ctx, challenge))
+
+	s.NoError(s.repo.IncrementChallengeAttempts(s.ctx, "hash"))
+	found, err := s.repo.FindChallenge(s.ctx, "hash")
+	s.Require().NoError(err)
+	s.Equal(challenge.UserID, found.UserID)
+	s.Equal(1, found.AttemptCount)
+
+	s.NoError(s.repo.DeleteChallenge(s.ctx, "hash"))
+	s.Error(s.repo.DeleteChallenge(s.ctx, "hash"), "a challenge can only be consumed once")
+	_, err = s.repo.FindChallenge(s.ctx, "hash")
+	s.Error(err)
+}
+
+func (s *mfaChallengeRepositoryTestSuite) TestDeleteChallengesByUserID() {
+	userID := primitive.NewObjectID()
+	expiresAt := time.Now().Add(5 * time.Minute)
+	s.Require().NoError(s.repo.StoreChallenge(s.ctx, userpkg.MFAChallenge{TokenHash: "a", UserID: userID, ExpiresAt: expiresAt}))
+	s.Require().NoError(s.repo.StoreChallenge(s.ctx, userpkg.MFAChallenge{TokenHash: "b", UserID: userID, ExpiresAt: expiresAt}))
+	s.Require().NoError(s.repo.StoreChallenge(s.ctx, userpkg.MFAChallenge{TokenHash: "c", UserID: primitive.NewObjectID(), ExpiresAt: expiresAt}))
+
+	s.NoError(s.repo.DeleteChallengesByUserID(s.ctx, userID))
+
+	_, err := s.repo.FindChallenge(s.ctx, "a")
+	s.Error(err)
+	_, err = s.repo.FindChallenge(s.ctx, "c")
+	s.NoError(err, "other users keep theirs")
+}
@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// OAuthStateRepository keeps OIDC sign-ins that are waiting for the
+// provider's callback. A TTL index on expiresAt clears abandoned ones.
+type OAuthStateRepository struct {
+	collection *mongo.Collection
+}
+
+func NewOAuthStateRepository(collection *mongo.Collection) *OAuthStateRepository {
+	return &OAuthStateRepository{collection: collection}
+}
+
+func (r *OAuthStateRepository) SaveOAuthState(ctx context.Context, state userpkg.OAuthState) error {
+	_, err := r.collection.InsertOne(ctx, state)
+	return err
+}
+
+// ConsumeOAuthState deletes the state as it reads it, so a callback can only
+// be replayed into an error
+func (r *OAuthStateRepository) ConsumeOAuthState(ctx context.Context, stateHash string) (userpkg.OAuthState, error) {
+	var state userpkg.OAuthState
+	filter := bson.M{"_id": stateHash, "expiresAt": bson.M{"$gt": time.Now()}}
+	err := r.collection.FindOneAndDelete(ctx, filter).Decode(&state)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return userpkg.OAuthState{}, userpkg.ErrOAuthStateNotFound
+	}
+	return state, err
+}
@@ -0,0 +1,82 @@
+package repositories_test
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	repositories "github.com/Amaankaa/Blog-Starter-Project/Repositories"
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const testOAuthStateCollection = "test_oauth_states"
+
+type oauthStateRepositoryTestSuite struct {
+	suite.Suite
+	client     *mongo.Client
+	ctx        context.Context
+	cancel     context.CancelFunc
+	collection *mongo.Collection
+	repo       *repositories.OAuthStateRepository
+}
+
+func TestOAuthStateRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(oauthStateRepositoryTestSuite))
+}
+
+func (s *oauthStateRepositoryTestSuite) SetupSuite() {
+	err := godotenv.Load("../.env")
+	if err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	mongoURI := os.Getenv("MONGODB_URI")
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(mongoURI))
+	s.Require().NoError(err)
+
+	s.client = client
+	s.collection = client.Database("test_blog_db").Collection(testOAuthStateCollection)
+	s.repo = repositories.NewOAuthStateRepository(s.collection)
+
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), 10*time.Second)
+}
+
+func (s *oauthStateRepositoryTestSuite) TearDownSuite() {
+	_ = s.collection.Drop(s.ctx)
+	s.cancel()
+	_ = s.client.Disconnect(s.ctx)
+}
+
+func (s *oauthStateRepositoryTestSuite) SetupTest() {
+	_, err := s.collection.DeleteMany(s.ctx, bson.M{})
+	s.Require().NoError(err)
+}
+
+func (s *oauthStateRepositoryTestSuite) TestConsumeOAuthState_SingleUse() {
+	now := time.Now()
+	s.Require().NoError(s.repo.SaveOAuthState(s.ctx, userpkg.OAuthState{
+		StateHash: "hash", Provider: "google", CodeVerifier: "verifier", Nonce: "nonce", CreatedAt: now, ExpiresAt: now.Add(time.Minute),
+	}))
+
+	state, err := s.repo.ConsumeOAuthState(s.ctx, "hash")
+	s.Require().NoError(err)
+	s.Equal("verifier", state.CodeVerifier)
+
+	_, err = s.repo.ConsumeOAuthState(s.ctx, "hash")
+	s.ErrorIs(err, userpkg.ErrOAuthStateNotFound)
+}
+
+func (s *oauthStateRepositoryTestSuite) TestConsumeOAuthState_Expired() {
+	past := time.Now().Add(-time.Minute)
+	s.Require().NoError(s.repo.SaveOAuthState(s.ctx, userpkg.OAuthState{StateHash: "old", Provider: "google", ExpiresAt: past}))
+
+	_, err := s.repo.ConsumeOAuthState(s.ctx, "old")
+	s.ErrorIs(err, userpkg.ErrOAuthStateNotFound)
+}
@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// OTPSendRepository stores OTP email counters. A TTL index on expiresAt
+// removes counters whose window has passed.
+type OTPSendRepository struct {
+	collection *mongo.Collection
+}
+
+func NewOTPSendRepository(collection *mongo.Collection) *OTPSendRepository {
+	return &OTPSendRepository{collection: collection}
+}
+
+func (r *OTPSendRepository) GetOTPSends(ctx context.Context, key string) (userpkg.OTPSends, error) {
+	var sends userpkg.OTPSends
+	err := r.collection.FindOne(ctx, bson.M{"_id": key}).Decode(&sends)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return userpkg.OTPSends{Key: key}, nil
+	}
+	return sends, err
+}
+
+func (r *OTPSendRepository) RecordOTPSend(ctx context.Context, key string, at time.Time, window time.Duration) (userpkg.OTPSends, error) {
+	// Pipeline update so a counter the TTL monitor has not removed yet still
+	// starts a new window instead of carrying old sends forward
+	open := bson.D{{Key: "$gt", Value: bson.A{"$expiresAt", at}}}
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.D{
+		{Key: "count", Value: bson.D{{Key: "$cond", Value: bson.A{
+			open,
+			bson.D{{Key: "$add", Value: bson.A{"$count", 1}}},
+			1,
+		}}}},
+		{Key: "windowStart", Value: bson.D{{Key: "$cond", Value: bson.A{open, "$windowStart", at}}}},
+		{Key: "expiresAt", Value: bson.D{{Key: "$cond", Value: bson.A{open, "$expiresAt", at.Add(window)}}}},
+		{Key: "lastSentAt", Value: at},
+	}}}}
+
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	var sends userpkg.OTPSends
+	err := r.collection.FindOneAndUpdate(ctx, bson.M{"_id": key}, update, opts).Decode(&sends)
+	return sends, err
+}
@@ -0,0 +1,97 @@
+package repositories_test
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	repositories "github.com/Amaankaa/Blog-Starter-Project/Repositories"
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const testOTPSendCollection = "test_otp_sends"
+
+type otpSendRepositoryTestSuite struct {
+	suite.Suite
+	client     *mongo.Client
+	ctx        context.Context
+	cancel     context.CancelFunc
+	collection *mongo.Collection
+	repo       *repositories.OTPSendRepository
+}
+
+func TestOTPSendRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(otpSendRepositoryTestSuite))
+}
+
+func (s *otpSendRepositoryTestSuite) SetupSuite() {
+	err := godotenv.Load("../.env")
+	if err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	mongoURI := os.Getenv("MONGODB_URI")
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(mongoURI))
+	s.Require().NoError(err)
+
+	s.client = client
+	s.collection = client.Database("test_blog_db").Collection(testOTPSendCollection)
+	s.repo = repositories.NewOTPSendRepository(s.collection)
+
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), 10*time.Second)
+}
+
+func (s *otpSendRepositoryTestSuite) TearDownSuite() {
+	_ = s.collection.Drop(s.ctx)
+	s.cancel()
+	_ = s.client.Disconnect(s.ctx)
+}
+
+func (s *otpSendRepositoryTestSuite) SetupTest() {
+	_, err := s.collection.DeleteMany(s.ctx, bson.M{})
+	s.Require().NoError(err)
+}
+
+func (s *otpSendRepositoryTestSuite) TestGetOTPSends_MissingIsZero() {
+	sends, err := s.repo.GetOTPSends(s.ctx, "reset:email:a@b.com")
+	s.NoError(err)
+	s.Equal("reset:email:a@b.com", sends.Key)
+	s.Zero(sends.Count)
+}
+
+func (s *otpSendRepositoryTestSuite) TestRecordOTPSend_CountsWithinWindow() {
+	assert := assert.New(s.T())
+	now := time.Now()
+
+	sends, err := s.repo.RecordOTPSend(s.ctx, "ip:1.2.3.4", now, 24*time.Hour)
+	assert.NoError(err)
+	assert.Equal(1, sends.Count)
+
+	sends, err = s.repo.RecordOTPSend(s.ctx, "ip:1.2.3.4", now.Add(time.Minute), 24*time.Hour)
+	assert.NoError(err)
+	assert.Equal(2, sends.Count)
+	// The window does not slide with later sends
+	assert.WithinDuration(now, sends.WindowStart, time.Second)
+	assert.WithinDuration(now.Add(24*time.Hour), sends.ExpiresAt, time.Second)
+	assert.WithinDuration(now.Add(time.Minute), sends.LastSentAt, time.Second)
+}
+
+func (s *otpSendRepositoryTestSuite) TestRecordOTPSend_StartsOverAfterWindow() {
+	assert := assert.New(s.T())
+	now := time.Now()
+
+	_, err := s.repo.RecordOTPSend(s.ctx, "ip:1.2.3.4", now.Add(-25*time.Hour), 24*time.Hour)
+	assert.NoError(err)
+
+	sends, err := s.repo.RecordOTPSend(s.ctx, "ip:1.2.3.4", now, 24*time.Hour)
+	assert.NoError(err)
+	assert.Equal(1, sends.Count)
+	assert.WithinDuration(now, sends.WindowStart, time.Second)
+}
@@ -6,6 +6,7 @@ import (
     userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
     "go.mongodb.org/mongo-driver/bson"
     "go.mongodb.org/mongo-driver/mongo"
+    "go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type PasswordResetRepo struct {
@@ -20,8 +21,15 @@ func NewPasswordResetRepo(r *mongo.Collection, uc *mongo.Collection) *PasswordRe
     }
 }
 
+// StoreResetRequest replaces any outstanding reset for the email, so only the
+// latest OTP or grant is ever valid.
 func (r *PasswordResetRepo) StoreResetRequest(ctx context.Context, reset userpkg.PasswordReset) error {
-    _, err := r.passwordResetCollection.InsertOne(ctx, reset)
+    _, err := r.passwordResetCollection.ReplaceOne(
+        ctx,
+        bson.M{"email": reset.Email},
+        reset,
+        options.Replace().SetUpsert(true),
+    )
     return err
 }
 
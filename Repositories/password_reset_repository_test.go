@@ -125,3 +125,32 @@ func (s *passwordResetRepoTestSuite) TestDeleteResetRequest() {
 	assert.Error(err)
 	assert.Equal(mongo.ErrNoDocuments, err)
 }
+
+func (s *passwordResetRepoTestSuite) TestStoreResetRequest_ReplacesExisting() {
+	assert := assert.New(s.T())
+
+	email := "replace@example.com"
+	_ = s.resetRepo.StoreResetRequest(s.ctx, userpkg.PasswordReset{
+		Email:        email,
+		OTP:          "hashed-otp",
+		ExpiresAt:    time.Now().Add(10 * time.Minute),
+		AttemptCount: 3,
+	})
+
+	err := s.resetRepo.StoreResetRequest(s.ctx, userpkg.PasswordReset{
+		Email:     email,
+		GrantHash: "hashed-grant",
+		ExpiresAt: time.Now().Add(15 * time.Minute),
+	})
+	assert.NoError(err)
+
+	count, err := s.resetCollection.CountDocuments(s.ctx, bson.M{"email": email})
+	assert.NoError(err)
+	assert.Equal(int64(1), count)
+
+	found, err := s.resetRepo.GetResetRequest(s.ctx, email)
+	assert.NoError(err)
+	assert.Empty(found.OTP)
+	assert.Equal("hashed-grant", found.GrantHash)
+	assert.Equal(0, found.AttemptCount)
+}
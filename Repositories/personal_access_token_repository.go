@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// PersonalAccessTokenRepository stores personal access tokens, looked up by
+// the hash of their secret
+type PersonalAccessTokenRepository struct {
+	collection *mongo.Collection
+}
+
+func NewPersonalAccessTokenRepository(collection *mongo.Collection) *PersonalAccessTokenRepository {
+	return &PersonalAccessTokenRepository{collection: collection}
+}
+
+func (r *PersonalAccessTokenRepository) CreateAccessToken(ctx context.Context, token userpkg.PersonalAccessToken) (userpkg.PersonalAccessToken, error) {
+	if token.ID.IsZero() {
+		token.ID = primitive.NewObjectID()
+	}
+	if _, err := r.collection.InsertOne(ctx, token); err != nil {
+		return userpkg.PersonalAccessToken{}, err
+	}
+	return token, nil
+}
+
+// ListAccessTokens returns the user's tokens, newest first
+func (r *PersonalAccessTokenRepository) ListAccessTokens(ctx context.Context, userID primitive.ObjectID) ([]userpkg.PersonalAccessToken, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"userId": userID}, options.Find().SetSort(bson.M{"createdAt": -1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	tokens := []userpkg.PersonalAccessToken{}
+	if err := cursor.All(ctx, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (r *PersonalAccessTokenRepository) CountAccessTokens(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"userId": userID})
+}
+
+func (r *PersonalAccessTokenRepository) FindAccessTokenByHash(ctx context.Context, tokenHash string) (userpkg.PersonalAccessToken, error) {
+	var token userpkg.PersonalAccessToken
+	err := r.collection.FindOne(ctx, bson.M{"tokenHash": tokenHash}).Decode(&token)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return userpkg.PersonalAccessToken{}, userpkg.ErrAccessTokenNotFound
+	}
+	return token, err
+}
+
+func (r *PersonalAccessTokenRepository) TouchAccessToken(ctx context.Context, tokenID primitive.ObjectID, usedAt time.Time) error {
+	_, err := r.collection.UpdateOne(ctx, bson.M{"_id": tokenID}, bson.M{"$set": bson.M{"lastUsedAt": usedAt}})
+	return err
+}
+
+func (r *PersonalAccessTokenRepository) DeleteAccessToken(ctx context.Context, userID, tokenID primitive.ObjectID) error {
+	res, err := r.collection.DeleteOne(ctx, bson.M{"_id": tokenID, "userId": userID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return userpkg.ErrAccessTokenNotFound
+	}
+	return nil
+}
+
+func (r *PersonalAccessTokenRepository) DeleteAccessTokensByUserID(ctx context.Context, userID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"userId": userID})
+	return err
+}
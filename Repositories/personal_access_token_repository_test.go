@@ -0,0 +1,123 @@
+package repositories_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	repositories "github.com/Amaankaa/Blog-Starter-Project/Repositories"
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const testAccessTokenCollection = "test_personal_access_tokens"
+
+type accessTokenRepositoryTestSuite struct {
+	suite.Suite
+	client     *mongo.Client
+	ctx        context.Context
+	cancel     context.CancelFunc
+	collection *mongo.Collection
+	repo       *repositories.PersonalAccessTokenRepository
+}
+
+func TestAccessTokenRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(accessTokenRepositoryTestSuite))
+}
+
+func (s *accessTokenRepositoryTestSuite) SetupSuite() {
+	err := godotenv.Load("../.env")
+	if err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	mongoURI := os.Getenv("MONGODB_URI")
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(mongoURI))
+	s.Require().NoError(err)
+
+	s.client = client
+	s.collection = client.Database("test_blog_db").Collection(testAccessTokenCollection)
+	s.repo = repositories.NewPersonalAccessTokenRepository(s.collection)
+
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), 10*time.Second)
+}
+
+func (s *accessTokenRepositoryTestSuite) TearDownSuite() {
+	_ = s.collection.Drop(s.ctx)
+	s.cancel()
+	_ = s.client.Disconnect(s.ctx)
+}
+
+func (s *accessTokenRepositoryTestSuite) SetupTest() {
+	_, err := s.collection.DeleteMany(s.ctx, bson.M{})
+	s.Require().NoError(err)
+}
+
+func (s *accessTokenRepositoryTestSuite) TestCreateFindAndTouch() {
+	userID := primitive.NewObjectID()
+	token, err := s.repo.CreateAccessToken(s.ctx, userpkg.PersonalAccessToken{
+		UserID:    userID,
+		Name:      "ci",
+		TokenHash: "hash-1",
+		Scopes:    []string{userpkg.ScopeResourcesWrite},
+		CreatedAt: time.Now(),
+	})
+	s.Require().NoError(err)
+
+	found, err := s.repo.FindAccessTokenByHash(s.ctx, "hash-1")
+	s.Require().NoError(err)
+	s.Equal(token.ID, found.ID)
+	s.Nil(found.LastUsedAt)
+
+	usedAt := time.Now().Truncate(time.Millisecond)
+	s.NoError(s.repo.TouchAccessToken(s.ctx, token.ID, usedAt))
+	found, err = s.repo.FindAccessTokenByHash(s.ctx, "hash-1")
+	s.Require().NoError(err)
+	s.Require().NotNil(found.LastUsedAt)
+	s.True(found.LastUsedAt.Equal(usedAt))
+
+	_, err = s.repo.FindAccessTokenByHash(s.ctx, "unknown")
+	s.ErrorIs(err, userpkg.ErrAccessTokenNotFound)
+
+	count, err := s.repo.CountAccessTokens(s.ctx, userID)
+	s.NoError(err)
+	s.Equal(int64(1), count)
+}
+
+func (s *accessTokenRepositoryTestSuite) TestDeleteAccessToken_OnlyOwner() {
+	owner := primitive.NewObjectID()
+	token, err := s.repo.CreateAccessToken(s.ctx, userpkg.PersonalAccessToken{UserID: owner, Name: "ci", TokenHash: "hash-2", CreatedAt: time.Now()})
+	s.Require().NoError(err)
+
+	s.ErrorIs(s.repo.DeleteAccessToken(s.ctx, primitive.NewObjectID(), token.ID), userpkg.ErrAccessTokenNotFound)
+	s.NoError(s.repo.DeleteAccessToken(s.ctx, owner, token.ID))
+
+	tokens, err := s.repo.ListAccessTokens(s.ctx, owner)
+	s.NoError(err)
+	s.Empty(tokens)
+}
+
+func (s *accessTokenRepositoryTestSuite) TestDeleteAccessTokensByUserID() {
+	owner, other := primitive.NewObjectID(), primitive.NewObjectID()
+	for i, userID := range []primitive.ObjectID{owner, owner, other} {
+		_, err := s.repo.CreateAccessToken(s.ctx, userpkg.PersonalAccessToken{UserID: userID, Name: "ci", TokenHash: fmt.Sprintf("hash-all-%d", i), CreatedAt: time.Now()})
+		s.Require().NoError(err)
+	}
+
+	s.NoError(s.repo.DeleteAccessTokensByUserID(s.ctx, owner))
+
+	count, err := s.repo.CountAccessTokens(s.ctx, owner)
+	s.NoError(err)
+	s.Zero(count)
+	count, err = s.repo.CountAccessTokens(s.ctx, other)
+	s.NoError(err)
+	s.Equal(int64(1), count)
+}
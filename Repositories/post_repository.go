@@ -131,6 +131,9 @@ func (r *PostRepository) GetPosts(ctx context.Context, filter postpkg.PostFilter
 		}
 		mongoFilter["authorId"] = authorID
 	}
+	if filter.AuthorIDs != nil {
+		mongoFilter["authorId"] = bson.M{"$in": filter.AuthorIDs}
+	}
 	if filter.Tag != "" {
 		mongoFilter["tags"] = bson.M{"$in": []string{filter.Tag}}
 	}
@@ -509,9 +512,9 @@ func (r *PostRepository) HidePost(ctx context.Context, postID primitive.ObjectID
 	return nil
 }
 
-// UnhidePost unhides a previously hidden post
+// UnhidePost unhides a previously hidden post; deleted posts stay deleted
 func (r *PostRepository) UnhidePost(ctx context.Context, postID primitive.ObjectID) error {
-	filter := bson.M{"_id": postID}
+	filter := bson.M{"_id": postID, "status": postpkg.PostStatusHidden}
 	update := bson.M{
 		"$set": bson.M{
 			"status":    postpkg.PostStatusActive,
@@ -531,3 +534,68 @@ func (r *PostRepository) UnhidePost(ctx context.Context, postID primitive.Object
 
 	return nil
 }
+
+// DeletePostsByAuthor removes every post of an author outright, whatever its
+// status, and returns their IDs
+func (r *PostRepository) DeletePostsByAuthor(ctx context.Context, authorID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	filter := bson.M{"authorId": authorID}
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find posts: %w", err)
+	}
+	var docs []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode posts: %w", err)
+	}
+	if len(docs) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(docs))
+	for _, d := range docs {
+		ids = append(ids, d.ID)
+	}
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}}); err != nil {
+		return nil, fmt.Errorf("failed to delete posts: %w", err)
+	}
+	return ids, nil
+}
+
+// ReassignPostsAuthor credits every post of one author to another
+func (r *PostRepository) ReassignPostsAuthor(ctx context.Context, fromID, toID primitive.ObjectID) error {
+	_, err := r.collection.UpdateMany(ctx, bson.M{"authorId": fromID}, bson.M{"$set": bson.M{"authorId": toID}})
+	if err != nil {
+		return fmt.Errorf("failed to reassign posts: %w", err)
+	}
+	return nil
+}
+
+// GetAllPostsByAuthor returns every post of an author, whatever its status
+func (r *PostRepository) GetAllPostsByAuthor(ctx context.Context, authorID primitive.ObjectID) ([]postpkg.Post, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"authorId": authorID}, options.Find().SetSort(bson.M{"createdAt": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find posts: %w", err)
+	}
+	defer cursor.Close(ctx)
+	var posts []postpkg.Post
+	if err := cursor.All(ctx, &posts); err != nil {
+		return nil, fmt.Errorf("failed to decode posts: %w", err)
+	}
+	return posts, nil
+}
+
+// GetPostsLikedByUser returns every post the user has liked, whatever its status
+func (r *PostRepository) GetPostsLikedByUser(ctx context.Context, userID primitive.ObjectID) ([]postpkg.Post, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"likedBy": userID}, options.Find().SetSort(bson.M{"createdAt": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find liked posts: %w", err)
+	}
+	defer cursor.Close(ctx)
+	var posts []postpkg.Post
+	if err := cursor.All(ctx, &posts); err != nil {
+		return nil, fmt.Errorf("failed to decode liked posts: %w", err)
+	}
+	return posts, nil
+}
@@ -134,6 +134,9 @@ func (r *ResourceRepository) GetResources(ctx context.Context, filter resourcepk
 			return nil, 0, fmt.Errorf("invalid creator ID: %w", err)
 		}
 	}
+	if filter.CreatorIDs != nil {
+		q["creatorId"] = bson.M{"$in": filter.CreatorIDs}
+	}
 	if filter.Tag != "" {
 		q["tags"] = bson.M{"$in": []string{filter.Tag}}
 	}
@@ -559,7 +562,7 @@ func (r *ResourceRepository) HideResource(ctx context.Context, resourceID primit
 }
 
 func (r *ResourceRepository) UnhideResource(ctx context.Context, resourceID primitive.ObjectID) error {
-	filter := bson.M{"_id": resourceID}
+	filter := bson.M{"_id": resourceID, "status": resourcepkg.ResourceStatusHidden}
 	update := bson.M{"$set": bson.M{"status": resourcepkg.ResourceStatusActive, "isHidden": false, "updatedAt": time.Now()}}
 	res, err := r.collection.UpdateOne(ctx, filter, update)
 	if err != nil {
@@ -659,3 +662,34 @@ func (r *ResourceRepository) GetUserLikedResources(ctx context.Context, userID p
 	}
 	return items, total, nil
 }
+
+// DeleteResourcesByCreator removes every resource of a creator outright, whatever its status
+func (r *ResourceRepository) DeleteResourcesByCreator(ctx context.Context, creatorID primitive.ObjectID) error {
+	if _, err := r.collection.DeleteMany(ctx, bson.M{"creatorId": creatorID}); err != nil {
+		return fmt.Errorf("failed to delete resources: %w", err)
+	}
+	return nil
+}
+
+// ReassignResourcesCreator credits every resource of one creator to another
+func (r *ResourceRepository) ReassignResourcesCreator(ctx context.Context, fromID, toID primitive.ObjectID) error {
+	_, err := r.collection.UpdateMany(ctx, bson.M{"creatorId": fromID}, bson.M{"$set": bson.M{"creatorId": toID}})
+	if err != nil {
+		return fmt.Errorf("failed to reassign resources: %w", err)
+	}
+	return nil
+}
+
+// GetAllResourcesByCreator returns every resource of a creator, whatever its status
+func (r *ResourceRepository) GetAllResourcesByCreator(ctx context.Context, creatorID primitive.ObjectID) ([]resourcepkg.Resource, error) {
+	cur, err := r.collection.Find(ctx, bson.M{"creatorId": creatorID}, options.Find().SetSort(bson.M{"createdAt": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find resources: %w", err)
+	}
+	defer cur.Close(ctx)
+	var items []resourcepkg.Resource
+	if err := cur.All(ctx, &items); err != nil {
+		return nil, fmt.Errorf("failed to decode resources: %w", err)
+	}
+	return items, nil
+}
@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// RevocationRepository is the Mongo-backed IRevocationStore. Each document
+// carries an expiresAt so a TTL index can discard it once every token it
+// could match has expired on its own.
+type RevocationRepository struct {
+	collection *mongo.Collection
+}
+
+type revocationDoc struct {
+	ID         string    `bson:"_id"`
+	ValidAfter time.Time `bson:"validAfter,omitempty"`
+	ExpiresAt  time.Time `bson:"expiresAt"`
+}
+
+func NewRevocationRepository(collection *mongo.Collection) *RevocationRepository {
+	return &RevocationRepository{collection: collection}
+}
+
+func tokenRevocationKey(tokenID string) string { return "jti:" + tokenID }
+func userRevocationKey(userID string) string   { return "user:" + userID }
+
+func (r *RevocationRepository) RevokeToken(ctx context.Context, tokenID string, expiresAt time.Time) error {
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": tokenRevocationKey(tokenID)},
+		bson.M{"$set": bson.M{"expiresAt": expiresAt}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// RevokeUserTokens rejects every token of the user issued before issuedBefore.
+// The cutoff only ever moves forward.
+func (r *RevocationRepository) RevokeUserTokens(ctx context.Context, userID string, issuedBefore time.Time) error {
+	// iat has second precision, so compare at that precision too
+	cutoff := issuedBefore.Truncate(time.Second)
+	_, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": userRevocationKey(userID)},
+		bson.M{
+			"$max": bson.M{
+				"validAfter": cutoff,
+				"expiresAt":  cutoff.Add(userpkg.AccessTokenTTL),
+			},
+		},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// IsRevoked checks the token's jti and the user's cutoff in a single query
+func (r *RevocationRepository) IsRevoked(ctx context.Context, tokenID, userID string, issuedAt time.Time) (bool, error) {
+	keys := []string{userRevocationKey(userID)}
+	if tokenID != "" {
+		keys = append(keys, tokenRevocationKey(tokenID))
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"_id":       bson.M{"$in": keys},
+		"expiresAt": bson.M{"$gt": time.Now()},
+	})
+	if err != nil {
+		return false, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc revocationDoc
+		if err := cursor.Decode(&doc); err != nil {
+			return false, err
+		}
+		if doc.ID != userRevocationKey(userID) || issuedAt.Before(doc.ValidAfter) {
+			return true, nil
+		}
+	}
+	return false, cursor.Err()
+}
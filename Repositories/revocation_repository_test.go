@@ -0,0 +1,121 @@
+package repositories_test
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	repositories "github.com/Amaankaa/Blog-Starter-Project/Repositories"
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const testRevocationCollection = "test_revoked_tokens"
+
+type revocationRepositoryTestSuite struct {
+	suite.Suite
+	client     *mongo.Client
+	ctx        context.Context
+	cancel     context.CancelFunc
+	collection *mongo.Collection
+	repo       *repositories.RevocationRepository
+}
+
+func TestRevocationRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(revocationRepositoryTestSuite))
+}
+
+func (s *revocationRepositoryTestSuite) SetupSuite() {
+	err := godotenv.Load("../.env")
+	if err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	mongoURI := os.Getenv("MONGODB_URI")
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(mongoURI))
+	s.Require().NoError(err)
+
+	s.client = client
+	s.collection = client.Database("test_blog_db").Collection(testRevocationCollection)
+	s.repo = repositories.NewRevocationRepository(s.collection)
+
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), 10*time.Second)
+}
+
+func (s *revocationRepositoryTestSuite) TearDownSuite() {
+	_ = s.collection.Drop(s.ctx)
+	s.cancel()
+	_ = s.client.Disconnect(s.ctx)
+}
+
+func (s *revocationRepositoryTestSuite) SetupTest() {
+	_, err := s.collection.DeleteMany(s.ctx, bson.M{})
+	s.Require().NoError(err)
+}
+
+func (s *revocationRepositoryTestSuite) TestRevokeToken() {
+	assert := assert.New(s.T())
+
+	err := s.repo.RevokeToken(s.ctx, "jti-1", time.Now().Add(10*time.Minute))
+	assert.NoError(err)
+
+	revoked, err := s.repo.IsRevoked(s.ctx, "jti-1", "user-1", time.Now())
+	assert.NoError(err)
+	assert.True(revoked)
+
+	revoked, err = s.repo.IsRevoked(s.ctx, "jti-2", "user-1", time.Now())
+	assert.NoError(err)
+	assert.False(revoked)
+}
+
+func (s *revocationRepositoryTestSuite) TestRevokeToken_ExpiredEntryIgnored() {
+	assert := assert.New(s.T())
+
+	err := s.repo.RevokeToken(s.ctx, "jti-old", time.Now().Add(-time.Minute))
+	assert.NoError(err)
+
+	revoked, err := s.repo.IsRevoked(s.ctx, "jti-old", "user-1", time.Now())
+	assert.NoError(err)
+	assert.False(revoked)
+}
+
+func (s *revocationRepositoryTestSuite) TestRevokeUserTokens() {
+	assert := assert.New(s.T())
+
+	cutoff := time.Now()
+	err := s.repo.RevokeUserTokens(s.ctx, "user-1", cutoff)
+	assert.NoError(err)
+
+	// Issued before the cutoff
+	revoked, err := s.repo.IsRevoked(s.ctx, "jti-a", "user-1", cutoff.Add(-time.Minute))
+	assert.NoError(err)
+	assert.True(revoked)
+
+	// Issued after the cutoff
+	revoked, err = s.repo.IsRevoked(s.ctx, "jti-b", "user-1", cutoff.Add(time.Minute))
+	assert.NoError(err)
+	assert.False(revoked)
+
+	// Other users are unaffected
+	revoked, err = s.repo.IsRevoked(s.ctx, "jti-c", "user-2", cutoff.Add(-time.Minute))
+	assert.NoError(err)
+	assert.False(revoked)
+}
+
+func (s *revocationRepositoryTestSuite) TestRevokeUserTokens_CutoffOnlyMovesForward() {
+	assert := assert.New(s.T())
+
+	now := time.Now()
+	assert.NoError(s.repo.RevokeUserTokens(s.ctx, "user-1", now))
+	assert.NoError(s.repo.RevokeUserTokens(s.ctx, "user-1", now.Add(-5*time.Minute)))
+
+	revoked, err := s.repo.IsRevoked(s.ctx, "", "user-1", now.Add(-2*time.Minute))
+	assert.NoError(err)
+	assert.True(revoked)
+}
@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SigningKeyRepository stores the JWT key ring shared by every API instance
+type SigningKeyRepository struct {
+	collection *mongo.Collection
+}
+
+func NewSigningKeyRepository(collection *mongo.Collection) *SigningKeyRepository {
+	return &SigningKeyRepository{collection: collection}
+}
+
+func (r *SigningKeyRepository) SaveKey(ctx context.Context, key userpkg.SigningKey) error {
+	_, err := r.collection.InsertOne(ctx, key)
+	return err
+}
+
+// FindActiveKeys returns the keys that have not expired at now, newest first
+func (r *SigningKeyRepository) FindActiveKeys(ctx context.Context, now time.Time) ([]userpkg.SigningKey, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"expiresAt": bson.M{"$gt": now}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var keys []userpkg.SigningKey
+	if err := cursor.All(ctx, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
@@ -0,0 +1,89 @@
+package repositories_test
+
+import (
+	"context"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	repositories "github.com/Amaankaa/Blog-Starter-Project/Repositories"
+	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const testSigningKeyCollection = "test_jwt_keys"
+
+type signingKeyRepositoryTestSuite struct {
+	suite.Suite
+	client     *mongo.Client
+	ctx        context.Context
+	cancel     context.CancelFunc
+	collection *mongo.Collection
+	repo       *repositories.SigningKeyRepository
+}
+
+func TestSigningKeyRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(signingKeyRepositoryTestSuite))
+}
+
+func (s *signingKeyRepositoryTestSuite) SetupSuite() {
+	err := godotenv.Load("../.env")
+	if err != nil {
+		log.Println("No .env file found, using environment variables")
+	}
+
+	mongoURI := os.Getenv("MONGODB_URI")
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI(mongoURI))
+	s.Require().NoError(err)
+
+	s.client = client
+	s.collection = client.Database("test_blog_db").Collection(testSigningKeyCollection)
+	s.repo = repositories.NewSigningKeyRepository(s.collection)
+
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), 10*time.Second)
+}
+
+func (s *signingKeyRepositoryTestSuite) TearDownSuite() {
+	_ = s.collection.Drop(s.ctx)
+	s.cancel()
+	_ = s.client.Disconnect(s.ctx)
+}
+
+func (s *signingKeyRepositoryTestSuite) SetupTest() {
+	_, err := s.collection.DeleteMany(s.ctx, bson.M{})
+	s.Require().NoError(err)
+}
+
+func (s *signingKeyRepositoryTestSuite) TestFindActiveKeys() {
+	assert := assert.New(s.T())
+	now := time.Now()
+
+	keys := []userpkg.SigningKey{
+		{ID: "old", Algorithm: "RS256", PrivateKey: []byte("a"), CreatedAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(time.Hour)},
+		{ID: "new", Algorithm: "RS256", PrivateKey: []byte("b"), CreatedAt: now.Add(-time.Hour), ExpiresAt: now.Add(2 * time.Hour)},
+		{ID: "expired", Algorithm: "RS256", PrivateKey: []byte("c"), CreatedAt: now.Add(-3 * time.Hour), ExpiresAt: now.Add(-time.Minute)},
+	}
+	for _, k := range keys {
+		assert.NoError(s.repo.SaveKey(s.ctx, k))
+	}
+
+	active, err := s.repo.FindActiveKeys(s.ctx, now)
+	assert.NoError(err)
+	if assert.Len(active, 2) {
+		assert.Equal("new", active[0].ID)
+		assert.Equal("old", active[1].ID)
+		assert.Equal([]byte("b"), active[0].PrivateKey)
+	}
+}
+
+func (s *signingKeyRepositoryTestSuite) TestSaveKey_DuplicateID() {
+	key := userpkg.SigningKey{ID: "dup", Algorithm: "EdDSA", CreatedAt: time.Now(), ExpiresAt: time.Now().Add(time.Hour)}
+	s.NoError(s.repo.SaveKey(s.ctx, key))
+	s.Error(s.repo.SaveKey(s.ctx, key))
+}
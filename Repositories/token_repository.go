@@ -2,11 +2,14 @@ package repositories
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	tokenpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type TokenRepository struct {
@@ -47,3 +50,99 @@ func (r *TokenRepository) DeleteTokensByUserID(ctx context.Context, userID strin
 	_, err = r.collection.DeleteMany(ctx, filter)
 	return err
 }
+
+// MarkTokenRotated flags a token as rotated. Only the first caller succeeds;
+// later callers get ErrRefreshTokenReused so concurrent refreshes of the same
+// token cannot both mint children.
+func (r *TokenRepository) MarkTokenRotated(ctx context.Context, tokenID primitive.ObjectID) error {
+	filter := bson.M{"_id": tokenID, "rotated_at": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"rotated_at": time.Now()}}
+	res, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return tokenpkg.ErrRefreshTokenReused
+	}
+	return nil
+}
+
+// DeleteTokensByFamilyID revokes every token descended from the same login
+func (r *TokenRepository) DeleteTokensByFamilyID(ctx context.Context, familyID primitive.ObjectID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"family_id": familyID})
+	return err
+}
+
+// activeSessionFilter matches the live (unrotated, unexpired) token of sessions
+func activeSessionFilter() bson.M {
+	return bson.M{
+		"family_id":  bson.M{"$exists": true},
+		"rotated_at": bson.M{"$exists": false},
+		"expires_at": bson.M{"$gt": time.Now()},
+	}
+}
+
+func (r *TokenRepository) FindActiveSessionsByUserID(ctx context.Context, userID primitive.ObjectID) ([]tokenpkg.Token, error) {
+	filter := activeSessionFilter()
+	filter["user_id"] = userID
+	opts := options.Find().SetSort(bson.D{{Key: "last_used_at", Value: -1}})
+
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var tokens []tokenpkg.Token
+	if err := cursor.All(ctx, &tokens); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// sessionTouchInterval limits how often a session's last-used time is written
+const sessionTouchInterval = time.Minute
+
+// TouchSession records activity on a session and reports ErrSessionNotFound
+// once it has been revoked or has expired. It runs on every authenticated
+// request, so the last-used time is only written when it is stale.
+func (r *TokenRepository) TouchSession(ctx context.Context, familyID primitive.ObjectID) error {
+	filter := activeSessionFilter()
+	filter["family_id"] = familyID
+	opts := options.FindOne().SetProjection(bson.M{"last_used_at": 1})
+
+	var session tokenpkg.Token
+	err := r.collection.FindOne(ctx, filter, opts).Decode(&session)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return tokenpkg.ErrSessionNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if now.Sub(session.LastUsedAt) < sessionTouchInterval {
+		return nil
+	}
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": session.ID}, bson.M{"$set": bson.M{"last_used_at": now}})
+	return err
+}
+
+// DeleteSession revokes one session, scoped to its owner
+func (r *TokenRepository) DeleteSession(ctx context.Context, userID, familyID primitive.ObjectID) error {
+	res, err := r.collection.DeleteMany(ctx, bson.M{"user_id": userID, "family_id": familyID})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return tokenpkg.ErrSessionNotFound
+	}
+	return nil
+}
+
+// DeleteOtherSessions revokes every session of the user except keepFamilyID
+func (r *TokenRepository) DeleteOtherSessions(ctx context.Context, userID, keepFamilyID primitive.ObjectID) error {
+	filter := bson.M{"user_id": userID, "family_id": bson.M{"$ne": keepFamilyID}}
+	_, err := r.collection.DeleteMany(ctx, filter)
+	return err
+}
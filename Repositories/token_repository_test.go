@@ -172,3 +172,160 @@ func (s *tokenRepositoryTestSuite) TestDeleteTokensByUserID() {
 	assert.NoError(err)
 	assert.Equal(int64(0), countAfter)
 }
+
+func (s *tokenRepositoryTestSuite) TestMarkTokenRotated() {
+	assert := assert.New(s.T())
+
+	token := userpkg.Token{
+		ID:           primitive.NewObjectID(),
+		UserID:       primitive.NewObjectID(),
+		FamilyID:     primitive.NewObjectID(),
+		RefreshToken: "rotate-me",
+		CreatedAt:    time.Now(),
+		ExpiresAt:    time.Now().Add(1 * time.Hour),
+	}
+	s.Require().NoError(s.repo.StoreToken(s.ctx, token))
+
+	// First rotation wins
+	err := s.repo.MarkTokenRotated(s.ctx, token.ID)
+	assert.NoError(err)
+
+	found, err := s.repo.FindByRefreshToken(s.ctx, "rotate-me")
+	assert.NoError(err)
+	assert.NotNil(found.RotatedAt)
+
+	// Second rotation is a reuse
+	err = s.repo.MarkTokenRotated(s.ctx, token.ID)
+	assert.ErrorIs(err, userpkg.ErrRefreshTokenReused)
+}
+
+func (s *tokenRepositoryTestSuite) TestDeleteTokensByFamilyID() {
+	assert := assert.New(s.T())
+
+	userID := primitive.NewObjectID()
+	familyID := primitive.NewObjectID()
+	otherFamilyID := primitive.NewObjectID()
+
+	tokens := []interface{}{
+		userpkg.Token{UserID: userID, FamilyID: familyID, RefreshToken: "family-1", ExpiresAt: time.Now().Add(time.Hour)},
+		userpkg.Token{UserID: userID, FamilyID: familyID, RefreshToken: "family-2", ExpiresAt: time.Now().Add(time.Hour)},
+		userpkg.Token{UserID: userID, FamilyID: otherFamilyID, RefreshToken: "other-1", ExpiresAt: time.Now().Add(time.Hour)},
+	}
+	_, err := s.collection.InsertMany(s.ctx, tokens)
+	s.Require().NoError(err)
+
+	err = s.repo.DeleteTokensByFamilyID(s.ctx, familyID)
+	assert.NoError(err)
+
+	count, err := s.collection.CountDocuments(s.ctx, bson.M{"family_id": familyID})
+	assert.NoError(err)
+	assert.Equal(int64(0), count)
+
+	// Other sessions of the same user survive
+	count, err = s.collection.CountDocuments(s.ctx, bson.M{"user_id": userID})
+	assert.NoError(err)
+	assert.Equal(int64(1), count)
+}
+
+func (s *tokenRepositoryTestSuite) TestFindActiveSessionsByUserID() {
+	assert := assert.New(s.T())
+
+	userID := primitive.NewObjectID()
+	rotatedAt := time.Now()
+	tokens := []interface{}{
+		userpkg.Token{UserID: userID, FamilyID: primitive.NewObjectID(), RefreshToken: "live", ExpiresAt: time.Now().Add(time.Hour)},
+		userpkg.Token{UserID: userID, FamilyID: primitive.NewObjectID(), RefreshToken: "rotated", ExpiresAt: time.Now().Add(time.Hour), RotatedAt: &rotatedAt},
+		userpkg.Token{UserID: userID, FamilyID: primitive.NewObjectID(), RefreshToken: "expired", ExpiresAt: time.Now().Add(-time.Hour)},
+	}
+	_, err := s.collection.InsertMany(s.ctx, tokens)
+	s.Require().NoError(err)
+
+	found, err := s.repo.FindActiveSessionsByUserID(s.ctx, userID)
+	assert.NoError(err)
+	assert.Len(found, 1)
+	assert.Equal("live", found[0].RefreshToken)
+}
+
+func (s *tokenRepositoryTestSuite) TestTouchSession() {
+	assert := assert.New(s.T())
+
+	familyID := primitive.NewObjectID()
+	s.Require().NoError(s.repo.StoreToken(s.ctx, userpkg.Token{
+		UserID:       primitive.NewObjectID(),
+		FamilyID:     familyID,
+		RefreshToken: "touch-me",
+		ExpiresAt:    time.Now().Add(time.Hour),
+	}))
+
+	assert.NoError(s.repo.TouchSession(s.ctx, familyID))
+
+	found, err := s.repo.FindByRefreshToken(s.ctx, "touch-me")
+	assert.NoError(err)
+	assert.WithinDuration(time.Now(), found.LastUsedAt, 5*time.Second)
+
+	err = s.repo.TouchSession(s.ctx, primitive.NewObjectID())
+	assert.ErrorIs(err, userpkg.ErrSessionNotFound)
+}
+
+func (s *tokenRepositoryTestSuite) TestTouchSession_SkipsWriteWhenRecent() {
+	assert := assert.New(s.T())
+
+	familyID := primitive.NewObjectID()
+	recent := time.Now().Add(-10 * time.Second).Truncate(time.Millisecond)
+	s.Require().NoError(s.repo.StoreToken(s.ctx, userpkg.Token{
+		UserID:       primitive.NewObjectID(),
+		FamilyID:     familyID,
+		RefreshToken: "busy",
+		ExpiresAt:    time.Now().Add(time.Hour),
+		LastUsedAt:   recent,
+	}))
+
+	assert.NoError(s.repo.TouchSession(s.ctx, familyID))
+
+	found, err := s.repo.FindByRefreshToken(s.ctx, "busy")
+	assert.NoError(err)
+	assert.True(found.LastUsedAt.Equal(recent), "a session used seconds ago is not written again")
+}
+
+func (s *tokenRepositoryTestSuite) TestDeleteSession_ScopedToOwner() {
+	assert := assert.New(s.T())
+
+	owner := primitive.NewObjectID()
+	familyID := primitive.NewObjectID()
+	s.Require().NoError(s.repo.StoreToken(s.ctx, userpkg.Token{
+		UserID:       owner,
+		FamilyID:     familyID,
+		RefreshToken: "owned",
+		ExpiresAt:    time.Now().Add(time.Hour),
+	}))
+
+	err := s.repo.DeleteSession(s.ctx, primitive.NewObjectID(), familyID)
+	assert.ErrorIs(err, userpkg.ErrSessionNotFound)
+
+	err = s.repo.DeleteSession(s.ctx, owner, familyID)
+	assert.NoError(err)
+
+	_, err = s.repo.FindByRefreshToken(s.ctx, "owned")
+	assert.Equal(mongo.ErrNoDocuments, err)
+}
+
+func (s *tokenRepositoryTestSuite) TestDeleteOtherSessions() {
+	assert := assert.New(s.T())
+
+	userID := primitive.NewObjectID()
+	keep := primitive.NewObjectID()
+	tokens := []interface{}{
+		userpkg.Token{UserID: userID, FamilyID: keep, RefreshToken: "keep", ExpiresAt: time.Now().Add(time.Hour)},
+		userpkg.Token{UserID: userID, FamilyID: primitive.NewObjectID(), RefreshToken: "drop-1", ExpiresAt: time.Now().Add(time.Hour)},
+		userpkg.Token{UserID: userID, RefreshToken: "drop-legacy", ExpiresAt: time.Now().Add(time.Hour)},
+	}
+	_, err := s.collection.InsertMany(s.ctx, tokens)
+	s.Require().NoError(err)
+
+	err = s.repo.DeleteOtherSessions(s.ctx, userID, keep)
+	assert.NoError(err)
+
+	count, err := s.collection.CountDocuments(s.ctx, bson.M{"user_id": userID})
+	assert.NoError(err)
+	assert.Equal(int64(1), count)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"reflect"
+	"regexp"
+	"strings"
 	"time"
 
 	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
@@ -101,6 +103,16 @@ func (ur *UserRepository) FindByID(ctx context.Context, userID string) (userpkg.
 	return user, nil
 }
 
+// FindByEmail looks a user up by exact email address
+func (ur *UserRepository) FindByEmail(ctx context.Context, email string) (userpkg.User, error) {
+	var user userpkg.User
+	err := ur.collection.FindOne(ctx, bson.M{"email": email}).Decode(&user)
+	if err == mongo.ErrNoDocuments {
+		return userpkg.User{}, errors.New("user not found")
+	}
+	return user, err
+}
+
 func (ur *UserRepository) UpdateUserRoleByID(ctx context.Context, userID, role string) error {
 	oid, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
@@ -162,6 +174,358 @@ func (ur *UserRepository) UpdateIsVerifiedByEmail(ctx context.Context, email str
 	return nil
 }
 
+// UpdateMFA replaces the user's two-factor settings
+func (ur *UserRepository) UpdateMFA(ctx context.Context, userID string, mfa userpkg.MFASettings) error {
+	oid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+	res, err := ur.collection.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": bson.M{"mfa": mfa}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+// ConsumeTOTPStep records step as used. Steps only move forward, so a code
+// (or an older one) cannot be accepted twice.
+func (ur *UserRepository) ConsumeTOTPStep(ctx context.Context, userID string, step int64) error {
+	oid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+	filter := bson.M{
+		"_id":         oid,
+		"mfa.enabled": true,
+		"$or": []bson.M{
+			{"mfa.lastUsedStep": bson.M{"$lt": step}},
+			{"mfa.lastUsedStep": bson.M{"$exists": false}},
+		},
+	}
+	res, err := ur.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"mfa.lastUsedStep": step}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return userpkg.ErrMFACodeUsed
+	}
+	return nil
+}
+
+// ConsumeRecoveryCode removes a recovery code hash; it fails if the code was
+// already removed by a concurrent login.
+func (ur *UserRepository) ConsumeRecoveryCode(ctx context.Context, userID, codeHash string) error {
+	oid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+	filter := bson.M{"_id": oid, "mfa.recoveryCodes": codeHash}
+	res, err := ur.collection.UpdateOne(ctx, filter, bson.M{"$pull": bson.M{"mfa.recoveryCodes": codeHash}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return userpkg.ErrMFACodeUsed
+	}
+	return nil
+}
+
+// UpdateRecoveryCodes replaces the recovery code hashes of an MFA-enabled user
+func (ur *UserRepository) UpdateRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error {
+	oid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+	filter := bson.M{"_id": oid, "mfa.enabled": true}
+	res, err := ur.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"mfa.recoveryCodes": codeHashes}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+// UpdateEmail moves the account to a confirmed address, which also counts as
+// verifying it
+func (ur *UserRepository) UpdateEmail(ctx context.Context, userID, email string) error {
+	oid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+	update := bson.M{"$set": bson.M{"email": email, "isVerified": true, "updatedAt": time.Now()}}
+	res, err := ur.collection.UpdateOne(ctx, bson.M{"_id": oid}, update)
+	if mongo.IsDuplicateKeyError(err) {
+		return userpkg.ErrEmailTaken
+	}
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+// UpdatePassword sets a new hash and appends the old one to the password
+// history in the same write, trimming it to the newest keepHistory entries
+func (ur *UserRepository) UpdatePassword(ctx context.Context, userID, hashedPassword string, keepHistory int) error {
+	oid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+	// $literal keeps the hash, which starts with "$", from being read as a field path
+	set := bson.M{"password": bson.M{"$literal": hashedPassword}, "updatedAt": time.Now()}
+	if keepHistory > 0 {
+		// Pipeline stages read the document as it was, so $password is the old hash
+		set["passwordHistory"] = bson.M{"$slice": bson.A{
+			bson.M{"$concatArrays": bson.A{bson.M{"$ifNull": bson.A{"$passwordHistory", bson.A{}}}, bson.A{"$password"}}},
+			-keepHistory,
+		}}
+	}
+	res, err := ur.collection.UpdateOne(ctx, bson.M{"_id": oid}, mongo.Pipeline{{{Key: "$set", Value: set}}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+// ListUsers returns one page of users matching filter, newest first, and
+// the total number of matches. Signup dates come from the ObjectID.
+func (ur *UserRepository) ListUsers(ctx context.Context, filter userpkg.UserFilter) ([]userpkg.User, int64, error) {
+	and := bson.A{bson.M{"_id": bson.M{"$ne": userpkg.TombstoneUserID}}}
+	if q := strings.TrimSpace(filter.Query); q != "" {
+		pattern := bson.M{"$regex": regexp.QuoteMeta(q), "$options": "i"}
+		and = append(and, bson.M{"$or": bson.A{
+			bson.M{"username": pattern},
+			bson.M{"email": pattern},
+			bson.M{"fullname": pattern},
+			bson.M{"displayName": pattern},
+		}})
+	}
+	if filter.Role != "" {
+		and = append(and, bson.M{"role": filter.Role})
+	}
+	if filter.IsVerified != nil {
+		and = append(and, bson.M{"isVerified": *filter.IsVerified})
+	}
+	if filter.IsMentor != nil {
+		and = append(and, bson.M{"isMentor": *filter.IsMentor})
+	}
+	if filter.Suspended != nil {
+		inForce := bson.M{"suspension": bson.M{"$ne": nil}, "$or": bson.A{
+			bson.M{"suspension.expiresAt": bson.M{"$exists": false}},
+			bson.M{"suspension.expiresAt": bson.M{"$gt": time.Now()}},
+		}}
+		if *filter.Suspended {
+			and = append(and, inForce)
+		} else {
+			and = append(and, bson.M{"$nor": bson.A{inForce}})
+		}
+	}
+	if filter.SignedUpFrom != nil || filter.SignedUpTo != nil {
+		idRange := bson.M{}
+		if filter.SignedUpFrom != nil {
+			idRange["$gte"] = primitive.NewObjectIDFromTimestamp(*filter.SignedUpFrom)
+		}
+		if filter.SignedUpTo != nil {
+			idRange["$lt"] = primitive.NewObjectIDFromTimestamp(*filter.SignedUpTo)
+		}
+		and = append(and, bson.M{"_id": idRange})
+	}
+	query := bson.M{"$and": and}
+
+	total, err := ur.collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetSkip(int64((filter.Page - 1) * filter.PageSize)).
+		SetLimit(int64(filter.PageSize)).
+		SetProjection(bson.M{"password": 0, "passwordHistory": 0})
+	cursor, err := ur.collection.Find(ctx, query, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	users := []userpkg.User{}
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+// UpdateSuspension sets or, when suspension is nil, lifts a suspension
+func (ur *UserRepository) UpdateSuspension(ctx context.Context, userID string, suspension *userpkg.Suspension) error {
+	oid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+	update := bson.M{"$unset": bson.M{"suspension": ""}, "$set": bson.M{"updatedAt": time.Now()}}
+	if suspension != nil {
+		update = bson.M{"$set": bson.M{"suspension": suspension, "updatedAt": time.Now()}}
+	}
+	res, err := ur.collection.UpdateOne(ctx, bson.M{"_id": oid}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+// UpdateStudentStatus sets or, when status is nil, clears the student status
+func (ur *UserRepository) UpdateStudentStatus(ctx context.Context, userID string, status *userpkg.StudentStatus) error {
+	oid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+	update := bson.M{"$unset": bson.M{"student": ""}, "$set": bson.M{"updatedAt": time.Now()}}
+	if status != nil {
+		update = bson.M{"$set": bson.M{"student": status, "updatedAt": time.Now()}}
+	}
+	res, err := ur.collection.UpdateOne(ctx, bson.M{"_id": oid}, update)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+func (ur *UserRepository) StudentEmailTaken(ctx context.Context, email, userID string) (bool, error) {
+	filter := bson.M{"student.email": email}
+	if oid, err := primitive.ObjectIDFromHex(userID); err == nil {
+		filter["_id"] = bson.M{"$ne": oid}
+	}
+	count, err := ur.collection.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	return count > 0, err
+}
+
+// FindStudentIDs returns the IDs of the institution's verified students
+func (ur *UserRepository) FindStudentIDs(ctx context.Context, institutionID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+	cursor, err := ur.collection.Find(ctx, bson.M{"student.institutionId": institutionID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	ids := []primitive.ObjectID{}
+	for cursor.Next(ctx) {
+		var doc struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		ids = append(ids, doc.ID)
+	}
+	return ids, cursor.Err()
+}
+
+// RenameStudentInstitution updates the institution name copied into its
+// students' status
+func (ur *UserRepository) RenameStudentInstitution(ctx context.Context, institutionID primitive.ObjectID, name string) error {
+	_, err := ur.collection.UpdateMany(ctx,
+		bson.M{"student.institutionId": institutionID},
+		bson.M{"$set": bson.M{"student.institution": name}})
+	return err
+}
+
+func (ur *UserRepository) ClearStudentInstitution(ctx context.Context, institutionID primitive.ObjectID) (int64, error) {
+	res, err := ur.collection.UpdateMany(ctx,
+		bson.M{"student.institutionId": institutionID},
+		bson.M{"$unset": bson.M{"student": ""}, "$set": bson.M{"updatedAt": time.Now()}})
+	if err != nil {
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
+
+// FindByIdentity looks a user up by a linked OIDC account
+func (ur *UserRepository) FindByIdentity(ctx context.Context, provider, subject string) (userpkg.User, error) {
+	var user userpkg.User
+	filter := bson.M{"identities": bson.M{"$elemMatch": bson.M{"provider": provider, "subject": subject}}}
+	err := ur.collection.FindOne(ctx, filter).Decode(&user)
+	if err == mongo.ErrNoDocuments {
+		return userpkg.User{}, errors.New("user not found")
+	}
+	return user, err
+}
+
+// AddIdentity links an OIDC account in one write, dropping any earlier link
+// to the same provider
+func (ur *UserRepository) AddIdentity(ctx context.Context, userID string, identity userpkg.LinkedIdentity) error {
+	oid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+	kept := bson.M{"$filter": bson.M{
+		"input": bson.M{"$ifNull": bson.A{"$identities", bson.A{}}},
+		"cond":  bson.M{"$ne": bson.A{"$$this.provider", identity.Provider}},
+	}}
+	// $literal keeps provider-supplied values from being read as field paths
+	set := bson.M{
+		"identities": bson.M{"$concatArrays": bson.A{kept, bson.A{bson.M{"$literal": identity}}}},
+		"updatedAt":  time.Now(),
+	}
+	res, err := ur.collection.UpdateOne(ctx, bson.M{"_id": oid}, mongo.Pipeline{{{Key: "$set", Value: set}}})
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+// DeleteUser removes the account document itself
+func (ur *UserRepository) DeleteUser(ctx context.Context, userID string) error {
+	oid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+	_, err = ur.collection.DeleteOne(ctx, bson.M{"_id": oid})
+	return err
+}
+
+func (ur *UserRepository) EnsureTombstoneUser(ctx context.Context) (userpkg.User, error) {
+	filter := bson.M{"_id": userpkg.TombstoneUserID}
+	// No password and never verified, so nobody can sign in as it
+	update := bson.M{"$setOnInsert": bson.M{
+		"username":    userpkg.TombstoneUsername,
+		"fullname":    "Deleted user",
+		"displayName": "Deleted user",
+		"email":       userpkg.TombstoneUsername + "@invalid",
+		"password":    "",
+		"role":        "user",
+		"isVerified":  false,
+		"isAnonymous": false,
+		"updatedAt":   time.Now(),
+	}}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var user userpkg.User
+	err := ur.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&user)
+	if mongo.IsDuplicateKeyError(err) {
+		// Another worker created it first
+		err = ur.collection.FindOne(ctx, filter).Decode(&user)
+	}
+	return user, err
+}
+
 func (ur *UserRepository) UpdateProfile(ctx context.Context, userID string, updates userpkg.UpdateProfileRequest) (userpkg.User, error) {
 	oid, err := primitive.ObjectIDFromHex(userID)
 	if err != nil {
@@ -181,6 +545,9 @@ func (ur *UserRepository) UpdateProfile(ctx context.Context, userID string, upda
 	if updates.ProfilePicture != "" {
 		updateDoc["$set"].(bson.M)["profilePicture"] = updates.ProfilePicture
 	}
+	if updates.Locale != "" {
+		updateDoc["$set"].(bson.M)["locale"] = updates.Locale
+	}
 	// Only update contactInfo if it is not empty
 	if !reflect.DeepEqual(updates.ContactInfo, userpkg.ContactInfo{}) {
 		updateDoc["$set"].(bson.M)["contactInfo"] = updates.ContactInfo
@@ -230,38 +597,20 @@ func (ur *UserRepository) GetPublicProfile(ctx context.Context, userID string) (
 		return userpkg.PublicProfile{}, err
 	}
 
-	// Build public profile respecting privacy settings
-	profile := userpkg.PublicProfile{
-		ID:                    user.ID,
-		DisplayName:           user.DisplayName,
-		Bio:                   user.Bio,
-		IsMentor:              user.IsMentor,
-		IsMentee:              user.IsMentee,
-		MentorshipTopics:      user.MentorshipTopics,
-		MentorshipBio:         user.MentorshipBio,
-		AvailableForMentoring: user.AvailableForMentoring,
-	}
-
-	// Include private information only if privacy settings allow
-	if user.PrivacySettings.ShowRealName {
-		profile.Fullname = user.Fullname
-	}
-	if user.PrivacySettings.ShowProfilePicture {
-		profile.ProfilePicture = user.ProfilePicture
-	}
-	if user.PrivacySettings.ShowContactInfo {
-		profile.ContactInfo = user.ContactInfo
-	}
-
-	return profile, nil
+	return ur.buildPublicProfile(user), nil
 }
 
 // Find mentors by topics with pagination
-func (ur *UserRepository) FindMentors(ctx context.Context, topics []string, limit int, offset int) ([]userpkg.PublicProfile, error) {
+func (ur *UserRepository) FindMentors(ctx context.Context, topics []string, institutionID primitive.ObjectID, limit int, offset int) ([]userpkg.PublicProfile, error) {
 	filter := bson.M{
 		"isMentor":              true,
 		"availableForMentoring": true,
-		"mentorshipTopics":      bson.M{"$in": topics},
+	}
+	if len(topics) > 0 {
+		filter["mentorshipTopics"] = bson.M{"$in": topics}
+	}
+	if !institutionID.IsZero() {
+		filter["student.institutionId"] = institutionID
 	}
 
 	opts := options.Find().SetLimit(int64(limit)).SetSkip(int64(offset))
@@ -359,6 +708,10 @@ func (ur *UserRepository) buildPublicProfile(user userpkg.User) userpkg.PublicPr
 		MentorshipBio:         user.MentorshipBio,
 		AvailableForMentoring: user.AvailableForMentoring,
 	}
+	if user.Student != nil {
+		profile.VerifiedStudent = true
+		profile.Institution = &userpkg.InstitutionSummary{ID: user.Student.InstitutionID, Name: user.Student.Institution}
+	}
 
 	// Include private information only if privacy settings allow
 	if user.PrivacySettings.ShowRealName {
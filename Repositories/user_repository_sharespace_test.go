@@ -153,26 +153,78 @@ func (s *userShareSpaceTestSuite) TestFindMentors() {
 	s.createTestUser("StudentSeeker", false, true, []string{"Technology Skills"})
 
 	// Search for mentors by topic
-	mentors, err := s.repo.FindMentors(s.ctx, []string{"Technology Skills"}, 10, 0)
+	mentors, err := s.repo.FindMentors(s.ctx, []string{"Technology Skills"}, primitive.NilObjectID, 10, 0)
 	s.Require().NoError(err)
 	s.Len(mentors, 1)
 	s.Equal("TechMentor", mentors[0].DisplayName)
 
 	// Search for mentors by multiple topics
-	mentors, err = s.repo.FindMentors(s.ctx, []string{"Career Guidance"}, 10, 0)
+	mentors, err = s.repo.FindMentors(s.ctx, []string{"Career Guidance"}, primitive.NilObjectID, 10, 0)
 	s.Require().NoError(err)
 	s.Len(mentors, 2) // TechMentor and CareerMentor
 
 	// Test pagination
-	mentors, err = s.repo.FindMentors(s.ctx, []string{"Career Guidance"}, 1, 0)
+	mentors, err = s.repo.FindMentors(s.ctx, []string{"Career Guidance"}, primitive.NilObjectID, 1, 0)
 	s.Require().NoError(err)
 	s.Len(mentors, 1)
 
-	mentors, err = s.repo.FindMentors(s.ctx, []string{"Career Guidance"}, 1, 1)
+	mentors, err = s.repo.FindMentors(s.ctx, []string{"Career Guidance"}, primitive.NilObjectID, 1, 1)
 	s.Require().NoError(err)
 	s.Len(mentors, 1)
 }
 
+func (s *userShareSpaceTestSuite) TestStudentStatus() {
+	institutionID := primitive.NewObjectID()
+	student := s.createTestUser("CampusMentor", true, false, []string{"Career Guidance"})
+	s.createTestUser("OtherMentor", true, false, []string{"Career Guidance"})
+
+	status := &userpkg.StudentStatus{
+		InstitutionID: institutionID,
+		Institution:   "Addis Ababa University",
+		Email:         "campus@aau.edu.et",
+		VerifiedAt:    time.Now(),
+	}
+	s.Require().NoError(s.repo.UpdateStudentStatus(s.ctx, student.ID.Hex(), status))
+
+	profile, err := s.repo.GetPublicProfile(s.ctx, student.ID.Hex())
+	s.Require().NoError(err)
+	s.True(profile.VerifiedStudent)
+	s.Equal(&userpkg.InstitutionSummary{ID: institutionID, Name: "Addis Ababa University"}, profile.Institution)
+
+	taken, err := s.repo.StudentEmailTaken(s.ctx, "campus@aau.edu.et", primitive.NewObjectID().Hex())
+	s.Require().NoError(err)
+	s.True(taken)
+	taken, err = s.repo.StudentEmailTaken(s.ctx, "campus@aau.edu.et", student.ID.Hex())
+	s.Require().NoError(err)
+	s.False(taken)
+
+	// Without topics every mentor matches; the institution narrows them
+	mentors, err := s.repo.FindMentors(s.ctx, nil, primitive.NilObjectID, 10, 0)
+	s.Require().NoError(err)
+	s.Len(mentors, 2)
+	mentors, err = s.repo.FindMentors(s.ctx, []string{"Career Guidance"}, institutionID, 10, 0)
+	s.Require().NoError(err)
+	s.Require().Len(mentors, 1)
+	s.Equal("CampusMentor", mentors[0].DisplayName)
+
+	ids, err := s.repo.FindStudentIDs(s.ctx, institutionID)
+	s.Require().NoError(err)
+	s.Equal([]primitive.ObjectID{student.ID}, ids)
+
+	s.Require().NoError(s.repo.RenameStudentInstitution(s.ctx, institutionID, "AAU"))
+	profile, err = s.repo.GetPublicProfile(s.ctx, student.ID.Hex())
+	s.Require().NoError(err)
+	s.Equal("AAU", profile.Institution.Name)
+
+	cleared, err := s.repo.ClearStudentInstitution(s.ctx, institutionID)
+	s.Require().NoError(err)
+	s.Equal(int64(1), cleared)
+	profile, err = s.repo.GetPublicProfile(s.ctx, student.ID.Hex())
+	s.Require().NoError(err)
+	s.False(profile.VerifiedStudent)
+	s.Nil(profile.Institution)
+}
+
 func (s *userShareSpaceTestSuite) TestFindMentees() {
 	// Create test mentees
 	s.createTestUser("TechStudent", false, true, []string{"Technology Skills", "Career Guidance"})
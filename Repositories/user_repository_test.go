@@ -290,3 +290,103 @@ func (s *userRepositoryTestSuite) TestGetUserProfile_NotFound() {
 	s.Contains(err.Error(), "no documents in result", "Error message mismatch")
 	s.Equal(userpkg.User{}, got, "Expected empty user object")
 }
+
+func (s *userRepositoryTestSuite) TestConsumeTOTPStep_OnlyMovesForward() {
+	created, err := s.repo.CreateUser(s.ctx, userpkg.User{Username: "mfauser", Email: "mfa@example.com", Password: "x"})
+	s.Require().NoError(err)
+	id := created.ID.Hex()
+	s.Require().NoError(s.repo.UpdateMFA(s.ctx, id, userpkg.MFASettings{Enabled: true, Secret: "S", LastUsedStep: 10}))
+
+	s.NoError(s.repo.ConsumeTOTPStep(s.ctx, id, 11))
+	s.ErrorIs(s.repo.ConsumeTOTPStep(s.ctx, id, 11), userpkg.ErrMFACodeUsed)
+	s.ErrorIs(s.repo.ConsumeTOTPStep(s.ctx, id, 10), userpkg.ErrMFACodeUsed)
+}
+
+func (s *userRepositoryTestSuite) TestConsumeRecoveryCode_SingleUse() {
+	created, err := s.repo.CreateUser(s.ctx, userpkg.User{Username: "mfauser", Email: "mfa@example.com", Password: "x"})
+	s.Require().NoError(err)
+	id := created.ID.Hex()
+	s.Require().NoError(s.repo.UpdateMFA(s.ctx, id, userpkg.MFASettings{Enabled: true, RecoveryCodes: []string{"h1", "h2"}}))
+
+	s.NoError(s.repo.ConsumeRecoveryCode(s.ctx, id, "h1"))
+	s.ErrorIs(s.repo.ConsumeRecoveryCode(s.ctx, id, "h1"), userpkg.ErrMFACodeUsed)
+
+	user, err := s.repo.FindByID(s.ctx, id)
+	s.Require().NoError(err)
+	s.Equal([]string{"h2"}, user.MFA.RecoveryCodes)
+}
+
+func (s *userRepositoryTestSuite) TestUpdatePassword_KeepsRecentHistory() {
+	// Real hashes start with "$", which a pipeline would read as a field path
+	hashes := []string{"$2a$10$first", "$argon2id$v=19$second", "$argon2id$v=19$third", "$argon2id$v=19$fourth"}
+	created, err := s.repo.CreateUser(s.ctx, userpkg.User{Username: "pwuser", Email: "pw@example.com", Password: hashes[0]})
+	s.Require().NoError(err)
+	id := created.ID.Hex()
+
+	s.NoError(s.repo.UpdatePassword(s.ctx, id, hashes[1], 2))
+	s.NoError(s.repo.UpdatePassword(s.ctx, id, hashes[2], 2))
+	s.NoError(s.repo.UpdatePassword(s.ctx, id, hashes[3], 2))
+
+	user, err := s.repo.FindByID(s.ctx, id)
+	s.Require().NoError(err)
+	s.Equal(hashes[3], user.Password)
+	s.Equal(hashes[1:3], user.PasswordHistory)
+}
+
+func (s *userRepositoryTestSuite) TestListUsers_FiltersSuspended() {
+	active, err := s.repo.CreateUser(s.ctx, userpkg.User{Username: "alice", Email: "alice@example.com", Password: "x", Role: "user"})
+	s.Require().NoError(err)
+	banned, err := s.repo.CreateUser(s.ctx, userpkg.User{Username: "bob", Email: "bob@example.com", Password: "x", Role: "user"})
+	s.Require().NoError(err)
+	lapsed, err := s.repo.CreateUser(s.ctx, userpkg.User{Username: "carol", Email: "carol@example.com", Password: "x", Role: "user"})
+	s.Require().NoError(err)
+
+	s.NoError(s.repo.UpdateSuspension(s.ctx, banned.ID.Hex(), &userpkg.Suspension{Reason: "spam", SuspendedAt: time.Now()}))
+	past := time.Now().Add(-time.Hour)
+	s.NoError(s.repo.UpdateSuspension(s.ctx, lapsed.ID.Hex(), &userpkg.Suspension{Reason: "cool off", SuspendedAt: time.Now(), ExpiresAt: &past}))
+
+	yes, no := true, false
+	users, total, err := s.repo.ListUsers(s.ctx, userpkg.UserFilter{Suspended: &yes, Page: 1, PageSize: 10})
+	s.Require().NoError(err)
+	s.Equal(int64(1), total)
+	s.Equal(banned.ID, users[0].ID)
+	s.Empty(users[0].Password)
+
+	users, total, err = s.repo.ListUsers(s.ctx, userpkg.UserFilter{Suspended: &no, Query: "A", Page: 1, PageSize: 10})
+	s.Require().NoError(err)
+	s.Equal(int64(2), total)
+	s.ElementsMatch([]primitive.ObjectID{active.ID, lapsed.ID}, []primitive.ObjectID{users[0].ID, users[1].ID})
+}
+
+func (s *userRepositoryTestSuite) TestUpdateSuspension_Lift() {
+	created, err := s.repo.CreateUser(s.ctx, userpkg.User{Username: "dave", Email: "dave@example.com", Password: "x"})
+	s.Require().NoError(err)
+	id := created.ID.Hex()
+
+	s.NoError(s.repo.UpdateSuspension(s.ctx, id, &userpkg.Suspension{Reason: "spam", SuspendedAt: time.Now()}))
+	s.NoError(s.repo.UpdateSuspension(s.ctx, id, nil))
+
+	user, err := s.repo.FindByID(s.ctx, id)
+	s.Require().NoError(err)
+	s.Nil(user.Suspension)
+}
+
+func (s *userRepositoryTestSuite) TestAddIdentity_ReplacesSameProvider() {
+	created, err := s.repo.CreateUser(s.ctx, userpkg.User{Username: "erin", Email: "erin@example.com", Password: "x"})
+	s.Require().NoError(err)
+	id := created.ID.Hex()
+
+	s.NoError(s.repo.AddIdentity(s.ctx, id, userpkg.LinkedIdentity{Provider: "google", Subject: "old", LinkedAt: time.Now()}))
+	s.NoError(s.repo.AddIdentity(s.ctx, id, userpkg.LinkedIdentity{Provider: "campus", Subject: "$sub", LinkedAt: time.Now()}))
+	s.NoError(s.repo.AddIdentity(s.ctx, id, userpkg.LinkedIdentity{Provider: "google", Subject: "new", LinkedAt: time.Now()}))
+
+	user, err := s.repo.FindByIdentity(s.ctx, "google", "new")
+	s.Require().NoError(err)
+	s.Equal(created.ID, user.ID)
+	s.Len(user.Identities, 2)
+
+	_, err = s.repo.FindByIdentity(s.ctx, "google", "old")
+	s.Error(err)
+	_, err = s.repo.FindByIdentity(s.ctx, "campus", "$sub")
+	s.NoError(err)
+}
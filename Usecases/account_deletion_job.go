@@ -0,0 +1,310 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	commentpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/comment"
+	mentorshippkg "github.com/Amaankaa/Blog-Starter-Project/Domain/mentorship"
+	msgpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/messaging"
+	postpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/post"
+	resourcepkg "github.com/Amaankaa/Blog-Starter-Project/Domain/resource"
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// accountDeletionLease is how long a worker may hold a deletion before
+// another worker assumes it crashed and takes over
+const accountDeletionLease = 15 * time.Minute
+
+const deletedAccountEndReason = "account deleted"
+
+// deletionBatchSize is how many mentorship records are fetched at a time
+const deletionBatchSize = 100
+
+// AccountDeletionJob erases accounts whose deletion grace period has passed.
+// Every step can be repeated safely, so a deletion that fails halfway is
+// simply retried once its lease runs out.
+type AccountDeletionJob struct {
+	deletions         userpkg.IAccountDeletionRepository
+	userRepo          userpkg.IUserRepository
+	tokenRepo         userpkg.ITokenRepository
+	verificationRepo  userpkg.IVerificationRepository
+	passwordResetRepo userpkg.IPasswordResetRepository
+	postRepo          postpkg.PostRepository
+	commentRepo       commentpkg.ICommentRepository
+	resourceRepo      resourcepkg.ResourceRepository
+	mentorshipRepo    mentorshippkg.IMentorshipRepository
+	messagingRepo     msgpkg.IMessagingRepository
+	accessTokens      userpkg.IPersonalAccessTokenRepository
+	exports           userpkg.IDataExportRepository
+	exportStore       userpkg.IExportStore
+	emailChanges      userpkg.IEmailChangeRepository
+	mfaChallenges     userpkg.IMFAChallengeRepository
+	loginAttempts     userpkg.ILoginAttemptRepository
+}
+
+func NewAccountDeletionJob(
+	deletions userpkg.IAccountDeletionRepository,
+	userRepo userpkg.IUserRepository,
+	tokenRepo userpkg.ITokenRepository,
+	verificationRepo userpkg.IVerificationRepository,
+	passwordResetRepo userpkg.IPasswordResetRepository,
+	postRepo postpkg.PostRepository,
+	commentRepo commentpkg.ICommentRepository,
+	resourceRepo resourcepkg.ResourceRepository,
+	mentorshipRepo mentorshippkg.IMentorshipRepository,
+	messagingRepo msgpkg.IMessagingRepository,
+	accessTokens userpkg.IPersonalAccessTokenRepository,
+	exports userpkg.IDataExportRepository,
+	exportStore userpkg.IExportStore,
+	emailChanges userpkg.IEmailChangeRepository,
+	mfaChallenges userpkg.IMFAChallengeRepository,
+	loginAttempts userpkg.ILoginAttemptRepository,
+) *AccountDeletionJob {
+	return &AccountDeletionJob{
+		deletions:         deletions,
+		userRepo:          userRepo,
+		tokenRepo:         tokenRepo,
+		verificationRepo:  verificationRepo,
+		passwordResetRepo: passwordResetRepo,
+		postRepo:          postRepo,
+		commentRepo:       commentRepo,
+		resourceRepo:      resourceRepo,
+		mentorshipRepo:    mentorshipRepo,
+		messagingRepo:     messagingRepo,
+		accessTokens:      accessTokens,
+		exports:           exports,
+		exportStore:       exportStore,
+		emailChanges:      emailChanges,
+		mfaChallenges:     mfaChallenges,
+		loginAttempts:     loginAttempts,
+	}
+}
+
+// Start runs the job every interval until ctx is cancelled
+func (j *AccountDeletionJob) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := j.RunOnce(ctx); err != nil {
+					log.Printf("account deletion: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// RunOnce erases every account that is due and returns how many were erased.
+// A failed account is logged and left for a later run.
+func (j *AccountDeletionJob) RunOnce(ctx context.Context) (int, error) {
+	erased := 0
+	for {
+		deletion, ok, err := j.deletions.ClaimDueDeletion(ctx, time.Now(), accountDeletionLease)
+		if err != nil {
+			return erased, fmt.Errorf("failed to claim deletion: %w", err)
+		}
+		if !ok {
+			return erased, nil
+		}
+
+		if err := j.erase(ctx, deletion); err != nil {
+			log.Printf("account deletion: failed to erase user=%s: %v", deletion.UserID.Hex(), err)
+			continue
+		}
+		if err := j.deletions.CompleteDeletion(ctx, deletion.UserID.Hex()); err != nil {
+			log.Printf("account deletion: failed to complete user=%s: %v", deletion.UserID.Hex(), err)
+			continue
+		}
+		log.Printf("security: account erased user=%s content=%s", deletion.UserID.Hex(), deletion.ContentMode)
+		erased++
+	}
+}
+
+func (j *AccountDeletionJob) erase(ctx context.Context, deletion userpkg.AccountDeletion) error {
+	userID := deletion.UserID
+	tombstone, err := j.userRepo.EnsureTombstoneUser(ctx)
+	if err != nil {
+		return fmt.Errorf("tombstone user: %w", err)
+	}
+
+	if err := j.endMentorships(ctx, userID); err != nil {
+		return fmt.Errorf("mentorships: %w", err)
+	}
+
+	if deletion.ContentMode == userpkg.DeletionContentDelete {
+		err = j.deleteContent(ctx, userID)
+	} else {
+		err = j.anonymizeContent(ctx, userID, tombstone.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("content: %w", err)
+	}
+
+	// Conversations belong to the other participants too, so messages stay
+	// but no longer point at the account
+	if err := j.messagingRepo.ReplaceParticipant(ctx, userID, tombstone.ID); err != nil {
+		return fmt.Errorf("messages: %w", err)
+	}
+
+	if err := j.tokenRepo.DeleteTokensByUserID(ctx, userID.Hex()); err != nil {
+		return fmt.Errorf("tokens: %w", err)
+	}
+	if err := j.accessTokens.DeleteAccessTokensByUserID(ctx, userID); err != nil {
+		return fmt.Errorf("access tokens: %w", err)
+	}
+	if err := j.deleteExports(ctx, userID); err != nil {
+		return fmt.Errorf("exports: %w", err)
+	}
+	if err := j.emailChanges.DeleteEmailChange(ctx, userID.Hex()); err != nil {
+		return fmt.Errorf("email change: %w", err)
+	}
+	if err := j.mfaChallenges.DeleteChallengesByUserID(ctx, userID); err != nil {
+		return fmt.Errorf("mfa challenges: %w", err)
+	}
+	if err := j.loginAttempts.ResetLoginAttempts(ctx, accountAttemptKey(userID.Hex())); err != nil {
+		return fmt.Errorf("login attempts: %w", err)
+	}
+	// Either may already be gone
+	_ = j.verificationRepo.DeleteVerification(ctx, deletion.Email)
+	_ = j.passwordResetRepo.DeleteResetRequest(ctx, deletion.Email)
+
+	if err := j.userRepo.DeleteUser(ctx, userID.Hex()); err != nil {
+		return fmt.Errorf("user: %w", err)
+	}
+	return nil
+}
+
+// deleteExports removes the user's exports and their archives. An export
+// still being built finds its record gone and drops the archive itself.
+func (j *AccountDeletionJob) deleteExports(ctx context.Context, userID primitive.ObjectID) error {
+	exports, err := j.exports.FindExportsByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, export := range exports {
+		if export.File != "" {
+			if err := j.exportStore.Delete(ctx, export.File); err != nil {
+				return err
+			}
+		}
+		if err := j.exports.DeleteExport(ctx, export.ID.Hex()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// endMentorships ends the user's running connections and cancels pending
+// requests in either direction
+func (j *AccountDeletionJob) endMentorships(ctx context.Context, userID primitive.ObjectID) error {
+	connections, err := j.mentorshipRepo.GetActiveConnectionsByUser(ctx, userID.Hex())
+	if err != nil {
+		return err
+	}
+	for _, conn := range connections {
+		if err := j.endConnection(ctx, conn, userID); err != nil {
+			return err
+		}
+	}
+
+	// Ended connections and canceled requests drop out of these searches, so
+	// each batch is searched from the start until none are left
+	paused := mentorshippkg.ConnectionPaused
+	for _, filters := range []mentorshippkg.ConnectionFilters{
+		{MenteeID: &userID, Status: &paused, Limit: deletionBatchSize},
+		{MentorID: &userID, Status: &paused, Limit: deletionBatchSize},
+	} {
+		ended := map[primitive.ObjectID]bool{}
+		for {
+			found, err := j.mentorshipRepo.SearchConnections(ctx, filters)
+			if err != nil {
+				return err
+			}
+			if len(found) == 0 {
+				break
+			}
+			for _, conn := range found {
+				if ended[conn.ID] {
+					return fmt.Errorf("connection %s still paused after ending it", conn.ID.Hex())
+				}
+				ended[conn.ID] = true
+				if err := j.endConnection(ctx, conn, userID); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	pending := mentorshippkg.StatusPending
+	for _, filters := range []mentorshippkg.RequestFilters{
+		{MenteeID: &userID, Status: &pending, Limit: deletionBatchSize},
+		{MentorID: &userID, Status: &pending, Limit: deletionBatchSize},
+	} {
+		canceled := map[primitive.ObjectID]bool{}
+		for {
+			requests, err := j.mentorshipRepo.SearchRequests(ctx, filters)
+			if err != nil {
+				return err
+			}
+			if len(requests) == 0 {
+				break
+			}
+			for _, req := range requests {
+				if canceled[req.ID] {
+					return fmt.Errorf("request %s still pending after canceling it", req.ID.Hex())
+				}
+				canceled[req.ID] = true
+				if err := j.mentorshipRepo.UpdateRequestStatus(ctx, req.ID.Hex(), mentorshippkg.StatusCanceled); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (j *AccountDeletionJob) endConnection(ctx context.Context, conn mentorshippkg.MentorshipConnection, userID primitive.ObjectID) error {
+	endedByMentor := conn.MentorID == userID
+	return j.mentorshipRepo.EndConnection(ctx, conn.ID.Hex(), deletedAccountEndReason, nil, "", endedByMentor)
+}
+
+func (j *AccountDeletionJob) deleteContent(ctx context.Context, userID primitive.ObjectID) error {
+	postIDs, err := j.postRepo.DeletePostsByAuthor(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := j.commentRepo.DeleteCommentsByPosts(ctx, postIDs); err != nil {
+		return err
+	}
+
+	counts, err := j.commentRepo.DeleteCommentsByAuthor(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for postID, n := range counts {
+		// The post may be gone or hidden; the count only matters on live posts
+		if err := j.postRepo.UpdateCommentsCount(ctx, postID, -n); err != nil {
+			log.Printf("account deletion: failed to update comment count post=%s: %v", postID.Hex(), err)
+		}
+	}
+
+	return j.resourceRepo.DeleteResourcesByCreator(ctx, userID)
+}
+
+func (j *AccountDeletionJob) anonymizeContent(ctx context.Context, userID, tombstoneID primitive.ObjectID) error {
+	if err := j.postRepo.ReassignPostsAuthor(ctx, userID, tombstoneID); err != nil {
+		return err
+	}
+	if err := j.commentRepo.ReassignCommentsAuthor(ctx, userID, tombstoneID); err != nil {
+		return err
+	}
+	return j.resourceRepo.ReassignResourcesCreator(ctx, userID, tombstoneID)
+}
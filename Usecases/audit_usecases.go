@@ -0,0 +1,157 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	auditpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/audit"
+)
+
+// maxAuditAppendAttempts bounds retries when other writers keep winning the
+// race for the next sequence number
+const maxAuditAppendAttempts = 5
+
+var errAuditStop = errors.New("stop")
+
+// AuditUsecase appends to and reads the hash-chained audit log
+type AuditUsecase struct {
+	repo auditpkg.IAuditRepository
+
+	// Appends from this process go one at a time; other replicas are handled
+	// by retrying on ErrSeqTaken
+	mu sync.Mutex
+}
+
+func NewAuditUsecase(repo auditpkg.IAuditRepository) *AuditUsecase {
+	return &AuditUsecase{repo: repo}
+}
+
+// Record links a new entry to the head of the chain
+func (au *AuditUsecase) Record(ctx context.Context, event auditpkg.Event) error {
+	before, err := auditSnapshot(event.Before)
+	if err != nil {
+		return err
+	}
+	after, err := auditSnapshot(event.After)
+	if err != nil {
+		return err
+	}
+
+	au.mu.Lock()
+	defer au.mu.Unlock()
+
+	for attempt := 0; attempt < maxAuditAppendAttempts; attempt++ {
+		head, found, err := au.repo.LastEntry(ctx)
+		if err != nil {
+			return err
+		}
+		entry := auditpkg.Entry{
+			Seq:        1,
+			ActorID:    event.ActorID,
+			Action:     event.Action,
+			TargetType: event.TargetType,
+			TargetID:   event.TargetID,
+			Before:     before,
+			After:      after,
+			IP:         auditpkg.ClientIP(ctx),
+			CreatedAt:  time.Now().UTC().Truncate(time.Millisecond),
+		}
+		if found {
+			entry.Seq = head.Seq + 1
+			entry.PrevHash = head.Hash
+		}
+		entry.Hash = entry.ComputeHash()
+
+		err = au.repo.InsertEntry(ctx, entry)
+		if !errors.Is(err, auditpkg.ErrSeqTaken) {
+			return err
+		}
+	}
+	return errors.New("audit log is busy, entry not recorded")
+}
+
+func (au *AuditUsecase) ListEntries(ctx context.Context, filter auditpkg.Filter) (auditpkg.EntryListResponse, error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 {
+		filter.PageSize = defaultUserPageSize
+	}
+	if filter.PageSize > maxUserPageSize {
+		filter.PageSize = maxUserPageSize
+	}
+
+	entries, total, err := au.repo.FindEntries(ctx, filter)
+	if err != nil {
+		return auditpkg.EntryListResponse{}, err
+	}
+	return auditpkg.EntryListResponse{
+		Entries:    entries,
+		Total:      total,
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		TotalPages: int((total + int64(filter.PageSize) - 1) / int64(filter.PageSize)),
+	}, nil
+}
+
+// ExportEntries streams every matching entry, oldest first, ignoring paging
+func (au *AuditUsecase) ExportEntries(ctx context.Context, filter auditpkg.Filter, fn func(auditpkg.Entry) error) error {
+	filter.Page, filter.PageSize = 0, 0
+	return au.repo.EachEntry(ctx, filter, fn)
+}
+
+// VerifyChain walks the chain from the start and reports the first entry
+// whose sequence, link or hash does not check out
+func (au *AuditUsecase) VerifyChain(ctx context.Context) (auditpkg.ChainVerification, error) {
+	result := auditpkg.ChainVerification{Valid: true}
+	var prev auditpkg.Entry
+
+	err := au.repo.EachEntry(ctx, auditpkg.Filter{}, func(e auditpkg.Entry) error {
+		var problem string
+		switch {
+		case e.Seq != prev.Seq+1:
+			problem = fmt.Sprintf("expected entry %d", prev.Seq+1)
+		case e.PrevHash != prev.Hash:
+			problem = "link to previous entry does not match"
+		case e.Hash != e.ComputeHash():
+			problem = "entry content does not match its hash"
+		}
+		if problem != "" {
+			result = auditpkg.ChainVerification{Checked: result.Checked, BrokenAtSeq: e.Seq, Problem: problem}
+			return errAuditStop
+		}
+		result.Checked++
+		prev = e
+		return nil
+	})
+	if err != nil && !errors.Is(err, errAuditStop) {
+		return auditpkg.ChainVerification{}, err
+	}
+	if !result.Valid {
+		log.Printf("security: audit chain broken at seq=%d: %s", result.BrokenAtSeq, result.Problem)
+	}
+	return result, nil
+}
+
+func auditSnapshot(v interface{}) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	return json.Marshal(v)
+}
+
+// recordAudit writes event through logger when one is configured. The action
+// has already happened, so a failure is logged rather than returned.
+func recordAudit(ctx context.Context, logger auditpkg.IAuditLogger, event auditpkg.Event) {
+	if logger == nil {
+		return
+	}
+	if err := logger.Record(ctx, event); err != nil {
+		log.Printf("audit: failed to record %s on %s %s by %s: %v", event.Action, event.TargetType, event.TargetID, event.ActorID, err)
+	}
+}
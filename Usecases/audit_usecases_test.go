@@ -0,0 +1,135 @@
+package usecases_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	auditpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/audit"
+	usecases "github.com/Amaankaa/Blog-Starter-Project/Usecases"
+	"github.com/Amaankaa/Blog-Starter-Project/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type auditUsecaseTestSuite struct {
+	suite.Suite
+	ctx      context.Context
+	mockRepo *mocks.IAuditRepository
+	usecase  *usecases.AuditUsecase
+}
+
+func TestAuditUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(auditUsecaseTestSuite))
+}
+
+func (s *auditUsecaseTestSuite) SetupTest() {
+	s.ctx = auditpkg.WithClientIP(context.Background(), "203.0.113.7")
+	s.mockRepo = new(mocks.IAuditRepository)
+	s.usecase = usecases.NewAuditUsecase(s.mockRepo)
+}
+
+func (s *auditUsecaseTestSuite) TearDownTest() {
+	s.mockRepo.AssertExpectations(s.T())
+}
+
+var roleGrant = auditpkg.Event{
+	ActorID:    "admin",
+	Action:     auditpkg.ActionRoleGrant,
+	TargetType: auditpkg.TargetUser,
+	TargetID:   "target",
+	Before:     map[string]string{"role": "user"},
+	After:      map[string]string{"role": "moderator"},
+}
+
+// chain builds n valid linked entries
+func chain(n int) []auditpkg.Entry {
+	entries := make([]auditpkg.Entry, n)
+	prev := ""
+	for i := range entries {
+		e := auditpkg.Entry{Seq: int64(i + 1), ActorID: "admin", Action: auditpkg.ActionRoleGrant, PrevHash: prev}
+		e.Hash = e.ComputeHash()
+		entries[i] = e
+		prev = e.Hash
+	}
+	return entries
+}
+
+func (s *auditUsecaseTestSuite) expectEntries(entries []auditpkg.Entry) {
+	s.mockRepo.On("EachEntry", s.ctx, auditpkg.Filter{}, mock.Anything).
+		Run(func(args mock.Arguments) {
+			fn := args.Get(2).(func(auditpkg.Entry) error)
+			for _, e := range entries {
+				if fn(e) != nil {
+					return
+				}
+			}
+		}).Return(nil)
+}
+
+func (s *auditUsecaseTestSuite) TestRecord_StartsChain() {
+	s.mockRepo.On("LastEntry", s.ctx).Return(auditpkg.Entry{}, false, nil)
+	s.mockRepo.On("InsertEntry", s.ctx, mock.MatchedBy(func(e auditpkg.Entry) bool {
+		return e.Seq == 1 && e.PrevHash == "" && e.IP == "203.0.113.7" &&
+			string(e.After) == `{"role":"moderator"}` && e.Hash == e.ComputeHash()
+	})).Return(nil)
+
+	s.NoError(s.usecase.Record(s.ctx, roleGrant))
+}
+
+func (s *auditUsecaseTestSuite) TestRecord_RetriesWhenSeqTaken() {
+	head := chain(2)
+	s.mockRepo.On("LastEntry", s.ctx).Return(head[0], true, nil).Once()
+	s.mockRepo.On("InsertEntry", s.ctx, mock.MatchedBy(func(e auditpkg.Entry) bool { return e.Seq == 2 })).
+		Return(auditpkg.ErrSeqTaken).Once()
+	s.mockRepo.On("LastEntry", s.ctx).Return(head[1], true, nil).Once()
+	s.mockRepo.On("InsertEntry", s.ctx, mock.MatchedBy(func(e auditpkg.Entry) bool {
+		return e.Seq == 3 && e.PrevHash == head[1].Hash
+	})).Return(nil).Once()
+
+	s.NoError(s.usecase.Record(s.ctx, roleGrant))
+}
+
+func (s *auditUsecaseTestSuite) TestVerifyChain_Valid() {
+	s.expectEntries(chain(3))
+
+	res, err := s.usecase.VerifyChain(s.ctx)
+
+	s.NoError(err)
+	s.True(res.Valid)
+	s.Equal(int64(3), res.Checked)
+}
+
+func (s *auditUsecaseTestSuite) TestVerifyChain_DetectsEditedEntry() {
+	entries := chain(3)
+	entries[1].After = json.RawMessage(`{"role":"admin"}`)
+	s.expectEntries(entries)
+
+	res, err := s.usecase.VerifyChain(s.ctx)
+
+	s.NoError(err)
+	s.False(res.Valid)
+	s.Equal(int64(2), res.BrokenAtSeq)
+	s.Equal(int64(1), res.Checked)
+}
+
+func (s *auditUsecaseTestSuite) TestVerifyChain_DetectsRemovedEntry() {
+	entries := chain(3)
+	s.expectEntries([]auditpkg.Entry{entries[0], entries[2]})
+
+	res, err := s.usecase.VerifyChain(s.ctx)
+
+	s.NoError(err)
+	s.False(res.Valid)
+	s.Equal(int64(3), res.BrokenAtSeq)
+}
+
+func (s *auditUsecaseTestSuite) TestListEntries_NormalizesPaging() {
+	s.mockRepo.On("FindEntries", s.ctx, auditpkg.Filter{Action: auditpkg.ActionUserSuspend, Page: 1, PageSize: 20}).
+		Return([]auditpkg.Entry{}, int64(41), nil)
+
+	res, err := s.usecase.ListEntries(s.ctx, auditpkg.Filter{Action: auditpkg.ActionUserSuspend})
+
+	s.NoError(err)
+	s.Equal(3, res.TotalPages)
+}
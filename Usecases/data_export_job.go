@@ -0,0 +1,486 @@
+package usecases
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	commentpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/comment"
+	mentorshippkg "github.com/Amaankaa/Blog-Starter-Project/Domain/mentorship"
+	msgpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/messaging"
+	postpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/post"
+	resourcepkg "github.com/Amaankaa/Blog-Starter-Project/Domain/resource"
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DefaultDataExportRetention is how long a finished archive can be downloaded
+const DefaultDataExportRetention = 7 * 24 * time.Hour
+
+// dataExportLease is how long a worker may build an export before another
+// worker assumes it crashed and starts over
+const dataExportLease = 15 * time.Minute
+
+// dataExportPageSize is the batch size for sources that are only paginated
+const dataExportPageSize = 100
+
+// DataExportJob builds data export archives and removes them once expired.
+//
+// The archive is meant to be safe to share: other people appear only by
+// display name, and as "Anonymous" when they post anonymously or have an
+// anonymous profile. Nobody else's IDs, emails or names are included.
+type DataExportJob struct {
+	exports        userpkg.IDataExportRepository
+	store          userpkg.IExportStore
+	userRepo       userpkg.IUserRepository
+	tokenRepo      userpkg.ITokenRepository
+	postRepo       postpkg.PostRepository
+	commentRepo    commentpkg.ICommentRepository
+	resourceRepo   resourcepkg.ResourceRepository
+	mentorshipRepo mentorshippkg.IMentorshipRepository
+	messagingRepo  msgpkg.IMessagingRepository
+	emailSender    services.IEmailSender
+	retention      time.Duration
+}
+
+func NewDataExportJob(
+	exports userpkg.IDataExportRepository,
+	store userpkg.IExportStore,
+	userRepo userpkg.IUserRepository,
+	tokenRepo userpkg.ITokenRepository,
+	postRepo postpkg.PostRepository,
+	commentRepo commentpkg.ICommentRepository,
+	resourceRepo resourcepkg.ResourceRepository,
+	mentorshipRepo mentorshippkg.IMentorshipRepository,
+	messagingRepo msgpkg.IMessagingRepository,
+	emailSender services.IEmailSender,
+	retention time.Duration,
+) *DataExportJob {
+	return &DataExportJob{
+		exports:        exports,
+		store:          store,
+		userRepo:       userRepo,
+		tokenRepo:      tokenRepo,
+		postRepo:       postRepo,
+		commentRepo:    commentRepo,
+		resourceRepo:   resourceRepo,
+		mentorshipRepo: mentorshipRepo,
+		messagingRepo:  messagingRepo,
+		emailSender:    emailSender,
+		retention:      retention,
+	}
+}
+
+// Start runs the job every interval until ctx is cancelled
+func (j *DataExportJob) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := j.RunOnce(ctx); err != nil {
+					log.Printf("data export: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// RunOnce removes expired archives, then builds every pending export and
+// returns how many were built. A failed export is marked failed so the user
+// can request another.
+func (j *DataExportJob) RunOnce(ctx context.Context) (int, error) {
+	j.removeExpired(ctx)
+
+	built := 0
+	for {
+		export, ok, err := j.exports.ClaimPendingExport(ctx, time.Now(), dataExportLease)
+		if err != nil {
+			return built, fmt.Errorf("failed to claim export: %w", err)
+		}
+		if !ok {
+			return built, nil
+		}
+
+		user, err := j.userRepo.FindByID(ctx, export.UserID.Hex())
+		if err != nil {
+			j.fail(ctx, export, fmt.Errorf("user: %w", err))
+			continue
+		}
+		archive, err := j.build(ctx, user)
+		if err != nil {
+			j.fail(ctx, export, err)
+			continue
+		}
+
+		name := export.ID.Hex() + ".zip"
+		if err := j.store.Save(ctx, name, archive); err != nil {
+			j.fail(ctx, export, fmt.Errorf("save: %w", err))
+			continue
+		}
+		now := time.Now()
+		if err := j.exports.MarkExportReady(ctx, export.ID.Hex(), name, int64(len(archive)), now, now.Add(j.retention)); err != nil {
+			log.Printf("data export: failed to mark export=%s ready: %v", export.ID.Hex(), err)
+			if errors.Is(err, userpkg.ErrExportNotFound) {
+				// The account was erased while the archive was being built
+				_ = j.store.Delete(ctx, name)
+			}
+			continue
+		}
+		built++
+
+		_ = j.emailSender.SendEmail(user.Email, user.Locale, services.TemplateDataExportReady, services.EmailData{
+			"Name":           user.Username,
+			"AvailableUntil": now.Add(j.retention),
+		})
+	}
+}
+
+func (j *DataExportJob) fail(ctx context.Context, export userpkg.DataExport, cause error) {
+	log.Printf("data export: failed to build export=%s user=%s: %v", export.ID.Hex(), export.UserID.Hex(), cause)
+	if err := j.exports.MarkExportFailed(ctx, export.ID.Hex(), "the export could not be built, please request another"); err != nil {
+		log.Printf("data export: failed to mark export=%s failed: %v", export.ID.Hex(), err)
+	}
+}
+
+func (j *DataExportJob) removeExpired(ctx context.Context) {
+	expired, err := j.exports.FindExpiredExports(ctx, time.Now())
+	if err != nil {
+		log.Printf("data export: failed to find expired exports: %v", err)
+		return
+	}
+	for _, export := range expired {
+		if export.File != "" {
+			if err := j.store.Delete(ctx, export.File); err != nil {
+				log.Printf("data export: failed to delete archive export=%s: %v", export.ID.Hex(), err)
+				continue
+			}
+		}
+		if err := j.exports.DeleteExport(ctx, export.ID.Hex()); err != nil {
+			log.Printf("data export: failed to delete export=%s: %v", export.ID.Hex(), err)
+		}
+	}
+}
+
+// exportPerson is how anyone other than the account owner appears in an archive
+type exportPerson struct {
+	DisplayName string `json:"displayName"`
+}
+
+var anonymousPerson = exportPerson{DisplayName: "Anonymous"}
+
+// people resolves other users to what the archive may say about them
+type people struct {
+	ctx      context.Context
+	userRepo userpkg.IUserRepository
+	selfID   primitive.ObjectID
+	seen     map[primitive.ObjectID]exportPerson
+}
+
+func (p *people) get(id primitive.ObjectID, anonymousContent bool) exportPerson {
+	if id == p.selfID {
+		return exportPerson{DisplayName: "You"}
+	}
+	if anonymousContent {
+		return anonymousPerson
+	}
+	if person, ok := p.seen[id]; ok {
+		return person
+	}
+	person := anonymousPerson
+	if user, err := p.userRepo.FindByID(p.ctx, id.Hex()); err == nil && !user.IsAnonymous && user.DisplayName != "" {
+		person = exportPerson{DisplayName: user.DisplayName}
+	}
+	p.seen[id] = person
+	return person
+}
+
+type exportItemRef struct {
+	ID     primitive.ObjectID `json:"id"`
+	Title  string             `json:"title"`
+	Author exportPerson       `json:"author"`
+}
+
+type exportMentorshipRequest struct {
+	ID        primitive.ObjectID                    `json:"id"`
+	Role      string                                `json:"role"` // "mentee" or "mentor"
+	With      exportPerson                          `json:"with"`
+	Status    mentorshippkg.MentorshipRequestStatus `json:"status"`
+	Message   string                                `json:"message,omitempty"`
+	Topics    []string                              `json:"topics"`
+	CreatedAt time.Time                             `json:"createdAt"`
+}
+
+type exportMentorshipConnection struct {
+	ID        primitive.ObjectID                       `json:"id"`
+	Role      string                                   `json:"role"`
+	With      exportPerson                             `json:"with"`
+	Status    mentorshippkg.MentorshipConnectionStatus `json:"status"`
+	Topics    []string                                 `json:"topics"`
+	StartedAt time.Time                                `json:"startedAt"`
+	EndedAt   *time.Time                               `json:"endedAt,omitempty"`
+	EndReason string                                   `json:"endReason,omitempty"`
+	// Only the feedback the user gave
+	Rating   *int   `json:"rating,omitempty"`
+	Feedback string `json:"feedback,omitempty"`
+}
+
+type exportMessage struct {
+	From      exportPerson `json:"from"`
+	Content   string       `json:"content"`
+	CreatedAt time.Time    `json:"createdAt"`
+}
+
+type exportConversation struct {
+	ID           primitive.ObjectID `json:"id"`
+	Participants []exportPerson     `json:"participants"`
+	Messages     []exportMessage    `json:"messages"`
+}
+
+// build collects everything the user has on ShareSpace into a zip of JSON files
+func (j *DataExportJob) build(ctx context.Context, user userpkg.User) ([]byte, error) {
+	p := &people{ctx: ctx, userRepo: j.userRepo, selfID: user.ID, seen: map[primitive.ObjectID]exportPerson{}}
+	files := map[string]interface{}{}
+
+	profile := user
+	profile.Password = ""
+	profile.PromotedBy = primitive.NilObjectID
+	files["profile.json"] = profile
+
+	tokens, err := j.tokenRepo.FindActiveSessionsByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("sessions: %w", err)
+	}
+	sessions := make([]userpkg.Session, 0, len(tokens))
+	for _, t := range tokens {
+		sessions = append(sessions, userpkg.Session{
+			ID:         t.FamilyID,
+			DeviceName: t.DeviceName,
+			UserAgent:  t.UserAgent,
+			IP:         t.IP,
+			CreatedAt:  t.FamilyID.Timestamp(),
+			LastUsedAt: t.LastUsedAt,
+			ExpiresAt:  t.ExpiresAt,
+		})
+	}
+	files["sessions.json"] = sessions
+
+	posts, err := j.postRepo.GetAllPostsByAuthor(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("posts: %w", err)
+	}
+	for i := range posts {
+		posts[i].LikedBy = nil
+	}
+	files["posts.json"] = nonNil(posts)
+
+	comments, err := j.commentRepo.GetCommentsByAuthor(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("comments: %w", err)
+	}
+	files["comments.json"] = nonNil(comments)
+
+	resources, err := j.resourceRepo.GetAllResourcesByCreator(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("resources: %w", err)
+	}
+	for i := range resources {
+		resources[i].LikedBy = nil
+		resources[i].BookmarkedBy = nil
+		resources[i].VerifiedBy = primitive.NilObjectID
+	}
+	files["resources.json"] = nonNil(resources)
+
+	likedPosts, err := j.postRepo.GetPostsLikedByUser(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("liked posts: %w", err)
+	}
+	likes := struct {
+		Posts     []exportItemRef `json:"posts"`
+		Resources []exportItemRef `json:"resources"`
+	}{Posts: []exportItemRef{}}
+	for _, post := range likedPosts {
+		likes.Posts = append(likes.Posts, exportItemRef{ID: post.ID, Title: post.Title, Author: p.get(post.AuthorID, post.IsAnonymous)})
+	}
+	if likes.Resources, err = j.resourceRefs(ctx, p, user.ID, j.resourceRepo.GetUserLikedResources); err != nil {
+		return nil, fmt.Errorf("liked resources: %w", err)
+	}
+	files["likes.json"] = likes
+
+	bookmarks, err := j.resourceRefs(ctx, p, user.ID, j.resourceRepo.GetUserBookmarkedResources)
+	if err != nil {
+		return nil, fmt.Errorf("bookmarks: %w", err)
+	}
+	files["bookmarks.json"] = bookmarks
+
+	mentorship, err := j.mentorship(ctx, p, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("mentorship: %w", err)
+	}
+	files["mentorship.json"] = mentorship
+
+	conversations, err := j.conversations(ctx, p, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("conversations: %w", err)
+	}
+	files["conversations.json"] = conversations
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range []string{
+		"profile.json", "sessions.json", "posts.json", "comments.json", "resources.json",
+		"likes.json", "bookmarks.json", "mentorship.json", "conversations.json",
+	} {
+		w, err := zw.Create(name)
+		if err != nil {
+			return nil, err
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(files[name]); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// nonNil makes empty sections encode as [] rather than null
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
+type resourcePage func(ctx context.Context, userID primitive.ObjectID, pagination resourcepkg.ResourcePagination) ([]resourcepkg.Resource, int64, error)
+
+func (j *DataExportJob) resourceRefs(ctx context.Context, p *people, userID primitive.ObjectID, list resourcePage) ([]exportItemRef, error) {
+	refs := []exportItemRef{}
+	for page := 1; ; page++ {
+		items, _, err := list(ctx, userID, resourcepkg.ResourcePagination{Page: page, PageSize: dataExportPageSize})
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range items {
+			refs = append(refs, exportItemRef{ID: r.ID, Title: r.Title, Author: p.get(r.CreatorID, false)})
+		}
+		if len(items) < dataExportPageSize {
+			return refs, nil
+		}
+	}
+}
+
+func (j *DataExportJob) mentorship(ctx context.Context, p *people, userID primitive.ObjectID) (interface{}, error) {
+	requests := []exportMentorshipRequest{}
+	for _, role := range []string{"mentee", "mentor"} {
+		filters := mentorshippkg.RequestFilters{Limit: dataExportPageSize}
+		if role == "mentee" {
+			filters.MenteeID = &userID
+		} else {
+			filters.MentorID = &userID
+		}
+		for {
+			found, err := j.mentorshipRepo.SearchRequests(ctx, filters)
+			if err != nil {
+				return nil, err
+			}
+			for _, r := range found {
+				other := r.MentorID
+				if role == "mentor" {
+					other = r.MenteeID
+				}
+				requests = append(requests, exportMentorshipRequest{
+					ID: r.ID, Role: role, With: p.get(other, false), Status: r.Status,
+					Message: r.Message, Topics: r.Topics, CreatedAt: r.CreatedAt,
+				})
+			}
+			if len(found) < dataExportPageSize {
+				break
+			}
+			filters.Offset += dataExportPageSize
+		}
+	}
+
+	connections := []exportMentorshipConnection{}
+	for _, role := range []string{"mentee", "mentor"} {
+		filters := mentorshippkg.ConnectionFilters{Limit: dataExportPageSize}
+		if role == "mentee" {
+			filters.MenteeID = &userID
+		} else {
+			filters.MentorID = &userID
+		}
+		for {
+			found, err := j.mentorshipRepo.SearchConnections(ctx, filters)
+			if err != nil {
+				return nil, err
+			}
+			for _, c := range found {
+				conn := exportMentorshipConnection{
+					ID: c.ID, Role: role, Status: c.Status, Topics: c.Topics,
+					StartedAt: c.StartedAt, EndedAt: c.EndedAt, EndReason: c.EndReason,
+				}
+				if role == "mentee" {
+					conn.With = p.get(c.MentorID, false)
+					conn.Rating, conn.Feedback = c.MenteeRating, c.MenteeFeedback
+				} else {
+					conn.With = p.get(c.MenteeID, false)
+					conn.Rating, conn.Feedback = c.MentorRating, c.MentorFeedback
+				}
+				connections = append(connections, conn)
+			}
+			if len(found) < dataExportPageSize {
+				break
+			}
+			filters.Offset += dataExportPageSize
+		}
+	}
+
+	return struct {
+		Requests    []exportMentorshipRequest    `json:"requests"`
+		Connections []exportMentorshipConnection `json:"connections"`
+	}{requests, connections}, nil
+}
+
+func (j *DataExportJob) conversations(ctx context.Context, p *people, userID primitive.ObjectID) ([]exportConversation, error) {
+	out := []exportConversation{}
+	for offset := 0; ; offset += dataExportPageSize {
+		convs, err := j.messagingRepo.GetUserConversations(ctx, userID, dataExportPageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+		for _, conv := range convs {
+			ec := exportConversation{ID: conv.ID, Participants: []exportPerson{}, Messages: []exportMessage{}}
+			for _, id := range conv.ParticipantIDs {
+				ec.Participants = append(ec.Participants, p.get(id, false))
+			}
+			for msgOffset := 0; ; msgOffset += dataExportPageSize {
+				msgs, err := j.messagingRepo.GetMessages(ctx, conv.ID, dataExportPageSize, msgOffset)
+				if err != nil {
+					return nil, err
+				}
+				for _, m := range msgs {
+					ec.Messages = append(ec.Messages, exportMessage{From: p.get(m.SenderID, false), Content: m.Content, CreatedAt: m.CreatedAt})
+				}
+				if len(msgs) < dataExportPageSize {
+					break
+				}
+			}
+			out = append(out, ec)
+		}
+		if len(convs) < dataExportPageSize {
+			return out, nil
+		}
+	}
+}
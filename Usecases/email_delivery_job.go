@@ -0,0 +1,167 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	emailpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/email"
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+)
+
+// EmailDeliveryPolicy controls how failed deliveries are retried
+type EmailDeliveryPolicy struct {
+	MaxAttempts int           // attempts before a message is dead-lettered
+	BaseDelay   time.Duration // wait after the first failure, doubled for each further one
+	MaxDelay    time.Duration
+	Timeout     time.Duration // per delivery attempt
+	// Claimed messages are handed out again after this long, in case their
+	// worker crashed. Must be longer than Timeout.
+	Lease     time.Duration
+	Retention time.Duration // delivered messages are removed after this long
+}
+
+func DefaultEmailDeliveryPolicy() EmailDeliveryPolicy {
+	return EmailDeliveryPolicy{
+		MaxAttempts: 8,
+		BaseDelay:   30 * time.Second,
+		MaxDelay:    2 * time.Hour,
+		Timeout:     30 * time.Second,
+		Lease:       5 * time.Minute,
+		Retention:   7 * 24 * time.Hour,
+	}
+}
+
+// backoff is how long to wait after the given number of failed attempts
+func (p EmailDeliveryPolicy) backoff(attempts int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempts && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+// EmailDeliveryJob delivers queued emails through transport. Several workers,
+// in one process or many, can share the outbox: each message is claimed by
+// exactly one of them at a time.
+type EmailDeliveryJob struct {
+	repo      emailpkg.IOutboxRepository
+	transport services.IEmailTransport
+	policy    EmailDeliveryPolicy
+}
+
+func NewEmailDeliveryJob(repo emailpkg.IOutboxRepository, transport services.IEmailTransport, policy EmailDeliveryPolicy) *EmailDeliveryJob {
+	return &EmailDeliveryJob{repo: repo, transport: transport, policy: policy}
+}
+
+// Start runs workers delivery loops, each draining the outbox every interval,
+// and removes old delivered messages hourly until ctx is cancelled
+func (j *EmailDeliveryJob) Start(ctx context.Context, workers int, interval time.Duration) {
+	for i := 0; i < workers; i++ {
+		go func() {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				if _, err := j.RunOnce(ctx); err != nil {
+					log.Printf("email delivery: %v", err)
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+		}()
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				j.removeSent(ctx)
+			}
+		}
+	}()
+}
+
+// RunOnce delivers every due message and returns how many were sent
+func (j *EmailDeliveryJob) RunOnce(ctx context.Context) (int, error) {
+	sent := 0
+	for {
+		if ctx.Err() != nil {
+			return sent, nil
+		}
+		message, ok, err := j.repo.ClaimDue(ctx, time.Now(), j.policy.Lease)
+		if err != nil {
+			return sent, fmt.Errorf("failed to claim email: %w", err)
+		}
+		if !ok {
+			return sent, nil
+		}
+		if j.deliver(ctx, message) {
+			sent++
+		}
+	}
+}
+
+// deliver makes one attempt and records the outcome: sent, retried after a
+// backoff, or dead-lettered once attempts run out or the provider rejects it
+func (j *EmailDeliveryJob) deliver(ctx context.Context, message emailpkg.Message) bool {
+	id := message.ID.Hex()
+	attempts := message.Attempts + 1
+	transport := j.transport.Name()
+
+	// Reclaimed after its workers' leases ran out as often as it may be tried
+	if message.Attempts >= j.policy.MaxAttempts {
+		log.Printf("email delivery: giving up on message=%s template=%s after %d unfinished attempts", id, message.Template, message.Attempts)
+		if err := j.repo.MarkDead(ctx, id, message.Attempts, transport, "delivery did not finish within the lease"); err != nil {
+			log.Printf("email delivery: failed to dead-letter message=%s: %v", id, err)
+		}
+		return false
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, j.policy.Timeout)
+	err := j.transport.Deliver(sendCtx, message.To, services.RenderedEmail{
+		Locale:  message.Locale,
+		Subject: message.Subject,
+		HTML:    message.HTML,
+		Text:    message.Text,
+	})
+	cancel()
+
+	if err == nil {
+		if err := j.repo.MarkSent(ctx, id, attempts, transport, time.Now()); err != nil {
+			log.Printf("email delivery: failed to mark message=%s sent: %v", id, err)
+		}
+		return true
+	}
+
+	if errors.Is(err, services.ErrEmailRejected) || attempts >= j.policy.MaxAttempts {
+		log.Printf("email delivery: giving up on message=%s template=%s after %d attempts: %v", id, message.Template, attempts, err)
+		if err := j.repo.MarkDead(ctx, id, attempts, transport, err.Error()); err != nil {
+			log.Printf("email delivery: failed to dead-letter message=%s: %v", id, err)
+		}
+		return false
+	}
+
+	next := time.Now().Add(j.policy.backoff(attempts))
+	if err := j.repo.MarkRetry(ctx, id, attempts, transport, err.Error(), next); err != nil {
+		log.Printf("email delivery: failed to reschedule message=%s: %v", id, err)
+	}
+	return false
+}
+
+func (j *EmailDeliveryJob) removeSent(ctx context.Context) {
+	if _, err := j.repo.DeleteSentBefore(ctx, time.Now().Add(-j.policy.Retention)); err != nil {
+		log.Printf("email delivery: failed to remove delivered emails: %v", err)
+	}
+}
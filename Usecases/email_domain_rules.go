@@ -0,0 +1,106 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	auditpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/audit"
+	emailpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/email"
+)
+
+// EmailDomainRules manages the domains signup email verification always
+// accepts or always refuses
+type EmailDomainRules struct {
+	repo  emailpkg.IDomainRuleRepository
+	audit auditpkg.IAuditLogger
+}
+
+func NewEmailDomainRules(repo emailpkg.IDomainRuleRepository) *EmailDomainRules {
+	return &EmailDomainRules{repo: repo}
+}
+
+// WithAuditLogger records rule changes in the audit log
+func (d *EmailDomainRules) WithAuditLogger(logger auditpkg.IAuditLogger) *EmailDomainRules {
+	d.audit = logger
+	return d
+}
+
+func (d *EmailDomainRules) ListRules(ctx context.Context, action emailpkg.DomainRuleAction) ([]emailpkg.DomainRule, error) {
+	if action != "" && action != emailpkg.DomainAllow && action != emailpkg.DomainDeny {
+		return nil, emailpkg.ErrInvalidDomainAction
+	}
+	return d.repo.ListRules(ctx, action)
+}
+
+// SetRule creates or replaces the rule for domain. A leading "@" or "*." is
+// dropped, so "@example.com" and "*.example.com" both mean example.com and
+// its subdomains.
+func (d *EmailDomainRules) SetRule(ctx context.Context, domain string, action emailpkg.DomainRuleAction, note, actorID string) (emailpkg.DomainRule, error) {
+	domain, ok := normalizeRuleDomain(domain)
+	if !ok {
+		return emailpkg.DomainRule{}, emailpkg.ErrInvalidDomain
+	}
+	if action != emailpkg.DomainAllow && action != emailpkg.DomainDeny {
+		return emailpkg.DomainRule{}, emailpkg.ErrInvalidDomainAction
+	}
+
+	before, err := d.repo.GetRule(ctx, domain)
+	if err != nil && !errors.Is(err, emailpkg.ErrDomainRuleNotFound) {
+		return emailpkg.DomainRule{}, err
+	}
+	rule := emailpkg.DomainRule{
+		Domain:    domain,
+		Action:    action,
+		Note:      strings.TrimSpace(note),
+		CreatedBy: actorID,
+		CreatedAt: time.Now(),
+	}
+	if err := d.repo.SaveRule(ctx, rule); err != nil {
+		return emailpkg.DomainRule{}, err
+	}
+
+	var beforeState interface{}
+	if before.Domain != "" {
+		beforeState = map[string]interface{}{"action": before.Action, "note": before.Note}
+	}
+	recordAudit(ctx, d.audit, auditpkg.Event{
+		ActorID:    actorID,
+		Action:     auditpkg.ActionEmailDomainSet,
+		TargetType: auditpkg.TargetDomain,
+		TargetID:   domain,
+		Before:     beforeState,
+		After:      map[string]interface{}{"action": rule.Action, "note": rule.Note},
+	})
+	return rule, nil
+}
+
+func (d *EmailDomainRules) DeleteRule(ctx context.Context, domain, actorID string) error {
+	domain, ok := normalizeRuleDomain(domain)
+	if !ok {
+		return emailpkg.ErrInvalidDomain
+	}
+	before, err := d.repo.GetRule(ctx, domain)
+	if err != nil {
+		return err
+	}
+	if err := d.repo.DeleteRule(ctx, domain); err != nil {
+		return err
+	}
+	recordAudit(ctx, d.audit, auditpkg.Event{
+		ActorID:    actorID,
+		Action:     auditpkg.ActionEmailDomainDelete,
+		TargetType: auditpkg.TargetDomain,
+		TargetID:   domain,
+		Before:     map[string]interface{}{"action": before.Action, "note": before.Note},
+	})
+	return nil
+}
+
+func normalizeRuleDomain(domain string) (string, bool) {
+	domain = strings.TrimSpace(domain)
+	domain = strings.TrimPrefix(domain, "@")
+	domain = strings.TrimPrefix(domain, "*.")
+	return emailpkg.NormalizeDomain(domain)
+}
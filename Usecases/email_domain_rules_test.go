@@ -0,0 +1,74 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+
+	auditpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/audit"
+	emailpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/email"
+	usecases "github.com/Amaankaa/Blog-Starter-Project/Usecases"
+	"github.com/Amaankaa/Blog-Starter-Project/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type emailDomainRulesTestSuite struct {
+	suite.Suite
+	ctx       context.Context
+	mockRepo  *mocks.IDomainRuleRepository
+	mockAudit *mocks.IAuditLogger
+	rules     *usecases.EmailDomainRules
+}
+
+func TestEmailDomainRulesTestSuite(t *testing.T) {
+	suite.Run(t, new(emailDomainRulesTestSuite))
+}
+
+func (s *emailDomainRulesTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockRepo = new(mocks.IDomainRuleRepository)
+	s.mockAudit = new(mocks.IAuditLogger)
+	s.rules = usecases.NewEmailDomainRules(s.mockRepo).WithAuditLogger(s.mockAudit)
+}
+
+func (s *emailDomainRulesTestSuite) TearDownTest() {
+	s.mockRepo.AssertExpectations(s.T())
+	s.mockAudit.AssertExpectations(s.T())
+}
+
+func (s *emailDomainRulesTestSuite) TestSetRule_NormalizesAndAudits() {
+	s.mockRepo.On("GetRule", s.ctx, "example.edu").
+		Return(emailpkg.DomainRule{Domain: "example.edu", Action: emailpkg.DomainAllow}, nil)
+	s.mockRepo.On("SaveRule", s.ctx, mock.MatchedBy(func(r emailpkg.DomainRule) bool {
+		return r.Domain == "example.edu" && r.Action == emailpkg.DomainDeny && r.Note == "spam wave" && r.CreatedBy == "admin"
+	})).Return(nil)
+	s.mockAudit.On("Record", s.ctx, mock.MatchedBy(func(e auditpkg.Event) bool {
+		return e.Action == auditpkg.ActionEmailDomainSet && e.TargetID == "example.edu" && e.Before != nil
+	})).Return(nil)
+
+	rule, err := s.rules.SetRule(s.ctx, " *.Example.EDU ", emailpkg.DomainDeny, " spam wave ", "admin")
+	s.NoError(err)
+	s.Equal("example.edu", rule.Domain)
+}
+
+func (s *emailDomainRulesTestSuite) TestSetRule_Invalid() {
+	_, err := s.rules.SetRule(s.ctx, "not a domain", emailpkg.DomainDeny, "", "admin")
+	s.ErrorIs(err, emailpkg.ErrInvalidDomain)
+
+	_, err = s.rules.SetRule(s.ctx, "example.com", "block", "", "admin")
+	s.ErrorIs(err, emailpkg.ErrInvalidDomainAction)
+	s.mockRepo.AssertNotCalled(s.T(), "SaveRule", mock.Anything, mock.Anything)
+}
+
+func (s *emailDomainRulesTestSuite) TestDeleteRule() {
+	s.mockRepo.On("GetRule", s.ctx, "mailinator.com").Return(emailpkg.DomainRule{}, emailpkg.ErrDomainRuleNotFound).Once()
+	s.ErrorIs(s.rules.DeleteRule(s.ctx, "@mailinator.com", "admin"), emailpkg.ErrDomainRuleNotFound)
+
+	s.mockRepo.On("GetRule", s.ctx, "mailinator.com").
+		Return(emailpkg.DomainRule{Domain: "mailinator.com", Action: emailpkg.DomainAllow}, nil)
+	s.mockRepo.On("DeleteRule", s.ctx, "mailinator.com").Return(nil)
+	s.mockAudit.On("Record", s.ctx, mock.MatchedBy(func(e auditpkg.Event) bool {
+		return e.Action == auditpkg.ActionEmailDomainDelete && e.TargetType == auditpkg.TargetDomain
+	})).Return(nil)
+	s.NoError(s.rules.DeleteRule(s.ctx, "mailinator.com", "admin"))
+}
@@ -0,0 +1,134 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	auditpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/audit"
+	emailpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/email"
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+)
+
+// enqueueTimeout bounds the outbox insert; SendEmail has no context of its own
+const enqueueTimeout = 5 * time.Second
+
+// EmailOutbox is the IEmailSender usecases send through. It renders the
+// email straight away, so a template error still fails the caller, and
+// queues it for EmailDeliveryJob instead of calling the provider inside the
+// request.
+type EmailOutbox struct {
+	repo      emailpkg.IOutboxRepository
+	templates services.IEmailRenderer
+	audit     auditpkg.IAuditLogger
+}
+
+func NewEmailOutbox(repo emailpkg.IOutboxRepository, templates services.IEmailRenderer) *EmailOutbox {
+	return &EmailOutbox{repo: repo, templates: templates}
+}
+
+// WithAuditLogger records admin retries in the audit log
+func (o *EmailOutbox) WithAuditLogger(logger auditpkg.IAuditLogger) *EmailOutbox {
+	o.audit = logger
+	return o
+}
+
+// SendEmail renders template and queues it; it only fails if the email
+// cannot be rendered or stored
+func (o *EmailOutbox) SendEmail(to, locale string, template services.EmailTemplate, data services.EmailData) error {
+	email, err := o.templates.Render(template, locale, data)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), enqueueTimeout)
+	defer cancel()
+
+	now := time.Now()
+	_, err = o.repo.Enqueue(ctx, emailpkg.Message{
+		To:            to,
+		Locale:        email.Locale,
+		Template:      template,
+		Subject:       email.Subject,
+		HTML:          email.HTML,
+		Text:          email.Text,
+		Status:        emailpkg.StatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	})
+	if err != nil {
+		log.Printf("email: failed to queue %s: %v", template, err)
+		return errors.New("failed to queue email")
+	}
+	return nil
+}
+
+func (o *EmailOutbox) ListMessages(ctx context.Context, filter emailpkg.Filter) (emailpkg.MessageListResponse, error) {
+	if filter.Status != "" && !emailpkg.IsValidStatus(filter.Status) {
+		return emailpkg.MessageListResponse{}, errors.New("invalid status")
+	}
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 {
+		filter.PageSize = defaultUserPageSize
+	}
+	if filter.PageSize > maxUserPageSize {
+		filter.PageSize = maxUserPageSize
+	}
+
+	messages, total, err := o.repo.FindMessages(ctx, filter)
+	if err != nil {
+		return emailpkg.MessageListResponse{}, err
+	}
+	return emailpkg.MessageListResponse{
+		Messages:   messages,
+		Total:      total,
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		TotalPages: int((total + int64(filter.PageSize) - 1) / int64(filter.PageSize)),
+	}, nil
+}
+
+func (o *EmailOutbox) GetMessage(ctx context.Context, messageID string) (emailpkg.Message, error) {
+	return o.repo.GetMessage(ctx, messageID)
+}
+
+// RetryMessage puts a dead-lettered message back in the queue with its
+// attempts reset
+func (o *EmailOutbox) RetryMessage(ctx context.Context, messageID, actorID string) (emailpkg.Message, error) {
+	message, err := o.repo.GetMessage(ctx, messageID)
+	if err != nil {
+		return emailpkg.Message{}, err
+	}
+	if message.Status != emailpkg.StatusDead {
+		return emailpkg.Message{}, emailpkg.ErrMessageNotDead
+	}
+
+	now := time.Now()
+	if err := o.repo.Requeue(ctx, messageID, now); err != nil {
+		if errors.Is(err, emailpkg.ErrMessageNotFound) {
+			// Someone else retried it in the meantime
+			return emailpkg.Message{}, emailpkg.ErrMessageNotDead
+		}
+		return emailpkg.Message{}, err
+	}
+	recordAudit(ctx, o.audit, auditpkg.Event{
+		ActorID:    actorID,
+		Action:     auditpkg.ActionEmailRetry,
+		TargetType: auditpkg.TargetEmail,
+		TargetID:   messageID,
+		Before:     map[string]interface{}{"status": message.Status, "attempts": message.Attempts},
+		After:      map[string]interface{}{"status": emailpkg.StatusPending, "attempts": 0},
+	})
+
+	message.Status = emailpkg.StatusPending
+	message.Attempts = 0
+	message.NextAttemptAt = now
+	return message, nil
+}
+
+func (o *EmailOutbox) Stats(ctx context.Context) (emailpkg.OutboxStats, error) {
+	return o.repo.Stats(ctx)
+}
@@ -0,0 +1,171 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	auditpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/audit"
+	emailpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/email"
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+	usecases "github.com/Amaankaa/Blog-Starter-Project/Usecases"
+	"github.com/Amaankaa/Blog-Starter-Project/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type emailOutboxTestSuite struct {
+	suite.Suite
+	ctx           context.Context
+	mockRepo      *mocks.IOutboxRepository
+	mockRenderer  *mocks.IEmailRenderer
+	mockTransport *mocks.IEmailTransport
+	mockAudit     *mocks.IAuditLogger
+	outbox        *usecases.EmailOutbox
+	job           *usecases.EmailDeliveryJob
+}
+
+func TestEmailOutboxTestSuite(t *testing.T) {
+	suite.Run(t, new(emailOutboxTestSuite))
+}
+
+func (s *emailOutboxTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockRepo = new(mocks.IOutboxRepository)
+	s.mockRenderer = new(mocks.IEmailRenderer)
+	s.mockTransport = new(mocks.IEmailTransport)
+	s.mockAudit = new(mocks.IAuditLogger)
+	s.outbox = usecases.NewEmailOutbox(s.mockRepo, s.mockRenderer).WithAuditLogger(s.mockAudit)
+
+	policy := usecases.DefaultEmailDeliveryPolicy()
+	policy.MaxAttempts = 3
+	policy.BaseDelay = time.Minute
+	s.job = usecases.NewEmailDeliveryJob(s.mockRepo, s.mockTransport, policy)
+	s.mockTransport.On("Name").Return("smtp").Maybe()
+}
+
+func (s *emailOutboxTestSuite) TearDownTest() {
+	s.mockRepo.AssertExpectations(s.T())
+	s.mockRenderer.AssertExpectations(s.T())
+	s.mockTransport.AssertExpectations(s.T())
+	s.mockAudit.AssertExpectations(s.T())
+}
+
+func (s *emailOutboxTestSuite) TestSendEmail_QueuesRenderedEmail() {
+	data := services.EmailData{"Code": "123456", "ExpiresInMinutes": 10}
+	s.mockRenderer.On("Render", services.TemplateVerification, "fr-CA", data).
+		Return(services.RenderedEmail{Locale: "fr", Subject: "Votre code", HTML: "<p>123456</p>", Text: "123456"}, nil)
+	s.mockRepo.On("Enqueue", mock.Anything, mock.MatchedBy(func(m emailpkg.Message) bool {
+		return m.To == "a@example.com" && m.Locale == "fr" && m.Template == services.TemplateVerification &&
+			m.Subject == "Votre code" && m.Text == "123456" && m.Status == emailpkg.StatusPending &&
+			!m.NextAttemptAt.After(time.Now())
+	})).Return(emailpkg.Message{ID: primitive.NewObjectID()}, nil)
+
+	s.NoError(s.outbox.SendEmail("a@example.com", "fr-CA", services.TemplateVerification, data))
+}
+
+func (s *emailOutboxTestSuite) TestSendEmail_RenderErrorIsNotQueued() {
+	s.mockRenderer.On("Render", services.TemplateDigest, "", mock.Anything).
+		Return(services.RenderedEmail{}, errors.New("map has no entry for key \"Items\""))
+
+	s.Error(s.outbox.SendEmail("a@example.com", "", services.TemplateDigest, services.EmailData{}))
+	s.mockRepo.AssertNotCalled(s.T(), "Enqueue", mock.Anything, mock.Anything)
+}
+
+func (s *emailOutboxTestSuite) TestRetryMessage() {
+	id := primitive.NewObjectID()
+	s.mockRepo.On("GetMessage", s.ctx, id.Hex()).
+		Return(emailpkg.Message{ID: id, Status: emailpkg.StatusDead, Attempts: 3}, nil)
+	s.mockRepo.On("Requeue", s.ctx, id.Hex(), mock.Anything).Return(nil)
+	s.mockAudit.On("Record", s.ctx, mock.MatchedBy(func(e auditpkg.Event) bool {
+		return e.Action == auditpkg.ActionEmailRetry && e.TargetID == id.Hex() && e.ActorID == "admin"
+	})).Return(nil)
+
+	message, err := s.outbox.RetryMessage(s.ctx, id.Hex(), "admin")
+	s.Require().NoError(err)
+	s.Equal(emailpkg.StatusPending, message.Status)
+	s.Equal(0, message.Attempts)
+}
+
+func (s *emailOutboxTestSuite) TestRetryMessage_OnlyDead() {
+	id := primitive.NewObjectID()
+	s.mockRepo.On("GetMessage", s.ctx, id.Hex()).Return(emailpkg.Message{ID: id, Status: emailpkg.StatusSent}, nil)
+
+	_, err := s.outbox.RetryMessage(s.ctx, id.Hex(), "admin")
+	s.ErrorIs(err, emailpkg.ErrMessageNotDead)
+}
+
+func (s *emailOutboxTestSuite) TestListMessages_InvalidStatus() {
+	_, err := s.outbox.ListMessages(s.ctx, emailpkg.Filter{Status: "bounced"})
+	s.Error(err)
+}
+
+// claimOnce hands out message once, then reports the outbox empty
+func (s *emailOutboxTestSuite) claimOnce(message emailpkg.Message) {
+	s.mockRepo.On("ClaimDue", s.ctx, mock.Anything, mock.Anything).Return(message, true, nil).Once()
+	s.mockRepo.On("ClaimDue", s.ctx, mock.Anything, mock.Anything).Return(emailpkg.Message{}, false, nil).Once()
+}
+
+func (s *emailOutboxTestSuite) TestDelivery_Sent() {
+	message := emailpkg.Message{ID: primitive.NewObjectID(), To: "a@example.com", Subject: "Hi", Text: "Hello", Attempts: 1}
+	s.claimOnce(message)
+	s.mockTransport.On("Deliver", mock.Anything, "a@example.com", services.RenderedEmail{Subject: "Hi", Text: "Hello"}).Return(nil)
+	s.mockRepo.On("MarkSent", s.ctx, message.ID.Hex(), 2, "smtp", mock.Anything).Return(nil)
+
+	sent, err := s.job.RunOnce(s.ctx)
+	s.NoError(err)
+	s.Equal(1, sent)
+}
+
+func (s *emailOutboxTestSuite) TestDelivery_FailureBacksOffExponentially() {
+	message := emailpkg.Message{ID: primitive.NewObjectID(), To: "a@example.com", Attempts: 1}
+	s.claimOnce(message)
+	s.mockTransport.On("Deliver", mock.Anything, "a@example.com", mock.Anything).Return(errors.New("connection refused"))
+	start := time.Now()
+	s.mockRepo.On("MarkRetry", s.ctx, message.ID.Hex(), 2, "smtp", "connection refused", mock.MatchedBy(func(next time.Time) bool {
+		// Second failure: twice the base delay
+		wait := next.Sub(start)
+		return wait >= 2*time.Minute && wait < 2*time.Minute+10*time.Second
+	})).Return(nil)
+
+	sent, err := s.job.RunOnce(s.ctx)
+	s.NoError(err)
+	s.Equal(0, sent)
+}
+
+func (s *emailOutboxTestSuite) TestDelivery_DeadLettersAfterMaxAttempts() {
+	message := emailpkg.Message{ID: primitive.NewObjectID(), To: "a@example.com", Attempts: 2}
+	s.claimOnce(message)
+	s.mockTransport.On("Deliver", mock.Anything, "a@example.com", mock.Anything).Return(errors.New("timeout"))
+	s.mockRepo.On("MarkDead", s.ctx, message.ID.Hex(), 3, "smtp", "timeout").Return(nil)
+
+	_, err := s.job.RunOnce(s.ctx)
+	s.NoError(err)
+}
+
+func (s *emailOutboxTestSuite) TestDelivery_RejectedIsDeadLetteredAtOnce() {
+	message := emailpkg.Message{ID: primitive.NewObjectID(), To: "nobody@example.com"}
+	s.claimOnce(message)
+	rejected := fmt.Errorf("%w: smtp: 550 no such user", services.ErrEmailRejected)
+	s.mockTransport.On("Deliver", mock.Anything, "nobody@example.com", mock.Anything).Return(rejected)
+	s.mockRepo.On("MarkDead", s.ctx, message.ID.Hex(), 1, "smtp", rejected.Error()).Return(nil)
+
+	_, err := s.job.RunOnce(s.ctx)
+	s.NoError(err)
+}
+
+func (s *emailOutboxTestSuite) TestDelivery_ReclaimedTooOftenIsDeadLettered() {
+	// Every worker that claimed it ran out of lease without reporting back
+	message := emailpkg.Message{ID: primitive.NewObjectID(), To: "a@example.com", Attempts: 3}
+	s.claimOnce(message)
+	s.mockRepo.On("MarkDead", s.ctx, message.ID.Hex(), 3, "smtp", "delivery did not finish within the lease").Return(nil)
+
+	sent, err := s.job.RunOnce(s.ctx)
+
+	s.NoError(err)
+	s.Zero(sent)
+	s.mockTransport.AssertNotCalled(s.T(), "Deliver", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,115 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+
+	auditpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/audit"
+	institutionpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/institution"
+	usecases "github.com/Amaankaa/Blog-Starter-Project/Usecases"
+	"github.com/Amaankaa/Blog-Starter-Project/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type institutionUsecaseTestSuite struct {
+	suite.Suite
+	ctx          context.Context
+	mockRepo     *mocks.IInstitutionRepository
+	mockUserRepo *mocks.IUserRepository
+	mockAudit    *mocks.IAuditLogger
+	usecase      *usecases.InstitutionUsecase
+	existing     institutionpkg.Institution
+}
+
+func TestInstitutionUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(institutionUsecaseTestSuite))
+}
+
+func (s *institutionUsecaseTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockRepo = new(mocks.IInstitutionRepository)
+	s.mockUserRepo = new(mocks.IUserRepository)
+	s.mockAudit = new(mocks.IAuditLogger)
+	s.usecase = usecases.NewInstitutionUsecase(s.mockRepo, s.mockUserRepo).WithAuditLogger(s.mockAudit)
+	s.existing = institutionpkg.Institution{ID: primitive.NewObjectID(), Name: "Addis Ababa University", Domains: []string{"aau.edu.et"}}
+}
+
+func (s *institutionUsecaseTestSuite) TearDownTest() {
+	s.mockRepo.AssertExpectations(s.T())
+	s.mockUserRepo.AssertExpectations(s.T())
+	s.mockAudit.AssertExpectations(s.T())
+}
+
+func (s *institutionUsecaseTestSuite) TestCreate_NormalizesDomains() {
+	s.mockRepo.On("FindByDomain", s.ctx, []string{"bdu.edu.et"}).Return(institutionpkg.Institution{}, false, nil)
+	s.mockRepo.On("CreateInstitution", s.ctx, mock.MatchedBy(func(i institutionpkg.Institution) bool {
+		return i.Name == "Bahir Dar University" && len(i.Domains) == 1 && i.Domains[0] == "bdu.edu.et" && i.CreatedBy == "admin"
+	})).Return(institutionpkg.Institution{ID: primitive.NewObjectID(), Name: "Bahir Dar University", Domains: []string{"bdu.edu.et"}}, nil)
+	s.mockAudit.On("Record", s.ctx, mock.MatchedBy(func(e auditpkg.Event) bool {
+		return e.Action == auditpkg.ActionInstitutionCreate && e.TargetType == auditpkg.TargetInstitution
+	})).Return(nil)
+
+	_, err := s.usecase.CreateInstitution(s.ctx, institutionpkg.InstitutionRequest{
+		Name:    " Bahir Dar University ",
+		Domains: []string{"@BDU.edu.et", "bdu.edu.et"},
+	}, "admin")
+
+	s.NoError(err)
+}
+
+func (s *institutionUsecaseTestSuite) TestCreate_Invalid() {
+	_, err := s.usecase.CreateInstitution(s.ctx, institutionpkg.InstitutionRequest{Name: "  ", Domains: []string{"aau.edu.et"}}, "admin")
+	s.ErrorIs(err, institutionpkg.ErrInvalidInstitution)
+
+	_, err = s.usecase.CreateInstitution(s.ctx, institutionpkg.InstitutionRequest{Name: "Nowhere", Domains: []string{"localhost"}}, "admin")
+	s.ErrorIs(err, institutionpkg.ErrInvalidInstitution)
+}
+
+func (s *institutionUsecaseTestSuite) TestCreate_DomainOwnedElsewhere() {
+	s.mockRepo.On("FindByDomain", s.ctx, []string{"aau.edu.et"}).Return(s.existing, true, nil)
+
+	_, err := s.usecase.CreateInstitution(s.ctx, institutionpkg.InstitutionRequest{Name: "AAU Copy", Domains: []string{"aau.edu.et"}}, "admin")
+
+	s.ErrorIs(err, institutionpkg.ErrDomainInUse)
+	s.mockRepo.AssertNotCalled(s.T(), "CreateInstitution", mock.Anything, mock.Anything)
+}
+
+func (s *institutionUsecaseTestSuite) TestUpdate_RenamesStudentProfiles() {
+	s.mockRepo.On("GetInstitution", s.ctx, s.existing.ID).Return(s.existing, nil)
+	// Keeping its own domain is not a conflict
+	s.mockRepo.On("FindByDomain", s.ctx, []string{"aau.edu.et"}).Return(s.existing, true, nil)
+	s.mockRepo.On("UpdateInstitution", s.ctx, mock.MatchedBy(func(i institutionpkg.Institution) bool {
+		return i.ID == s.existing.ID && i.Name == "AAU" && i.Country == "ET"
+	})).Return(nil)
+	s.mockUserRepo.On("RenameStudentInstitution", s.ctx, s.existing.ID, "AAU").Return(nil)
+	s.mockAudit.On("Record", s.ctx, mock.MatchedBy(func(e auditpkg.Event) bool {
+		return e.Action == auditpkg.ActionInstitutionUpdate && e.Before != nil
+	})).Return(nil)
+
+	institution, err := s.usecase.UpdateInstitution(s.ctx, s.existing.ID.Hex(), institutionpkg.InstitutionRequest{
+		Name:    "AAU",
+		Domains: []string{"aau.edu.et"},
+		Country: "ET",
+	}, "admin")
+
+	s.Require().NoError(err)
+	s.Equal("AAU", institution.Name)
+}
+
+func (s *institutionUsecaseTestSuite) TestDelete_ClearsStudents() {
+	s.mockRepo.On("GetInstitution", s.ctx, s.existing.ID).Return(s.existing, nil)
+	s.mockRepo.On("DeleteInstitution", s.ctx, s.existing.ID).Return(nil)
+	s.mockUserRepo.On("ClearStudentInstitution", s.ctx, s.existing.ID).Return(int64(3), nil)
+	s.mockAudit.On("Record", s.ctx, mock.MatchedBy(func(e auditpkg.Event) bool {
+		return e.Action == auditpkg.ActionInstitutionDelete && e.TargetID == s.existing.ID.Hex()
+	})).Return(nil)
+
+	s.NoError(s.usecase.DeleteInstitution(s.ctx, s.existing.ID.Hex(), "admin"))
+}
+
+func (s *institutionUsecaseTestSuite) TestGet_InvalidID() {
+	_, err := s.usecase.GetInstitution(s.ctx, "nope")
+	s.ErrorIs(err, institutionpkg.ErrInstitutionNotFound)
+}
@@ -0,0 +1,200 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	auditpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/audit"
+	institutionpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/institution"
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// InstitutionUsecase manages the institutions students verify their status with
+type InstitutionUsecase struct {
+	repo     institutionpkg.IInstitutionRepository
+	userRepo userpkg.IUserRepository
+	audit    auditpkg.IAuditLogger
+}
+
+func NewInstitutionUsecase(repo institutionpkg.IInstitutionRepository, userRepo userpkg.IUserRepository) *InstitutionUsecase {
+	return &InstitutionUsecase{repo: repo, userRepo: userRepo}
+}
+
+// WithAuditLogger records institution changes in the audit log
+func (iu *InstitutionUsecase) WithAuditLogger(logger auditpkg.IAuditLogger) *InstitutionUsecase {
+	iu.audit = logger
+	return iu
+}
+
+func (iu *InstitutionUsecase) CreateInstitution(ctx context.Context, req institutionpkg.InstitutionRequest, actorID string) (institutionpkg.Institution, error) {
+	name, domains, err := iu.validate(ctx, req, primitive.NilObjectID)
+	if err != nil {
+		return institutionpkg.Institution{}, err
+	}
+	now := time.Now()
+	institution, err := iu.repo.CreateInstitution(ctx, institutionpkg.Institution{
+		Name:      name,
+		Domains:   domains,
+		Country:   strings.TrimSpace(req.Country),
+		CreatedBy: actorID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	})
+	if err != nil {
+		return institutionpkg.Institution{}, err
+	}
+
+	recordAudit(ctx, iu.audit, auditpkg.Event{
+		ActorID:    actorID,
+		Action:     auditpkg.ActionInstitutionCreate,
+		TargetType: auditpkg.TargetInstitution,
+		TargetID:   institution.ID.Hex(),
+		After:      institutionAuditState(institution),
+	})
+	return institution, nil
+}
+
+func (iu *InstitutionUsecase) UpdateInstitution(ctx context.Context, id string, req institutionpkg.InstitutionRequest, actorID string) (institutionpkg.Institution, error) {
+	before, err := iu.getInstitution(ctx, id)
+	if err != nil {
+		return institutionpkg.Institution{}, err
+	}
+	name, domains, err := iu.validate(ctx, req, before.ID)
+	if err != nil {
+		return institutionpkg.Institution{}, err
+	}
+
+	institution := before
+	institution.Name = name
+	institution.Domains = domains
+	institution.Country = strings.TrimSpace(req.Country)
+	institution.UpdatedAt = time.Now()
+	if err := iu.repo.UpdateInstitution(ctx, institution); err != nil {
+		return institutionpkg.Institution{}, err
+	}
+	// Students verified earlier stay verified even if their domain was
+	// dropped; only the name they show changes
+	if institution.Name != before.Name {
+		if err := iu.userRepo.RenameStudentInstitution(ctx, institution.ID, institution.Name); err != nil {
+			log.Printf("institutions: failed to rename %s on student profiles: %v", institution.ID.Hex(), err)
+		}
+	}
+
+	recordAudit(ctx, iu.audit, auditpkg.Event{
+		ActorID:    actorID,
+		Action:     auditpkg.ActionInstitutionUpdate,
+		TargetType: auditpkg.TargetInstitution,
+		TargetID:   institution.ID.Hex(),
+		Before:     institutionAuditState(before),
+		After:      institutionAuditState(institution),
+	})
+	return institution, nil
+}
+
+func (iu *InstitutionUsecase) DeleteInstitution(ctx context.Context, id, actorID string) error {
+	before, err := iu.getInstitution(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := iu.repo.DeleteInstitution(ctx, before.ID); err != nil {
+		return err
+	}
+	cleared, err := iu.userRepo.ClearStudentInstitution(ctx, before.ID)
+	if err != nil {
+		return errors.New("institution deleted but failed to clear student status: " + err.Error())
+	}
+
+	after := map[string]interface{}{"studentsCleared": cleared}
+	recordAudit(ctx, iu.audit, auditpkg.Event{
+		ActorID:    actorID,
+		Action:     auditpkg.ActionInstitutionDelete,
+		TargetType: auditpkg.TargetInstitution,
+		TargetID:   before.ID.Hex(),
+		Before:     institutionAuditState(before),
+		After:      after,
+	})
+	return nil
+}
+
+func (iu *InstitutionUsecase) GetInstitution(ctx context.Context, id string) (institutionpkg.Institution, error) {
+	return iu.getInstitution(ctx, id)
+}
+
+func (iu *InstitutionUsecase) ListInstitutions(ctx context.Context, query string) ([]institutionpkg.Institution, error) {
+	return iu.repo.ListInstitutions(ctx, strings.TrimSpace(query))
+}
+
+func (iu *InstitutionUsecase) getInstitution(ctx context.Context, id string) (institutionpkg.Institution, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return institutionpkg.Institution{}, institutionpkg.ErrInstitutionNotFound
+	}
+	return iu.repo.GetInstitution(ctx, oid)
+}
+
+// validate trims the name and normalizes the domains, dropping duplicates.
+// A domain may only belong to one institution, self being the one edited.
+func (iu *InstitutionUsecase) validate(ctx context.Context, req institutionpkg.InstitutionRequest, self primitive.ObjectID) (string, []string, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(req.Domains) == 0 {
+		return "", nil, institutionpkg.ErrInvalidInstitution
+	}
+
+	domains := []string{}
+	seen := map[string]bool{}
+	for _, raw := range req.Domains {
+		domain, ok := normalizeRuleDomain(raw)
+		if !ok || !strings.Contains(domain, ".") {
+			return "", nil, institutionpkg.ErrInvalidInstitution
+		}
+		if seen[domain] {
+			continue
+		}
+		seen[domain] = true
+
+		owner, found, err := iu.repo.FindByDomain(ctx, []string{domain})
+		if err != nil {
+			return "", nil, err
+		}
+		if found && owner.ID != self {
+			return "", nil, institutionpkg.ErrDomainInUse
+		}
+		domains = append(domains, domain)
+	}
+	return name, domains, nil
+}
+
+// institutionMemberIDs resolves a feed's institution filter to the IDs of its
+// verified students, narrowed to authorID when the feed filters on that too
+func institutionMemberIDs(ctx context.Context, userRepo userpkg.IUserRepository, institutionID, authorID string) ([]primitive.ObjectID, error) {
+	oid, err := primitive.ObjectIDFromHex(institutionID)
+	if err != nil {
+		return nil, institutionpkg.ErrInstitutionNotFound
+	}
+	ids, err := userRepo.FindStudentIDs(ctx, oid)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find institution members: %w", err)
+	}
+	if authorID == "" {
+		return append([]primitive.ObjectID{}, ids...), nil
+	}
+	for _, id := range ids {
+		if id.Hex() == authorID {
+			return []primitive.ObjectID{id}, nil
+		}
+	}
+	return []primitive.ObjectID{}, nil
+}
+
+func institutionAuditState(institution institutionpkg.Institution) map[string]interface{} {
+	return map[string]interface{}{
+		"name":    institution.Name,
+		"domains": institution.Domains,
+		"country": institution.Country,
+	}
+}
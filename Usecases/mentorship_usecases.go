@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	mentorshippkg "github.com/Amaankaa/Blog-Starter-Project/Domain/mentorship"
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
 	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -12,6 +13,8 @@ import (
 type MentorshipUsecase struct {
 	mentorshipRepo mentorshippkg.IMentorshipRepository
 	userRepo       userpkg.IUserRepository
+
+	emailSender services.IEmailSender // nil: no email notifications
 }
 
 func NewMentorshipUsecase(
@@ -24,6 +27,13 @@ func NewMentorshipUsecase(
 	}
 }
 
+// WithEmailNotifications emails mentors about new requests and mentees about
+// the answer. Notifications are best-effort and never fail the request.
+func (mu *MentorshipUsecase) WithEmailNotifications(sender services.IEmailSender) *MentorshipUsecase {
+	mu.emailSender = sender
+	return mu
+}
+
 // SendMentorshipRequest creates a new mentorship request
 func (mu *MentorshipUsecase) SendMentorshipRequest(ctx context.Context, menteeID string, request mentorshippkg.CreateMentorshipRequestDTO) (mentorshippkg.MentorshipRequestResponse, error) {
 	// Validate the request
@@ -56,7 +66,17 @@ func (mu *MentorshipUsecase) SendMentorshipRequest(ctx context.Context, menteeID
 	}
 
 	// Build response with user information
-	return mu.buildRequestResponse(ctx, createdRequest)
+	response, err := mu.buildRequestResponse(ctx, createdRequest)
+	if err != nil {
+		return mentorshippkg.MentorshipRequestResponse{}, err
+	}
+
+	mu.notify(ctx, createdRequest.MentorID, services.TemplateMentorshipRequest, services.EmailData{
+		"MenteeName": response.MenteeInfo.DisplayName,
+		"Topics":     createdRequest.Topics,
+		"Message":    createdRequest.Message,
+	})
+	return response, nil
 }
 
 // GetMentorshipRequest retrieves a specific mentorship request
@@ -181,7 +201,16 @@ func (mu *MentorshipUsecase) RespondToRequest(ctx context.Context, requestID str
 		return mentorshippkg.MentorshipRequestResponse{}, err
 	}
 
-	return mu.buildRequestResponse(ctx, updatedRequest)
+	result, err := mu.buildRequestResponse(ctx, updatedRequest)
+	if err != nil {
+		return mentorshippkg.MentorshipRequestResponse{}, err
+	}
+
+	mu.notify(ctx, updatedRequest.MenteeID, services.TemplateMentorshipResponse, services.EmailData{
+		"MentorName": result.MentorInfo.DisplayName,
+		"Accepted":   newStatus == mentorshippkg.StatusAccepted,
+	})
+	return result, nil
 }
 
 // CancelRequest allows a mentee to cancel their mentorship request
@@ -554,6 +583,19 @@ func (mu *MentorshipUsecase) ValidateConnectionAccess(ctx context.Context, conne
 
 // Helper methods for building responses
 
+// notify emails template to the user with userID in their own locale
+func (mu *MentorshipUsecase) notify(ctx context.Context, userID primitive.ObjectID, template services.EmailTemplate, data services.EmailData) {
+	if mu.emailSender == nil {
+		return
+	}
+	user, err := mu.userRepo.FindByID(ctx, userID.Hex())
+	if err != nil {
+		return
+	}
+	data["Name"] = user.Username
+	_ = mu.emailSender.SendEmail(user.Email, user.Locale, template, data)
+}
+
 // buildRequestResponse builds a MentorshipRequestResponse with user information
 func (mu *MentorshipUsecase) buildRequestResponse(ctx context.Context, request mentorshippkg.MentorshipRequest) (mentorshippkg.MentorshipRequestResponse, error) {
 	// Get mentee info
@@ -335,6 +335,48 @@ func (s *PostUsecaseTestSuite) TestLikePost_AlreadyLiked() {
 	s.Contains(err.Error(), "already liked")
 }
 
+// Test HidePost
+func (s *PostUsecaseTestSuite) TestHidePost_Success() {
+	// Arrange
+	postID := primitive.NewObjectID()
+	s.mockPostRepo.On("GetPostByID", s.ctx, postID).Return(&postpkg.Post{ID: postID}, nil)
+	s.mockPostRepo.On("HidePost", s.ctx, postID).Return(nil)
+
+	// Act
+	err := s.usecase.HidePost(s.ctx, postID, primitive.NewObjectID())
+
+	// Assert
+	s.NoError(err)
+}
+
+func (s *PostUsecaseTestSuite) TestHidePost_NotFound() {
+	// Arrange
+	postID := primitive.NewObjectID()
+	s.mockPostRepo.On("GetPostByID", s.ctx, postID).Return(nil, errors.New("post not found"))
+
+	// Act
+	err := s.usecase.HidePost(s.ctx, postID, primitive.NewObjectID())
+
+	// Assert
+	s.EqualError(err, "post not found")
+}
+
+func (s *PostUsecaseTestSuite) TestGetPosts_ByInstitution() {
+	// Arrange
+	institutionID := primitive.NewObjectID()
+	studentID := primitive.NewObjectID()
+	s.mockUserRepo.On("FindStudentIDs", s.ctx, institutionID).Return([]primitive.ObjectID{studentID}, nil)
+	s.mockPostRepo.On("GetPosts", s.ctx, mock.MatchedBy(func(f postpkg.PostFilter) bool {
+		return len(f.AuthorIDs) == 1 && f.AuthorIDs[0] == studentID && f.IsAnonymous != nil && !*f.IsAnonymous
+	}), mock.Anything).Return([]postpkg.Post{}, int64(0), nil)
+
+	// Act
+	_, err := s.usecase.GetPosts(s.ctx, postpkg.PostFilter{Institution: institutionID.Hex()}, postpkg.PostPagination{}, nil)
+
+	// Assert
+	s.NoError(err)
+}
+
 // Test ValidatePostCategory
 func (s *PostUsecaseTestSuite) TestValidatePostCategory_Valid() {
 	// Act
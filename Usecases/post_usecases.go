@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"math"
 	"slices"
 	"strings"
 
+	auditpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/audit"
 	postpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/post"
 	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -16,6 +18,7 @@ import (
 type PostUsecase struct {
 	postRepo postpkg.PostRepository
 	userRepo userpkg.IUserRepository
+	audit    auditpkg.IAuditLogger
 }
 
 func NewPostUsecase(
@@ -28,6 +31,12 @@ func NewPostUsecase(
 	}
 }
 
+// WithAuditLogger records moderation actions
+func (uc *PostUsecase) WithAuditLogger(logger auditpkg.IAuditLogger) *PostUsecase {
+	uc.audit = logger
+	return uc
+}
+
 // CreatePost creates a new post with validation
 func (uc *PostUsecase) CreatePost(ctx context.Context, req postpkg.CreatePostRequest, authorID primitive.ObjectID) (*postpkg.PostResponse, error) {
 	// Validate category
@@ -185,6 +194,18 @@ func (uc *PostUsecase) GetPosts(ctx context.Context, filter postpkg.PostFilter,
 		pagination.SortOrder = "desc"
 	}
 
+	// Anonymous posts stay out of institution feeds, or they would give
+	// their author's institution away
+	if filter.Institution != "" {
+		ids, err := institutionMemberIDs(ctx, uc.userRepo, filter.Institution, filter.AuthorID)
+		if err != nil {
+			return nil, err
+		}
+		filter.AuthorIDs = ids
+		notAnonymous := false
+		filter.IsAnonymous = &notAnonymous
+	}
+
 	// Get posts
 	posts, total, err := uc.postRepo.GetPosts(ctx, filter, pagination)
 	if err != nil {
@@ -357,6 +378,44 @@ func (uc *PostUsecase) ReportPost(ctx context.Context, postID, reporterID primit
 	return uc.postRepo.ReportPost(ctx, postID)
 }
 
+// HidePost takes a post out of public view without deleting it
+func (uc *PostUsecase) HidePost(ctx context.Context, postID, moderatorID primitive.ObjectID) error {
+	post, err := uc.postRepo.GetPostByID(ctx, postID)
+	if err != nil {
+		return err
+	}
+	if err := uc.postRepo.HidePost(ctx, postID); err != nil {
+		return err
+	}
+	log.Printf("moderation: post hidden post=%s by=%s", postID.Hex(), moderatorID.Hex())
+	recordAudit(ctx, uc.audit, auditpkg.Event{
+		ActorID:    moderatorID.Hex(),
+		Action:     auditpkg.ActionPostHide,
+		TargetType: auditpkg.TargetPost,
+		TargetID:   postID.Hex(),
+		Before:     map[string]string{"status": post.Status},
+		After:      map[string]string{"status": postpkg.PostStatusHidden},
+	})
+	return nil
+}
+
+// UnhidePost makes a hidden post public again
+func (uc *PostUsecase) UnhidePost(ctx context.Context, postID, moderatorID primitive.ObjectID) error {
+	if err := uc.postRepo.UnhidePost(ctx, postID); err != nil {
+		return err
+	}
+	log.Printf("moderation: post unhidden post=%s by=%s", postID.Hex(), moderatorID.Hex())
+	recordAudit(ctx, uc.audit, auditpkg.Event{
+		ActorID:    moderatorID.Hex(),
+		Action:     auditpkg.ActionPostUnhide,
+		TargetType: auditpkg.TargetPost,
+		TargetID:   postID.Hex(),
+		Before:     map[string]string{"status": postpkg.PostStatusHidden},
+		After:      map[string]string{"status": postpkg.PostStatusActive},
+	})
+	return nil
+}
+
 // ValidatePostCategory validates if a category is allowed
 func (uc *PostUsecase) ValidatePostCategory(category string) error {
 	if !slices.Contains(postpkg.PostCategories, category) {
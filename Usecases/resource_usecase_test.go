@@ -340,6 +340,18 @@ func (s *ResourceUsecaseTestSuite) TestGetResourcesWithDeadlines_Error() {
 	s.Error(err)
 }
 
+func (s *ResourceUsecaseTestSuite) TestGetResources_ByInstitution() {
+	institutionID := primitive.NewObjectID()
+	s.mockUsers.On("FindStudentIDs", mock.Anything, institutionID).Return([]primitive.ObjectID{primitive.NewObjectID()}, nil)
+	// A creator who is not one of its students matches nothing
+	s.mockRepo.On("GetResources", mock.Anything, mock.MatchedBy(func(f resourcepkg.ResourceFilter) bool {
+		return f.CreatorIDs != nil && len(f.CreatorIDs) == 0
+	}), mock.AnythingOfType("resourcepkg.ResourcePagination")).Return([]resourcepkg.Resource{}, int64(0), nil)
+	resp, err := s.usecase.GetResources(s.ctx, resourcepkg.ResourceFilter{Institution: institutionID.Hex(), CreatorID: primitive.NewObjectID().Hex()}, resourcepkg.ResourcePagination{}, nil)
+	s.NoError(err)
+	s.Equal(int64(0), resp.Total)
+}
+
 func (s *ResourceUsecaseTestSuite) TestReportResource_Success() {
 	id := primitive.NewObjectID()
 	s.mockRepo.On("GetResourceByID", mock.Anything, id).Return(&resourcepkg.Resource{ID: id}, nil)
@@ -370,6 +382,21 @@ func (s *ResourceUsecaseTestSuite) TestVerifyResource_NotFound() {
 	s.Error(err)
 }
 
+func (s *ResourceUsecaseTestSuite) TestHideResource_Success() {
+	id := primitive.NewObjectID()
+	s.mockRepo.On("GetResourceByID", mock.Anything, id).Return(&resourcepkg.Resource{ID: id}, nil)
+	s.mockRepo.On("HideResource", mock.Anything, id).Return(nil)
+	err := s.usecase.HideResource(s.ctx, id, primitive.NewObjectID())
+	s.NoError(err)
+}
+
+func (s *ResourceUsecaseTestSuite) TestUnhideResource_NotHidden() {
+	id := primitive.NewObjectID()
+	s.mockRepo.On("UnhideResource", mock.Anything, id).Return(errors.New("resource not found"))
+	err := s.usecase.UnhideResource(s.ctx, id, primitive.NewObjectID())
+	s.Error(err)
+}
+
 func (s *ResourceUsecaseTestSuite) TestSearchResources_EmptyQuery() {
 	_, err := s.usecase.SearchResources(s.ctx, " ", resourcepkg.ResourceFilter{}, resourcepkg.ResourcePagination{}, nil)
 	s.Error(err)
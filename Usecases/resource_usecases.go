@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"math"
 	"strings"
 
+	auditpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/audit"
 	resourcepkg "github.com/Amaankaa/Blog-Starter-Project/Domain/resource"
 	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -15,12 +17,19 @@ import (
 type ResourceUsecase struct {
 	resourceRepo resourcepkg.ResourceRepository
 	userRepo     userpkg.IUserRepository
+	audit        auditpkg.IAuditLogger
 }
 
 func NewResourceUsecase(resourceRepo resourcepkg.ResourceRepository, userRepo userpkg.IUserRepository) *ResourceUsecase {
 	return &ResourceUsecase{resourceRepo: resourceRepo, userRepo: userRepo}
 }
 
+// WithAuditLogger records verification and moderation actions
+func (uc *ResourceUsecase) WithAuditLogger(logger auditpkg.IAuditLogger) *ResourceUsecase {
+	uc.audit = logger
+	return uc
+}
+
 // Core
 func (uc *ResourceUsecase) CreateResource(ctx context.Context, req resourcepkg.CreateResourceRequest, creatorID primitive.ObjectID) (*resourcepkg.ResourceResponse, error) {
 	if err := uc.ValidateResourceType(req.Type); err != nil {
@@ -181,6 +190,13 @@ func (uc *ResourceUsecase) DeleteResource(ctx context.Context, id primitive.Obje
 // Lists
 func (uc *ResourceUsecase) GetResources(ctx context.Context, filter resourcepkg.ResourceFilter, pagination resourcepkg.ResourcePagination, viewerID *primitive.ObjectID) (*resourcepkg.ResourceListResponse, error) {
 	setDefaults(&pagination)
+	if filter.Institution != "" {
+		ids, err := institutionMemberIDs(ctx, uc.userRepo, filter.Institution, filter.CreatorID)
+		if err != nil {
+			return nil, err
+		}
+		filter.CreatorIDs = ids
+	}
 	items, total, err := uc.resourceRepo.GetResources(ctx, filter, pagination)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get resources: %w", err)
@@ -501,11 +517,60 @@ func (uc *ResourceUsecase) ReportResource(ctx context.Context, resourceID, repor
 }
 
 func (uc *ResourceUsecase) VerifyResource(ctx context.Context, resourceID, verifierID primitive.ObjectID) error {
-	_, err := uc.resourceRepo.GetResourceByID(ctx, resourceID)
+	resource, err := uc.resourceRepo.GetResourceByID(ctx, resourceID)
 	if err != nil {
 		return err
 	}
-	return uc.resourceRepo.VerifyResource(ctx, resourceID, verifierID)
+	if err := uc.resourceRepo.VerifyResource(ctx, resourceID, verifierID); err != nil {
+		return err
+	}
+	recordAudit(ctx, uc.audit, auditpkg.Event{
+		ActorID:    verifierID.Hex(),
+		Action:     auditpkg.ActionResourceVerify,
+		TargetType: auditpkg.TargetResource,
+		TargetID:   resourceID.Hex(),
+		Before:     map[string]bool{"isVerified": resource.IsVerified},
+		After:      map[string]bool{"isVerified": true},
+	})
+	return nil
+}
+
+// HideResource takes a resource out of public view without deleting it
+func (uc *ResourceUsecase) HideResource(ctx context.Context, resourceID, moderatorID primitive.ObjectID) error {
+	resource, err := uc.resourceRepo.GetResourceByID(ctx, resourceID)
+	if err != nil {
+		return err
+	}
+	if err := uc.resourceRepo.HideResource(ctx, resourceID); err != nil {
+		return err
+	}
+	log.Printf("moderation: resource hidden resource=%s by=%s", resourceID.Hex(), moderatorID.Hex())
+	recordAudit(ctx, uc.audit, auditpkg.Event{
+		ActorID:    moderatorID.Hex(),
+		Action:     auditpkg.ActionResourceHide,
+		TargetType: auditpkg.TargetResource,
+		TargetID:   resourceID.Hex(),
+		Before:     map[string]string{"status": resource.Status},
+		After:      map[string]string{"status": resourcepkg.ResourceStatusHidden},
+	})
+	return nil
+}
+
+// UnhideResource makes a hidden resource public again
+func (uc *ResourceUsecase) UnhideResource(ctx context.Context, resourceID, moderatorID primitive.ObjectID) error {
+	if err := uc.resourceRepo.UnhideResource(ctx, resourceID); err != nil {
+		return err
+	}
+	log.Printf("moderation: resource unhidden resource=%s by=%s", resourceID.Hex(), moderatorID.Hex())
+	recordAudit(ctx, uc.audit, auditpkg.Event{
+		ActorID:    moderatorID.Hex(),
+		Action:     auditpkg.ActionResourceUnhide,
+		TargetType: auditpkg.TargetResource,
+		TargetID:   resourceID.Hex(),
+		Before:     map[string]string{"status": resourcepkg.ResourceStatusHidden},
+		After:      map[string]string{"status": resourcepkg.ResourceStatusActive},
+	})
+	return nil
 }
 
 // Validation helpers
@@ -0,0 +1,165 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	utils "github.com/Amaankaa/Blog-Starter-Project/Domain/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// maxAccessTokensPerUser keeps a leaked account from minting tokens without limit
+	maxAccessTokensPerUser = 25
+	maxAccessTokenNameLen  = 100
+	// accessTokenPrefixLen is how much of the secret is kept in the clear so
+	// users can tell their tokens apart
+	accessTokenPrefixLen = 8
+)
+
+// WithPersonalAccessTokens lets users create scoped tokens for scripts and
+// integrations
+func (uu *UserUsecase) WithPersonalAccessTokens(repo userpkg.IPersonalAccessTokenRepository) *UserUsecase {
+	uu.accessTokens = repo
+	return uu
+}
+
+// CreateAccessToken issues a named token limited to scopes. The secret is
+// returned once and only its hash is stored.
+func (uu *UserUsecase) CreateAccessToken(ctx context.Context, userID, name string, scopes []string, expiresAt *time.Time) (userpkg.CreatedAccessToken, error) {
+	if uu.accessTokens == nil {
+		return userpkg.CreatedAccessToken{}, errors.New("personal access tokens are not enabled")
+	}
+	oid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return userpkg.CreatedAccessToken{}, errors.New("invalid user ID")
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return userpkg.CreatedAccessToken{}, errors.New("name is required")
+	}
+	if len(name) > maxAccessTokenNameLen {
+		return userpkg.CreatedAccessToken{}, errors.New("name is too long")
+	}
+	scopes, err = normalizeTokenScopes(scopes)
+	if err != nil {
+		return userpkg.CreatedAccessToken{}, err
+	}
+	now := time.Now()
+	if expiresAt != nil && !expiresAt.After(now) {
+		return userpkg.CreatedAccessToken{}, errors.New("expiry must be in the future")
+	}
+
+	count, err := uu.accessTokens.CountAccessTokens(ctx, oid)
+	if err != nil {
+		return userpkg.CreatedAccessToken{}, errors.New("failed to count access tokens")
+	}
+	if count >= maxAccessTokensPerUser {
+		return userpkg.CreatedAccessToken{}, errors.New("access token limit reached")
+	}
+
+	secret, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return userpkg.CreatedAccessToken{}, errors.New("failed to generate access token")
+	}
+	raw := userpkg.PersonalAccessTokenPrefix + secret
+
+	token, err := uu.accessTokens.CreateAccessToken(ctx, userpkg.PersonalAccessToken{
+		UserID:    oid,
+		Name:      name,
+		Prefix:    raw[:len(userpkg.PersonalAccessTokenPrefix)+accessTokenPrefixLen],
+		TokenHash: utils.HashToken(raw),
+		Scopes:    scopes,
+		CreatedAt: now,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return userpkg.CreatedAccessToken{}, errors.New("failed to store access token")
+	}
+	log.Printf("security: access token created user=%s token=%s scopes=%s", userID, token.ID.Hex(), strings.Join(scopes, ","))
+	return userpkg.CreatedAccessToken{PersonalAccessToken: token, Token: raw}, nil
+}
+
+// ListAccessTokens returns the user's tokens without their secrets
+func (uu *UserUsecase) ListAccessTokens(ctx context.Context, userID string) ([]userpkg.PersonalAccessToken, error) {
+	if uu.accessTokens == nil {
+		return []userpkg.PersonalAccessToken{}, nil
+	}
+	oid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+	tokens, err := uu.accessTokens.ListAccessTokens(ctx, oid)
+	if err != nil {
+		return nil, errors.New("failed to list access tokens")
+	}
+	return tokens, nil
+}
+
+// RevokeAccessToken deletes one of the user's tokens; it stops working at once
+func (uu *UserUsecase) RevokeAccessToken(ctx context.Context, userID, tokenID string) error {
+	if uu.accessTokens == nil {
+		return userpkg.ErrAccessTokenNotFound
+	}
+	oid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return errors.New("invalid user ID")
+	}
+	tid, err := primitive.ObjectIDFromHex(tokenID)
+	if err != nil {
+		return userpkg.ErrAccessTokenNotFound
+	}
+	if err := uu.accessTokens.DeleteAccessToken(ctx, oid, tid); err != nil {
+		return err
+	}
+	log.Printf("security: access token revoked user=%s token=%s", userID, tokenID)
+	return nil
+}
+
+// normalizeTokenScopes rejects unknown scopes and drops duplicates
+func normalizeTokenScopes(scopes []string) ([]string, error) {
+	if len(scopes) == 0 {
+		return nil, errors.New("at least one scope is required")
+	}
+	seen := make(map[string]bool, len(scopes))
+	out := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if !validTokenScope(scope) {
+			return nil, errors.New("unknown scope: " + scope)
+		}
+		if seen[scope] {
+			continue
+		}
+		seen[scope] = true
+		out = append(out, scope)
+	}
+	return out, nil
+}
+
+func validTokenScope(scope string) bool {
+	for _, s := range userpkg.TokenScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteAllAccessTokens revokes every personal access token of the user, so
+// a password reset also locks out tokens minted by whoever knew the old one
+func (uu *UserUsecase) deleteAllAccessTokens(ctx context.Context, userID string) error {
+	if uu.accessTokens == nil {
+		return nil
+	}
+	oid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return errors.New("invalid user ID")
+	}
+	return uu.accessTokens.DeleteAccessTokensByUserID(ctx, oid)
+}
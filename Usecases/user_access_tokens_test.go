@@ -0,0 +1,96 @@
+package usecases_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	utils "github.com/Amaankaa/Blog-Starter-Project/Domain/utils"
+	usecases "github.com/Amaankaa/Blog-Starter-Project/Usecases"
+	"github.com/Amaankaa/Blog-Starter-Project/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type accessTokenTestSuite struct {
+	suite.Suite
+	ctx        context.Context
+	mockTokens *mocks.IPersonalAccessTokenRepository
+	usecase    *usecases.UserUsecase
+	userID     primitive.ObjectID
+}
+
+func TestAccessTokenTestSuite(t *testing.T) {
+	suite.Run(t, new(accessTokenTestSuite))
+}
+
+func (s *accessTokenTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockTokens = new(mocks.IPersonalAccessTokenRepository)
+	s.usecase = usecases.NewUserUsecase(
+		new(mocks.IUserRepository),
+		new(mocks.IPasswordService),
+		new(mocks.ITokenRepository),
+		new(mocks.IJWTService),
+		new(mocks.IEmailVerifier),
+		new(mocks.IEmailSender),
+		new(mocks.IPasswordResetRepository),
+		new(mocks.IVerificationRepository),
+		new(mocks.ICloudinaryService),
+	).WithPersonalAccessTokens(s.mockTokens)
+	s.userID = primitive.NewObjectID()
+}
+
+func (s *accessTokenTestSuite) TearDownTest() {
+	s.mockTokens.AssertExpectations(s.T())
+}
+
+func (s *accessTokenTestSuite) TestCreateAccessToken_StoresHash() {
+	s.mockTokens.On("CountAccessTokens", s.ctx, s.userID).Return(int64(0), nil)
+	var stored userpkg.PersonalAccessToken
+	s.mockTokens.On("CreateAccessToken", s.ctx, mock.AnythingOfType("userpkg.PersonalAccessToken")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(userpkg.PersonalAccessToken) }).
+		Return(func(_ context.Context, t userpkg.PersonalAccessToken) userpkg.PersonalAccessToken { return t }, nil)
+
+	created, err := s.usecase.CreateAccessToken(s.ctx, s.userID.Hex(), " ci ", []string{"resources:write", "resources:write"}, nil)
+
+	s.Require().NoError(err)
+	s.True(strings.HasPrefix(created.Token, userpkg.PersonalAccessTokenPrefix))
+	s.Equal(utils.HashToken(created.Token), stored.TokenHash)
+	s.True(strings.HasPrefix(created.Token, stored.Prefix))
+	s.Equal("ci", stored.Name)
+	s.Equal([]string{userpkg.ScopeResourcesWrite}, stored.Scopes)
+}
+
+func (s *accessTokenTestSuite) TestCreateAccessToken_Rejects() {
+	past := time.Now().Add(-time.Minute)
+
+	_, err := s.usecase.CreateAccessToken(s.ctx, s.userID.Hex(), "", []string{userpkg.ScopePostsWrite}, nil)
+	s.EqualError(err, "name is required")
+	_, err = s.usecase.CreateAccessToken(s.ctx, s.userID.Hex(), "ci", nil, nil)
+	s.EqualError(err, "at least one scope is required")
+	_, err = s.usecase.CreateAccessToken(s.ctx, s.userID.Hex(), "ci", []string{"users:manage"}, nil)
+	s.EqualError(err, "unknown scope: users:manage")
+	_, err = s.usecase.CreateAccessToken(s.ctx, s.userID.Hex(), "ci", []string{userpkg.ScopePostsWrite}, &past)
+	s.EqualError(err, "expiry must be in the future")
+}
+
+func (s *accessTokenTestSuite) TestCreateAccessToken_LimitReached() {
+	s.mockTokens.On("CountAccessTokens", s.ctx, s.userID).Return(int64(25), nil)
+
+	_, err := s.usecase.CreateAccessToken(s.ctx, s.userID.Hex(), "ci", []string{userpkg.ScopePostsWrite}, nil)
+
+	s.EqualError(err, "access token limit reached")
+}
+
+func (s *accessTokenTestSuite) TestRevokeAccessToken_NotOwned() {
+	tokenID := primitive.NewObjectID()
+	s.mockTokens.On("DeleteAccessToken", s.ctx, s.userID, tokenID).Return(userpkg.ErrAccessTokenNotFound)
+
+	err := s.usecase.RevokeAccessToken(s.ctx, s.userID.Hex(), tokenID.Hex())
+
+	s.ErrorIs(err, userpkg.ErrAccessTokenNotFound)
+}
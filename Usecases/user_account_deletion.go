@@ -0,0 +1,97 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+)
+
+// DefaultAccountDeletionGrace is how long a deletion can be undone
+const DefaultAccountDeletionGrace = 7 * 24 * time.Hour
+
+// WithAccountDeletion enables self-service account deletion. Accounts are
+// erased by an AccountDeletionJob once grace has passed.
+func (uu *UserUsecase) WithAccountDeletion(repo userpkg.IAccountDeletionRepository, grace time.Duration) *UserUsecase {
+	uu.accountDeletions = repo
+	uu.deletionGrace = grace
+	return uu
+}
+
+// RequestAccountDeletion schedules the account for deletion after the grace
+// period and signs it out everywhere. Signing back in and cancelling undoes it.
+// Accounts without a password pass a code from SendReauthCode as password.
+func (uu *UserUsecase) RequestAccountDeletion(ctx context.Context, userID, password, contentMode string) (userpkg.AccountDeletion, error) {
+	if uu.accountDeletions == nil {
+		return userpkg.AccountDeletion{}, errors.New("account deletion is not enabled")
+	}
+	if contentMode != userpkg.DeletionContentDelete && contentMode != userpkg.DeletionContentAnonymize {
+		return userpkg.AccountDeletion{}, errors.New(`content must be "delete" or "anonymize"`)
+	}
+
+	user, err := uu.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return userpkg.AccountDeletion{}, errors.New("user not found")
+	}
+	if err := uu.reauthenticate(ctx, user, password); err != nil {
+		return userpkg.AccountDeletion{}, err
+	}
+
+	now := time.Now()
+	deletion := userpkg.AccountDeletion{
+		UserID:       user.ID,
+		Email:        user.Email,
+		ContentMode:  contentMode,
+		RequestedAt:  now,
+		ScheduledFor: now.Add(uu.deletionGrace),
+	}
+	if err := uu.accountDeletions.ScheduleDeletion(ctx, deletion); err != nil {
+		return userpkg.AccountDeletion{}, errors.New("failed to schedule account deletion")
+	}
+	log.Printf("security: account deletion requested user=%s content=%s scheduled_for=%s",
+		userID, contentMode, deletion.ScheduledFor.UTC().Format(time.RFC3339))
+
+	if err := uu.tokenRepo.DeleteTokensByUserID(ctx, userID); err != nil {
+		return userpkg.AccountDeletion{}, errors.New("deletion scheduled but failed to end existing sessions")
+	}
+	_ = uu.revokeAccessTokens(ctx, userID)
+	// Personal access tokens would otherwise keep working through the grace period
+	if err := uu.deleteAllAccessTokens(ctx, userID); err != nil {
+		return userpkg.AccountDeletion{}, errors.New("deletion scheduled but failed to revoke personal access tokens")
+	}
+
+	_ = uu.emailSender.SendEmail(user.Email, user.Locale, services.TemplateDeletionScheduled, services.EmailData{
+		"Name":         user.Username,
+		"ScheduledFor": deletion.ScheduledFor,
+	})
+	return deletion, nil
+}
+
+// GetAccountDeletion returns the user's pending deletion, if any
+func (uu *UserUsecase) GetAccountDeletion(ctx context.Context, userID string) (userpkg.AccountDeletion, error) {
+	if uu.accountDeletions == nil {
+		return userpkg.AccountDeletion{}, userpkg.ErrDeletionNotFound
+	}
+	return uu.accountDeletions.GetDeletion(ctx, userID)
+}
+
+// CancelAccountDeletion undoes a pending deletion during the grace period
+func (uu *UserUsecase) CancelAccountDeletion(ctx context.Context, userID string) error {
+	if uu.accountDeletions == nil {
+		return userpkg.ErrDeletionNotFound
+	}
+	if err := uu.accountDeletions.CancelDeletion(ctx, userID); err != nil {
+		return err
+	}
+	log.Printf("security: account deletion cancelled user=%s", userID)
+
+	if user, err := uu.userRepo.FindByID(ctx, userID); err == nil {
+		_ = uu.emailSender.SendEmail(user.Email, user.Locale, services.TemplateDeletionCancelled, services.EmailData{
+			"Name": user.Username,
+		})
+	}
+	return nil
+}
@@ -0,0 +1,294 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mentorshippkg "github.com/Amaankaa/Blog-Starter-Project/Domain/mentorship"
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	usecases "github.com/Amaankaa/Blog-Starter-Project/Usecases"
+	"github.com/Amaankaa/Blog-Starter-Project/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type accountDeletionTestSuite struct {
+	suite.Suite
+	ctx              context.Context
+	mockUserRepo     *mocks.IUserRepository
+	mockPasswordSvc  *mocks.IPasswordService
+	mockTokenRepo    *mocks.ITokenRepository
+	mockEmailSender  *mocks.IEmailSender
+	mockDeletions    *mocks.IAccountDeletionRepository
+	mockVerification *mocks.IVerificationRepository
+	mockResets       *mocks.IPasswordResetRepository
+	mockPosts        *mocks.PostRepository
+	mockComments     *mocks.ICommentRepository
+	mockResources    *mocks.ResourceRepository
+	mockMentorship   *mocks.IMentorshipRepository
+	mockMessaging    *mocks.IMessagingRepository
+	mockAccessTokens *mocks.IPersonalAccessTokenRepository
+	mockExports      *mocks.IDataExportRepository
+	mockExportStore  *mocks.IExportStore
+	mockEmailChanges *mocks.IEmailChangeRepository
+	mockChallenges   *mocks.IMFAChallengeRepository
+	mockAttempts     *mocks.ILoginAttemptRepository
+	usecase          *usecases.UserUsecase
+	job              *usecases.AccountDeletionJob
+	user             userpkg.User
+	tombstone        userpkg.User
+}
+
+func TestAccountDeletionTestSuite(t *testing.T) {
+	suite.Run(t, new(accountDeletionTestSuite))
+}
+
+func (s *accountDeletionTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockUserRepo = new(mocks.IUserRepository)
+	s.mockPasswordSvc = new(mocks.IPasswordService)
+	s.mockTokenRepo = new(mocks.ITokenRepository)
+	s.mockEmailSender = new(mocks.IEmailSender)
+	s.mockDeletions = new(mocks.IAccountDeletionRepository)
+	s.mockVerification = new(mocks.IVerificationRepository)
+	s.mockResets = new(mocks.IPasswordResetRepository)
+	s.mockPosts = new(mocks.PostRepository)
+	s.mockComments = new(mocks.ICommentRepository)
+	s.mockResources = new(mocks.ResourceRepository)
+	s.mockMentorship = new(mocks.IMentorshipRepository)
+	s.mockMessaging = new(mocks.IMessagingRepository)
+	s.mockAccessTokens = new(mocks.IPersonalAccessTokenRepository)
+	s.mockExports = new(mocks.IDataExportRepository)
+	s.mockExportStore = new(mocks.IExportStore)
+	s.mockEmailChanges = new(mocks.IEmailChangeRepository)
+	s.mockChallenges = new(mocks.IMFAChallengeRepository)
+	s.mockAttempts = new(mocks.ILoginAttemptRepository)
+
+	s.usecase = usecases.NewUserUsecase(
+		s.mockUserRepo,
+		s.mockPasswordSvc,
+		s.mockTokenRepo,
+		new(mocks.IJWTService),
+		new(mocks.IEmailVerifier),
+		s.mockEmailSender,
+		s.mockResets,
+		s.mockVerification,
+		new(mocks.ICloudinaryService),
+	).WithAccountDeletion(s.mockDeletions, 7*24*time.Hour).
+		WithPersonalAccessTokens(s.mockAccessTokens)
+
+	s.job = usecases.NewAccountDeletionJob(
+		s.mockDeletions,
+		s.mockUserRepo,
+		s.mockTokenRepo,
+		s.mockVerification,
+		s.mockResets,
+		s.mockPosts,
+		s.mockComments,
+		s.mockResources,
+		s.mockMentorship,
+		s.mockMessaging,
+		s.mockAccessTokens,
+		s.mockExports,
+		s.mockExportStore,
+		s.mockEmailChanges,
+		s.mockChallenges,
+		s.mockAttempts,
+	)
+
+	s.user = userpkg.User{ID: primitive.NewObjectID(), Email: "bob@example.com", Password: "hashed"}
+	s.tombstone = userpkg.User{ID: primitive.NewObjectID(), Username: userpkg.TombstoneUsername}
+}
+
+func (s *accountDeletionTestSuite) TearDownTest() {
+	s.mockUserRepo.AssertExpectations(s.T())
+	s.mockPasswordSvc.AssertExpectations(s.T())
+	s.mockTokenRepo.AssertExpectations(s.T())
+	s.mockEmailSender.AssertExpectations(s.T())
+	s.mockDeletions.AssertExpectations(s.T())
+	s.mockPosts.AssertExpectations(s.T())
+	s.mockComments.AssertExpectations(s.T())
+	s.mockResources.AssertExpectations(s.T())
+	s.mockMentorship.AssertExpectations(s.T())
+	s.mockMessaging.AssertExpectations(s.T())
+	s.mockAccessTokens.AssertExpectations(s.T())
+	s.mockExports.AssertExpectations(s.T())
+	s.mockExportStore.AssertExpectations(s.T())
+	s.mockEmailChanges.AssertExpectations(s.T())
+	s.mockChallenges.AssertExpectations(s.T())
+	s.mockAttempts.AssertExpectations(s.T())
+}
+
+func (s *accountDeletionTestSuite) TestRequestAccountDeletion_Schedules() {
+	userID := s.user.ID.Hex()
+	s.mockUserRepo.On("FindByID", s.ctx, userID).Return(s.user, nil)
+	s.mockPasswordSvc.On("ComparePassword", "hashed", "secret").Return(nil)
+	s.mockDeletions.On("ScheduleDeletion", s.ctx, mock.MatchedBy(func(d userpkg.AccountDeletion) bool {
+		return d.UserID == s.user.ID && d.Email == s.user.Email && d.ContentMode == userpkg.DeletionContentDelete &&
+			d.ScheduledFor.Sub(d.RequestedAt) == 7*24*time.Hour
+	})).Return(nil)
+	s.mockTokenRepo.On("DeleteTokensByUserID", s.ctx, userID).Return(nil)
+	s.mockAccessTokens.On("DeleteAccessTokensByUserID", s.ctx, s.user.ID).Return(nil)
+	s.mockEmailSender.On("SendEmail", s.user.Email, "", services.TemplateDeletionScheduled, mock.Anything).Return(nil)
+
+	deletion, err := s.usecase.RequestAccountDeletion(s.ctx, userID, "secret", userpkg.DeletionContentDelete)
+
+	s.NoError(err)
+	s.WithinDuration(time.Now().Add(7*24*time.Hour), deletion.ScheduledFor, time.Second)
+}
+
+func (s *accountDeletionTestSuite) TestRequestAccountDeletion_WrongPassword() {
+	userID := s.user.ID.Hex()
+	s.mockUserRepo.On("FindByID", s.ctx, userID).Return(s.user, nil)
+	s.mockPasswordSvc.On("ComparePassword", "hashed", "wrong").Return(errors.New("mismatch"))
+
+	_, err := s.usecase.RequestAccountDeletion(s.ctx, userID, "wrong", userpkg.DeletionContentAnonymize)
+
+	s.EqualError(err, "invalid credentials")
+}
+
+func (s *accountDeletionTestSuite) TestRequestAccountDeletion_InvalidContentMode() {
+	_, err := s.usecase.RequestAccountDeletion(s.ctx, s.user.ID.Hex(), "secret", "keep")
+
+	s.Error(err)
+}
+
+func (s *accountDeletionTestSuite) TestCancelAccountDeletion() {
+	userID := s.user.ID.Hex()
+	s.mockDeletions.On("CancelDeletion", s.ctx, userID).Return(nil)
+	s.mockUserRepo.On("FindByID", s.ctx, userID).Return(s.user, nil)
+	s.mockEmailSender.On("SendEmail", s.user.Email, "", services.TemplateDeletionCancelled, mock.Anything).Return(nil)
+
+	s.NoError(s.usecase.CancelAccountDeletion(s.ctx, userID))
+}
+
+func (s *accountDeletionTestSuite) TestCancelAccountDeletion_InProgress() {
+	s.mockDeletions.On("CancelDeletion", s.ctx, s.user.ID.Hex()).Return(userpkg.ErrDeletionInProgress)
+
+	s.ErrorIs(s.usecase.CancelAccountDeletion(s.ctx, s.user.ID.Hex()), userpkg.ErrDeletionInProgress)
+}
+
+// expectErase sets up the steps shared by both content modes
+func (s *accountDeletionTestSuite) expectErase(mode string) userpkg.AccountDeletion {
+	deletion := userpkg.AccountDeletion{UserID: s.user.ID, Email: s.user.Email, ContentMode: mode}
+	userID := s.user.ID.Hex()
+
+	s.mockDeletions.On("ClaimDueDeletion", s.ctx, mock.Anything, mock.Anything).Return(deletion, true, nil).Once()
+	s.mockDeletions.On("ClaimDueDeletion", s.ctx, mock.Anything, mock.Anything).Return(userpkg.AccountDeletion{}, false, nil).Once()
+	s.mockUserRepo.On("EnsureTombstoneUser", s.ctx).Return(s.tombstone, nil)
+
+	mentee := primitive.NewObjectID()
+	s.mockMentorship.On("GetActiveConnectionsByUser", s.ctx, userID).
+		Return([]mentorshippkg.MentorshipConnection{{ID: primitive.NewObjectID(), MentorID: s.user.ID, MenteeID: mentee}}, nil)
+	s.mockMentorship.On("SearchConnections", s.ctx, mock.Anything).Return(nil, nil)
+	s.mockMentorship.On("EndConnection", s.ctx, mock.Anything, "account deleted", (*int)(nil), "", true).Return(nil)
+	pendingReq := mentorshippkg.MentorshipRequest{ID: primitive.NewObjectID()}
+	// Canceled requests stop matching, so the second search finds none
+	s.mockMentorship.On("SearchRequests", s.ctx, mock.MatchedBy(func(f mentorshippkg.RequestFilters) bool { return f.MenteeID != nil })).
+		Return([]mentorshippkg.MentorshipRequest{pendingReq}, nil).Once()
+	s.mockMentorship.On("SearchRequests", s.ctx, mock.Anything).Return(nil, nil)
+	s.mockMentorship.On("UpdateRequestStatus", s.ctx, pendingReq.ID.Hex(), mentorshippkg.StatusCanceled).Return(nil)
+
+	s.mockMessaging.On("ReplaceParticipant", s.ctx, s.user.ID, s.tombstone.ID).Return(nil)
+	s.mockTokenRepo.On("DeleteTokensByUserID", s.ctx, userID).Return(nil)
+	s.mockAccessTokens.On("DeleteAccessTokensByUserID", s.ctx, s.user.ID).Return(nil)
+	ready := userpkg.DataExport{ID: primitive.NewObjectID(), UserID: s.user.ID, Status: userpkg.ExportReady, File: "ready.zip"}
+	pending := userpkg.DataExport{ID: primitive.NewObjectID(), UserID: s.user.ID, Status: userpkg.ExportPending}
+	s.mockExports.On("FindExportsByUserID", s.ctx, s.user.ID).Return([]userpkg.DataExport{ready, pending}, nil)
+	s.mockExportStore.On("Delete", s.ctx, "ready.zip").Return(nil)
+	s.mockExports.On("DeleteExport", s.ctx, ready.ID.Hex()).Return(nil)
+	s.mockExports.On("DeleteExport", s.ctx, pending.ID.Hex()).Return(nil)
+	s.mockEmailChanges.On("DeleteEmailChange", s.ctx, userID).Return(nil)
+	s.mockChallenges.On("DeleteChallengesByUserID", s.ctx, s.user.ID).Return(nil)
+	s.mockAttempts.On("ResetLoginAttempts", s.ctx, "account:"+userID).Return(nil)
+	s.mockVerification.On("DeleteVerification", s.ctx, s.user.Email).Return(errors.New("not found"))
+	s.mockResets.On("DeleteResetRequest", s.ctx, s.user.Email).Return(nil)
+	s.mockUserRepo.On("DeleteUser", s.ctx, userID).Return(nil)
+	s.mockDeletions.On("CompleteDeletion", s.ctx, userID).Return(nil)
+	return deletion
+}
+
+func (s *accountDeletionTestSuite) TestJob_AnonymizesContent() {
+	s.expectErase(userpkg.DeletionContentAnonymize)
+	s.mockPosts.On("ReassignPostsAuthor", s.ctx, s.user.ID, s.tombstone.ID).Return(nil)
+	s.mockComments.On("ReassignCommentsAuthor", s.ctx, s.user.ID, s.tombstone.ID).Return(nil)
+	s.mockResources.On("ReassignResourcesCreator", s.ctx, s.user.ID, s.tombstone.ID).Return(nil)
+
+	erased, err := s.job.RunOnce(s.ctx)
+
+	s.NoError(err)
+	s.Equal(1, erased)
+}
+
+func (s *accountDeletionTestSuite) TestJob_DeletesContent() {
+	s.expectErase(userpkg.DeletionContentDelete)
+	ownPost := primitive.NewObjectID()
+	otherPost := primitive.NewObjectID()
+	s.mockPosts.On("DeletePostsByAuthor", s.ctx, s.user.ID).Return([]primitive.ObjectID{ownPost}, nil)
+	s.mockComments.On("DeleteCommentsByPosts", s.ctx, []primitive.ObjectID{ownPost}).Return(nil)
+	s.mockComments.On("DeleteCommentsByAuthor", s.ctx, s.user.ID).Return(map[primitive.ObjectID]int{otherPost: 2}, nil)
+	s.mockPosts.On("UpdateCommentsCount", s.ctx, otherPost, -2).Return(nil)
+	s.mockResources.On("DeleteResourcesByCreator", s.ctx, s.user.ID).Return(nil)
+
+	erased, err := s.job.RunOnce(s.ctx)
+
+	s.NoError(err)
+	s.Equal(1, erased)
+}
+
+func (s *accountDeletionTestSuite) TestJob_EndsEveryPausedConnection() {
+	isMentee := mock.MatchedBy(func(f mentorshippkg.ConnectionFilters) bool { return f.MenteeID != nil })
+	page := make([]mentorshippkg.MentorshipConnection, 100)
+	for i := range page {
+		page[i] = mentorshippkg.MentorshipConnection{ID: primitive.NewObjectID(), MenteeID: s.user.ID}
+	}
+	last := []mentorshippkg.MentorshipConnection{{ID: primitive.NewObjectID(), MenteeID: s.user.ID}}
+	s.mockMentorship.On("SearchConnections", s.ctx, isMentee).Return(page, nil).Once()
+	s.mockMentorship.On("SearchConnections", s.ctx, isMentee).Return(last, nil).Once()
+	s.mockMentorship.On("EndConnection", s.ctx, mock.Anything, "account deleted", (*int)(nil), "", false).Return(nil)
+	s.expectErase(userpkg.DeletionContentAnonymize)
+	s.mockPosts.On("ReassignPostsAuthor", s.ctx, s.user.ID, s.tombstone.ID).Return(nil)
+	s.mockComments.On("ReassignCommentsAuthor", s.ctx, s.user.ID, s.tombstone.ID).Return(nil)
+	s.mockResources.On("ReassignResourcesCreator", s.ctx, s.user.ID, s.tombstone.ID).Return(nil)
+
+	erased, err := s.job.RunOnce(s.ctx)
+
+	s.NoError(err)
+	s.Equal(1, erased)
+	s.mockMentorship.AssertNumberOfCalls(s.T(), "EndConnection", 102)
+}
+
+func (s *accountDeletionTestSuite) TestJob_ConnectionThatWillNotEndFailsDeletion() {
+	deletion := userpkg.AccountDeletion{UserID: s.user.ID, Email: s.user.Email, ContentMode: userpkg.DeletionContentAnonymize}
+	s.mockDeletions.On("ClaimDueDeletion", s.ctx, mock.Anything, mock.Anything).Return(deletion, true, nil).Once()
+	s.mockDeletions.On("ClaimDueDeletion", s.ctx, mock.Anything, mock.Anything).Return(userpkg.AccountDeletion{}, false, nil).Once()
+	s.mockUserRepo.On("EnsureTombstoneUser", s.ctx).Return(s.tombstone, nil)
+	s.mockMentorship.On("GetActiveConnectionsByUser", s.ctx, s.user.ID.Hex()).Return(nil, nil)
+	stuck := []mentorshippkg.MentorshipConnection{{ID: primitive.NewObjectID(), MenteeID: s.user.ID}}
+	s.mockMentorship.On("SearchConnections", s.ctx, mock.Anything).Return(stuck, nil)
+	s.mockMentorship.On("EndConnection", s.ctx, stuck[0].ID.Hex(), "account deleted", (*int)(nil), "", false).Return(nil).Once()
+
+	erased, err := s.job.RunOnce(s.ctx)
+
+	s.NoError(err)
+	s.Zero(erased, "left for a later run instead of looping")
+	s.mockDeletions.AssertNotCalled(s.T(), "CompleteDeletion", mock.Anything, mock.Anything)
+}
+
+func (s *accountDeletionTestSuite) TestJob_FailureLeavesDeletionForRetry() {
+	deletion := userpkg.AccountDeletion{UserID: s.user.ID, Email: s.user.Email, ContentMode: userpkg.DeletionContentAnonymize}
+	s.mockDeletions.On("ClaimDueDeletion", s.ctx, mock.Anything, mock.Anything).Return(deletion, true, nil).Once()
+	s.mockDeletions.On("ClaimDueDeletion", s.ctx, mock.Anything, mock.Anything).Return(userpkg.AccountDeletion{}, false, nil).Once()
+	s.mockUserRepo.On("EnsureTombstoneUser", s.ctx).Return(userpkg.User{}, errors.New("db down"))
+
+	erased, err := s.job.RunOnce(s.ctx)
+
+	s.NoError(err)
+	s.Zero(erased)
+	s.mockDeletions.AssertNotCalled(s.T(), "CompleteDeletion", mock.Anything, mock.Anything)
+	s.mockUserRepo.AssertNotCalled(s.T(), "DeleteUser", mock.Anything, mock.Anything)
+}
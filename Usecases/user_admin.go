@@ -0,0 +1,217 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	auditpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/audit"
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultUserPageSize = 20
+	maxUserPageSize     = 100
+)
+
+// WithAuditLogger records role changes, unlocks and admin console actions
+func (uu *UserUsecase) WithAuditLogger(logger auditpkg.IAuditLogger) *UserUsecase {
+	uu.audit = logger
+	return uu
+}
+
+// ListUsers returns one page of users matching filter, newest signups first
+func (uu *UserUsecase) ListUsers(ctx context.Context, filter userpkg.UserFilter) (userpkg.UserListResponse, error) {
+	if filter.Role != "" && !userpkg.IsValidRole(filter.Role) {
+		return userpkg.UserListResponse{}, errors.New("unknown role")
+	}
+	if filter.SignedUpFrom != nil && filter.SignedUpTo != nil && !filter.SignedUpFrom.Before(*filter.SignedUpTo) {
+		return userpkg.UserListResponse{}, errors.New("signup range is empty")
+	}
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 {
+		filter.PageSize = defaultUserPageSize
+	}
+	if filter.PageSize > maxUserPageSize {
+		filter.PageSize = maxUserPageSize
+	}
+	filter.Query = strings.TrimSpace(filter.Query)
+
+	users, total, err := uu.userRepo.ListUsers(ctx, filter)
+	if err != nil {
+		return userpkg.UserListResponse{}, err
+	}
+
+	views := make([]userpkg.AdminUserView, 0, len(users))
+	for _, user := range users {
+		views = append(views, adminUserView(user))
+	}
+	return userpkg.UserListResponse{
+		Users:      views,
+		Total:      total,
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		TotalPages: int((total + int64(filter.PageSize) - 1) / int64(filter.PageSize)),
+	}, nil
+}
+
+// GetUserForAdmin returns the full record of one user
+func (uu *UserUsecase) GetUserForAdmin(ctx context.Context, userID string) (userpkg.AdminUserView, error) {
+	user, err := uu.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return userpkg.AdminUserView{}, err
+	}
+	return adminUserView(user), nil
+}
+
+// SuspendUser keeps the target out until expiresAt, or for good when it is
+// nil. Their sessions end at once.
+func (uu *UserUsecase) SuspendUser(ctx context.Context, targetUserID, actorUserID, reason string, expiresAt *time.Time) (userpkg.AdminUserView, error) {
+	if targetUserID == actorUserID {
+		return userpkg.AdminUserView{}, errors.New("cannot suspend yourself")
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return userpkg.AdminUserView{}, errors.New("reason is required")
+	}
+	now := time.Now()
+	if expiresAt != nil && !expiresAt.After(now) {
+		return userpkg.AdminUserView{}, errors.New("expiry must be in the future")
+	}
+	actorID, err := primitive.ObjectIDFromHex(actorUserID)
+	if err != nil {
+		return userpkg.AdminUserView{}, errors.New("invalid actor ID")
+	}
+	target, err := uu.userRepo.FindByID(ctx, targetUserID)
+	if err != nil {
+		return userpkg.AdminUserView{}, err
+	}
+
+	suspension := &userpkg.Suspension{Reason: reason, SuspendedBy: actorID, SuspendedAt: now, ExpiresAt: expiresAt}
+	if err := uu.userRepo.UpdateSuspension(ctx, targetUserID, suspension); err != nil {
+		return userpkg.AdminUserView{}, err
+	}
+	if err := uu.endAllSessions(ctx, targetUserID); err != nil {
+		return userpkg.AdminUserView{}, err
+	}
+	log.Printf("security: user suspended user=%s by=%s until=%v", targetUserID, actorUserID, expiresAt)
+	recordAudit(ctx, uu.audit, auditpkg.Event{
+		ActorID:    actorUserID,
+		Action:     auditpkg.ActionUserSuspend,
+		TargetType: auditpkg.TargetUser,
+		TargetID:   targetUserID,
+		Before:     target.Suspension,
+		After:      suspension,
+	})
+	target.Suspension = suspension
+
+	_ = uu.emailSender.SendEmail(target.Email, target.Locale, services.TemplateAccountSuspended, services.EmailData{
+		"Name":   target.Username,
+		"Reason": reason,
+		"Until":  expiresAt,
+	})
+	return adminUserView(target), nil
+}
+
+// LiftSuspension lets a suspended or banned user back in
+func (uu *UserUsecase) LiftSuspension(ctx context.Context, targetUserID, actorUserID string) error {
+	target, err := uu.userRepo.FindByID(ctx, targetUserID)
+	if err != nil {
+		return err
+	}
+	if target.Suspension == nil {
+		return errors.New("user is not suspended")
+	}
+	if err := uu.userRepo.UpdateSuspension(ctx, targetUserID, nil); err != nil {
+		return err
+	}
+	log.Printf("security: suspension lifted user=%s by=%s", targetUserID, actorUserID)
+	recordAudit(ctx, uu.audit, auditpkg.Event{
+		ActorID:    actorUserID,
+		Action:     auditpkg.ActionUserUnsuspend,
+		TargetType: auditpkg.TargetUser,
+		TargetID:   targetUserID,
+		Before:     target.Suspension,
+	})
+	return nil
+}
+
+// ForceReverification marks the user's email unverified, signs them out and
+// sends a fresh verification code. They cannot log in until they use it.
+func (uu *UserUsecase) ForceReverification(ctx context.Context, targetUserID, actorUserID string) error {
+	target, err := uu.userRepo.FindByID(ctx, targetUserID)
+	if err != nil {
+		return err
+	}
+	if err := uu.userRepo.UpdateIsVerifiedByEmail(ctx, target.Email, false); err != nil {
+		return err
+	}
+	if err := uu.endAllSessions(ctx, targetUserID); err != nil {
+		return err
+	}
+	log.Printf("security: re-verification forced user=%s by=%s", targetUserID, actorUserID)
+	recordAudit(ctx, uu.audit, auditpkg.Event{
+		ActorID:    actorUserID,
+		Action:     auditpkg.ActionUserReverify,
+		TargetType: auditpkg.TargetUser,
+		TargetID:   targetUserID,
+		Before:     map[string]bool{"isVerified": target.IsVerified},
+		After:      map[string]bool{"isVerified": false},
+	})
+	return uu.SendVerificationOTP(ctx, target.Email, "")
+}
+
+// ResetUserMFA turns off two-factor authentication for a user who lost their
+// authenticator and recovery codes. They can enroll again after logging in.
+func (uu *UserUsecase) ResetUserMFA(ctx context.Context, targetUserID, actorUserID string) error {
+	if targetUserID == actorUserID {
+		return errors.New("cannot reset your own two-factor authentication")
+	}
+	target, err := uu.userRepo.FindByID(ctx, targetUserID)
+	if err != nil {
+		return err
+	}
+	if !target.MFA.Enabled && target.MFA.PendingSecret == "" {
+		return errors.New("mfa is not enabled")
+	}
+	if err := uu.userRepo.UpdateMFA(ctx, targetUserID, userpkg.MFASettings{}); err != nil {
+		return errors.New("failed to reset mfa")
+	}
+	log.Printf("security: mfa reset user=%s by=%s", targetUserID, actorUserID)
+	recordAudit(ctx, uu.audit, auditpkg.Event{
+		ActorID:    actorUserID,
+		Action:     auditpkg.ActionUserMFAReset,
+		TargetType: auditpkg.TargetUser,
+		TargetID:   targetUserID,
+		Before:     map[string]bool{"mfaEnabled": target.MFA.Enabled},
+		After:      map[string]bool{"mfaEnabled": false},
+	})
+
+	_ = uu.emailSender.SendEmail(target.Email, target.Locale, services.TemplateMFAReset, services.EmailData{
+		"Name": target.Username,
+	})
+	return nil
+}
+
+// endAllSessions drops every refresh token and personal access token, and
+// revokes outstanding access tokens
+func (uu *UserUsecase) endAllSessions(ctx context.Context, userID string) error {
+	if err := uu.tokenRepo.DeleteTokensByUserID(ctx, userID); err != nil {
+		return err
+	}
+	if err := uu.deleteAllAccessTokens(ctx, userID); err != nil {
+		return err
+	}
+	return uu.revokeAccessTokens(ctx, userID)
+}
+
+func adminUserView(user userpkg.User) userpkg.AdminUserView {
+	user.Password = ""
+	return userpkg.AdminUserView{User: user, SignedUpAt: user.ID.Timestamp()}
+}
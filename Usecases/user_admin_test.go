@@ -0,0 +1,176 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	utils "github.com/Amaankaa/Blog-Starter-Project/Domain/utils"
+	usecases "github.com/Amaankaa/Blog-Starter-Project/Usecases"
+	"github.com/Amaankaa/Blog-Starter-Project/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type adminConsoleTestSuite struct {
+	suite.Suite
+	ctx              context.Context
+	mockUserRepo     *mocks.IUserRepository
+	mockPasswordSvc  *mocks.IPasswordService
+	mockTokenRepo    *mocks.ITokenRepository
+	mockEmailSender  *mocks.IEmailSender
+	mockVerification *mocks.IVerificationRepository
+	mockRevocations  *mocks.IRevocationStore
+	usecase          *usecases.UserUsecase
+	admin            userpkg.User
+	target           userpkg.User
+}
+
+func TestAdminConsoleTestSuite(t *testing.T) {
+	suite.Run(t, new(adminConsoleTestSuite))
+}
+
+func (s *adminConsoleTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockUserRepo = new(mocks.IUserRepository)
+	s.mockPasswordSvc = new(mocks.IPasswordService)
+	s.mockTokenRepo = new(mocks.ITokenRepository)
+	s.mockEmailSender = new(mocks.IEmailSender)
+	s.mockVerification = new(mocks.IVerificationRepository)
+	s.mockRevocations = new(mocks.IRevocationStore)
+
+	s.usecase = usecases.NewUserUsecase(
+		s.mockUserRepo,
+		s.mockPasswordSvc,
+		s.mockTokenRepo,
+		new(mocks.IJWTService),
+		new(mocks.IEmailVerifier),
+		s.mockEmailSender,
+		new(mocks.IPasswordResetRepository),
+		s.mockVerification,
+		new(mocks.ICloudinaryService),
+	).WithRevocationStore(s.mockRevocations).
+		WithCodeKey(testCodeKey)
+
+	s.admin = userpkg.User{ID: primitive.NewObjectID(), Role: userpkg.RoleAdmin}
+	s.target = userpkg.User{ID: primitive.NewObjectID(), Email: "bob@example.com", Password: "hashed", IsVerified: true}
+}
+
+func (s *adminConsoleTestSuite) TearDownTest() {
+	s.mockUserRepo.AssertExpectations(s.T())
+	s.mockPasswordSvc.AssertExpectations(s.T())
+	s.mockTokenRepo.AssertExpectations(s.T())
+	s.mockEmailSender.AssertExpectations(s.T())
+	s.mockVerification.AssertExpectations(s.T())
+	s.mockRevocations.AssertExpectations(s.T())
+}
+
+func (s *adminConsoleTestSuite) expectSessionsEnded() {
+	targetID := s.target.ID.Hex()
+	s.mockTokenRepo.On("DeleteTokensByUserID", s.ctx, targetID).Return(nil)
+	s.mockRevocations.On("RevokeUserTokens", s.ctx, targetID, mock.AnythingOfType("time.Time")).Return(nil)
+}
+
+func (s *adminConsoleTestSuite) TestListUsers_NormalizesPaging() {
+	s.mockUserRepo.On("ListUsers", s.ctx, mock.MatchedBy(func(f userpkg.UserFilter) bool {
+		return f.Page == 1 && f.PageSize == 100 && f.Query == "bob"
+	})).Return([]userpkg.User{s.target}, int64(101), nil)
+
+	res, err := s.usecase.ListUsers(s.ctx, userpkg.UserFilter{Query: " bob ", PageSize: 500})
+
+	s.NoError(err)
+	s.Equal(2, res.TotalPages)
+	s.Require().Len(res.Users, 1)
+	s.Empty(res.Users[0].Password)
+	s.Equal(s.target.ID.Timestamp(), res.Users[0].SignedUpAt)
+}
+
+func (s *adminConsoleTestSuite) TestListUsers_UnknownRole() {
+	_, err := s.usecase.ListUsers(s.ctx, userpkg.UserFilter{Role: "superuser"})
+
+	s.EqualError(err, "unknown role")
+}
+
+func (s *adminConsoleTestSuite) TestSuspendUser_EndsSessions() {
+	targetID := s.target.ID.Hex()
+	until := time.Now().Add(24 * time.Hour)
+	s.mockUserRepo.On("FindByID", s.ctx, targetID).Return(s.target, nil)
+	s.mockUserRepo.On("UpdateSuspension", s.ctx, targetID, mock.MatchedBy(func(sus *userpkg.Suspension) bool {
+		return sus.Reason == "spam" && sus.SuspendedBy == s.admin.ID && sus.ExpiresAt.Equal(until)
+	})).Return(nil)
+	s.expectSessionsEnded()
+	s.mockEmailSender.On("SendEmail", s.target.Email, "", services.TemplateAccountSuspended, mock.Anything).Return(nil)
+
+	view, err := s.usecase.SuspendUser(s.ctx, targetID, s.admin.ID.Hex(), " spam ", &until)
+
+	s.NoError(err)
+	s.Require().NotNil(view.Suspension)
+	s.Error(view.SuspensionError(time.Now()))
+}
+
+func (s *adminConsoleTestSuite) TestSuspendUser_Rejects() {
+	past := time.Now().Add(-time.Minute)
+
+	_, err := s.usecase.SuspendUser(s.ctx, s.admin.ID.Hex(), s.admin.ID.Hex(), "spam", nil)
+	s.EqualError(err, "cannot suspend yourself")
+	_, err = s.usecase.SuspendUser(s.ctx, s.target.ID.Hex(), s.admin.ID.Hex(), "  ", nil)
+	s.EqualError(err, "reason is required")
+	_, err = s.usecase.SuspendUser(s.ctx, s.target.ID.Hex(), s.admin.ID.Hex(), "spam", &past)
+	s.EqualError(err, "expiry must be in the future")
+}
+
+func (s *adminConsoleTestSuite) TestLiftSuspension() {
+	s.target.Suspension = &userpkg.Suspension{Reason: "spam"}
+	s.mockUserRepo.On("FindByID", s.ctx, s.target.ID.Hex()).Return(s.target, nil)
+	s.mockUserRepo.On("UpdateSuspension", s.ctx, s.target.ID.Hex(), (*userpkg.Suspension)(nil)).Return(nil)
+
+	s.NoError(s.usecase.LiftSuspension(s.ctx, s.target.ID.Hex(), s.admin.ID.Hex()))
+}
+
+func (s *adminConsoleTestSuite) TestForceReverification_SendsCode() {
+	s.mockUserRepo.On("FindByID", s.ctx, s.target.ID.Hex()).Return(s.target, nil)
+	s.mockUserRepo.On("UpdateIsVerifiedByEmail", s.ctx, s.target.Email, false).Return(nil)
+	s.expectSessionsEnded()
+	s.mockUserRepo.On("FindByEmail", s.ctx, s.target.Email).Return(s.target, nil)
+	var code string
+	s.mockEmailSender.On("SendEmail", s.target.Email, "", services.TemplateVerification, mock.Anything).
+		Run(func(args mock.Arguments) { code = args.Get(3).(services.EmailData)["Code"].(string) }).Return(nil)
+	var stored userpkg.Verification
+	s.mockVerification.On("StoreVerification", s.ctx, mock.MatchedBy(func(v userpkg.Verification) bool {
+		return v.Email == s.target.Email
+	})).Run(func(args mock.Arguments) { stored = args.Get(1).(userpkg.Verification) }).Return(nil)
+
+	s.NoError(s.usecase.ForceReverification(s.ctx, s.target.ID.Hex(), s.admin.ID.Hex()))
+	s.Equal(utils.HashCode(testCodeKey, code), stored.OTP)
+}
+
+func (s *adminConsoleTestSuite) TestResetUserMFA() {
+	s.target.MFA = userpkg.MFASettings{Enabled: true, Secret: "SECRET"}
+	s.mockUserRepo.On("FindByID", s.ctx, s.target.ID.Hex()).Return(s.target, nil)
+	s.mockUserRepo.On("UpdateMFA", s.ctx, s.target.ID.Hex(), userpkg.MFASettings{}).Return(nil)
+	s.mockEmailSender.On("SendEmail", s.target.Email, "", services.TemplateMFAReset, mock.Anything).Return(nil)
+
+	s.NoError(s.usecase.ResetUserMFA(s.ctx, s.target.ID.Hex(), s.admin.ID.Hex()))
+}
+
+func (s *adminConsoleTestSuite) TestResetUserMFA_NotEnabled() {
+	s.mockUserRepo.On("FindByID", s.ctx, s.target.ID.Hex()).Return(s.target, nil)
+
+	s.EqualError(s.usecase.ResetUserMFA(s.ctx, s.target.ID.Hex(), s.admin.ID.Hex()), "mfa is not enabled")
+}
+
+func (s *adminConsoleTestSuite) TestLoginUser_RejectsSuspended() {
+	s.target.Username = "bob"
+	s.target.Suspension = &userpkg.Suspension{Reason: "spam", SuspendedAt: time.Now()}
+	s.mockUserRepo.On("GetUserByLogin", s.ctx, "bob").Return(s.target, nil)
+	s.mockPasswordSvc.On("ComparePassword", "hashed", "secret").Return(nil)
+
+	_, err := s.usecase.LoginUser(s.ctx, "bob", "secret", userpkg.DeviceInfo{})
+
+	var suspended *userpkg.AccountSuspendedError
+	s.ErrorAs(err, &suspended)
+	s.EqualError(err, "account banned: spam")
+}
@@ -0,0 +1,115 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"strconv"
+	"time"
+
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+)
+
+// dataExportLinkTTL is how long a download link stays valid. Polling the
+// export again issues a fresh one.
+const dataExportLinkTTL = time.Hour
+
+// WithDataExport enables data export requests. Archives are built by a
+// DataExportJob and served from store through links signed by signer.
+func (uu *UserUsecase) WithDataExport(repo userpkg.IDataExportRepository, store userpkg.IExportStore, signer userpkg.IURLSigner) *UserUsecase {
+	uu.dataExports = repo
+	uu.exportStore = store
+	uu.urlSigner = signer
+	return uu
+}
+
+func dataExportResource(exportID string) string { return "export:" + exportID }
+
+// RequestDataExport queues an archive of the user's data. A request that is
+// still waiting or being built is returned instead of queueing another.
+func (uu *UserUsecase) RequestDataExport(ctx context.Context, userID string) (userpkg.DataExport, error) {
+	if uu.dataExports == nil {
+		return userpkg.DataExport{}, errors.New("data export is not enabled")
+	}
+	user, err := uu.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return userpkg.DataExport{}, errors.New("user not found")
+	}
+
+	latest, err := uu.dataExports.GetLatestExport(ctx, userID)
+	if err == nil && (latest.Status == userpkg.ExportPending || latest.Status == userpkg.ExportRunning) {
+		return latest, nil
+	}
+	if err != nil && !errors.Is(err, userpkg.ErrExportNotFound) {
+		return userpkg.DataExport{}, errors.New("failed to check existing exports")
+	}
+
+	export, err := uu.dataExports.CreateExport(ctx, userpkg.DataExport{
+		UserID:      user.ID,
+		Status:      userpkg.ExportPending,
+		RequestedAt: time.Now(),
+	})
+	if err != nil {
+		return userpkg.DataExport{}, errors.New("failed to queue data export")
+	}
+	log.Printf("security: data export requested user=%s export=%s", userID, export.ID.Hex())
+	return export, nil
+}
+
+// GetDataExport reports an export's progress. Once it is ready the result
+// carries a signed download link.
+func (uu *UserUsecase) GetDataExport(ctx context.Context, userID, exportID string) (userpkg.DataExport, error) {
+	if uu.dataExports == nil {
+		return userpkg.DataExport{}, userpkg.ErrExportNotFound
+	}
+	export, err := uu.dataExports.GetExport(ctx, exportID)
+	if err != nil {
+		return userpkg.DataExport{}, err
+	}
+	if export.UserID.Hex() != userID {
+		return userpkg.DataExport{}, userpkg.ErrExportNotFound
+	}
+
+	now := time.Now()
+	if export.Status == userpkg.ExportReady && export.ExpiresAt != nil && now.Before(*export.ExpiresAt) {
+		linkExpires := now.Add(dataExportLinkTTL).Truncate(time.Second)
+		if export.ExpiresAt.Before(linkExpires) {
+			linkExpires = export.ExpiresAt.Truncate(time.Second)
+		}
+		query := url.Values{}
+		query.Set("expires", strconv.FormatInt(linkExpires.Unix(), 10))
+		query.Set("signature", uu.urlSigner.Sign(dataExportResource(exportID), linkExpires))
+		export.DownloadURL = fmt.Sprintf("/account/export/%s/download?%s", exportID, query.Encode())
+		export.DownloadExpiresAt = &linkExpires
+	}
+	return export, nil
+}
+
+// OpenDataExport checks a signed download link and opens the archive. The
+// caller must close the reader.
+func (uu *UserUsecase) OpenDataExport(ctx context.Context, exportID string, expiresAt time.Time, signature string) (io.ReadCloser, userpkg.DataExport, error) {
+	if uu.dataExports == nil {
+		return nil, userpkg.DataExport{}, userpkg.ErrExportNotFound
+	}
+	if !uu.urlSigner.Verify(dataExportResource(exportID), expiresAt, signature) {
+		return nil, userpkg.DataExport{}, userpkg.ErrInvalidLink
+	}
+
+	export, err := uu.dataExports.GetExport(ctx, exportID)
+	if err != nil {
+		return nil, userpkg.DataExport{}, err
+	}
+	if export.Status != userpkg.ExportReady || export.ExpiresAt == nil || !time.Now().Before(*export.ExpiresAt) {
+		return nil, userpkg.DataExport{}, userpkg.ErrExportNotFound
+	}
+
+	archive, err := uu.exportStore.Open(ctx, export.File)
+	if err != nil {
+		return nil, userpkg.DataExport{}, errors.New("failed to open data export")
+	}
+	log.Printf("security: data export downloaded user=%s export=%s", export.UserID.Hex(), exportID)
+	return archive, export, nil
+}
@@ -0,0 +1,322 @@
+package usecases_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	mentorshippkg "github.com/Amaankaa/Blog-Starter-Project/Domain/mentorship"
+	msgpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/messaging"
+	postpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/post"
+	resourcepkg "github.com/Amaankaa/Blog-Starter-Project/Domain/resource"
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	usecases "github.com/Amaankaa/Blog-Starter-Project/Usecases"
+	"github.com/Amaankaa/Blog-Starter-Project/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type dataExportTestSuite struct {
+	suite.Suite
+	ctx             context.Context
+	mockUserRepo    *mocks.IUserRepository
+	mockTokenRepo   *mocks.ITokenRepository
+	mockEmailSender *mocks.IEmailSender
+	mockExports     *mocks.IDataExportRepository
+	mockStore       *mocks.IExportStore
+	mockSigner      *mocks.IURLSigner
+	mockPosts       *mocks.PostRepository
+	mockComments    *mocks.ICommentRepository
+	mockResources   *mocks.ResourceRepository
+	mockMentorship  *mocks.IMentorshipRepository
+	mockMessaging   *mocks.IMessagingRepository
+	usecase         *usecases.UserUsecase
+	job             *usecases.DataExportJob
+	user            userpkg.User
+}
+
+func TestDataExportTestSuite(t *testing.T) {
+	suite.Run(t, new(dataExportTestSuite))
+}
+
+func (s *dataExportTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockUserRepo = new(mocks.IUserRepository)
+	s.mockTokenRepo = new(mocks.ITokenRepository)
+	s.mockEmailSender = new(mocks.IEmailSender)
+	s.mockExports = new(mocks.IDataExportRepository)
+	s.mockStore = new(mocks.IExportStore)
+	s.mockSigner = new(mocks.IURLSigner)
+	s.mockPosts = new(mocks.PostRepository)
+	s.mockComments = new(mocks.ICommentRepository)
+	s.mockResources = new(mocks.ResourceRepository)
+	s.mockMentorship = new(mocks.IMentorshipRepository)
+	s.mockMessaging = new(mocks.IMessagingRepository)
+
+	s.usecase = usecases.NewUserUsecase(
+		s.mockUserRepo,
+		new(mocks.IPasswordService),
+		s.mockTokenRepo,
+		new(mocks.IJWTService),
+		new(mocks.IEmailVerifier),
+		s.mockEmailSender,
+		new(mocks.IPasswordResetRepository),
+		new(mocks.IVerificationRepository),
+		new(mocks.ICloudinaryService),
+	).WithDataExport(s.mockExports, s.mockStore, s.mockSigner)
+
+	s.job = usecases.NewDataExportJob(
+		s.mockExports,
+		s.mockStore,
+		s.mockUserRepo,
+		s.mockTokenRepo,
+		s.mockPosts,
+		s.mockComments,
+		s.mockResources,
+		s.mockMentorship,
+		s.mockMessaging,
+		s.mockEmailSender,
+		24*time.Hour,
+	)
+
+	s.user = userpkg.User{ID: primitive.NewObjectID(), Email: "bob@example.com", Password: "hashed", DisplayName: "bob"}
+}
+
+func (s *dataExportTestSuite) TearDownTest() {
+	s.mockUserRepo.AssertExpectations(s.T())
+	s.mockTokenRepo.AssertExpectations(s.T())
+	s.mockEmailSender.AssertExpectations(s.T())
+	s.mockExports.AssertExpectations(s.T())
+	s.mockStore.AssertExpectations(s.T())
+	s.mockSigner.AssertExpectations(s.T())
+	s.mockPosts.AssertExpectations(s.T())
+	s.mockComments.AssertExpectations(s.T())
+	s.mockResources.AssertExpectations(s.T())
+	s.mockMentorship.AssertExpectations(s.T())
+	s.mockMessaging.AssertExpectations(s.T())
+}
+
+func (s *dataExportTestSuite) TestRequestDataExport_Queues() {
+	userID := s.user.ID.Hex()
+	s.mockUserRepo.On("FindByID", s.ctx, userID).Return(s.user, nil)
+	s.mockExports.On("GetLatestExport", s.ctx, userID).Return(userpkg.DataExport{Status: userpkg.ExportReady}, nil)
+	s.mockExports.On("CreateExport", s.ctx, mock.MatchedBy(func(e userpkg.DataExport) bool {
+		return e.UserID == s.user.ID && e.Status == userpkg.ExportPending
+	})).Return(userpkg.DataExport{ID: primitive.NewObjectID(), Status: userpkg.ExportPending}, nil)
+
+	export, err := s.usecase.RequestDataExport(s.ctx, userID)
+
+	s.NoError(err)
+	s.Equal(userpkg.ExportPending, export.Status)
+}
+
+func (s *dataExportTestSuite) TestRequestDataExport_ReusesRunningExport() {
+	userID := s.user.ID.Hex()
+	running := userpkg.DataExport{ID: primitive.NewObjectID(), UserID: s.user.ID, Status: userpkg.ExportRunning}
+	s.mockUserRepo.On("FindByID", s.ctx, userID).Return(s.user, nil)
+	s.mockExports.On("GetLatestExport", s.ctx, userID).Return(running, nil)
+
+	export, err := s.usecase.RequestDataExport(s.ctx, userID)
+
+	s.NoError(err)
+	s.Equal(running.ID, export.ID)
+	s.mockExports.AssertNotCalled(s.T(), "CreateExport", mock.Anything, mock.Anything)
+}
+
+func (s *dataExportTestSuite) TestGetDataExport_ReadyHasSignedLink() {
+	expiresAt := time.Now().Add(24 * time.Hour)
+	export := userpkg.DataExport{ID: primitive.NewObjectID(), UserID: s.user.ID, Status: userpkg.ExportReady, ExpiresAt: &expiresAt}
+	s.mockExports.On("GetExport", s.ctx, export.ID.Hex()).Return(export, nil)
+	s.mockSigner.On("Sign", "export:"+export.ID.Hex(), mock.AnythingOfType("time.Time")).Return("sig")
+
+	found, err := s.usecase.GetDataExport(s.ctx, s.user.ID.Hex(), export.ID.Hex())
+
+	s.Require().NoError(err)
+	s.Require().NotNil(found.DownloadExpiresAt)
+	s.WithinDuration(time.Now().Add(time.Hour), *found.DownloadExpiresAt, 2*time.Second)
+	link, err := url.Parse(found.DownloadURL)
+	s.Require().NoError(err)
+	s.Equal("/account/export/"+export.ID.Hex()+"/download", link.Path)
+	s.Equal("sig", link.Query().Get("signature"))
+	s.Equal(strconv.FormatInt(found.DownloadExpiresAt.Unix(), 10), link.Query().Get("expires"))
+}
+
+func (s *dataExportTestSuite) TestGetDataExport_OtherUsersExportNotFound() {
+	export := userpkg.DataExport{ID: primitive.NewObjectID(), UserID: primitive.NewObjectID(), Status: userpkg.ExportPending}
+	s.mockExports.On("GetExport", s.ctx, export.ID.Hex()).Return(export, nil)
+
+	_, err := s.usecase.GetDataExport(s.ctx, s.user.ID.Hex(), export.ID.Hex())
+
+	s.ErrorIs(err, userpkg.ErrExportNotFound)
+}
+
+func (s *dataExportTestSuite) TestOpenDataExport_BadSignature() {
+	exportID := primitive.NewObjectID().Hex()
+	expires := time.Now().Add(time.Hour)
+	s.mockSigner.On("Verify", "export:"+exportID, expires, "forged").Return(false)
+
+	_, _, err := s.usecase.OpenDataExport(s.ctx, exportID, expires, "forged")
+
+	s.ErrorIs(err, userpkg.ErrInvalidLink)
+	s.mockExports.AssertNotCalled(s.T(), "GetExport", mock.Anything, mock.Anything)
+}
+
+func (s *dataExportTestSuite) TestOpenDataExport_OpensArchive() {
+	expiresAt := time.Now().Add(24 * time.Hour)
+	export := userpkg.DataExport{ID: primitive.NewObjectID(), UserID: s.user.ID, Status: userpkg.ExportReady, File: "a.zip", ExpiresAt: &expiresAt}
+	linkExpires := time.Now().Add(time.Hour)
+	s.mockSigner.On("Verify", "export:"+export.ID.Hex(), linkExpires, "sig").Return(true)
+	s.mockExports.On("GetExport", s.ctx, export.ID.Hex()).Return(export, nil)
+	s.mockStore.On("Open", s.ctx, "a.zip").Return(io.NopCloser(bytes.NewReader([]byte("zip"))), nil)
+
+	archive, found, err := s.usecase.OpenDataExport(s.ctx, export.ID.Hex(), linkExpires, "sig")
+
+	s.Require().NoError(err)
+	s.Equal(export.ID, found.ID)
+	data, _ := io.ReadAll(archive)
+	s.Equal("zip", string(data))
+}
+
+func (s *dataExportTestSuite) TestJob_BuildsArchiveSafeToShare() {
+	export := userpkg.DataExport{ID: primitive.NewObjectID(), UserID: s.user.ID, Status: userpkg.ExportRunning}
+	userID := s.user.ID.Hex()
+	friend := userpkg.User{ID: primitive.NewObjectID(), Email: "friend@example.com", Fullname: "Real Name", DisplayName: "pal"}
+	hidden := userpkg.User{ID: primitive.NewObjectID(), Email: "hidden@example.com", DisplayName: "ghost", IsAnonymous: true}
+
+	s.mockExports.On("FindExpiredExports", s.ctx, mock.Anything).Return(nil, nil)
+	s.mockExports.On("ClaimPendingExport", s.ctx, mock.Anything, mock.Anything).Return(export, true, nil).Once()
+	s.mockExports.On("ClaimPendingExport", s.ctx, mock.Anything, mock.Anything).Return(userpkg.DataExport{}, false, nil).Once()
+	s.mockUserRepo.On("FindByID", s.ctx, userID).Return(s.user, nil)
+	s.mockUserRepo.On("FindByID", s.ctx, friend.ID.Hex()).Return(friend, nil)
+	s.mockUserRepo.On("FindByID", s.ctx, hidden.ID.Hex()).Return(hidden, nil)
+
+	s.mockTokenRepo.On("FindActiveSessionsByUserID", s.ctx, s.user.ID).Return(nil, nil)
+	s.mockPosts.On("GetAllPostsByAuthor", s.ctx, s.user.ID).
+		Return([]postpkg.Post{{ID: primitive.NewObjectID(), AuthorID: s.user.ID, Title: "mine", LikedBy: []primitive.ObjectID{friend.ID}}}, nil)
+	s.mockComments.On("GetCommentsByAuthor", s.ctx, s.user.ID).Return(nil, nil)
+	s.mockResources.On("GetAllResourcesByCreator", s.ctx, s.user.ID).Return(nil, nil)
+	s.mockPosts.On("GetPostsLikedByUser", s.ctx, s.user.ID).Return([]postpkg.Post{
+		{ID: primitive.NewObjectID(), AuthorID: friend.ID, Title: "open"},
+		{ID: primitive.NewObjectID(), AuthorID: friend.ID, Title: "secret", IsAnonymous: true},
+	}, nil)
+	s.mockResources.On("GetUserLikedResources", s.ctx, s.user.ID, mock.Anything).Return(nil, int64(0), nil)
+	s.mockResources.On("GetUserBookmarkedResources", s.ctx, s.user.ID, mock.Anything).
+		Return([]resourcepkg.Resource{{ID: primitive.NewObjectID(), CreatorID: hidden.ID, Title: "guide"}}, int64(1), nil)
+	s.mockMentorship.On("SearchRequests", s.ctx, mock.Anything).Return(nil, nil)
+	s.mockMentorship.On("SearchConnections", s.ctx, mock.MatchedBy(func(f mentorshippkg.ConnectionFilters) bool { return f.MenteeID != nil })).
+		Return([]mentorshippkg.MentorshipConnection{{ID: primitive.NewObjectID(), MentorID: friend.ID, MenteeID: s.user.ID, MentorFeedback: "their words"}}, nil)
+	s.mockMentorship.On("SearchConnections", s.ctx, mock.Anything).Return(nil, nil)
+	conv := msgpkg.Conversation{ID: primitive.NewObjectID(), ParticipantIDs: []primitive.ObjectID{s.user.ID, hidden.ID}}
+	s.mockMessaging.On("GetUserConversations", s.ctx, s.user.ID, 100, 0).Return([]msgpkg.Conversation{conv}, nil)
+	s.mockMessaging.On("GetMessages", s.ctx, conv.ID, 100, 0).Return([]msgpkg.Message{{SenderID: hidden.ID, Content: "hi"}}, nil)
+
+	var archive []byte
+	s.mockStore.On("Save", s.ctx, export.ID.Hex()+".zip", mock.Anything).Run(func(args mock.Arguments) {
+		archive = args.Get(2).([]byte)
+	}).Return(nil)
+	s.mockExports.On("MarkExportReady", s.ctx, export.ID.Hex(), export.ID.Hex()+".zip", mock.AnythingOfType("int64"), mock.Anything, mock.Anything).Return(nil)
+	s.mockEmailSender.On("SendEmail", s.user.Email, "", services.TemplateDataExportReady, mock.Anything).Return(nil)
+
+	built, err := s.job.RunOnce(s.ctx)
+
+	s.Require().NoError(err)
+	s.Equal(1, built)
+
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	s.Require().NoError(err)
+	var all bytes.Buffer
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		s.Require().NoError(err)
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(data)
+		all.Write(data)
+	}
+	s.Len(files, 9)
+
+	var profile map[string]interface{}
+	s.Require().NoError(json.Unmarshal([]byte(files["profile.json"]), &profile))
+	s.Empty(profile["password"])
+	s.Equal(s.user.Email, profile["email"])
+
+	s.Contains(files["likes.json"], `"title": "open"`)
+	s.Contains(files["likes.json"], `"displayName": "pal"`)
+	s.Contains(files["bookmarks.json"], `"displayName": "Anonymous"`)
+	s.Contains(files["conversations.json"], `"displayName": "You"`)
+	s.NotContains(files["mentorship.json"], "their words")
+
+	// Nothing identifies other people beyond a display name
+	for _, leak := range []string{friend.ID.Hex(), hidden.ID.Hex(), friend.Email, hidden.Email, friend.Fullname, hidden.DisplayName} {
+		s.NotContains(all.String(), leak)
+	}
+}
+
+func (s *dataExportTestSuite) TestJob_FailureMarksExportFailed() {
+	export := userpkg.DataExport{ID: primitive.NewObjectID(), UserID: s.user.ID, Status: userpkg.ExportRunning}
+	s.mockExports.On("FindExpiredExports", s.ctx, mock.Anything).Return(nil, nil)
+	s.mockExports.On("ClaimPendingExport", s.ctx, mock.Anything, mock.Anything).Return(export, true, nil).Once()
+	s.mockExports.On("ClaimPendingExport", s.ctx, mock.Anything, mock.Anything).Return(userpkg.DataExport{}, false, nil).Once()
+	s.mockUserRepo.On("FindByID", s.ctx, s.user.ID.Hex()).Return(s.user, nil)
+	s.mockTokenRepo.On("FindActiveSessionsByUserID", s.ctx, s.user.ID).Return(nil, errors.New("db down"))
+	s.mockExports.On("MarkExportFailed", s.ctx, export.ID.Hex(), mock.AnythingOfType("string")).Return(nil)
+
+	built, err := s.job.RunOnce(s.ctx)
+
+	s.NoError(err)
+	s.Zero(built)
+	s.mockStore.AssertNotCalled(s.T(), "Save", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *dataExportTestSuite) TestJob_DropsArchiveOfErasedAccount() {
+	export := userpkg.DataExport{ID: primitive.NewObjectID(), UserID: s.user.ID, Status: userpkg.ExportRunning}
+	name := export.ID.Hex() + ".zip"
+	s.mockExports.On("FindExpiredExports", s.ctx, mock.Anything).Return(nil, nil)
+	s.mockExports.On("ClaimPendingExport", s.ctx, mock.Anything, mock.Anything).Return(export, true, nil).Once()
+	s.mockExports.On("ClaimPendingExport", s.ctx, mock.Anything, mock.Anything).Return(userpkg.DataExport{}, false, nil).Once()
+	s.mockUserRepo.On("FindByID", s.ctx, s.user.ID.Hex()).Return(s.user, nil)
+	s.mockTokenRepo.On("FindActiveSessionsByUserID", s.ctx, s.user.ID).Return(nil, nil)
+	s.mockPosts.On("GetAllPostsByAuthor", s.ctx, s.user.ID).Return(nil, nil)
+	s.mockComments.On("GetCommentsByAuthor", s.ctx, s.user.ID).Return(nil, nil)
+	s.mockResources.On("GetAllResourcesByCreator", s.ctx, s.user.ID).Return(nil, nil)
+	s.mockPosts.On("GetPostsLikedByUser", s.ctx, s.user.ID).Return(nil, nil)
+	s.mockResources.On("GetUserLikedResources", s.ctx, s.user.ID, mock.Anything).Return(nil, int64(0), nil)
+	s.mockResources.On("GetUserBookmarkedResources", s.ctx, s.user.ID, mock.Anything).Return(nil, int64(0), nil)
+	s.mockMentorship.On("SearchRequests", s.ctx, mock.Anything).Return(nil, nil)
+	s.mockMentorship.On("SearchConnections", s.ctx, mock.Anything).Return(nil, nil)
+	s.mockMessaging.On("GetUserConversations", s.ctx, s.user.ID, 100, 0).Return(nil, nil)
+	s.mockStore.On("Save", s.ctx, name, mock.Anything).Return(nil)
+	// The account was erased while the archive was being built
+	s.mockExports.On("MarkExportReady", s.ctx, export.ID.Hex(), name, mock.AnythingOfType("int64"), mock.Anything, mock.Anything).
+		Return(userpkg.ErrExportNotFound)
+	s.mockStore.On("Delete", s.ctx, name).Return(nil)
+
+	built, err := s.job.RunOnce(s.ctx)
+
+	s.NoError(err)
+	s.Zero(built)
+	s.mockEmailSender.AssertNotCalled(s.T(), "SendEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *dataExportTestSuite) TestJob_RemovesExpiredArchives() {
+	expired := userpkg.DataExport{ID: primitive.NewObjectID(), File: "old.zip"}
+	s.mockExports.On("FindExpiredExports", s.ctx, mock.Anything).Return([]userpkg.DataExport{expired}, nil)
+	s.mockStore.On("Delete", s.ctx, "old.zip").Return(nil)
+	s.mockExports.On("DeleteExport", s.ctx, expired.ID.Hex()).Return(nil)
+	s.mockExports.On("ClaimPendingExport", s.ctx, mock.Anything, mock.Anything).Return(userpkg.DataExport{}, false, nil)
+
+	built, err := s.job.RunOnce(s.ctx)
+
+	s.NoError(err)
+	s.Zero(built)
+}
@@ -0,0 +1,219 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	utils "github.com/Amaankaa/Blog-Starter-Project/Domain/utils"
+)
+
+// emailChangeTTL is how long both addresses have to confirm their codes, and
+// how long the old address can cancel
+const emailChangeTTL = 30 * time.Minute
+
+const otpPurposeEmailChange = "email-change"
+
+// WithEmailChange enables changing the account email. Cancel links in the
+// notice to the old address are signed by signer and point at publicURL.
+func (uu *UserUsecase) WithEmailChange(repo userpkg.IEmailChangeRepository, signer userpkg.IURLSigner, publicURL string) *UserUsecase {
+	uu.emailChanges = repo
+	uu.urlSigner = signer
+	uu.publicURL = strings.TrimRight(publicURL, "/")
+	return uu
+}
+
+// The request time is signed too, so a link cannot cancel a later change. It
+// is taken in milliseconds, the precision it is stored at.
+func emailChangeResource(change userpkg.EmailChange) string {
+	return "email-change:" + change.UserID.Hex() + ":" + strconv.FormatInt(change.RequestedAt.UnixMilli(), 10)
+}
+
+// RequestEmailChange starts moving the account to newEmail. A code goes to
+// the new address, and a notice with a second code and a cancel link to the
+// current one. The email only changes once ConfirmEmailChange gets both
+// codes, so a stolen session and password are not enough to take the account.
+// Accounts without a password pass a code from SendReauthCode as password.
+func (uu *UserUsecase) RequestEmailChange(ctx context.Context, userID, password, newEmail, clientIP string) (userpkg.EmailChange, error) {
+	if uu.emailChanges == nil {
+		return userpkg.EmailChange{}, errors.New("email change is not enabled")
+	}
+	newEmail = strings.TrimSpace(newEmail)
+
+	user, err := uu.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return userpkg.EmailChange{}, errors.New("user not found")
+	}
+	if err := uu.reauthenticate(ctx, user, password); err != nil {
+		return userpkg.EmailChange{}, err
+	}
+
+	if !utils.IsValidEmail(newEmail) {
+		return userpkg.EmailChange{}, errors.New("invalid email format")
+	}
+	if strings.EqualFold(newEmail, user.Email) {
+		return userpkg.EmailChange{}, errors.New("new email is the same as the current one")
+	}
+	exists, err := uu.userRepo.ExistsByEmail(ctx, newEmail)
+	if err != nil {
+		return userpkg.EmailChange{}, errors.New("failed to check email existence: " + err.Error())
+	}
+	if exists {
+		return userpkg.EmailChange{}, userpkg.ErrEmailTaken
+	}
+	isReal, err := uu.emailVerifier.IsRealEmail(newEmail)
+	if err != nil {
+		return userpkg.EmailChange{}, errors.New("failed to verify email: " + err.Error())
+	}
+	if !isReal {
+		return userpkg.EmailChange{}, errors.New("email is unreachable")
+	}
+
+	// Codes to the new address share the OTP cooldowns and daily caps
+	if _, err := uu.checkOTPSend(ctx, otpPurposeEmailChange, newEmail, clientIP, func() bool { return false }); err != nil {
+		return userpkg.EmailChange{}, err
+	}
+
+	now := time.Now()
+	oldEmailOTP := utils.GenerateOTP(6)
+	change := userpkg.EmailChange{
+		UserID:      user.ID,
+		OldEmail:    user.Email,
+		OldEmailOTP: uu.hashCode(oldEmailOTP),
+		NewEmail:    newEmail,
+		RequestedAt: now,
+		ExpiresAt:   now.Add(emailChangeTTL),
+	}
+
+	otp := utils.GenerateOTP(6)
+	hashed := uu.hashCode(otp)
+	if err := uu.verificationRepo.StoreVerification(ctx, userpkg.Verification{
+		Email:     newEmail,
+		OTP:       hashed,
+		ExpiresAt: change.ExpiresAt,
+	}); err != nil {
+		return userpkg.EmailChange{}, errors.New("failed to store verification code")
+	}
+	if err := uu.emailChanges.SaveEmailChange(ctx, change); err != nil {
+		return userpkg.EmailChange{}, errors.New("failed to start email change")
+	}
+
+	if err := uu.emailSender.SendEmail(newEmail, user.Locale, services.TemplateEmailChangeCode, services.EmailData{
+		"Name":             user.Username,
+		"Code":             otp,
+		"ExpiresInMinutes": int(emailChangeTTL / time.Minute),
+	}); err != nil {
+		return userpkg.EmailChange{}, errors.New("failed to send verification code")
+	}
+	uu.recordOTPSend(ctx, otpPurposeEmailChange, newEmail, clientIP)
+
+	query := url.Values{}
+	query.Set("user", userID)
+	query.Set("signature", uu.urlSigner.Sign(emailChangeResource(change), change.ExpiresAt))
+	cancelURL := uu.publicURL + "/account/email/cancel?" + query.Encode()
+	if err := uu.emailSender.SendEmail(user.Email, user.Locale, services.TemplateEmailChangePending, services.EmailData{
+		"Name":         user.Username,
+		"NewEmail":     newEmail,
+		"Code":         oldEmailOTP,
+		"CancelURL":    cancelURL,
+		"CancelBefore": change.ExpiresAt,
+	}); err != nil {
+		return userpkg.EmailChange{}, errors.New("failed to send approval code")
+	}
+
+	log.Printf("security: email change requested user=%s", userID)
+	return change, nil
+}
+
+// ConfirmEmailChange checks the codes sent to the new and the old address and
+// moves the account to the new one
+func (uu *UserUsecase) ConfirmEmailChange(ctx context.Context, userID, otp, oldEmailOTP string) (userpkg.User, error) {
+	if uu.emailChanges == nil {
+		return userpkg.User{}, userpkg.ErrEmailChangeNotFound
+	}
+	change, err := uu.emailChanges.GetEmailChange(ctx, userID)
+	if err != nil {
+		return userpkg.User{}, err
+	}
+
+	v, err := uu.verificationRepo.GetVerification(ctx, change.NewEmail)
+	if err != nil {
+		return userpkg.User{}, errors.New("no verification found")
+	}
+	if time.Now().After(v.ExpiresAt) {
+		_ = uu.verificationRepo.DeleteVerification(ctx, change.NewEmail)
+		return userpkg.User{}, errors.New("verification code expired")
+	}
+	if v.AttemptCount >= 5 {
+		_ = uu.verificationRepo.DeleteVerification(ctx, change.NewEmail)
+		_ = uu.emailChanges.DeleteEmailChange(ctx, userID)
+		return userpkg.User{}, errors.New("too many invalid attempts")
+	}
+	// Both codes share the attempt count, so neither can be guessed on its own
+	newOK := uu.codeMatches(v.OTP, otp)
+	oldOK := uu.codeMatches(change.OldEmailOTP, oldEmailOTP)
+	if !newOK || !oldOK {
+		_ = uu.verificationRepo.IncrementAttemptCount(ctx, change.NewEmail)
+		return userpkg.User{}, errors.New("invalid code")
+	}
+
+	// Someone may have registered the address since the change started
+	exists, err := uu.userRepo.ExistsByEmail(ctx, change.NewEmail)
+	if err != nil {
+		return userpkg.User{}, errors.New("failed to check email existence: " + err.Error())
+	}
+	if exists {
+		return userpkg.User{}, userpkg.ErrEmailTaken
+	}
+	if err := uu.userRepo.UpdateEmail(ctx, userID, change.NewEmail); err != nil {
+		return userpkg.User{}, err
+	}
+	_ = uu.emailChanges.DeleteEmailChange(ctx, userID)
+	_ = uu.verificationRepo.DeleteVerification(ctx, change.NewEmail)
+	// A reset started for the old address must not work any more
+	_ = uu.passwordResetRepo.DeleteResetRequest(ctx, change.OldEmail)
+	log.Printf("security: email changed user=%s", userID)
+
+	user, err := uu.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return userpkg.User{}, err
+	}
+	_ = uu.emailSender.SendEmail(change.OldEmail, user.Locale, services.TemplateEmailChanged, services.EmailData{
+		"Name":     user.Username,
+		"NewEmail": change.NewEmail,
+	})
+	user.Password = ""
+	return user, nil
+}
+
+// CancelEmailChange drops a pending change through the link sent to the old
+// address
+func (uu *UserUsecase) CancelEmailChange(ctx context.Context, userID, signature string) error {
+	if uu.emailChanges == nil {
+		return userpkg.ErrEmailChangeNotFound
+	}
+	change, err := uu.emailChanges.GetEmailChange(ctx, userID)
+	if errors.Is(err, userpkg.ErrEmailChangeNotFound) {
+		// Expired, already cancelled or already done: the link no longer applies
+		return userpkg.ErrInvalidLink
+	}
+	if err != nil {
+		return err
+	}
+	if !uu.urlSigner.Verify(emailChangeResource(change), change.ExpiresAt, signature) {
+		return userpkg.ErrInvalidLink
+	}
+
+	if err := uu.emailChanges.DeleteEmailChange(ctx, userID); err != nil {
+		return errors.New("failed to cancel email change")
+	}
+	_ = uu.verificationRepo.DeleteVerification(ctx, change.NewEmail)
+	log.Printf("security: email change cancelled from old address user=%s", userID)
+	return nil
+}
@@ -0,0 +1,262 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	utils "github.com/Amaankaa/Blog-Starter-Project/Domain/utils"
+	usecases "github.com/Amaankaa/Blog-Starter-Project/Usecases"
+	"github.com/Amaankaa/Blog-Starter-Project/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type emailChangeTestSuite struct {
+	suite.Suite
+	ctx               context.Context
+	mockUserRepo      *mocks.IUserRepository
+	mockPasswordSvc   *mocks.IPasswordService
+	mockEmailVerifier *mocks.IEmailVerifier
+	mockEmailSender   *mocks.IEmailSender
+	mockResetRepo     *mocks.IPasswordResetRepository
+	mockVerification  *mocks.IVerificationRepository
+	mockChanges       *mocks.IEmailChangeRepository
+	mockSigner        *mocks.IURLSigner
+	usecase           *usecases.UserUsecase
+	user              userpkg.User
+	change            userpkg.EmailChange
+}
+
+const newAddress = "bob.new@example.com"
+
+func TestEmailChangeTestSuite(t *testing.T) {
+	suite.Run(t, new(emailChangeTestSuite))
+}
+
+func (s *emailChangeTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockUserRepo = new(mocks.IUserRepository)
+	s.mockPasswordSvc = new(mocks.IPasswordService)
+	s.mockEmailVerifier = new(mocks.IEmailVerifier)
+	s.mockEmailSender = new(mocks.IEmailSender)
+	s.mockResetRepo = new(mocks.IPasswordResetRepository)
+	s.mockVerification = new(mocks.IVerificationRepository)
+	s.mockChanges = new(mocks.IEmailChangeRepository)
+	s.mockSigner = new(mocks.IURLSigner)
+
+	s.usecase = usecases.NewUserUsecase(
+		s.mockUserRepo,
+		s.mockPasswordSvc,
+		new(mocks.ITokenRepository),
+		new(mocks.IJWTService),
+		s.mockEmailVerifier,
+		s.mockEmailSender,
+		s.mockResetRepo,
+		s.mockVerification,
+		new(mocks.ICloudinaryService),
+	).WithEmailChange(s.mockChanges, s.mockSigner, "https://api.example.com/").
+		WithCodeKey(testCodeKey)
+
+	s.user = userpkg.User{ID: primitive.NewObjectID(), Email: "bob@example.com", Password: "hashed"}
+	now := time.Now()
+	s.change = userpkg.EmailChange{
+		UserID:      s.user.ID,
+		OldEmail:    s.user.Email,
+		OldEmailOTP: utils.HashCode(testCodeKey, "654321"),
+		NewEmail:    newAddress,
+		RequestedAt: now,
+		ExpiresAt:   now.Add(30 * time.Minute),
+	}
+}
+
+func (s *emailChangeTestSuite) TearDownTest() {
+	s.mockUserRepo.AssertExpectations(s.T())
+	s.mockPasswordSvc.AssertExpectations(s.T())
+	s.mockEmailVerifier.AssertExpectations(s.T())
+	s.mockEmailSender.AssertExpectations(s.T())
+	s.mockResetRepo.AssertExpectations(s.T())
+	s.mockVerification.AssertExpectations(s.T())
+	s.mockChanges.AssertExpectations(s.T())
+	s.mockSigner.AssertExpectations(s.T())
+}
+
+func (s *emailChangeTestSuite) TestRequestEmailChange_SendsCodeAndNotice() {
+	userID := s.user.ID.Hex()
+	s.mockUserRepo.On("FindByID", s.ctx, userID).Return(s.user, nil)
+	s.mockPasswordSvc.On("ComparePassword", "hashed", "secret").Return(nil)
+	s.mockUserRepo.On("ExistsByEmail", s.ctx, newAddress).Return(false, nil)
+	s.mockEmailVerifier.On("IsRealEmail", newAddress).Return(true, nil)
+	var stored userpkg.Verification
+	s.mockVerification.On("StoreVerification", s.ctx, mock.MatchedBy(func(v userpkg.Verification) bool {
+		return v.Email == newAddress
+	})).Run(func(args mock.Arguments) { stored = args.Get(1).(userpkg.Verification) }).Return(nil)
+	var saved userpkg.EmailChange
+	s.mockChanges.On("SaveEmailChange", s.ctx, mock.MatchedBy(func(c userpkg.EmailChange) bool {
+		return c.UserID == s.user.ID && c.OldEmail == s.user.Email && c.NewEmail == newAddress
+	})).Run(func(args mock.Arguments) { saved = args.Get(1).(userpkg.EmailChange) }).Return(nil)
+	var code string
+	s.mockEmailSender.On("SendEmail", newAddress, "", services.TemplateEmailChangeCode, mock.Anything).
+		Run(func(args mock.Arguments) { code = args.Get(3).(services.EmailData)["Code"].(string) }).Return(nil)
+	s.mockSigner.On("Sign", mock.MatchedBy(func(r string) bool { return strings.HasPrefix(r, "email-change:"+userID+":") }), mock.Anything).Return("sig")
+	var notice services.EmailData
+	s.mockEmailSender.On("SendEmail", s.user.Email, "", services.TemplateEmailChangePending, mock.Anything).
+		Run(func(args mock.Arguments) { notice = args.Get(3).(services.EmailData) }).Return(nil)
+
+	change, err := s.usecase.RequestEmailChange(s.ctx, userID, "secret", " "+newAddress+" ", "")
+
+	s.Require().NoError(err)
+	s.Equal(newAddress, change.NewEmail)
+	s.Equal(utils.HashCode(testCodeKey, code), stored.OTP)
+	s.Equal(newAddress, notice["NewEmail"])
+	s.Equal(utils.HashCode(testCodeKey, notice["Code"].(string)), saved.OldEmailOTP, "the old address gets a code of its own")
+	s.NotEqual(code, notice["Code"])
+	s.Equal("https://api.example.com/account/email/cancel?"+url.Values{"signature": {"sig"}, "user": {userID}}.Encode(), notice["CancelURL"])
+}
+
+func (s *emailChangeTestSuite) TestRequestEmailChange_WrongPassword() {
+	s.mockUserRepo.On("FindByID", s.ctx, s.user.ID.Hex()).Return(s.user, nil)
+	s.mockPasswordSvc.On("ComparePassword", "hashed", "wrong").Return(errors.New("mismatch"))
+
+	_, err := s.usecase.RequestEmailChange(s.ctx, s.user.ID.Hex(), "wrong", newAddress, "")
+
+	s.EqualError(err, "invalid credentials")
+}
+
+func (s *emailChangeTestSuite) TestRequestEmailChange_AddressTaken() {
+	s.mockUserRepo.On("FindByID", s.ctx, s.user.ID.Hex()).Return(s.user, nil)
+	s.mockPasswordSvc.On("ComparePassword", "hashed", "secret").Return(nil)
+	s.mockUserRepo.On("ExistsByEmail", s.ctx, newAddress).Return(true, nil)
+
+	_, err := s.usecase.RequestEmailChange(s.ctx, s.user.ID.Hex(), "secret", newAddress, "")
+
+	s.ErrorIs(err, userpkg.ErrEmailTaken)
+	s.mockEmailSender.AssertNotCalled(s.T(), "SendEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *emailChangeTestSuite) TestRequestEmailChange_Unreachable() {
+	s.mockUserRepo.On("FindByID", s.ctx, s.user.ID.Hex()).Return(s.user, nil)
+	s.mockPasswordSvc.On("ComparePassword", "hashed", "secret").Return(nil)
+	s.mockUserRepo.On("ExistsByEmail", s.ctx, newAddress).Return(false, nil)
+	s.mockEmailVerifier.On("IsRealEmail", newAddress).Return(false, nil)
+
+	_, err := s.usecase.RequestEmailChange(s.ctx, s.user.ID.Hex(), "secret", newAddress, "")
+
+	s.EqualError(err, "email is unreachable")
+}
+
+func (s *emailChangeTestSuite) TestConfirmEmailChange_SwapsEmail() {
+	userID := s.user.ID.Hex()
+	s.mockChanges.On("GetEmailChange", s.ctx, userID).Return(s.change, nil)
+	s.mockVerification.On("GetVerification", s.ctx, newAddress).
+		Return(userpkg.Verification{Email: newAddress, OTP: utils.HashCode(testCodeKey, "123456"), ExpiresAt: s.change.ExpiresAt}, nil)
+	s.mockUserRepo.On("ExistsByEmail", s.ctx, newAddress).Return(false, nil)
+	s.mockUserRepo.On("UpdateEmail", s.ctx, userID, newAddress).Return(nil)
+	s.mockChanges.On("DeleteEmailChange", s.ctx, userID).Return(nil)
+	s.mockVerification.On("DeleteVerification", s.ctx, newAddress).Return(nil)
+	s.mockResetRepo.On("DeleteResetRequest", s.ctx, s.user.Email).Return(nil)
+	s.mockEmailSender.On("SendEmail", s.user.Email, "", services.TemplateEmailChanged, mock.Anything).Return(nil)
+	updated := s.user
+	updated.Email = newAddress
+	s.mockUserRepo.On("FindByID", s.ctx, userID).Return(updated, nil)
+
+	user, err := s.usecase.ConfirmEmailChange(s.ctx, userID, "123456", "654321")
+
+	s.Require().NoError(err)
+	s.Equal(newAddress, user.Email)
+	s.Empty(user.Password)
+}
+
+func (s *emailChangeTestSuite) TestConfirmEmailChange_WrongCodeKeepsEmail() {
+	userID := s.user.ID.Hex()
+	s.mockChanges.On("GetEmailChange", s.ctx, userID).Return(s.change, nil)
+	s.mockVerification.On("GetVerification", s.ctx, newAddress).
+		Return(userpkg.Verification{Email: newAddress, OTP: utils.HashCode(testCodeKey, "123456"), ExpiresAt: s.change.ExpiresAt}, nil)
+	s.mockVerification.On("IncrementAttemptCount", s.ctx, newAddress).Return(nil)
+
+	_, err := s.usecase.ConfirmEmailChange(s.ctx, userID, "000000", "654321")
+
+	s.EqualError(err, "invalid code")
+	s.mockUserRepo.AssertNotCalled(s.T(), "UpdateEmail", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *emailChangeTestSuite) TestConfirmEmailChange_NeedsOldAddressApproval() {
+	userID := s.user.ID.Hex()
+	s.mockChanges.On("GetEmailChange", s.ctx, userID).Return(s.change, nil)
+	s.mockVerification.On("GetVerification", s.ctx, newAddress).
+		Return(userpkg.Verification{Email: newAddress, OTP: utils.HashCode(testCodeKey, "123456"), ExpiresAt: s.change.ExpiresAt}, nil)
+	s.mockVerification.On("IncrementAttemptCount", s.ctx, newAddress).Return(nil)
+
+	// Whoever controls the new address alone cannot finish the change
+	_, err := s.usecase.ConfirmEmailChange(s.ctx, userID, "123456", "000000")
+
+	s.EqualError(err, "invalid code")
+	s.mockUserRepo.AssertNotCalled(s.T(), "UpdateEmail", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *emailChangeTestSuite) TestConfirmEmailChange_AddressTakenMeanwhile() {
+	userID := s.user.ID.Hex()
+	s.mockChanges.On("GetEmailChange", s.ctx, userID).Return(s.change, nil)
+	s.mockVerification.On("GetVerification", s.ctx, newAddress).
+		Return(userpkg.Verification{Email: newAddress, OTP: utils.HashCode(testCodeKey, "123456"), ExpiresAt: s.change.ExpiresAt}, nil)
+	s.mockUserRepo.On("ExistsByEmail", s.ctx, newAddress).Return(true, nil)
+
+	_, err := s.usecase.ConfirmEmailChange(s.ctx, userID, "123456", "654321")
+
+	s.ErrorIs(err, userpkg.ErrEmailTaken)
+}
+
+func (s *emailChangeTestSuite) TestCancelEmailChange_ValidLink() {
+	userID := s.user.ID.Hex()
+	s.mockChanges.On("GetEmailChange", s.ctx, userID).Return(s.change, nil)
+	s.mockSigner.On("Verify", mock.AnythingOfType("string"), s.change.ExpiresAt, "sig").Return(true)
+	s.mockChanges.On("DeleteEmailChange", s.ctx, userID).Return(nil)
+	s.mockVerification.On("DeleteVerification", s.ctx, newAddress).Return(nil)
+
+	s.NoError(s.usecase.CancelEmailChange(s.ctx, userID, "sig"))
+}
+
+func (s *emailChangeTestSuite) TestCancelEmailChange_ForgedLink() {
+	userID := s.user.ID.Hex()
+	s.mockChanges.On("GetEmailChange", s.ctx, userID).Return(s.change, nil)
+	s.mockSigner.On("Verify", mock.AnythingOfType("string"), s.change.ExpiresAt, "forged").Return(false)
+
+	s.ErrorIs(s.usecase.CancelEmailChange(s.ctx, userID, "forged"), userpkg.ErrInvalidLink)
+	s.mockChanges.AssertNotCalled(s.T(), "DeleteEmailChange", mock.Anything, mock.Anything)
+}
+
+func (s *emailChangeTestSuite) TestCancelEmailChange_LinkSurvivesStorage() {
+	userID := s.user.ID.Hex()
+	s.mockUserRepo.On("FindByID", s.ctx, userID).Return(s.user, nil)
+	s.mockPasswordSvc.On("ComparePassword", "hashed", "secret").Return(nil)
+	s.mockUserRepo.On("ExistsByEmail", s.ctx, newAddress).Return(false, nil)
+	s.mockEmailVerifier.On("IsRealEmail", newAddress).Return(true, nil)
+	s.mockVerification.On("StoreVerification", s.ctx, mock.Anything).Return(nil)
+	s.mockEmailSender.On("SendEmail", mock.Anything, "", mock.Anything, mock.Anything).Return(nil)
+	// Stored the way the repository stores it, which keeps only milliseconds
+	var stored userpkg.EmailChange
+	s.mockChanges.On("SaveEmailChange", s.ctx, mock.Anything).Run(func(args mock.Arguments) {
+		raw, err := bson.Marshal(args.Get(1))
+		s.Require().NoError(err)
+		s.Require().NoError(bson.Unmarshal(raw, &stored))
+	}).Return(nil)
+	var signed string
+	s.mockSigner.On("Sign", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) { signed = args.String(0) }).Return("sig")
+	_, err := s.usecase.RequestEmailChange(s.ctx, userID, "secret", newAddress, "")
+	s.Require().NoError(err)
+
+	s.mockChanges.On("GetEmailChange", s.ctx, userID).Return(func(context.Context, string) (userpkg.EmailChange, error) { return stored, nil })
+	s.mockSigner.On("Verify", mock.MatchedBy(func(r string) bool { return r == signed }), mock.Anything, "sig").Return(true)
+	s.mockChanges.On("DeleteEmailChange", s.ctx, userID).Return(nil)
+	s.mockVerification.On("DeleteVerification", s.ctx, newAddress).Return(nil)
+
+	s.NoError(s.usecase.CancelEmailChange(s.ctx, userID, "sig"))
+}
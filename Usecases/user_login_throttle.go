@@ -0,0 +1,165 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	auditpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/audit"
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+)
+
+// LoginThrottlePolicy controls how failed logins slow down and lock accounts
+type LoginThrottlePolicy struct {
+	FreeAttempts   int           // account failures allowed before backoff starts
+	IPFreeAttempts int           // an IP may front many users, so it gets more slack
+	BaseDelay      time.Duration // first backoff delay, doubled for each further failure
+	MaxDelay       time.Duration
+	LockThreshold  int // account failures that lock the account
+	LockDuration   time.Duration
+	Window         time.Duration // failures are forgotten after this long without another
+}
+
+func DefaultLoginThrottlePolicy() LoginThrottlePolicy {
+	return LoginThrottlePolicy{
+		FreeAttempts:   3,
+		IPFreeAttempts: 20,
+		BaseDelay:      time.Second,
+		MaxDelay:       15 * time.Minute,
+		LockThreshold:  10,
+		LockDuration:   15 * time.Minute,
+		Window:         time.Hour,
+	}
+}
+
+// WithLoginThrottle enables failed-login tracking per account and per IP
+func (uu *UserUsecase) WithLoginThrottle(repo userpkg.ILoginAttemptRepository, policy LoginThrottlePolicy) *UserUsecase {
+	uu.loginAttempts = repo
+	uu.loginPolicy = policy
+	return uu
+}
+
+func accountAttemptKey(userID string) string { return "account:" + userID }
+func ipAttemptKey(ip string) string           { return "ip:" + ip }
+
+// backoff is how long to wait after the given number of failures
+func (p LoginThrottlePolicy) backoff(failures, free int) time.Duration {
+	over := failures - free
+	if over <= 0 {
+		return 0
+	}
+	delay := p.BaseDelay
+	for i := 1; i < over && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+	return delay
+}
+
+// checkLoginThrottle refuses a login while key is locked or backing off.
+// Store errors are logged and let the login through.
+func (uu *UserUsecase) checkLoginThrottle(ctx context.Context, key string, free int) error {
+	if uu.loginAttempts == nil {
+		return nil
+	}
+
+	attempts, err := uu.loginAttempts.GetLoginAttempts(ctx, key)
+	if err != nil {
+		log.Printf("login throttle: failed to read %s: %v", key, err)
+		return nil
+	}
+
+	now := time.Now()
+	if attempts.LockedUntil != nil && now.Before(*attempts.LockedUntil) {
+		return &userpkg.LoginThrottledError{Locked: true, RetryAfter: attempts.LockedUntil.Sub(now)}
+	}
+	if attempts.ExpiresAt.Before(now) {
+		return nil
+	}
+	retryAt := attempts.LastFailureAt.Add(uu.loginPolicy.backoff(attempts.Failures, free))
+	if now.Before(retryAt) {
+		return &userpkg.LoginThrottledError{RetryAfter: retryAt.Sub(now)}
+	}
+	return nil
+}
+
+// recordLoginFailure counts a failed login against the client IP and, when
+// the login matched an account, against that account, locking it once the
+// threshold is reached.
+func (uu *UserUsecase) recordLoginFailure(ctx context.Context, user *userpkg.User, ip string) {
+	if uu.loginAttempts == nil {
+		return
+	}
+
+	now := time.Now()
+	if ip != "" {
+		if _, err := uu.loginAttempts.RecordLoginFailure(ctx, ipAttemptKey(ip), now, uu.loginPolicy.Window); err != nil {
+			log.Printf("login throttle: failed to record failure for ip %s: %v", ip, err)
+		}
+	}
+	if user == nil {
+		return
+	}
+
+	key := accountAttemptKey(user.ID.Hex())
+	attempts, err := uu.loginAttempts.RecordLoginFailure(ctx, key, now, uu.loginPolicy.Window)
+	if err != nil {
+		log.Printf("login throttle: failed to record failure for %s: %v", key, err)
+		return
+	}
+	if attempts.Failures < uu.loginPolicy.LockThreshold {
+		return
+	}
+	if attempts.LockedUntil != nil && now.Before(*attempts.LockedUntil) {
+		return
+	}
+
+	until := now.Add(uu.loginPolicy.LockDuration)
+	if err := uu.loginAttempts.LockLogin(ctx, key, until); err != nil {
+		log.Printf("login throttle: failed to lock %s: %v", key, err)
+		return
+	}
+	log.Printf("security: account locked user=%s failures=%d ip=%s until=%s",
+		user.ID.Hex(), attempts.Failures, ip, until.UTC().Format(time.RFC3339))
+
+	_ = uu.emailSender.SendEmail(user.Email, user.Locale, services.TemplateAccountLocked, services.EmailData{
+		"Name":     user.Username,
+		"Failures": attempts.Failures,
+		"Until":    until,
+	})
+}
+
+// clearLoginFailures forgets an account's failures after a successful login.
+// IP counters are left to expire so one valid account cannot reset them.
+func (uu *UserUsecase) clearLoginFailures(ctx context.Context, userID string) {
+	if uu.loginAttempts == nil {
+		return
+	}
+	if err := uu.loginAttempts.ResetLoginAttempts(ctx, accountAttemptKey(userID)); err != nil {
+		log.Printf("login throttle: failed to reset %s: %v", accountAttemptKey(userID), err)
+	}
+}
+
+// UnlockUser lets an admin clear a lockout before it expires
+func (uu *UserUsecase) UnlockUser(ctx context.Context, targetUserID, actorUserID string) error {
+	if uu.loginAttempts == nil {
+		return errors.New("login throttling is not enabled")
+	}
+	if _, err := uu.userRepo.FindByID(ctx, targetUserID); err != nil {
+		return errors.New("user not found")
+	}
+	if err := uu.loginAttempts.ResetLoginAttempts(ctx, accountAttemptKey(targetUserID)); err != nil {
+		return err
+	}
+	recordAudit(ctx, uu.audit, auditpkg.Event{
+		ActorID:    actorUserID,
+		Action:     auditpkg.ActionUserUnlock,
+		TargetType: auditpkg.TargetUser,
+		TargetID:   targetUserID,
+	})
+	return nil
+}
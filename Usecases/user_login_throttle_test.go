@@ -0,0 +1,320 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	utils "github.com/Amaankaa/Blog-Starter-Project/Domain/utils"
+	usecases "github.com/Amaankaa/Blog-Starter-Project/Usecases"
+	"github.com/Amaankaa/Blog-Starter-Project/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type loginThrottleTestSuite struct {
+	suite.Suite
+	ctx             context.Context
+	mockUserRepo    *mocks.IUserRepository
+	mockPasswordSvc *mocks.IPasswordService
+	mockTokenRepo   *mocks.ITokenRepository
+	mockJWTService  *mocks.IJWTService
+	mockEmailSender *mocks.IEmailSender
+	mockAttempts    *mocks.ILoginAttemptRepository
+	mockTOTP        *mocks.ITOTPService
+	mockChallenges  *mocks.IMFAChallengeRepository
+	usecase         *usecases.UserUsecase
+	user            userpkg.User
+	device          userpkg.DeviceInfo
+}
+
+func TestLoginThrottleTestSuite(t *testing.T) {
+	suite.Run(t, new(loginThrottleTestSuite))
+}
+
+func (s *loginThrottleTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockUserRepo = new(mocks.IUserRepository)
+	s.mockPasswordSvc = new(mocks.IPasswordService)
+	s.mockTokenRepo = new(mocks.ITokenRepository)
+	s.mockJWTService = new(mocks.IJWTService)
+	s.mockEmailSender = new(mocks.IEmailSender)
+	s.mockAttempts = new(mocks.ILoginAttemptRepository)
+	s.mockTOTP = new(mocks.ITOTPService)
+	s.mockChallenges = new(mocks.IMFAChallengeRepository)
+
+	s.usecase = usecases.NewUserUsecase(
+		s.mockUserRepo,
+		s.mockPasswordSvc,
+		s.mockTokenRepo,
+		s.mockJWTService,
+		new(mocks.IEmailVerifier),
+		s.mockEmailSender,
+		new(mocks.IPasswordResetRepository),
+		new(mocks.IVerificationRepository),
+		new(mocks.ICloudinaryService),
+	).WithLoginThrottle(s.mockAttempts, usecases.DefaultLoginThrottlePolicy()).
+		WithMFA(s.mockTOTP, s.mockChallenges, false)
+
+	s.user = userpkg.User{
+		ID:         primitive.NewObjectID(),
+		Username:   "bob",
+		Email:      "bob@example.com",
+		Password:   "hashed",
+		Role:       "user",
+		IsVerified: true,
+	}
+	s.device = userpkg.DeviceInfo{IP: "203.0.113.7"}
+}
+
+func (s *loginThrottleTestSuite) TearDownTest() {
+	s.mockUserRepo.AssertExpectations(s.T())
+	s.mockPasswordSvc.AssertExpectations(s.T())
+	s.mockAttempts.AssertExpectations(s.T())
+	s.mockEmailSender.AssertExpectations(s.T())
+	s.mockTOTP.AssertExpectations(s.T())
+	s.mockChallenges.AssertExpectations(s.T())
+}
+
+func (s *loginThrottleTestSuite) accountKey() string { return "account:" + s.user.ID.Hex() }
+
+func (s *loginThrottleTestSuite) TestFailedPasswordRecordsAccountAndIP() {
+	s.mockAttempts.On("GetLoginAttempts", s.ctx, "ip:203.0.113.7").Return(userpkg.LoginAttempts{}, nil)
+	s.mockUserRepo.On("GetUserByLogin", s.ctx, "bob").Return(s.user, nil)
+	s.mockAttempts.On("GetLoginAttempts", s.ctx, s.accountKey()).Return(userpkg.LoginAttempts{}, nil)
+	s.mockPasswordSvc.On("ComparePassword", "hashed", "wrong").Return(errors.New("mismatch"))
+	s.mockAttempts.On("RecordLoginFailure", s.ctx, "ip:203.0.113.7", mock.Anything, time.Hour).
+		Return(userpkg.LoginAttempts{Failures: 1}, nil)
+	s.mockAttempts.On("RecordLoginFailure", s.ctx, s.accountKey(), mock.Anything, time.Hour).
+		Return(userpkg.LoginAttempts{Failures: 1}, nil)
+
+	_, err := s.usecase.LoginUser(s.ctx, "bob", "wrong", s.device)
+
+	s.EqualError(err, "invalid credentials")
+	s.mockAttempts.AssertNotCalled(s.T(), "LockLogin", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *loginThrottleTestSuite) TestUnknownLoginOnlyCountsIP() {
+	s.mockAttempts.On("GetLoginAttempts", s.ctx, "ip:203.0.113.7").Return(userpkg.LoginAttempts{}, nil)
+	s.mockUserRepo.On("GetUserByLogin", s.ctx, "ghost").Return(userpkg.User{}, errors.New("not found"))
+	s.mockAttempts.On("RecordLoginFailure", s.ctx, "ip:203.0.113.7", mock.Anything, time.Hour).
+		Return(userpkg.LoginAttempts{Failures: 1}, nil)
+
+	_, err := s.usecase.LoginUser(s.ctx, "ghost", "pw", s.device)
+
+	s.EqualError(err, "invalid credentials")
+}
+
+func (s *loginThrottleTestSuite) TestLockoutAtThresholdSendsEmail() {
+	s.mockAttempts.On("GetLoginAttempts", s.ctx, "ip:203.0.113.7").Return(userpkg.LoginAttempts{}, nil)
+	s.mockUserRepo.On("GetUserByLogin", s.ctx, "bob").Return(s.user, nil)
+	s.mockAttempts.On("GetLoginAttempts", s.ctx, s.accountKey()).Return(userpkg.LoginAttempts{}, nil)
+	s.mockPasswordSvc.On("ComparePassword", "hashed", "wrong").Return(errors.New("mismatch"))
+	s.mockAttempts.On("RecordLoginFailure", s.ctx, "ip:203.0.113.7", mock.Anything, time.Hour).
+		Return(userpkg.LoginAttempts{Failures: 10}, nil)
+	s.mockAttempts.On("RecordLoginFailure", s.ctx, s.accountKey(), mock.Anything, time.Hour).
+		Return(userpkg.LoginAttempts{Failures: 10}, nil)
+	s.mockAttempts.On("LockLogin", s.ctx, s.accountKey(), mock.MatchedBy(func(until time.Time) bool {
+		return until.After(time.Now().Add(14*time.Minute)) && until.Before(time.Now().Add(16*time.Minute))
+	})).Return(nil)
+	s.mockEmailSender.On("SendEmail", "bob@example.com", "", services.TemplateAccountLocked, mock.Anything).Return(nil)
+
+	_, err := s.usecase.LoginUser(s.ctx, "bob", "wrong", s.device)
+
+	s.EqualError(err, "invalid credentials")
+}
+
+func (s *loginThrottleTestSuite) TestAlreadyLockedDoesNotEmailAgain() {
+	lockedUntil := time.Now().Add(5 * time.Minute)
+	s.mockAttempts.On("GetLoginAttempts", s.ctx, "ip:203.0.113.7").Return(userpkg.LoginAttempts{}, nil)
+	s.mockUserRepo.On("GetUserByLogin", s.ctx, "bob").Return(s.user, nil)
+	// Lock elapsed between the check and the failure
+	s.mockAttempts.On("GetLoginAttempts", s.ctx, s.accountKey()).Return(userpkg.LoginAttempts{}, nil)
+	s.mockPasswordSvc.On("ComparePassword", "hashed", "wrong").Return(errors.New("mismatch"))
+	s.mockAttempts.On("RecordLoginFailure", s.ctx, "ip:203.0.113.7", mock.Anything, time.Hour).
+		Return(userpkg.LoginAttempts{Failures: 1}, nil)
+	s.mockAttempts.On("RecordLoginFailure", s.ctx, s.accountKey(), mock.Anything, time.Hour).
+		Return(userpkg.LoginAttempts{Failures: 11, LockedUntil: &lockedUntil}, nil)
+
+	_, err := s.usecase.LoginUser(s.ctx, "bob", "wrong", s.device)
+
+	s.EqualError(err, "invalid credentials")
+	s.mockAttempts.AssertNotCalled(s.T(), "LockLogin", mock.Anything, mock.Anything, mock.Anything)
+	s.mockEmailSender.AssertNotCalled(s.T(), "SendEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *loginThrottleTestSuite) TestLockedAccountRejectedBeforePasswordCheck() {
+	lockedUntil := time.Now().Add(10 * time.Minute)
+	s.mockAttempts.On("GetLoginAttempts", s.ctx, "ip:203.0.113.7").Return(userpkg.LoginAttempts{}, nil)
+	s.mockUserRepo.On("GetUserByLogin", s.ctx, "bob").Return(s.user, nil)
+	s.mockAttempts.On("GetLoginAttempts", s.ctx, s.accountKey()).Return(userpkg.LoginAttempts{
+		Failures:    10,
+		LockedUntil: &lockedUntil,
+		ExpiresAt:   lockedUntil,
+	}, nil)
+
+	_, err := s.usecase.LoginUser(s.ctx, "bob", "correct", s.device)
+
+	var throttled *userpkg.LoginThrottledError
+	s.Require().ErrorAs(err, &throttled)
+	s.True(throttled.Locked)
+	s.InDelta((10 * time.Minute).Seconds(), throttled.RetryAfter.Seconds(), 5)
+	s.mockPasswordSvc.AssertNotCalled(s.T(), "ComparePassword", mock.Anything, mock.Anything)
+}
+
+func (s *loginThrottleTestSuite) TestExponentialBackoff() {
+	// 6 failures with 3 free: 1s * 2^(6-3-1) = 4s after the last failure
+	s.mockAttempts.On("GetLoginAttempts", s.ctx, "ip:203.0.113.7").Return(userpkg.LoginAttempts{}, nil)
+	s.mockUserRepo.On("GetUserByLogin", s.ctx, "bob").Return(s.user, nil)
+	s.mockAttempts.On("GetLoginAttempts", s.ctx, s.accountKey()).Return(userpkg.LoginAttempts{
+		Failures:      6,
+		LastFailureAt: time.Now().Add(-time.Second),
+		ExpiresAt:     time.Now().Add(time.Hour),
+	}, nil)
+
+	_, err := s.usecase.LoginUser(s.ctx, "bob", "pw", s.device)
+
+	var throttled *userpkg.LoginThrottledError
+	s.Require().ErrorAs(err, &throttled)
+	s.False(throttled.Locked)
+	s.InDelta(3, throttled.RetryAfter.Seconds(), 0.5)
+}
+
+func (s *loginThrottleTestSuite) TestBackoffElapsedAllowsAttempt() {
+	s.mockAttempts.On("GetLoginAttempts", s.ctx, "ip:203.0.113.7").Return(userpkg.LoginAttempts{}, nil)
+	s.mockUserRepo.On("GetUserByLogin", s.ctx, "bob").Return(s.user, nil)
+	s.mockAttempts.On("GetLoginAttempts", s.ctx, s.accountKey()).Return(userpkg.LoginAttempts{
+		Failures:      4,
+		LastFailureAt: time.Now().Add(-2 * time.Second),
+		ExpiresAt:     time.Now().Add(time.Hour),
+	}, nil)
+	s.mockPasswordSvc.On("ComparePassword", "hashed", "pw").Return(nil)
+	s.mockPasswordSvc.On("NeedsRehash", "hashed").Return(false)
+	s.mockAttempts.On("ResetLoginAttempts", s.ctx, s.accountKey()).Return(nil)
+	s.mockJWTService.On("GenerateToken", mock.Anything).Return(userpkg.TokenResult{AccessToken: "access"}, nil)
+	s.mockTokenRepo.On("StoreToken", s.ctx, mock.Anything).Return(nil)
+
+	result, err := s.usecase.LoginUser(s.ctx, "bob", "pw", s.device)
+
+	s.NoError(err)
+	s.Equal("access", result.AccessToken)
+}
+
+func (s *loginThrottleTestSuite) TestIPBackoffRejectsBeforeLookup() {
+	s.mockAttempts.On("GetLoginAttempts", s.ctx, "ip:203.0.113.7").Return(userpkg.LoginAttempts{
+		Failures:      25,
+		LastFailureAt: time.Now(),
+		ExpiresAt:     time.Now().Add(time.Hour),
+	}, nil)
+
+	_, err := s.usecase.LoginUser(s.ctx, "bob", "pw", s.device)
+
+	var throttled *userpkg.LoginThrottledError
+	s.Require().ErrorAs(err, &throttled)
+	s.mockUserRepo.AssertNotCalled(s.T(), "GetUserByLogin", mock.Anything, mock.Anything)
+}
+
+func (s *loginThrottleTestSuite) TestStoreErrorFailsOpen() {
+	s.mockAttempts.On("GetLoginAttempts", s.ctx, "ip:203.0.113.7").Return(userpkg.LoginAttempts{}, errors.New("db down"))
+	s.mockUserRepo.On("GetUserByLogin", s.ctx, "bob").Return(s.user, nil)
+	s.mockAttempts.On("GetLoginAttempts", s.ctx, s.accountKey()).Return(userpkg.LoginAttempts{}, errors.New("db down"))
+	s.mockPasswordSvc.On("ComparePassword", "hashed", "pw").Return(nil)
+	s.mockPasswordSvc.On("NeedsRehash", "hashed").Return(false)
+	s.mockAttempts.On("ResetLoginAttempts", s.ctx, s.accountKey()).Return(errors.New("db down"))
+	s.mockJWTService.On("GenerateToken", mock.Anything).Return(userpkg.TokenResult{AccessToken: "access"}, nil)
+	s.mockTokenRepo.On("StoreToken", s.ctx, mock.Anything).Return(nil)
+
+	_, err := s.usecase.LoginUser(s.ctx, "bob", "pw", s.device)
+
+	s.NoError(err)
+}
+
+func (s *loginThrottleTestSuite) TestPasswordAloneDoesNotClearFailures() {
+	s.user.MFA = userpkg.MFASettings{Enabled: true, Secret: "secret"}
+	s.mockAttempts.On("GetLoginAttempts", s.ctx, "ip:203.0.113.7").Return(userpkg.LoginAttempts{}, nil)
+	s.mockUserRepo.On("GetUserByLogin", s.ctx, "bob").Return(s.user, nil)
+	s.mockAttempts.On("GetLoginAttempts", s.ctx, s.accountKey()).Return(userpkg.LoginAttempts{}, nil)
+	s.mockPasswordSvc.On("ComparePassword", "hashed", "pw").Return(nil)
+	s.mockPasswordSvc.On("NeedsRehash", "hashed").Return(false)
+	s.mockChallenges.On("StoreChallenge", s.ctx, mock.Anything).Return(nil)
+
+	result, err := s.usecase.LoginUser(s.ctx, "bob", "pw", s.device)
+
+	s.NoError(err)
+	s.NotEmpty(result.MFAToken)
+	s.mockAttempts.AssertNotCalled(s.T(), "ResetLoginAttempts", mock.Anything, mock.Anything)
+}
+
+func (s *loginThrottleTestSuite) expectMFAChallenge() {
+	s.user.MFA = userpkg.MFASettings{Enabled: true, Secret: "secret"}
+	s.mockChallenges.On("FindChallenge", s.ctx, utils.HashToken("mfa-token")).Return(userpkg.MFAChallenge{
+		UserID:    s.user.ID,
+		ExpiresAt: time.Now().Add(time.Minute),
+	}, nil)
+	s.mockUserRepo.On("FindByID", s.ctx, s.user.ID.Hex()).Return(s.user, nil)
+}
+
+func (s *loginThrottleTestSuite) TestWrongMFACodeCountsAgainstAccount() {
+	s.expectMFAChallenge()
+	s.mockAttempts.On("GetLoginAttempts", s.ctx, s.accountKey()).Return(userpkg.LoginAttempts{}, nil)
+	s.mockTOTP.On("ValidateCode", "secret", "000000", mock.Anything).Return(int64(0), false)
+	s.mockChallenges.On("IncrementChallengeAttempts", s.ctx, utils.HashToken("mfa-token")).Return(nil)
+	s.mockAttempts.On("RecordLoginFailure", s.ctx, "ip:203.0.113.7", mock.Anything, time.Hour).
+		Return(userpkg.LoginAttempts{Failures: 1}, nil)
+	s.mockAttempts.On("RecordLoginFailure", s.ctx, s.accountKey(), mock.Anything, time.Hour).
+		Return(userpkg.LoginAttempts{Failures: 4}, nil)
+
+	_, err := s.usecase.CompleteMFALogin(s.ctx, "mfa-token", "000000", s.device)
+
+	s.EqualError(err, "invalid mfa code")
+}
+
+func (s *loginThrottleTestSuite) TestLockedAccountCannotCompleteMFA() {
+	lockedUntil := time.Now().Add(10 * time.Minute)
+	s.expectMFAChallenge()
+	s.mockAttempts.On("GetLoginAttempts", s.ctx, s.accountKey()).Return(userpkg.LoginAttempts{
+		Failures:    10,
+		LockedUntil: &lockedUntil,
+		ExpiresAt:   lockedUntil,
+	}, nil)
+
+	_, err := s.usecase.CompleteMFALogin(s.ctx, "mfa-token", "123456", s.device)
+
+	var throttled *userpkg.LoginThrottledError
+	s.Require().ErrorAs(err, &throttled)
+	s.True(throttled.Locked)
+	s.mockTOTP.AssertNotCalled(s.T(), "ValidateCode", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *loginThrottleTestSuite) TestMFALoginClearsFailures() {
+	s.expectMFAChallenge()
+	s.mockAttempts.On("GetLoginAttempts", s.ctx, s.accountKey()).Return(userpkg.LoginAttempts{}, nil)
+	s.mockTOTP.On("ValidateCode", "secret", "123456", mock.Anything).Return(int64(42), true)
+	s.mockUserRepo.On("ConsumeTOTPStep", s.ctx, s.user.ID.Hex(), int64(42)).Return(nil)
+	s.mockChallenges.On("DeleteChallenge", s.ctx, utils.HashToken("mfa-token")).Return(nil)
+	s.mockAttempts.On("ResetLoginAttempts", s.ctx, s.accountKey()).Return(nil)
+	s.mockJWTService.On("GenerateToken", mock.Anything).Return(userpkg.TokenResult{AccessToken: "access"}, nil)
+	s.mockTokenRepo.On("StoreToken", s.ctx, mock.Anything).Return(nil)
+
+	result, err := s.usecase.CompleteMFALogin(s.ctx, "mfa-token", "123456", s.device)
+
+	s.NoError(err)
+	s.Equal("access", result.AccessToken)
+}
+
+func (s *loginThrottleTestSuite) TestUnlockUser() {
+	s.mockUserRepo.On("FindByID", s.ctx, s.user.ID.Hex()).Return(s.user, nil)
+	s.mockAttempts.On("ResetLoginAttempts", s.ctx, s.accountKey()).Return(nil)
+
+	s.NoError(s.usecase.UnlockUser(s.ctx, s.user.ID.Hex(), "admin"))
+}
+
+func (s *loginThrottleTestSuite) TestUnlockUser_NotFound() {
+	s.mockUserRepo.On("FindByID", s.ctx, "missing").Return(userpkg.User{}, errors.New("user not found"))
+
+	s.EqualError(s.usecase.UnlockUser(s.ctx, "missing", "admin"), "user not found")
+}
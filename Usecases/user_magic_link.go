@@ -0,0 +1,158 @@
+package usecases
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	utils "github.com/Amaankaa/Blog-Starter-Project/Domain/utils"
+)
+
+// magicLinkTTL is how long a sign-in link works
+const magicLinkTTL = 10 * time.Minute
+
+const otpPurposeMagicLink = "magic-link"
+
+// WithMagicLinks enables passwordless sign-in by email. Pending links are
+// kept in links, which should be a separate collection from registration
+// codes; the link in the email opens linkURL, which posts its query to
+// POST /login/magic/verify.
+func (uu *UserUsecase) WithMagicLinks(links userpkg.IVerificationRepository, signer userpkg.IURLSigner, linkURL string) *UserUsecase {
+	uu.magicLinks = links
+	uu.magicLinkSigner = signer
+	uu.magicLinkURL = linkURL
+	return uu
+}
+
+// The token is signed with the address, so a link cannot be pointed at
+// another account
+func magicLinkResource(email, token string) string {
+	return "magic-link:" + email + ":" + token
+}
+
+// RequestMagicLink emails a single-use sign-in link to a verified account.
+// Unknown and unverified addresses get no email and no error, so the
+// endpoint does not reveal who has an account.
+func (uu *UserUsecase) RequestMagicLink(ctx context.Context, email, clientIP string) error {
+	if uu.magicLinks == nil {
+		return errors.New("magic link login is not enabled")
+	}
+	email = strings.TrimSpace(email)
+	if !utils.IsValidEmail(email) {
+		return errors.New("invalid email format")
+	}
+
+	user, err := uu.userRepo.FindByEmail(ctx, email)
+	if err != nil || !user.IsVerified || user.SuspensionError(time.Now()) != nil {
+		return nil
+	}
+
+	// Links share the OTP cooldowns and daily caps; a link sent moments ago
+	// that has not been used is not sent again
+	reuse, err := uu.checkOTPSend(ctx, otpPurposeMagicLink, email, clientIP, func() bool {
+		v, err := uu.magicLinks.GetVerification(ctx, email)
+		return err == nil && otpStillValid(v.OTP, v.ExpiresAt, v.AttemptCount)
+	})
+	if err != nil {
+		return err
+	}
+	if reuse {
+		return nil
+	}
+
+	token, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return errors.New("failed to generate sign-in link")
+	}
+	expiresAt := time.Now().Add(magicLinkTTL).Truncate(time.Second)
+	if err := uu.magicLinks.StoreVerification(ctx, userpkg.Verification{
+		Email:     email,
+		OTP:       utils.HashToken(token),
+		ExpiresAt: expiresAt,
+	}); err != nil {
+		return errors.New("failed to store sign-in link")
+	}
+
+	query := url.Values{}
+	query.Set("email", email)
+	query.Set("token", token)
+	query.Set("expires", strconv.FormatInt(expiresAt.Unix(), 10))
+	query.Set("signature", uu.magicLinkSigner.Sign(magicLinkResource(email, token), expiresAt))
+	link := uu.magicLinkURL + "?" + query.Encode()
+
+	if err := uu.emailSender.SendEmail(email, user.Locale, services.TemplateSignInLink, services.EmailData{
+		"Name":             user.Username,
+		"Link":             link,
+		"ExpiresInMinutes": int(magicLinkTTL / time.Minute),
+	}); err != nil {
+		return errors.New("failed to send sign-in link")
+	}
+	uu.recordOTPSend(ctx, otpPurposeMagicLink, email, clientIP)
+	log.Printf("security: magic link sent user=%s", user.ID.Hex())
+	return nil
+}
+
+// LoginWithMagicLink exchanges a link from RequestMagicLink for the same
+// result LoginUser gives, including the MFA challenge when it is enabled.
+// A link works once; wrong tokens count towards the attempt limit.
+func (uu *UserUsecase) LoginWithMagicLink(ctx context.Context, email, token string, expiresAt time.Time, signature string, device userpkg.DeviceInfo) (userpkg.LoginResult, error) {
+	if uu.magicLinks == nil {
+		return userpkg.LoginResult{}, userpkg.ErrInvalidLink
+	}
+	if device.IP != "" {
+		if err := uu.checkLoginThrottle(ctx, ipAttemptKey(device.IP), uu.loginPolicy.IPFreeAttempts); err != nil {
+			return userpkg.LoginResult{}, err
+		}
+	}
+	email = strings.TrimSpace(email)
+	if !uu.magicLinkSigner.Verify(magicLinkResource(email, token), expiresAt, signature) {
+		uu.recordLoginFailure(ctx, nil, device.IP)
+		return userpkg.LoginResult{}, userpkg.ErrInvalidLink
+	}
+
+	v, err := uu.magicLinks.GetVerification(ctx, email)
+	if err != nil || v.OTP == "" {
+		// Already used, or replaced by a newer link
+		return userpkg.LoginResult{}, userpkg.ErrInvalidLink
+	}
+	if time.Now().After(v.ExpiresAt) {
+		_ = uu.magicLinks.DeleteVerification(ctx, email)
+		return userpkg.LoginResult{}, userpkg.ErrInvalidLink
+	}
+	if v.AttemptCount >= 5 {
+		_ = uu.magicLinks.DeleteVerification(ctx, email)
+		return userpkg.LoginResult{}, errors.New("too many invalid attempts")
+	}
+	if subtle.ConstantTimeCompare([]byte(v.OTP), []byte(utils.HashToken(token))) != 1 {
+		_ = uu.magicLinks.IncrementAttemptCount(ctx, email)
+		return userpkg.LoginResult{}, userpkg.ErrInvalidLink
+	}
+	// Single use: the link is spent before any tokens are issued
+	if err := uu.magicLinks.DeleteVerification(ctx, email); err != nil {
+		return userpkg.LoginResult{}, errors.New("failed to use sign-in link")
+	}
+
+	user, err := uu.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		return userpkg.LoginResult{}, errors.New("invalid credentials")
+	}
+	if !user.IsVerified {
+		return userpkg.LoginResult{}, errors.New("email not verified")
+	}
+	if err := user.SuspensionError(time.Now()); err != nil {
+		return userpkg.LoginResult{}, err
+	}
+	log.Printf("security: magic link login user=%s ip=%s", user.ID.Hex(), device.IP)
+
+	if user.MFA.Enabled {
+		return uu.startMFAChallenge(ctx, user)
+	}
+	return uu.startSession(ctx, user, device, false)
+}
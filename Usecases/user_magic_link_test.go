@@ -0,0 +1,145 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	utils "github.com/Amaankaa/Blog-Starter-Project/Domain/utils"
+	usecases "github.com/Amaankaa/Blog-Starter-Project/Usecases"
+	"github.com/Amaankaa/Blog-Starter-Project/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type magicLinkTestSuite struct {
+	suite.Suite
+	ctx             context.Context
+	mockUserRepo    *mocks.IUserRepository
+	mockTokenRepo   *mocks.ITokenRepository
+	mockJWTService  *mocks.IJWTService
+	mockEmailSender *mocks.IEmailSender
+	mockLinks       *mocks.IVerificationRepository
+	mockSigner      *mocks.IURLSigner
+	usecase         *usecases.UserUsecase
+	user            userpkg.User
+	expires         time.Time
+}
+
+func TestMagicLinkTestSuite(t *testing.T) {
+	suite.Run(t, new(magicLinkTestSuite))
+}
+
+func (s *magicLinkTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockUserRepo = new(mocks.IUserRepository)
+	s.mockTokenRepo = new(mocks.ITokenRepository)
+	s.mockJWTService = new(mocks.IJWTService)
+	s.mockEmailSender = new(mocks.IEmailSender)
+	s.mockLinks = new(mocks.IVerificationRepository)
+	s.mockSigner = new(mocks.IURLSigner)
+
+	s.usecase = usecases.NewUserUsecase(
+		s.mockUserRepo,
+		new(mocks.IPasswordService),
+		s.mockTokenRepo,
+		s.mockJWTService,
+		new(mocks.IEmailVerifier),
+		s.mockEmailSender,
+		new(mocks.IPasswordResetRepository),
+		new(mocks.IVerificationRepository),
+		new(mocks.ICloudinaryService),
+	).WithMagicLinks(s.mockLinks, s.mockSigner, "https://app.example.com/login/magic")
+
+	s.user = userpkg.User{ID: primitive.NewObjectID(), Email: "alice@example.com", Username: "alice", IsVerified: true}
+	s.expires = time.Now().Add(5 * time.Minute)
+}
+
+func (s *magicLinkTestSuite) TearDownTest() {
+	s.mockUserRepo.AssertExpectations(s.T())
+	s.mockTokenRepo.AssertExpectations(s.T())
+	s.mockJWTService.AssertExpectations(s.T())
+	s.mockEmailSender.AssertExpectations(s.T())
+	s.mockLinks.AssertExpectations(s.T())
+	s.mockSigner.AssertExpectations(s.T())
+}
+
+func (s *magicLinkTestSuite) TestRequestMagicLink_SendsSignedLink() {
+	s.mockUserRepo.On("FindByEmail", s.ctx, s.user.Email).Return(s.user, nil)
+	var stored userpkg.Verification
+	s.mockLinks.On("StoreVerification", s.ctx, mock.AnythingOfType("userpkg.Verification")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(userpkg.Verification) }).Return(nil)
+	s.mockSigner.On("Sign", mock.MatchedBy(func(r string) bool { return strings.HasPrefix(r, "magic-link:alice@example.com:") }),
+		mock.AnythingOfType("time.Time")).Return("sig")
+	var data services.EmailData
+	s.mockEmailSender.On("SendEmail", s.user.Email, "", services.TemplateSignInLink, mock.Anything).
+		Run(func(args mock.Arguments) { data = args.Get(3).(services.EmailData) }).Return(nil)
+
+	s.NoError(s.usecase.RequestMagicLink(s.ctx, " alice@example.com ", "203.0.113.7"))
+
+	s.Equal(10, data["ExpiresInMinutes"])
+	link, err := url.Parse(data["Link"].(string))
+	s.Require().NoError(err)
+	s.Equal("sig", link.Query().Get("signature"))
+	s.Equal(utils.HashToken(link.Query().Get("token")), stored.OTP)
+	s.WithinDuration(time.Now().Add(10*time.Minute), stored.ExpiresAt, 2*time.Second)
+}
+
+func (s *magicLinkTestSuite) TestRequestMagicLink_UnknownAddressIsSilent() {
+	s.mockUserRepo.On("FindByEmail", s.ctx, "nobody@example.com").Return(userpkg.User{}, errors.New("user not found"))
+
+	s.NoError(s.usecase.RequestMagicLink(s.ctx, "nobody@example.com", ""))
+}
+
+func (s *magicLinkTestSuite) TestLoginWithMagicLink_StartsSession() {
+	s.mockSigner.On("Verify", "magic-link:alice@example.com:tok", s.expires, "sig").Return(true)
+	s.mockLinks.On("GetVerification", s.ctx, s.user.Email).
+		Return(userpkg.Verification{Email: s.user.Email, OTP: utils.HashToken("tok"), ExpiresAt: s.expires}, nil)
+	s.mockLinks.On("DeleteVerification", s.ctx, s.user.Email).Return(nil)
+	s.mockUserRepo.On("FindByEmail", s.ctx, s.user.Email).Return(s.user, nil)
+	s.mockJWTService.On("GenerateToken", mock.MatchedBy(func(sub userpkg.TokenSubject) bool {
+		return sub.UserID == s.user.ID.Hex() && !sub.MFA
+	})).Return(userpkg.TokenResult{AccessToken: "access", RefreshToken: "refresh"}, nil)
+	s.mockTokenRepo.On("StoreToken", s.ctx, mock.Anything).Return(nil)
+
+	res, err := s.usecase.LoginWithMagicLink(s.ctx, s.user.Email, "tok", s.expires, "sig", userpkg.DeviceInfo{})
+
+	s.NoError(err)
+	s.Equal("access", res.AccessToken)
+	s.Equal("refresh", res.RefreshToken)
+}
+
+func (s *magicLinkTestSuite) TestLoginWithMagicLink_RejectsBadSignature() {
+	s.mockSigner.On("Verify", "magic-link:alice@example.com:tok", s.expires, "forged").Return(false)
+
+	_, err := s.usecase.LoginWithMagicLink(s.ctx, s.user.Email, "tok", s.expires, "forged", userpkg.DeviceInfo{})
+
+	s.ErrorIs(err, userpkg.ErrInvalidLink)
+}
+
+func (s *magicLinkTestSuite) TestLoginWithMagicLink_UsedLink() {
+	s.mockSigner.On("Verify", "magic-link:alice@example.com:tok", s.expires, "sig").Return(true)
+	s.mockLinks.On("GetVerification", s.ctx, s.user.Email).Return(userpkg.Verification{}, mongo.ErrNoDocuments)
+
+	_, err := s.usecase.LoginWithMagicLink(s.ctx, s.user.Email, "tok", s.expires, "sig", userpkg.DeviceInfo{})
+
+	s.ErrorIs(err, userpkg.ErrInvalidLink)
+}
+
+func (s *magicLinkTestSuite) TestLoginWithMagicLink_WrongTokenCountsAttempt() {
+	s.mockSigner.On("Verify", "magic-link:alice@example.com:other", s.expires, "sig").Return(true)
+	s.mockLinks.On("GetVerification", s.ctx, s.user.Email).
+		Return(userpkg.Verification{Email: s.user.Email, OTP: utils.HashToken("tok"), ExpiresAt: s.expires}, nil)
+	s.mockLinks.On("IncrementAttemptCount", s.ctx, s.user.Email).Return(nil)
+
+	_, err := s.usecase.LoginWithMagicLink(s.ctx, s.user.Email, "other", s.expires, "sig", userpkg.DeviceInfo{})
+
+	s.ErrorIs(err, userpkg.ErrInvalidLink)
+}
@@ -0,0 +1,283 @@
+package usecases_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	utils "github.com/Amaankaa/Blog-Starter-Project/Domain/utils"
+	usecases "github.com/Amaankaa/Blog-Starter-Project/Usecases"
+	"github.com/Amaankaa/Blog-Starter-Project/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type userMFAUsecaseTestSuite struct {
+	suite.Suite
+	ctx               context.Context
+	mockUserRepo      *mocks.IUserRepository
+	mockPasswordSvc   *mocks.IPasswordService
+	mockTokenRepo     *mocks.ITokenRepository
+	mockJWTService    *mocks.IJWTService
+	mockEmailSender   *mocks.IEmailSender
+	mockTOTP          *mocks.ITOTPService
+	mockChallengeRepo *mocks.IMFAChallengeRepository
+	usecase           *usecases.UserUsecase
+}
+
+func TestUserMFAUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(userMFAUsecaseTestSuite))
+}
+
+func (s *userMFAUsecaseTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockUserRepo = new(mocks.IUserRepository)
+	s.mockPasswordSvc = new(mocks.IPasswordService)
+	s.mockTokenRepo = new(mocks.ITokenRepository)
+	s.mockJWTService = new(mocks.IJWTService)
+	s.mockEmailSender = new(mocks.IEmailSender)
+	s.mockTOTP = new(mocks.ITOTPService)
+	s.mockChallengeRepo = new(mocks.IMFAChallengeRepository)
+
+	s.usecase = usecases.NewUserUsecase(
+		s.mockUserRepo,
+		s.mockPasswordSvc,
+		s.mockTokenRepo,
+		s.mockJWTService,
+		new(mocks.IEmailVerifier),
+		s.mockEmailSender,
+		new(mocks.IPasswordResetRepository),
+		new(mocks.IVerificationRepository),
+		new(mocks.ICloudinaryService),
+	).WithMFA(s.mockTOTP, s.mockChallengeRepo, true)
+}
+
+func (s *userMFAUsecaseTestSuite) TearDownTest() {
+	s.mockUserRepo.AssertExpectations(s.T())
+	s.mockTOTP.AssertExpectations(s.T())
+	s.mockChallengeRepo.AssertExpectations(s.T())
+	s.mockTokenRepo.AssertExpectations(s.T())
+	s.mockJWTService.AssertExpectations(s.T())
+}
+
+func (s *userMFAUsecaseTestSuite) mfaUser() userpkg.User {
+	return userpkg.User{
+		ID:         primitive.NewObjectID(),
+		Username:   "alice",
+		Email:      "alice@example.com",
+		Password:   "hashed",
+		Role:       "user",
+		IsVerified: true,
+		MFA: userpkg.MFASettings{
+			Enabled:       true,
+			Secret:        "SECRET",
+			RecoveryCodes: []string{utils.HashToken("aaaabbbbccccdddd"), utils.HashToken("abcdefghijklmnop")},
+		},
+	}
+}
+
+func (s *userMFAUsecaseTestSuite) TestLoginUser_MFAEnabledReturnsChallenge() {
+	user := s.mfaUser()
+	s.mockUserRepo.On("GetUserByLogin", s.ctx, "alice").Return(user, nil)
+	s.mockPasswordSvc.On("ComparePassword", "hashed", "pw").Return(nil)
+	s.mockPasswordSvc.On("NeedsRehash", "hashed").Return(false)
+	s.mockChallengeRepo.On("StoreChallenge", s.ctx, mock.MatchedBy(func(c userpkg.MFAChallenge) bool {
+		return c.UserID == user.ID && c.TokenHash != "" && c.ExpiresAt.After(time.Now())
+	})).Return(nil)
+
+	result, err := s.usecase.LoginUser(s.ctx, "alice", "pw", userpkg.DeviceInfo{})
+
+	s.NoError(err)
+	s.True(result.MFARequired())
+	s.Empty(result.AccessToken)
+	s.Empty(result.RefreshToken)
+	s.mockJWTService.AssertNotCalled(s.T(), "GenerateToken", mock.Anything)
+}
+
+func (s *userMFAUsecaseTestSuite) TestCompleteMFALogin_TOTPIssuesMFASession() {
+	user := s.mfaUser()
+	hash := utils.HashToken("challenge")
+	s.mockChallengeRepo.On("FindChallenge", s.ctx, hash).
+		Return(userpkg.MFAChallenge{TokenHash: hash, UserID: user.ID, ExpiresAt: time.Now().Add(time.Minute)}, nil)
+	s.mockUserRepo.On("FindByID", s.ctx, user.ID.Hex()).Return(user, nil)
+	s.mockTOTP.On("ValidateCode", "SECRET", "123456", mock.Anything).Return(int64(42), true)
+	s.mockUserRepo.On("ConsumeTOTPStep", s.ctx, user.ID.Hex(), int64(42)).Return(nil)
+	s.mockChallengeRepo.On("DeleteChallenge", s.ctx, hash).Return(nil)
+	s.mockJWTService.On("GenerateToken", mock.MatchedBy(func(sub userpkg.TokenSubject) bool {
+		return sub.UserID == user.ID.Hex() && sub.MFA
+	})).Return(userpkg.TokenResult{AccessToken: "access", RefreshToken: "refresh"}, nil)
+	s.mockTokenRepo.On("StoreToken", s.ctx, mock.MatchedBy(func(t userpkg.Token) bool { return t.MFA })).Return(nil)
+
+	result, err := s.usecase.CompleteMFALogin(s.ctx, "challenge", "123456", userpkg.DeviceInfo{})
+
+	s.NoError(err)
+	s.Equal("access", result.AccessToken)
+	s.Empty(result.User.Password)
+}
+
+func (s *userMFAUsecaseTestSuite) TestCompleteMFALogin_ReplayedTOTPRejected() {
+	user := s.mfaUser()
+	hash := utils.HashToken("challenge")
+	s.mockChallengeRepo.On("FindChallenge", s.ctx, hash).
+		Return(userpkg.MFAChallenge{TokenHash: hash, UserID: user.ID, ExpiresAt: time.Now().Add(time.Minute)}, nil)
+	s.mockUserRepo.On("FindByID", s.ctx, user.ID.Hex()).Return(user, nil)
+	s.mockTOTP.On("ValidateCode", "SECRET", "123456", mock.Anything).Return(int64(42), true)
+	s.mockUserRepo.On("ConsumeTOTPStep", s.ctx, user.ID.Hex(), int64(42)).Return(userpkg.ErrMFACodeUsed)
+	s.mockChallengeRepo.On("IncrementChallengeAttempts", s.ctx, hash).Return(nil)
+
+	_, err := s.usecase.CompleteMFALogin(s.ctx, "challenge", "123456", userpkg.DeviceInfo{})
+
+	s.EqualError(err, "invalid mfa code")
+}
+
+func (s *userMFAUsecaseTestSuite) TestCompleteMFALogin_RecoveryCodeConsumed() {
+	user := s.mfaUser()
+	hash := utils.HashToken("challenge")
+	s.mockChallengeRepo.On("FindChallenge", s.ctx, hash).
+		Return(userpkg.MFAChallenge{TokenHash: hash, UserID: user.ID, ExpiresAt: time.Now().Add(time.Minute)}, nil)
+	s.mockUserRepo.On("FindByID", s.ctx, user.ID.Hex()).Return(user, nil)
+	s.mockTOTP.On("ValidateCode", "SECRET", "ABCD-EFGH-IJKL-MNOP", mock.Anything).Return(int64(0), false)
+	s.mockUserRepo.On("ConsumeRecoveryCode", s.ctx, user.ID.Hex(), user.MFA.RecoveryCodes[1]).Return(nil)
+	s.mockEmailSender.On("SendEmail", user.Email, "", services.TemplateRecoveryCodeUsed, mock.Anything).Return(nil)
+	s.mockChallengeRepo.On("DeleteChallenge", s.ctx, hash).Return(nil)
+	s.mockJWTService.On("GenerateToken", mock.Anything).Return(userpkg.TokenResult{AccessToken: "access"}, nil)
+	s.mockTokenRepo.On("StoreToken", s.ctx, mock.Anything).Return(nil)
+
+	result, err := s.usecase.CompleteMFALogin(s.ctx, "challenge", "ABCD-EFGH-IJKL-MNOP", userpkg.DeviceInfo{})
+
+	s.NoError(err)
+	s.Equal("access", result.AccessToken)
+	s.mockPasswordSvc.AssertNotCalled(s.T(), "ComparePassword", mock.Anything, mock.Anything)
+}
+
+func (s *userMFAUsecaseTestSuite) TestCompleteMFALogin_LegacyRecoveryCode() {
+	user := s.mfaUser()
+	user.MFA.RecoveryCodes = []string{"$argon2id$v=19$legacy"}
+	hash := utils.HashToken("challenge")
+	s.mockChallengeRepo.On("FindChallenge", s.ctx, hash).
+		Return(userpkg.MFAChallenge{TokenHash: hash, UserID: user.ID, ExpiresAt: time.Now().Add(time.Minute)}, nil)
+	s.mockUserRepo.On("FindByID", s.ctx, user.ID.Hex()).Return(user, nil)
+	s.mockTOTP.On("ValidateCode", "SECRET", "ABCDE-FGHIJ", mock.Anything).Return(int64(0), false)
+	s.mockPasswordSvc.On("ComparePassword", "$argon2id$v=19$legacy", "abcdefghij").Return(nil)
+	s.mockUserRepo.On("ConsumeRecoveryCode", s.ctx, user.ID.Hex(), "$argon2id$v=19$legacy").Return(nil)
+	s.mockEmailSender.On("SendEmail", user.Email, "", services.TemplateRecoveryCodeUsed, mock.Anything).Return(nil)
+	s.mockChallengeRepo.On("DeleteChallenge", s.ctx, hash).Return(nil)
+	s.mockJWTService.On("GenerateToken", mock.Anything).Return(userpkg.TokenResult{AccessToken: "access"}, nil)
+	s.mockTokenRepo.On("StoreToken", s.ctx, mock.Anything).Return(nil)
+
+	_, err := s.usecase.CompleteMFALogin(s.ctx, "challenge", "ABCDE-FGHIJ", userpkg.DeviceInfo{})
+
+	s.NoError(err)
+	s.mockUserRepo.AssertExpectations(s.T())
+}
+
+func (s *userMFAUsecaseTestSuite) TestCompleteMFALogin_TooManyAttempts() {
+	hash := utils.HashToken("challenge")
+	s.mockChallengeRepo.On("FindChallenge", s.ctx, hash).
+		Return(userpkg.MFAChallenge{TokenHash: hash, ExpiresAt: time.Now().Add(time.Minute), AttemptCount: 5}, nil)
+	s.mockChallengeRepo.On("DeleteChallenge", s.ctx, hash).Return(nil)
+
+	_, err := s.usecase.CompleteMFALogin(s.ctx, "challenge", "123456", userpkg.DeviceInfo{})
+
+	s.Error(err)
+	s.Contains(err.Error(), "too many invalid attempts")
+}
+
+func (s *userMFAUsecaseTestSuite) TestCompleteMFALogin_ExpiredChallenge() {
+	hash := utils.HashToken("challenge")
+	s.mockChallengeRepo.On("FindChallenge", s.ctx, hash).
+		Return(userpkg.MFAChallenge{TokenHash: hash, ExpiresAt: time.Now().Add(-time.Second)}, nil)
+	s.mockChallengeRepo.On("DeleteChallenge", s.ctx, hash).Return(nil)
+
+	_, err := s.usecase.CompleteMFALogin(s.ctx, "challenge", "123456", userpkg.DeviceInfo{})
+
+	s.EqualError(err, "invalid or expired mfa token")
+}
+
+func (s *userMFAUsecaseTestSuite) TestEnrollMFA_StoresPendingSecret() {
+	user := s.mfaUser()
+	user.MFA = userpkg.MFASettings{}
+	s.mockUserRepo.On("FindByID", s.ctx, user.ID.Hex()).Return(user, nil)
+	s.mockTOTP.On("GenerateSecret").Return("NEWSECRET", nil)
+	s.mockUserRepo.On("UpdateMFA", s.ctx, user.ID.Hex(), userpkg.MFASettings{PendingSecret: "NEWSECRET"}).Return(nil)
+	s.mockTOTP.On("ProvisioningURI", "NEWSECRET", user.Email).Return("otpauth://totp/x")
+
+	enrollment, err := s.usecase.EnrollMFA(s.ctx, user.ID.Hex())
+
+	s.NoError(err)
+	s.Equal("NEWSECRET", enrollment.Secret)
+	s.Equal("otpauth://totp/x", enrollment.OTPAuthURI)
+}
+
+func (s *userMFAUsecaseTestSuite) TestConfirmMFA_EnablesAndReturnsRecoveryCodes() {
+	user := s.mfaUser()
+	user.MFA = userpkg.MFASettings{PendingSecret: "NEWSECRET"}
+	s.mockUserRepo.On("FindByID", s.ctx, user.ID.Hex()).Return(user, nil)
+	s.mockTOTP.On("ValidateCode", "NEWSECRET", "123456", mock.Anything).Return(int64(7), true)
+	var stored userpkg.MFASettings
+	s.mockUserRepo.On("UpdateMFA", s.ctx, user.ID.Hex(), mock.MatchedBy(func(m userpkg.MFASettings) bool {
+		return m.Enabled && m.Secret == "NEWSECRET" && m.PendingSecret == "" &&
+			m.LastUsedStep == 7 && len(m.RecoveryCodes) == 10 && m.EnabledAt != nil
+	})).Run(func(args mock.Arguments) { stored = args.Get(2).(userpkg.MFASettings) }).Return(nil)
+	s.mockEmailSender.On("SendEmail", user.Email, "", services.TemplateMFAEnabled, mock.Anything).Return(nil)
+
+	codes, err := s.usecase.ConfirmMFA(s.ctx, user.ID.Hex(), "123456")
+
+	s.NoError(err)
+	s.Len(codes, 10)
+	s.Regexp(`^[a-z2-7]{4}(-[a-z2-7]{4}){3}$`, codes[0])
+	s.Equal(utils.HashToken(strings.ReplaceAll(codes[0], "-", "")), stored.RecoveryCodes[0])
+}
+
+func (s *userMFAUsecaseTestSuite) TestConfirmMFA_InvalidCode() {
+	user := s.mfaUser()
+	user.MFA = userpkg.MFASettings{PendingSecret: "NEWSECRET"}
+	s.mockUserRepo.On("FindByID", s.ctx, user.ID.Hex()).Return(user, nil)
+	s.mockTOTP.On("ValidateCode", "NEWSECRET", "000000", mock.Anything).Return(int64(0), false)
+
+	_, err := s.usecase.ConfirmMFA(s.ctx, user.ID.Hex(), "000000")
+
+	s.EqualError(err, "invalid mfa code")
+	s.mockUserRepo.AssertNotCalled(s.T(), "UpdateMFA", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *userMFAUsecaseTestSuite) TestDisableMFA_AdminBlocked() {
+	user := s.mfaUser()
+	user.Role = "admin"
+	s.mockUserRepo.On("FindByID", s.ctx, user.ID.Hex()).Return(user, nil)
+
+	err := s.usecase.DisableMFA(s.ctx, user.ID.Hex(), "pw", "123456")
+
+	s.EqualError(err, "admin accounts must keep two-factor authentication enabled")
+}
+
+func (s *userMFAUsecaseTestSuite) TestDisableMFA_Success() {
+	user := s.mfaUser()
+	s.mockUserRepo.On("FindByID", s.ctx, user.ID.Hex()).Return(user, nil)
+	s.mockPasswordSvc.On("ComparePassword", "hashed", "pw").Return(nil)
+	s.mockTOTP.On("ValidateCode", "SECRET", "123456", mock.Anything).Return(int64(9), true)
+	s.mockUserRepo.On("ConsumeTOTPStep", s.ctx, user.ID.Hex(), int64(9)).Return(nil)
+	s.mockUserRepo.On("UpdateMFA", s.ctx, user.ID.Hex(), userpkg.MFASettings{}).Return(nil)
+	s.mockEmailSender.On("SendEmail", user.Email, "", services.TemplateMFADisabled, mock.Anything).Return(nil)
+
+	err := s.usecase.DisableMFA(s.ctx, user.ID.Hex(), "pw", "123456")
+
+	s.NoError(err)
+}
+
+func (s *userMFAUsecaseTestSuite) TestRegenerateRecoveryCodes() {
+	user := s.mfaUser()
+	s.mockUserRepo.On("FindByID", s.ctx, user.ID.Hex()).Return(user, nil)
+	s.mockTOTP.On("ValidateCode", "SECRET", "123456", mock.Anything).Return(int64(9), true)
+	s.mockUserRepo.On("ConsumeTOTPStep", s.ctx, user.ID.Hex(), int64(9)).Return(nil)
+	s.mockUserRepo.On("UpdateRecoveryCodes", s.ctx, user.ID.Hex(), mock.MatchedBy(func(h []string) bool { return len(h) == 10 })).Return(nil)
+
+	codes, err := s.usecase.RegenerateRecoveryCodes(s.ctx, user.ID.Hex(), "123456")
+
+	s.NoError(err)
+	s.Len(codes, 10)
+}
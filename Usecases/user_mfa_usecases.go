@@ -0,0 +1,299 @@
+package usecases
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	utils "github.com/Amaankaa/Blog-Starter-Project/Domain/utils"
+)
+
+const (
+	mfaChallengeTTL         = 5 * time.Minute
+	maxMFAChallengeAttempts = 5
+	recoveryCodeCount       = 10
+)
+
+// WithMFA enables TOTP two-factor authentication. When requireForAdmins is
+// set, admin accounts may not turn MFA off.
+func (uu *UserUsecase) WithMFA(totp userpkg.ITOTPService, challenges userpkg.IMFAChallengeRepository, requireForAdmins bool) *UserUsecase {
+	uu.totp = totp
+	uu.mfaChallenges = challenges
+	uu.requireMFAForAdmins = requireForAdmins
+	return uu
+}
+
+// startMFAChallenge is the first step of a login on an MFA-enabled account
+func (uu *UserUsecase) startMFAChallenge(ctx context.Context, user userpkg.User) (userpkg.LoginResult, error) {
+	if uu.totp == nil || uu.mfaChallenges == nil {
+		return userpkg.LoginResult{}, errors.New("two-factor authentication is unavailable")
+	}
+
+	token, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return userpkg.LoginResult{}, errors.New("failed to generate mfa token")
+	}
+
+	expiresAt := time.Now().Add(mfaChallengeTTL)
+	err = uu.mfaChallenges.StoreChallenge(ctx, userpkg.MFAChallenge{
+		TokenHash: utils.HashToken(token),
+		UserID:    user.ID,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return userpkg.LoginResult{}, errors.New("failed to store mfa challenge")
+	}
+
+	return userpkg.LoginResult{MFAToken: token, MFAExpiresAt: expiresAt}, nil
+}
+
+// CompleteMFALogin exchanges an MFA challenge token and a TOTP or recovery
+// code for a token pair.
+func (uu *UserUsecase) CompleteMFALogin(ctx context.Context, mfaToken, code string, device userpkg.DeviceInfo) (userpkg.LoginResult, error) {
+	if uu.mfaChallenges == nil {
+		return userpkg.LoginResult{}, errors.New("two-factor authentication is unavailable")
+	}
+	if mfaToken == "" || code == "" {
+		return userpkg.LoginResult{}, errors.New("mfa token and code are required")
+	}
+
+	tokenHash := utils.HashToken(mfaToken)
+	challenge, err := uu.mfaChallenges.FindChallenge(ctx, tokenHash)
+	if err != nil {
+		return userpkg.LoginResult{}, errors.New("invalid or expired mfa token")
+	}
+	if time.Now().After(challenge.ExpiresAt) {
+		_ = uu.mfaChallenges.DeleteChallenge(ctx, tokenHash)
+		return userpkg.LoginResult{}, errors.New("invalid or expired mfa token")
+	}
+	if challenge.AttemptCount >= maxMFAChallengeAttempts {
+		_ = uu.mfaChallenges.DeleteChallenge(ctx, tokenHash)
+		return userpkg.LoginResult{}, errors.New("too many invalid attempts — please log in again")
+	}
+
+	user, err := uu.userRepo.FindByID(ctx, challenge.UserID.Hex())
+	if err != nil || !user.MFA.Enabled {
+		return userpkg.LoginResult{}, errors.New("invalid or expired mfa token")
+	}
+	// Wrong codes count against the account like wrong passwords, so fresh
+	// challenges cannot be used to keep guessing once it is locked
+	if err := uu.checkLoginThrottle(ctx, accountAttemptKey(user.ID.Hex()), uu.loginPolicy.FreeAttempts); err != nil {
+		return userpkg.LoginResult{}, err
+	}
+
+	if err := uu.verifyMFACode(ctx, user, code); err != nil {
+		_ = uu.mfaChallenges.IncrementChallengeAttempts(ctx, tokenHash)
+		uu.recordLoginFailure(ctx, &user, device.IP)
+		return userpkg.LoginResult{}, err
+	}
+
+	// Single use: only the caller that deletes the challenge gets a session
+	if err := uu.mfaChallenges.DeleteChallenge(ctx, tokenHash); err != nil {
+		return userpkg.LoginResult{}, errors.New("invalid or expired mfa token")
+	}
+	if err := user.SuspensionError(time.Now()); err != nil {
+		return userpkg.LoginResult{}, err
+	}
+
+	return uu.startSession(ctx, user, device, true)
+}
+
+// EnrollMFA generates a new TOTP secret. MFA is not enabled until the user
+// proves they can generate codes for it with ConfirmMFA.
+func (uu *UserUsecase) EnrollMFA(ctx context.Context, userID string) (userpkg.MFAEnrollment, error) {
+	if uu.totp == nil {
+		return userpkg.MFAEnrollment{}, errors.New("two-factor authentication is unavailable")
+	}
+
+	user, err := uu.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return userpkg.MFAEnrollment{}, errors.New("user not found")
+	}
+	if user.MFA.Enabled {
+		return userpkg.MFAEnrollment{}, errors.New("mfa is already enabled")
+	}
+
+	secret, err := uu.totp.GenerateSecret()
+	if err != nil {
+		return userpkg.MFAEnrollment{}, errors.New("failed to generate mfa secret")
+	}
+	if err := uu.userRepo.UpdateMFA(ctx, userID, userpkg.MFASettings{PendingSecret: secret}); err != nil {
+		return userpkg.MFAEnrollment{}, errors.New("failed to start mfa enrollment")
+	}
+
+	return userpkg.MFAEnrollment{
+		Secret:     secret,
+		OTPAuthURI: uu.totp.ProvisioningURI(secret, user.Email),
+	}, nil
+}
+
+// ConfirmMFA enables MFA once the user supplies a valid code for the pending
+// secret. The returned recovery codes are shown once and stored hashed.
+func (uu *UserUsecase) ConfirmMFA(ctx context.Context, userID, code string) ([]string, error) {
+	if uu.totp == nil {
+		return nil, errors.New("two-factor authentication is unavailable")
+	}
+
+	user, err := uu.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if user.MFA.Enabled {
+		return nil, errors.New("mfa is already enabled")
+	}
+	if user.MFA.PendingSecret == "" {
+		return nil, errors.New("no mfa enrollment in progress")
+	}
+
+	step, ok := uu.totp.ValidateCode(user.MFA.PendingSecret, code, time.Now())
+	if !ok {
+		return nil, errors.New("invalid mfa code")
+	}
+
+	codes, hashes, err := uu.generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	err = uu.userRepo.UpdateMFA(ctx, userID, userpkg.MFASettings{
+		Enabled:       true,
+		Secret:        user.MFA.PendingSecret,
+		RecoveryCodes: hashes,
+		LastUsedStep:  step,
+		EnabledAt:     &now,
+	})
+	if err != nil {
+		return nil, errors.New("failed to enable mfa")
+	}
+
+	_ = uu.emailSender.SendEmail(user.Email, user.Locale, services.TemplateMFAEnabled, services.EmailData{
+		"Name": user.Username,
+	})
+	return codes, nil
+}
+
+// DisableMFA turns MFA off after re-checking both the password and a code
+func (uu *UserUsecase) DisableMFA(ctx context.Context, userID, password, code string) error {
+	user, err := uu.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+	if !user.MFA.Enabled {
+		return errors.New("mfa is not enabled")
+	}
+	if uu.requireMFAForAdmins && user.Role == userpkg.RoleAdmin {
+		return errors.New("admin accounts must keep two-factor authentication enabled")
+	}
+	if err := uu.passwordSvc.ComparePassword(user.Password, password); err != nil {
+		return errors.New("invalid credentials")
+	}
+	if err := uu.verifyMFACode(ctx, user, code); err != nil {
+		return err
+	}
+
+	if err := uu.userRepo.UpdateMFA(ctx, userID, userpkg.MFASettings{}); err != nil {
+		return errors.New("failed to disable mfa")
+	}
+
+	_ = uu.emailSender.SendEmail(user.Email, user.Locale, services.TemplateMFADisabled, services.EmailData{
+		"Name": user.Username,
+	})
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces every recovery code with a fresh set
+func (uu *UserUsecase) RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error) {
+	user, err := uu.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if !user.MFA.Enabled {
+		return nil, errors.New("mfa is not enabled")
+	}
+	if err := uu.verifyMFACode(ctx, user, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := uu.generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uu.userRepo.UpdateRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, errors.New("failed to store recovery codes")
+	}
+	return codes, nil
+}
+
+// verifyMFACode accepts a current TOTP code or an unused recovery code, and
+// marks it used so it cannot be presented again.
+func (uu *UserUsecase) verifyMFACode(ctx context.Context, user userpkg.User, code string) error {
+	if uu.totp == nil {
+		return errors.New("two-factor authentication is unavailable")
+	}
+	code = strings.TrimSpace(code)
+
+	if step, ok := uu.totp.ValidateCode(user.MFA.Secret, code, time.Now()); ok {
+		if err := uu.userRepo.ConsumeTOTPStep(ctx, user.ID.Hex(), step); err != nil {
+			return errors.New("invalid mfa code")
+		}
+		return nil
+	}
+
+	normalized := normalizeRecoveryCode(code)
+	for _, hash := range user.MFA.RecoveryCodes {
+		if !uu.recoveryCodeMatches(hash, normalized) {
+			continue
+		}
+		if err := uu.userRepo.ConsumeRecoveryCode(ctx, user.ID.Hex(), hash); err != nil {
+			return errors.New("invalid mfa code")
+		}
+		_ = uu.emailSender.SendEmail(user.Email, user.Locale, services.TemplateRecoveryCodeUsed, services.EmailData{
+			"Name": user.Username,
+		})
+		return nil
+	}
+
+	return errors.New("invalid mfa code")
+}
+
+// generateRecoveryCodes returns the plaintext codes and their hashes
+func (uu *UserUsecase) generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw := make([]byte, 10)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, errors.New("failed to generate recovery codes")
+		}
+		// 16 base32 characters, 80 bits of entropy: enough for a fast hash
+		code := strings.ToLower(base32.StdEncoding.EncodeToString(raw))
+		codes = append(codes, code[:4]+"-"+code[4:8]+"-"+code[8:12]+"-"+code[12:])
+		hashes = append(hashes, utils.HashToken(code))
+	}
+	return codes, hashes, nil
+}
+
+// recoveryCodeMatches compares a code to its SHA-256 hash in constant time.
+// Codes issued before that were hashed with the password hasher, recognisable
+// by their "$" prefix, and are checked with it until they are used up or
+// regenerated.
+func (uu *UserUsecase) recoveryCodeMatches(hash, code string) bool {
+	if strings.HasPrefix(hash, "$") {
+		return uu.passwordSvc.ComparePassword(hash, code) == nil
+	}
+	return utils.TokenMatches(hash, code)
+}
+
+// normalizeRecoveryCode makes codes case- and dash-insensitive
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.NewReplacer("-", "", "_", "", " ", "").Replace(code)
+	return code
+}
@@ -0,0 +1,257 @@
+package usecases
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	utils "github.com/Amaankaa/Blog-Starter-Project/Domain/utils"
+)
+
+// oauthStateTTL is how long the user has to finish signing in at the provider
+const oauthStateTTL = 10 * time.Minute
+
+// WithOIDC enables sign-in through the given OpenID Connect providers
+func (uu *UserUsecase) WithOIDC(states userpkg.IOAuthStateRepository, providers ...userpkg.IOIDCProvider) *UserUsecase {
+	uu.oauthStates = states
+	uu.oidcProviders = make(map[string]userpkg.IOIDCProvider, len(providers))
+	for _, p := range providers {
+		uu.oidcProviders[p.Name()] = p
+	}
+	return uu
+}
+
+// OIDCProviders lists the names of the configured providers
+func (uu *UserUsecase) OIDCProviders() []string {
+	names := make([]string, 0, len(uu.oidcProviders))
+	for name := range uu.oidcProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// pkceChallenge is the S256 code challenge for verifier (RFC 7636)
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// StartOIDCLogin returns the provider URL to send the user to. The state,
+// nonce and PKCE verifier are kept server-side until the callback; the state
+// is also returned so the caller can bind it to the browser.
+func (uu *UserUsecase) StartOIDCLogin(ctx context.Context, provider string) (string, string, error) {
+	p, ok := uu.oidcProviders[provider]
+	if !ok || uu.oauthStates == nil {
+		return "", "", errors.New("unknown sign-in provider")
+	}
+
+	state, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return "", "", errors.New("failed to start sign-in")
+	}
+	nonce, err := utils.GenerateSecureToken(16)
+	if err != nil {
+		return "", "", errors.New("failed to start sign-in")
+	}
+	verifier, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return "", "", errors.New("failed to start sign-in")
+	}
+
+	now := time.Now()
+	if err := uu.oauthStates.SaveOAuthState(ctx, userpkg.OAuthState{
+		StateHash:    utils.HashToken(state),
+		Provider:     provider,
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(oauthStateTTL),
+	}); err != nil {
+		return "", "", errors.New("failed to start sign-in")
+	}
+
+	authURL, err := p.AuthCodeURL(ctx, state, nonce, pkceChallenge(verifier))
+	if err != nil {
+		log.Printf("oidc: %s unavailable: %v", provider, err)
+		return "", "", errors.New("sign-in provider is unavailable")
+	}
+	return authURL, state, nil
+}
+
+// CompleteOIDCLogin finishes a sign-in when the provider redirects back. The
+// provider account is matched to a user by its link, then by verified email
+// (linking it), and otherwise a new verified account is created. The result
+// is the same as LoginUser's, including the MFA challenge.
+//
+// browserState is the state the browser kept from StartOIDCLogin. Without it
+// anyone could start a sign-in and have a victim's browser finish it, signing
+// the victim into the attacker's account.
+func (uu *UserUsecase) CompleteOIDCLogin(ctx context.Context, provider, state, browserState, code string, device userpkg.DeviceInfo) (userpkg.LoginResult, error) {
+	p, ok := uu.oidcProviders[provider]
+	if !ok || uu.oauthStates == nil {
+		return userpkg.LoginResult{}, errors.New("unknown sign-in provider")
+	}
+	if state == "" || code == "" {
+		return userpkg.LoginResult{}, userpkg.ErrOAuthStateNotFound
+	}
+	if subtle.ConstantTimeCompare([]byte(state), []byte(browserState)) != 1 {
+		log.Printf("security: oidc callback from another browser provider=%s ip=%s", provider, device.IP)
+		return userpkg.LoginResult{}, userpkg.ErrOAuthStateNotFound
+	}
+
+	// Single use: the state is gone whether or not the exchange succeeds
+	stored, err := uu.oauthStates.ConsumeOAuthState(ctx, utils.HashToken(state))
+	if err != nil || stored.Provider != provider || time.Now().After(stored.ExpiresAt) {
+		return userpkg.LoginResult{}, userpkg.ErrOAuthStateNotFound
+	}
+
+	identity, err := p.Exchange(ctx, code, stored.CodeVerifier, stored.Nonce)
+	if err != nil {
+		log.Printf("security: oidc sign-in rejected provider=%s ip=%s: %v", provider, device.IP, err)
+		return userpkg.LoginResult{}, errors.New("sign-in with " + provider + " failed")
+	}
+
+	user, err := uu.userForIdentity(ctx, identity)
+	if err != nil {
+		return userpkg.LoginResult{}, err
+	}
+	if err := user.SuspensionError(time.Now()); err != nil {
+		return userpkg.LoginResult{}, err
+	}
+	log.Printf("security: oidc login user=%s provider=%s ip=%s", user.ID.Hex(), provider, device.IP)
+
+	if user.MFA.Enabled {
+		return uu.startMFAChallenge(ctx, user)
+	}
+	return uu.startSession(ctx, user, device, false)
+}
+
+// userForIdentity finds or creates the user a provider account signs in as.
+// Only emails the provider has verified are trusted for linking; otherwise
+// anyone could claim an existing account by registering its address there.
+// Linking an account that was never verified drops its password, which the
+// owner can set again through the password reset flow.
+func (uu *UserUsecase) userForIdentity(ctx context.Context, identity userpkg.OIDCIdentity) (userpkg.User, error) {
+	if user, err := uu.userRepo.FindByIdentity(ctx, identity.Provider, identity.Subject); err == nil {
+		return user, nil
+	}
+
+	email := strings.TrimSpace(identity.Email)
+	if email == "" || !identity.EmailVerified || !utils.IsValidEmail(email) {
+		return userpkg.User{}, errors.New("the provider did not share a verified email address")
+	}
+	link := userpkg.LinkedIdentity{
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    email,
+		LinkedAt: time.Now(),
+	}
+
+	user, err := uu.userRepo.FindByEmail(ctx, email)
+	if err == nil {
+		if !user.IsVerified {
+			// Whoever registered this address never proved they own it, and
+			// could be an attacker waiting for the owner to sign in here. Their
+			// password and sessions must not survive the account being claimed.
+			if err := uu.userRepo.UpdatePasswordByEmail(ctx, user.Email, ""); err != nil {
+				return userpkg.User{}, errors.New("failed to link account")
+			}
+			if err := uu.endAllSessions(ctx, user.ID.Hex()); err != nil {
+				return userpkg.User{}, errors.New("failed to link account")
+			}
+			user.Password = ""
+		}
+		if err := uu.userRepo.AddIdentity(ctx, user.ID.Hex(), link); err != nil {
+			return userpkg.User{}, errors.New("failed to link account")
+		}
+		if !user.IsVerified {
+			// The provider has confirmed the address for us
+			if err := uu.userRepo.UpdateIsVerifiedByEmail(ctx, user.Email, true); err != nil {
+				return userpkg.User{}, errors.New("failed to verify account")
+			}
+			user.IsVerified = true
+			_ = uu.verificationRepo.DeleteVerification(ctx, user.Email)
+		}
+		log.Printf("security: oidc account linked user=%s provider=%s", user.ID.Hex(), identity.Provider)
+		_ = uu.emailSender.SendEmail(user.Email, user.Locale, services.TemplateSignInMethodAdded, services.EmailData{
+			"Name":     user.Username,
+			"Provider": identity.Provider,
+		})
+		return user, nil
+	}
+
+	return uu.createOIDCUser(ctx, identity, email, link)
+}
+
+// createOIDCUser registers a new account for a provider identity. It has no
+// password; the user can set one through the password reset flow.
+func (uu *UserUsecase) createOIDCUser(ctx context.Context, identity userpkg.OIDCIdentity, email string, link userpkg.LinkedIdentity) (userpkg.User, error) {
+	username, err := uu.usernameFromEmail(ctx, email)
+	if err != nil {
+		return userpkg.User{}, err
+	}
+	fullname := strings.TrimSpace(identity.Name)
+	if fullname == "" {
+		fullname = username
+	}
+	role := userpkg.RoleUser
+	if count, err := uu.userRepo.CountUsers(ctx); err == nil && count == 0 {
+		role = userpkg.RoleAdmin
+	}
+
+	user, err := uu.userRepo.CreateUser(ctx, userpkg.User{
+		Username:       username,
+		Fullname:       fullname,
+		Email:          email,
+		Role:           role,
+		ProfilePicture: identity.Picture,
+		Identities:     []userpkg.LinkedIdentity{link},
+	})
+	if err != nil {
+		return userpkg.User{}, errors.New("failed to create account")
+	}
+	if err := uu.userRepo.UpdateIsVerifiedByEmail(ctx, email, true); err != nil {
+		return userpkg.User{}, errors.New("failed to verify account")
+	}
+	user.IsVerified = true
+	log.Printf("security: oidc account created user=%s provider=%s", user.ID.Hex(), identity.Provider)
+	return user, nil
+}
+
+var usernameUnsafe = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// usernameFromEmail derives a free username from the address's local part
+func (uu *UserUsecase) usernameFromEmail(ctx context.Context, email string) (string, error) {
+	base := usernameUnsafe.ReplaceAllString(strings.ToLower(strings.SplitN(email, "@", 2)[0]), "")
+	if len(base) < 3 {
+		base = "user" + base
+	}
+	if len(base) > 20 {
+		base = base[:20]
+	}
+
+	candidate := base
+	for i := 0; i < 10; i++ {
+		exists, err := uu.userRepo.ExistsByUsername(ctx, candidate)
+		if err != nil {
+			return "", errors.New("failed to check username existence: " + err.Error())
+		}
+		if !exists {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s%d", base, rand.Intn(10000))
+	}
+	return "", errors.New("could not pick a username")
+}
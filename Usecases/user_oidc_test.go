@@ -0,0 +1,241 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	utils "github.com/Amaankaa/Blog-Starter-Project/Domain/utils"
+	usecases "github.com/Amaankaa/Blog-Starter-Project/Usecases"
+	"github.com/Amaankaa/Blog-Starter-Project/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type oidcTestSuite struct {
+	suite.Suite
+	ctx              context.Context
+	mockUserRepo     *mocks.IUserRepository
+	mockTokenRepo    *mocks.ITokenRepository
+	mockJWTService   *mocks.IJWTService
+	mockEmailSender  *mocks.IEmailSender
+	mockVerification *mocks.IVerificationRepository
+	mockStates       *mocks.IOAuthStateRepository
+	mockProvider     *mocks.IOIDCProvider
+	usecase          *usecases.UserUsecase
+	user             userpkg.User
+	identity         userpkg.OIDCIdentity
+}
+
+func TestOIDCTestSuite(t *testing.T) {
+	suite.Run(t, new(oidcTestSuite))
+}
+
+func (s *oidcTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockUserRepo = new(mocks.IUserRepository)
+	s.mockTokenRepo = new(mocks.ITokenRepository)
+	s.mockJWTService = new(mocks.IJWTService)
+	s.mockEmailSender = new(mocks.IEmailSender)
+	s.mockVerification = new(mocks.IVerificationRepository)
+	s.mockStates = new(mocks.IOAuthStateRepository)
+	s.mockProvider = new(mocks.IOIDCProvider)
+	s.mockProvider.On("Name").Return("google")
+
+	s.usecase = usecases.NewUserUsecase(
+		s.mockUserRepo,
+		new(mocks.IPasswordService),
+		s.mockTokenRepo,
+		s.mockJWTService,
+		new(mocks.IEmailVerifier),
+		s.mockEmailSender,
+		new(mocks.IPasswordResetRepository),
+		s.mockVerification,
+		new(mocks.ICloudinaryService),
+	).WithOIDC(s.mockStates, s.mockProvider)
+
+	s.user = userpkg.User{ID: primitive.NewObjectID(), Email: "alice@example.com", Username: "alice", IsVerified: true}
+	s.identity = userpkg.OIDCIdentity{Provider: "google", Subject: "g-123", Email: "alice@example.com", EmailVerified: true, Name: "Alice A"}
+}
+
+func (s *oidcTestSuite) TearDownTest() {
+	s.mockUserRepo.AssertExpectations(s.T())
+	s.mockTokenRepo.AssertExpectations(s.T())
+	s.mockJWTService.AssertExpectations(s.T())
+	s.mockEmailSender.AssertExpectations(s.T())
+	s.mockVerification.AssertExpectations(s.T())
+	s.mockStates.AssertExpectations(s.T())
+	s.mockProvider.AssertExpectations(s.T())
+}
+
+// expectCallback sets up a valid pending state for "state" and an exchange
+// that returns s.identity
+func (s *oidcTestSuite) expectCallback() {
+	s.mockStates.On("ConsumeOAuthState", s.ctx, utils.HashToken("state")).Return(userpkg.OAuthState{
+		Provider:     "google",
+		CodeVerifier: "verifier",
+		Nonce:        "nonce",
+		ExpiresAt:    time.Now().Add(time.Minute),
+	}, nil)
+	s.mockProvider.On("Exchange", s.ctx, "code", "verifier", "nonce").Return(s.identity, nil)
+}
+
+func (s *oidcTestSuite) expectSession(userID primitive.ObjectID) {
+	s.mockJWTService.On("GenerateToken", mock.MatchedBy(func(sub userpkg.TokenSubject) bool {
+		return sub.UserID == userID.Hex()
+	})).Return(userpkg.TokenResult{AccessToken: "access", RefreshToken: "refresh"}, nil)
+	s.mockTokenRepo.On("StoreToken", s.ctx, mock.Anything).Return(nil)
+}
+
+func (s *oidcTestSuite) TestStartOIDCLogin_StoresHashedStateWithPKCE() {
+	var stored userpkg.OAuthState
+	s.mockStates.On("SaveOAuthState", s.ctx, mock.AnythingOfType("userpkg.OAuthState")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(userpkg.OAuthState) }).Return(nil)
+	var state, challenge string
+	s.mockProvider.On("AuthCodeURL", s.ctx, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { state, challenge = args.String(1), args.String(3) }).
+		Return("https://accounts.example.com/authorize?x=1", nil)
+
+	authURL, returned, err := s.usecase.StartOIDCLogin(s.ctx, "google")
+
+	s.NoError(err)
+	s.Equal("https://accounts.example.com/authorize?x=1", authURL)
+	s.Equal(state, returned, "handed back for the browser to keep")
+	s.Equal(utils.HashToken(state), stored.StateHash)
+	s.Equal("google", stored.Provider)
+	s.NotEqual(stored.CodeVerifier, challenge)
+	s.WithinDuration(time.Now().Add(10*time.Minute), stored.ExpiresAt, 2*time.Second)
+}
+
+func (s *oidcTestSuite) TestStartOIDCLogin_UnknownProvider() {
+	_, _, err := s.usecase.StartOIDCLogin(s.ctx, "myspace")
+
+	s.EqualError(err, "unknown sign-in provider")
+}
+
+func (s *oidcTestSuite) TestCompleteOIDCLogin_LinkedIdentitySignsIn() {
+	s.expectCallback()
+	s.mockUserRepo.On("FindByIdentity", s.ctx, "google", "g-123").Return(s.user, nil)
+	s.expectSession(s.user.ID)
+
+	res, err := s.usecase.CompleteOIDCLogin(s.ctx, "google", "state", "state", "code", userpkg.DeviceInfo{})
+
+	s.NoError(err)
+	s.Equal("access", res.AccessToken)
+}
+
+func (s *oidcTestSuite) TestCompleteOIDCLogin_LinksByVerifiedEmail() {
+	unverified := s.user
+	unverified.IsVerified = false
+	s.expectCallback()
+	s.mockUserRepo.On("FindByIdentity", s.ctx, "google", "g-123").Return(userpkg.User{}, mongo.ErrNoDocuments)
+	s.mockUserRepo.On("FindByEmail", s.ctx, s.user.Email).Return(unverified, nil)
+	s.mockUserRepo.On("AddIdentity", s.ctx, s.user.ID.Hex(), mock.MatchedBy(func(l userpkg.LinkedIdentity) bool {
+		return l.Provider == "google" && l.Subject == "g-123"
+	})).Return(nil)
+	// Someone else may have registered the address with their own password
+	s.mockUserRepo.On("UpdatePasswordByEmail", s.ctx, s.user.Email, "").Return(nil)
+	s.mockTokenRepo.On("DeleteTokensByUserID", s.ctx, s.user.ID.Hex()).Return(nil)
+	s.mockUserRepo.On("UpdateIsVerifiedByEmail", s.ctx, s.user.Email, true).Return(nil)
+	s.mockVerification.On("DeleteVerification", s.ctx, s.user.Email).Return(nil)
+	s.mockEmailSender.On("SendEmail", s.user.Email, "", services.TemplateSignInMethodAdded, mock.Anything).Return(nil)
+	s.expectSession(s.user.ID)
+
+	res, err := s.usecase.CompleteOIDCLogin(s.ctx, "google", "state", "state", "code", userpkg.DeviceInfo{})
+
+	s.NoError(err)
+	s.True(res.User.IsVerified)
+}
+
+func (s *oidcTestSuite) TestCompleteOIDCLogin_LinkingVerifiedAccountKeepsPassword() {
+	verified := s.user
+	verified.Password = "hashed"
+	s.expectCallback()
+	s.mockUserRepo.On("FindByIdentity", s.ctx, "google", "g-123").Return(userpkg.User{}, mongo.ErrNoDocuments)
+	s.mockUserRepo.On("FindByEmail", s.ctx, s.user.Email).Return(verified, nil)
+	s.mockUserRepo.On("AddIdentity", s.ctx, s.user.ID.Hex(), mock.Anything).Return(nil)
+	s.mockEmailSender.On("SendEmail", s.user.Email, "", services.TemplateSignInMethodAdded, mock.Anything).Return(nil)
+	s.expectSession(s.user.ID)
+
+	_, err := s.usecase.CompleteOIDCLogin(s.ctx, "google", "state", "state", "code", userpkg.DeviceInfo{})
+
+	s.NoError(err)
+	s.mockUserRepo.AssertNotCalled(s.T(), "UpdatePasswordByEmail", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *oidcTestSuite) TestCompleteOIDCLogin_RejectsUnverifiedEmail() {
+	s.identity.EmailVerified = false
+	s.expectCallback()
+	s.mockUserRepo.On("FindByIdentity", s.ctx, "google", "g-123").Return(userpkg.User{}, mongo.ErrNoDocuments)
+
+	_, err := s.usecase.CompleteOIDCLogin(s.ctx, "google", "state", "state", "code", userpkg.DeviceInfo{})
+
+	s.EqualError(err, "the provider did not share a verified email address")
+}
+
+func (s *oidcTestSuite) TestCompleteOIDCLogin_CreatesVerifiedUser() {
+	s.identity.Email = "new.person@example.com"
+	s.expectCallback()
+	s.mockUserRepo.On("FindByIdentity", s.ctx, "google", "g-123").Return(userpkg.User{}, mongo.ErrNoDocuments)
+	s.mockUserRepo.On("FindByEmail", s.ctx, "new.person@example.com").Return(userpkg.User{}, errors.New("user not found"))
+	s.mockUserRepo.On("ExistsByUsername", s.ctx, "newperson").Return(false, nil)
+	s.mockUserRepo.On("CountUsers", s.ctx).Return(int64(3), nil)
+	created := primitive.NewObjectID()
+	s.mockUserRepo.On("CreateUser", s.ctx, mock.MatchedBy(func(u userpkg.User) bool {
+		return u.Username == "newperson" && u.Fullname == "Alice A" && u.Password == "" &&
+			u.Role == userpkg.RoleUser && len(u.Identities) == 1
+	})).Return(func(_ context.Context, u userpkg.User) userpkg.User {
+		u.ID = created
+		return u
+	}, nil)
+	s.mockUserRepo.On("UpdateIsVerifiedByEmail", s.ctx, "new.person@example.com", true).Return(nil)
+	s.expectSession(created)
+
+	res, err := s.usecase.CompleteOIDCLogin(s.ctx, "google", "state", "state", "code", userpkg.DeviceInfo{})
+
+	s.NoError(err)
+	s.Equal("newperson", res.User.Username)
+	s.True(res.User.IsVerified)
+}
+
+func (s *oidcTestSuite) TestCompleteOIDCLogin_StateFromAnotherProvider() {
+	s.mockStates.On("ConsumeOAuthState", s.ctx, utils.HashToken("state")).Return(userpkg.OAuthState{
+		Provider:  "github",
+		ExpiresAt: time.Now().Add(time.Minute),
+	}, nil)
+
+	_, err := s.usecase.CompleteOIDCLogin(s.ctx, "google", "state", "state", "code", userpkg.DeviceInfo{})
+
+	s.ErrorIs(err, userpkg.ErrOAuthStateNotFound)
+}
+
+func (s *oidcTestSuite) TestCompleteOIDCLogin_StateFromAnotherBrowser() {
+	// An attacker's own state and code, replayed in a browser that never
+	// started a sign-in or started a different one
+	for _, browserState := range []string{"", "other"} {
+		_, err := s.usecase.CompleteOIDCLogin(s.ctx, "google", "state", browserState, "code", userpkg.DeviceInfo{})
+
+		s.ErrorIs(err, userpkg.ErrOAuthStateNotFound)
+	}
+	s.mockStates.AssertNotCalled(s.T(), "ConsumeOAuthState", mock.Anything, mock.Anything)
+	s.mockProvider.AssertNotCalled(s.T(), "Exchange", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *oidcTestSuite) TestCompleteOIDCLogin_ExchangeFailure() {
+	s.mockStates.On("ConsumeOAuthState", s.ctx, utils.HashToken("state")).Return(userpkg.OAuthState{
+		Provider:     "google",
+		CodeVerifier: "verifier",
+		Nonce:        "nonce",
+		ExpiresAt:    time.Now().Add(time.Minute),
+	}, nil)
+	s.mockProvider.On("Exchange", s.ctx, "code", "verifier", "nonce").Return(userpkg.OIDCIdentity{}, errors.New("nonce mismatch"))
+
+	_, err := s.usecase.CompleteOIDCLogin(s.ctx, "google", "state", "state", "code", userpkg.DeviceInfo{})
+
+	s.EqualError(err, "sign-in with google failed")
+}
@@ -0,0 +1,109 @@
+package usecases
+
+import (
+	"context"
+	"log"
+	"time"
+
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+)
+
+// OTPSendPolicy limits how often OTP emails go out, so the endpoints that
+// send them cannot be used to flood an inbox or burn the email quota.
+type OTPSendPolicy struct {
+	Cooldown   time.Duration // between emails to one address; repeats inside it reuse the code already sent
+	IPCooldown time.Duration // between emails requested from one IP, to any address
+	DailyCap   int           // emails per address and purpose per Window
+	IPDailyCap int
+	Window     time.Duration
+}
+
+func DefaultOTPSendPolicy() OTPSendPolicy {
+	return OTPSendPolicy{
+		Cooldown:   time.Minute,
+		IPCooldown: 10 * time.Second,
+		DailyCap:   5,
+		IPDailyCap: 30,
+		Window:     24 * time.Hour,
+	}
+}
+
+// WithOTPThrottle enables cooldowns and daily caps on OTP emails
+func (uu *UserUsecase) WithOTPThrottle(repo userpkg.IOTPSendRepository, policy OTPSendPolicy) *UserUsecase {
+	uu.otpSends = repo
+	uu.otpPolicy = policy
+	return uu
+}
+
+const (
+	otpPurposeReset  = "reset"
+	otpPurposeVerify = "verify"
+)
+
+func otpEmailKey(purpose, email string) string { return purpose + ":email:" + email }
+func otpIPKey(ip string) string                { return "ip:" + ip }
+
+// checkOTPSend decides whether a new OTP email may go out. It reports reuse
+// when the address got a code within the cooldown that is still valid, in
+// which case nothing should be sent. Store errors are logged and let the
+// email through.
+func (uu *UserUsecase) checkOTPSend(ctx context.Context, purpose, email, ip string, stillValid func() bool) (reuse bool, err error) {
+	if uu.otpSends == nil {
+		return false, nil
+	}
+	now := time.Now()
+
+	sends, err := uu.otpSends.GetOTPSends(ctx, otpEmailKey(purpose, email))
+	if err != nil {
+		log.Printf("otp throttle: failed to read sends for %s: %v", email, err)
+		return false, nil
+	}
+	if retryAt := sends.LastSentAt.Add(uu.otpPolicy.Cooldown); now.Before(retryAt) {
+		if stillValid() {
+			return true, nil
+		}
+		return false, &userpkg.OTPThrottledError{RetryAfter: retryAt.Sub(now)}
+	}
+	if sends.Count >= uu.otpPolicy.DailyCap && now.Before(sends.ExpiresAt) {
+		return false, &userpkg.OTPThrottledError{RetryAfter: sends.ExpiresAt.Sub(now)}
+	}
+
+	if ip == "" {
+		return false, nil
+	}
+	ipSends, err := uu.otpSends.GetOTPSends(ctx, otpIPKey(ip))
+	if err != nil {
+		log.Printf("otp throttle: failed to read sends for ip %s: %v", ip, err)
+		return false, nil
+	}
+	if retryAt := ipSends.LastSentAt.Add(uu.otpPolicy.IPCooldown); now.Before(retryAt) {
+		return false, &userpkg.OTPThrottledError{RetryAfter: retryAt.Sub(now)}
+	}
+	if ipSends.Count >= uu.otpPolicy.IPDailyCap && now.Before(ipSends.ExpiresAt) {
+		log.Printf("security: otp daily cap reached ip=%s", ip)
+		return false, &userpkg.OTPThrottledError{RetryAfter: ipSends.ExpiresAt.Sub(now)}
+	}
+	return false, nil
+}
+
+// recordOTPSend counts an email that went out against the address and IP
+func (uu *UserUsecase) recordOTPSend(ctx context.Context, purpose, email, ip string) {
+	if uu.otpSends == nil {
+		return
+	}
+	now := time.Now()
+	if _, err := uu.otpSends.RecordOTPSend(ctx, otpEmailKey(purpose, email), now, uu.otpPolicy.Window); err != nil {
+		log.Printf("otp throttle: failed to record send for %s: %v", email, err)
+	}
+	if ip == "" {
+		return
+	}
+	if _, err := uu.otpSends.RecordOTPSend(ctx, otpIPKey(ip), now, uu.otpPolicy.Window); err != nil {
+		log.Printf("otp throttle: failed to record send for ip %s: %v", ip, err)
+	}
+}
+
+// otpStillValid reports whether a stored OTP can still be used
+func otpStillValid(otp string, expiresAt time.Time, attempts int) bool {
+	return otp != "" && time.Now().Before(expiresAt) && attempts < 5
+}
@@ -0,0 +1,155 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	usecases "github.com/Amaankaa/Blog-Starter-Project/Usecases"
+	"github.com/Amaankaa/Blog-Starter-Project/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type otpThrottleTestSuite struct {
+	suite.Suite
+	ctx              context.Context
+	mockUserRepo     *mocks.IUserRepository
+	mockPasswordSvc  *mocks.IPasswordService
+	mockEmailSender  *mocks.IEmailSender
+	mockResetRepo    *mocks.IPasswordResetRepository
+	mockVerification *mocks.IVerificationRepository
+	mockSends        *mocks.IOTPSendRepository
+	usecase          *usecases.UserUsecase
+}
+
+const (
+	otpEmail = "bob@example.com"
+	otpIP    = "203.0.113.7"
+)
+
+func TestOTPThrottleTestSuite(t *testing.T) {
+	suite.Run(t, new(otpThrottleTestSuite))
+}
+
+func (s *otpThrottleTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockUserRepo = new(mocks.IUserRepository)
+	s.mockPasswordSvc = new(mocks.IPasswordService)
+	s.mockEmailSender = new(mocks.IEmailSender)
+	s.mockResetRepo = new(mocks.IPasswordResetRepository)
+	s.mockVerification = new(mocks.IVerificationRepository)
+	s.mockSends = new(mocks.IOTPSendRepository)
+
+	s.usecase = usecases.NewUserUsecase(
+		s.mockUserRepo,
+		s.mockPasswordSvc,
+		new(mocks.ITokenRepository),
+		new(mocks.IJWTService),
+		new(mocks.IEmailVerifier),
+		s.mockEmailSender,
+		s.mockResetRepo,
+		s.mockVerification,
+		new(mocks.ICloudinaryService),
+	).WithOTPThrottle(s.mockSends, usecases.DefaultOTPSendPolicy())
+
+	s.mockUserRepo.On("FindByEmail", s.ctx, otpEmail).Return(userpkg.User{Email: otpEmail}, nil)
+}
+
+func (s *otpThrottleTestSuite) TearDownTest() {
+	s.mockUserRepo.AssertExpectations(s.T())
+	s.mockPasswordSvc.AssertExpectations(s.T())
+	s.mockEmailSender.AssertExpectations(s.T())
+	s.mockResetRepo.AssertExpectations(s.T())
+	s.mockVerification.AssertExpectations(s.T())
+	s.mockSends.AssertExpectations(s.T())
+}
+
+func (s *otpThrottleTestSuite) TestSendResetOTP_SendsAndRecords() {
+	s.mockSends.On("GetOTPSends", s.ctx, "reset:email:"+otpEmail).Return(userpkg.OTPSends{}, nil)
+	s.mockSends.On("GetOTPSends", s.ctx, "ip:"+otpIP).Return(userpkg.OTPSends{}, nil)
+	s.mockEmailSender.On("SendEmail", otpEmail, "", services.TemplatePasswordReset, mock.Anything).Return(nil)
+	s.mockSends.On("RecordOTPSend", s.ctx, "reset:email:"+otpEmail, mock.Anything, 24*time.Hour).Return(userpkg.OTPSends{Count: 1}, nil)
+	s.mockSends.On("RecordOTPSend", s.ctx, "ip:"+otpIP, mock.Anything, 24*time.Hour).Return(userpkg.OTPSends{Count: 1}, nil)
+	s.mockResetRepo.On("StoreResetRequest", s.ctx, mock.AnythingOfType("userpkg.PasswordReset")).Return(nil)
+
+	s.NoError(s.usecase.SendResetOTP(s.ctx, otpEmail, otpIP))
+}
+
+func (s *otpThrottleTestSuite) TestSendResetOTP_ReusesValidCodeWithinCooldown() {
+	s.mockSends.On("GetOTPSends", s.ctx, "reset:email:"+otpEmail).
+		Return(userpkg.OTPSends{Count: 1, LastSentAt: time.Now().Add(-10 * time.Second)}, nil)
+	s.mockResetRepo.On("GetResetRequest", s.ctx, otpEmail).
+		Return(userpkg.PasswordReset{OTP: "hashed", ExpiresAt: time.Now().Add(9 * time.Minute)}, nil)
+
+	// No email and no new code
+	s.NoError(s.usecase.SendResetOTP(s.ctx, otpEmail, otpIP))
+}
+
+func (s *otpThrottleTestSuite) TestSendResetOTP_CooldownWithoutValidCode() {
+	s.mockSends.On("GetOTPSends", s.ctx, "reset:email:"+otpEmail).
+		Return(userpkg.OTPSends{Count: 1, LastSentAt: time.Now().Add(-10 * time.Second)}, nil)
+	s.mockResetRepo.On("GetResetRequest", s.ctx, otpEmail).
+		Return(userpkg.PasswordReset{OTP: "hashed", ExpiresAt: time.Now().Add(9 * time.Minute), AttemptCount: 5}, nil)
+
+	err := s.usecase.SendResetOTP(s.ctx, otpEmail, otpIP)
+
+	var throttled *userpkg.OTPThrottledError
+	s.Require().True(errors.As(err, &throttled))
+	s.InDelta(50, throttled.RetryAfter.Seconds(), 1)
+}
+
+func (s *otpThrottleTestSuite) TestSendResetOTP_DailyCap() {
+	expires := time.Now().Add(3 * time.Hour)
+	s.mockSends.On("GetOTPSends", s.ctx, "reset:email:"+otpEmail).
+		Return(userpkg.OTPSends{Count: 5, LastSentAt: time.Now().Add(-time.Hour), ExpiresAt: expires}, nil)
+
+	err := s.usecase.SendResetOTP(s.ctx, otpEmail, otpIP)
+
+	var throttled *userpkg.OTPThrottledError
+	s.Require().True(errors.As(err, &throttled))
+	s.InDelta(3*time.Hour.Seconds(), throttled.RetryAfter.Seconds(), 1)
+}
+
+func (s *otpThrottleTestSuite) TestSendResetOTP_IPCooldown() {
+	s.mockSends.On("GetOTPSends", s.ctx, "reset:email:"+otpEmail).Return(userpkg.OTPSends{}, nil)
+	s.mockSends.On("GetOTPSends", s.ctx, "ip:"+otpIP).
+		Return(userpkg.OTPSends{Count: 3, LastSentAt: time.Now().Add(-2 * time.Second)}, nil)
+
+	err := s.usecase.SendResetOTP(s.ctx, otpEmail, otpIP)
+
+	var throttled *userpkg.OTPThrottledError
+	s.True(errors.As(err, &throttled))
+}
+
+func (s *otpThrottleTestSuite) TestSendResetOTP_IPDailyCap() {
+	s.mockSends.On("GetOTPSends", s.ctx, "reset:email:"+otpEmail).Return(userpkg.OTPSends{}, nil)
+	s.mockSends.On("GetOTPSends", s.ctx, "ip:"+otpIP).
+		Return(userpkg.OTPSends{Count: 30, LastSentAt: time.Now().Add(-time.Minute), ExpiresAt: time.Now().Add(time.Hour)}, nil)
+
+	err := s.usecase.SendResetOTP(s.ctx, otpEmail, otpIP)
+
+	var throttled *userpkg.OTPThrottledError
+	s.True(errors.As(err, &throttled))
+}
+
+func (s *otpThrottleTestSuite) TestSendResetOTP_StoreErrorFailsOpen() {
+	s.mockSends.On("GetOTPSends", s.ctx, "reset:email:"+otpEmail).Return(userpkg.OTPSends{}, errors.New("db down"))
+	s.mockEmailSender.On("SendEmail", otpEmail, "", services.TemplatePasswordReset, mock.Anything).Return(nil)
+	s.mockSends.On("RecordOTPSend", s.ctx, mock.Anything, mock.Anything, mock.Anything).Return(userpkg.OTPSends{}, errors.New("db down"))
+	s.mockResetRepo.On("StoreResetRequest", s.ctx, mock.AnythingOfType("userpkg.PasswordReset")).Return(nil)
+
+	s.NoError(s.usecase.SendResetOTP(s.ctx, otpEmail, otpIP))
+}
+
+func (s *otpThrottleTestSuite) TestSendVerificationOTP_ReusesValidCodeWithinCooldown() {
+	s.mockSends.On("GetOTPSends", s.ctx, "verify:email:"+otpEmail).
+		Return(userpkg.OTPSends{Count: 1, LastSentAt: time.Now().Add(-5 * time.Second)}, nil)
+	s.mockVerification.On("GetVerification", s.ctx, otpEmail).
+		Return(userpkg.Verification{OTP: "hashed", ExpiresAt: time.Now().Add(14 * time.Minute)}, nil)
+
+	s.NoError(s.usecase.SendVerificationOTP(s.ctx, otpEmail, otpIP))
+}
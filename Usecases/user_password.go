@@ -0,0 +1,83 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	utils "github.com/Amaankaa/Blog-Starter-Project/Domain/utils"
+)
+
+// WithPasswordPolicy checks new passwords against policy at registration,
+// reset and change. Without one the fixed utils.IsStrongPassword rule applies.
+func (uu *UserUsecase) WithPasswordPolicy(policy userpkg.IPasswordPolicy) *UserUsecase {
+	uu.passwordPolicy = policy
+	return uu
+}
+
+func (uu *UserUsecase) validatePassword(password string, user userpkg.User) error {
+	if uu.passwordPolicy == nil {
+		if !utils.IsStrongPassword(password) {
+			return errors.New("password must be at least 8 chars, with upper, lower, number, and special char")
+		}
+		return nil
+	}
+	return uu.passwordPolicy.Validate(password, user)
+}
+
+// passwordHistoryKeep is how many earlier hashes to store besides the current one
+func (uu *UserUsecase) passwordHistoryKeep() int {
+	if uu.passwordPolicy == nil || uu.passwordPolicy.HistorySize() <= 1 {
+		return 0
+	}
+	return uu.passwordPolicy.HistorySize() - 1
+}
+
+// ChangePassword replaces the password of a signed-in user. The current
+// session stays signed in; every other session is ended and outstanding
+// access tokens are revoked, so the caller refreshes once afterwards.
+func (uu *UserUsecase) ChangePassword(ctx context.Context, userID, sessionID, currentPassword, newPassword string) error {
+	user, err := uu.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+	if err := uu.passwordSvc.ComparePassword(user.Password, currentPassword); err != nil {
+		return errors.New("invalid credentials")
+	}
+	if newPassword == currentPassword {
+		return errors.New("new password must be different from the current one")
+	}
+	if err := uu.validatePassword(newPassword, user); err != nil {
+		return err
+	}
+
+	hashed, err := uu.passwordSvc.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	if err := uu.userRepo.UpdatePassword(ctx, userID, hashed, uu.passwordHistoryKeep()); err != nil {
+		return errors.New("failed to update password")
+	}
+	log.Printf("security: password changed user=%s", userID)
+
+	if sessionID == "" {
+		err = uu.tokenRepo.DeleteTokensByUserID(ctx, userID)
+	} else {
+		err = uu.RevokeOtherSessions(ctx, userID, sessionID)
+	}
+	if err != nil {
+		return errors.New("password updated but failed to end other sessions")
+	}
+	if err := uu.deleteAllAccessTokens(ctx, userID); err != nil {
+		return errors.New("password updated but failed to revoke personal access tokens")
+	}
+	_ = uu.revokeAccessTokens(ctx, userID)
+
+	_ = uu.emailSender.SendEmail(user.Email, user.Locale, services.TemplatePasswordChanged, services.EmailData{
+		"Name":          user.Username,
+		"SessionsEnded": true,
+	})
+	return nil
+}
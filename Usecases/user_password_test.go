@@ -0,0 +1,139 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	usecases "github.com/Amaankaa/Blog-Starter-Project/Usecases"
+	"github.com/Amaankaa/Blog-Starter-Project/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type passwordChangeTestSuite struct {
+	suite.Suite
+	ctx             context.Context
+	mockUserRepo    *mocks.IUserRepository
+	mockPasswordSvc *mocks.IPasswordService
+	mockTokenRepo   *mocks.ITokenRepository
+	mockEmailSender *mocks.IEmailSender
+	mockRevocations *mocks.IRevocationStore
+	mockPolicy      *mocks.IPasswordPolicy
+	mockPATs        *mocks.IPersonalAccessTokenRepository
+	usecase         *usecases.UserUsecase
+	user            userpkg.User
+	sessionID       primitive.ObjectID
+}
+
+func TestPasswordChangeTestSuite(t *testing.T) {
+	suite.Run(t, new(passwordChangeTestSuite))
+}
+
+func (s *passwordChangeTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockUserRepo = new(mocks.IUserRepository)
+	s.mockPasswordSvc = new(mocks.IPasswordService)
+	s.mockTokenRepo = new(mocks.ITokenRepository)
+	s.mockEmailSender = new(mocks.IEmailSender)
+	s.mockRevocations = new(mocks.IRevocationStore)
+	s.mockPolicy = new(mocks.IPasswordPolicy)
+	s.mockPATs = new(mocks.IPersonalAccessTokenRepository)
+
+	s.usecase = usecases.NewUserUsecase(
+		s.mockUserRepo,
+		s.mockPasswordSvc,
+		s.mockTokenRepo,
+		new(mocks.IJWTService),
+		new(mocks.IEmailVerifier),
+		s.mockEmailSender,
+		new(mocks.IPasswordResetRepository),
+		new(mocks.IVerificationRepository),
+		new(mocks.ICloudinaryService),
+	).WithRevocationStore(s.mockRevocations).WithPasswordPolicy(s.mockPolicy).
+		WithPersonalAccessTokens(s.mockPATs)
+
+	s.user = userpkg.User{ID: primitive.NewObjectID(), Email: "bob@example.com", Password: "hashed"}
+	s.sessionID = primitive.NewObjectID()
+}
+
+func (s *passwordChangeTestSuite) TearDownTest() {
+	s.mockUserRepo.AssertExpectations(s.T())
+	s.mockPasswordSvc.AssertExpectations(s.T())
+	s.mockTokenRepo.AssertExpectations(s.T())
+	s.mockEmailSender.AssertExpectations(s.T())
+	s.mockRevocations.AssertExpectations(s.T())
+	s.mockPolicy.AssertExpectations(s.T())
+	s.mockPATs.AssertExpectations(s.T())
+}
+
+func (s *passwordChangeTestSuite) TestChangePassword_RevokesOtherSessions() {
+	userID := s.user.ID.Hex()
+	s.mockUserRepo.On("FindByID", s.ctx, userID).Return(s.user, nil)
+	s.mockPasswordSvc.On("ComparePassword", "hashed", "old secret").Return(nil)
+	s.mockPolicy.On("Validate", "a much better passphrase", s.user).Return(nil)
+	s.mockPasswordSvc.On("HashPassword", "a much better passphrase").Return("newhash", nil)
+	s.mockPolicy.On("HistorySize").Return(5)
+	s.mockUserRepo.On("UpdatePassword", s.ctx, userID, "newhash", 4).Return(nil)
+	s.mockTokenRepo.On("DeleteOtherSessions", s.ctx, s.user.ID, s.sessionID).Return(nil)
+	s.mockPATs.On("DeleteAccessTokensByUserID", s.ctx, s.user.ID).Return(nil)
+	s.mockRevocations.On("RevokeUserTokens", s.ctx, userID, mock.AnythingOfType("time.Time")).Return(nil)
+	s.mockEmailSender.On("SendEmail", s.user.Email, "", services.TemplatePasswordChanged, mock.Anything).Return(nil)
+
+	err := s.usecase.ChangePassword(s.ctx, userID, s.sessionID.Hex(), "old secret", "a much better passphrase")
+
+	s.NoError(err)
+}
+
+func (s *passwordChangeTestSuite) TestChangePassword_WithoutSessionEndsAll() {
+	userID := s.user.ID.Hex()
+	s.mockUserRepo.On("FindByID", s.ctx, userID).Return(s.user, nil)
+	s.mockPasswordSvc.On("ComparePassword", "hashed", "old secret").Return(nil)
+	s.mockPolicy.On("Validate", "a much better passphrase", s.user).Return(nil)
+	s.mockPasswordSvc.On("HashPassword", "a much better passphrase").Return("newhash", nil)
+	s.mockPolicy.On("HistorySize").Return(1)
+	s.mockUserRepo.On("UpdatePassword", s.ctx, userID, "newhash", 0).Return(nil)
+	s.mockTokenRepo.On("DeleteTokensByUserID", s.ctx, userID).Return(nil)
+	s.mockPATs.On("DeleteAccessTokensByUserID", s.ctx, s.user.ID).Return(nil)
+	s.mockRevocations.On("RevokeUserTokens", s.ctx, userID, mock.AnythingOfType("time.Time")).Return(nil)
+	s.mockEmailSender.On("SendEmail", s.user.Email, "", services.TemplatePasswordChanged, mock.Anything).Return(nil)
+
+	s.NoError(s.usecase.ChangePassword(s.ctx, userID, "", "old secret", "a much better passphrase"))
+}
+
+func (s *passwordChangeTestSuite) TestChangePassword_WrongCurrentPassword() {
+	userID := s.user.ID.Hex()
+	s.mockUserRepo.On("FindByID", s.ctx, userID).Return(s.user, nil)
+	s.mockPasswordSvc.On("ComparePassword", "hashed", "guess").Return(errors.New("mismatch"))
+
+	err := s.usecase.ChangePassword(s.ctx, userID, s.sessionID.Hex(), "guess", "a much better passphrase")
+
+	s.EqualError(err, "invalid credentials")
+	s.mockUserRepo.AssertNotCalled(s.T(), "UpdatePassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *passwordChangeTestSuite) TestChangePassword_RejectedByPolicy() {
+	userID := s.user.ID.Hex()
+	s.mockUserRepo.On("FindByID", s.ctx, userID).Return(s.user, nil)
+	s.mockPasswordSvc.On("ComparePassword", "hashed", "old secret").Return(nil)
+	s.mockPolicy.On("Validate", "password1", s.user).Return(errors.New("password appears in a list of leaked passwords; choose another"))
+
+	err := s.usecase.ChangePassword(s.ctx, userID, s.sessionID.Hex(), "old secret", "password1")
+
+	s.EqualError(err, "password appears in a list of leaked passwords; choose another")
+	s.mockUserRepo.AssertNotCalled(s.T(), "UpdatePassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	s.mockTokenRepo.AssertNotCalled(s.T(), "DeleteOtherSessions", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *passwordChangeTestSuite) TestChangePassword_SameAsCurrent() {
+	userID := s.user.ID.Hex()
+	s.mockUserRepo.On("FindByID", s.ctx, userID).Return(s.user, nil)
+	s.mockPasswordSvc.On("ComparePassword", "hashed", "old secret").Return(nil)
+
+	err := s.usecase.ChangePassword(s.ctx, userID, s.sessionID.Hex(), "old secret", "old secret")
+
+	s.Error(err)
+}
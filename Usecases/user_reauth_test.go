@@ -0,0 +1,140 @@
+package usecases_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	utils "github.com/Amaankaa/Blog-Starter-Project/Domain/utils"
+	usecases "github.com/Amaankaa/Blog-Starter-Project/Usecases"
+	"github.com/Amaankaa/Blog-Starter-Project/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type reauthTestSuite struct {
+	suite.Suite
+	ctx              context.Context
+	mockUserRepo     *mocks.IUserRepository
+	mockPasswordSvc  *mocks.IPasswordService
+	mockTokenRepo    *mocks.ITokenRepository
+	mockEmailSender  *mocks.IEmailSender
+	mockVerification *mocks.IVerificationRepository
+	mockDeletions    *mocks.IAccountDeletionRepository
+	mockAccessTokens *mocks.IPersonalAccessTokenRepository
+	usecase          *usecases.UserUsecase
+	user             userpkg.User
+	key              string
+}
+
+func TestReauthTestSuite(t *testing.T) {
+	suite.Run(t, new(reauthTestSuite))
+}
+
+func (s *reauthTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockUserRepo = new(mocks.IUserRepository)
+	s.mockPasswordSvc = new(mocks.IPasswordService)
+	s.mockTokenRepo = new(mocks.ITokenRepository)
+	s.mockEmailSender = new(mocks.IEmailSender)
+	s.mockVerification = new(mocks.IVerificationRepository)
+	s.mockDeletions = new(mocks.IAccountDeletionRepository)
+	s.mockAccessTokens = new(mocks.IPersonalAccessTokenRepository)
+
+	s.usecase = usecases.NewUserUsecase(
+		s.mockUserRepo,
+		s.mockPasswordSvc,
+		s.mockTokenRepo,
+		new(mocks.IJWTService),
+		new(mocks.IEmailVerifier),
+		s.mockEmailSender,
+		new(mocks.IPasswordResetRepository),
+		s.mockVerification,
+		new(mocks.ICloudinaryService),
+	).WithAccountDeletion(s.mockDeletions, 7*24*time.Hour).
+		WithPersonalAccessTokens(s.mockAccessTokens).
+		WithCodeKey(testCodeKey)
+
+	// Signed up through OIDC, so there is no password to confirm with
+	s.user = userpkg.User{ID: primitive.NewObjectID(), Email: "oidc@example.com", Username: "oidc"}
+	s.key = "reauth:" + s.user.ID.Hex()
+}
+
+func (s *reauthTestSuite) TearDownTest() {
+	s.mockUserRepo.AssertExpectations(s.T())
+	s.mockPasswordSvc.AssertExpectations(s.T())
+	s.mockTokenRepo.AssertExpectations(s.T())
+	s.mockEmailSender.AssertExpectations(s.T())
+	s.mockVerification.AssertExpectations(s.T())
+	s.mockDeletions.AssertExpectations(s.T())
+	s.mockAccessTokens.AssertExpectations(s.T())
+}
+
+func (s *reauthTestSuite) TestSendReauthCode_EmailsCode() {
+	s.mockUserRepo.On("FindByID", s.ctx, s.user.ID.Hex()).Return(s.user, nil)
+	var sent string
+	s.mockEmailSender.On("SendEmail", s.user.Email, "", services.TemplateReauthCode, mock.MatchedBy(func(d services.EmailData) bool {
+		sent, _ = d["Code"].(string)
+		return len(sent) == 6
+	})).Return(nil)
+	s.mockVerification.On("StoreVerification", s.ctx, mock.MatchedBy(func(v userpkg.Verification) bool {
+		return v.Email == s.key && v.OTP != "" && time.Until(v.ExpiresAt) > 9*time.Minute
+	})).Return(nil)
+
+	s.Require().NoError(s.usecase.SendReauthCode(s.ctx, s.user.ID.Hex(), "203.0.113.7"))
+
+	stored := s.mockVerification.Calls[0].Arguments.Get(1).(userpkg.Verification)
+	s.NotEqual(sent, stored.OTP, "only the hash is stored")
+	s.True(utils.CodeMatches(testCodeKey, stored.OTP, sent))
+}
+
+func (s *reauthTestSuite) TestSendReauthCode_RefusesPasswordAccounts() {
+	s.user.Password = "hashed"
+	s.mockUserRepo.On("FindByID", s.ctx, s.user.ID.Hex()).Return(s.user, nil)
+
+	s.Error(s.usecase.SendReauthCode(s.ctx, s.user.ID.Hex(), ""))
+}
+
+func (s *reauthTestSuite) TestRequestAccountDeletion_WithCode() {
+	userID := s.user.ID.Hex()
+	s.mockUserRepo.On("FindByID", s.ctx, userID).Return(s.user, nil)
+	s.mockVerification.On("GetVerification", s.ctx, s.key).Return(userpkg.Verification{
+		Email: s.key, OTP: utils.HashCode(testCodeKey, "123456"), ExpiresAt: time.Now().Add(time.Minute),
+	}, nil)
+	s.mockVerification.On("DeleteVerification", s.ctx, s.key).Return(nil)
+	s.mockDeletions.On("ScheduleDeletion", s.ctx, mock.Anything).Return(nil)
+	s.mockTokenRepo.On("DeleteTokensByUserID", s.ctx, userID).Return(nil)
+	s.mockAccessTokens.On("DeleteAccessTokensByUserID", s.ctx, s.user.ID).Return(nil)
+	s.mockEmailSender.On("SendEmail", s.user.Email, "", services.TemplateDeletionScheduled, mock.Anything).Return(nil)
+
+	_, err := s.usecase.RequestAccountDeletion(s.ctx, userID, "123456", userpkg.DeletionContentDelete)
+
+	s.NoError(err)
+}
+
+func (s *reauthTestSuite) TestRequestAccountDeletion_WrongCode() {
+	userID := s.user.ID.Hex()
+	s.mockUserRepo.On("FindByID", s.ctx, userID).Return(s.user, nil)
+	s.mockVerification.On("GetVerification", s.ctx, s.key).Return(userpkg.Verification{
+		Email: s.key, OTP: utils.HashCode(testCodeKey, "123456"), ExpiresAt: time.Now().Add(time.Minute),
+	}, nil)
+	s.mockVerification.On("IncrementAttemptCount", s.ctx, s.key).Return(nil)
+
+	_, err := s.usecase.RequestAccountDeletion(s.ctx, userID, "654321", userpkg.DeletionContentDelete)
+
+	s.EqualError(err, "invalid credentials")
+}
+
+func (s *reauthTestSuite) TestRequestAccountDeletion_NoCodeRequested() {
+	userID := s.user.ID.Hex()
+	s.mockUserRepo.On("FindByID", s.ctx, userID).Return(s.user, nil)
+	s.mockVerification.On("GetVerification", s.ctx, s.key).Return(userpkg.Verification{}, errors.New("not found"))
+
+	_, err := s.usecase.RequestAccountDeletion(s.ctx, userID, "", userpkg.DeletionContentDelete)
+
+	s.EqualError(err, "invalid credentials")
+}
@@ -0,0 +1,79 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	auditpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/audit"
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+)
+
+// GrantRole gives the target user role, replacing their current one. Their
+// access tokens are revoked so the new permissions apply from the next refresh.
+func (uu *UserUsecase) GrantRole(ctx context.Context, targetUserID, role, actorUserID string) error {
+	if role == userpkg.RoleUser || !userpkg.IsValidRole(role) {
+		return errors.New("unknown role")
+	}
+	target, err := uu.loadRoleTarget(ctx, targetUserID, actorUserID)
+	if err != nil {
+		return err
+	}
+	if target.Role == role {
+		return nil
+	}
+	if err := uu.userRepo.UpdateRoleAndPromoter(ctx, targetUserID, role, &actorUserID); err != nil {
+		return err
+	}
+	log.Printf("security: role granted user=%s role=%s by=%s", targetUserID, role, actorUserID)
+	uu.auditRoleChange(ctx, auditpkg.ActionRoleGrant, target, role, actorUserID)
+	return uu.revokeAccessTokens(ctx, targetUserID)
+}
+
+// RevokeRole takes role away from the target user, leaving them a regular user
+func (uu *UserUsecase) RevokeRole(ctx context.Context, targetUserID, role, actorUserID string) error {
+	target, err := uu.loadRoleTarget(ctx, targetUserID, actorUserID)
+	if err != nil {
+		return err
+	}
+	if role == userpkg.RoleUser || target.Role != role {
+		return errors.New("user does not have this role")
+	}
+	if err := uu.userRepo.UpdateRoleAndPromoter(ctx, targetUserID, userpkg.RoleUser, nil); err != nil {
+		return err
+	}
+	log.Printf("security: role revoked user=%s role=%s by=%s", targetUserID, role, actorUserID)
+	uu.auditRoleChange(ctx, auditpkg.ActionRoleRevoke, target, userpkg.RoleUser, actorUserID)
+	return uu.revokeAccessTokens(ctx, targetUserID)
+}
+
+// loadRoleTarget applies the rules shared by every role change: nobody
+// changes their own role or the role of whoever promoted them
+func (uu *UserUsecase) loadRoleTarget(ctx context.Context, targetUserID, actorUserID string) (userpkg.User, error) {
+	if targetUserID == actorUserID {
+		return userpkg.User{}, errors.New("cannot change your own role")
+	}
+	target, err := uu.userRepo.FindByID(ctx, targetUserID)
+	if err != nil {
+		return userpkg.User{}, err
+	}
+	actor, err := uu.userRepo.FindByID(ctx, actorUserID)
+	if err != nil {
+		return userpkg.User{}, err
+	}
+	if !actor.PromotedBy.IsZero() && actor.PromotedBy == target.ID {
+		return userpkg.User{}, errors.New("cannot act on your promoter")
+	}
+	return target, nil
+}
+
+func (uu *UserUsecase) auditRoleChange(ctx context.Context, action string, target userpkg.User, newRole, actorUserID string) {
+	recordAudit(ctx, uu.audit, auditpkg.Event{
+		ActorID:    actorUserID,
+		Action:     action,
+		TargetType: auditpkg.TargetUser,
+		TargetID:   target.ID.Hex(),
+		Before:     map[string]string{"role": target.Role},
+		After:      map[string]string{"role": newRole},
+	})
+}
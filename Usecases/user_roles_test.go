@@ -0,0 +1,135 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+
+	auditpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/audit"
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	usecases "github.com/Amaankaa/Blog-Starter-Project/Usecases"
+	"github.com/Amaankaa/Blog-Starter-Project/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type rolesTestSuite struct {
+	suite.Suite
+	ctx             context.Context
+	mockUserRepo    *mocks.IUserRepository
+	mockRevocations *mocks.IRevocationStore
+	usecase         *usecases.UserUsecase
+	admin           userpkg.User
+	target          userpkg.User
+}
+
+func TestRolesTestSuite(t *testing.T) {
+	suite.Run(t, new(rolesTestSuite))
+}
+
+func (s *rolesTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockUserRepo = new(mocks.IUserRepository)
+	s.mockRevocations = new(mocks.IRevocationStore)
+
+	s.usecase = usecases.NewUserUsecase(
+		s.mockUserRepo,
+		new(mocks.IPasswordService),
+		new(mocks.ITokenRepository),
+		new(mocks.IJWTService),
+		new(mocks.IEmailVerifier),
+		new(mocks.IEmailSender),
+		new(mocks.IPasswordResetRepository),
+		new(mocks.IVerificationRepository),
+		new(mocks.ICloudinaryService),
+	).WithRevocationStore(s.mockRevocations)
+
+	s.admin = userpkg.User{ID: primitive.NewObjectID(), Role: userpkg.RoleAdmin}
+	s.target = userpkg.User{ID: primitive.NewObjectID(), Role: userpkg.RoleUser}
+}
+
+func (s *rolesTestSuite) TearDownTest() {
+	s.mockUserRepo.AssertExpectations(s.T())
+	s.mockRevocations.AssertExpectations(s.T())
+}
+
+func (s *rolesTestSuite) expectUsers() {
+	s.mockUserRepo.On("FindByID", s.ctx, s.target.ID.Hex()).Return(s.target, nil)
+	s.mockUserRepo.On("FindByID", s.ctx, s.admin.ID.Hex()).Return(s.admin, nil)
+}
+
+func (s *rolesTestSuite) TestGrantRole_RevokesAccessTokens() {
+	s.expectUsers()
+	adminID := s.admin.ID.Hex()
+	s.mockUserRepo.On("UpdateRoleAndPromoter", s.ctx, s.target.ID.Hex(), userpkg.RoleVerifier, &adminID).Return(nil)
+	s.mockRevocations.On("RevokeUserTokens", s.ctx, s.target.ID.Hex(), mock.AnythingOfType("time.Time")).Return(nil)
+
+	s.NoError(s.usecase.GrantRole(s.ctx, s.target.ID.Hex(), userpkg.RoleVerifier, adminID))
+}
+
+func (s *rolesTestSuite) TestGrantRole_RecordsAudit() {
+	s.expectUsers()
+	adminID := s.admin.ID.Hex()
+	audit := new(mocks.IAuditLogger)
+	s.usecase.WithAuditLogger(audit)
+	s.mockUserRepo.On("UpdateRoleAndPromoter", s.ctx, s.target.ID.Hex(), userpkg.RoleModerator, &adminID).Return(nil)
+	s.mockRevocations.On("RevokeUserTokens", s.ctx, s.target.ID.Hex(), mock.AnythingOfType("time.Time")).Return(nil)
+	audit.On("Record", s.ctx, auditpkg.Event{
+		ActorID:    adminID,
+		Action:     auditpkg.ActionRoleGrant,
+		TargetType: auditpkg.TargetUser,
+		TargetID:   s.target.ID.Hex(),
+		Before:     map[string]string{"role": userpkg.RoleUser},
+		After:      map[string]string{"role": userpkg.RoleModerator},
+	}).Return(nil)
+
+	s.NoError(s.usecase.GrantRole(s.ctx, s.target.ID.Hex(), userpkg.RoleModerator, adminID))
+	audit.AssertExpectations(s.T())
+}
+
+func (s *rolesTestSuite) TestGrantRole_UnknownRole() {
+	s.EqualError(s.usecase.GrantRole(s.ctx, s.target.ID.Hex(), "superuser", s.admin.ID.Hex()), "unknown role")
+	s.EqualError(s.usecase.GrantRole(s.ctx, s.target.ID.Hex(), userpkg.RoleUser, s.admin.ID.Hex()), "unknown role")
+}
+
+func (s *rolesTestSuite) TestGrantRole_Self() {
+	err := s.usecase.GrantRole(s.ctx, s.admin.ID.Hex(), userpkg.RoleModerator, s.admin.ID.Hex())
+
+	s.EqualError(err, "cannot change your own role")
+}
+
+func (s *rolesTestSuite) TestGrantRole_PromoterIsProtected() {
+	s.admin.PromotedBy = s.target.ID
+	s.target.Role = userpkg.RoleAdmin
+	s.expectUsers()
+
+	err := s.usecase.GrantRole(s.ctx, s.target.ID.Hex(), userpkg.RoleModerator, s.admin.ID.Hex())
+
+	s.EqualError(err, "cannot act on your promoter")
+}
+
+func (s *rolesTestSuite) TestRevokeRole_LeavesRegularUser() {
+	s.target.Role = userpkg.RoleModerator
+	s.expectUsers()
+	s.mockUserRepo.On("UpdateRoleAndPromoter", s.ctx, s.target.ID.Hex(), userpkg.RoleUser, (*string)(nil)).Return(nil)
+	s.mockRevocations.On("RevokeUserTokens", s.ctx, s.target.ID.Hex(), mock.AnythingOfType("time.Time")).Return(nil)
+
+	s.NoError(s.usecase.RevokeRole(s.ctx, s.target.ID.Hex(), userpkg.RoleModerator, s.admin.ID.Hex()))
+}
+
+func (s *rolesTestSuite) TestRevokeRole_NotHeld() {
+	s.target.Role = userpkg.RoleVerifier
+	s.expectUsers()
+
+	err := s.usecase.RevokeRole(s.ctx, s.target.ID.Hex(), userpkg.RoleModerator, s.admin.ID.Hex())
+
+	s.EqualError(err, "user does not have this role")
+	s.mockUserRepo.AssertNotCalled(s.T(), "UpdateRoleAndPromoter", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *rolesTestSuite) TestPermissionsForRole() {
+	s.ElementsMatch([]string{userpkg.PermResourcesVerify}, userpkg.PermissionsForRole(userpkg.RoleVerifier))
+	s.Contains(userpkg.PermissionsForRole(userpkg.RoleAdmin), userpkg.PermContentModerate)
+	s.Empty(userpkg.PermissionsForRole(userpkg.RoleUser))
+	s.Empty(userpkg.PermissionsForRole("unknown"))
+}
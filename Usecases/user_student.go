@@ -0,0 +1,177 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	emailpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/email"
+	institutionpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/institution"
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	utils "github.com/Amaankaa/Blog-Starter-Project/Domain/utils"
+)
+
+// studentCodeTTL is how long a code sent to an institutional address is valid
+const studentCodeTTL = 30 * time.Minute
+
+const otpPurposeStudent = "student"
+
+// WithStudentVerification lets users prove they study at one of the
+// registered institutions by confirming an address at its email domains
+func (uu *UserUsecase) WithStudentVerification(institutions institutionpkg.IInstitutionRepository) *UserUsecase {
+	uu.institutions = institutions
+	return uu
+}
+
+// The code is stored per user and address, so it cannot collide with the
+// signup code for the same address or be confirmed by another account
+func studentVerificationKey(userID, email string) string {
+	return "student:" + userID + ":" + email
+}
+
+// RequestStudentVerification sends a code to email, which must be at a
+// registered institution's domain and not verified by another account. The
+// account email is unaffected.
+func (uu *UserUsecase) RequestStudentVerification(ctx context.Context, userID, email, clientIP string) (userpkg.StudentVerification, error) {
+	if uu.institutions == nil {
+		return userpkg.StudentVerification{}, errors.New("student verification is not enabled")
+	}
+	email = strings.ToLower(strings.TrimSpace(email))
+	if !utils.IsValidEmail(email) {
+		return userpkg.StudentVerification{}, errors.New("invalid email format")
+	}
+
+	user, err := uu.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return userpkg.StudentVerification{}, errors.New("user not found")
+	}
+	if user.Student != nil && user.Student.Email == email {
+		return userpkg.StudentVerification{}, errors.New("this address is already verified")
+	}
+	institution, err := uu.institutionForEmail(ctx, email)
+	if err != nil {
+		return userpkg.StudentVerification{}, err
+	}
+	taken, err := uu.userRepo.StudentEmailTaken(ctx, email, userID)
+	if err != nil {
+		return userpkg.StudentVerification{}, errors.New("failed to check student email: " + err.Error())
+	}
+	if taken {
+		return userpkg.StudentVerification{}, userpkg.ErrStudentEmailTaken
+	}
+
+	if _, err := uu.checkOTPSend(ctx, otpPurposeStudent, email, clientIP, func() bool { return false }); err != nil {
+		return userpkg.StudentVerification{}, err
+	}
+
+	otp := utils.GenerateOTP(6)
+	hashed := uu.hashCode(otp)
+	expiresAt := time.Now().Add(studentCodeTTL)
+	if err := uu.verificationRepo.StoreVerification(ctx, userpkg.Verification{
+		Email:     studentVerificationKey(userID, email),
+		OTP:       hashed,
+		ExpiresAt: expiresAt,
+	}); err != nil {
+		return userpkg.StudentVerification{}, errors.New("failed to store verification code")
+	}
+
+	if err := uu.emailSender.SendEmail(email, user.Locale, services.TemplateStudentEmailCode, services.EmailData{
+		"Name":             user.Username,
+		"Code":             otp,
+		"Institution":      institution.Name,
+		"ExpiresInMinutes": int(studentCodeTTL / time.Minute),
+	}); err != nil {
+		return userpkg.StudentVerification{}, errors.New("failed to send verification code")
+	}
+	uu.recordOTPSend(ctx, otpPurposeStudent, email, clientIP)
+
+	return userpkg.StudentVerification{
+		Email:       email,
+		Institution: userpkg.InstitutionSummary{ID: institution.ID, Name: institution.Name},
+		ExpiresAt:   expiresAt,
+	}, nil
+}
+
+// ConfirmStudentVerification checks the code sent to email and makes the
+// user a verified student of its institution, replacing any earlier status
+func (uu *UserUsecase) ConfirmStudentVerification(ctx context.Context, userID, email, otp string) (userpkg.User, error) {
+	if uu.institutions == nil {
+		return userpkg.User{}, errors.New("student verification is not enabled")
+	}
+	email = strings.ToLower(strings.TrimSpace(email))
+	key := studentVerificationKey(userID, email)
+
+	v, err := uu.verificationRepo.GetVerification(ctx, key)
+	if err != nil {
+		return userpkg.User{}, errors.New("no verification found")
+	}
+	if time.Now().After(v.ExpiresAt) {
+		_ = uu.verificationRepo.DeleteVerification(ctx, key)
+		return userpkg.User{}, errors.New("verification code expired")
+	}
+	if v.AttemptCount >= 5 {
+		_ = uu.verificationRepo.DeleteVerification(ctx, key)
+		return userpkg.User{}, errors.New("too many invalid attempts")
+	}
+	if !uu.codeMatches(v.OTP, otp) {
+		_ = uu.verificationRepo.IncrementAttemptCount(ctx, key)
+		return userpkg.User{}, errors.New("invalid code")
+	}
+
+	// The domain may have been removed, or the address verified by someone
+	// else, since the code was sent
+	institution, err := uu.institutionForEmail(ctx, email)
+	if err != nil {
+		return userpkg.User{}, err
+	}
+	taken, err := uu.userRepo.StudentEmailTaken(ctx, email, userID)
+	if err != nil {
+		return userpkg.User{}, errors.New("failed to check student email: " + err.Error())
+	}
+	if taken {
+		return userpkg.User{}, userpkg.ErrStudentEmailTaken
+	}
+
+	if err := uu.userRepo.UpdateStudentStatus(ctx, userID, &userpkg.StudentStatus{
+		InstitutionID: institution.ID,
+		Institution:   institution.Name,
+		Email:         email,
+		VerifiedAt:    time.Now(),
+	}); err != nil {
+		return userpkg.User{}, err
+	}
+	_ = uu.verificationRepo.DeleteVerification(ctx, key)
+	log.Printf("students: user=%s verified at institution=%s", userID, institution.ID.Hex())
+
+	user, err := uu.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return userpkg.User{}, err
+	}
+	user.Password = ""
+	return user, nil
+}
+
+// RemoveStudentStatus drops the user's verified student status
+func (uu *UserUsecase) RemoveStudentStatus(ctx context.Context, userID string) error {
+	return uu.userRepo.UpdateStudentStatus(ctx, userID, nil)
+}
+
+// institutionForEmail finds the institution owning the most specific domain
+// of email
+func (uu *UserUsecase) institutionForEmail(ctx context.Context, email string) (institutionpkg.Institution, error) {
+	domain, ok := emailpkg.NormalizeDomain(email[strings.LastIndexByte(email, '@')+1:])
+	if !ok {
+		return institutionpkg.Institution{}, errors.New("invalid email format")
+	}
+	institution, found, err := uu.institutions.FindByDomain(ctx, emailpkg.DomainAndParents(domain))
+	if err != nil {
+		return institutionpkg.Institution{}, errors.New("failed to look up institution: " + err.Error())
+	}
+	if !found {
+		return institutionpkg.Institution{}, userpkg.ErrNoInstitutionForEmail
+	}
+	return institution, nil
+}
@@ -0,0 +1,161 @@
+package usecases_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	institutionpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/institution"
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	utils "github.com/Amaankaa/Blog-Starter-Project/Domain/utils"
+	usecases "github.com/Amaankaa/Blog-Starter-Project/Usecases"
+	"github.com/Amaankaa/Blog-Starter-Project/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type studentVerificationTestSuite struct {
+	suite.Suite
+	ctx              context.Context
+	mockUserRepo     *mocks.IUserRepository
+	mockPasswordSvc  *mocks.IPasswordService
+	mockEmailSender  *mocks.IEmailSender
+	mockVerification *mocks.IVerificationRepository
+	mockInstitutions *mocks.IInstitutionRepository
+	usecase          *usecases.UserUsecase
+	user             userpkg.User
+	institution      institutionpkg.Institution
+	key              string
+}
+
+const studentAddress = "selam@cs.aau.edu.et"
+
+func TestStudentVerificationTestSuite(t *testing.T) {
+	suite.Run(t, new(studentVerificationTestSuite))
+}
+
+func (s *studentVerificationTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.mockUserRepo = new(mocks.IUserRepository)
+	s.mockPasswordSvc = new(mocks.IPasswordService)
+	s.mockEmailSender = new(mocks.IEmailSender)
+	s.mockVerification = new(mocks.IVerificationRepository)
+	s.mockInstitutions = new(mocks.IInstitutionRepository)
+
+	s.usecase = usecases.NewUserUsecase(
+		s.mockUserRepo,
+		s.mockPasswordSvc,
+		new(mocks.ITokenRepository),
+		new(mocks.IJWTService),
+		new(mocks.IEmailVerifier),
+		s.mockEmailSender,
+		new(mocks.IPasswordResetRepository),
+		s.mockVerification,
+		new(mocks.ICloudinaryService),
+	).WithStudentVerification(s.mockInstitutions).
+		WithCodeKey(testCodeKey)
+
+	s.user = userpkg.User{ID: primitive.NewObjectID(), Username: "selam", Email: "selam@gmail.com", Password: "hashed"}
+	s.institution = institutionpkg.Institution{ID: primitive.NewObjectID(), Name: "Addis Ababa University", Domains: []string{"aau.edu.et"}}
+	s.key = "student:" + s.user.ID.Hex() + ":" + studentAddress
+}
+
+func (s *studentVerificationTestSuite) TearDownTest() {
+	s.mockUserRepo.AssertExpectations(s.T())
+	s.mockPasswordSvc.AssertExpectations(s.T())
+	s.mockEmailSender.AssertExpectations(s.T())
+	s.mockVerification.AssertExpectations(s.T())
+	s.mockInstitutions.AssertExpectations(s.T())
+}
+
+func (s *studentVerificationTestSuite) expectInstitutionLookup() {
+	s.mockInstitutions.On("FindByDomain", s.ctx, []string{"cs.aau.edu.et", "aau.edu.et", "edu.et", "et"}).
+		Return(s.institution, true, nil)
+}
+
+func (s *studentVerificationTestSuite) TestRequest_SendsCodeToInstitutionalAddress() {
+	userID := s.user.ID.Hex()
+	s.mockUserRepo.On("FindByID", s.ctx, userID).Return(s.user, nil)
+	s.expectInstitutionLookup()
+	s.mockUserRepo.On("StudentEmailTaken", s.ctx, studentAddress, userID).Return(false, nil)
+	var stored userpkg.Verification
+	s.mockVerification.On("StoreVerification", s.ctx, mock.MatchedBy(func(v userpkg.Verification) bool {
+		return v.Email == s.key
+	})).Run(func(args mock.Arguments) { stored = args.Get(1).(userpkg.Verification) }).Return(nil)
+	var data services.EmailData
+	s.mockEmailSender.On("SendEmail", studentAddress, "", services.TemplateStudentEmailCode, mock.Anything).
+		Run(func(args mock.Arguments) { data = args.Get(3).(services.EmailData) }).Return(nil)
+
+	pending, err := s.usecase.RequestStudentVerification(s.ctx, userID, " Selam@CS.aau.edu.et ", "")
+
+	s.Require().NoError(err)
+	s.Equal(studentAddress, pending.Email)
+	s.Equal(s.institution.ID, pending.Institution.ID)
+	s.Equal("Addis Ababa University", data["Institution"])
+	s.Equal(utils.HashCode(testCodeKey, data["Code"].(string)), stored.OTP)
+}
+
+func (s *studentVerificationTestSuite) TestRequest_UnknownDomain() {
+	s.mockUserRepo.On("FindByID", s.ctx, s.user.ID.Hex()).Return(s.user, nil)
+	s.mockInstitutions.On("FindByDomain", s.ctx, mock.Anything).Return(institutionpkg.Institution{}, false, nil)
+
+	_, err := s.usecase.RequestStudentVerification(s.ctx, s.user.ID.Hex(), "selam@gmail.com", "")
+
+	s.ErrorIs(err, userpkg.ErrNoInstitutionForEmail)
+	s.mockEmailSender.AssertNotCalled(s.T(), "SendEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *studentVerificationTestSuite) TestRequest_AddressVerifiedByAnotherAccount() {
+	userID := s.user.ID.Hex()
+	s.mockUserRepo.On("FindByID", s.ctx, userID).Return(s.user, nil)
+	s.expectInstitutionLookup()
+	s.mockUserRepo.On("StudentEmailTaken", s.ctx, studentAddress, userID).Return(true, nil)
+
+	_, err := s.usecase.RequestStudentVerification(s.ctx, userID, studentAddress, "")
+
+	s.ErrorIs(err, userpkg.ErrStudentEmailTaken)
+}
+
+func (s *studentVerificationTestSuite) TestConfirm_MakesUserVerifiedStudent() {
+	userID := s.user.ID.Hex()
+	s.mockVerification.On("GetVerification", s.ctx, s.key).
+		Return(userpkg.Verification{Email: s.key, OTP: utils.HashCode(testCodeKey, "123456"), ExpiresAt: time.Now().Add(time.Minute)}, nil)
+	s.expectInstitutionLookup()
+	s.mockUserRepo.On("StudentEmailTaken", s.ctx, studentAddress, userID).Return(false, nil)
+	s.mockUserRepo.On("UpdateStudentStatus", s.ctx, userID, mock.MatchedBy(func(st *userpkg.StudentStatus) bool {
+		return st.InstitutionID == s.institution.ID && st.Institution == s.institution.Name && st.Email == studentAddress
+	})).Return(nil)
+	s.mockVerification.On("DeleteVerification", s.ctx, s.key).Return(nil)
+	verified := s.user
+	verified.Student = &userpkg.StudentStatus{InstitutionID: s.institution.ID, Institution: s.institution.Name, Email: studentAddress}
+	s.mockUserRepo.On("FindByID", s.ctx, userID).Return(verified, nil)
+
+	user, err := s.usecase.ConfirmStudentVerification(s.ctx, userID, studentAddress, "123456")
+
+	s.Require().NoError(err)
+	s.Empty(user.Password)
+	s.Equal(s.institution.ID, user.Student.InstitutionID)
+}
+
+func (s *studentVerificationTestSuite) TestConfirm_WrongCode() {
+	s.mockVerification.On("GetVerification", s.ctx, s.key).
+		Return(userpkg.Verification{Email: s.key, OTP: utils.HashCode(testCodeKey, "123456"), ExpiresAt: time.Now().Add(time.Minute)}, nil)
+	s.mockVerification.On("IncrementAttemptCount", s.ctx, s.key).Return(nil)
+
+	_, err := s.usecase.ConfirmStudentVerification(s.ctx, s.user.ID.Hex(), studentAddress, "000000")
+
+	s.EqualError(err, "invalid code")
+	s.mockUserRepo.AssertNotCalled(s.T(), "UpdateStudentStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *studentVerificationTestSuite) TestConfirm_DomainRemovedSinceRequest() {
+	s.mockVerification.On("GetVerification", s.ctx, s.key).
+		Return(userpkg.Verification{Email: s.key, OTP: utils.HashCode(testCodeKey, "123456"), ExpiresAt: time.Now().Add(time.Minute)}, nil)
+	s.mockInstitutions.On("FindByDomain", s.ctx, mock.Anything).Return(institutionpkg.Institution{}, false, nil)
+
+	_, err := s.usecase.ConfirmStudentVerification(s.ctx, s.user.ID.Hex(), studentAddress, "123456")
+
+	s.ErrorIs(err, userpkg.ErrNoInstitutionForEmail)
+}
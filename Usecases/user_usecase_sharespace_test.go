@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	institutionpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/institution"
 	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
 	usecases "github.com/Amaankaa/Blog-Starter-Project/Usecases"
 	"github.com/Amaankaa/Blog-Starter-Project/mocks"
@@ -100,9 +101,9 @@ func (s *userShareSpaceUsecaseTestSuite) TestFindMentors_Success() {
 		},
 	}
 
-	s.mockUserRepo.On("FindMentors", s.ctx, topics, limit, offset).Return(expectedProfiles, nil)
+	s.mockUserRepo.On("FindMentors", s.ctx, topics, primitive.NilObjectID, limit, offset).Return(expectedProfiles, nil)
 
-	result, err := s.usecase.FindMentors(s.ctx, topics, limit, offset)
+	result, err := s.usecase.FindMentors(s.ctx, topics, "", limit, offset)
 
 	s.NoError(err)
 	s.Equal(expectedProfiles, result)
@@ -117,15 +118,27 @@ func (s *userShareSpaceUsecaseTestSuite) TestFindMentors_DefaultLimit() {
 	expectedProfiles := []userpkg.PublicProfile{}
 
 	// Should use default limit of 20
-	s.mockUserRepo.On("FindMentors", s.ctx, topics, 20, offset).Return(expectedProfiles, nil)
+	s.mockUserRepo.On("FindMentors", s.ctx, topics, primitive.NilObjectID, 20, offset).Return(expectedProfiles, nil)
 
-	result, err := s.usecase.FindMentors(s.ctx, topics, limit, offset)
+	result, err := s.usecase.FindMentors(s.ctx, topics, "", limit, offset)
 
 	s.NoError(err)
 	s.Equal(expectedProfiles, result)
 	s.mockUserRepo.AssertExpectations(s.T())
 }
 
+func (s *userShareSpaceUsecaseTestSuite) TestFindMentors_ByInstitution() {
+	institutionID := primitive.NewObjectID()
+	s.mockUserRepo.On("FindMentors", s.ctx, []string(nil), institutionID, 20, 0).Return([]userpkg.PublicProfile{}, nil)
+
+	_, err := s.usecase.FindMentors(s.ctx, nil, institutionID.Hex(), 0, 0)
+	s.NoError(err)
+
+	_, err = s.usecase.FindMentors(s.ctx, nil, "not-an-id", 0, 0)
+	s.ErrorIs(err, institutionpkg.ErrInstitutionNotFound)
+	s.mockUserRepo.AssertExpectations(s.T())
+}
+
 func (s *userShareSpaceUsecaseTestSuite) TestFindMentees_Success() {
 	topics := []string{"Study Techniques"}
 	limit := 5
@@ -8,7 +8,9 @@ import (
 
 	"strings"
 
+	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
 	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
+	utils "github.com/Amaankaa/Blog-Starter-Project/Domain/utils"
 	usecases "github.com/Amaankaa/Blog-Starter-Project/Usecases"
 	"github.com/Amaankaa/Blog-Starter-Project/mocks"
 	"github.com/stretchr/testify/mock"
@@ -28,9 +30,13 @@ type UserUsecaseTestSuite struct {
 	mockResetRepo        *mocks.IPasswordResetRepository
 	mockVerificationRepo *mocks.IVerificationRepository
 	mockCloudinaryService *mocks.ICloudinaryService
+	mockRevocationStore  *mocks.IRevocationStore
 	usecase              *usecases.UserUsecase
 }
 
+// testCodeKey keys the hashes of emailed codes in the usecase tests
+var testCodeKey = []byte("test-code-key")
+
 func TestUserUsecaseTestSuite(t *testing.T) {
 	suite.Run(t, new(UserUsecaseTestSuite))
 }
@@ -47,6 +53,7 @@ func (s *UserUsecaseTestSuite) SetupTest() {
 	s.mockResetRepo = new(mocks.IPasswordResetRepository)
 	s.mockVerificationRepo = new(mocks.IVerificationRepository)
 	s.mockCloudinaryService = new(mocks.ICloudinaryService)
+	s.mockRevocationStore = new(mocks.IRevocationStore)
 
 	s.usecase = usecases.NewUserUsecase(
 		s.mockUserRepo,
@@ -58,7 +65,8 @@ func (s *UserUsecaseTestSuite) SetupTest() {
 		s.mockResetRepo,
 		s.mockVerificationRepo,
 		s.mockCloudinaryService,
-	)
+	).WithRevocationStore(s.mockRevocationStore).
+		WithCodeKey(testCodeKey)
 }
 
 func (s *UserUsecaseTestSuite) TestRegisterFirstUserAsAdmin() {
@@ -85,12 +93,11 @@ func (s *UserUsecaseTestSuite) TestRegisterFirstUserAsAdmin() {
 	}).Return(expectedUser, nil)
 
 	// Mock email sending for verification
-	s.mockEmailSender.On("SendEmail", testUser.Email, "Email Verification Code", mock.MatchedBy(func(body string) bool {
-		return strings.Contains(body, "Your verification OTP:")
+	s.mockEmailSender.On("SendEmail", testUser.Email, "", services.TemplateVerification, mock.MatchedBy(func(data services.EmailData) bool {
+		return len(data["Code"].(string)) == 6
 	})).Return(nil)
 
-	// Mock OTP hashing and verification storage
-	s.mockPasswordSvc.On("HashPassword", mock.Anything).Return("hashedOTP", nil)
+	// Mock verification storage
 	s.mockVerificationRepo.On("StoreVerification", s.ctx, mock.Anything).Return(nil)
 
 	// Act
@@ -128,12 +135,11 @@ func (s *UserUsecaseTestSuite) TestRegisterSecondUserAsNormal() {
 	}).Return(expectedUser, nil)
 
 	// Mock email sending for verification
-	s.mockEmailSender.On("SendEmail", testUser.Email, "Email Verification Code", mock.MatchedBy(func(body string) bool {
-		return strings.Contains(body, "Your verification OTP:")
+	s.mockEmailSender.On("SendEmail", testUser.Email, "", services.TemplateVerification, mock.MatchedBy(func(data services.EmailData) bool {
+		return len(data["Code"].(string)) == 6
 	})).Return(nil)
 
-	// Mock OTP hashing and verification storage
-	s.mockPasswordSvc.On("HashPassword", mock.Anything).Return("hashedOTP", nil)
+	// Mock verification storage
 	s.mockVerificationRepo.On("StoreVerification", s.ctx, mock.Anything).Return(nil)
 
 	// Act
@@ -164,6 +170,20 @@ func (s *UserUsecaseTestSuite) TestRejectsInvalidEmailFormat() {
 	s.Equal("invalid email format", err.Error())
 }
 
+func (s *UserUsecaseTestSuite) TestRejectsTombstoneUsername() {
+	testUser := userpkg.User{
+		Username: "Deleted-User",
+		Email:    "someone@example.com",
+		Password: "ValidPass123!",
+		Fullname: "Test User",
+	}
+
+	_, err := s.usecase.RegisterUser(s.ctx, testUser)
+
+	s.EqualError(err, "username already taken")
+	s.mockUserRepo.AssertNotCalled(s.T(), "ExistsByUsername", mock.Anything, mock.Anything)
+}
+
 func (s *UserUsecaseTestSuite) TestRejectWeakPassword() {
 	// Arrange
 	testUser := userpkg.User{
@@ -299,24 +319,53 @@ func (s *UserUsecaseTestSuite) TestLoginUser_Success() {
 
 	s.mockUserRepo.On("GetUserByLogin", s.ctx, login).Return(testUser, nil)
 	s.mockPasswordSvc.On("ComparePassword", hashedPassword, password).Return(nil)
-	s.mockJWTService.On("GenerateToken", userID.Hex(), login, "user").Return(tokenRes, nil)
-	s.mockTokenRepo.On("StoreToken", s.ctx, mock.Anything).Return(nil)
+	s.mockPasswordSvc.On("NeedsRehash", hashedPassword).Return(false)
+	s.mockJWTService.On("GenerateToken", mock.MatchedBy(func(sub userpkg.TokenSubject) bool {
+		return sub.UserID == userID.Hex() && sub.Username == login && sub.Role == "user" && sub.SessionID != "" && !sub.MFA
+	})).Return(tokenRes, nil)
+	s.mockTokenRepo.On("StoreToken", s.ctx, mock.MatchedBy(func(t userpkg.Token) bool {
+		return !t.FamilyID.IsZero() && t.ParentID.IsZero()
+	})).Return(nil)
 
 	// Act
-	user, accessToken, refreshToken, err := s.usecase.LoginUser(s.ctx, login, password)
+	result, err := s.usecase.LoginUser(s.ctx, login, password, userpkg.DeviceInfo{})
 
 	// Assert
 	s.NoError(err)
-	s.Equal(testUser.Username, user.Username)
-	s.Equal(tokenRes.AccessToken, accessToken)
-	s.Equal(tokenRes.RefreshToken, refreshToken)
-	s.Empty(user.Password)
+	s.False(result.MFARequired())
+	s.Equal(testUser.Username, result.User.Username)
+	s.Equal(tokenRes.AccessToken, result.AccessToken)
+	s.Equal(tokenRes.RefreshToken, result.RefreshToken)
+	s.Empty(result.User.Password)
 	s.mockUserRepo.AssertExpectations(s.T())
 	s.mockPasswordSvc.AssertExpectations(s.T())
 	s.mockJWTService.AssertExpectations(s.T())
 	s.mockTokenRepo.AssertExpectations(s.T())
 }
 
+func (s *UserUsecaseTestSuite) TestLoginUser_RehashesOutdatedHash() {
+	// Arrange: a verified user whose hash predates the current settings
+	userID := primitive.NewObjectID()
+	testUser := userpkg.User{ID: userID, Username: "testuser", Password: "$2a$10$legacy", Role: "user", IsVerified: true}
+
+	s.mockUserRepo.On("GetUserByLogin", s.ctx, "testuser").Return(testUser, nil)
+	s.mockPasswordSvc.On("ComparePassword", "$2a$10$legacy", "ValidPass123!").Return(nil)
+	s.mockPasswordSvc.On("NeedsRehash", "$2a$10$legacy").Return(true)
+	s.mockPasswordSvc.On("HashPassword", "ValidPass123!").Return("$argon2id$v=19$new", nil)
+	s.mockUserRepo.On("UpdatePassword", s.ctx, userID.Hex(), "$argon2id$v=19$new", 0).Return(nil)
+	s.mockJWTService.On("GenerateToken", mock.Anything).Return(userpkg.TokenResult{AccessToken: "access_token"}, nil)
+	s.mockTokenRepo.On("StoreToken", s.ctx, mock.Anything).Return(nil)
+
+	// Act
+	result, err := s.usecase.LoginUser(s.ctx, "testuser", "ValidPass123!", userpkg.DeviceInfo{})
+
+	// Assert
+	s.NoError(err)
+	s.Equal("access_token", result.AccessToken)
+	s.mockUserRepo.AssertExpectations(s.T())
+	s.mockPasswordSvc.AssertExpectations(s.T())
+}
+
 func (s *UserUsecaseTestSuite) TestLoginUser_NotFound() {
 	// Arrange
 	login := "nonexistent"
@@ -325,7 +374,7 @@ func (s *UserUsecaseTestSuite) TestLoginUser_NotFound() {
 	s.mockUserRepo.On("GetUserByLogin", s.ctx, login).Return(userpkg.User{}, errors.New("not found"))
 
 	// Act
-	_, _, _, err := s.usecase.LoginUser(s.ctx, login, password)
+	_, err := s.usecase.LoginUser(s.ctx, login, password, userpkg.DeviceInfo{})
 
 	// Assert
 	s.Error(err)
@@ -350,7 +399,7 @@ func (s *UserUsecaseTestSuite) TestLoginUser_WrongPassword() {
 	s.mockPasswordSvc.On("ComparePassword", hashedPassword, password).Return(errors.New("mismatch"))
 
 	// Act
-	_, _, _, err := s.usecase.LoginUser(s.ctx, login, password)
+	_, err := s.usecase.LoginUser(s.ctx, login, password, userpkg.DeviceInfo{})
 
 	// Assert
 	s.Error(err)
@@ -363,10 +412,10 @@ func (s *UserUsecaseTestSuite) TestSendResetOTP_EmailNotFound() {
 	// Arrange
 	email := "nonexistent@example.com"
 
-	s.mockUserRepo.On("ExistsByEmail", s.ctx, email).Return(false, nil)
+	s.mockUserRepo.On("FindByEmail", s.ctx, email).Return(userpkg.User{}, errors.New("user not found"))
 
 	// Act
-	err := s.usecase.SendResetOTP(s.ctx, email)
+	err := s.usecase.SendResetOTP(s.ctx, email, "")
 
 	// Assert
 	s.Error(err)
@@ -377,50 +426,74 @@ func (s *UserUsecaseTestSuite) TestSendResetOTP_EmailNotFound() {
 func (s *UserUsecaseTestSuite) TestSendResetOTP_Success() {
 	// Arrange
 	email := "user@example.com"
-	hashedOTP := "hashedOTP"
-
-	s.mockUserRepo.On("ExistsByEmail", s.ctx, email).Return(true, nil)
-	s.mockEmailSender.On("SendEmail", "user@example.com", "Your OTP Code", mock.Anything).Return(nil)
-	s.mockPasswordSvc.
-		On("HashPassword", mock.Anything).
-		Return(hashedOTP, nil)
+	var sentCode string
+	var stored userpkg.PasswordReset
 
-	s.mockResetRepo.On("StoreResetRequest", s.ctx, mock.Anything).Return(nil)
+	s.mockUserRepo.On("FindByEmail", s.ctx, email).Return(userpkg.User{Email: email, Locale: "fr"}, nil)
+	s.mockEmailSender.On("SendEmail", "user@example.com", "fr", services.TemplatePasswordReset, mock.Anything).
+		Run(func(args mock.Arguments) { sentCode = args.Get(3).(services.EmailData)["Code"].(string) }).
+		Return(nil)
+	s.mockResetRepo.On("StoreResetRequest", s.ctx, mock.Anything).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(userpkg.PasswordReset) }).
+		Return(nil)
 
 	// Act
-	err := s.usecase.SendResetOTP(s.ctx, email)
+	err := s.usecase.SendResetOTP(s.ctx, email, "")
 
 	// Assert
 	s.NoError(err)
 	s.mockUserRepo.AssertExpectations(s.T())
 	s.mockEmailSender.AssertExpectations(s.T())
-	s.mockPasswordSvc.AssertExpectations(s.T())
 	s.mockResetRepo.AssertExpectations(s.T())
+	// A fast hash, but keyed: six digits are too few to store unkeyed
+	s.Equal(utils.HashCode(testCodeKey, sentCode), stored.OTP)
+	s.NotEqual(utils.HashToken(sentCode), stored.OTP)
+	s.mockPasswordSvc.AssertNotCalled(s.T(), "HashPassword", mock.Anything)
 }
 
 func (s *UserUsecaseTestSuite) TestVerifyOTP_Success() {
 	// Arrange
 	email := "user@example.com"
 	otp := "123456"
-	hashedOTP := "hashedOTP"
 
 	storedReset := userpkg.PasswordReset{
 		Email:     email,
-		OTP:       hashedOTP,
+		OTP:       utils.HashCode(testCodeKey, otp),
 		ExpiresAt: time.Now().Add(10 * time.Minute),
 	}
 
 	s.mockResetRepo.On("GetResetRequest", s.ctx, email).Return(storedReset, nil)
-	s.mockPasswordSvc.On("ComparePassword", hashedOTP, otp).Return(nil)
-	s.mockResetRepo.On("DeleteResetRequest", s.ctx, email).Return(nil)
+	var stored userpkg.PasswordReset
+	s.mockResetRepo.On("StoreResetRequest", s.ctx, mock.MatchedBy(func(r userpkg.PasswordReset) bool {
+		return r.Email == email && r.OTP == "" && r.GrantHash != "" && r.ExpiresAt.After(time.Now())
+	})).Run(func(args mock.Arguments) { stored = args.Get(1).(userpkg.PasswordReset) }).Return(nil)
 
 	// Act
-	err := s.usecase.VerifyOTP(s.ctx, email, otp)
+	grant, err := s.usecase.VerifyOTP(s.ctx, email, otp)
 
 	// Assert
 	s.NoError(err)
+	s.NotEmpty(grant)
+	s.Equal(utils.HashToken(grant), stored.GrantHash)
 	s.mockResetRepo.AssertExpectations(s.T())
-	s.mockPasswordSvc.AssertExpectations(s.T())
+}
+
+func (s *UserUsecaseTestSuite) TestVerifyOTP_AlreadyVerified() {
+	// Arrange: the OTP was already exchanged for a grant
+	email := "user@example.com"
+	storedReset := userpkg.PasswordReset{
+		Email:     email,
+		GrantHash: "hashedGrant",
+		ExpiresAt: time.Now().Add(10 * time.Minute),
+	}
+	s.mockResetRepo.On("GetResetRequest", s.ctx, email).Return(storedReset, nil)
+
+	// Act
+	_, err := s.usecase.VerifyOTP(s.ctx, email, "123456")
+
+	// Assert
+	s.EqualError(err, "no reset request found")
+	s.mockPasswordSvc.AssertNotCalled(s.T(), "ComparePassword", mock.Anything, mock.Anything)
 }
 
 func (s *UserUsecaseTestSuite) TestVerifyOTP_Expired() {
@@ -438,7 +511,7 @@ func (s *UserUsecaseTestSuite) TestVerifyOTP_Expired() {
 	s.mockResetRepo.On("DeleteResetRequest", s.ctx, email).Return(nil)
 
 	// Act
-	err := s.usecase.VerifyOTP(s.ctx, email, otp)
+	_, err := s.usecase.VerifyOTP(s.ctx, email, otp)
 
 	// Assert
 	s.Error(err)
@@ -462,7 +535,7 @@ func (s *UserUsecaseTestSuite) TestVerifyOTP_MaxAttempts() {
 	s.mockResetRepo.On("DeleteResetRequest", s.ctx, email).Return(nil)
 
 	// Act
-	err := s.usecase.VerifyOTP(s.ctx, email, otp)
+	_, err := s.usecase.VerifyOTP(s.ctx, email, otp)
 
 	// Assert
 	s.Error(err)
@@ -474,44 +547,141 @@ func (s *UserUsecaseTestSuite) TestVerifyOTP_InvalidOTP() {
 	// Arrange
 	email := "user@example.com"
 	otp := "wrong123"
-	hashedOTP := "hashedOTP"
 
 	storedReset := userpkg.PasswordReset{
 		Email:     email,
-		OTP:       hashedOTP,
+		OTP:       utils.HashCode(testCodeKey, "123456"),
 		ExpiresAt: time.Now().Add(10 * time.Minute),
 	}
 
 	s.mockResetRepo.On("GetResetRequest", s.ctx, email).Return(storedReset, nil)
-	s.mockPasswordSvc.On("ComparePassword", hashedOTP, otp).Return(errors.New("mismatch"))
 	s.mockResetRepo.On("IncrementAttemptCount", s.ctx, email).Return(nil)
 
 	// Act
-	err := s.usecase.VerifyOTP(s.ctx, email, otp)
+	_, err := s.usecase.VerifyOTP(s.ctx, email, otp)
 
 	// Assert
 	s.Error(err)
 	s.Equal("invalid OTP", err.Error())
 	s.mockResetRepo.AssertExpectations(s.T())
-	s.mockPasswordSvc.AssertExpectations(s.T())
 }
 
 func (s *UserUsecaseTestSuite) TestResetPassword_Success() {
 	// Arrange
 	email := "user@example.com"
+	grant := "reset-grant"
 	newPassword := "NewPass123!"
 	hashedPassword := "hashedNewPassword"
+	userID := primitive.NewObjectID()
 
+	s.mockResetRepo.On("GetResetRequest", s.ctx, email).Return(userpkg.PasswordReset{
+		Email:     email,
+		GrantHash: utils.HashToken(grant),
+		ExpiresAt: time.Now().Add(10 * time.Minute),
+	}, nil)
+	s.mockUserRepo.On("FindByEmail", s.ctx, email).Return(userpkg.User{ID: userID, Email: email}, nil)
+	s.mockResetRepo.On("DeleteResetRequest", s.ctx, email).Return(nil)
 	s.mockPasswordSvc.On("HashPassword", newPassword).Return(hashedPassword, nil)
-	s.mockUserRepo.On("UpdatePasswordByEmail", s.ctx, email, hashedPassword).Return(nil)
+	s.mockUserRepo.On("UpdatePassword", s.ctx, userID.Hex(), hashedPassword, 0).Return(nil)
+	s.mockTokenRepo.On("DeleteTokensByUserID", s.ctx, userID.Hex()).Return(nil)
+	// Tokens minted by whoever knew the old password stop working too
+	accessTokens := new(mocks.IPersonalAccessTokenRepository)
+	s.usecase.WithPersonalAccessTokens(accessTokens)
+	accessTokens.On("DeleteAccessTokensByUserID", s.ctx, userID).Return(nil)
+	s.mockRevocationStore.On("RevokeUserTokens", s.ctx, userID.Hex(), mock.AnythingOfType("time.Time")).Return(nil)
+	s.mockEmailSender.On("SendEmail", email, "", services.TemplatePasswordChanged, mock.Anything).Return(nil)
 
 	// Act
-	err := s.usecase.ResetPassword(s.ctx, email, newPassword)
+	err := s.usecase.ResetPassword(s.ctx, email, grant, newPassword)
 
 	// Assert
 	s.NoError(err)
+	accessTokens.AssertExpectations(s.T())
+	s.mockRevocationStore.AssertExpectations(s.T())
 	s.mockPasswordSvc.AssertExpectations(s.T())
 	s.mockUserRepo.AssertExpectations(s.T())
+	s.mockResetRepo.AssertExpectations(s.T())
+	s.mockTokenRepo.AssertExpectations(s.T())
+	s.mockEmailSender.AssertExpectations(s.T())
+}
+
+func (s *UserUsecaseTestSuite) TestResetPassword_MissingToken() {
+	err := s.usecase.ResetPassword(s.ctx, "user@example.com", "", "NewPass123!")
+
+	s.EqualError(err, "reset token required")
+	s.mockUserRepo.AssertNotCalled(s.T(), "UpdatePassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *UserUsecaseTestSuite) TestResetPassword_OTPNotVerified() {
+	// Arrange: only an OTP is on file, no grant yet
+	email := "user@example.com"
+	s.mockResetRepo.On("GetResetRequest", s.ctx, email).Return(userpkg.PasswordReset{
+		Email:     email,
+		OTP:       "hashedOTP",
+		ExpiresAt: time.Now().Add(10 * time.Minute),
+	}, nil)
+
+	// Act
+	err := s.usecase.ResetPassword(s.ctx, email, "guess", "NewPass123!")
+
+	// Assert
+	s.EqualError(err, "no verified reset request found")
+	s.mockUserRepo.AssertNotCalled(s.T(), "UpdatePassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *UserUsecaseTestSuite) TestResetPassword_InvalidToken() {
+	// Arrange
+	email := "user@example.com"
+	s.mockResetRepo.On("GetResetRequest", s.ctx, email).Return(userpkg.PasswordReset{
+		Email:     email,
+		GrantHash: utils.HashToken("reset-grant"),
+		ExpiresAt: time.Now().Add(10 * time.Minute),
+	}, nil)
+	s.mockResetRepo.On("IncrementAttemptCount", s.ctx, email).Return(nil)
+
+	// Act
+	err := s.usecase.ResetPassword(s.ctx, email, "wrong", "NewPass123!")
+
+	// Assert
+	s.EqualError(err, "invalid reset token")
+	s.mockResetRepo.AssertExpectations(s.T())
+	s.mockUserRepo.AssertNotCalled(s.T(), "UpdatePassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *UserUsecaseTestSuite) TestResetPassword_TokenExpired() {
+	// Arrange
+	email := "user@example.com"
+	s.mockResetRepo.On("GetResetRequest", s.ctx, email).Return(userpkg.PasswordReset{
+		Email:     email,
+		GrantHash: "hashedGrant",
+		ExpiresAt: time.Now().Add(-1 * time.Minute),
+	}, nil)
+	s.mockResetRepo.On("DeleteResetRequest", s.ctx, email).Return(nil)
+
+	// Act
+	err := s.usecase.ResetPassword(s.ctx, email, "reset-grant", "NewPass123!")
+
+	// Assert
+	s.EqualError(err, "reset token expired")
+	s.mockResetRepo.AssertExpectations(s.T())
+}
+
+func (s *UserUsecaseTestSuite) TestResetPassword_WeakPasswordKeepsGrant() {
+	// Arrange
+	email := "user@example.com"
+	s.mockResetRepo.On("GetResetRequest", s.ctx, email).Return(userpkg.PasswordReset{
+		Email:     email,
+		GrantHash: utils.HashToken("reset-grant"),
+		ExpiresAt: time.Now().Add(10 * time.Minute),
+	}, nil)
+	s.mockUserRepo.On("FindByEmail", s.ctx, email).Return(userpkg.User{ID: primitive.NewObjectID(), Email: email}, nil)
+
+	// Act
+	err := s.usecase.ResetPassword(s.ctx, email, "reset-grant", "weak")
+
+	// Assert
+	s.Error(err)
+	s.mockResetRepo.AssertNotCalled(s.T(), "DeleteResetRequest", mock.Anything, mock.Anything)
 }
 
 func (s *UserUsecaseTestSuite) TestRefreshToken_Success() {
@@ -522,13 +692,16 @@ func (s *UserUsecaseTestSuite) TestRefreshToken_Success() {
 	role := "user"
 
 	claims := map[string]interface{}{
+		"typ":      userpkg.TokenTypeRefresh,
 		"_id":      userID.Hex(),
 		"username": username,
 		"role":     role,
 	}
 
 	storedToken := userpkg.Token{
+		ID:           primitive.NewObjectID(),
 		UserID:       userID,
+		FamilyID:     primitive.NewObjectID(),
 		RefreshToken: refreshToken,
 		ExpiresAt:    time.Now().Add(24 * time.Hour),
 	}
@@ -548,12 +721,22 @@ func (s *UserUsecaseTestSuite) TestRefreshToken_Success() {
 	s.mockJWTService.On("ValidateToken", refreshToken).Return(claims, nil)
 	s.mockTokenRepo.On("FindByRefreshToken", s.ctx, refreshToken).Return(storedToken, nil)
 	s.mockUserRepo.On("FindByID", s.ctx, userID.Hex()).Return(user, nil)
-	s.mockJWTService.On("GenerateToken", userID.Hex(), username, role).Return(newTokens, nil)
-	s.mockTokenRepo.On("DeleteByRefreshToken", s.ctx, refreshToken).Return(nil)
-	s.mockTokenRepo.On("StoreToken", s.ctx, mock.Anything).Return(nil)
+	s.mockJWTService.On("GenerateToken", userpkg.TokenSubject{
+		UserID:    userID.Hex(),
+		Username:  username,
+		Role:      role,
+		SessionID: storedToken.FamilyID.Hex(),
+
+		Permissions: []string{},
+	}).Return(newTokens, nil)
+	s.mockTokenRepo.On("MarkTokenRotated", s.ctx, storedToken.ID).Return(nil)
+	s.mockTokenRepo.On("StoreToken", s.ctx, mock.MatchedBy(func(t userpkg.Token) bool {
+		// child stays in the family and points at its parent
+		return t.FamilyID == storedToken.FamilyID && t.ParentID == storedToken.ID && t.RefreshToken == newTokens.RefreshToken
+	})).Return(nil)
 
 	// Act
-	result, err := s.usecase.RefreshToken(s.ctx, refreshToken)
+	result, err := s.usecase.RefreshToken(s.ctx, refreshToken, userpkg.DeviceInfo{})
 
 	// Assert
 	s.NoError(err)
@@ -571,7 +754,7 @@ func (s *UserUsecaseTestSuite) TestRefreshToken_InvalidToken() {
 	s.mockJWTService.On("ValidateToken", invalidToken).Return(nil, errors.New("invalid token"))
 
 	// Act
-	_, err := s.usecase.RefreshToken(s.ctx, invalidToken)
+	_, err := s.usecase.RefreshToken(s.ctx, invalidToken, userpkg.DeviceInfo{})
 
 	// Assert
 	s.Error(err)
@@ -579,12 +762,24 @@ func (s *UserUsecaseTestSuite) TestRefreshToken_InvalidToken() {
 	s.mockJWTService.AssertExpectations(s.T())
 }
 
+func (s *UserUsecaseTestSuite) TestRefreshToken_RejectsAccessToken() {
+	accessToken := "access_token"
+	s.mockJWTService.On("ValidateToken", accessToken).
+		Return(map[string]interface{}{"typ": userpkg.TokenTypeAccess, "_id": primitive.NewObjectID().Hex()}, nil)
+
+	_, err := s.usecase.RefreshToken(s.ctx, accessToken, userpkg.DeviceInfo{})
+
+	s.EqualError(err, "invalid or expired refresh token")
+	s.mockTokenRepo.AssertNotCalled(s.T(), "FindByRefreshToken", mock.Anything, mock.Anything)
+}
+
 func (s *UserUsecaseTestSuite) TestRefreshToken_TokenExpired() {
 	// Arrange
 	expiredToken := "expired_token"
 	userID := primitive.NewObjectID()
 
 	claims := map[string]interface{}{
+		"typ": userpkg.TokenTypeRefresh,
 		"_id": userID.Hex(),
 	}
 
@@ -598,7 +793,7 @@ func (s *UserUsecaseTestSuite) TestRefreshToken_TokenExpired() {
 	s.mockTokenRepo.On("FindByRefreshToken", s.ctx, expiredToken).Return(storedToken, nil)
 
 	// Act
-	_, err := s.usecase.RefreshToken(s.ctx, expiredToken)
+	_, err := s.usecase.RefreshToken(s.ctx, expiredToken, userpkg.DeviceInfo{})
 
 	// Assert
 	s.Error(err)
@@ -613,6 +808,7 @@ func (s *UserUsecaseTestSuite) TestRefreshToken_UserNotFound() {
 	userID := primitive.NewObjectID()
 
 	claims := map[string]interface{}{
+		"typ": userpkg.TokenTypeRefresh,
 		"_id": userID.Hex(),
 	}
 
@@ -624,10 +820,11 @@ func (s *UserUsecaseTestSuite) TestRefreshToken_UserNotFound() {
 
 	s.mockJWTService.On("ValidateToken", refreshToken).Return(claims, nil)
 	s.mockTokenRepo.On("FindByRefreshToken", s.ctx, refreshToken).Return(storedToken, nil)
+	s.mockTokenRepo.On("MarkTokenRotated", s.ctx, storedToken.ID).Return(nil)
 	s.mockUserRepo.On("FindByID", s.ctx, userID.Hex()).Return(userpkg.User{}, errors.New("not found"))
 
 	// Act
-	_, err := s.usecase.RefreshToken(s.ctx, refreshToken)
+	_, err := s.usecase.RefreshToken(s.ctx, refreshToken, userpkg.DeviceInfo{})
 
 	// Assert
 	s.Error(err)
@@ -636,6 +833,98 @@ func (s *UserUsecaseTestSuite) TestRefreshToken_UserNotFound() {
 	s.mockUserRepo.AssertExpectations(s.T())
 }
 
+func (s *UserUsecaseTestSuite) TestRefreshToken_ReuseRevokesFamily() {
+	// Arrange: the presented token was already rotated once
+	refreshToken := "stolen_token"
+	userID := primitive.NewObjectID()
+	rotatedAt := time.Now().Add(-time.Minute)
+
+	storedToken := userpkg.Token{
+		ID:           primitive.NewObjectID(),
+		UserID:       userID,
+		FamilyID:     primitive.NewObjectID(),
+		RefreshToken: refreshToken,
+		ExpiresAt:    time.Now().Add(24 * time.Hour),
+		RotatedAt:    &rotatedAt,
+	}
+
+	s.mockJWTService.On("ValidateToken", refreshToken).Return(map[string]interface{}{"typ": userpkg.TokenTypeRefresh, "_id": userID.Hex()}, nil)
+	s.mockTokenRepo.On("FindByRefreshToken", s.ctx, refreshToken).Return(storedToken, nil)
+	s.mockTokenRepo.On("DeleteTokensByFamilyID", s.ctx, storedToken.FamilyID).Return(nil)
+	s.mockTokenRepo.On("DeleteByRefreshToken", s.ctx, refreshToken).Return(nil)
+
+	// Act
+	_, err := s.usecase.RefreshToken(s.ctx, refreshToken, userpkg.DeviceInfo{})
+
+	// Assert
+	s.ErrorIs(err, userpkg.ErrRefreshTokenReused)
+	s.mockTokenRepo.AssertExpectations(s.T())
+	s.mockTokenRepo.AssertNotCalled(s.T(), "StoreToken", mock.Anything, mock.Anything)
+	s.mockJWTService.AssertNotCalled(s.T(), "GenerateToken", mock.Anything)
+}
+
+func (s *UserUsecaseTestSuite) TestRefreshToken_ConcurrentReuseRevokesFamily() {
+	// Arrange: another request rotated the token between find and mark
+	refreshToken := "raced_token"
+	userID := primitive.NewObjectID()
+
+	storedToken := userpkg.Token{
+		ID:           primitive.NewObjectID(),
+		UserID:       userID,
+		FamilyID:     primitive.NewObjectID(),
+		RefreshToken: refreshToken,
+		ExpiresAt:    time.Now().Add(24 * time.Hour),
+	}
+
+	s.mockJWTService.On("ValidateToken", refreshToken).Return(map[string]interface{}{"typ": userpkg.TokenTypeRefresh, "_id": userID.Hex()}, nil)
+	s.mockTokenRepo.On("FindByRefreshToken", s.ctx, refreshToken).Return(storedToken, nil)
+	s.mockTokenRepo.On("MarkTokenRotated", s.ctx, storedToken.ID).Return(userpkg.ErrRefreshTokenReused)
+	s.mockTokenRepo.On("DeleteTokensByFamilyID", s.ctx, storedToken.FamilyID).Return(nil)
+	s.mockTokenRepo.On("DeleteByRefreshToken", s.ctx, refreshToken).Return(nil)
+
+	// Act
+	_, err := s.usecase.RefreshToken(s.ctx, refreshToken, userpkg.DeviceInfo{})
+
+	// Assert
+	s.ErrorIs(err, userpkg.ErrRefreshTokenReused)
+	s.mockTokenRepo.AssertExpectations(s.T())
+}
+
+func (s *UserUsecaseTestSuite) TestRefreshToken_StoreFailure() {
+	// Arrange
+	refreshToken := "valid_token"
+	userID := primitive.NewObjectID()
+
+	storedToken := userpkg.Token{
+		ID:           primitive.NewObjectID(),
+		UserID:       userID,
+		FamilyID:     primitive.NewObjectID(),
+		RefreshToken: refreshToken,
+		ExpiresAt:    time.Now().Add(24 * time.Hour),
+	}
+	user := userpkg.User{ID: userID, Username: "testuser", Role: "user"}
+
+	s.mockJWTService.On("ValidateToken", refreshToken).Return(map[string]interface{}{"typ": userpkg.TokenTypeRefresh, "_id": userID.Hex()}, nil)
+	s.mockTokenRepo.On("FindByRefreshToken", s.ctx, refreshToken).Return(storedToken, nil)
+	s.mockTokenRepo.On("MarkTokenRotated", s.ctx, storedToken.ID).Return(nil)
+	s.mockUserRepo.On("FindByID", s.ctx, userID.Hex()).Return(user, nil)
+	s.mockJWTService.On("GenerateToken", userpkg.TokenSubject{
+		UserID:    userID.Hex(),
+		Username:  "testuser",
+		Role:      "user",
+		SessionID: storedToken.FamilyID.Hex(),
+
+		Permissions: []string{},
+	}).Return(userpkg.TokenResult{RefreshToken: "new"}, nil)
+	s.mockTokenRepo.On("StoreToken", s.ctx, mock.Anything).Return(errors.New("db down"))
+
+	// Act
+	_, err := s.usecase.RefreshToken(s.ctx, refreshToken, userpkg.DeviceInfo{})
+
+	// Assert
+	s.EqualError(err, "failed to store refresh token")
+}
+
 func (s *UserUsecaseTestSuite) TestLogout_Success() {
 	// Arrange
 	userID := primitive.NewObjectID().Hex()
@@ -645,7 +934,7 @@ func (s *UserUsecaseTestSuite) TestLogout_Success() {
 		Return(nil)
 
 	// Act
-	err := s.usecase.Logout(s.ctx, userID)
+	err := s.usecase.Logout(s.ctx, userID, "", "")
 
 	// Assert
 	s.NoError(err)
@@ -662,13 +951,110 @@ func (s *UserUsecaseTestSuite) TestLogout_FailureFromTokenRepo() {
 		Return(expectedErr)
 
 	// Act
-	err := s.usecase.Logout(s.ctx, userID)
+	err := s.usecase.Logout(s.ctx, userID, "", "")
 
 	// Assert
 	s.EqualError(err, expectedErr.Error())
 	s.mockTokenRepo.AssertCalled(s.T(), "DeleteTokensByUserID", mock.Anything, userID) // <-- Fix here
 }
 
+func (s *UserUsecaseTestSuite) TestLogout_CurrentSessionOnly() {
+	// Arrange
+	userID := primitive.NewObjectID()
+	sessionID := primitive.NewObjectID()
+	s.mockTokenRepo.On("DeleteSession", s.ctx, userID, sessionID).Return(nil)
+
+	// Act
+	err := s.usecase.Logout(s.ctx, userID.Hex(), sessionID.Hex(), "")
+
+	// Assert
+	s.NoError(err)
+	s.mockTokenRepo.AssertExpectations(s.T())
+	s.mockTokenRepo.AssertNotCalled(s.T(), "DeleteTokensByUserID", mock.Anything, mock.Anything)
+}
+
+func (s *UserUsecaseTestSuite) TestLogout_RevokesAccessToken() {
+	// Arrange
+	userID := primitive.NewObjectID()
+	sessionID := primitive.NewObjectID()
+	s.mockRevocationStore.On("RevokeToken", s.ctx, "jti-123", mock.MatchedBy(func(exp time.Time) bool {
+		return exp.After(time.Now())
+	})).Return(nil)
+	s.mockTokenRepo.On("DeleteSession", s.ctx, userID, sessionID).Return(nil)
+
+	// Act
+	err := s.usecase.Logout(s.ctx, userID.Hex(), sessionID.Hex(), "jti-123")
+
+	// Assert
+	s.NoError(err)
+	s.mockRevocationStore.AssertExpectations(s.T())
+	s.mockTokenRepo.AssertExpectations(s.T())
+}
+
+func (s *UserUsecaseTestSuite) TestDemoteUser_RevocationFailure() {
+	targetID := "user456"
+	actorID := "admin999"
+	s.mockUserRepo.On("FindByID", s.ctx, targetID).Return(userpkg.User{}, nil)
+	s.mockUserRepo.On("FindByID", s.ctx, actorID).Return(userpkg.User{}, nil)
+	s.mockUserRepo.On("UpdateRoleAndPromoter", s.ctx, targetID, "user", (*string)(nil)).Return(nil)
+	s.mockRevocationStore.On("RevokeUserTokens", s.ctx, targetID, mock.Anything).Return(errors.New("store down"))
+
+	err := s.usecase.DemoteUser(s.ctx, targetID, actorID)
+
+	s.EqualError(err, "store down")
+}
+
+func (s *UserUsecaseTestSuite) TestListSessions_MarksCurrent() {
+	// Arrange
+	userID := primitive.NewObjectID()
+	current := primitive.NewObjectID()
+	other := primitive.NewObjectID()
+	s.mockTokenRepo.On("FindActiveSessionsByUserID", s.ctx, userID).Return([]userpkg.Token{
+		{UserID: userID, FamilyID: current, DeviceName: "Laptop", IP: "10.0.0.1"},
+		{UserID: userID, FamilyID: other, UserAgent: "okhttp/4"},
+	}, nil)
+
+	// Act
+	sessions, err := s.usecase.ListSessions(s.ctx, userID.Hex(), current.Hex())
+
+	// Assert
+	s.NoError(err)
+	s.Len(sessions, 2)
+	s.Equal(current, sessions[0].ID)
+	s.True(sessions[0].Current)
+	s.Equal("Laptop", sessions[0].DeviceName)
+	s.False(sessions[1].Current)
+	s.Equal(other.Timestamp(), sessions[1].CreatedAt)
+}
+
+func (s *UserUsecaseTestSuite) TestRevokeSession_InvalidID() {
+	err := s.usecase.RevokeSession(s.ctx, primitive.NewObjectID().Hex(), "not-an-id")
+
+	s.ErrorIs(err, userpkg.ErrSessionNotFound)
+	s.mockTokenRepo.AssertNotCalled(s.T(), "DeleteSession", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *UserUsecaseTestSuite) TestRevokeOtherSessions_KeepsCurrent() {
+	// Arrange
+	userID := primitive.NewObjectID()
+	current := primitive.NewObjectID()
+	s.mockTokenRepo.On("DeleteOtherSessions", s.ctx, userID, current).Return(nil)
+
+	// Act
+	err := s.usecase.RevokeOtherSessions(s.ctx, userID.Hex(), current.Hex())
+
+	// Assert
+	s.NoError(err)
+	s.mockTokenRepo.AssertExpectations(s.T())
+}
+
+func (s *UserUsecaseTestSuite) TestRevokeOtherSessions_NoCurrentSession() {
+	err := s.usecase.RevokeOtherSessions(s.ctx, primitive.NewObjectID().Hex(), "")
+
+	s.Error(err)
+	s.mockTokenRepo.AssertNotCalled(s.T(), "DeleteOtherSessions", mock.Anything, mock.Anything, mock.Anything)
+}
+
 // TestPromoteUser_CallsRepo ensures PromoteUser calls the repository
 func (s *UserUsecaseTestSuite) TestPromoteUser_CallsRepo() {
 	targetID := "user123"
@@ -680,8 +1066,10 @@ func (s *UserUsecaseTestSuite) TestPromoteUser_CallsRepo() {
 		p := args.Get(3).(*string)
 		s.Equal(actorID, *p)
 	}).Return(nil)
+	s.mockRevocationStore.On("RevokeUserTokens", s.ctx, targetID, mock.AnythingOfType("time.Time")).Return(nil)
 	err := s.usecase.PromoteUser(s.ctx, targetID, actorID)
 	s.NoError(err)
+	s.mockRevocationStore.AssertExpectations(s.T())
 	s.mockUserRepo.AssertCalled(s.T(), "UpdateRoleAndPromoter", s.ctx, targetID, "admin", mock.AnythingOfType("*string"))
 }
 
@@ -692,8 +1080,10 @@ func (s *UserUsecaseTestSuite) TestDemoteUser_CallsRepo() {
 	s.mockUserRepo.On("FindByID", s.ctx, targetID).Return(userpkg.User{}, nil)
 	s.mockUserRepo.On("FindByID", s.ctx, actorID).Return(userpkg.User{}, nil)
 	s.mockUserRepo.On("UpdateRoleAndPromoter", s.ctx, targetID, "user", (*string)(nil)).Return(nil)
+	s.mockRevocationStore.On("RevokeUserTokens", s.ctx, targetID, mock.AnythingOfType("time.Time")).Return(nil)
 	err := s.usecase.DemoteUser(s.ctx, targetID, actorID)
 	s.NoError(err)
+	s.mockRevocationStore.AssertExpectations(s.T())
 	s.mockUserRepo.AssertCalled(s.T(), "UpdateRoleAndPromoter", s.ctx, targetID, "user", (*string)(nil))
 }
 
@@ -701,15 +1091,14 @@ func (s *UserUsecaseTestSuite) TestDemoteUser_CallsRepo() {
 func (s *UserUsecaseTestSuite) TestSendVerificationOTP_Success() {
 	email := "reg@example.com"
 
-	s.mockUserRepo.On("ExistsByEmail", s.ctx, email).Return(true, nil)
+	s.mockUserRepo.On("FindByEmail", s.ctx, email).Return(userpkg.User{Email: email, Username: "reg"}, nil)
 	// capture generated otp
-	s.mockEmailSender.On("SendEmail", email, "Your verification code", mock.MatchedBy(func(body string) bool {
-		return strings.Contains(body, "Your code:")
+	s.mockEmailSender.On("SendEmail", email, "", services.TemplateVerification, mock.MatchedBy(func(data services.EmailData) bool {
+		return data["Name"] == "reg" && len(data["Code"].(string)) == 6
 	})).Return(nil)
-	s.mockPasswordSvc.On("HashPassword", mock.Anything).Return("hashedOTP", nil)
 	s.mockVerificationRepo.On("StoreVerification", s.ctx, mock.Anything).Return(nil)
 
-	err := s.usecase.SendVerificationOTP(s.ctx, email)
+	err := s.usecase.SendVerificationOTP(s.ctx, email, "")
 	s.NoError(err)
 	s.mockVerificationRepo.AssertCalled(s.T(), "StoreVerification", s.ctx, mock.Anything)
 }
@@ -718,10 +1107,9 @@ func (s *UserUsecaseTestSuite) TestSendVerificationOTP_Success() {
 func (s *UserUsecaseTestSuite) TestVerifyUser_Success() {
 	email := "reg@example.com"
 	otp := "654321"
-	record := userpkg.Verification{Email: email, OTP: "hashedOTP", ExpiresAt: time.Now().Add(10 * time.Minute), AttemptCount: 0}
+	record := userpkg.Verification{Email: email, OTP: utils.HashCode(testCodeKey, otp), ExpiresAt: time.Now().Add(10 * time.Minute), AttemptCount: 0}
 
 	s.mockVerificationRepo.On("GetVerification", s.ctx, email).Return(record, nil)
-	s.mockPasswordSvc.On("ComparePassword", record.OTP, otp).Return(nil)
 	s.mockVerificationRepo.On("DeleteVerification", s.ctx, email).Return(nil)
 	s.mockUserRepo.On("UpdateIsVerifiedByEmail", s.ctx, email, true).Return(nil)
 
@@ -749,7 +1137,6 @@ func (s *UserUsecaseTestSuite) TestVerifyUser_InvalidCode() {
 	record := userpkg.Verification{Email: email, OTP: "hash", ExpiresAt: time.Now().Add(10 * time.Minute), AttemptCount: 1}
 
 	s.mockVerificationRepo.On("GetVerification", s.ctx, email).Return(record, nil)
-	s.mockPasswordSvc.On("ComparePassword", record.OTP, "wrong").Return(errors.New("mismatch"))
 	s.mockVerificationRepo.On("IncrementAttemptCount", s.ctx, email).Return(nil)
 
 	err := s.usecase.VerifyUser(s.ctx, email, "wrong")
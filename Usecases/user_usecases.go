@@ -2,8 +2,10 @@ package usecases
 
 import (
 	"context"
+	crand "crypto/rand"
 	"errors"
 	"fmt"
+	"log"
 	"math/rand"
 	"mime/multipart"
 	"net/url"
@@ -11,9 +13,12 @@ import (
 	"strings"
 	"time"
 
+	auditpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/audit"
+	institutionpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/institution"
 	"github.com/Amaankaa/Blog-Starter-Project/Domain/services"
 	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
 	utils "github.com/Amaankaa/Blog-Starter-Project/Domain/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type UserUsecase struct {
@@ -26,6 +31,44 @@ type UserUsecase struct {
 	passwordResetRepo userpkg.IPasswordResetRepository
 	verificationRepo  userpkg.IVerificationRepository
 	cloudinaryService userpkg.ICloudinaryService
+	revocations       userpkg.IRevocationStore
+
+	codeKey []byte // keys the stored hashes of emailed codes
+
+	totp                userpkg.ITOTPService
+	mfaChallenges       userpkg.IMFAChallengeRepository
+	requireMFAForAdmins bool
+
+	loginAttempts userpkg.ILoginAttemptRepository
+	loginPolicy   LoginThrottlePolicy
+
+	otpSends  userpkg.IOTPSendRepository
+	otpPolicy OTPSendPolicy
+
+	accountDeletions userpkg.IAccountDeletionRepository
+	deletionGrace    time.Duration
+
+	dataExports userpkg.IDataExportRepository
+	exportStore userpkg.IExportStore
+	urlSigner   userpkg.IURLSigner
+
+	emailChanges userpkg.IEmailChangeRepository
+	publicURL    string
+
+	passwordPolicy userpkg.IPasswordPolicy
+
+	accessTokens userpkg.IPersonalAccessTokenRepository
+
+	magicLinks      userpkg.IVerificationRepository
+	magicLinkSigner userpkg.IURLSigner
+	magicLinkURL    string
+
+	oauthStates   userpkg.IOAuthStateRepository
+	oidcProviders map[string]userpkg.IOIDCProvider
+
+	institutions institutionpkg.IInstitutionRepository
+
+	audit auditpkg.IAuditLogger
 }
 
 func NewUserUsecase(
@@ -39,7 +82,11 @@ func NewUserUsecase(
 	verificationRepo userpkg.IVerificationRepository,
 	cloudinaryService userpkg.ICloudinaryService,
 ) *UserUsecase {
+	// Replaced by WithCodeKey; on its own, codes do not survive a restart
+	codeKey := make([]byte, 32)
+	crand.Read(codeKey) // never returns an error since Go 1.24
 	return &UserUsecase{
+		codeKey:           codeKey,
 		userRepo:          userRepo,
 		passwordSvc:       passwordSvc,
 		tokenRepo:         tokenRepo,
@@ -52,6 +99,30 @@ func NewUserUsecase(
 	}
 }
 
+// WithRevocationStore makes logout and role changes take effect on access
+// tokens that have already been issued.
+func (uu *UserUsecase) WithRevocationStore(store userpkg.IRevocationStore) *UserUsecase {
+	uu.revocations = store
+	return uu
+}
+
+// WithCodeKey sets the secret that emailed codes are hashed with, so they
+// keep working across restarts and replicas
+func (uu *UserUsecase) WithCodeKey(key []byte) *UserUsecase {
+	uu.codeKey = key
+	return uu
+}
+
+// hashCode hashes a short emailed code for storing
+func (uu *UserUsecase) hashCode(code string) string {
+	return utils.HashCode(uu.codeKey, code)
+}
+
+// codeMatches reports whether code is the one hashCode turned into hash
+func (uu *UserUsecase) codeMatches(hash, code string) bool {
+	return utils.CodeMatches(uu.codeKey, hash, code)
+}
+
 func (uu *UserUsecase) RegisterUser(ctx context.Context, user userpkg.User) (userpkg.User, error) {
 	// Basic field validation
 	if user.Username == "" || user.Email == "" || user.Password == "" || user.Fullname == "" {
@@ -62,6 +133,13 @@ func (uu *UserUsecase) RegisterUser(ctx context.Context, user userpkg.User) (use
 	if !utils.IsValidEmail(user.Email) {
 		return userpkg.User{}, errors.New("invalid email format")
 	}
+	if user.Locale != "" && !IsValidLocale(user.Locale) {
+		return userpkg.User{}, errors.New("invalid locale")
+	}
+	// Reserved for the placeholder that deleted users' content is credited to
+	if strings.EqualFold(user.Username, userpkg.TombstoneUsername) {
+		return userpkg.User{}, errors.New("username already taken")
+	}
 
 	// Username and email uniqueness
 	exists, err := uu.userRepo.ExistsByUsername(ctx, user.Username)
@@ -89,9 +167,9 @@ func (uu *UserUsecase) RegisterUser(ctx context.Context, user userpkg.User) (use
 		return userpkg.User{}, errors.New("email is unreachable")
 	}
 
-	// Strong password check
-	if !utils.IsStrongPassword(user.Password) {
-		return userpkg.User{}, errors.New("password must be at least 8 chars, with upper, lower, number, and special char")
+	// Password policy
+	if err := uu.validatePassword(user.Password, user); err != nil {
+		return userpkg.User{}, err
 	}
 
 	// Assign role
@@ -100,9 +178,9 @@ func (uu *UserUsecase) RegisterUser(ctx context.Context, user userpkg.User) (use
 		return userpkg.User{}, err
 	}
 	if count == 0 {
-		user.Role = "admin"
+		user.Role = userpkg.RoleAdmin
 	} else {
-		user.Role = "user"
+		user.Role = userpkg.RoleUser
 	}
 
 	// Password hashing
@@ -122,16 +200,19 @@ func (uu *UserUsecase) RegisterUser(ctx context.Context, user userpkg.User) (use
 	otp := utils.GenerateOTP(6)
 
 	// Send verification email
-	err = uu.emailSender.SendEmail(user.Email, "Email Verification Code", "Your verification OTP: "+otp)
+	err = uu.emailSender.SendEmail(user.Email, user.Locale, services.TemplateVerification, services.EmailData{
+		"Name":             user.Username,
+		"Code":             otp,
+		"ExpiresInMinutes": 10,
+	})
 	if err != nil {
 		return userpkg.User{}, errors.New("failed to send verification code")
 	}
+	// Counted so a resend right after registering reuses this code
+	uu.recordOTPSend(ctx, otpPurposeVerify, user.Email, "")
 
 	// Hash OTP before storing
-	hashedOTP, err := uu.passwordSvc.HashPassword(otp)
-	if err != nil {
-		return userpkg.User{}, errors.New("failed to process verification code")
-	}
+	hashedOTP := uu.hashCode(otp)
 
 	// Store verification request
 	verification := userpkg.Verification{
@@ -150,45 +231,114 @@ func (uu *UserUsecase) RegisterUser(ctx context.Context, user userpkg.User) (use
 	return createdUser, nil
 }
 
-func (uu *UserUsecase) LoginUser(ctx context.Context, login, password string) (userpkg.User, string, string, error) {
+func (uu *UserUsecase) LoginUser(ctx context.Context, login, password string, device userpkg.DeviceInfo) (userpkg.LoginResult, error) {
+	if device.IP != "" {
+		if err := uu.checkLoginThrottle(ctx, ipAttemptKey(device.IP), uu.loginPolicy.IPFreeAttempts); err != nil {
+			return userpkg.LoginResult{}, err
+		}
+	}
+
 	user, err := uu.userRepo.GetUserByLogin(ctx, login)
 	if err != nil {
-		return userpkg.User{}, "", "", errors.New("invalid credentials")
+		uu.recordLoginFailure(ctx, nil, device.IP)
+		return userpkg.LoginResult{}, errors.New("invalid credentials")
+	}
+	if err := uu.checkLoginThrottle(ctx, accountAttemptKey(user.ID.Hex()), uu.loginPolicy.FreeAttempts); err != nil {
+		return userpkg.LoginResult{}, err
 	}
 	// Prevent login if email not verified
 	if !user.IsVerified {
-		return userpkg.User{}, "", "", errors.New("email not verified")
+		return userpkg.LoginResult{}, errors.New("email not verified")
 	}
 
 	if err := uu.passwordSvc.ComparePassword(user.Password, password); err != nil {
-		return userpkg.User{}, "", "", errors.New("invalid credentials")
+		uu.recordLoginFailure(ctx, &user, device.IP)
+		return userpkg.LoginResult{}, errors.New("invalid credentials")
+	}
+	if err := user.SuspensionError(time.Now()); err != nil {
+		return userpkg.LoginResult{}, err
+	}
+	uu.upgradePasswordHash(ctx, user, password)
+
+	// Accounts with MFA get a challenge instead of tokens
+	if user.MFA.Enabled {
+		return uu.startMFAChallenge(ctx, user)
 	}
 
+	return uu.startSession(ctx, user, device, false)
+}
+
+// upgradePasswordHash re-hashes a correct password whose stored hash uses an
+// older algorithm or cost, so users migrate as they sign in. Failures only
+// delay the upgrade to the next login.
+func (uu *UserUsecase) upgradePasswordHash(ctx context.Context, user userpkg.User, password string) {
+	if !uu.passwordSvc.NeedsRehash(user.Password) {
+		return
+	}
+	hashed, err := uu.passwordSvc.HashPassword(password)
+	if err != nil {
+		log.Printf("password rehash failed user=%s: %v", user.ID.Hex(), err)
+		return
+	}
+	if err := uu.userRepo.UpdatePassword(ctx, user.ID.Hex(), hashed, 0); err != nil {
+		log.Printf("password rehash failed user=%s: %v", user.ID.Hex(), err)
+	}
+}
+
+// startSession issues a token pair for a fully authenticated user. Each login
+// starts a new session, i.e. a new rotation family. Failed attempts are only
+// forgotten here, so a known password alone cannot reset the lockout count
+// while codes for the second factor are guessed.
+func (uu *UserUsecase) startSession(ctx context.Context, user userpkg.User, device userpkg.DeviceInfo, mfa bool) (userpkg.LoginResult, error) {
+	uu.clearLoginFailures(ctx, user.ID.Hex())
+	sessionID := primitive.NewObjectID()
+
 	// Generate tokens
-	tokenRes, err := uu.jwtService.GenerateToken(user.ID.Hex(), user.Username, user.Role)
+	tokenRes, err := uu.jwtService.GenerateToken(userpkg.TokenSubject{
+		UserID:    user.ID.Hex(),
+		Username:  user.Username,
+		Role:      user.Role,
+		SessionID: sessionID.Hex(),
+		MFA:       mfa,
+
+		Permissions: userpkg.PermissionsForRole(user.Role),
+	})
 	if err != nil {
-		return userpkg.User{}, "", "", err
+		return userpkg.LoginResult{}, err
 	}
 
 	// Store tokens
+	now := time.Now()
 	err = uu.tokenRepo.StoreToken(ctx, userpkg.Token{
 		UserID:       user.ID,
+		FamilyID:     sessionID,
 		AccessToken:  tokenRes.AccessToken,
 		RefreshToken: tokenRes.RefreshToken,
-		CreatedAt:    time.Now(),
+		CreatedAt:    now,
 		ExpiresAt:    tokenRes.RefreshExpiresAt,
+		MFA:          mfa,
+		DeviceName:   device.DeviceName,
+		UserAgent:    device.UserAgent,
+		IP:           device.IP,
+		LastUsedAt:   now,
 	})
 	if err != nil {
-		return userpkg.User{}, "", "", err
+		return userpkg.LoginResult{}, err
 	}
 
 	user.Password = ""
-	return user, tokenRes.AccessToken, tokenRes.RefreshToken, nil
+	return userpkg.LoginResult{
+		User:         user,
+		AccessToken:  tokenRes.AccessToken,
+		RefreshToken: tokenRes.RefreshToken,
+	}, nil
 }
 
-func (uu *UserUsecase) RefreshToken(ctx context.Context, refreshToken string) (userpkg.TokenResult, error) {
+// RefreshToken rotates a refresh token within its family. Presenting a token
+// that was already rotated is treated as theft: the whole family is revoked.
+func (uu *UserUsecase) RefreshToken(ctx context.Context, refreshToken string, device userpkg.DeviceInfo) (userpkg.TokenResult, error) {
 	claims, err := uu.jwtService.ValidateToken(refreshToken)
-	if err != nil {
+	if err != nil || claims["typ"] != userpkg.TokenTypeRefresh {
 		return userpkg.TokenResult{}, errors.New("invalid or expired refresh token")
 	}
 
@@ -202,53 +352,139 @@ func (uu *UserUsecase) RefreshToken(ctx context.Context, refreshToken string) (u
 	if err != nil {
 		return userpkg.TokenResult{}, errors.New("refresh token not recognized")
 	}
+	if stored.UserID.Hex() != userID {
+		return userpkg.TokenResult{}, errors.New("refresh token not recognized")
+	}
+
+	if stored.RotatedAt != nil {
+		return userpkg.TokenResult{}, uu.revokeTokenFamily(ctx, stored)
+	}
 
 	if stored.ExpiresAt.Before(time.Now()) {
 		return userpkg.TokenResult{}, errors.New("refresh token expired")
 	}
 
+	// Claim the token before minting a child so concurrent replays lose
+	if err := uu.tokenRepo.MarkTokenRotated(ctx, stored.ID); err != nil {
+		if errors.Is(err, userpkg.ErrRefreshTokenReused) {
+			return userpkg.TokenResult{}, uu.revokeTokenFamily(ctx, stored)
+		}
+		return userpkg.TokenResult{}, err
+	}
+
 	// Fetch user info (optional, for roles/username)
 	user, err := uu.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return userpkg.TokenResult{}, err
 	}
+	if err := user.SuspensionError(time.Now()); err != nil {
+		return userpkg.TokenResult{}, err
+	}
 
 	// Generate new tokens
-	tokens, err := uu.jwtService.GenerateToken(user.ID.Hex(), user.Username, user.Role)
+	familyID := tokenFamilyID(stored)
+	tokens, err := uu.jwtService.GenerateToken(userpkg.TokenSubject{
+		UserID:    user.ID.Hex(),
+		Username:  user.Username,
+		Role:      user.Role,
+		SessionID: familyID.Hex(),
+		MFA:       stored.MFA,
+
+		Permissions: userpkg.PermissionsForRole(user.Role),
+	})
 	if err != nil {
 		return userpkg.TokenResult{}, err
 	}
 
-	// Store new refresh token, remove old
-	_ = uu.tokenRepo.DeleteByRefreshToken(ctx, refreshToken)
-	_ = uu.tokenRepo.StoreToken(ctx, userpkg.Token{
+	// The child inherits the session's device, refreshed with the latest client details
+	child := userpkg.Token{
 		UserID:       user.ID,
+		FamilyID:     familyID,
+		ParentID:     stored.ID,
+		AccessToken:  tokens.AccessToken,
 		RefreshToken: tokens.RefreshToken,
 		ExpiresAt:    tokens.RefreshExpiresAt,
 		CreatedAt:    time.Now(),
-	})
+		MFA:          stored.MFA,
+		DeviceName:   stored.DeviceName,
+		UserAgent:    stored.UserAgent,
+		IP:           stored.IP,
+		LastUsedAt:   time.Now(),
+	}
+	if device.DeviceName != "" {
+		child.DeviceName = device.DeviceName
+	}
+	if device.UserAgent != "" {
+		child.UserAgent = device.UserAgent
+	}
+	if device.IP != "" {
+		child.IP = device.IP
+	}
+
+	err = uu.tokenRepo.StoreToken(ctx, child)
+	if err != nil {
+		return userpkg.TokenResult{}, errors.New("failed to store refresh token")
+	}
 
 	return tokens, nil
 }
 
-func (u *UserUsecase) SendResetOTP(ctx context.Context, email string) error {
-	exists, _ := u.userRepo.ExistsByEmail(ctx, email)
-	if !exists {
-		return errors.New("email not registered")
+// revokeTokenFamily handles a replayed refresh token by dropping every token in
+// its family and recording a security event.
+func (uu *UserUsecase) revokeTokenFamily(ctx context.Context, stored userpkg.Token) error {
+	familyID := tokenFamilyID(stored)
+	log.Printf("security: refresh token reuse detected user=%s family=%s token=%s",
+		stored.UserID.Hex(), familyID.Hex(), stored.ID.Hex())
+
+	if err := uu.tokenRepo.DeleteTokensByFamilyID(ctx, familyID); err != nil {
+		return err
 	}
+	// Tokens issued before families existed are their own family root
+	_ = uu.tokenRepo.DeleteByRefreshToken(ctx, stored.RefreshToken)
+	return userpkg.ErrRefreshTokenReused
+}
 
-	otp := utils.GenerateOTP(6)
+// tokenFamilyID returns the rotation family of a token, treating legacy tokens
+// without one as the root of their own family.
+func tokenFamilyID(t userpkg.Token) primitive.ObjectID {
+	if t.FamilyID.IsZero() {
+		return t.ID
+	}
+	return t.FamilyID
+}
+
+func (u *UserUsecase) SendResetOTP(ctx context.Context, email, clientIP string) error {
+	user, err := u.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		return errors.New("email not registered")
+	}
 
-	err := u.emailSender.SendEmail(email, "Your OTP Code", "Your OTP: "+otp)
+	reuse, err := u.checkOTPSend(ctx, otpPurposeReset, email, clientIP, func() bool {
+		stored, err := u.passwordResetRepo.GetResetRequest(ctx, email)
+		return err == nil && otpStillValid(stored.OTP, stored.ExpiresAt, stored.AttemptCount)
+	})
 	if err != nil {
 		return err
 	}
+	if reuse {
+		// The code sent moments ago still works
+		return nil
+	}
 
-	//Hash OTP before storing
-	hashedOTP, err := u.passwordSvc.HashPassword(otp)
+	otp := utils.GenerateOTP(6)
+
+	err = u.emailSender.SendEmail(email, user.Locale, services.TemplatePasswordReset, services.EmailData{
+		"Name":             user.Username,
+		"Code":             otp,
+		"ExpiresInMinutes": 10,
+	})
 	if err != nil {
 		return err
 	}
+	u.recordOTPSend(ctx, otpPurposeReset, email, clientIP)
+
+	//Hash OTP before storing
+	hashedOTP := u.hashCode(otp)
 
 	reset := userpkg.PasswordReset{
 		Email:        email,
@@ -259,43 +495,190 @@ func (u *UserUsecase) SendResetOTP(ctx context.Context, email string) error {
 	return u.passwordResetRepo.StoreResetRequest(ctx, reset)
 }
 
-func (u *UserUsecase) VerifyOTP(ctx context.Context, email, otp string) error {
+// VerifyOTP checks the reset OTP and, on success, swaps it for a short-lived,
+// single-use reset grant. Only the hash of the grant is stored.
+func (u *UserUsecase) VerifyOTP(ctx context.Context, email, otp string) (string, error) {
 	stored, err := u.passwordResetRepo.GetResetRequest(ctx, email)
-	if err != nil {
-		return errors.New("no reset request found")
+	if err != nil || stored.OTP == "" {
+		return "", errors.New("no reset request found")
 	}
 
 	if time.Now().After(stored.ExpiresAt) {
 		_ = u.passwordResetRepo.DeleteResetRequest(ctx, email)
-		return errors.New("OTP expired")
+		return "", errors.New("OTP expired")
 	}
 
 	if stored.AttemptCount >= 5 {
 		_ = u.passwordResetRepo.DeleteResetRequest(ctx, email)
-		return errors.New("too many invalid attempts — OTP expired")
+		return "", errors.New("too many invalid attempts — OTP expired")
 	}
 
-	if u.passwordSvc.ComparePassword(stored.OTP, otp) != nil {
+	if !u.codeMatches(stored.OTP, otp) {
 		// increment attempt count
 		_ = u.passwordResetRepo.IncrementAttemptCount(ctx, email)
-		return errors.New("invalid OTP")
+		return "", errors.New("invalid OTP")
 	}
 
-	// OTP is valid — delete it
-	_ = u.passwordResetRepo.DeleteResetRequest(ctx, email)
-	return nil
+	// OTP is valid — replace it with a reset grant
+	grant, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		return "", errors.New("failed to issue reset token")
+	}
+	hashedGrant := utils.HashToken(grant)
+
+	err = u.passwordResetRepo.StoreResetRequest(ctx, userpkg.PasswordReset{
+		Email:        email,
+		GrantHash:    hashedGrant,
+		ExpiresAt:    time.Now().Add(15 * time.Minute),
+		AttemptCount: 0,
+	})
+	if err != nil {
+		return "", errors.New("failed to issue reset token")
+	}
+	return grant, nil
 }
 
-func (u *UserUsecase) ResetPassword(ctx context.Context, email, newPassword string) error {
+// ResetPassword sets a new password for the email, provided the caller holds
+// the reset grant issued by VerifyOTP. The grant is consumed, every session of
+// the account is revoked and the owner is notified.
+func (u *UserUsecase) ResetPassword(ctx context.Context, email, resetToken, newPassword string) error {
+	if resetToken == "" {
+		return errors.New("reset token required")
+	}
+
+	stored, err := u.passwordResetRepo.GetResetRequest(ctx, email)
+	if err != nil || stored.GrantHash == "" {
+		return errors.New("no verified reset request found")
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		_ = u.passwordResetRepo.DeleteResetRequest(ctx, email)
+		return errors.New("reset token expired")
+	}
+
+	if stored.AttemptCount >= 5 {
+		_ = u.passwordResetRepo.DeleteResetRequest(ctx, email)
+		return errors.New("too many invalid attempts — reset token revoked")
+	}
+
+	if !utils.TokenMatches(stored.GrantHash, resetToken) {
+		_ = u.passwordResetRepo.IncrementAttemptCount(ctx, email)
+		return errors.New("invalid reset token")
+	}
+
+	user, err := u.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+	if err := u.validatePassword(newPassword, user); err != nil {
+		return err
+	}
+
+	// Consume the grant before touching the password so it cannot be replayed
+	if err := u.passwordResetRepo.DeleteResetRequest(ctx, email); err != nil {
+		return err
+	}
+
 	hashed, err := u.passwordSvc.HashPassword(newPassword)
 	if err != nil {
 		return err
 	}
-	return u.userRepo.UpdatePasswordByEmail(ctx, email, hashed)
+	if err := u.userRepo.UpdatePassword(ctx, user.ID.Hex(), hashed, u.passwordHistoryKeep()); err != nil {
+		return err
+	}
+
+	if err := u.tokenRepo.DeleteTokensByUserID(ctx, user.ID.Hex()); err != nil {
+		return errors.New("password updated but failed to revoke existing sessions")
+	}
+	if err := u.deleteAllAccessTokens(ctx, user.ID.Hex()); err != nil {
+		return errors.New("password updated but failed to revoke personal access tokens")
+	}
+	_ = u.revokeAccessTokens(ctx, user.ID.Hex())
+
+	// Best-effort notice; the reset itself already succeeded
+	_ = u.emailSender.SendEmail(email, user.Locale, services.TemplatePasswordChanged, services.EmailData{
+		"Name":          user.Username,
+		"SessionsEnded": true,
+	})
+	return nil
 }
 
-func (u *UserUsecase) Logout(ctx context.Context, userID string) error {
-	return u.tokenRepo.DeleteTokensByUserID(ctx, userID)
+// Logout ends the current session and revokes the access token used for the
+// request. Tokens issued before sessions existed carry no session ID, in which
+// case every session of the user is dropped.
+func (u *UserUsecase) Logout(ctx context.Context, userID, sessionID, tokenID string) error {
+	if u.revocations != nil && tokenID != "" {
+		if err := u.revocations.RevokeToken(ctx, tokenID, time.Now().Add(userpkg.AccessTokenTTL)); err != nil {
+			return err
+		}
+	}
+	if sessionID == "" {
+		return u.tokenRepo.DeleteTokensByUserID(ctx, userID)
+	}
+	return u.RevokeSession(ctx, userID, sessionID)
+}
+
+// revokeAccessTokens invalidates every access token the user currently holds,
+// e.g. after a role change so no stale role claim survives.
+func (u *UserUsecase) revokeAccessTokens(ctx context.Context, userID string) error {
+	if u.revocations == nil {
+		return nil
+	}
+	return u.revocations.RevokeUserTokens(ctx, userID, time.Now())
+}
+
+// ListSessions returns the user's active sessions, flagging the one making the request
+func (u *UserUsecase) ListSessions(ctx context.Context, userID, currentSessionID string) ([]userpkg.Session, error) {
+	uid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, errors.New("invalid user ID")
+	}
+
+	tokens, err := u.tokenRepo.FindActiveSessionsByUserID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]userpkg.Session, 0, len(tokens))
+	for _, t := range tokens {
+		sessions = append(sessions, userpkg.Session{
+			ID:         t.FamilyID,
+			DeviceName: t.DeviceName,
+			UserAgent:  t.UserAgent,
+			IP:         t.IP,
+			CreatedAt:  t.FamilyID.Timestamp(),
+			LastUsedAt: t.LastUsedAt,
+			ExpiresAt:  t.ExpiresAt,
+			Current:    t.FamilyID.Hex() == currentSessionID,
+		})
+	}
+	return sessions, nil
+}
+
+// RevokeSession ends one of the user's sessions
+func (u *UserUsecase) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	uid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return errors.New("invalid user ID")
+	}
+	sid, err := primitive.ObjectIDFromHex(sessionID)
+	if err != nil {
+		return userpkg.ErrSessionNotFound
+	}
+	return u.tokenRepo.DeleteSession(ctx, uid, sid)
+}
+
+// RevokeOtherSessions logs the user out everywhere except the current session
+func (u *UserUsecase) RevokeOtherSessions(ctx context.Context, userID, currentSessionID string) error {
+	uid, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return errors.New("invalid user ID")
+	}
+	sid, err := primitive.ObjectIDFromHex(currentSessionID)
+	if err != nil {
+		return errors.New("current session unknown; log in again to manage sessions")
+	}
+	return u.tokenRepo.DeleteOtherSessions(ctx, uid, sid)
 }
 
 func (uu *UserUsecase) PromoteUser(ctx context.Context, targetUserID string, actorUserID string) error {
@@ -315,13 +698,14 @@ func (uu *UserUsecase) PromoteUser(ctx context.Context, targetUserID string, act
 	if !actor.PromotedBy.IsZero() && actor.PromotedBy.Hex() == target.ID.Hex() {
 		return errors.New("cannot act on your promoter")
 	}
-	if target.Role == "admin" {
+	if target.Role == userpkg.RoleAdmin {
 		return nil
 	}
-	if err := uu.userRepo.UpdateRoleAndPromoter(ctx, targetUserID, "admin", &actorUserID); err != nil {
+	if err := uu.userRepo.UpdateRoleAndPromoter(ctx, targetUserID, userpkg.RoleAdmin, &actorUserID); err != nil {
 		return err
 	}
-	return nil
+	uu.auditRoleChange(ctx, auditpkg.ActionRoleGrant, target, userpkg.RoleAdmin, actorUserID)
+	return uu.revokeAccessTokens(ctx, targetUserID)
 }
 
 func (uu *UserUsecase) DemoteUser(ctx context.Context, targetUserID string, actorUserID string) error {
@@ -338,27 +722,42 @@ func (uu *UserUsecase) DemoteUser(ctx context.Context, targetUserID string, acto
 	if !actor.PromotedBy.IsZero() && actor.PromotedBy.Hex() == target.ID.Hex() {
 		return errors.New("cannot act on your promoter")
 	}
-	if err := uu.userRepo.UpdateRoleAndPromoter(ctx, targetUserID, "user", nil); err != nil {
+	if err := uu.userRepo.UpdateRoleAndPromoter(ctx, targetUserID, userpkg.RoleUser, nil); err != nil {
 		return err
 	}
-	return nil
+	uu.auditRoleChange(ctx, auditpkg.ActionRoleRevoke, target, userpkg.RoleUser, actorUserID)
+	return uu.revokeAccessTokens(ctx, targetUserID)
 }
 
-func (u *UserUsecase) SendVerificationOTP(ctx context.Context, email string) error {
-	exists, _ := u.userRepo.ExistsByEmail(ctx, email)
-	if !exists {
+func (u *UserUsecase) SendVerificationOTP(ctx context.Context, email, clientIP string) error {
+	user, err := u.userRepo.FindByEmail(ctx, email)
+	if err != nil {
 		return errors.New("email not registered")
 	}
 
-	otp := utils.GenerateOTP(6)
-	if err := u.emailSender.SendEmail(email, "Your verification code", "Your code: "+otp); err != nil {
+	reuse, err := u.checkOTPSend(ctx, otpPurposeVerify, email, clientIP, func() bool {
+		stored, err := u.verificationRepo.GetVerification(ctx, email)
+		return err == nil && otpStillValid(stored.OTP, stored.ExpiresAt, stored.AttemptCount)
+	})
+	if err != nil {
 		return err
 	}
+	if reuse {
+		// The code sent moments ago still works
+		return nil
+	}
 
-	hashed, err := u.passwordSvc.HashPassword(otp)
-	if err != nil {
+	otp := utils.GenerateOTP(6)
+	if err := u.emailSender.SendEmail(email, user.Locale, services.TemplateVerification, services.EmailData{
+		"Name":             user.Username,
+		"Code":             otp,
+		"ExpiresInMinutes": 15,
+	}); err != nil {
 		return err
 	}
+	u.recordOTPSend(ctx, otpPurposeVerify, email, clientIP)
+
+	hashed := u.hashCode(otp)
 
 	v := userpkg.Verification{
 		Email:        email,
@@ -382,7 +781,7 @@ func (u *UserUsecase) VerifyUser(ctx context.Context, email, otp string) error {
 		_ = u.verificationRepo.DeleteVerification(ctx, email)
 		return errors.New("too many invalid attempts")
 	}
-	if u.passwordSvc.ComparePassword(v.OTP, otp) != nil {
+	if !u.codeMatches(v.OTP, otp) {
 		_ = u.verificationRepo.IncrementAttemptCount(ctx, email)
 		return errors.New("invalid code")
 	}
@@ -408,6 +807,9 @@ func (u *UserUsecase) UpdateProfile(ctx context.Context, userID string, updates
 	if updates.ContactInfo.Website != "" && !IsValidURL(updates.ContactInfo.Website) {
 		return userpkg.User{}, errors.New("invalid website URL")
 	}
+	if updates.Locale != "" && !IsValidLocale(updates.Locale) {
+		return userpkg.User{}, errors.New("invalid locale")
+	}
 
 	if file != nil && filename != "" {
 		imageURL, err := u.cloudinaryService.UploadImage(ctx, file, filename)
@@ -426,6 +828,13 @@ func IsValidPhone(phone string) bool {
 	return phoneRegex.MatchString(phone)
 }
 
+// IsValidLocale accepts a language tag such as "fr" or "en-GB"
+func IsValidLocale(locale string) bool {
+	return localePattern.MatchString(locale)
+}
+
+var localePattern = regexp.MustCompile(`^[a-zA-Z]{2,3}([-_][a-zA-Z0-9]{2,8})?$`)
+
 func IsValidURL(rawurl string) bool {
 	_, err := url.Parse(rawurl)
 	return err == nil && (strings.HasPrefix(rawurl, "http://") || strings.HasPrefix(rawurl, "https://"))
@@ -442,8 +851,9 @@ func (u *UserUsecase) GetPublicProfile(ctx context.Context, userID string) (user
 	return u.userRepo.GetPublicProfile(ctx, userID)
 }
 
-// FindMentors searches for available mentors by topics
-func (u *UserUsecase) FindMentors(ctx context.Context, topics []string, limit int, offset int) ([]userpkg.PublicProfile, error) {
+// FindMentors searches for available mentors by topics, optionally only the
+// verified students of one institution
+func (u *UserUsecase) FindMentors(ctx context.Context, topics []string, institutionID string, limit int, offset int) ([]userpkg.PublicProfile, error) {
 	if limit <= 0 || limit > 100 {
 		limit = 20 // Default limit
 	}
@@ -451,7 +861,16 @@ func (u *UserUsecase) FindMentors(ctx context.Context, topics []string, limit in
 		offset = 0
 	}
 
-	return u.userRepo.FindMentors(ctx, topics, limit, offset)
+	var institution primitive.ObjectID
+	if institutionID != "" {
+		oid, err := primitive.ObjectIDFromHex(institutionID)
+		if err != nil {
+			return nil, institutionpkg.ErrInstitutionNotFound
+		}
+		institution = oid
+	}
+
+	return u.userRepo.FindMentors(ctx, topics, institution, limit, offset)
 }
 
 // FindMentees searches for mentees by topics
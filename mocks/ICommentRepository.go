@@ -65,6 +65,54 @@ func (_m *ICommentRepository) DeleteComment(ctx context.Context, id primitive.Ob
 	return r0
 }
 
+// DeleteCommentsByAuthor provides a mock function with given fields: ctx, authorID
+func (_m *ICommentRepository) DeleteCommentsByAuthor(ctx context.Context, authorID primitive.ObjectID) (map[primitive.ObjectID]int, error) {
+	ret := _m.Called(ctx, authorID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteCommentsByAuthor")
+	}
+
+	var r0 map[primitive.ObjectID]int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) (map[primitive.ObjectID]int, error)); ok {
+		return rf(ctx, authorID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) map[primitive.ObjectID]int); ok {
+		r0 = rf(ctx, authorID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[primitive.ObjectID]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID) error); ok {
+		r1 = rf(ctx, authorID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteCommentsByPosts provides a mock function with given fields: ctx, postIDs
+func (_m *ICommentRepository) DeleteCommentsByPosts(ctx context.Context, postIDs []primitive.ObjectID) error {
+	ret := _m.Called(ctx, postIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteCommentsByPosts")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []primitive.ObjectID) error); ok {
+		r0 = rf(ctx, postIDs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetByID provides a mock function with given fields: ctx, id
 func (_m *ICommentRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*comment.Comment, error) {
 	ret := _m.Called(ctx, id)
@@ -95,6 +143,36 @@ func (_m *ICommentRepository) GetByID(ctx context.Context, id primitive.ObjectID
 	return r0, r1
 }
 
+// GetCommentsByAuthor provides a mock function with given fields: ctx, authorID
+func (_m *ICommentRepository) GetCommentsByAuthor(ctx context.Context, authorID primitive.ObjectID) ([]comment.Comment, error) {
+	ret := _m.Called(ctx, authorID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCommentsByAuthor")
+	}
+
+	var r0 []comment.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) ([]comment.Comment, error)); ok {
+		return rf(ctx, authorID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) []comment.Comment); ok {
+		r0 = rf(ctx, authorID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]comment.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID) error); ok {
+		r1 = rf(ctx, authorID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetCommentsByPost provides a mock function with given fields: ctx, postID, pagination
 func (_m *ICommentRepository) GetCommentsByPost(ctx context.Context, postID primitive.ObjectID, pagination comment.CommentPagination) ([]comment.Comment, int64, error) {
 	ret := _m.Called(ctx, postID, pagination)
@@ -132,6 +210,24 @@ func (_m *ICommentRepository) GetCommentsByPost(ctx context.Context, postID prim
 	return r0, r1, r2
 }
 
+// ReassignCommentsAuthor provides a mock function with given fields: ctx, fromID, toID
+func (_m *ICommentRepository) ReassignCommentsAuthor(ctx context.Context, fromID primitive.ObjectID, toID primitive.ObjectID) error {
+	ret := _m.Called(ctx, fromID, toID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReassignCommentsAuthor")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, primitive.ObjectID) error); ok {
+		r0 = rf(ctx, fromID, toID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateComment provides a mock function with given fields: ctx, id, content
 func (_m *ICommentRepository) UpdateComment(ctx context.Context, id primitive.ObjectID, content string) (*comment.Comment, error) {
 	ret := _m.Called(ctx, id, content)
@@ -12,9 +12,9 @@ type IJWTService struct {
 	mock.Mock
 }
 
-// GenerateToken provides a mock function with given fields: userID, username, role
-func (_m *IJWTService) GenerateToken(userID string, username string, role string) (userpkg.TokenResult, error) {
-	ret := _m.Called(userID, username, role)
+// GenerateToken provides a mock function with given fields: subject
+func (_m *IJWTService) GenerateToken(subject userpkg.TokenSubject) (userpkg.TokenResult, error) {
+	ret := _m.Called(subject)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GenerateToken")
@@ -22,17 +22,17 @@ func (_m *IJWTService) GenerateToken(userID string, username string, role string
 
 	var r0 userpkg.TokenResult
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string, string) (userpkg.TokenResult, error)); ok {
-		return rf(userID, username, role)
+	if rf, ok := ret.Get(0).(func(userpkg.TokenSubject) (userpkg.TokenResult, error)); ok {
+		return rf(subject)
 	}
-	if rf, ok := ret.Get(0).(func(string, string, string) userpkg.TokenResult); ok {
-		r0 = rf(userID, username, role)
+	if rf, ok := ret.Get(0).(func(userpkg.TokenSubject) userpkg.TokenResult); ok {
+		r0 = rf(subject)
 	} else {
 		r0 = ret.Get(0).(userpkg.TokenResult)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
-		r1 = rf(userID, username, role)
+	if rf, ok := ret.Get(1).(func(userpkg.TokenSubject) error); ok {
+		r1 = rf(subject)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -40,6 +40,24 @@ func (_m *IJWTService) GenerateToken(userID string, username string, role string
 	return r0, r1
 }
 
+// PublicKeys provides a mock function with no fields
+func (_m *IJWTService) PublicKeys() userpkg.JWKSet {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublicKeys")
+	}
+
+	var r0 userpkg.JWKSet
+	if rf, ok := ret.Get(0).(func() userpkg.JWKSet); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(userpkg.JWKSet)
+	}
+
+	return r0
+}
+
 // ValidateToken provides a mock function with given fields: tokenString
 func (_m *IJWTService) ValidateToken(tokenString string) (map[string]interface{}, error) {
 	ret := _m.Called(tokenString)
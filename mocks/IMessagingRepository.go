@@ -132,6 +132,24 @@ func (_m *IMessagingRepository) GetUserConversations(ctx context.Context, userID
 	return r0, r1
 }
 
+// ReplaceParticipant provides a mock function with given fields: ctx, fromID, toID
+func (_m *IMessagingRepository) ReplaceParticipant(ctx context.Context, fromID primitive.ObjectID, toID primitive.ObjectID) error {
+	ret := _m.Called(ctx, fromID, toID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplaceParticipant")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, primitive.ObjectID) error); ok {
+		r0 = rf(ctx, fromID, toID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SaveMessage provides a mock function with given fields: ctx, msg
 func (_m *IMessagingRepository) SaveMessage(ctx context.Context, msg messaging.Message) (messaging.Message, error) {
 	ret := _m.Called(ctx, msg)
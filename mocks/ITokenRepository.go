@@ -5,8 +5,10 @@ package mocks
 import (
 	context "context"
 
-	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
 	mock "github.com/stretchr/testify/mock"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+
+	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
 )
 
 // ITokenRepository is an autogenerated mock type for the ITokenRepository type
@@ -32,6 +34,60 @@ func (_m *ITokenRepository) DeleteByRefreshToken(ctx context.Context, refreshTok
 	return r0
 }
 
+// DeleteOtherSessions provides a mock function with given fields: ctx, userID, keepFamilyID
+func (_m *ITokenRepository) DeleteOtherSessions(ctx context.Context, userID primitive.ObjectID, keepFamilyID primitive.ObjectID) error {
+	ret := _m.Called(ctx, userID, keepFamilyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteOtherSessions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, primitive.ObjectID) error); ok {
+		r0 = rf(ctx, userID, keepFamilyID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteSession provides a mock function with given fields: ctx, userID, familyID
+func (_m *ITokenRepository) DeleteSession(ctx context.Context, userID primitive.ObjectID, familyID primitive.ObjectID) error {
+	ret := _m.Called(ctx, userID, familyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, primitive.ObjectID) error); ok {
+		r0 = rf(ctx, userID, familyID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteTokensByFamilyID provides a mock function with given fields: ctx, familyID
+func (_m *ITokenRepository) DeleteTokensByFamilyID(ctx context.Context, familyID primitive.ObjectID) error {
+	ret := _m.Called(ctx, familyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTokensByFamilyID")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) error); ok {
+		r0 = rf(ctx, familyID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteTokensByUserID provides a mock function with given fields: ctx, userID
 func (_m *ITokenRepository) DeleteTokensByUserID(ctx context.Context, userID string) error {
 	ret := _m.Called(ctx, userID)
@@ -50,6 +106,36 @@ func (_m *ITokenRepository) DeleteTokensByUserID(ctx context.Context, userID str
 	return r0
 }
 
+// FindActiveSessionsByUserID provides a mock function with given fields: ctx, userID
+func (_m *ITokenRepository) FindActiveSessionsByUserID(ctx context.Context, userID primitive.ObjectID) ([]userpkg.Token, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindActiveSessionsByUserID")
+	}
+
+	var r0 []userpkg.Token
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) ([]userpkg.Token, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) []userpkg.Token); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]userpkg.Token)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindByRefreshToken provides a mock function with given fields: ctx, refreshToken
 func (_m *ITokenRepository) FindByRefreshToken(ctx context.Context, refreshToken string) (userpkg.Token, error) {
 	ret := _m.Called(ctx, refreshToken)
@@ -78,6 +164,24 @@ func (_m *ITokenRepository) FindByRefreshToken(ctx context.Context, refreshToken
 	return r0, r1
 }
 
+// MarkTokenRotated provides a mock function with given fields: ctx, tokenID
+func (_m *ITokenRepository) MarkTokenRotated(ctx context.Context, tokenID primitive.ObjectID) error {
+	ret := _m.Called(ctx, tokenID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkTokenRotated")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) error); ok {
+		r0 = rf(ctx, tokenID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // StoreToken provides a mock function with given fields: ctx, token
 func (_m *ITokenRepository) StoreToken(ctx context.Context, token userpkg.Token) error {
 	ret := _m.Called(ctx, token)
@@ -96,6 +200,24 @@ func (_m *ITokenRepository) StoreToken(ctx context.Context, token userpkg.Token)
 	return r0
 }
 
+// TouchSession provides a mock function with given fields: ctx, familyID
+func (_m *ITokenRepository) TouchSession(ctx context.Context, familyID primitive.ObjectID) error {
+	ret := _m.Called(ctx, familyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TouchSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) error); ok {
+		r0 = rf(ctx, familyID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewITokenRepository creates a new instance of ITokenRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewITokenRepository(t interface {
@@ -7,6 +7,8 @@ import (
 
 	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
 	mock "github.com/stretchr/testify/mock"
+
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // IUserRepository is an autogenerated mock type for the IUserRepository type
@@ -14,6 +16,88 @@ type IUserRepository struct {
 	mock.Mock
 }
 
+// AddIdentity provides a mock function with given fields: ctx, userID, identity
+func (_m *IUserRepository) AddIdentity(ctx context.Context, userID string, identity userpkg.LinkedIdentity) error {
+	ret := _m.Called(ctx, userID, identity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddIdentity")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, userpkg.LinkedIdentity) error); ok {
+		r0 = rf(ctx, userID, identity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ClearStudentInstitution provides a mock function with given fields: ctx, institutionID
+func (_m *IUserRepository) ClearStudentInstitution(ctx context.Context, institutionID primitive.ObjectID) (int64, error) {
+	ret := _m.Called(ctx, institutionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClearStudentInstitution")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) (int64, error)); ok {
+		return rf(ctx, institutionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) int64); ok {
+		r0 = rf(ctx, institutionID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID) error); ok {
+		r1 = rf(ctx, institutionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConsumeRecoveryCode provides a mock function with given fields: ctx, userID, codeHash
+func (_m *IUserRepository) ConsumeRecoveryCode(ctx context.Context, userID string, codeHash string) error {
+	ret := _m.Called(ctx, userID, codeHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConsumeRecoveryCode")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, codeHash)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ConsumeTOTPStep provides a mock function with given fields: ctx, userID, step
+func (_m *IUserRepository) ConsumeTOTPStep(ctx context.Context, userID string, step int64) error {
+	ret := _m.Called(ctx, userID, step)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConsumeTOTPStep")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) error); ok {
+		r0 = rf(ctx, userID, step)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CountUsers provides a mock function with given fields: ctx
 func (_m *IUserRepository) CountUsers(ctx context.Context) (int64, error) {
 	ret := _m.Called(ctx)
@@ -70,6 +154,52 @@ func (_m *IUserRepository) CreateUser(ctx context.Context, user userpkg.User) (u
 	return r0, r1
 }
 
+// DeleteUser provides a mock function with given fields: ctx, userID
+func (_m *IUserRepository) DeleteUser(ctx context.Context, userID string) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnsureTombstoneUser provides a mock function with given fields: ctx
+func (_m *IUserRepository) EnsureTombstoneUser(ctx context.Context) (userpkg.User, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnsureTombstoneUser")
+	}
+
+	var r0 userpkg.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (userpkg.User, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) userpkg.User); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(userpkg.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ExistsByDisplayName provides a mock function with given fields: ctx, displayName
 func (_m *IUserRepository) ExistsByDisplayName(ctx context.Context, displayName string) (bool, error) {
 	ret := _m.Called(ctx, displayName)
@@ -154,6 +284,34 @@ func (_m *IUserRepository) ExistsByUsername(ctx context.Context, username string
 	return r0, r1
 }
 
+// FindByEmail provides a mock function with given fields: ctx, email
+func (_m *IUserRepository) FindByEmail(ctx context.Context, email string) (userpkg.User, error) {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByEmail")
+	}
+
+	var r0 userpkg.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (userpkg.User, error)); ok {
+		return rf(ctx, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) userpkg.User); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Get(0).(userpkg.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindByID provides a mock function with given fields: ctx, userID
 func (_m *IUserRepository) FindByID(ctx context.Context, userID string) (userpkg.User, error) {
 	ret := _m.Called(ctx, userID)
@@ -182,6 +340,34 @@ func (_m *IUserRepository) FindByID(ctx context.Context, userID string) (userpkg
 	return r0, r1
 }
 
+// FindByIdentity provides a mock function with given fields: ctx, provider, subject
+func (_m *IUserRepository) FindByIdentity(ctx context.Context, provider string, subject string) (userpkg.User, error) {
+	ret := _m.Called(ctx, provider, subject)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByIdentity")
+	}
+
+	var r0 userpkg.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (userpkg.User, error)); ok {
+		return rf(ctx, provider, subject)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) userpkg.User); ok {
+		r0 = rf(ctx, provider, subject)
+	} else {
+		r0 = ret.Get(0).(userpkg.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, provider, subject)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindMentees provides a mock function with given fields: ctx, topics, limit, offset
 func (_m *IUserRepository) FindMentees(ctx context.Context, topics []string, limit int, offset int) ([]userpkg.PublicProfile, error) {
 	ret := _m.Called(ctx, topics, limit, offset)
@@ -212,9 +398,9 @@ func (_m *IUserRepository) FindMentees(ctx context.Context, topics []string, lim
 	return r0, r1
 }
 
-// FindMentors provides a mock function with given fields: ctx, topics, limit, offset
-func (_m *IUserRepository) FindMentors(ctx context.Context, topics []string, limit int, offset int) ([]userpkg.PublicProfile, error) {
-	ret := _m.Called(ctx, topics, limit, offset)
+// FindMentors provides a mock function with given fields: ctx, topics, institutionID, limit, offset
+func (_m *IUserRepository) FindMentors(ctx context.Context, topics []string, institutionID primitive.ObjectID, limit int, offset int) ([]userpkg.PublicProfile, error) {
+	ret := _m.Called(ctx, topics, institutionID, limit, offset)
 
 	if len(ret) == 0 {
 		panic("no return value specified for FindMentors")
@@ -222,19 +408,49 @@ func (_m *IUserRepository) FindMentors(ctx context.Context, topics []string, lim
 
 	var r0 []userpkg.PublicProfile
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, []string, int, int) ([]userpkg.PublicProfile, error)); ok {
-		return rf(ctx, topics, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, []string, primitive.ObjectID, int, int) ([]userpkg.PublicProfile, error)); ok {
+		return rf(ctx, topics, institutionID, limit, offset)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, []string, int, int) []userpkg.PublicProfile); ok {
-		r0 = rf(ctx, topics, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, []string, primitive.ObjectID, int, int) []userpkg.PublicProfile); ok {
+		r0 = rf(ctx, topics, institutionID, limit, offset)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]userpkg.PublicProfile)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, []string, int, int) error); ok {
-		r1 = rf(ctx, topics, limit, offset)
+	if rf, ok := ret.Get(1).(func(context.Context, []string, primitive.ObjectID, int, int) error); ok {
+		r1 = rf(ctx, topics, institutionID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindStudentIDs provides a mock function with given fields: ctx, institutionID
+func (_m *IUserRepository) FindStudentIDs(ctx context.Context, institutionID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	ret := _m.Called(ctx, institutionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindStudentIDs")
+	}
+
+	var r0 []primitive.ObjectID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) ([]primitive.ObjectID, error)); ok {
+		return rf(ctx, institutionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) []primitive.ObjectID); ok {
+		r0 = rf(ctx, institutionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]primitive.ObjectID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID) error); ok {
+		r1 = rf(ctx, institutionID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -326,6 +542,61 @@ func (_m *IUserRepository) GetUserProfile(ctx context.Context, userID string) (u
 	return r0, r1
 }
 
+// ListUsers provides a mock function with given fields: ctx, filter
+func (_m *IUserRepository) ListUsers(ctx context.Context, filter userpkg.UserFilter) ([]userpkg.User, int64, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUsers")
+	}
+
+	var r0 []userpkg.User
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, userpkg.UserFilter) ([]userpkg.User, int64, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, userpkg.UserFilter) []userpkg.User); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]userpkg.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, userpkg.UserFilter) int64); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, userpkg.UserFilter) error); ok {
+		r2 = rf(ctx, filter)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// RenameStudentInstitution provides a mock function with given fields: ctx, institutionID, name
+func (_m *IUserRepository) RenameStudentInstitution(ctx context.Context, institutionID primitive.ObjectID, name string) error {
+	ret := _m.Called(ctx, institutionID, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RenameStudentInstitution")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, string) error); ok {
+		r0 = rf(ctx, institutionID, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // SearchUsersByTopic provides a mock function with given fields: ctx, topic, isMentor, limit, offset
 func (_m *IUserRepository) SearchUsersByTopic(ctx context.Context, topic string, isMentor bool, limit int, offset int) ([]userpkg.PublicProfile, error) {
 	ret := _m.Called(ctx, topic, isMentor, limit, offset)
@@ -356,6 +627,52 @@ func (_m *IUserRepository) SearchUsersByTopic(ctx context.Context, topic string,
 	return r0, r1
 }
 
+// StudentEmailTaken provides a mock function with given fields: ctx, email, userID
+func (_m *IUserRepository) StudentEmailTaken(ctx context.Context, email string, userID string) (bool, error) {
+	ret := _m.Called(ctx, email, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StudentEmailTaken")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (bool, error)); ok {
+		return rf(ctx, email, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) bool); ok {
+		r0 = rf(ctx, email, userID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, email, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateEmail provides a mock function with given fields: ctx, userID, email
+func (_m *IUserRepository) UpdateEmail(ctx context.Context, userID string, email string) error {
+	ret := _m.Called(ctx, userID, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateEmail")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateIsVerifiedByEmail provides a mock function with given fields: ctx, email, verified
 func (_m *IUserRepository) UpdateIsVerifiedByEmail(ctx context.Context, email string, verified bool) error {
 	ret := _m.Called(ctx, email, verified)
@@ -374,6 +691,42 @@ func (_m *IUserRepository) UpdateIsVerifiedByEmail(ctx context.Context, email st
 	return r0
 }
 
+// UpdateMFA provides a mock function with given fields: ctx, userID, mfa
+func (_m *IUserRepository) UpdateMFA(ctx context.Context, userID string, mfa userpkg.MFASettings) error {
+	ret := _m.Called(ctx, userID, mfa)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateMFA")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, userpkg.MFASettings) error); ok {
+		r0 = rf(ctx, userID, mfa)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePassword provides a mock function with given fields: ctx, userID, hashedPassword, keepHistory
+func (_m *IUserRepository) UpdatePassword(ctx context.Context, userID string, hashedPassword string, keepHistory int) error {
+	ret := _m.Called(ctx, userID, hashedPassword, keepHistory)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) error); ok {
+		r0 = rf(ctx, userID, hashedPassword, keepHistory)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdatePasswordByEmail provides a mock function with given fields: ctx, email, hashedPassword
 func (_m *IUserRepository) UpdatePasswordByEmail(ctx context.Context, email string, hashedPassword string) error {
 	ret := _m.Called(ctx, email, hashedPassword)
@@ -420,6 +773,24 @@ func (_m *IUserRepository) UpdateProfile(ctx context.Context, userID string, upd
 	return r0, r1
 }
 
+// UpdateRecoveryCodes provides a mock function with given fields: ctx, userID, codeHashes
+func (_m *IUserRepository) UpdateRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error {
+	ret := _m.Called(ctx, userID, codeHashes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateRecoveryCodes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) error); ok {
+		r0 = rf(ctx, userID, codeHashes)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateRoleAndPromoter provides a mock function with given fields: ctx, userID, role, promoterID
 func (_m *IUserRepository) UpdateRoleAndPromoter(ctx context.Context, userID string, role string, promoterID *string) error {
 	ret := _m.Called(ctx, userID, role, promoterID)
@@ -438,6 +809,42 @@ func (_m *IUserRepository) UpdateRoleAndPromoter(ctx context.Context, userID str
 	return r0
 }
 
+// UpdateStudentStatus provides a mock function with given fields: ctx, userID, status
+func (_m *IUserRepository) UpdateStudentStatus(ctx context.Context, userID string, status *userpkg.StudentStatus) error {
+	ret := _m.Called(ctx, userID, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateStudentStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *userpkg.StudentStatus) error); ok {
+		r0 = rf(ctx, userID, status)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateSuspension provides a mock function with given fields: ctx, userID, suspension
+func (_m *IUserRepository) UpdateSuspension(ctx context.Context, userID string, suspension *userpkg.Suspension) error {
+	ret := _m.Called(ctx, userID, suspension)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateSuspension")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *userpkg.Suspension) error); ok {
+		r0 = rf(ctx, userID, suspension)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateUserRoleByID provides a mock function with given fields: ctx, userID, role
 func (_m *IUserRepository) UpdateUserRoleByID(ctx context.Context, userID string, role string) error {
 	ret := _m.Called(ctx, userID, role)
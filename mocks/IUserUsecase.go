@@ -4,10 +4,14 @@ package mocks
 
 import (
 	context "context"
-	multipart "mime/multipart"
+	io "io"
 
 	mock "github.com/stretchr/testify/mock"
 
+	multipart "mime/multipart"
+
+	time "time"
+
 	userpkg "github.com/Amaankaa/Blog-Starter-Project/Domain/user"
 )
 
@@ -16,12 +20,821 @@ type IUserUsecase struct {
 	mock.Mock
 }
 
+// CancelAccountDeletion provides a mock function with given fields: ctx, userID
+func (_m *IUserUsecase) CancelAccountDeletion(ctx context.Context, userID string) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelAccountDeletion")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CancelEmailChange provides a mock function with given fields: ctx, userID, signature
+func (_m *IUserUsecase) CancelEmailChange(ctx context.Context, userID string, signature string) error {
+	ret := _m.Called(ctx, userID, signature)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelEmailChange")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, signature)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ChangePassword provides a mock function with given fields: ctx, userID, sessionID, currentPassword, newPassword
+func (_m *IUserUsecase) ChangePassword(ctx context.Context, userID string, sessionID string, currentPassword string, newPassword string) error {
+	ret := _m.Called(ctx, userID, sessionID, currentPassword, newPassword)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangePassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) error); ok {
+		r0 = rf(ctx, userID, sessionID, currentPassword, newPassword)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CompleteMFALogin provides a mock function with given fields: ctx, mfaToken, code, device
+func (_m *IUserUsecase) CompleteMFALogin(ctx context.Context, mfaToken string, code string, device userpkg.DeviceInfo) (userpkg.LoginResult, error) {
+	ret := _m.Called(ctx, mfaToken, code, device)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteMFALogin")
+	}
+
+	var r0 userpkg.LoginResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, userpkg.DeviceInfo) (userpkg.LoginResult, error)); ok {
+		return rf(ctx, mfaToken, code, device)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, userpkg.DeviceInfo) userpkg.LoginResult); ok {
+		r0 = rf(ctx, mfaToken, code, device)
+	} else {
+		r0 = ret.Get(0).(userpkg.LoginResult)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, userpkg.DeviceInfo) error); ok {
+		r1 = rf(ctx, mfaToken, code, device)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CompleteOIDCLogin provides a mock function with given fields: ctx, provider, state, browserState, code, device
+func (_m *IUserUsecase) CompleteOIDCLogin(ctx context.Context, provider string, state string, browserState string, code string, device userpkg.DeviceInfo) (userpkg.LoginResult, error) {
+	ret := _m.Called(ctx, provider, state, browserState, code, device)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteOIDCLogin")
+	}
+
+	var r0 userpkg.LoginResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, userpkg.DeviceInfo) (userpkg.LoginResult, error)); ok {
+		return rf(ctx, provider, state, browserState, code, device)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string, userpkg.DeviceInfo) userpkg.LoginResult); ok {
+		r0 = rf(ctx, provider, state, browserState, code, device)
+	} else {
+		r0 = ret.Get(0).(userpkg.LoginResult)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string, userpkg.DeviceInfo) error); ok {
+		r1 = rf(ctx, provider, state, browserState, code, device)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConfirmEmailChange provides a mock function with given fields: ctx, userID, otp, oldEmailOTP
+func (_m *IUserUsecase) ConfirmEmailChange(ctx context.Context, userID string, otp string, oldEmailOTP string) (userpkg.User, error) {
+	ret := _m.Called(ctx, userID, otp, oldEmailOTP)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConfirmEmailChange")
+	}
+
+	var r0 userpkg.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (userpkg.User, error)); ok {
+		return rf(ctx, userID, otp, oldEmailOTP)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) userpkg.User); ok {
+		r0 = rf(ctx, userID, otp, oldEmailOTP)
+	} else {
+		r0 = ret.Get(0).(userpkg.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, userID, otp, oldEmailOTP)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConfirmMFA provides a mock function with given fields: ctx, userID, code
+func (_m *IUserUsecase) ConfirmMFA(ctx context.Context, userID string, code string) ([]string, error) {
+	ret := _m.Called(ctx, userID, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConfirmMFA")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]string, error)); ok {
+		return rf(ctx, userID, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []string); ok {
+		r0 = rf(ctx, userID, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ConfirmStudentVerification provides a mock function with given fields: ctx, userID, email, otp
+func (_m *IUserUsecase) ConfirmStudentVerification(ctx context.Context, userID string, email string, otp string) (userpkg.User, error) {
+	ret := _m.Called(ctx, userID, email, otp)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConfirmStudentVerification")
+	}
+
+	var r0 userpkg.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (userpkg.User, error)); ok {
+		return rf(ctx, userID, email, otp)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) userpkg.User); ok {
+		r0 = rf(ctx, userID, email, otp)
+	} else {
+		r0 = ret.Get(0).(userpkg.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, userID, email, otp)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateAccessToken provides a mock function with given fields: ctx, userID, name, scopes, expiresAt
+func (_m *IUserUsecase) CreateAccessToken(ctx context.Context, userID string, name string, scopes []string, expiresAt *time.Time) (userpkg.CreatedAccessToken, error) {
+	ret := _m.Called(ctx, userID, name, scopes, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateAccessToken")
+	}
+
+	var r0 userpkg.CreatedAccessToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string, *time.Time) (userpkg.CreatedAccessToken, error)); ok {
+		return rf(ctx, userID, name, scopes, expiresAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string, *time.Time) userpkg.CreatedAccessToken); ok {
+		r0 = rf(ctx, userID, name, scopes, expiresAt)
+	} else {
+		r0 = ret.Get(0).(userpkg.CreatedAccessToken)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, []string, *time.Time) error); ok {
+		r1 = rf(ctx, userID, name, scopes, expiresAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // DemoteUser provides a mock function with given fields: ctx, targetUserID, actorUserID
 func (_m *IUserUsecase) DemoteUser(ctx context.Context, targetUserID string, actorUserID string) error {
 	ret := _m.Called(ctx, targetUserID, actorUserID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DemoteUser")
+		panic("no return value specified for DemoteUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, targetUserID, actorUserID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DisableMFA provides a mock function with given fields: ctx, userID, password, code
+func (_m *IUserUsecase) DisableMFA(ctx context.Context, userID string, password string, code string) error {
+	ret := _m.Called(ctx, userID, password, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DisableMFA")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, userID, password, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnrollMFA provides a mock function with given fields: ctx, userID
+func (_m *IUserUsecase) EnrollMFA(ctx context.Context, userID string) (userpkg.MFAEnrollment, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnrollMFA")
+	}
+
+	var r0 userpkg.MFAEnrollment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (userpkg.MFAEnrollment, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) userpkg.MFAEnrollment); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(userpkg.MFAEnrollment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindMentees provides a mock function with given fields: ctx, topics, limit, offset
+func (_m *IUserUsecase) FindMentees(ctx context.Context, topics []string, limit int, offset int) ([]userpkg.PublicProfile, error) {
+	ret := _m.Called(ctx, topics, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindMentees")
+	}
+
+	var r0 []userpkg.PublicProfile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, int, int) ([]userpkg.PublicProfile, error)); ok {
+		return rf(ctx, topics, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string, int, int) []userpkg.PublicProfile); ok {
+		r0 = rf(ctx, topics, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]userpkg.PublicProfile)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string, int, int) error); ok {
+		r1 = rf(ctx, topics, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindMentors provides a mock function with given fields: ctx, topics, institutionID, limit, offset
+func (_m *IUserUsecase) FindMentors(ctx context.Context, topics []string, institutionID string, limit int, offset int) ([]userpkg.PublicProfile, error) {
+	ret := _m.Called(ctx, topics, institutionID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindMentors")
+	}
+
+	var r0 []userpkg.PublicProfile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, string, int, int) ([]userpkg.PublicProfile, error)); ok {
+		return rf(ctx, topics, institutionID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string, string, int, int) []userpkg.PublicProfile); ok {
+		r0 = rf(ctx, topics, institutionID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]userpkg.PublicProfile)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string, string, int, int) error); ok {
+		r1 = rf(ctx, topics, institutionID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ForceReverification provides a mock function with given fields: ctx, targetUserID, actorUserID
+func (_m *IUserUsecase) ForceReverification(ctx context.Context, targetUserID string, actorUserID string) error {
+	ret := _m.Called(ctx, targetUserID, actorUserID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ForceReverification")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, targetUserID, actorUserID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GenerateDisplayName provides a mock function with given fields: ctx, baseName
+func (_m *IUserUsecase) GenerateDisplayName(ctx context.Context, baseName string) (string, error) {
+	ret := _m.Called(ctx, baseName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GenerateDisplayName")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(ctx, baseName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, baseName)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, baseName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAccountDeletion provides a mock function with given fields: ctx, userID
+func (_m *IUserUsecase) GetAccountDeletion(ctx context.Context, userID string) (userpkg.AccountDeletion, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAccountDeletion")
+	}
+
+	var r0 userpkg.AccountDeletion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (userpkg.AccountDeletion, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) userpkg.AccountDeletion); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(userpkg.AccountDeletion)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAvailableMentorshipTopics provides a mock function with no fields
+func (_m *IUserUsecase) GetAvailableMentorshipTopics() []string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAvailableMentorshipTopics")
+	}
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
+// GetDataExport provides a mock function with given fields: ctx, userID, exportID
+func (_m *IUserUsecase) GetDataExport(ctx context.Context, userID string, exportID string) (userpkg.DataExport, error) {
+	ret := _m.Called(ctx, userID, exportID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDataExport")
+	}
+
+	var r0 userpkg.DataExport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (userpkg.DataExport, error)); ok {
+		return rf(ctx, userID, exportID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) userpkg.DataExport); ok {
+		r0 = rf(ctx, userID, exportID)
+	} else {
+		r0 = ret.Get(0).(userpkg.DataExport)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, exportID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPublicProfile provides a mock function with given fields: ctx, userID
+func (_m *IUserUsecase) GetPublicProfile(ctx context.Context, userID string) (userpkg.PublicProfile, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPublicProfile")
+	}
+
+	var r0 userpkg.PublicProfile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (userpkg.PublicProfile, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) userpkg.PublicProfile); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(userpkg.PublicProfile)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserForAdmin provides a mock function with given fields: ctx, userID
+func (_m *IUserUsecase) GetUserForAdmin(ctx context.Context, userID string) (userpkg.AdminUserView, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserForAdmin")
+	}
+
+	var r0 userpkg.AdminUserView
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (userpkg.AdminUserView, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) userpkg.AdminUserView); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(userpkg.AdminUserView)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserProfile provides a mock function with given fields: ctx, userID
+func (_m *IUserUsecase) GetUserProfile(ctx context.Context, userID string) (userpkg.User, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserProfile")
+	}
+
+	var r0 userpkg.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (userpkg.User, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) userpkg.User); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(userpkg.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GrantRole provides a mock function with given fields: ctx, targetUserID, role, actorUserID
+func (_m *IUserUsecase) GrantRole(ctx context.Context, targetUserID string, role string, actorUserID string) error {
+	ret := _m.Called(ctx, targetUserID, role, actorUserID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GrantRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, targetUserID, role, actorUserID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LiftSuspension provides a mock function with given fields: ctx, targetUserID, actorUserID
+func (_m *IUserUsecase) LiftSuspension(ctx context.Context, targetUserID string, actorUserID string) error {
+	ret := _m.Called(ctx, targetUserID, actorUserID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LiftSuspension")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, targetUserID, actorUserID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListAccessTokens provides a mock function with given fields: ctx, userID
+func (_m *IUserUsecase) ListAccessTokens(ctx context.Context, userID string) ([]userpkg.PersonalAccessToken, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAccessTokens")
+	}
+
+	var r0 []userpkg.PersonalAccessToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]userpkg.PersonalAccessToken, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []userpkg.PersonalAccessToken); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]userpkg.PersonalAccessToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListSessions provides a mock function with given fields: ctx, userID, currentSessionID
+func (_m *IUserUsecase) ListSessions(ctx context.Context, userID string, currentSessionID string) ([]userpkg.Session, error) {
+	ret := _m.Called(ctx, userID, currentSessionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSessions")
+	}
+
+	var r0 []userpkg.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]userpkg.Session, error)); ok {
+		return rf(ctx, userID, currentSessionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []userpkg.Session); ok {
+		r0 = rf(ctx, userID, currentSessionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]userpkg.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, currentSessionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListUsers provides a mock function with given fields: ctx, filter
+func (_m *IUserUsecase) ListUsers(ctx context.Context, filter userpkg.UserFilter) (userpkg.UserListResponse, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUsers")
+	}
+
+	var r0 userpkg.UserListResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, userpkg.UserFilter) (userpkg.UserListResponse, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, userpkg.UserFilter) userpkg.UserListResponse); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		r0 = ret.Get(0).(userpkg.UserListResponse)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, userpkg.UserFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LoginUser provides a mock function with given fields: ctx, login, password, device
+func (_m *IUserUsecase) LoginUser(ctx context.Context, login string, password string, device userpkg.DeviceInfo) (userpkg.LoginResult, error) {
+	ret := _m.Called(ctx, login, password, device)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LoginUser")
+	}
+
+	var r0 userpkg.LoginResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, userpkg.DeviceInfo) (userpkg.LoginResult, error)); ok {
+		return rf(ctx, login, password, device)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, userpkg.DeviceInfo) userpkg.LoginResult); ok {
+		r0 = rf(ctx, login, password, device)
+	} else {
+		r0 = ret.Get(0).(userpkg.LoginResult)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, userpkg.DeviceInfo) error); ok {
+		r1 = rf(ctx, login, password, device)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LoginWithMagicLink provides a mock function with given fields: ctx, email, token, expiresAt, signature, device
+func (_m *IUserUsecase) LoginWithMagicLink(ctx context.Context, email string, token string, expiresAt time.Time, signature string, device userpkg.DeviceInfo) (userpkg.LoginResult, error) {
+	ret := _m.Called(ctx, email, token, expiresAt, signature, device)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LoginWithMagicLink")
+	}
+
+	var r0 userpkg.LoginResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time, string, userpkg.DeviceInfo) (userpkg.LoginResult, error)); ok {
+		return rf(ctx, email, token, expiresAt, signature, device)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time, string, userpkg.DeviceInfo) userpkg.LoginResult); ok {
+		r0 = rf(ctx, email, token, expiresAt, signature, device)
+	} else {
+		r0 = ret.Get(0).(userpkg.LoginResult)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, time.Time, string, userpkg.DeviceInfo) error); ok {
+		r1 = rf(ctx, email, token, expiresAt, signature, device)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Logout provides a mock function with given fields: ctx, userID, sessionID, tokenID
+func (_m *IUserUsecase) Logout(ctx context.Context, userID string, sessionID string, tokenID string) error {
+	ret := _m.Called(ctx, userID, sessionID, tokenID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Logout")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, userID, sessionID, tokenID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// OIDCProviders provides a mock function with no fields
+func (_m *IUserUsecase) OIDCProviders() []string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for OIDCProviders")
+	}
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
+// OpenDataExport provides a mock function with given fields: ctx, exportID, expiresAt, signature
+func (_m *IUserUsecase) OpenDataExport(ctx context.Context, exportID string, expiresAt time.Time, signature string) (io.ReadCloser, userpkg.DataExport, error) {
+	ret := _m.Called(ctx, exportID, expiresAt, signature)
+
+	if len(ret) == 0 {
+		panic("no return value specified for OpenDataExport")
+	}
+
+	var r0 io.ReadCloser
+	var r1 userpkg.DataExport
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, string) (io.ReadCloser, userpkg.DataExport, error)); ok {
+		return rf(ctx, exportID, expiresAt, signature)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, string) io.ReadCloser); ok {
+		r0 = rf(ctx, exportID, expiresAt, signature)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(io.ReadCloser)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, string) userpkg.DataExport); ok {
+		r1 = rf(ctx, exportID, expiresAt, signature)
+	} else {
+		r1 = ret.Get(1).(userpkg.DataExport)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, time.Time, string) error); ok {
+		r2 = rf(ctx, exportID, expiresAt, signature)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// PromoteUser provides a mock function with given fields: ctx, targetUserID, actorUserID
+func (_m *IUserUsecase) PromoteUser(ctx context.Context, targetUserID string, actorUserID string) error {
+	ret := _m.Called(ctx, targetUserID, actorUserID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PromoteUser")
 	}
 
 	var r0 error
@@ -34,29 +847,27 @@ func (_m *IUserUsecase) DemoteUser(ctx context.Context, targetUserID string, act
 	return r0
 }
 
-// FindMentees provides a mock function with given fields: ctx, topics, limit, offset
-func (_m *IUserUsecase) FindMentees(ctx context.Context, topics []string, limit int, offset int) ([]userpkg.PublicProfile, error) {
-	ret := _m.Called(ctx, topics, limit, offset)
+// RefreshToken provides a mock function with given fields: ctx, refreshToken, device
+func (_m *IUserUsecase) RefreshToken(ctx context.Context, refreshToken string, device userpkg.DeviceInfo) (userpkg.TokenResult, error) {
+	ret := _m.Called(ctx, refreshToken, device)
 
 	if len(ret) == 0 {
-		panic("no return value specified for FindMentees")
+		panic("no return value specified for RefreshToken")
 	}
 
-	var r0 []userpkg.PublicProfile
+	var r0 userpkg.TokenResult
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, []string, int, int) ([]userpkg.PublicProfile, error)); ok {
-		return rf(ctx, topics, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, string, userpkg.DeviceInfo) (userpkg.TokenResult, error)); ok {
+		return rf(ctx, refreshToken, device)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, []string, int, int) []userpkg.PublicProfile); ok {
-		r0 = rf(ctx, topics, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, string, userpkg.DeviceInfo) userpkg.TokenResult); ok {
+		r0 = rf(ctx, refreshToken, device)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]userpkg.PublicProfile)
-		}
+		r0 = ret.Get(0).(userpkg.TokenResult)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, []string, int, int) error); ok {
-		r1 = rf(ctx, topics, limit, offset)
+	if rf, ok := ret.Get(1).(func(context.Context, string, userpkg.DeviceInfo) error); ok {
+		r1 = rf(ctx, refreshToken, device)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -64,29 +875,29 @@ func (_m *IUserUsecase) FindMentees(ctx context.Context, topics []string, limit
 	return r0, r1
 }
 
-// FindMentors provides a mock function with given fields: ctx, topics, limit, offset
-func (_m *IUserUsecase) FindMentors(ctx context.Context, topics []string, limit int, offset int) ([]userpkg.PublicProfile, error) {
-	ret := _m.Called(ctx, topics, limit, offset)
+// RegenerateRecoveryCodes provides a mock function with given fields: ctx, userID, code
+func (_m *IUserUsecase) RegenerateRecoveryCodes(ctx context.Context, userID string, code string) ([]string, error) {
+	ret := _m.Called(ctx, userID, code)
 
 	if len(ret) == 0 {
-		panic("no return value specified for FindMentors")
+		panic("no return value specified for RegenerateRecoveryCodes")
 	}
 
-	var r0 []userpkg.PublicProfile
+	var r0 []string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, []string, int, int) ([]userpkg.PublicProfile, error)); ok {
-		return rf(ctx, topics, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]string, error)); ok {
+		return rf(ctx, userID, code)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, []string, int, int) []userpkg.PublicProfile); ok {
-		r0 = rf(ctx, topics, limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []string); ok {
+		r0 = rf(ctx, userID, code)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]userpkg.PublicProfile)
+			r0 = ret.Get(0).([]string)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, []string, int, int) error); ok {
-		r1 = rf(ctx, topics, limit, offset)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, code)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -94,27 +905,27 @@ func (_m *IUserUsecase) FindMentors(ctx context.Context, topics []string, limit
 	return r0, r1
 }
 
-// GenerateDisplayName provides a mock function with given fields: ctx, baseName
-func (_m *IUserUsecase) GenerateDisplayName(ctx context.Context, baseName string) (string, error) {
-	ret := _m.Called(ctx, baseName)
+// RegisterUser provides a mock function with given fields: ctx, user
+func (_m *IUserUsecase) RegisterUser(ctx context.Context, user userpkg.User) (userpkg.User, error) {
+	ret := _m.Called(ctx, user)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GenerateDisplayName")
+		panic("no return value specified for RegisterUser")
 	}
 
-	var r0 string
+	var r0 userpkg.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
-		return rf(ctx, baseName)
+	if rf, ok := ret.Get(0).(func(context.Context, userpkg.User) (userpkg.User, error)); ok {
+		return rf(ctx, user)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
-		r0 = rf(ctx, baseName)
+	if rf, ok := ret.Get(0).(func(context.Context, userpkg.User) userpkg.User); ok {
+		r0 = rf(ctx, user)
 	} else {
-		r0 = ret.Get(0).(string)
+		r0 = ret.Get(0).(userpkg.User)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, baseName)
+	if rf, ok := ret.Get(1).(func(context.Context, userpkg.User) error); ok {
+		r1 = rf(ctx, user)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -122,47 +933,45 @@ func (_m *IUserUsecase) GenerateDisplayName(ctx context.Context, baseName string
 	return r0, r1
 }
 
-// GetAvailableMentorshipTopics provides a mock function with no fields
-func (_m *IUserUsecase) GetAvailableMentorshipTopics() []string {
-	ret := _m.Called()
+// RemoveStudentStatus provides a mock function with given fields: ctx, userID
+func (_m *IUserUsecase) RemoveStudentStatus(ctx context.Context, userID string) error {
+	ret := _m.Called(ctx, userID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetAvailableMentorshipTopics")
+		panic("no return value specified for RemoveStudentStatus")
 	}
 
-	var r0 []string
-	if rf, ok := ret.Get(0).(func() []string); ok {
-		r0 = rf()
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, userID)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]string)
-		}
+		r0 = ret.Error(0)
 	}
 
 	return r0
 }
 
-// GetPublicProfile provides a mock function with given fields: ctx, userID
-func (_m *IUserUsecase) GetPublicProfile(ctx context.Context, userID string) (userpkg.PublicProfile, error) {
-	ret := _m.Called(ctx, userID)
+// RequestAccountDeletion provides a mock function with given fields: ctx, userID, password, contentMode
+func (_m *IUserUsecase) RequestAccountDeletion(ctx context.Context, userID string, password string, contentMode string) (userpkg.AccountDeletion, error) {
+	ret := _m.Called(ctx, userID, password, contentMode)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetPublicProfile")
+		panic("no return value specified for RequestAccountDeletion")
 	}
 
-	var r0 userpkg.PublicProfile
+	var r0 userpkg.AccountDeletion
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (userpkg.PublicProfile, error)); ok {
-		return rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (userpkg.AccountDeletion, error)); ok {
+		return rf(ctx, userID, password, contentMode)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) userpkg.PublicProfile); ok {
-		r0 = rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) userpkg.AccountDeletion); ok {
+		r0 = rf(ctx, userID, password, contentMode)
 	} else {
-		r0 = ret.Get(0).(userpkg.PublicProfile)
+		r0 = ret.Get(0).(userpkg.AccountDeletion)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, userID)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, userID, password, contentMode)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -170,23 +979,23 @@ func (_m *IUserUsecase) GetPublicProfile(ctx context.Context, userID string) (us
 	return r0, r1
 }
 
-// GetUserProfile provides a mock function with given fields: ctx, userID
-func (_m *IUserUsecase) GetUserProfile(ctx context.Context, userID string) (userpkg.User, error) {
+// RequestDataExport provides a mock function with given fields: ctx, userID
+func (_m *IUserUsecase) RequestDataExport(ctx context.Context, userID string) (userpkg.DataExport, error) {
 	ret := _m.Called(ctx, userID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetUserProfile")
+		panic("no return value specified for RequestDataExport")
 	}
 
-	var r0 userpkg.User
+	var r0 userpkg.DataExport
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (userpkg.User, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) (userpkg.DataExport, error)); ok {
 		return rf(ctx, userID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) userpkg.User); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) userpkg.DataExport); ok {
 		r0 = rf(ctx, userID)
 	} else {
-		r0 = ret.Get(0).(userpkg.User)
+		r0 = ret.Get(0).(userpkg.DataExport)
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
@@ -198,59 +1007,91 @@ func (_m *IUserUsecase) GetUserProfile(ctx context.Context, userID string) (user
 	return r0, r1
 }
 
-// LoginUser provides a mock function with given fields: ctx, login, password
-func (_m *IUserUsecase) LoginUser(ctx context.Context, login string, password string) (userpkg.User, string, string, error) {
-	ret := _m.Called(ctx, login, password)
+// RequestEmailChange provides a mock function with given fields: ctx, userID, password, newEmail, clientIP
+func (_m *IUserUsecase) RequestEmailChange(ctx context.Context, userID string, password string, newEmail string, clientIP string) (userpkg.EmailChange, error) {
+	ret := _m.Called(ctx, userID, password, newEmail, clientIP)
 
 	if len(ret) == 0 {
-		panic("no return value specified for LoginUser")
+		panic("no return value specified for RequestEmailChange")
 	}
 
-	var r0 userpkg.User
-	var r1 string
-	var r2 string
-	var r3 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) (userpkg.User, string, string, error)); ok {
-		return rf(ctx, login, password)
+	var r0 userpkg.EmailChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) (userpkg.EmailChange, error)); ok {
+		return rf(ctx, userID, password, newEmail, clientIP)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) userpkg.User); ok {
-		r0 = rf(ctx, login, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) userpkg.EmailChange); ok {
+		r0 = rf(ctx, userID, password, newEmail, clientIP)
 	} else {
-		r0 = ret.Get(0).(userpkg.User)
+		r0 = ret.Get(0).(userpkg.EmailChange)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) string); ok {
-		r1 = rf(ctx, login, password)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string) error); ok {
+		r1 = rf(ctx, userID, password, newEmail, clientIP)
 	} else {
-		r1 = ret.Get(1).(string)
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RequestMagicLink provides a mock function with given fields: ctx, email, clientIP
+func (_m *IUserUsecase) RequestMagicLink(ctx context.Context, email string, clientIP string) error {
+	ret := _m.Called(ctx, email, clientIP)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequestMagicLink")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, email, clientIP)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RequestStudentVerification provides a mock function with given fields: ctx, userID, email, clientIP
+func (_m *IUserUsecase) RequestStudentVerification(ctx context.Context, userID string, email string, clientIP string) (userpkg.StudentVerification, error) {
+	ret := _m.Called(ctx, userID, email, clientIP)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequestStudentVerification")
 	}
 
-	if rf, ok := ret.Get(2).(func(context.Context, string, string) string); ok {
-		r2 = rf(ctx, login, password)
+	var r0 userpkg.StudentVerification
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (userpkg.StudentVerification, error)); ok {
+		return rf(ctx, userID, email, clientIP)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) userpkg.StudentVerification); ok {
+		r0 = rf(ctx, userID, email, clientIP)
 	} else {
-		r2 = ret.Get(2).(string)
+		r0 = ret.Get(0).(userpkg.StudentVerification)
 	}
 
-	if rf, ok := ret.Get(3).(func(context.Context, string, string) error); ok {
-		r3 = rf(ctx, login, password)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, userID, email, clientIP)
 	} else {
-		r3 = ret.Error(3)
+		r1 = ret.Error(1)
 	}
 
-	return r0, r1, r2, r3
+	return r0, r1
 }
 
-// Logout provides a mock function with given fields: ctx, userID
-func (_m *IUserUsecase) Logout(ctx context.Context, userID string) error {
-	ret := _m.Called(ctx, userID)
+// ResetPassword provides a mock function with given fields: ctx, email, resetToken, newPassword
+func (_m *IUserUsecase) ResetPassword(ctx context.Context, email string, resetToken string, newPassword string) error {
+	ret := _m.Called(ctx, email, resetToken, newPassword)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Logout")
+		panic("no return value specified for ResetPassword")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, email, resetToken, newPassword)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -258,12 +1099,12 @@ func (_m *IUserUsecase) Logout(ctx context.Context, userID string) error {
 	return r0
 }
 
-// PromoteUser provides a mock function with given fields: ctx, targetUserID, actorUserID
-func (_m *IUserUsecase) PromoteUser(ctx context.Context, targetUserID string, actorUserID string) error {
+// ResetUserMFA provides a mock function with given fields: ctx, targetUserID, actorUserID
+func (_m *IUserUsecase) ResetUserMFA(ctx context.Context, targetUserID string, actorUserID string) error {
 	ret := _m.Called(ctx, targetUserID, actorUserID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for PromoteUser")
+		panic("no return value specified for ResetUserMFA")
 	}
 
 	var r0 error
@@ -276,73 +1117,71 @@ func (_m *IUserUsecase) PromoteUser(ctx context.Context, targetUserID string, ac
 	return r0
 }
 
-// RefreshToken provides a mock function with given fields: ctx, refreshToken
-func (_m *IUserUsecase) RefreshToken(ctx context.Context, refreshToken string) (userpkg.TokenResult, error) {
-	ret := _m.Called(ctx, refreshToken)
+// RevokeAccessToken provides a mock function with given fields: ctx, userID, tokenID
+func (_m *IUserUsecase) RevokeAccessToken(ctx context.Context, userID string, tokenID string) error {
+	ret := _m.Called(ctx, userID, tokenID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for RefreshToken")
-	}
-
-	var r0 userpkg.TokenResult
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (userpkg.TokenResult, error)); ok {
-		return rf(ctx, refreshToken)
-	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) userpkg.TokenResult); ok {
-		r0 = rf(ctx, refreshToken)
-	} else {
-		r0 = ret.Get(0).(userpkg.TokenResult)
+		panic("no return value specified for RevokeAccessToken")
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, refreshToken)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, tokenID)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
 
-	return r0, r1
+	return r0
 }
 
-// RegisterUser provides a mock function with given fields: ctx, user
-func (_m *IUserUsecase) RegisterUser(ctx context.Context, user userpkg.User) (userpkg.User, error) {
-	ret := _m.Called(ctx, user)
+// RevokeOtherSessions provides a mock function with given fields: ctx, userID, currentSessionID
+func (_m *IUserUsecase) RevokeOtherSessions(ctx context.Context, userID string, currentSessionID string) error {
+	ret := _m.Called(ctx, userID, currentSessionID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for RegisterUser")
+		panic("no return value specified for RevokeOtherSessions")
 	}
 
-	var r0 userpkg.User
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, userpkg.User) (userpkg.User, error)); ok {
-		return rf(ctx, user)
-	}
-	if rf, ok := ret.Get(0).(func(context.Context, userpkg.User) userpkg.User); ok {
-		r0 = rf(ctx, user)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, currentSessionID)
 	} else {
-		r0 = ret.Get(0).(userpkg.User)
+		r0 = ret.Error(0)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, userpkg.User) error); ok {
-		r1 = rf(ctx, user)
+	return r0
+}
+
+// RevokeRole provides a mock function with given fields: ctx, targetUserID, role, actorUserID
+func (_m *IUserUsecase) RevokeRole(ctx context.Context, targetUserID string, role string, actorUserID string) error {
+	ret := _m.Called(ctx, targetUserID, role, actorUserID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, targetUserID, role, actorUserID)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
 
-	return r0, r1
+	return r0
 }
 
-// ResetPassword provides a mock function with given fields: ctx, email, newPassword
-func (_m *IUserUsecase) ResetPassword(ctx context.Context, email string, newPassword string) error {
-	ret := _m.Called(ctx, email, newPassword)
+// RevokeSession provides a mock function with given fields: ctx, userID, sessionID
+func (_m *IUserUsecase) RevokeSession(ctx context.Context, userID string, sessionID string) error {
+	ret := _m.Called(ctx, userID, sessionID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ResetPassword")
+		panic("no return value specified for RevokeSession")
 	}
 
 	var r0 error
 	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
-		r0 = rf(ctx, email, newPassword)
+		r0 = rf(ctx, userID, sessionID)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -380,17 +1219,35 @@ func (_m *IUserUsecase) SearchUsersByTopic(ctx context.Context, topic string, is
 	return r0, r1
 }
 
-// SendResetOTP provides a mock function with given fields: ctx, email
-func (_m *IUserUsecase) SendResetOTP(ctx context.Context, email string) error {
-	ret := _m.Called(ctx, email)
+// SendReauthCode provides a mock function with given fields: ctx, userID, clientIP
+func (_m *IUserUsecase) SendReauthCode(ctx context.Context, userID string, clientIP string) error {
+	ret := _m.Called(ctx, userID, clientIP)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendReauthCode")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, clientIP)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SendResetOTP provides a mock function with given fields: ctx, email, clientIP
+func (_m *IUserUsecase) SendResetOTP(ctx context.Context, email string, clientIP string) error {
+	ret := _m.Called(ctx, email, clientIP)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SendResetOTP")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = rf(ctx, email)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, email, clientIP)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -398,17 +1255,98 @@ func (_m *IUserUsecase) SendResetOTP(ctx context.Context, email string) error {
 	return r0
 }
 
-// SendVerificationOTP provides a mock function with given fields: ctx, email
-func (_m *IUserUsecase) SendVerificationOTP(ctx context.Context, email string) error {
-	ret := _m.Called(ctx, email)
+// SendVerificationOTP provides a mock function with given fields: ctx, email, clientIP
+func (_m *IUserUsecase) SendVerificationOTP(ctx context.Context, email string, clientIP string) error {
+	ret := _m.Called(ctx, email, clientIP)
 
 	if len(ret) == 0 {
 		panic("no return value specified for SendVerificationOTP")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = rf(ctx, email)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, email, clientIP)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// StartOIDCLogin provides a mock function with given fields: ctx, provider
+func (_m *IUserUsecase) StartOIDCLogin(ctx context.Context, provider string) (string, string, error) {
+	ret := _m.Called(ctx, provider)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartOIDCLogin")
+	}
+
+	var r0 string
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, string, error)); ok {
+		return rf(ctx, provider)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, provider)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) string); ok {
+		r1 = rf(ctx, provider)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(ctx, provider)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// SuspendUser provides a mock function with given fields: ctx, targetUserID, actorUserID, reason, expiresAt
+func (_m *IUserUsecase) SuspendUser(ctx context.Context, targetUserID string, actorUserID string, reason string, expiresAt *time.Time) (userpkg.AdminUserView, error) {
+	ret := _m.Called(ctx, targetUserID, actorUserID, reason, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SuspendUser")
+	}
+
+	var r0 userpkg.AdminUserView
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, *time.Time) (userpkg.AdminUserView, error)); ok {
+		return rf(ctx, targetUserID, actorUserID, reason, expiresAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, *time.Time) userpkg.AdminUserView); ok {
+		r0 = rf(ctx, targetUserID, actorUserID, reason, expiresAt)
+	} else {
+		r0 = ret.Get(0).(userpkg.AdminUserView)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, *time.Time) error); ok {
+		r1 = rf(ctx, targetUserID, actorUserID, reason, expiresAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UnlockUser provides a mock function with given fields: ctx, targetUserID, actorUserID
+func (_m *IUserUsecase) UnlockUser(ctx context.Context, targetUserID string, actorUserID string) error {
+	ret := _m.Called(ctx, targetUserID, actorUserID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnlockUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, targetUserID, actorUserID)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -445,21 +1383,31 @@ func (_m *IUserUsecase) UpdateProfile(ctx context.Context, userID string, update
 }
 
 // VerifyOTP provides a mock function with given fields: ctx, email, otp
-func (_m *IUserUsecase) VerifyOTP(ctx context.Context, email string, otp string) error {
+func (_m *IUserUsecase) VerifyOTP(ctx context.Context, email string, otp string) (string, error) {
 	ret := _m.Called(ctx, email, otp)
 
 	if len(ret) == 0 {
 		panic("no return value specified for VerifyOTP")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (string, error)); ok {
+		return rf(ctx, email, otp)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
 		r0 = rf(ctx, email, otp)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(string)
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, email, otp)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
 // VerifyUser provides a mock function with given fields: ctx, email, otp
@@ -64,6 +64,66 @@ func (_m *PostRepository) DeletePost(ctx context.Context, id primitive.ObjectID)
 	return r0
 }
 
+// DeletePostsByAuthor provides a mock function with given fields: ctx, authorID
+func (_m *PostRepository) DeletePostsByAuthor(ctx context.Context, authorID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	ret := _m.Called(ctx, authorID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePostsByAuthor")
+	}
+
+	var r0 []primitive.ObjectID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) ([]primitive.ObjectID, error)); ok {
+		return rf(ctx, authorID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) []primitive.ObjectID); ok {
+		r0 = rf(ctx, authorID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]primitive.ObjectID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID) error); ok {
+		r1 = rf(ctx, authorID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAllPostsByAuthor provides a mock function with given fields: ctx, authorID
+func (_m *PostRepository) GetAllPostsByAuthor(ctx context.Context, authorID primitive.ObjectID) ([]postpkg.Post, error) {
+	ret := _m.Called(ctx, authorID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllPostsByAuthor")
+	}
+
+	var r0 []postpkg.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) ([]postpkg.Post, error)); ok {
+		return rf(ctx, authorID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) []postpkg.Post); ok {
+		r0 = rf(ctx, authorID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]postpkg.Post)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID) error); ok {
+		r1 = rf(ctx, authorID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetPopularPosts provides a mock function with given fields: ctx, limit, timeframe
 func (_m *PostRepository) GetPopularPosts(ctx context.Context, limit int, timeframe string) ([]postpkg.Post, error) {
 	ret := _m.Called(ctx, limit, timeframe)
@@ -302,6 +362,36 @@ func (_m *PostRepository) GetPostsByTag(ctx context.Context, tag string, paginat
 	return r0, r1, r2
 }
 
+// GetPostsLikedByUser provides a mock function with given fields: ctx, userID
+func (_m *PostRepository) GetPostsLikedByUser(ctx context.Context, userID primitive.ObjectID) ([]postpkg.Post, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPostsLikedByUser")
+	}
+
+	var r0 []postpkg.Post
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) ([]postpkg.Post, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) []postpkg.Post); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]postpkg.Post)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTrendingTags provides a mock function with given fields: ctx, limit
 func (_m *PostRepository) GetTrendingTags(ctx context.Context, limit int) ([]string, error) {
 	ret := _m.Called(ctx, limit)
@@ -414,6 +504,24 @@ func (_m *PostRepository) LikePost(ctx context.Context, postID primitive.ObjectI
 	return r0
 }
 
+// ReassignPostsAuthor provides a mock function with given fields: ctx, fromID, toID
+func (_m *PostRepository) ReassignPostsAuthor(ctx context.Context, fromID primitive.ObjectID, toID primitive.ObjectID) error {
+	ret := _m.Called(ctx, fromID, toID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReassignPostsAuthor")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, primitive.ObjectID) error); ok {
+		r0 = rf(ctx, fromID, toID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ReportPost provides a mock function with given fields: ctx, postID
 func (_m *PostRepository) ReportPost(ctx context.Context, postID primitive.ObjectID) error {
 	ret := _m.Called(ctx, postID)
@@ -304,6 +304,24 @@ func (_m *PostUsecase) GetUserPosts(ctx context.Context, userID primitive.Object
 	return r0, r1
 }
 
+// HidePost provides a mock function with given fields: ctx, postID, moderatorID
+func (_m *PostUsecase) HidePost(ctx context.Context, postID primitive.ObjectID, moderatorID primitive.ObjectID) error {
+	ret := _m.Called(ctx, postID, moderatorID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HidePost")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, primitive.ObjectID) error); ok {
+		r0 = rf(ctx, postID, moderatorID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // LikePost provides a mock function with given fields: ctx, postID, userID
 func (_m *PostUsecase) LikePost(ctx context.Context, postID primitive.ObjectID, userID primitive.ObjectID) error {
 	ret := _m.Called(ctx, postID, userID)
@@ -370,6 +388,24 @@ func (_m *PostUsecase) SearchPosts(ctx context.Context, query string, filter pos
 	return r0, r1
 }
 
+// UnhidePost provides a mock function with given fields: ctx, postID, moderatorID
+func (_m *PostUsecase) UnhidePost(ctx context.Context, postID primitive.ObjectID, moderatorID primitive.ObjectID) error {
+	ret := _m.Called(ctx, postID, moderatorID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnhidePost")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, primitive.ObjectID) error); ok {
+		r0 = rf(ctx, postID, moderatorID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UnlikePost provides a mock function with given fields: ctx, postID, userID
 func (_m *PostUsecase) UnlikePost(ctx context.Context, postID primitive.ObjectID, userID primitive.ObjectID) error {
 	ret := _m.Called(ctx, postID, userID)
@@ -82,6 +82,54 @@ func (_m *ResourceRepository) DeleteResource(ctx context.Context, id primitive.O
 	return r0
 }
 
+// DeleteResourcesByCreator provides a mock function with given fields: ctx, creatorID
+func (_m *ResourceRepository) DeleteResourcesByCreator(ctx context.Context, creatorID primitive.ObjectID) error {
+	ret := _m.Called(ctx, creatorID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteResourcesByCreator")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) error); ok {
+		r0 = rf(ctx, creatorID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAllResourcesByCreator provides a mock function with given fields: ctx, creatorID
+func (_m *ResourceRepository) GetAllResourcesByCreator(ctx context.Context, creatorID primitive.ObjectID) ([]resourcepkg.Resource, error) {
+	ret := _m.Called(ctx, creatorID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllResourcesByCreator")
+	}
+
+	var r0 []resourcepkg.Resource
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) ([]resourcepkg.Resource, error)); ok {
+		return rf(ctx, creatorID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID) []resourcepkg.Resource); ok {
+		r0 = rf(ctx, creatorID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]resourcepkg.Resource)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, primitive.ObjectID) error); ok {
+		r1 = rf(ctx, creatorID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetExpiredResources provides a mock function with given fields: ctx, pagination
 func (_m *ResourceRepository) GetExpiredResources(ctx context.Context, pagination resourcepkg.ResourcePagination) ([]resourcepkg.Resource, int64, error) {
 	ret := _m.Called(ctx, pagination)
@@ -811,6 +859,24 @@ func (_m *ResourceRepository) RateResource(ctx context.Context, resourceID primi
 	return r0
 }
 
+// ReassignResourcesCreator provides a mock function with given fields: ctx, fromID, toID
+func (_m *ResourceRepository) ReassignResourcesCreator(ctx context.Context, fromID primitive.ObjectID, toID primitive.ObjectID) error {
+	ret := _m.Called(ctx, fromID, toID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReassignResourcesCreator")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, primitive.ObjectID) error); ok {
+		r0 = rf(ctx, fromID, toID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ReportResource provides a mock function with given fields: ctx, resourceID
 func (_m *ResourceRepository) ReportResource(ctx context.Context, resourceID primitive.ObjectID) error {
 	ret := _m.Called(ctx, resourceID)
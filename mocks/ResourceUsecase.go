@@ -532,6 +532,24 @@ func (_m *ResourceUsecase) GetUserResources(ctx context.Context, userID primitiv
 	return r0, r1
 }
 
+// HideResource provides a mock function with given fields: ctx, resourceID, moderatorID
+func (_m *ResourceUsecase) HideResource(ctx context.Context, resourceID primitive.ObjectID, moderatorID primitive.ObjectID) error {
+	ret := _m.Called(ctx, resourceID, moderatorID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HideResource")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, primitive.ObjectID) error); ok {
+		r0 = rf(ctx, resourceID, moderatorID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // LikeResource provides a mock function with given fields: ctx, resourceID, userID
 func (_m *ResourceUsecase) LikeResource(ctx context.Context, resourceID primitive.ObjectID, userID primitive.ObjectID) error {
 	ret := _m.Called(ctx, resourceID, userID)
@@ -634,6 +652,24 @@ func (_m *ResourceUsecase) UnbookmarkResource(ctx context.Context, resourceID pr
 	return r0
 }
 
+// UnhideResource provides a mock function with given fields: ctx, resourceID, moderatorID
+func (_m *ResourceUsecase) UnhideResource(ctx context.Context, resourceID primitive.ObjectID, moderatorID primitive.ObjectID) error {
+	ret := _m.Called(ctx, resourceID, moderatorID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnhideResource")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, primitive.ObjectID, primitive.ObjectID) error); ok {
+		r0 = rf(ctx, resourceID, moderatorID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UnlikeResource provides a mock function with given fields: ctx, resourceID, userID
 func (_m *ResourceUsecase) UnlikeResource(ctx context.Context, resourceID primitive.ObjectID, userID primitive.ObjectID) error {
 	ret := _m.Called(ctx, resourceID, userID)
@@ -48,8 +48,8 @@ db.createCollection('users', {
         },
         role: {
           bsonType: 'string',
-          enum: ['admin', 'user'],
-          description: 'Role must be either admin or user'
+          enum: ['admin', 'moderator', 'verifier', 'user'],
+          description: 'Role must be admin, moderator, verifier or user'
         },
         isVerified: {
           bsonType: 'bool',
@@ -185,6 +185,8 @@ db.users.createIndex({ 'displayName': 1 }, { unique: true, sparse: true });
 db.users.createIndex({ 'isMentor': 1, 'availableForMentoring': 1 });
 db.users.createIndex({ 'isMentee': 1 });
 db.users.createIndex({ 'mentorshipTopics': 1 });
+db.users.createIndex({ 'role': 1 });
+db.users.createIndex({ 'suspension.expiresAt': 1 }, { sparse: true });
 
 // Mentorship requests indexes
 db.mentorship_requests.createIndex({ 'menteeId': 1 });
@@ -206,6 +208,62 @@ db.mentorship_connections.createIndex({ 'topics': 1 });
 db.mentorship_connections.createIndex({ 'menteeId': 1, 'status': 1 });
 db.mentorship_connections.createIndex({ 'mentorId': 1, 'status': 1 });
 
+// Token and session indexes
+db.tokens.createIndex({ 'refresh_token': 1 });
+db.tokens.createIndex({ 'user_id': 1, 'family_id': 1 });
+
+// Access-token revocations expire once the tokens they match would have expired
+db.revoked_tokens.createIndex({ 'expiresAt': 1 }, { expireAfterSeconds: 0 });
+
+// Pending two-factor logins expire after a few minutes
+db.mfa_challenges.createIndex({ 'expiresAt': 1 }, { expireAfterSeconds: 0 });
+
+// Failed-login counters are forgotten once they go quiet or a lockout ends
+db.login_attempts.createIndex({ 'expiresAt': 1 }, { expireAfterSeconds: 0 });
+
+// Account deletions are picked up by the worker once their grace period ends
+db.account_deletions.createIndex({ 'scheduledFor': 1 });
+
+// Data exports are polled by owner and claimed in request order; expired
+// archives are removed by the export job, which also deletes the files
+db.data_exports.createIndex({ 'userId': 1, 'requestedAt': -1 });
+db.data_exports.createIndex({ 'status': 1, 'requestedAt': 1 });
+db.data_exports.createIndex({ 'expiresAt': 1 }, { sparse: true });
+
+// Personal access tokens are looked up by the hash of their secret
+db.personal_access_tokens.createIndex({ 'tokenHash': 1 }, { unique: true });
+db.personal_access_tokens.createIndex({ 'userId': 1, 'createdAt': -1 });
+
+// Audit log: entries are keyed by their sequence number and never updated.
+// Give the application's database user insert and find only on this collection.
+db.createCollection('audit_log');
+db.audit_log.createIndex({ 'actorId': 1, '_id': -1 });
+db.audit_log.createIndex({ 'targetType': 1, 'targetId': 1, '_id': -1 });
+db.audit_log.createIndex({ 'action': 1, '_id': -1 });
+db.audit_log.createIndex({ 'createdAt': 1 });
+
+// Unconfirmed email changes lapse on their own
+db.email_changes.createIndex({ 'expiresAt': 1 }, { expireAfterSeconds: 0 });
+
+// Sign-in links are single-use and keyed by address; unused ones lapse on their own
+db.magic_links.createIndex({ 'email': 1 }, { unique: true });
+db.magic_links.createIndex({ 'expiresAt': 1 }, { expireAfterSeconds: 0 });
+
+// Pending OIDC sign-ins are single-use and lapse after 10 minutes
+db.oauth_states.createIndex({ 'expiresAt': 1 }, { expireAfterSeconds: 0 });
+
+// A provider account can be linked to only one user
+db.users.createIndex(
+  { 'identities.provider': 1, 'identities.subject': 1 },
+  { unique: true, partialFilterExpression: { 'identities.subject': { $exists: true } } }
+);
+
+// OTP email counters start over after their daily window
+db.otp_sends.createIndex({ 'expiresAt': 1 }, { expireAfterSeconds: 0 });
+
+// JWT signing keys are dropped once no token they signed can still be valid
+db.jwt_keys.createIndex({ 'expiresAt': 1 }, { expireAfterSeconds: 0 });
+
 print('ShareSpace database initialization completed successfully!');
 print('Collections created: users, mentorship_requests, mentorship_connections');
 print('Indexes created for optimal query performance');